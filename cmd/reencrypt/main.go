@@ -0,0 +1,54 @@
+// Command reencrypt encrypts sensitive columns again with the primary key
+// encryption key (KEK) from the config.
+//
+// To rotate the KEK, generate a new one (e.g. `openssl rand -base64 32`),
+// make it encryption.kek_path and move the old one to
+// encryption.previous_kek_paths, restart the server and run:
+//
+//	go run ./cmd/reencrypt --config=./config/local.yaml
+//
+// Afterwards the old KEK may be removed from previous_kek_paths.
+// Values stored before encryption was enabled are encrypted as well, and
+// values encrypted before they were bound to their rows are bound to them.
+package main
+
+import (
+	"SSO/internal/config"
+	"SSO/internal/lib/envelope"
+	"SSO/storage/postgresql"
+	"context"
+	"log/slog"
+	"os"
+)
+
+func main() {
+	cfg := config.MustLoad()
+
+	log := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo}))
+
+	if cfg.Encryption.KEKPath == "" {
+		log.Error("encryption.kek_path is not set")
+		os.Exit(1)
+	}
+
+	encryptor, err := envelope.LoadFiles(cfg.Encryption.KEKPath, cfg.Encryption.PreviousKEKPaths...)
+	if err != nil {
+		log.Error("failed to load key encryption keys", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+
+	storage, err := postgresql.New(cfg.StoragePath, encryptor)
+	if err != nil {
+		log.Error("failed to connect to database", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+	defer storage.DB.Close()
+
+	n, err := storage.ReencryptColumns(context.Background(), encryptor.Stale)
+	if err != nil {
+		log.Error("failed to re-encrypt", slog.Int("reencrypted", n), slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+
+	log.Info("re-encryption finished", slog.Int("reencrypted", n))
+}
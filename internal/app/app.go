@@ -2,36 +2,236 @@ package app
 
 import (
 	grpcapp "SSO/internal/app/grpc"
+	"SSO/internal/config"
+	"SSO/internal/lib/envelope"
+	"SSO/internal/lib/ldap"
+	"SSO/internal/lib/mailer"
+	"SSO/internal/lib/oidc"
+	"SSO/internal/lib/samlidp"
+	"SSO/internal/lib/sms"
+	"SSO/internal/services/apikeys"
+	"SSO/internal/services/apps"
+	"SSO/internal/services/audit"
 	"SSO/internal/services/auth"
+	"SSO/internal/services/clients"
+	"SSO/internal/services/consents"
+	"SSO/internal/services/devices"
+	"SSO/internal/services/federation"
+	"SSO/internal/services/followers"
+	"SSO/internal/services/groups"
+	"SSO/internal/services/identities"
+	"SSO/internal/services/impersonation"
+	"SSO/internal/services/invitations"
+	"SSO/internal/services/magiclinks"
+	"SSO/internal/services/organizations"
+	"SSO/internal/services/otp"
+	"SSO/internal/services/permissions"
+	"SSO/internal/services/policies"
+	"SSO/internal/services/saml"
+	"SSO/internal/services/serviceaccounts"
 	"SSO/storage/postgresql"
+	"context"
 	"fmt"
 	"log/slog"
-	"time"
 )
 
 type App struct {
 	GRPCSrv *grpcapp.App
 	Storage *postgresql.Storage
+	// stopCleanup stops background cleanup of expired records.
+	stopCleanup context.CancelFunc
 }
 
 func New(
 	log *slog.Logger,
-	grpcPort int,
-	storagePath string,
-	tokenTTL time.Duration,
+	cfg *config.Config,
 ) *App {
-	storage, err := postgresql.New(storagePath)
+	storage, err := postgresql.New(cfg.StoragePath, newCipher(log, cfg.Encryption))
 	if err != nil {
 		panic(err)
 	}
 
-	authService := auth.New(log, storage, storage, storage, tokenTTL)
+	authService := auth.New(
+		log,
+		storage,
+		storage,
+		storage,
+		storage,
+		storage,
+		storage,
+		storage,
+		newVerifier(cfg.LDAP),
+		storage,
+		storage,
+		cfg.OTP.CodeTTL,
+		cfg.OTP.ResendInterval,
+		cfg.TokenTTL,
+	)
 
-	grpcApp := grpcapp.New(log, authService, grpcPort)
+	permissionsService := permissions.New(log, storage, storage)
+
+	policiesService := policies.New(log, storage, storage, storage, storage)
+
+	orgsService := organizations.New(log, storage, storage, permissionsService)
+
+	mail := newMailer(log, cfg.Mailer)
+
+	invitationsService := invitations.New(
+		log,
+		storage,
+		storage,
+		storage,
+		orgsService,
+		mail,
+		cfg.Invitations.TTL,
+		cfg.Invitations.AcceptURL,
+	)
+
+	groupsService := groups.New(log, storage, storage)
+
+	appsService := apps.New(log, storage, storage, cfg.AdminAppID, cfg.Secrets.RotationOverlap)
+
+	apiKeysService := apikeys.New(log, storage, storage, authService)
+
+	serviceAccountsService := serviceaccounts.New(
+		log,
+		storage,
+		storage,
+		apiKeysService,
+		authService,
+		cfg.ServiceAccounts.AssertionAudience,
+		cfg.ServiceAccounts.AssertionMaxTTL,
+	)
+
+	auditService := audit.New(log, storage, storage)
+
+	impersonationService := impersonation.New(
+		log,
+		authService,
+		auditService,
+		min(cfg.Impersonation.TokenTTL, cfg.TokenTTL),
+	)
+
+	consentsService := consents.New(log, storage, storage, storage, storage)
+
+	devicesService := devices.New(
+		log,
+		storage,
+		storage,
+		storage,
+		storage,
+		authService,
+		cfg.Devices.CodeTTL,
+		cfg.Devices.Interval,
+		cfg.Devices.VerificationURI,
+	)
+
+	clientsService := clients.New(
+		log,
+		storage,
+		storage,
+		storage,
+		storage,
+		cfg.ClientRegistration.AllowedGrantTypes,
+	)
+
+	federationService := federation.New(
+		log,
+		newUpstreams(cfg.Federation.Providers),
+		storage,
+		storage,
+		storage,
+		storage,
+		storage,
+		authService,
+		cfg.Federation.StateTTL,
+	)
+
+	identitiesService := identities.New(
+		log,
+		storage,
+		storage,
+		storage,
+		federationService,
+		cfg.Identities.ReauthMaxAge,
+	)
+
+	samlService := saml.New(
+		log,
+		newIdentityProvider(log, cfg.SAML),
+		storage,
+		storage,
+		storage,
+		storage,
+		storage,
+		cfg.SAML.AssertionTTL,
+	)
+
+	magicLinksService := magiclinks.New(
+		log,
+		storage,
+		storage,
+		storage,
+		authService,
+		mail,
+		cfg.MagicLinks.TTL,
+		cfg.MagicLinks.LinkURL,
+	)
+
+	otpService := otp.New(
+		log,
+		storage,
+		storage,
+		storage,
+		storage,
+		storage,
+		storage,
+		authService,
+		mail,
+		newSMSSender(log, cfg.SMS),
+		otp.Limits{
+			CodeTTL:        cfg.OTP.CodeTTL,
+			MaxAttempts:    cfg.OTP.MaxAttempts,
+			ResendInterval: cfg.OTP.ResendInterval,
+			MaxSends:       cfg.OTP.MaxSends,
+		},
+		cfg.OTP.ReauthMaxAge,
+	)
+
+	followersService := followers.New(log, storage, storage, storage)
+
+	cleanupCtx, stopCleanup := context.WithCancel(context.Background())
+	go devicesService.RunCleanup(cleanupCtx, cfg.Devices.CleanupInterval)
+
+	grpcApp := grpcapp.New(
+		log,
+		authService,
+		permissionsService,
+		policiesService,
+		orgsService,
+		invitationsService,
+		groupsService,
+		appsService,
+		apiKeysService,
+		serviceAccountsService,
+		auditService,
+		impersonationService,
+		consentsService,
+		devicesService,
+		clientsService,
+		federationService,
+		identitiesService,
+		samlService,
+		magicLinksService,
+		otpService,
+		followersService,
+		cfg.GRPC.Port,
+	)
 
 	return &App{
-		GRPCSrv: grpcApp,
-		Storage: storage,
+		GRPCSrv:     grpcApp,
+		Storage:     storage,
+		stopCleanup: stopCleanup,
 	}
 }
 
@@ -40,9 +240,125 @@ func (a *App) Stop() error {
 
 	a.GRPCSrv.Stop()
 
+	a.stopCleanup()
+
 	if err = a.Storage.DB.Close(); err != nil {
 		return fmt.Errorf("failed to close database: %w", err)
 	}
 
 	return nil
 }
+
+// newCipher creates cipher for sensitive columns from the configured KEK files.
+func newCipher(log *slog.Logger, cfg config.EncryptionConfig) postgresql.Cipher {
+	if cfg.KEKPath == "" {
+		log.Warn("encryption.kek_path is not set, sensitive columns are stored unencrypted")
+
+		return nil
+	}
+
+	encryptor, err := envelope.LoadFiles(cfg.KEKPath, cfg.PreviousKEKPaths...)
+	if err != nil {
+		panic(err)
+	}
+
+	return encryptor
+}
+
+// newMailer creates mailer of the configured type.
+func newMailer(log *slog.Logger, cfg config.MailerConfig) mailer.Mailer {
+	switch cfg.Type {
+	case "log":
+		return mailer.NewLogMailer(log)
+	case "file":
+		return mailer.NewFileMailer(cfg.Path)
+	case "smtp":
+		return mailer.NewSMTPMailer(cfg.SMTP.Host, cfg.SMTP.Port, cfg.SMTP.Username, cfg.SMTP.Password, cfg.From)
+	}
+
+	panic("unknown mailer type: " + cfg.Type)
+}
+
+// newSMSSender creates SMS sender of the configured type.
+func newSMSSender(log *slog.Logger, cfg config.SMSConfig) sms.Sender {
+	switch cfg.Type {
+	case "log":
+		return sms.NewLogSender(log)
+	case "file":
+		return sms.NewFileSender(cfg.Path)
+	case "webhook":
+		return sms.NewWebhookSender(cfg.WebhookURL, cfg.WebhookToken, cfg.Timeout)
+	}
+
+	panic("unknown sms sender type: " + cfg.Type)
+}
+
+// newUpstreams creates upstream providers of federated login from the config.
+func newUpstreams(providers []config.OIDCProviderConfig) []federation.Upstream {
+	upstreams := make([]federation.Upstream, 0, len(providers))
+	for _, p := range providers {
+		upstreams = append(upstreams, federation.Upstream{
+			Name: p.Name,
+			Provider: oidc.New(oidc.Config{
+				Issuer:       p.Issuer,
+				ClientID:     p.ClientID,
+				ClientSecret: p.ClientSecret,
+				RedirectURI:  p.RedirectURI,
+				Scopes:       p.Scopes,
+			}, nil),
+			TrustEmail: p.TrustEmail,
+			Provision:  p.Provision,
+		})
+	}
+
+	return upstreams
+}
+
+// newVerifier creates verifier of credentials in the configured LDAP
+// directory or returns nil if there is none.
+func newVerifier(cfg config.LDAPConfig) auth.CredentialVerifier {
+	if cfg.URL == "" {
+		return nil
+	}
+
+	return ldap.New(ldap.Config{
+		Name:           cfg.Name,
+		URL:            cfg.URL,
+		BindDN:         cfg.BindDN,
+		BindPassword:   cfg.BindPassword,
+		BaseDN:         cfg.BaseDN,
+		ObjectClass:    cfg.ObjectClass,
+		LoginAttribute: cfg.LoginAttribute,
+		Attributes: ldap.Mapping{
+			Email:       cfg.Attributes.Email,
+			Username:    cfg.Attributes.Username,
+			Sex:         cfg.Attributes.Sex,
+			Location:    cfg.Attributes.Location,
+			DateOfBirth: cfg.Attributes.DateOfBirth,
+		},
+		Timeout: cfg.Timeout,
+	})
+}
+
+// newIdentityProvider creates SAML identity provider with the configured
+// key pair or, if it's not set, a temporary one.
+func newIdentityProvider(log *slog.Logger, cfg config.SAMLConfig) *samlidp.IdentityProvider {
+	idp := &samlidp.IdentityProvider{
+		EntityID: cfg.EntityID,
+		SSOURL:   cfg.SSOURL,
+	}
+
+	var err error
+	if cfg.KeyPath == "" {
+		log.Warn("saml.key_path is not set, assertions are signed with a temporary key")
+
+		idp.Key, idp.Certificate, err = samlidp.GenerateKeyPair(cfg.EntityID)
+	} else {
+		idp.Key, idp.Certificate, err = samlidp.LoadKeyPair(cfg.CertPath, cfg.KeyPath)
+	}
+	if err != nil {
+		panic(err)
+	}
+
+	return idp
+}
@@ -1,7 +1,27 @@
 package grpcapp
 
 import (
+	apikeysgrpc "SSO/internal/grpc/apikeys"
+	appsgrpc "SSO/internal/grpc/apps"
+	auditgrpc "SSO/internal/grpc/audit"
 	authgrpc "SSO/internal/grpc/auth"
+	clientsgrpc "SSO/internal/grpc/clients"
+	consentsgrpc "SSO/internal/grpc/consents"
+	devicesgrpc "SSO/internal/grpc/devices"
+	federationgrpc "SSO/internal/grpc/federation"
+	followersgrpc "SSO/internal/grpc/followers"
+	groupsgrpc "SSO/internal/grpc/groups"
+	identitiesgrpc "SSO/internal/grpc/identities"
+	impersonationgrpc "SSO/internal/grpc/impersonation"
+	"SSO/internal/grpc/interceptors"
+	invitationsgrpc "SSO/internal/grpc/invitations"
+	magiclinksgrpc "SSO/internal/grpc/magiclinks"
+	orgsgrpc "SSO/internal/grpc/organizations"
+	otpgrpc "SSO/internal/grpc/otp"
+	permissionsgrpc "SSO/internal/grpc/permissions"
+	policiesgrpc "SSO/internal/grpc/policies"
+	samlgrpc "SSO/internal/grpc/saml"
+	serviceaccountsgrpc "SSO/internal/grpc/serviceaccounts"
 	"fmt"
 	"net"
 
@@ -16,15 +36,76 @@ type App struct {
 	port       int
 }
 
+// APIKeysService manages API keys and verifies requests authenticated with them.
+type APIKeysService interface {
+	apikeysgrpc.APIKeys
+	interceptors.APIKeyVerifier
+}
+
+// OrganizationsService manages organizations and checks rights of their members.
+type OrganizationsService interface {
+	orgsgrpc.Organizations
+	serviceaccountsgrpc.OrganizationManagers
+}
+
+// ImpersonationService starts impersonations and records requests made with their tokens.
+type ImpersonationService interface {
+	impersonationgrpc.Impersonation
+	interceptors.ImpersonationRecorder
+}
+
 // New creates new gRPC server app
 func New(
 	log *slog.Logger,
 	authService authgrpc.Auth,
+	permissionsService permissionsgrpc.Permissions,
+	policiesService policiesgrpc.Policies,
+	orgsService OrganizationsService,
+	invitationsService invitationsgrpc.Invitations,
+	groupsService groupsgrpc.Groups,
+	appsService appsgrpc.Apps,
+	apiKeysService APIKeysService,
+	serviceAccountsService serviceaccountsgrpc.ServiceAccounts,
+	auditService auditgrpc.Audit,
+	impersonationService ImpersonationService,
+	consentsService consentsgrpc.Consents,
+	devicesService devicesgrpc.Devices,
+	clientsService clientsgrpc.Clients,
+	federationService federationgrpc.Federation,
+	identitiesService identitiesgrpc.Identities,
+	samlService samlgrpc.SAML,
+	magicLinksService magiclinksgrpc.MagicLinks,
+	otpService otpgrpc.OTP,
+	followersService followersgrpc.Followers,
 	port int,
 ) *App {
-	gRPCServer := grpc.NewServer()
+	gRPCServer := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(
+			interceptors.Auth(authService, apiKeysService),
+			interceptors.AuditImpersonation(impersonationService),
+		),
+	)
 
 	authgrpc.Register(gRPCServer, authService)
+	permissionsgrpc.Register(gRPCServer, permissionsService)
+	policiesgrpc.Register(gRPCServer, policiesService, permissionsService)
+	orgsgrpc.Register(gRPCServer, orgsService)
+	invitationsgrpc.Register(gRPCServer, invitationsService)
+	groupsgrpc.Register(gRPCServer, groupsService, permissionsService)
+	appsgrpc.Register(gRPCServer, appsService, permissionsService)
+	apikeysgrpc.Register(gRPCServer, apiKeysService)
+	serviceaccountsgrpc.Register(gRPCServer, serviceAccountsService, permissionsService, orgsService)
+	auditgrpc.Register(gRPCServer, auditService, permissionsService)
+	impersonationgrpc.Register(gRPCServer, impersonationService, permissionsService)
+	consentsgrpc.Register(gRPCServer, consentsService, permissionsService)
+	devicesgrpc.Register(gRPCServer, devicesService)
+	clientsgrpc.Register(gRPCServer, clientsService, appsService.AdminAppID(), permissionsService)
+	federationgrpc.Register(gRPCServer, federationService)
+	identitiesgrpc.Register(gRPCServer, identitiesService)
+	samlgrpc.Register(gRPCServer, samlService, permissionsService)
+	magiclinksgrpc.Register(gRPCServer, magicLinksService)
+	otpgrpc.Register(gRPCServer, otpService)
+	followersgrpc.Register(gRPCServer, followersService)
 
 	return &App{
 		log:        log,
@@ -8,10 +8,32 @@ import (
 )
 
 type Config struct {
-	Env         string        `yaml:"env" env-default:"local"`
-	StoragePath string        `yaml:"storage_path" env-required:"true"`
-	TokenTTL    time.Duration `yaml:"token_ttl" env-required:"true"`
-	GRPC        GRPCConfig    `yaml:"grpc"`
+	Env         string            `yaml:"env" env-default:"local"`
+	StoragePath string            `yaml:"storage_path" env-required:"true"`
+	TokenTTL    time.Duration     `yaml:"token_ttl" env-required:"true"`
+	AdminAppID  int               `yaml:"admin_app_id" env-default:"1"`
+	Secrets     SecretsConfig     `yaml:"secrets"`
+	Encryption  EncryptionConfig  `yaml:"encryption"`
+	GRPC        GRPCConfig        `yaml:"grpc"`
+	Mailer      MailerConfig      `yaml:"mailer"`
+	Invitations InvitationsConfig `yaml:"invitations"`
+	// ServiceAccounts configures JWT assertions service accounts exchange for tokens.
+	ServiceAccounts ServiceAccountsConfig `yaml:"service_accounts"`
+	Impersonation   ImpersonationConfig   `yaml:"impersonation"`
+	Devices         DevicesConfig         `yaml:"devices"`
+	// ClientRegistration configures apps partners register themselves.
+	ClientRegistration ClientRegistrationConfig `yaml:"client_registration"`
+	// Federation configures upstream OpenID Connect providers users sign in with.
+	Federation FederationConfig `yaml:"federation"`
+	Identities IdentitiesConfig `yaml:"identities"`
+	// SAML configures the SAML identity provider of service providers registered for apps.
+	SAML SAMLConfig `yaml:"saml"`
+	// LDAP configures the directory users without local password log in through.
+	LDAP       LDAPConfig       `yaml:"ldap"`
+	MagicLinks MagicLinksConfig `yaml:"magic_links"`
+	// SMS configures delivery of text messages with one-time passcodes.
+	SMS SMSConfig `yaml:"sms"`
+	OTP OTPConfig `yaml:"otp"`
 }
 
 type GRPCConfig struct {
@@ -19,6 +41,169 @@ type GRPCConfig struct {
 	Timeout time.Duration `yaml:"timeout"`
 }
 
+type SecretsConfig struct {
+	// RotationOverlap is how long former app secret is still accepted after rotation.
+	RotationOverlap time.Duration `yaml:"rotation_overlap" env-default:"24h"`
+}
+
+type EncryptionConfig struct {
+	// KEKPath is the file with the key encryption key sensitive columns are
+	// encrypted with. They are stored unencrypted if it's empty.
+	KEKPath string `yaml:"kek_path" env:"KEK_PATH"`
+	// PreviousKEKPaths are files with retired KEKs still needed to decrypt
+	// values until they are re-encrypted with cmd/reencrypt.
+	PreviousKEKPaths []string `yaml:"previous_kek_paths"`
+}
+
+type MailerConfig struct {
+	// Type is "log" to write emails to the log, "file" to append them to
+	// Path as JSON lines or "smtp" to send them.
+	Type string     `yaml:"type" env-default:"log"`
+	From string     `yaml:"from"`
+	Path string     `yaml:"path"`
+	SMTP SMTPConfig `yaml:"smtp"`
+}
+
+type SMTPConfig struct {
+	Host     string `yaml:"host"`
+	Port     int    `yaml:"port" env-default:"587"`
+	Username string `yaml:"username"`
+	Password string `yaml:"password" env:"SMTP_PASSWORD"`
+}
+
+type InvitationsConfig struct {
+	TTL time.Duration `yaml:"ttl" env-default:"72h"`
+	// AcceptURL is the page invite token is appended to in invitation emails.
+	AcceptURL string `yaml:"accept_url"`
+}
+
+type ServiceAccountsConfig struct {
+	// AssertionAudience is the "aud" claim assertions must be issued for.
+	AssertionAudience string `yaml:"assertion_audience" env-default:"sso"`
+	// AssertionMaxTTL limits how far in the future assertions may expire.
+	AssertionMaxTTL time.Duration `yaml:"assertion_max_ttl" env-default:"5m"`
+}
+
+type ImpersonationConfig struct {
+	// TokenTTL is the lifetime of impersonation tokens, it's capped by token_ttl.
+	TokenTTL time.Duration `yaml:"token_ttl" env-default:"15m"`
+}
+
+type DevicesConfig struct {
+	// CodeTTL is the lifetime of device authorization requests.
+	CodeTTL time.Duration `yaml:"code_ttl" env-default:"10m"`
+	// Interval is the minimal time devices must wait between polls for token.
+	Interval time.Duration `yaml:"interval" env-default:"5s"`
+	// VerificationURI is the page users enter user codes at.
+	VerificationURI string `yaml:"verification_uri"`
+	// CleanupInterval is how often expired requests are deleted.
+	CleanupInterval time.Duration `yaml:"cleanup_interval" env-default:"1m"`
+}
+
+type ClientRegistrationConfig struct {
+	// AllowedGrantTypes are grant types registered apps may use.
+	AllowedGrantTypes []string `yaml:"allowed_grant_types" env-default:"password,urn:ietf:params:oauth:grant-type:device_code"`
+}
+
+type FederationConfig struct {
+	// StateTTL is how long users have to sign in at the provider.
+	StateTTL  time.Duration        `yaml:"state_ttl" env-default:"10m"`
+	Providers []OIDCProviderConfig `yaml:"providers"`
+}
+
+type OIDCProviderConfig struct {
+	// Name identifies the provider in requests and linked identities, it
+	// must not change.
+	Name         string `yaml:"name"`
+	Issuer       string `yaml:"issuer"`
+	ClientID     string `yaml:"client_id"`
+	ClientSecret string `yaml:"client_secret"`
+	// RedirectURI is the callback the provider redirects users back to.
+	RedirectURI string `yaml:"redirect_uri"`
+	// Scopes default to "openid email profile".
+	Scopes []string `yaml:"scopes"`
+	// TrustEmail links identities to existing users by email the provider verified.
+	TrustEmail bool `yaml:"trust_email"`
+	// Provision registers users signing in for the first time.
+	Provision bool `yaml:"provision"`
+}
+
+type IdentitiesConfig struct {
+	// ReauthMaxAge is how recently users without password must have signed
+	// in to link or unlink identities.
+	ReauthMaxAge time.Duration `yaml:"reauth_max_age" env-default:"5m"`
+}
+
+type SAMLConfig struct {
+	// EntityID identifies the identity provider, usually the URL its metadata is served at.
+	EntityID string `yaml:"entity_id" env-default:"sso"`
+	// SSOURL is the login page service providers send users to.
+	SSOURL string `yaml:"sso_url"`
+	// CertPath and KeyPath are PEM files with the certificate and RSA key
+	// assertions are signed with. A temporary pair is generated if they're empty.
+	CertPath string `yaml:"cert_path"`
+	KeyPath  string `yaml:"key_path" env:"SAML_KEY_PATH"`
+	// AssertionTTL is how long service providers accept assertions.
+	AssertionTTL time.Duration `yaml:"assertion_ttl" env-default:"5m"`
+}
+
+type LDAPConfig struct {
+	// URL is ldap:// or ldaps:// URL of the directory. Login through the
+	// directory is off if it's empty.
+	URL string `yaml:"url"`
+	// Name identifies the directory in linked identities, it must not
+	// change or match a federation provider.
+	Name string `yaml:"name" env-default:"ldap"`
+	// BindDN and BindPassword are the service account users are searched
+	// with. Search is anonymous if BindDN is empty.
+	BindDN       string `yaml:"bind_dn"`
+	BindPassword string `yaml:"bind_password" env:"LDAP_BIND_PASSWORD"`
+	BaseDN       string `yaml:"base_dn"`
+	ObjectClass  string `yaml:"object_class" env-default:"person"`
+	// LoginAttribute is the attribute matched against email users log in with.
+	LoginAttribute string              `yaml:"login_attribute" env-default:"mail"`
+	Attributes     LDAPAttributeConfig `yaml:"attributes"`
+	Timeout        time.Duration       `yaml:"timeout" env-default:"10s"`
+}
+
+// LDAPAttributeConfig names attributes of directory entries user fields
+// are provisioned from.
+type LDAPAttributeConfig struct {
+	Email       string `yaml:"email" env-default:"mail"`
+	Username    string `yaml:"username" env-default:"uid"`
+	Sex         string `yaml:"sex"`
+	Location    string `yaml:"location" env-default:"l"`
+	DateOfBirth string `yaml:"date_of_birth"`
+}
+
+type MagicLinksConfig struct {
+	TTL time.Duration `yaml:"ttl" env-default:"15m"`
+	// LinkURL is the page link token is appended to in emails.
+	LinkURL string `yaml:"link_url"`
+}
+
+type SMSConfig struct {
+	// Type is "log" to write messages to the log, "file" to append them to
+	// Path as JSON lines or "webhook" to post them to the gateway at WebhookURL.
+	Type         string        `yaml:"type" env-default:"log"`
+	Path         string        `yaml:"path"`
+	WebhookURL   string        `yaml:"webhook_url"`
+	WebhookToken string        `yaml:"webhook_token" env:"SMS_WEBHOOK_TOKEN"`
+	Timeout      time.Duration `yaml:"timeout" env-default:"10s"`
+}
+
+type OTPConfig struct {
+	// CodeTTL is how long one-time passcodes and second factor challenges are valid.
+	CodeTTL     time.Duration `yaml:"code_ttl" env-default:"5m"`
+	MaxAttempts int           `yaml:"max_attempts" env-default:"5"`
+	// ResendInterval is how long to wait before sending another code.
+	ResendInterval time.Duration `yaml:"resend_interval" env-default:"30s"`
+	// MaxSends is how many codes can be sent for a challenge.
+	MaxSends int `yaml:"max_sends" env-default:"5"`
+	// ReauthMaxAge is how recently users must have logged in to delete factors.
+	ReauthMaxAge time.Duration `yaml:"reauth_max_age" env-default:"5m"`
+}
+
 func MustLoad() *Config {
 	path := fetchConfigPath()
 	if path == "" {
@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// APIKey is a long-lived credential of the user for the app. The key itself
+// is stored only hashed, Prefix identifies it to the user.
+type APIKey struct {
+	ID     int64
+	UserID int64
+	AppID  int
+	Name   string
+	Prefix string
+	Scopes []string
+	// ExpiresAt is zero for keys that never expire.
+	ExpiresAt  time.Time
+	LastUsedAt time.Time
+	CreatedAt  time.Time
+}
+
+// Expired reports whether the key has expired at the time.
+func (k APIKey) Expired(at time.Time) bool {
+	return !k.ExpiresAt.IsZero() && !at.Before(k.ExpiresAt)
+}
@@ -1,7 +1,122 @@
 package models
 
+import (
+	"slices"
+	"time"
+)
+
+// Grant types an app may be allowed to use.
+const (
+	GrantPassword      = "password"
+	GrantAPIKey        = "api_key"
+	GrantJWTBearer     = "urn:ietf:params:oauth:grant-type:jwt-bearer"
+	GrantTokenExchange = "urn:ietf:params:oauth:grant-type:token-exchange"
+	GrantDeviceCode    = "urn:ietf:params:oauth:grant-type:device_code"
+	// GrantFederated is sign in through an upstream OpenID Connect provider.
+	GrantFederated = "federated"
+	// GrantMagicLink is login with a link emailed to the user.
+	GrantMagicLink = "magic_link"
+	// GrantOTP is login with a one-time passcode sent by email or SMS.
+	GrantOTP = "otp"
+)
+
+// GrantTypes are all supported grant types.
+var GrantTypes = []string{
+	GrantPassword,
+	GrantAPIKey,
+	GrantJWTBearer,
+	GrantTokenExchange,
+	GrantDeviceCode,
+	GrantFederated,
+	GrantMagicLink,
+	GrantOTP,
+}
+
+// Token endpoint authentication methods of an app (RFC 7591, section 2).
+const (
+	AuthMethodClientSecretBasic = "client_secret_basic"
+	AuthMethodClientSecretPost  = "client_secret_post"
+	// AuthMethodNone is used by public clients, which can't keep a secret.
+	AuthMethodNone = "none"
+)
+
+// AuthMethods are all supported token endpoint authentication methods.
+var AuthMethods = []string{AuthMethodClientSecretBasic, AuthMethodClientSecretPost, AuthMethodNone}
+
+// TokenTypeAccessToken identifies access tokens in token exchange (RFC 8693).
+const TokenTypeAccessToken = "urn:ietf:params:oauth:token-type:access_token"
+
+// Registration modes of an app.
+const (
+	RegistrationOpen       = "open"
+	RegistrationClosed     = "closed"
+	RegistrationInviteOnly = "invite_only"
+)
+
+// Profile fields an app may require on registration.
+const (
+	FieldUsername    = "username"
+	FieldLocation    = "location"
+	FieldDateOfBirth = "date_of_birth"
+)
+
+// ProfileFields are all profile fields an app may require.
+var ProfileFields = []string{FieldUsername, FieldLocation, FieldDateOfBirth}
+
+// RegistrationRules are rules users registering for an app must meet.
+type RegistrationRules struct {
+	Mode string
+	// AllowedEmailDomains restrict emails of new users, any domain is allowed if empty.
+	AllowedEmailDomains []string
+	RequiredFields      []string
+	MinAge              int
+}
+
 type App struct {
 	ID     int
 	Name   string
 	Secret string
+	// SecretKID identifies Secret in the header of the tokens signed with it.
+	SecretKID string
+	// GroupClaims tells to put groups of the user into tokens issued for the app.
+	GroupClaims  bool
+	RedirectURIs []string
+	GrantTypes   []string
+	// Disabled app can't be logged in to and its tokens aren't accepted.
+	Disabled     bool
+	Registration RegistrationRules
+	// ConsentRequired app must request scopes at login and the user must
+	// have granted them, it's set for third-party apps.
+	ConsentRequired         bool
+	TokenEndpointAuthMethod string
+	// SelfRegistered app was registered dynamically by its developer with
+	// an initial access token, not created by admins.
+	SelfRegistered bool
+	CreatedAt      time.Time
+}
+
+// AllowsGrant reports whether the app is allowed to use grant type.
+func (a App) AllowsGrant(grantType string) bool {
+	return slices.Contains(a.GrantTypes, grantType)
+}
+
+// AppSecret is a former secret of the app still accepted for verification
+// of the tokens signed with it until it expires.
+type AppSecret struct {
+	AppID     int
+	KID       string
+	Secret    string
+	RotatedAt time.Time // When the secret stopped being the primary one.
+	ExpiresAt time.Time
+}
+
+// TokenExchangeRule permits exchanging tokens of the source app for tokens
+// of the target app.
+type TokenExchangeRule struct {
+	SourceAppID int
+	TargetAppID int
+	// Scopes are roles and permissions of the target app exchanged tokens
+	// may carry, any if empty.
+	Scopes    []string
+	CreatedAt time.Time
 }
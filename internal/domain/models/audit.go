@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+const (
+	// AuditImpersonationStart is recorded when an admin starts impersonating a user.
+	AuditImpersonationStart = "impersonation.start"
+	// AuditImpersonationUse is recorded for every request made with an impersonation token.
+	AuditImpersonationUse = "impersonation.use"
+)
+
+// AuditEvent records an action ActorID took in the app, on behalf of or
+// against UserID.
+type AuditEvent struct {
+	ID      int64
+	Action  string
+	AppID   int
+	ActorID int64
+	UserID  int64
+	// SessionID groups events of one impersonation session.
+	SessionID string
+	Details   string
+	CreatedAt time.Time
+}
+
+// AuditFilter selects audit events of the app, newest first. Zero fields
+// other than AppID don't filter.
+type AuditFilter struct {
+	AppID     int
+	UserID    int64
+	SessionID string
+	// BeforeID returns events older than the event, for paging.
+	BeforeID int64
+	Limit    int
+}
@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// InitialAccessToken permits registering apps dynamically (RFC 7591). The
+// token itself is stored only hashed.
+type InitialAccessToken struct {
+	ID   int64
+	Name string
+	// MaxUses is the number of apps the token may register, unlimited if 0.
+	MaxUses   int
+	Uses      int
+	CreatedBy int64
+	// ExpiresAt is zero for tokens that never expire.
+	ExpiresAt time.Time
+	CreatedAt time.Time
+}
+
+// ClientMetadata is metadata of a dynamically registered app its developer
+// manages.
+type ClientMetadata struct {
+	Name                    string
+	RedirectURIs            []string
+	GrantTypes              []string
+	TokenEndpointAuthMethod string
+}
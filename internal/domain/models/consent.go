@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// Scope is a scope the app may request at login. Scopes name roles and
+// permissions of the app or app specific access the app defines.
+type Scope struct {
+	AppID       int
+	Name        string
+	Description string
+	CreatedAt   time.Time
+}
+
+// Consent records scopes the user granted to the app.
+type Consent struct {
+	UserID    int64
+	AppID     int
+	Scopes    []string
+	GrantedAt time.Time
+	UpdatedAt time.Time
+}
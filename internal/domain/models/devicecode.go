@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// Statuses of a device authorization request.
+const (
+	DeviceCodePending  = "pending"
+	DeviceCodeApproved = "approved"
+	DeviceCodeDenied   = "denied"
+)
+
+// DeviceCode is a device authorization request (RFC 8628). The device polls
+// for a token with the device code, stored only hashed, while the user
+// approves the request entering UserCode on another device.
+type DeviceCode struct {
+	ID       int64
+	AppID    int
+	UserCode string
+	Scopes   []string
+	Status   string
+	// UserID is the user who approved or denied the request.
+	UserID int64
+	// Interval is the minimal time between polls of the device.
+	Interval     time.Duration
+	LastPolledAt time.Time
+	ExpiresAt    time.Time
+	CreatedAt    time.Time
+}
+
+// Expired reports whether the request has expired at the time.
+func (d DeviceCode) Expired(at time.Time) bool {
+	return !at.Before(d.ExpiresAt)
+}
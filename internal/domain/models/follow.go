@@ -0,0 +1,63 @@
+package models
+
+// Statuses of follows.
+const (
+	// FollowPending is a request to follow a private account awaiting its approval.
+	FollowPending = "pending"
+	// FollowAccepted is a follow of a public account or an approved request.
+	FollowAccepted = "accepted"
+)
+
+// Follow is the follower following the user with FollowingID.
+type Follow struct {
+	ID          int64
+	FollowerID  int64
+	FollowingID int64
+	Status      string
+}
+
+// FollowEntry is a user in lists of followers, followed users and follow
+// requests.
+type FollowEntry struct {
+	// ID identifies the follow, lists are paged by it.
+	ID       int64
+	UserID   int64
+	Username string
+}
+
+// FollowPage is a page of followers or followed users, most recent
+// follows first.
+type FollowPage struct {
+	Entries []FollowEntry
+	// NextCursor is passed to get the next page, 0 on the last page.
+	NextCursor int64
+}
+
+// FollowCounts are numbers of followers of the user and of users the user
+// follows, pending requests aren't counted.
+type FollowCounts struct {
+	UserID    int64
+	Followers int64
+	Following int64
+	// Private is set if follows of the user need the user's approval.
+	Private bool
+}
+
+// Relationship is how the user and the other user follow each other.
+type Relationship struct {
+	UserID      int64
+	OtherUserID int64
+	// Following is set if the user follows the other user.
+	Following bool
+	// FollowedBy is set if the other user follows the user.
+	FollowedBy bool
+	// Requested is set if the user requested to follow the other user.
+	Requested bool
+	// RequestedBy is set if the other user requested to follow the user.
+	RequestedBy bool
+}
+
+// Mutual reports whether the users follow each other.
+func (r Relationship) Mutual() bool {
+	return r.Following && r.FollowedBy
+}
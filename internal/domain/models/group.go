@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+type Group struct {
+	ID        int64
+	AppID     int
+	Name      string
+	Roles     []string // Names of the roles assigned to the group.
+	Subgroups []int64  // IDs of the groups nested directly into the group.
+	CreatedAt time.Time
+}
+
+type GroupMember struct {
+	GroupID int64
+	UserID  int64
+	Email   string
+	// Direct is false if user is a member only through a subgroup.
+	Direct bool
+}
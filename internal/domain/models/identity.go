@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+// Identity is an account of the user at an upstream identity provider the
+// user signs in with.
+type Identity struct {
+	ID     int64
+	UserID int64
+	// Provider is the name of the provider in the config.
+	Provider string
+	// Subject identifies the user at the provider.
+	Subject string
+	// Email is the email the provider reported on the last sign in.
+	Email       string
+	CreatedAt   time.Time
+	LastLoginAt time.Time
+}
+
+// FederationState is a sign in through an upstream provider in progress,
+// from redirecting the user to the provider until the callback.
+type FederationState struct {
+	Provider string
+	AppID    int
+	Scopes   []string
+	Nonce    string
+	// CodeVerifier is the PKCE verifier the authorization code is exchanged with.
+	CodeVerifier string
+	// ReturnTo is the redirect URI of the app the user returns to, if any.
+	ReturnTo string
+	// LinkUserID is the signed in user the identity is linked to, set when
+	// linking identity rather than signing in.
+	LinkUserID int64
+	ExpiresAt  time.Time
+}
+
+// Expired reports whether the sign in has expired at the time.
+func (s FederationState) Expired(at time.Time) bool {
+	return !at.Before(s.ExpiresAt)
+}
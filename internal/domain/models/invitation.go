@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+type Invitation struct {
+	ID             int64
+	OrganizationID int64
+	Email          string
+	Role           string
+	InvitedBy      int64
+	ExpiresAt      time.Time
+	CreatedAt      time.Time
+}
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// MagicLink is a link emailed to the user to log in to the app without
+// password. It's identified by hash of its token and used once.
+type MagicLink struct {
+	UserID    int64
+	AppID     int
+	Scopes    []string
+	ExpiresAt time.Time
+}
+
+// Expired reports whether the link has expired at the time.
+func (l MagicLink) Expired(at time.Time) bool {
+	return !at.Before(l.ExpiresAt)
+}
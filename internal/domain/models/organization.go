@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// Roles of organization members.
+const (
+	OrgRoleOwner  = "owner"
+	OrgRoleAdmin  = "admin"
+	OrgRoleMember = "member"
+)
+
+type Organization struct {
+	ID        int64
+	Name      string
+	CreatedAt time.Time
+}
+
+type OrganizationMember struct {
+	OrganizationID   int64
+	OrganizationName string
+	UserID           int64
+	Email            string
+	Role             string
+	CreatedAt        time.Time
+}
@@ -0,0 +1,64 @@
+package models
+
+import "time"
+
+// Channels one-time passcodes are delivered through.
+const (
+	OTPChannelEmail = "email"
+	OTPChannelSMS   = "sms"
+)
+
+// OTPChannels are all supported channels.
+var OTPChannels = []string{OTPChannelEmail, OTPChannelSMS}
+
+// Purposes of one-time passcode challenges.
+const (
+	// OTPPurposeLogin is login with a passcode instead of password.
+	OTPPurposeLogin = "login"
+	// OTPPurposeSecondFactor is confirmation of login with password.
+	OTPPurposeSecondFactor = "second_factor"
+	// OTPPurposeEnroll is confirmation of a new factor.
+	OTPPurposeEnroll = "enroll"
+)
+
+// OTPFactor is a confirmed email address or phone number the user
+// receives one-time passcodes at. Users have at most one per channel.
+type OTPFactor struct {
+	ID          int64
+	UserID      int64
+	Channel     string
+	Destination string
+	CreatedAt   time.Time
+}
+
+// OTPChallenge is a one-time passcode the user must enter, identified by
+// hash of its challenge token. Second factor challenges have no code until
+// the user chooses a factor to receive it at.
+type OTPChallenge struct {
+	Purpose string
+	UserID  int64
+	// FactorID is the factor the code was sent to, 0 if it was sent to the
+	// account email or to a destination being enrolled.
+	FactorID    int64
+	Channel     string
+	Destination string
+	// AppID, OrganizationID, Scopes and GrantType are the login the
+	// challenge confirms.
+	AppID          int
+	OrganizationID int64
+	Scopes         []string
+	GrantType      string
+	// CodeHash is hash of the code, empty until a code is sent.
+	CodeHash string
+	// Attempts is how many times the current code was entered.
+	Attempts int
+	// Sends is how many codes were sent.
+	Sends     int
+	SentAt    time.Time
+	ExpiresAt time.Time
+}
+
+// Expired reports whether the challenge has expired at the time.
+func (c OTPChallenge) Expired(at time.Time) bool {
+	return !at.Before(c.ExpiresAt)
+}
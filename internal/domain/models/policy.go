@@ -0,0 +1,43 @@
+package models
+
+import "time"
+
+type Policy struct {
+	ID          int64
+	AppID       int
+	Name        string
+	Description string
+	Effect      string
+	Actions     []string
+	Resources   []string
+	Conditions  []PolicyCondition
+}
+
+// PolicyCondition compares request attribute with Value.
+// Value starting with "$" refers to another attribute, e.g. "$subject.id".
+type PolicyCondition struct {
+	Attribute string `json:"attribute"`
+	Operator  string `json:"operator"`
+	Value     string `json:"value"`
+}
+
+// AuthorizationRequest is a recorded authorization decision.
+// Attributes hold the full evaluation input, so the request can be
+// replayed against other policies.
+type AuthorizationRequest struct {
+	ID           int64
+	AppID        int
+	UserID       int64
+	Action       string
+	ResourceType string
+	Attributes   map[string]string
+	Allowed      bool
+	CreatedAt    time.Time
+}
+
+// Resource is the object of an authorization request.
+type Resource struct {
+	Type       string
+	ID         string
+	Attributes map[string]string
+}
@@ -0,0 +1,14 @@
+package models
+
+type Role struct {
+	ID          int64
+	AppID       int
+	Name        string
+	Permissions []string
+}
+
+type Permission struct {
+	ID    int64
+	AppID int
+	Name  string
+}
@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// User fields SAML attributes may carry, besides the profile fields.
+const (
+	FieldID     = "id"
+	FieldEmail  = "email"
+	FieldSex    = "sex"
+	FieldRoles  = "roles"
+	FieldGroups = "groups"
+)
+
+// SAMLFields are all user fields SAML attributes may carry. Roles and
+// groups are the ones the user holds in the app of the service provider.
+var SAMLFields = []string{
+	FieldID,
+	FieldEmail,
+	FieldUsername,
+	FieldSex,
+	FieldLocation,
+	FieldDateOfBirth,
+	FieldRoles,
+	FieldGroups,
+}
+
+// ServiceProvider is a SAML service provider registered for the app, users
+// sign in to it with the app's login.
+type ServiceProvider struct {
+	AppID    int
+	EntityID string
+	// ACSURLs are URLs of assertion consumer services responses are posted
+	// to, the first one is the default.
+	ACSURLs      []string
+	NameIDFormat string
+	// Attributes maps names of SAML attributes to user fields they carry.
+	Attributes map[string]string
+	CreatedAt  time.Time
+}
@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// ServiceAccount is a non-human user owned by either an app or an
+// organization. Its ID is its user ID, so it holds roles and groups like
+// any user.
+type ServiceAccount struct {
+	ID             int64
+	AppID          int
+	OrganizationID int64
+	Name           string
+	Description    string
+	Disabled       bool
+	CreatedBy      int64
+	CreatedAt      time.Time
+}
+
+// ServiceAccountKey is a public key service account signs JWT assertions with.
+type ServiceAccountKey struct {
+	ID               int64
+	ServiceAccountID int64
+	KID              string
+	PublicKey        string
+	// ExpiresAt is zero for keys that never expire.
+	ExpiresAt time.Time
+	CreatedAt time.Time
+}
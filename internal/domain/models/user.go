@@ -1,5 +1,11 @@
 package models
 
+// Kinds of users.
+const (
+	UserKindHuman   = "human"
+	UserKindService = "service"
+)
+
 type User struct {
 	ID          int64
 	Email       string
@@ -8,4 +14,17 @@ type User struct {
 	Sex         string
 	Location    string
 	DateOfBirth string
+	// Kind is UserKindService for service accounts, which can't log in with password.
+	Kind string
+}
+
+// IsServiceAccount reports whether the user is a service account.
+func (u User) IsServiceAccount() bool {
+	return u.Kind == UserKindService
+}
+
+// HasPassword reports whether the user can log in with password, users
+// signed up through upstream providers have none.
+func (u User) HasPassword() bool {
+	return len(u.PassHash) > 0
 }
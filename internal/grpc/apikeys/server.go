@@ -0,0 +1,192 @@
+package apikeys
+
+import (
+	"SSO/internal/domain/models"
+	"SSO/internal/grpc/interceptors"
+	"SSO/internal/lib/jwt"
+	"SSO/internal/lib/validations"
+	"SSO/internal/services/apikeys"
+	"SSO/internal/services/auth"
+	"context"
+	"errors"
+	ssov1 "github.com/futod4m4/protos/gen/go/sso"
+	"github.com/go-playground/validator/v10"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"time"
+)
+
+type serverAPI struct {
+	ssov1.UnimplementedAPIKeysServer
+	keys APIKeys
+}
+
+type APIKeys interface {
+	Create(
+		ctx context.Context,
+		caller jwt.Claims,
+		name string,
+		scopes []string,
+		expiresAt time.Time,
+	) (key string, apiKey models.APIKey, err error)
+	Keys(ctx context.Context, userID int64) ([]models.APIKey, error)
+	Revoke(ctx context.Context, userID, keyID int64) error
+	Exchange(ctx context.Context, key string) (token string, err error)
+}
+
+var (
+	validate = validator.New(validator.WithRequiredStructEnabled())
+)
+
+func Register(gRPC *grpc.Server, keys APIKeys) {
+	ssov1.RegisterAPIKeysServer(gRPC, &serverAPI{keys: keys})
+}
+
+func (s *serverAPI) CreateAPIKey(
+	ctx context.Context,
+	req *ssov1.CreateAPIKeyRequest,
+) (*ssov1.CreateAPIKeyResponse, error) {
+
+	claims, err := interceptors.RequireClaims(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validations.ValidateAPIKeyName(req.GetName(), validate); err != nil {
+		return nil, err
+	}
+
+	var expiresAt time.Time
+	if req.GetExpiresAt() != 0 {
+		expiresAt = time.Unix(req.GetExpiresAt(), 0)
+	}
+
+	key, apiKey, err := s.keys.Create(ctx, claims, req.GetName(), req.GetScopes(), expiresAt)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.CreateAPIKeyResponse{
+		ApiKey: ToAPIKey(apiKey),
+		Key:    key,
+	}, nil
+}
+
+func (s *serverAPI) ListAPIKeys(
+	ctx context.Context,
+	req *ssov1.ListAPIKeysRequest,
+) (*ssov1.ListAPIKeysResponse, error) {
+
+	claims, err := interceptors.RequireClaims(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := s.keys.Keys(ctx, claims.UserID)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp := &ssov1.ListAPIKeysResponse{
+		ApiKeys: make([]*ssov1.APIKey, 0, len(keys)),
+	}
+	for _, key := range keys {
+		resp.ApiKeys = append(resp.ApiKeys, ToAPIKey(key))
+	}
+
+	return resp, nil
+}
+
+func (s *serverAPI) RevokeAPIKey(
+	ctx context.Context,
+	req *ssov1.RevokeAPIKeyRequest,
+) (*ssov1.RevokeAPIKeyResponse, error) {
+
+	claims, err := interceptors.RequireClaims(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validations.ValidateAPIKeyId(req.GetKeyId(), validate); err != nil {
+		return nil, err
+	}
+
+	if err := s.keys.Revoke(ctx, claims.UserID, req.GetKeyId()); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.RevokeAPIKeyResponse{}, nil
+}
+
+func (s *serverAPI) ExchangeAPIKey(
+	ctx context.Context,
+	req *ssov1.ExchangeAPIKeyRequest,
+) (*ssov1.ExchangeAPIKeyResponse, error) {
+
+	if err := validate.Var(req.GetKey(), "required"); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "key is required")
+	}
+
+	token, err := s.keys.Exchange(ctx, req.GetKey())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.ExchangeAPIKeyResponse{
+		Token: token,
+	}, nil
+}
+
+func toStatus(err error) error {
+	switch {
+	case errors.Is(err, apikeys.ErrInvalidKey):
+		return status.Error(codes.Unauthenticated, "invalid api key")
+	case errors.Is(err, apikeys.ErrKeyNotFound):
+		return status.Error(codes.NotFound, "api key not found")
+	case errors.Is(err, apikeys.ErrKeyExists):
+		return status.Error(codes.AlreadyExists, "api key with this name already exists")
+	case errors.Is(err, apikeys.ErrInvalidExpiry):
+		return status.Error(codes.InvalidArgument, "expires_at must be in the future")
+	case errors.Is(err, apikeys.ErrInvalidScope):
+		return status.Error(codes.InvalidArgument, "scopes must not be empty")
+	case errors.Is(err, apikeys.ErrKeyFromKey):
+		return status.Error(codes.PermissionDenied, "api keys can't be created with api key")
+	case errors.Is(err, apikeys.ErrImpersonated):
+		return status.Error(codes.PermissionDenied, "api keys can't be created with impersonation token")
+	case errors.Is(err, apikeys.ErrScopedToken):
+		return status.Error(codes.PermissionDenied, "api keys can't be created with scoped token")
+	case errors.Is(err, apikeys.ErrAppNotFound), errors.Is(err, auth.ErrInvalidAppID):
+		return status.Error(codes.NotFound, "app not found")
+	case errors.Is(err, auth.ErrAppDisabled):
+		return status.Error(codes.FailedPrecondition, "app is disabled")
+	case errors.Is(err, auth.ErrGrantNotAllowed):
+		return status.Error(codes.PermissionDenied, "api keys are not allowed for the app")
+	case errors.Is(err, auth.ErrUserNotFound):
+		return status.Error(codes.Unauthenticated, "invalid api key")
+	}
+
+	return status.Error(codes.Internal, "internal error")
+}
+
+// ToAPIKey converts API key to its protobuf message.
+func ToAPIKey(key models.APIKey) *ssov1.APIKey {
+	return &ssov1.APIKey{
+		Id:         key.ID,
+		AppId:      int32(key.AppID),
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		Scopes:     key.Scopes,
+		ExpiresAt:  unixOrZero(key.ExpiresAt),
+		LastUsedAt: unixOrZero(key.LastUsedAt),
+		CreatedAt:  key.CreatedAt.Unix(),
+	}
+}
+
+func unixOrZero(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+
+	return t.Unix()
+}
@@ -0,0 +1,475 @@
+package apps
+
+import (
+	"SSO/internal/domain/models"
+	"SSO/internal/grpc/interceptors"
+	"SSO/internal/lib/validations"
+	"SSO/internal/services/apps"
+	"context"
+	"errors"
+	ssov1 "github.com/futod4m4/protos/gen/go/sso"
+	"github.com/go-playground/validator/v10"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type serverAPI struct {
+	ssov1.UnimplementedAppsServer
+	apps   Apps
+	admins interceptors.AppAdminChecker
+}
+
+type Apps interface {
+	AdminAppID() int
+	CreateApp(ctx context.Context, name string, redirectURIs, grantTypes []string) (models.App, error)
+	UpdateApp(ctx context.Context, appID int, name string, redirectURIs, grantTypes []string) error
+	App(ctx context.Context, appID int) (models.App, error)
+	Apps(ctx context.Context) ([]models.App, error)
+	SetDisabled(ctx context.Context, appID int, disabled bool) error
+	DeleteApp(ctx context.Context, appID int) error
+	RotateSecret(ctx context.Context, appID int) (secret string, kid string, err error)
+	Secrets(ctx context.Context, appID int) (primaryKID string, rotated []models.AppSecret, err error)
+	RevokeSecret(ctx context.Context, appID int, kid string) error
+	SetRegistrationRules(ctx context.Context, appID int, rules models.RegistrationRules) error
+	SetTokenExchangeRule(ctx context.Context, rule models.TokenExchangeRule) error
+	TokenExchangeRules(ctx context.Context, targetAppID int) ([]models.TokenExchangeRule, error)
+	DeleteTokenExchangeRule(ctx context.Context, sourceAppID, targetAppID int) error
+	SetConsentRequired(ctx context.Context, appID int, required bool) error
+}
+
+var (
+	validate = validator.New(validator.WithRequiredStructEnabled())
+)
+
+func Register(gRPC *grpc.Server, apps Apps, admins interceptors.AppAdminChecker) {
+	ssov1.RegisterAppsServer(gRPC, &serverAPI{apps: apps, admins: admins})
+}
+
+func (s *serverAPI) CreateApp(
+	ctx context.Context,
+	req *ssov1.CreateAppRequest,
+) (*ssov1.CreateAppResponse, error) {
+
+	if err := validations.ValidateAppName(req.GetName(), validate); err != nil {
+		return nil, err
+	}
+
+	if err := validations.ValidateRedirectURIs(req.GetRedirectUris(), validate); err != nil {
+		return nil, err
+	}
+
+	if err := s.requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	app, err := s.apps.CreateApp(ctx, req.GetName(), req.GetRedirectUris(), req.GetGrantTypes())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.CreateAppResponse{
+		App:    toApp(app),
+		Secret: app.Secret,
+	}, nil
+}
+
+func (s *serverAPI) GetApp(
+	ctx context.Context,
+	req *ssov1.GetAppRequest,
+) (*ssov1.GetAppResponse, error) {
+
+	if err := validations.ValidateAppId(req.GetAppId(), validate); err != nil {
+		return nil, err
+	}
+
+	if err := s.requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	app, err := s.apps.App(ctx, int(req.GetAppId()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.GetAppResponse{
+		App: toApp(app),
+	}, nil
+}
+
+func (s *serverAPI) ListApps(
+	ctx context.Context,
+	req *ssov1.ListAppsRequest,
+) (*ssov1.ListAppsResponse, error) {
+
+	if err := s.requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	apps, err := s.apps.Apps(ctx)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp := &ssov1.ListAppsResponse{
+		Apps: make([]*ssov1.App, 0, len(apps)),
+	}
+	for _, app := range apps {
+		resp.Apps = append(resp.Apps, toApp(app))
+	}
+
+	return resp, nil
+}
+
+func (s *serverAPI) UpdateApp(
+	ctx context.Context,
+	req *ssov1.UpdateAppRequest,
+) (*ssov1.UpdateAppResponse, error) {
+
+	if err := validations.ValidateAppId(req.GetAppId(), validate); err != nil {
+		return nil, err
+	}
+
+	if err := validations.ValidateAppName(req.GetName(), validate); err != nil {
+		return nil, err
+	}
+
+	if err := validations.ValidateRedirectURIs(req.GetRedirectUris(), validate); err != nil {
+		return nil, err
+	}
+
+	if err := s.requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	err := s.apps.UpdateApp(ctx, int(req.GetAppId()), req.GetName(), req.GetRedirectUris(), req.GetGrantTypes())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.UpdateAppResponse{}, nil
+}
+
+func (s *serverAPI) DisableApp(
+	ctx context.Context,
+	req *ssov1.DisableAppRequest,
+) (*ssov1.DisableAppResponse, error) {
+
+	if err := validations.ValidateAppId(req.GetAppId(), validate); err != nil {
+		return nil, err
+	}
+
+	if err := s.requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := s.apps.SetDisabled(ctx, int(req.GetAppId()), true); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.DisableAppResponse{}, nil
+}
+
+func (s *serverAPI) EnableApp(
+	ctx context.Context,
+	req *ssov1.EnableAppRequest,
+) (*ssov1.EnableAppResponse, error) {
+
+	if err := validations.ValidateAppId(req.GetAppId(), validate); err != nil {
+		return nil, err
+	}
+
+	if err := s.requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := s.apps.SetDisabled(ctx, int(req.GetAppId()), false); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.EnableAppResponse{}, nil
+}
+
+func (s *serverAPI) DeleteApp(
+	ctx context.Context,
+	req *ssov1.DeleteAppRequest,
+) (*ssov1.DeleteAppResponse, error) {
+
+	if err := validations.ValidateAppId(req.GetAppId(), validate); err != nil {
+		return nil, err
+	}
+
+	if err := s.requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := s.apps.DeleteApp(ctx, int(req.GetAppId())); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.DeleteAppResponse{}, nil
+}
+
+func (s *serverAPI) RotateAppSecret(
+	ctx context.Context,
+	req *ssov1.RotateAppSecretRequest,
+) (*ssov1.RotateAppSecretResponse, error) {
+
+	if err := validations.ValidateAppId(req.GetAppId(), validate); err != nil {
+		return nil, err
+	}
+
+	if err := s.requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	secret, kid, err := s.apps.RotateSecret(ctx, int(req.GetAppId()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.RotateAppSecretResponse{
+		Secret: secret,
+		Kid:    kid,
+	}, nil
+}
+
+func (s *serverAPI) ListAppSecrets(
+	ctx context.Context,
+	req *ssov1.ListAppSecretsRequest,
+) (*ssov1.ListAppSecretsResponse, error) {
+
+	if err := validations.ValidateAppId(req.GetAppId(), validate); err != nil {
+		return nil, err
+	}
+
+	if err := s.requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	primaryKID, rotated, err := s.apps.Secrets(ctx, int(req.GetAppId()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp := &ssov1.ListAppSecretsResponse{
+		PrimaryKid: primaryKID,
+		Rotated:    make([]*ssov1.AppSecret, 0, len(rotated)),
+	}
+	for _, secret := range rotated {
+		resp.Rotated = append(resp.Rotated, &ssov1.AppSecret{
+			Kid:       secret.KID,
+			RotatedAt: secret.RotatedAt.Unix(),
+			ExpiresAt: secret.ExpiresAt.Unix(),
+		})
+	}
+
+	return resp, nil
+}
+
+func (s *serverAPI) RevokeAppSecret(
+	ctx context.Context,
+	req *ssov1.RevokeAppSecretRequest,
+) (*ssov1.RevokeAppSecretResponse, error) {
+
+	if err := validations.ValidateAppId(req.GetAppId(), validate); err != nil {
+		return nil, err
+	}
+
+	if err := validate.Var(req.GetKid(), "required"); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "kid is required")
+	}
+
+	if err := s.requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := s.apps.RevokeSecret(ctx, int(req.GetAppId()), req.GetKid()); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.RevokeAppSecretResponse{}, nil
+}
+
+func (s *serverAPI) SetRegistrationRules(
+	ctx context.Context,
+	req *ssov1.SetRegistrationRulesRequest,
+) (*ssov1.SetRegistrationRulesResponse, error) {
+
+	if err := validations.ValidateAppId(req.GetAppId(), validate); err != nil {
+		return nil, err
+	}
+
+	if err := s.requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	rules := req.GetRules()
+	err := s.apps.SetRegistrationRules(ctx, int(req.GetAppId()), models.RegistrationRules{
+		Mode:                rules.GetMode(),
+		AllowedEmailDomains: rules.GetAllowedEmailDomains(),
+		RequiredFields:      rules.GetRequiredFields(),
+		MinAge:              int(rules.GetMinAge()),
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.SetRegistrationRulesResponse{}, nil
+}
+
+func (s *serverAPI) SetTokenExchangeRule(
+	ctx context.Context,
+	req *ssov1.SetTokenExchangeRuleRequest,
+) (*ssov1.SetTokenExchangeRuleResponse, error) {
+
+	if err := validations.ValidateAppId(req.GetSourceAppId(), validate); err != nil {
+		return nil, err
+	}
+
+	if err := validations.ValidateAppId(req.GetTargetAppId(), validate); err != nil {
+		return nil, err
+	}
+
+	if err := s.requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	err := s.apps.SetTokenExchangeRule(ctx, models.TokenExchangeRule{
+		SourceAppID: int(req.GetSourceAppId()),
+		TargetAppID: int(req.GetTargetAppId()),
+		Scopes:      req.GetScopes(),
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.SetTokenExchangeRuleResponse{}, nil
+}
+
+func (s *serverAPI) ListTokenExchangeRules(
+	ctx context.Context,
+	req *ssov1.ListTokenExchangeRulesRequest,
+) (*ssov1.ListTokenExchangeRulesResponse, error) {
+
+	if err := validations.ValidateAppId(req.GetTargetAppId(), validate); err != nil {
+		return nil, err
+	}
+
+	if err := s.requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	rules, err := s.apps.TokenExchangeRules(ctx, int(req.GetTargetAppId()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp := &ssov1.ListTokenExchangeRulesResponse{
+		Rules: make([]*ssov1.TokenExchangeRule, 0, len(rules)),
+	}
+	for _, rule := range rules {
+		resp.Rules = append(resp.Rules, &ssov1.TokenExchangeRule{
+			SourceAppId: int32(rule.SourceAppID),
+			TargetAppId: int32(rule.TargetAppID),
+			Scopes:      rule.Scopes,
+			CreatedAt:   rule.CreatedAt.Unix(),
+		})
+	}
+
+	return resp, nil
+}
+
+func (s *serverAPI) DeleteTokenExchangeRule(
+	ctx context.Context,
+	req *ssov1.DeleteTokenExchangeRuleRequest,
+) (*ssov1.DeleteTokenExchangeRuleResponse, error) {
+
+	if err := validations.ValidateAppId(req.GetSourceAppId(), validate); err != nil {
+		return nil, err
+	}
+
+	if err := validations.ValidateAppId(req.GetTargetAppId(), validate); err != nil {
+		return nil, err
+	}
+
+	if err := s.requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := s.apps.DeleteTokenExchangeRule(ctx, int(req.GetSourceAppId()), int(req.GetTargetAppId())); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.DeleteTokenExchangeRuleResponse{}, nil
+}
+
+func (s *serverAPI) SetConsentRequired(
+	ctx context.Context,
+	req *ssov1.SetConsentRequiredRequest,
+) (*ssov1.SetConsentRequiredResponse, error) {
+
+	if err := validations.ValidateAppId(req.GetAppId(), validate); err != nil {
+		return nil, err
+	}
+
+	if err := s.requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := s.apps.SetConsentRequired(ctx, int(req.GetAppId()), req.GetRequired()); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.SetConsentRequiredResponse{}, nil
+}
+
+// requireAdmin checks that caller is an admin of the admin app.
+func (s *serverAPI) requireAdmin(ctx context.Context) error {
+	return interceptors.RequireAppAdmin(ctx, s.apps.AdminAppID(), s.admins)
+}
+
+func toStatus(err error) error {
+	switch {
+	case errors.Is(err, apps.ErrAppNotFound):
+		return status.Error(codes.NotFound, "app not found")
+	case errors.Is(err, apps.ErrSecretNotFound):
+		return status.Error(codes.NotFound, "app secret not found")
+	case errors.Is(err, apps.ErrAppExists):
+		return status.Error(codes.AlreadyExists, "app already exists")
+	case errors.Is(err, apps.ErrAdminApp):
+		return status.Error(codes.FailedPrecondition, "admin app can't be disabled or deleted")
+	case errors.Is(err, apps.ErrInvalidGrantType):
+		return status.Error(codes.InvalidArgument, "invalid grant type")
+	case errors.Is(err, apps.ErrInvalidRules):
+		return status.Error(codes.InvalidArgument, "invalid registration rules")
+	case errors.Is(err, apps.ErrRuleNotFound):
+		return status.Error(codes.NotFound, "token exchange rule not found")
+	case errors.Is(err, apps.ErrInvalidScope):
+		return status.Error(codes.InvalidArgument, "scopes must not be empty")
+	}
+
+	return status.Error(codes.Internal, "internal error")
+}
+
+func toApp(app models.App) *ssov1.App {
+	return &ssov1.App{
+		Id:           int32(app.ID),
+		Name:         app.Name,
+		RedirectUris: app.RedirectURIs,
+		GrantTypes:   app.GrantTypes,
+		Disabled:     app.Disabled,
+		GroupClaims:  app.GroupClaims,
+		CreatedAt:    app.CreatedAt.Unix(),
+		SecretKid:    app.SecretKID,
+		Registration: &ssov1.RegistrationRules{
+			Mode:                app.Registration.Mode,
+			AllowedEmailDomains: app.Registration.AllowedEmailDomains,
+			RequiredFields:      app.Registration.RequiredFields,
+			MinAge:              int32(app.Registration.MinAge),
+		},
+		ConsentRequired:         app.ConsentRequired,
+		TokenEndpointAuthMethod: app.TokenEndpointAuthMethod,
+		SelfRegistered:          app.SelfRegistered,
+	}
+}
@@ -0,0 +1,78 @@
+package audit
+
+import (
+	"SSO/internal/domain/models"
+	"SSO/internal/grpc/interceptors"
+	"SSO/internal/lib/validations"
+	"context"
+	ssov1 "github.com/futod4m4/protos/gen/go/sso"
+	"github.com/go-playground/validator/v10"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type serverAPI struct {
+	ssov1.UnimplementedAuditServer
+	audit  Audit
+	admins interceptors.AppAdminChecker
+}
+
+type Audit interface {
+	Events(ctx context.Context, filter models.AuditFilter) ([]models.AuditEvent, error)
+}
+
+var (
+	validate = validator.New(validator.WithRequiredStructEnabled())
+)
+
+func Register(gRPC *grpc.Server, audit Audit, admins interceptors.AppAdminChecker) {
+	ssov1.RegisterAuditServer(gRPC, &serverAPI{audit: audit, admins: admins})
+}
+
+func (s *serverAPI) ListAuditEvents(
+	ctx context.Context,
+	req *ssov1.ListAuditEventsRequest,
+) (*ssov1.ListAuditEventsResponse, error) {
+
+	if err := validations.ValidateAppId(req.GetAppId(), validate); err != nil {
+		return nil, err
+	}
+
+	if err := validate.Var(req.GetLimit(), "gte=0"); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "limit must not be negative")
+	}
+
+	if err := interceptors.RequireAppAdmin(ctx, int(req.GetAppId()), s.admins); err != nil {
+		return nil, err
+	}
+
+	events, err := s.audit.Events(ctx, models.AuditFilter{
+		AppID:     int(req.GetAppId()),
+		UserID:    req.GetUserId(),
+		SessionID: req.GetSessionId(),
+		BeforeID:  req.GetBeforeId(),
+		Limit:     int(req.GetLimit()),
+	})
+	if err != nil {
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	resp := &ssov1.ListAuditEventsResponse{
+		Events: make([]*ssov1.AuditEvent, 0, len(events)),
+	}
+	for _, e := range events {
+		resp.Events = append(resp.Events, &ssov1.AuditEvent{
+			Id:        e.ID,
+			Action:    e.Action,
+			AppId:     int32(e.AppID),
+			ActorId:   e.ActorID,
+			UserId:    e.UserID,
+			SessionId: e.SessionID,
+			Details:   e.Details,
+			CreatedAt: e.CreatedAt.Unix(),
+		})
+	}
+
+	return resp, nil
+}
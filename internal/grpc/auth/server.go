@@ -1,8 +1,11 @@
 package auth
 
 import (
+	"SSO/internal/domain/models"
+	"SSO/internal/lib/jwt"
 	"SSO/internal/lib/validations"
 	"SSO/internal/services/auth"
+	"SSO/internal/services/otp"
 	"context"
 	"errors"
 	ssov1 "github.com/futod4m4/protos/gen/go/sso"
@@ -10,6 +13,7 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"time"
 )
 
 type serverAPI struct {
@@ -23,6 +27,8 @@ type Auth interface {
 		email string,
 		password string,
 		appId int,
+		orgID int64,
+		scopes []string,
 	) (token string, err error)
 	RegisterNewUser(
 		ctx context.Context,
@@ -32,9 +38,17 @@ type Auth interface {
 		sex string,
 		location string,
 		dateOfBirth string,
+		appID int,
 	) (userID int64, err error)
 	IsAdmin(ctx context.Context, userID int64) (bool, error)
 	IsUserExists(ctx context.Context, email string) (bool, error)
+	VerifyToken(ctx context.Context, token string) (jwt.Claims, error)
+	ExchangeToken(
+		ctx context.Context,
+		subjectToken string,
+		appID int,
+		scopes []string,
+	) (token string, expiresAt time.Time, err error)
 }
 
 var (
@@ -54,11 +68,46 @@ func (s *serverAPI) Login(
 		return nil, err
 	}
 
-	token, err := s.auth.Login(ctx, req.GetEmail(), req.GetPassword(), int(req.GetAppId()))
+	token, err := s.auth.Login(
+		ctx,
+		req.GetEmail(),
+		req.GetPassword(),
+		int(req.GetAppId()),
+		req.GetOrganizationId(),
+		req.GetScopes(),
+	)
 	if err != nil {
+		var secondFactor *auth.SecondFactorRequiredError
+		if errors.As(err, &secondFactor) {
+			return toSecondFactorResponse(secondFactor), nil
+		}
 		if errors.Is(err, auth.ErrInvalidCredentials) {
 			return nil, status.Error(codes.InvalidArgument, "email or password is incorrect")
 		}
+		if errors.Is(err, auth.ErrSecondFactorTooSoon) {
+			return nil, status.Error(codes.ResourceExhausted, "second factor code was sent recently")
+		}
+		if errors.Is(err, auth.ErrNotOrgMember) {
+			return nil, status.Error(codes.PermissionDenied, "user is not a member of the organization")
+		}
+		if errors.Is(err, auth.ErrOrgAppNotAllowed) {
+			return nil, status.Error(codes.PermissionDenied, "organization has no access to the app")
+		}
+		if errors.Is(err, auth.ErrAppDisabled) {
+			return nil, status.Error(codes.FailedPrecondition, "app is disabled")
+		}
+		if errors.Is(err, auth.ErrGrantNotAllowed) {
+			return nil, status.Error(codes.PermissionDenied, "password login is not allowed for the app")
+		}
+		if errors.Is(err, auth.ErrScopeRequired) {
+			return nil, status.Error(codes.InvalidArgument, "app must request scopes")
+		}
+		if errors.Is(err, auth.ErrUnknownScope) {
+			return nil, status.Error(codes.InvalidArgument, "scope is not defined for the app")
+		}
+		if errors.Is(err, auth.ErrConsentRequired) {
+			return nil, status.Error(codes.FailedPrecondition, "user hasn't consented to the requested scopes")
+		}
 		return nil, status.Error(codes.Internal, "internal error")
 	}
 
@@ -76,11 +125,36 @@ func (s *serverAPI) Register(
 		return nil, err
 	}
 
-	userID, err := s.auth.RegisterNewUser(ctx, req.GetEmail(), req.GetPassword(), req.GetUsername(), req.GetSex(), req.GetLocation(), req.GetDateOfBirth())
+	userID, err := s.auth.RegisterNewUser(ctx, req.GetEmail(), req.GetPassword(), req.GetUsername(), req.GetSex(), req.GetLocation(), req.GetDateOfBirth(), int(req.GetAppId()))
 	if err != nil {
 		if errors.Is(err, auth.ErrUserExists) {
 			return nil, status.Error(codes.AlreadyExists, "user already exists")
 		}
+		if errors.Is(err, auth.ErrInvalidAppID) {
+			return nil, status.Error(codes.NotFound, "app not found")
+		}
+		if errors.Is(err, auth.ErrAppDisabled) {
+			return nil, status.Error(codes.FailedPrecondition, "app is disabled")
+		}
+		if errors.Is(err, auth.ErrRegistrationClosed) {
+			return nil, status.Error(codes.PermissionDenied, "registration is closed for the app")
+		}
+		if errors.Is(err, auth.ErrInviteOnly) {
+			return nil, status.Error(codes.PermissionDenied, "registration for the app is by invitation only")
+		}
+		if errors.Is(err, auth.ErrEmailDomainNotAllowed) {
+			return nil, status.Error(codes.InvalidArgument, "email domain is not allowed for the app")
+		}
+		var fieldErr *auth.FieldRequiredError
+		if errors.As(err, &fieldErr) {
+			return nil, status.Error(codes.InvalidArgument, fieldErr.Error())
+		}
+		if errors.Is(err, auth.ErrInvalidDateOfBirth) {
+			return nil, status.Error(codes.InvalidArgument, "date of birth must be in YYYY-MM-DD format")
+		}
+		if errors.Is(err, auth.ErrTooYoung) {
+			return nil, status.Error(codes.FailedPrecondition, "user is too young to register for the app")
+		}
 
 		return nil, status.Error(codes.Internal, "internal error")
 	}
@@ -128,3 +202,76 @@ func (s *serverAPI) IsUserExists(
 		IsExists: isExists,
 	}, nil
 }
+
+func (s *serverAPI) ExchangeToken(
+	ctx context.Context,
+	req *ssov1.ExchangeTokenRequest,
+) (*ssov1.ExchangeTokenResponse, error) {
+
+	if err := validations.ValidateExchangeToken(req, validate); err != nil {
+		return nil, err
+	}
+
+	token, expiresAt, err := s.auth.ExchangeToken(ctx, req.GetSubjectToken(), int(req.GetAppId()), req.GetScopes())
+	if err != nil {
+		if errors.Is(err, auth.ErrInvalidToken) {
+			return nil, status.Error(codes.Unauthenticated, "invalid subject token")
+		}
+		if errors.Is(err, auth.ErrInvalidAppID) {
+			return nil, status.Error(codes.NotFound, "app not found")
+		}
+		if errors.Is(err, auth.ErrAppDisabled) {
+			return nil, status.Error(codes.FailedPrecondition, "app is disabled")
+		}
+		if errors.Is(err, auth.ErrGrantNotAllowed) {
+			return nil, status.Error(codes.PermissionDenied, "token exchange is not allowed for the app")
+		}
+		if errors.Is(err, auth.ErrExchangeNotAllowed) {
+			return nil, status.Error(codes.PermissionDenied, "tokens of the app can't be exchanged for the app")
+		}
+		if errors.Is(err, auth.ErrInvalidScope) {
+			return nil, status.Error(codes.PermissionDenied, "requested scopes are not allowed")
+		}
+		if errors.Is(err, auth.ErrNotOrgMember) {
+			return nil, status.Error(codes.PermissionDenied, "user is not a member of the organization")
+		}
+		if errors.Is(err, auth.ErrOrgAppNotAllowed) || errors.Is(err, auth.ErrAccountAppNotAllowed) {
+			return nil, status.Error(codes.PermissionDenied, "no access to the app")
+		}
+		if errors.Is(err, auth.ErrAccountDisabled) {
+			return nil, status.Error(codes.FailedPrecondition, "service account is disabled")
+		}
+
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	return &ssov1.ExchangeTokenResponse{
+		Token:           token,
+		IssuedTokenType: models.TokenTypeAccessToken,
+		ExpiresAt:       expiresAt.Unix(),
+	}, nil
+}
+
+// toSecondFactorResponse returns the challenge the user must pass to
+// complete login, with masked factors to pick from.
+func toSecondFactorResponse(err *auth.SecondFactorRequiredError) *ssov1.LoginResponse {
+	return &ssov1.LoginResponse{
+		SecondFactorChallenge: err.Challenge,
+		SecondFactors:         ToSecondFactors(err),
+	}
+}
+
+// ToSecondFactors converts factors the code of the second factor challenge
+// can be sent to into protobuf messages, with destinations masked.
+func ToSecondFactors(err *auth.SecondFactorRequiredError) []*ssov1.SecondFactor {
+	factors := make([]*ssov1.SecondFactor, 0, len(err.Factors))
+	for _, factor := range err.Factors {
+		factors = append(factors, &ssov1.SecondFactor{
+			FactorId:    factor.ID,
+			Channel:     factor.Channel,
+			Destination: otp.MaskDestination(factor.Channel, factor.Destination),
+		})
+	}
+
+	return factors
+}
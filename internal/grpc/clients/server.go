@@ -0,0 +1,300 @@
+package clients
+
+import (
+	"SSO/internal/domain/models"
+	"SSO/internal/grpc/interceptors"
+	"SSO/internal/lib/validations"
+	"SSO/internal/services/clients"
+	"context"
+	"errors"
+	ssov1 "github.com/futod4m4/protos/gen/go/sso"
+	"github.com/go-playground/validator/v10"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"time"
+)
+
+type serverAPI struct {
+	ssov1.UnimplementedClientRegistrationServer
+	clients    Clients
+	adminAppID int
+	admins     interceptors.AppAdminChecker
+}
+
+type Clients interface {
+	Register(ctx context.Context, initialToken string, metadata models.ClientMetadata) (clients.Registration, error)
+	Client(ctx context.Context, appID int, registrationToken string) (models.App, error)
+	Update(ctx context.Context, appID int, registrationToken string, metadata models.ClientMetadata) (models.App, error)
+	Delete(ctx context.Context, appID int, registrationToken string) error
+	CreateInitialToken(
+		ctx context.Context,
+		createdBy int64,
+		name string,
+		maxUses int,
+		expiresAt time.Time,
+	) (string, models.InitialAccessToken, error)
+	InitialTokens(ctx context.Context) ([]models.InitialAccessToken, error)
+	RevokeInitialToken(ctx context.Context, id int64) error
+}
+
+var (
+	validate = validator.New(validator.WithRequiredStructEnabled())
+)
+
+// Register registers the server. Admins of the app with adminAppID manage
+// initial access tokens.
+func Register(gRPC *grpc.Server, clients Clients, adminAppID int, admins interceptors.AppAdminChecker) {
+	ssov1.RegisterClientRegistrationServer(gRPC, &serverAPI{clients: clients, adminAppID: adminAppID, admins: admins})
+}
+
+func (s *serverAPI) RegisterClient(
+	ctx context.Context,
+	req *ssov1.RegisterClientRequest,
+) (*ssov1.RegisterClientResponse, error) {
+
+	if err := validations.ValidateInitialAccessToken(req.GetInitialAccessToken(), validate); err != nil {
+		return nil, err
+	}
+
+	if err := validations.ValidateClientMetadata(req.GetMetadata(), validate); err != nil {
+		return nil, err
+	}
+
+	reg, err := s.clients.Register(ctx, req.GetInitialAccessToken(), toMetadata(req.GetMetadata()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.RegisterClientResponse{
+		Client:                  toClient(reg.App),
+		ClientSecret:            reg.Secret,
+		ClientSecretExpiresAt:   0,
+		RegistrationAccessToken: reg.RegistrationToken,
+	}, nil
+}
+
+func (s *serverAPI) GetClient(
+	ctx context.Context,
+	req *ssov1.GetClientRequest,
+) (*ssov1.GetClientResponse, error) {
+
+	if err := validations.ValidateRegistrationAccessToken(
+		req.GetClientId(), req.GetRegistrationAccessToken(), validate,
+	); err != nil {
+		return nil, err
+	}
+
+	app, err := s.clients.Client(ctx, int(req.GetClientId()), req.GetRegistrationAccessToken())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.GetClientResponse{
+		Client: toClient(app),
+	}, nil
+}
+
+func (s *serverAPI) UpdateClient(
+	ctx context.Context,
+	req *ssov1.UpdateClientRequest,
+) (*ssov1.UpdateClientResponse, error) {
+
+	if err := validations.ValidateRegistrationAccessToken(
+		req.GetClientId(), req.GetRegistrationAccessToken(), validate,
+	); err != nil {
+		return nil, err
+	}
+
+	if err := validations.ValidateClientMetadata(req.GetMetadata(), validate); err != nil {
+		return nil, err
+	}
+
+	app, err := s.clients.Update(
+		ctx,
+		int(req.GetClientId()),
+		req.GetRegistrationAccessToken(),
+		toMetadata(req.GetMetadata()),
+	)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.UpdateClientResponse{
+		Client: toClient(app),
+	}, nil
+}
+
+func (s *serverAPI) DeleteClient(
+	ctx context.Context,
+	req *ssov1.DeleteClientRequest,
+) (*ssov1.DeleteClientResponse, error) {
+
+	if err := validations.ValidateRegistrationAccessToken(
+		req.GetClientId(), req.GetRegistrationAccessToken(), validate,
+	); err != nil {
+		return nil, err
+	}
+
+	if err := s.clients.Delete(ctx, int(req.GetClientId()), req.GetRegistrationAccessToken()); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.DeleteClientResponse{}, nil
+}
+
+func (s *serverAPI) CreateInitialAccessToken(
+	ctx context.Context,
+	req *ssov1.CreateInitialAccessTokenRequest,
+) (*ssov1.CreateInitialAccessTokenResponse, error) {
+
+	if err := validations.ValidateInitialAccessTokenName(req.GetName(), req.GetMaxUses(), validate); err != nil {
+		return nil, err
+	}
+
+	if err := s.requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	claims, err := interceptors.RequireClaims(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var expiresAt time.Time
+	if req.GetExpiresAt() != 0 {
+		expiresAt = time.Unix(req.GetExpiresAt(), 0)
+	}
+
+	token, initial, err := s.clients.CreateInitialToken(
+		ctx,
+		claims.UserID,
+		req.GetName(),
+		int(req.GetMaxUses()),
+		expiresAt,
+	)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.CreateInitialAccessTokenResponse{
+		Token:              token,
+		InitialAccessToken: toInitialToken(initial),
+	}, nil
+}
+
+func (s *serverAPI) ListInitialAccessTokens(
+	ctx context.Context,
+	req *ssov1.ListInitialAccessTokensRequest,
+) (*ssov1.ListInitialAccessTokensResponse, error) {
+
+	if err := s.requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	tokens, err := s.clients.InitialTokens(ctx)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp := &ssov1.ListInitialAccessTokensResponse{
+		Tokens: make([]*ssov1.InitialAccessToken, 0, len(tokens)),
+	}
+	for _, token := range tokens {
+		resp.Tokens = append(resp.Tokens, toInitialToken(token))
+	}
+
+	return resp, nil
+}
+
+func (s *serverAPI) RevokeInitialAccessToken(
+	ctx context.Context,
+	req *ssov1.RevokeInitialAccessTokenRequest,
+) (*ssov1.RevokeInitialAccessTokenResponse, error) {
+
+	if err := validations.ValidateInitialAccessTokenId(req.GetId(), validate); err != nil {
+		return nil, err
+	}
+
+	if err := s.requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := s.clients.RevokeInitialToken(ctx, req.GetId()); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.RevokeInitialAccessTokenResponse{}, nil
+}
+
+// requireAdmin checks that caller is an admin of the admin app.
+func (s *serverAPI) requireAdmin(ctx context.Context) error {
+	return interceptors.RequireAppAdmin(ctx, s.adminAppID, s.admins)
+}
+
+// toStatus maps errors to statuses, messages of registration errors are
+// the RFC 7591 error codes.
+func toStatus(err error) error {
+	switch {
+	case errors.Is(err, clients.ErrInvalidInitialToken):
+		return status.Error(codes.Unauthenticated, "invalid_token")
+	case errors.Is(err, clients.ErrInvalidRegistrationToken):
+		return status.Error(codes.Unauthenticated, "invalid_token")
+	case errors.Is(err, clients.ErrInvalidRedirectURI):
+		return status.Error(codes.InvalidArgument, "invalid_redirect_uri")
+	case errors.Is(err, clients.ErrInvalidMetadata):
+		return status.Error(codes.InvalidArgument, "invalid_client_metadata")
+	case errors.Is(err, clients.ErrAppExists):
+		return status.Error(codes.AlreadyExists, "app already exists")
+	case errors.Is(err, clients.ErrInitialTokenNotFound):
+		return status.Error(codes.NotFound, "initial access token not found")
+	case errors.Is(err, clients.ErrInvalidMaxUses):
+		return status.Error(codes.InvalidArgument, "max_uses must not be negative")
+	case errors.Is(err, clients.ErrInvalidExpiry):
+		return status.Error(codes.InvalidArgument, "expires_at must be in the future")
+	}
+
+	return status.Error(codes.Internal, "internal error")
+}
+
+func toMetadata(metadata *ssov1.ClientMetadata) models.ClientMetadata {
+	return models.ClientMetadata{
+		Name:                    metadata.GetClientName(),
+		RedirectURIs:            metadata.GetRedirectUris(),
+		GrantTypes:              metadata.GetGrantTypes(),
+		TokenEndpointAuthMethod: metadata.GetTokenEndpointAuthMethod(),
+	}
+}
+
+func toClient(app models.App) *ssov1.ClientInformation {
+	return &ssov1.ClientInformation{
+		ClientId:         int32(app.ID),
+		ClientIdIssuedAt: app.CreatedAt.Unix(),
+		Metadata: &ssov1.ClientMetadata{
+			ClientName:              app.Name,
+			RedirectUris:            app.RedirectURIs,
+			GrantTypes:              app.GrantTypes,
+			TokenEndpointAuthMethod: app.TokenEndpointAuthMethod,
+		},
+	}
+}
+
+func toInitialToken(token models.InitialAccessToken) *ssov1.InitialAccessToken {
+	return &ssov1.InitialAccessToken{
+		Id:        token.ID,
+		Name:      token.Name,
+		MaxUses:   int32(token.MaxUses),
+		Uses:      int32(token.Uses),
+		CreatedBy: token.CreatedBy,
+		ExpiresAt: unixOrZero(token.ExpiresAt),
+		CreatedAt: token.CreatedAt.Unix(),
+	}
+}
+
+func unixOrZero(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+
+	return t.Unix()
+}
@@ -0,0 +1,218 @@
+package consents
+
+import (
+	"SSO/internal/domain/models"
+	"SSO/internal/grpc/interceptors"
+	"SSO/internal/lib/jwt"
+	"SSO/internal/lib/validations"
+	"SSO/internal/services/consents"
+	"context"
+	"errors"
+	ssov1 "github.com/futod4m4/protos/gen/go/sso"
+	"github.com/go-playground/validator/v10"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type serverAPI struct {
+	ssov1.UnimplementedConsentsServer
+	consents Consents
+	admins   interceptors.AppAdminChecker
+}
+
+type Consents interface {
+	SetScope(ctx context.Context, appID int, name, description string) error
+	Scopes(ctx context.Context, appID int) ([]models.Scope, error)
+	DeleteScope(ctx context.Context, appID int, name string) error
+	Grant(ctx context.Context, caller jwt.Claims, appID int, scopes []string) (models.Consent, error)
+	Consents(ctx context.Context, userID int64) ([]models.Consent, error)
+	Revoke(ctx context.Context, userID int64, appID int) error
+}
+
+var (
+	validate = validator.New(validator.WithRequiredStructEnabled())
+)
+
+func Register(gRPC *grpc.Server, consents Consents, admins interceptors.AppAdminChecker) {
+	ssov1.RegisterConsentsServer(gRPC, &serverAPI{consents: consents, admins: admins})
+}
+
+func (s *serverAPI) SetScope(
+	ctx context.Context,
+	req *ssov1.SetScopeRequest,
+) (*ssov1.SetScopeResponse, error) {
+
+	if err := validations.ValidateAppId(req.GetAppId(), validate); err != nil {
+		return nil, err
+	}
+
+	if err := validations.ValidateScopeName(req.GetName(), validate); err != nil {
+		return nil, err
+	}
+
+	if err := interceptors.RequireAppAdmin(ctx, int(req.GetAppId()), s.admins); err != nil {
+		return nil, err
+	}
+
+	if err := s.consents.SetScope(ctx, int(req.GetAppId()), req.GetName(), req.GetDescription()); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.SetScopeResponse{}, nil
+}
+
+func (s *serverAPI) ListScopes(
+	ctx context.Context,
+	req *ssov1.ListScopesRequest,
+) (*ssov1.ListScopesResponse, error) {
+
+	if err := validations.ValidateAppId(req.GetAppId(), validate); err != nil {
+		return nil, err
+	}
+
+	scopes, err := s.consents.Scopes(ctx, int(req.GetAppId()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp := &ssov1.ListScopesResponse{
+		Scopes: make([]*ssov1.Scope, 0, len(scopes)),
+	}
+	for _, scope := range scopes {
+		resp.Scopes = append(resp.Scopes, &ssov1.Scope{
+			AppId:       int32(scope.AppID),
+			Name:        scope.Name,
+			Description: scope.Description,
+			CreatedAt:   scope.CreatedAt.Unix(),
+		})
+	}
+
+	return resp, nil
+}
+
+func (s *serverAPI) DeleteScope(
+	ctx context.Context,
+	req *ssov1.DeleteScopeRequest,
+) (*ssov1.DeleteScopeResponse, error) {
+
+	if err := validations.ValidateAppId(req.GetAppId(), validate); err != nil {
+		return nil, err
+	}
+
+	if err := validations.ValidateScopeName(req.GetName(), validate); err != nil {
+		return nil, err
+	}
+
+	if err := interceptors.RequireAppAdmin(ctx, int(req.GetAppId()), s.admins); err != nil {
+		return nil, err
+	}
+
+	if err := s.consents.DeleteScope(ctx, int(req.GetAppId()), req.GetName()); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.DeleteScopeResponse{}, nil
+}
+
+func (s *serverAPI) GrantConsent(
+	ctx context.Context,
+	req *ssov1.GrantConsentRequest,
+) (*ssov1.GrantConsentResponse, error) {
+
+	claims, err := interceptors.RequireClaims(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validations.ValidateAppId(req.GetAppId(), validate); err != nil {
+		return nil, err
+	}
+
+	if err := validations.ValidateScopes(req.GetScopes(), validate); err != nil {
+		return nil, err
+	}
+
+	consent, err := s.consents.Grant(ctx, claims, int(req.GetAppId()), req.GetScopes())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.GrantConsentResponse{
+		Consent: toConsent(consent),
+	}, nil
+}
+
+func (s *serverAPI) ListConsents(
+	ctx context.Context,
+	req *ssov1.ListConsentsRequest,
+) (*ssov1.ListConsentsResponse, error) {
+
+	claims, err := interceptors.RequireClaims(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	consents, err := s.consents.Consents(ctx, claims.UserID)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp := &ssov1.ListConsentsResponse{
+		Consents: make([]*ssov1.Consent, 0, len(consents)),
+	}
+	for _, consent := range consents {
+		resp.Consents = append(resp.Consents, toConsent(consent))
+	}
+
+	return resp, nil
+}
+
+func (s *serverAPI) RevokeConsent(
+	ctx context.Context,
+	req *ssov1.RevokeConsentRequest,
+) (*ssov1.RevokeConsentResponse, error) {
+
+	claims, err := interceptors.RequireClaims(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validations.ValidateAppId(req.GetAppId(), validate); err != nil {
+		return nil, err
+	}
+
+	if err := s.consents.Revoke(ctx, claims.UserID, int(req.GetAppId())); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.RevokeConsentResponse{}, nil
+}
+
+func toStatus(err error) error {
+	switch {
+	case errors.Is(err, consents.ErrAppNotFound):
+		return status.Error(codes.NotFound, "app not found")
+	case errors.Is(err, consents.ErrScopeNotFound):
+		return status.Error(codes.NotFound, "scope not found")
+	case errors.Is(err, consents.ErrConsentNotFound):
+		return status.Error(codes.NotFound, "consent not found")
+	case errors.Is(err, consents.ErrInvalidScope):
+		return status.Error(codes.InvalidArgument, "scopes must not be empty")
+	case errors.Is(err, consents.ErrUnknownScope):
+		return status.Error(codes.InvalidArgument, "scope is not defined for the app")
+	case errors.Is(err, consents.ErrConsentForbidden):
+		return status.Error(codes.PermissionDenied, "consent can't be granted with impersonation or api key token")
+	}
+
+	return status.Error(codes.Internal, "internal error")
+}
+
+func toConsent(consent models.Consent) *ssov1.Consent {
+	return &ssov1.Consent{
+		AppId:     int32(consent.AppID),
+		Scopes:    consent.Scopes,
+		GrantedAt: consent.GrantedAt.Unix(),
+		UpdatedAt: consent.UpdatedAt.Unix(),
+	}
+}
@@ -0,0 +1,199 @@
+package devices
+
+import (
+	"SSO/internal/domain/models"
+	authgrpc "SSO/internal/grpc/auth"
+	"SSO/internal/grpc/interceptors"
+	"SSO/internal/lib/jwt"
+	"SSO/internal/lib/validations"
+	"SSO/internal/services/auth"
+	"SSO/internal/services/devices"
+	"context"
+	"errors"
+	ssov1 "github.com/futod4m4/protos/gen/go/sso"
+	"github.com/go-playground/validator/v10"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type serverAPI struct {
+	ssov1.UnimplementedDeviceAuthorizationServer
+	devices Devices
+}
+
+type Devices interface {
+	Authorize(ctx context.Context, appID int, scopes []string) (devices.Authorization, error)
+	Token(ctx context.Context, appID int, deviceCode string) (string, error)
+	Request(ctx context.Context, userCode string) (models.DeviceCode, models.App, error)
+	Decide(ctx context.Context, caller jwt.Claims, userCode string, approve bool) error
+}
+
+var (
+	validate = validator.New(validator.WithRequiredStructEnabled())
+)
+
+func Register(gRPC *grpc.Server, devices Devices) {
+	ssov1.RegisterDeviceAuthorizationServer(gRPC, &serverAPI{devices: devices})
+}
+
+func (s *serverAPI) AuthorizeDevice(
+	ctx context.Context,
+	req *ssov1.AuthorizeDeviceRequest,
+) (*ssov1.AuthorizeDeviceResponse, error) {
+
+	if err := validations.ValidateAppId(req.GetAppId(), validate); err != nil {
+		return nil, err
+	}
+
+	if err := validations.ValidateDeviceScopes(req.GetScopes(), validate); err != nil {
+		return nil, err
+	}
+
+	auth, err := s.devices.Authorize(ctx, int(req.GetAppId()), req.GetScopes())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.AuthorizeDeviceResponse{
+		DeviceCode:              auth.DeviceCode,
+		UserCode:                auth.UserCode,
+		VerificationUri:         auth.VerificationURI,
+		VerificationUriComplete: auth.VerificationURIComplete,
+		ExpiresAt:               auth.ExpiresAt.Unix(),
+		Interval:                int32(auth.Interval.Seconds()),
+	}, nil
+}
+
+func (s *serverAPI) DeviceToken(
+	ctx context.Context,
+	req *ssov1.DeviceTokenRequest,
+) (*ssov1.DeviceTokenResponse, error) {
+
+	if err := validations.ValidateAppId(req.GetAppId(), validate); err != nil {
+		return nil, err
+	}
+
+	if err := validations.ValidateDeviceCode(req.GetDeviceCode(), validate); err != nil {
+		return nil, err
+	}
+
+	token, err := s.devices.Token(ctx, int(req.GetAppId()), req.GetDeviceCode())
+	if err != nil {
+		var secondFactor *auth.SecondFactorRequiredError
+		if errors.As(err, &secondFactor) {
+			return &ssov1.DeviceTokenResponse{
+				SecondFactorChallenge: secondFactor.Challenge,
+				SecondFactors:         authgrpc.ToSecondFactors(secondFactor),
+			}, nil
+		}
+
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.DeviceTokenResponse{
+		Token: token,
+	}, nil
+}
+
+func (s *serverAPI) GetDeviceRequest(
+	ctx context.Context,
+	req *ssov1.GetDeviceRequestRequest,
+) (*ssov1.GetDeviceRequestResponse, error) {
+
+	if _, err := interceptors.RequireClaims(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := validations.ValidateUserCode(req.GetUserCode(), validate); err != nil {
+		return nil, err
+	}
+
+	code, app, err := s.devices.Request(ctx, req.GetUserCode())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.GetDeviceRequestResponse{
+		Request: &ssov1.DeviceRequest{
+			UserCode:  code.UserCode,
+			AppId:     int32(app.ID),
+			AppName:   app.Name,
+			Scopes:    code.Scopes,
+			ExpiresAt: code.ExpiresAt.Unix(),
+		},
+	}, nil
+}
+
+func (s *serverAPI) ApproveDevice(
+	ctx context.Context,
+	req *ssov1.ApproveDeviceRequest,
+) (*ssov1.ApproveDeviceResponse, error) {
+
+	claims, err := interceptors.RequireClaims(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validations.ValidateUserCode(req.GetUserCode(), validate); err != nil {
+		return nil, err
+	}
+
+	if err := s.devices.Decide(ctx, claims, req.GetUserCode(), true); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.ApproveDeviceResponse{}, nil
+}
+
+func (s *serverAPI) DenyDevice(
+	ctx context.Context,
+	req *ssov1.DenyDeviceRequest,
+) (*ssov1.DenyDeviceResponse, error) {
+
+	claims, err := interceptors.RequireClaims(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validations.ValidateUserCode(req.GetUserCode(), validate); err != nil {
+		return nil, err
+	}
+
+	if err := s.devices.Decide(ctx, claims, req.GetUserCode(), false); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.DenyDeviceResponse{}, nil
+}
+
+func toStatus(err error) error {
+	switch {
+	case errors.Is(err, devices.ErrAppNotFound):
+		return status.Error(codes.NotFound, "app not found")
+	case errors.Is(err, devices.ErrAppDisabled):
+		return status.Error(codes.FailedPrecondition, "app is disabled")
+	case errors.Is(err, devices.ErrGrantNotAllowed):
+		return status.Error(codes.PermissionDenied, "device code grant is not allowed for the app")
+	case errors.Is(err, devices.ErrInvalidScope):
+		return status.Error(codes.InvalidArgument, "scope is not defined for the app")
+	case errors.Is(err, devices.ErrInvalidUserCode):
+		return status.Error(codes.NotFound, "invalid or expired user code")
+	case errors.Is(err, devices.ErrInvalidDeviceCode):
+		return status.Error(codes.InvalidArgument, "invalid device code")
+	case errors.Is(err, devices.ErrApproverNotAllowed):
+		return status.Error(codes.PermissionDenied, "device must be approved by the user themselves")
+	case errors.Is(err, devices.ErrAuthorizationPending):
+		return status.Error(codes.FailedPrecondition, "authorization_pending")
+	case errors.Is(err, devices.ErrSlowDown):
+		return status.Error(codes.ResourceExhausted, "slow_down")
+	case errors.Is(err, devices.ErrAccessDenied):
+		return status.Error(codes.PermissionDenied, "access_denied")
+	case errors.Is(err, devices.ErrExpiredToken):
+		return status.Error(codes.FailedPrecondition, "expired_token")
+	case errors.Is(err, auth.ErrSecondFactorTooSoon):
+		return status.Error(codes.ResourceExhausted, "second factor code was sent recently")
+	}
+
+	return status.Error(codes.Internal, "internal error")
+}
@@ -0,0 +1,150 @@
+package federation
+
+import (
+	authgrpc "SSO/internal/grpc/auth"
+	"SSO/internal/lib/validations"
+	"SSO/internal/services/auth"
+	"SSO/internal/services/federation"
+	"context"
+	"errors"
+	ssov1 "github.com/futod4m4/protos/gen/go/sso"
+	"github.com/go-playground/validator/v10"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type serverAPI struct {
+	ssov1.UnimplementedFederationServer
+	federation Federation
+}
+
+type Federation interface {
+	Providers() []string
+	Start(ctx context.Context, provider string, appID int, scopes []string, returnTo string) (string, error)
+	Complete(ctx context.Context, state, code string) (federation.Login, error)
+}
+
+var (
+	validate = validator.New(validator.WithRequiredStructEnabled())
+)
+
+func Register(gRPC *grpc.Server, federation Federation) {
+	ssov1.RegisterFederationServer(gRPC, &serverAPI{federation: federation})
+}
+
+func (s *serverAPI) ListProviders(
+	ctx context.Context,
+	req *ssov1.ListProvidersRequest,
+) (*ssov1.ListProvidersResponse, error) {
+
+	return &ssov1.ListProvidersResponse{
+		Providers: s.federation.Providers(),
+	}, nil
+}
+
+func (s *serverAPI) StartFederatedLogin(
+	ctx context.Context,
+	req *ssov1.StartFederatedLoginRequest,
+) (*ssov1.StartFederatedLoginResponse, error) {
+
+	if err := validations.ValidateStartFederatedLogin(
+		req.GetProvider(), req.GetAppId(), req.GetScopes(), validate,
+	); err != nil {
+		return nil, err
+	}
+
+	authURL, err := s.federation.Start(
+		ctx,
+		req.GetProvider(),
+		int(req.GetAppId()),
+		req.GetScopes(),
+		req.GetReturnTo(),
+	)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.StartFederatedLoginResponse{
+		AuthorizationUrl: authURL,
+	}, nil
+}
+
+func (s *serverAPI) CompleteFederatedLogin(
+	ctx context.Context,
+	req *ssov1.CompleteFederatedLoginRequest,
+) (*ssov1.CompleteFederatedLoginResponse, error) {
+
+	if err := validations.ValidateCompleteFederatedLogin(req.GetState(), req.GetCode(), validate); err != nil {
+		return nil, err
+	}
+
+	login, err := s.federation.Complete(ctx, req.GetState(), req.GetCode())
+	if err != nil {
+		var secondFactor *auth.SecondFactorRequiredError
+		if errors.As(err, &secondFactor) {
+			return &ssov1.CompleteFederatedLoginResponse{
+				ReturnTo:              login.ReturnTo,
+				SecondFactorChallenge: secondFactor.Challenge,
+				SecondFactors:         authgrpc.ToSecondFactors(secondFactor),
+			}, nil
+		}
+
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.CompleteFederatedLoginResponse{
+		Token:    login.Token,
+		ReturnTo: login.ReturnTo,
+		Linked:   login.Linked,
+	}, nil
+}
+
+// toStatus maps errors of the service and of the sign in to the app
+// the user is provisioned for or issued token for.
+func toStatus(err error) error {
+	switch {
+	case errors.Is(err, federation.ErrUnknownProvider):
+		return status.Error(codes.NotFound, "unknown identity provider")
+	case errors.Is(err, federation.ErrAppNotFound), errors.Is(err, auth.ErrInvalidAppID):
+		return status.Error(codes.NotFound, "app not found")
+	case errors.Is(err, federation.ErrAppDisabled), errors.Is(err, auth.ErrAppDisabled):
+		return status.Error(codes.FailedPrecondition, "app is disabled")
+	case errors.Is(err, federation.ErrGrantNotAllowed), errors.Is(err, auth.ErrGrantNotAllowed):
+		return status.Error(codes.PermissionDenied, "federated login is not allowed for the app")
+	case errors.Is(err, federation.ErrInvalidReturnTo):
+		return status.Error(codes.InvalidArgument, "return_to is not a redirect URI of the app")
+	case errors.Is(err, federation.ErrInvalidState):
+		return status.Error(codes.InvalidArgument, "invalid or expired state")
+	case errors.Is(err, federation.ErrUpstream):
+		return status.Error(codes.Unauthenticated, "identity provider rejected the sign in")
+	case errors.Is(err, federation.ErrEmailRequired):
+		return status.Error(codes.FailedPrecondition, "identity provider didn't return email")
+	case errors.Is(err, federation.ErrEmailNotVerified):
+		return status.Error(codes.FailedPrecondition, "email is not verified by identity provider")
+	case errors.Is(err, federation.ErrAccountExists), errors.Is(err, auth.ErrUserExists):
+		return status.Error(codes.AlreadyExists, "account with the email exists and can't be linked")
+	case errors.Is(err, federation.ErrNotProvisioned):
+		return status.Error(codes.PermissionDenied, "no account is linked to the identity")
+	case errors.Is(err, federation.ErrIdentityLinked):
+		return status.Error(codes.AlreadyExists, "identity is linked to another account")
+	case errors.Is(err, auth.ErrRegistrationClosed):
+		return status.Error(codes.PermissionDenied, "registration is closed for the app")
+	case errors.Is(err, auth.ErrInviteOnly):
+		return status.Error(codes.PermissionDenied, "registration for the app is by invitation only")
+	case errors.Is(err, auth.ErrEmailDomainNotAllowed):
+		return status.Error(codes.PermissionDenied, "email domain is not allowed for the app")
+	case errors.Is(err, auth.ErrFieldRequired), errors.Is(err, auth.ErrInvalidDateOfBirth):
+		return status.Error(codes.FailedPrecondition, "app requires profile fields the provider doesn't return")
+	case errors.Is(err, auth.ErrScopeRequired):
+		return status.Error(codes.InvalidArgument, "app must request scopes")
+	case errors.Is(err, auth.ErrUnknownScope):
+		return status.Error(codes.InvalidArgument, "scope is not defined for the app")
+	case errors.Is(err, auth.ErrConsentRequired):
+		return status.Error(codes.FailedPrecondition, "user hasn't consented to the requested scopes")
+	case errors.Is(err, auth.ErrSecondFactorTooSoon):
+		return status.Error(codes.ResourceExhausted, "second factor code was sent recently")
+	}
+
+	return status.Error(codes.Internal, "internal error")
+}
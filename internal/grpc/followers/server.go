@@ -0,0 +1,377 @@
+package followers
+
+import (
+	"SSO/internal/domain/models"
+	"SSO/internal/grpc/interceptors"
+	"SSO/internal/lib/jwt"
+	"SSO/internal/lib/validations"
+	"SSO/internal/services/followers"
+	"context"
+	"errors"
+	ssov1 "github.com/futod4m4/protos/gen/go/sso"
+	"github.com/go-playground/validator/v10"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type serverAPI struct {
+	ssov1.UnimplementedFollowersServer
+	followers Followers
+}
+
+type Followers interface {
+	Follow(ctx context.Context, caller jwt.Claims, userID int64) (string, error)
+	Unfollow(ctx context.Context, caller jwt.Claims, userID int64) error
+	Followers(ctx context.Context, userID, cursor int64, limit int) (models.FollowPage, error)
+	Following(ctx context.Context, userID, cursor int64, limit int) (models.FollowPage, error)
+	Counts(ctx context.Context, userIDs []int64) ([]models.FollowCounts, error)
+	Relationship(ctx context.Context, caller jwt.Claims, userID, otherID int64) (models.Relationship, error)
+	Relationships(ctx context.Context, caller jwt.Claims, userID int64, otherIDs []int64) ([]models.Relationship, error)
+	SetPrivate(ctx context.Context, caller jwt.Claims, private bool) error
+	Approve(ctx context.Context, caller jwt.Claims, followerID int64) error
+	Deny(ctx context.Context, caller jwt.Claims, followerID int64) error
+	IncomingRequests(ctx context.Context, caller jwt.Claims, cursor int64, limit int) (models.FollowPage, error)
+	OutgoingRequests(ctx context.Context, caller jwt.Claims, cursor int64, limit int) (models.FollowPage, error)
+}
+
+var (
+	validate = validator.New(validator.WithRequiredStructEnabled())
+)
+
+func Register(gRPC *grpc.Server, followers Followers) {
+	ssov1.RegisterFollowersServer(gRPC, &serverAPI{followers: followers})
+}
+
+func (s *serverAPI) Follow(
+	ctx context.Context,
+	req *ssov1.FollowRequest,
+) (*ssov1.FollowResponse, error) {
+
+	claims, err := interceptors.RequireClaims(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validations.ValidateUserId(req.GetUserId(), validate); err != nil {
+		return nil, err
+	}
+
+	followStatus, err := s.followers.Follow(ctx, claims, req.GetUserId())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.FollowResponse{
+		Status: followStatus,
+	}, nil
+}
+
+func (s *serverAPI) Unfollow(
+	ctx context.Context,
+	req *ssov1.UnfollowRequest,
+) (*ssov1.UnfollowResponse, error) {
+
+	claims, err := interceptors.RequireClaims(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validations.ValidateUserId(req.GetUserId(), validate); err != nil {
+		return nil, err
+	}
+
+	if err := s.followers.Unfollow(ctx, claims, req.GetUserId()); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.UnfollowResponse{}, nil
+}
+
+func (s *serverAPI) ListFollowers(
+	ctx context.Context,
+	req *ssov1.ListFollowersRequest,
+) (*ssov1.ListFollowersResponse, error) {
+
+	if _, err := interceptors.RequireClaims(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := validations.ValidateFollowPage(req.GetUserId(), req.GetCursor(), req.GetLimit(), validate); err != nil {
+		return nil, err
+	}
+
+	page, err := s.followers.Followers(ctx, req.GetUserId(), req.GetCursor(), int(req.GetLimit()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.ListFollowersResponse{
+		Users:      toFollowedUsers(page.Entries),
+		NextCursor: page.NextCursor,
+	}, nil
+}
+
+func (s *serverAPI) ListFollowing(
+	ctx context.Context,
+	req *ssov1.ListFollowingRequest,
+) (*ssov1.ListFollowingResponse, error) {
+
+	if _, err := interceptors.RequireClaims(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := validations.ValidateFollowPage(req.GetUserId(), req.GetCursor(), req.GetLimit(), validate); err != nil {
+		return nil, err
+	}
+
+	page, err := s.followers.Following(ctx, req.GetUserId(), req.GetCursor(), int(req.GetLimit()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.ListFollowingResponse{
+		Users:      toFollowedUsers(page.Entries),
+		NextCursor: page.NextCursor,
+	}, nil
+}
+
+func (s *serverAPI) GetFollowCounts(
+	ctx context.Context,
+	req *ssov1.GetFollowCountsRequest,
+) (*ssov1.GetFollowCountsResponse, error) {
+
+	if _, err := interceptors.RequireClaims(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := validations.ValidateUserIds(req.GetUserIds(), validate); err != nil {
+		return nil, err
+	}
+
+	counts, err := s.followers.Counts(ctx, req.GetUserIds())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp := &ssov1.GetFollowCountsResponse{
+		Counts: make([]*ssov1.FollowCounts, 0, len(counts)),
+	}
+	for _, c := range counts {
+		resp.Counts = append(resp.Counts, &ssov1.FollowCounts{
+			UserId:    c.UserID,
+			Followers: c.Followers,
+			Following: c.Following,
+			Private:   c.Private,
+		})
+	}
+
+	return resp, nil
+}
+
+func (s *serverAPI) GetRelationship(
+	ctx context.Context,
+	req *ssov1.GetRelationshipRequest,
+) (*ssov1.GetRelationshipResponse, error) {
+
+	claims, err := interceptors.RequireClaims(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validations.ValidateUserIds([]int64{req.GetUserId(), req.GetOtherUserId()}, validate); err != nil {
+		return nil, err
+	}
+
+	relationship, err := s.followers.Relationship(ctx, claims, req.GetUserId(), req.GetOtherUserId())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.GetRelationshipResponse{
+		Relationship: toRelationship(relationship),
+	}, nil
+}
+
+func (s *serverAPI) GetRelationships(
+	ctx context.Context,
+	req *ssov1.GetRelationshipsRequest,
+) (*ssov1.GetRelationshipsResponse, error) {
+
+	claims, err := interceptors.RequireClaims(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validations.ValidateUserId(req.GetUserId(), validate); err != nil {
+		return nil, err
+	}
+
+	if err := validations.ValidateUserIds(req.GetOtherUserIds(), validate); err != nil {
+		return nil, err
+	}
+
+	relationships, err := s.followers.Relationships(ctx, claims, req.GetUserId(), req.GetOtherUserIds())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp := &ssov1.GetRelationshipsResponse{
+		Relationships: make([]*ssov1.Relationship, 0, len(relationships)),
+	}
+	for _, r := range relationships {
+		resp.Relationships = append(resp.Relationships, toRelationship(r))
+	}
+
+	return resp, nil
+}
+
+func (s *serverAPI) SetAccountPrivacy(
+	ctx context.Context,
+	req *ssov1.SetAccountPrivacyRequest,
+) (*ssov1.SetAccountPrivacyResponse, error) {
+
+	claims, err := interceptors.RequireClaims(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.followers.SetPrivate(ctx, claims, req.GetPrivate()); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.SetAccountPrivacyResponse{}, nil
+}
+
+func (s *serverAPI) ApproveFollowRequest(
+	ctx context.Context,
+	req *ssov1.ApproveFollowRequestRequest,
+) (*ssov1.ApproveFollowRequestResponse, error) {
+
+	claims, err := interceptors.RequireClaims(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validations.ValidateUserId(req.GetUserId(), validate); err != nil {
+		return nil, err
+	}
+
+	if err := s.followers.Approve(ctx, claims, req.GetUserId()); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.ApproveFollowRequestResponse{}, nil
+}
+
+func (s *serverAPI) DenyFollowRequest(
+	ctx context.Context,
+	req *ssov1.DenyFollowRequestRequest,
+) (*ssov1.DenyFollowRequestResponse, error) {
+
+	claims, err := interceptors.RequireClaims(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validations.ValidateUserId(req.GetUserId(), validate); err != nil {
+		return nil, err
+	}
+
+	if err := s.followers.Deny(ctx, claims, req.GetUserId()); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.DenyFollowRequestResponse{}, nil
+}
+
+func (s *serverAPI) ListIncomingFollowRequests(
+	ctx context.Context,
+	req *ssov1.ListIncomingFollowRequestsRequest,
+) (*ssov1.ListIncomingFollowRequestsResponse, error) {
+
+	claims, err := interceptors.RequireClaims(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validations.ValidatePage(req.GetCursor(), req.GetLimit(), validate); err != nil {
+		return nil, err
+	}
+
+	page, err := s.followers.IncomingRequests(ctx, claims, req.GetCursor(), int(req.GetLimit()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.ListIncomingFollowRequestsResponse{
+		Users:      toFollowedUsers(page.Entries),
+		NextCursor: page.NextCursor,
+	}, nil
+}
+
+func (s *serverAPI) ListOutgoingFollowRequests(
+	ctx context.Context,
+	req *ssov1.ListOutgoingFollowRequestsRequest,
+) (*ssov1.ListOutgoingFollowRequestsResponse, error) {
+
+	claims, err := interceptors.RequireClaims(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validations.ValidatePage(req.GetCursor(), req.GetLimit(), validate); err != nil {
+		return nil, err
+	}
+
+	page, err := s.followers.OutgoingRequests(ctx, claims, req.GetCursor(), int(req.GetLimit()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.ListOutgoingFollowRequestsResponse{
+		Users:      toFollowedUsers(page.Entries),
+		NextCursor: page.NextCursor,
+	}, nil
+}
+
+func toStatus(err error) error {
+	switch {
+	case errors.Is(err, followers.ErrUserNotFound):
+		return status.Error(codes.NotFound, "user not found")
+	case errors.Is(err, followers.ErrSelfFollow):
+		return status.Error(codes.InvalidArgument, "users can't follow themselves")
+	case errors.Is(err, followers.ErrCallerNotAllowed):
+		return status.Error(codes.PermissionDenied, "service accounts can't follow or be followed")
+	case errors.Is(err, followers.ErrTooManyUsers):
+		return status.Error(codes.InvalidArgument, "too many users requested at once")
+	case errors.Is(err, followers.ErrRequestNotFound):
+		return status.Error(codes.NotFound, "follow request not found")
+	}
+
+	return status.Error(codes.Internal, "internal error")
+}
+
+func toFollowedUsers(entries []models.FollowEntry) []*ssov1.FollowedUser {
+	users := make([]*ssov1.FollowedUser, 0, len(entries))
+	for _, e := range entries {
+		users = append(users, &ssov1.FollowedUser{
+			UserId:   e.UserID,
+			Username: e.Username,
+		})
+	}
+
+	return users
+}
+
+func toRelationship(r models.Relationship) *ssov1.Relationship {
+	return &ssov1.Relationship{
+		UserId:      r.UserID,
+		OtherUserId: r.OtherUserID,
+		Following:   r.Following,
+		FollowedBy:  r.FollowedBy,
+		Mutual:      r.Mutual(),
+		Requested:   r.Requested,
+		RequestedBy: r.RequestedBy,
+	}
+}
@@ -0,0 +1,359 @@
+package groups
+
+import (
+	"SSO/internal/domain/models"
+	"SSO/internal/grpc/interceptors"
+	"SSO/internal/lib/validations"
+	"SSO/internal/services/groups"
+	"context"
+	"errors"
+	ssov1 "github.com/futod4m4/protos/gen/go/sso"
+	"github.com/go-playground/validator/v10"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type serverAPI struct {
+	ssov1.UnimplementedGroupsServer
+	groups Groups
+	admins interceptors.AppAdminChecker
+}
+
+type Groups interface {
+	CreateGroup(ctx context.Context, appID int, name string) (groupID int64, err error)
+	DeleteGroup(ctx context.Context, appID int, groupID int64) error
+	Groups(ctx context.Context, appID int) ([]models.Group, error)
+	AddMember(ctx context.Context, appID int, groupID, userID int64) error
+	RemoveMember(ctx context.Context, appID int, groupID, userID int64) error
+	Members(ctx context.Context, appID int, groupID int64) ([]models.GroupMember, error)
+	AddSubgroup(ctx context.Context, appID int, parentID, childID int64) error
+	RemoveSubgroup(ctx context.Context, appID int, parentID, childID int64) error
+	AssignRole(ctx context.Context, appID int, groupID, roleID int64) error
+	UnassignRole(ctx context.Context, appID int, groupID, roleID int64) error
+	UserGroups(ctx context.Context, appID int, userID int64) ([]models.Group, error)
+	SetGroupClaims(ctx context.Context, appID int, enabled bool) error
+}
+
+var (
+	validate = validator.New(validator.WithRequiredStructEnabled())
+)
+
+func Register(gRPC *grpc.Server, groups Groups, admins interceptors.AppAdminChecker) {
+	ssov1.RegisterGroupsServer(gRPC, &serverAPI{groups: groups, admins: admins})
+}
+
+func (s *serverAPI) CreateGroup(
+	ctx context.Context,
+	req *ssov1.CreateGroupRequest,
+) (*ssov1.CreateGroupResponse, error) {
+
+	if err := validations.ValidateAccessName(req.GetName(), validate); err != nil {
+		return nil, err
+	}
+
+	if err := s.requireAdmin(ctx, req.GetAppId()); err != nil {
+		return nil, err
+	}
+
+	groupID, err := s.groups.CreateGroup(ctx, int(req.GetAppId()), req.GetName())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.CreateGroupResponse{
+		GroupId: groupID,
+	}, nil
+}
+
+func (s *serverAPI) DeleteGroup(
+	ctx context.Context,
+	req *ssov1.DeleteGroupRequest,
+) (*ssov1.DeleteGroupResponse, error) {
+
+	if err := validations.ValidateGroupId(req.GetGroupId(), validate); err != nil {
+		return nil, err
+	}
+
+	if err := s.requireAdmin(ctx, req.GetAppId()); err != nil {
+		return nil, err
+	}
+
+	if err := s.groups.DeleteGroup(ctx, int(req.GetAppId()), req.GetGroupId()); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.DeleteGroupResponse{}, nil
+}
+
+func (s *serverAPI) ListGroups(
+	ctx context.Context,
+	req *ssov1.ListGroupsRequest,
+) (*ssov1.ListGroupsResponse, error) {
+
+	if err := s.requireAdmin(ctx, req.GetAppId()); err != nil {
+		return nil, err
+	}
+
+	groups, err := s.groups.Groups(ctx, int(req.GetAppId()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.ListGroupsResponse{
+		Groups: toGroups(groups),
+	}, nil
+}
+
+func (s *serverAPI) AddGroupMember(
+	ctx context.Context,
+	req *ssov1.AddGroupMemberRequest,
+) (*ssov1.AddGroupMemberResponse, error) {
+
+	if err := validateMemberRequest(req.GetGroupId(), req.GetUserId()); err != nil {
+		return nil, err
+	}
+
+	if err := s.requireAdmin(ctx, req.GetAppId()); err != nil {
+		return nil, err
+	}
+
+	if err := s.groups.AddMember(ctx, int(req.GetAppId()), req.GetGroupId(), req.GetUserId()); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.AddGroupMemberResponse{}, nil
+}
+
+func (s *serverAPI) RemoveGroupMember(
+	ctx context.Context,
+	req *ssov1.RemoveGroupMemberRequest,
+) (*ssov1.RemoveGroupMemberResponse, error) {
+
+	if err := validateMemberRequest(req.GetGroupId(), req.GetUserId()); err != nil {
+		return nil, err
+	}
+
+	if err := s.requireAdmin(ctx, req.GetAppId()); err != nil {
+		return nil, err
+	}
+
+	if err := s.groups.RemoveMember(ctx, int(req.GetAppId()), req.GetGroupId(), req.GetUserId()); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.RemoveGroupMemberResponse{}, nil
+}
+
+func (s *serverAPI) ListGroupMembers(
+	ctx context.Context,
+	req *ssov1.ListGroupMembersRequest,
+) (*ssov1.ListGroupMembersResponse, error) {
+
+	if err := validations.ValidateGroupId(req.GetGroupId(), validate); err != nil {
+		return nil, err
+	}
+
+	if err := s.requireAdmin(ctx, req.GetAppId()); err != nil {
+		return nil, err
+	}
+
+	members, err := s.groups.Members(ctx, int(req.GetAppId()), req.GetGroupId())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp := &ssov1.ListGroupMembersResponse{
+		Members: make([]*ssov1.GroupMember, 0, len(members)),
+	}
+	for _, m := range members {
+		resp.Members = append(resp.Members, &ssov1.GroupMember{
+			UserId: m.UserID,
+			Email:  m.Email,
+			Direct: m.Direct,
+		})
+	}
+
+	return resp, nil
+}
+
+func (s *serverAPI) AddSubgroup(
+	ctx context.Context,
+	req *ssov1.AddSubgroupRequest,
+) (*ssov1.AddSubgroupResponse, error) {
+
+	if err := validations.ValidateSubgroup(req.GetParentId(), req.GetChildId(), validate); err != nil {
+		return nil, err
+	}
+
+	if err := s.requireAdmin(ctx, req.GetAppId()); err != nil {
+		return nil, err
+	}
+
+	if err := s.groups.AddSubgroup(ctx, int(req.GetAppId()), req.GetParentId(), req.GetChildId()); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.AddSubgroupResponse{}, nil
+}
+
+func (s *serverAPI) RemoveSubgroup(
+	ctx context.Context,
+	req *ssov1.RemoveSubgroupRequest,
+) (*ssov1.RemoveSubgroupResponse, error) {
+
+	if err := validations.ValidateSubgroup(req.GetParentId(), req.GetChildId(), validate); err != nil {
+		return nil, err
+	}
+
+	if err := s.requireAdmin(ctx, req.GetAppId()); err != nil {
+		return nil, err
+	}
+
+	if err := s.groups.RemoveSubgroup(ctx, int(req.GetAppId()), req.GetParentId(), req.GetChildId()); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.RemoveSubgroupResponse{}, nil
+}
+
+func (s *serverAPI) AssignGroupRole(
+	ctx context.Context,
+	req *ssov1.AssignGroupRoleRequest,
+) (*ssov1.AssignGroupRoleResponse, error) {
+
+	if err := validateRoleRequest(req.GetGroupId(), req.GetRoleId()); err != nil {
+		return nil, err
+	}
+
+	if err := s.requireAdmin(ctx, req.GetAppId()); err != nil {
+		return nil, err
+	}
+
+	if err := s.groups.AssignRole(ctx, int(req.GetAppId()), req.GetGroupId(), req.GetRoleId()); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.AssignGroupRoleResponse{}, nil
+}
+
+func (s *serverAPI) UnassignGroupRole(
+	ctx context.Context,
+	req *ssov1.UnassignGroupRoleRequest,
+) (*ssov1.UnassignGroupRoleResponse, error) {
+
+	if err := validateRoleRequest(req.GetGroupId(), req.GetRoleId()); err != nil {
+		return nil, err
+	}
+
+	if err := s.requireAdmin(ctx, req.GetAppId()); err != nil {
+		return nil, err
+	}
+
+	if err := s.groups.UnassignRole(ctx, int(req.GetAppId()), req.GetGroupId(), req.GetRoleId()); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.UnassignGroupRoleResponse{}, nil
+}
+
+func (s *serverAPI) ListUserGroups(
+	ctx context.Context,
+	req *ssov1.ListUserGroupsRequest,
+) (*ssov1.ListUserGroupsResponse, error) {
+
+	if err := validations.ValidateUserId(req.GetUserId(), validate); err != nil {
+		return nil, err
+	}
+
+	if err := s.requireAdmin(ctx, req.GetAppId()); err != nil {
+		return nil, err
+	}
+
+	groups, err := s.groups.UserGroups(ctx, int(req.GetAppId()), req.GetUserId())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.ListUserGroupsResponse{
+		Groups: toGroups(groups),
+	}, nil
+}
+
+func (s *serverAPI) SetGroupClaims(
+	ctx context.Context,
+	req *ssov1.SetGroupClaimsRequest,
+) (*ssov1.SetGroupClaimsResponse, error) {
+
+	if err := s.requireAdmin(ctx, req.GetAppId()); err != nil {
+		return nil, err
+	}
+
+	if err := s.groups.SetGroupClaims(ctx, int(req.GetAppId()), req.GetEnabled()); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.SetGroupClaimsResponse{}, nil
+}
+
+// requireAdmin checks that caller is an admin of the app.
+func (s *serverAPI) requireAdmin(ctx context.Context, appID int32) error {
+	if err := validations.ValidateAppId(appID, validate); err != nil {
+		return err
+	}
+
+	return interceptors.RequireAppAdmin(ctx, int(appID), s.admins)
+}
+
+func validateMemberRequest(groupID, userID int64) error {
+	if err := validations.ValidateGroupId(groupID, validate); err != nil {
+		return err
+	}
+
+	return validations.ValidateUserId(userID, validate)
+}
+
+func validateRoleRequest(groupID, roleID int64) error {
+	if err := validations.ValidateGroupId(groupID, validate); err != nil {
+		return err
+	}
+
+	return validations.ValidateRoleId(roleID, validate)
+}
+
+func toStatus(err error) error {
+	switch {
+	case errors.Is(err, groups.ErrAppNotFound):
+		return status.Error(codes.NotFound, "app not found")
+	case errors.Is(err, groups.ErrUserNotFound):
+		return status.Error(codes.NotFound, "user not found")
+	case errors.Is(err, groups.ErrRoleNotFound):
+		return status.Error(codes.NotFound, "role not found")
+	case errors.Is(err, groups.ErrGroupNotFound):
+		return status.Error(codes.NotFound, "group not found")
+	case errors.Is(err, groups.ErrMemberNotFound):
+		return status.Error(codes.NotFound, "user is not a member of the group")
+	case errors.Is(err, groups.ErrGroupExists):
+		return status.Error(codes.AlreadyExists, "group already exists")
+	case errors.Is(err, groups.ErrGroupCycle):
+		return status.Error(codes.FailedPrecondition, "nesting would create a cycle of groups")
+	}
+
+	return status.Error(codes.Internal, "internal error")
+}
+
+func toGroups(groups []models.Group) []*ssov1.Group {
+	res := make([]*ssov1.Group, 0, len(groups))
+	for _, g := range groups {
+		res = append(res, &ssov1.Group{
+			Id:        g.ID,
+			AppId:     int32(g.AppID),
+			Name:      g.Name,
+			Roles:     g.Roles,
+			Subgroups: g.Subgroups,
+			CreatedAt: g.CreatedAt.Unix(),
+		})
+	}
+
+	return res
+}
@@ -0,0 +1,154 @@
+package identities
+
+import (
+	"SSO/internal/domain/models"
+	"SSO/internal/grpc/interceptors"
+	"SSO/internal/lib/jwt"
+	"SSO/internal/lib/validations"
+	"SSO/internal/services/federation"
+	"SSO/internal/services/identities"
+	"context"
+	"errors"
+	ssov1 "github.com/futod4m4/protos/gen/go/sso"
+	"github.com/go-playground/validator/v10"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"time"
+)
+
+type serverAPI struct {
+	ssov1.UnimplementedIdentitiesServer
+	identities Identities
+}
+
+type Identities interface {
+	List(ctx context.Context, caller jwt.Claims) (identities.LoginMethods, error)
+	Link(ctx context.Context, caller jwt.Claims, password, provider, returnTo string) (string, error)
+	Unlink(ctx context.Context, caller jwt.Claims, password string, identityID int64) error
+}
+
+var (
+	validate = validator.New(validator.WithRequiredStructEnabled())
+)
+
+func Register(gRPC *grpc.Server, identities Identities) {
+	ssov1.RegisterIdentitiesServer(gRPC, &serverAPI{identities: identities})
+}
+
+func (s *serverAPI) ListIdentities(
+	ctx context.Context,
+	req *ssov1.ListIdentitiesRequest,
+) (*ssov1.ListIdentitiesResponse, error) {
+
+	claims, err := interceptors.RequireClaims(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	methods, err := s.identities.List(ctx, claims)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp := &ssov1.ListIdentitiesResponse{
+		HasPassword: methods.HasPassword,
+		Identities:  make([]*ssov1.Identity, 0, len(methods.Identities)),
+	}
+	for _, identity := range methods.Identities {
+		resp.Identities = append(resp.Identities, toIdentity(identity))
+	}
+
+	return resp, nil
+}
+
+func (s *serverAPI) LinkIdentity(
+	ctx context.Context,
+	req *ssov1.LinkIdentityRequest,
+) (*ssov1.LinkIdentityResponse, error) {
+
+	claims, err := interceptors.RequireClaims(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validations.ValidateLinkIdentity(req.GetProvider(), validate); err != nil {
+		return nil, err
+	}
+
+	authURL, err := s.identities.Link(ctx, claims, req.GetPassword(), req.GetProvider(), req.GetReturnTo())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.LinkIdentityResponse{
+		AuthorizationUrl: authURL,
+	}, nil
+}
+
+func (s *serverAPI) UnlinkIdentity(
+	ctx context.Context,
+	req *ssov1.UnlinkIdentityRequest,
+) (*ssov1.UnlinkIdentityResponse, error) {
+
+	claims, err := interceptors.RequireClaims(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validations.ValidateIdentityId(req.GetIdentityId(), validate); err != nil {
+		return nil, err
+	}
+
+	if err := s.identities.Unlink(ctx, claims, req.GetPassword(), req.GetIdentityId()); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.UnlinkIdentityResponse{}, nil
+}
+
+func toStatus(err error) error {
+	switch {
+	case errors.Is(err, identities.ErrUserNotFound):
+		return status.Error(codes.NotFound, "user not found")
+	case errors.Is(err, identities.ErrIdentityNotFound):
+		return status.Error(codes.NotFound, "identity not found")
+	case errors.Is(err, identities.ErrCallerNotAllowed):
+		return status.Error(codes.PermissionDenied, "login methods must be managed by the user themselves")
+	case errors.Is(err, identities.ErrReauthRequired):
+		return status.Error(codes.Unauthenticated, "re-authentication required")
+	case errors.Is(err, identities.ErrInvalidPassword):
+		return status.Error(codes.Unauthenticated, "password is incorrect")
+	case errors.Is(err, identities.ErrLastLoginMethod):
+		return status.Error(codes.FailedPrecondition, "can't unlink the last login method")
+	case errors.Is(err, federation.ErrUnknownProvider):
+		return status.Error(codes.NotFound, "unknown identity provider")
+	case errors.Is(err, federation.ErrAppNotFound):
+		return status.Error(codes.NotFound, "app not found")
+	case errors.Is(err, federation.ErrAppDisabled):
+		return status.Error(codes.FailedPrecondition, "app is disabled")
+	case errors.Is(err, federation.ErrInvalidReturnTo):
+		return status.Error(codes.InvalidArgument, "return_to is not a redirect URI of the app")
+	}
+
+	return status.Error(codes.Internal, "internal error")
+}
+
+func toIdentity(identity models.Identity) *ssov1.Identity {
+	return &ssov1.Identity{
+		Id:          identity.ID,
+		Provider:    identity.Provider,
+		Subject:     identity.Subject,
+		Email:       identity.Email,
+		CreatedAt:   identity.CreatedAt.Unix(),
+		LastLoginAt: unixOrZero(identity.LastLoginAt),
+	}
+}
+
+func unixOrZero(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+
+	return t.Unix()
+}
@@ -0,0 +1,100 @@
+package impersonation
+
+import (
+	"SSO/internal/grpc/interceptors"
+	"SSO/internal/lib/jwt"
+	"SSO/internal/lib/validations"
+	"SSO/internal/services/auth"
+	"SSO/internal/services/impersonation"
+	"context"
+	"errors"
+	ssov1 "github.com/futod4m4/protos/gen/go/sso"
+	"github.com/go-playground/validator/v10"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type serverAPI struct {
+	ssov1.UnimplementedImpersonationServer
+	impersonation Impersonation
+	admins        interceptors.AppAdminChecker
+}
+
+type Impersonation interface {
+	Start(
+		ctx context.Context,
+		admin jwt.Claims,
+		userID int64,
+		scopes []string,
+		reason string,
+	) (impersonation.Session, error)
+}
+
+var (
+	validate = validator.New(validator.WithRequiredStructEnabled())
+)
+
+func Register(gRPC *grpc.Server, impersonation Impersonation, admins interceptors.AppAdminChecker) {
+	ssov1.RegisterImpersonationServer(gRPC, &serverAPI{impersonation: impersonation, admins: admins})
+}
+
+func (s *serverAPI) Impersonate(
+	ctx context.Context,
+	req *ssov1.ImpersonateRequest,
+) (*ssov1.ImpersonateResponse, error) {
+
+	if err := validations.ValidateUserId(req.GetUserId(), validate); err != nil {
+		return nil, err
+	}
+
+	if err := validations.ValidateAppId(req.GetAppId(), validate); err != nil {
+		return nil, err
+	}
+
+	if err := validations.ValidateImpersonationScopes(req.GetScopes(), validate); err != nil {
+		return nil, err
+	}
+
+	if err := validations.ValidateImpersonationReason(req.GetReason(), validate); err != nil {
+		return nil, err
+	}
+
+	if err := interceptors.RequireAppAdmin(ctx, int(req.GetAppId()), s.admins); err != nil {
+		return nil, err
+	}
+
+	claims, _ := interceptors.ClaimsFromContext(ctx)
+
+	session, err := s.impersonation.Start(ctx, claims, req.GetUserId(), req.GetScopes(), req.GetReason())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.ImpersonateResponse{
+		Token:     session.Token,
+		SessionId: session.ID,
+		ExpiresAt: session.ExpiresAt.Unix(),
+	}, nil
+}
+
+func toStatus(err error) error {
+	switch {
+	case errors.Is(err, impersonation.ErrActorNotAllowed):
+		return status.Error(codes.PermissionDenied, "impersonation must be started with a login token")
+	case errors.Is(err, impersonation.ErrSelf):
+		return status.Error(codes.InvalidArgument, "admin can't impersonate themselves")
+	case errors.Is(err, impersonation.ErrInvalidScope):
+		return status.Error(codes.InvalidArgument, "scopes must not be empty or include the admin role")
+	case errors.Is(err, auth.ErrUserNotFound):
+		return status.Error(codes.NotFound, "user not found")
+	case errors.Is(err, auth.ErrNotImpersonable):
+		return status.Error(codes.FailedPrecondition, "service accounts can't be impersonated")
+	case errors.Is(err, auth.ErrInvalidAppID):
+		return status.Error(codes.NotFound, "app not found")
+	case errors.Is(err, auth.ErrAppDisabled):
+		return status.Error(codes.FailedPrecondition, "app is disabled")
+	}
+
+	return status.Error(codes.Internal, "internal error")
+}
@@ -0,0 +1,179 @@
+package interceptors
+
+import (
+	"SSO/internal/lib/jwt"
+	"SSO/internal/services/apikeys"
+	"SSO/internal/services/permissions"
+	"context"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const authorizationHeader = "authorization"
+
+type TokenVerifier interface {
+	VerifyToken(ctx context.Context, token string) (jwt.Claims, error)
+}
+
+type APIKeyVerifier interface {
+	VerifyAPIKey(ctx context.Context, key string) (jwt.Claims, error)
+}
+
+// ImpersonationRecorder records requests made with impersonation tokens.
+type ImpersonationRecorder interface {
+	RecordUse(ctx context.Context, claims jwt.Claims, method string) error
+}
+
+type AppAdminChecker interface {
+	IsAppAdmin(ctx context.Context, appID int, userID int64) (bool, error)
+}
+
+type claimsKey struct{}
+
+// Auth verifies bearer token from the authorization metadata, if any,
+// and puts its claims into the request context. API keys are accepted
+// as bearer tokens too.
+//
+// Requests without token are passed through, handlers that require
+// authentication check for claims with ClaimsFromContext.
+func Auth(verifier TokenVerifier, keys APIKeyVerifier) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req any,
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (any, error) {
+		token, ok := bearerToken(ctx)
+		if !ok {
+			return handler(ctx, req)
+		}
+
+		var (
+			claims jwt.Claims
+			err    error
+		)
+		if apikeys.IsAPIKey(token) {
+			claims, err = keys.VerifyAPIKey(ctx, token)
+		} else {
+			claims, err = verifier.VerifyToken(ctx, token)
+		}
+		if err != nil {
+			return nil, status.Error(codes.Unauthenticated, "invalid token")
+		}
+
+		return handler(context.WithValue(ctx, claimsKey{}, claims), req)
+	}
+}
+
+// AuditImpersonation records every request authenticated with an
+// impersonation token to the audit trail and refuses the request if it
+// can't be recorded. It must be chained after Auth.
+func AuditImpersonation(recorder ImpersonationRecorder) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req any,
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (any, error) {
+		claims, ok := ClaimsFromContext(ctx)
+		if !ok || !claims.Impersonated() {
+			return handler(ctx, req)
+		}
+
+		if err := recorder.RecordUse(ctx, claims, info.FullMethod); err != nil {
+			return nil, status.Error(codes.Unavailable, "failed to audit impersonated request")
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// ClaimsFromContext returns claims of the token the request was authenticated with.
+func ClaimsFromContext(ctx context.Context) (jwt.Claims, bool) {
+	claims, ok := ctx.Value(claimsKey{}).(jwt.Claims)
+
+	return claims, ok
+}
+
+// RequireClaims returns claims of the request token or Unauthenticated
+// status if request has no token.
+func RequireClaims(ctx context.Context) (jwt.Claims, error) {
+	claims, ok := ClaimsFromContext(ctx)
+	if !ok {
+		return jwt.Claims{}, status.Error(codes.Unauthenticated, "authentication required")
+	}
+
+	return claims, nil
+}
+
+// RequireApp returns claims of the request token or an error status if
+// request has no token or the token is issued for another app.
+func RequireApp(ctx context.Context, appID int) (jwt.Claims, error) {
+	claims, err := RequireClaims(ctx)
+	if err != nil {
+		return jwt.Claims{}, err
+	}
+
+	if claims.AppID != appID {
+		return jwt.Claims{}, status.Error(codes.PermissionDenied, "token is issued for another app")
+	}
+
+	return claims, nil
+}
+
+// RequireAppAdmin checks that request is authenticated with a token issued
+// for the app to a user holding the admin role in it. Scoped tokens must
+// have the admin role among their scopes, impersonation tokens are never
+// allowed admin access.
+func RequireAppAdmin(ctx context.Context, appID int, checker AppAdminChecker) error {
+	claims, err := RequireClaims(ctx)
+	if err != nil {
+		return err
+	}
+
+	if claims.Impersonated() {
+		return status.Error(codes.PermissionDenied, "impersonation tokens don't allow admin access")
+	}
+
+	if claims.AppID != appID {
+		return status.Error(codes.PermissionDenied, "token is issued for another app")
+	}
+
+	if !claims.AllowsScope(permissions.AdminRole) {
+		return status.Error(codes.PermissionDenied, "token scopes don't allow admin access")
+	}
+
+	isAdmin, err := checker.IsAppAdmin(ctx, appID, claims.UserID)
+	if err != nil {
+		return status.Error(codes.Internal, "internal error")
+	}
+
+	if !isAdmin {
+		return status.Error(codes.PermissionDenied, "admin role required")
+	}
+
+	return nil
+}
+
+func bearerToken(ctx context.Context) (string, bool) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return "", false
+	}
+
+	values := md.Get(authorizationHeader)
+	if len(values) == 0 {
+		return "", false
+	}
+
+	token, ok := strings.CutPrefix(values[0], "Bearer ")
+	if !ok || token == "" {
+		return "", false
+	}
+
+	return token, true
+}
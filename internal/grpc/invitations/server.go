@@ -0,0 +1,183 @@
+package invitations
+
+import (
+	"SSO/internal/domain/models"
+	"SSO/internal/grpc/interceptors"
+	"SSO/internal/lib/validations"
+	"SSO/internal/services/auth"
+	"SSO/internal/services/invitations"
+	"SSO/internal/services/organizations"
+	"context"
+	"errors"
+	ssov1 "github.com/futod4m4/protos/gen/go/sso"
+	"github.com/go-playground/validator/v10"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type serverAPI struct {
+	ssov1.UnimplementedInvitationsServer
+	invitations Invitations
+}
+
+type Invitations interface {
+	Invite(ctx context.Context, actorID, orgID int64, email, role string) (invitationID int64, err error)
+	PendingInvitations(ctx context.Context, actorID, orgID int64) ([]models.Invitation, error)
+	Revoke(ctx context.Context, actorID, orgID, invitationID int64) error
+	Accept(
+		ctx context.Context,
+		token string,
+		userID int64,
+		reg invitations.Registration,
+	) (orgID int64, uid int64, err error)
+}
+
+var (
+	validate = validator.New(validator.WithRequiredStructEnabled())
+)
+
+func Register(gRPC *grpc.Server, invitations Invitations) {
+	ssov1.RegisterInvitationsServer(gRPC, &serverAPI{invitations: invitations})
+}
+
+func (s *serverAPI) CreateInvitation(
+	ctx context.Context,
+	req *ssov1.CreateInvitationRequest,
+) (*ssov1.CreateInvitationResponse, error) {
+
+	claims, err := interceptors.RequireClaims(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validations.ValidateCreateInvitation(req, validate); err != nil {
+		return nil, err
+	}
+
+	id, err := s.invitations.Invite(ctx, claims.UserID, req.GetOrganizationId(), req.GetEmail(), req.GetRole())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.CreateInvitationResponse{
+		InvitationId: id,
+	}, nil
+}
+
+func (s *serverAPI) ListInvitations(
+	ctx context.Context,
+	req *ssov1.ListInvitationsRequest,
+) (*ssov1.ListInvitationsResponse, error) {
+
+	claims, err := interceptors.RequireClaims(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validations.ValidateOrganizationId(req.GetOrganizationId(), validate); err != nil {
+		return nil, err
+	}
+
+	list, err := s.invitations.PendingInvitations(ctx, claims.UserID, req.GetOrganizationId())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp := &ssov1.ListInvitationsResponse{
+		Invitations: make([]*ssov1.Invitation, 0, len(list)),
+	}
+	for _, inv := range list {
+		resp.Invitations = append(resp.Invitations, &ssov1.Invitation{
+			Id:             inv.ID,
+			OrganizationId: inv.OrganizationID,
+			Email:          inv.Email,
+			Role:           inv.Role,
+			InvitedBy:      inv.InvitedBy,
+			ExpiresAt:      inv.ExpiresAt.Unix(),
+			CreatedAt:      inv.CreatedAt.Unix(),
+		})
+	}
+
+	return resp, nil
+}
+
+func (s *serverAPI) RevokeInvitation(
+	ctx context.Context,
+	req *ssov1.RevokeInvitationRequest,
+) (*ssov1.RevokeInvitationResponse, error) {
+
+	claims, err := interceptors.RequireClaims(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validations.ValidateOrganizationId(req.GetOrganizationId(), validate); err != nil {
+		return nil, err
+	}
+
+	if err := validate.Var(req.GetInvitationId(), "required"); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invitation_id is required")
+	}
+
+	err = s.invitations.Revoke(ctx, claims.UserID, req.GetOrganizationId(), req.GetInvitationId())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.RevokeInvitationResponse{}, nil
+}
+
+func (s *serverAPI) AcceptInvitation(
+	ctx context.Context,
+	req *ssov1.AcceptInvitationRequest,
+) (*ssov1.AcceptInvitationResponse, error) {
+
+	var userID int64
+	if claims, ok := interceptors.ClaimsFromContext(ctx); ok {
+		userID = claims.UserID
+	}
+
+	if err := validations.ValidateAcceptInvitation(req, userID == 0, validate); err != nil {
+		return nil, err
+	}
+
+	orgID, uid, err := s.invitations.Accept(ctx, req.GetToken(), userID, invitations.Registration{
+		Password:    req.GetPassword(),
+		Username:    req.GetUsername(),
+		Sex:         req.GetSex(),
+		Location:    req.GetLocation(),
+		DateOfBirth: req.GetDateOfBirth(),
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.AcceptInvitationResponse{
+		OrganizationId: orgID,
+		UserId:         uid,
+	}, nil
+}
+
+func toStatus(err error) error {
+	switch {
+	case errors.Is(err, invitations.ErrInviteNotFound):
+		return status.Error(codes.NotFound, "invitation not found or expired")
+	case errors.Is(err, invitations.ErrEmailMismatch):
+		return status.Error(codes.PermissionDenied, "invitation was sent to another email")
+	case errors.Is(err, invitations.ErrLoginRequired):
+		return status.Error(codes.FailedPrecondition, "account with invited email exists, log in to accept invitation")
+	case errors.Is(err, invitations.ErrAlreadyMember):
+		return status.Error(codes.AlreadyExists, "user is already a member")
+	case errors.Is(err, auth.ErrUserExists):
+		return status.Error(codes.AlreadyExists, "user already exists")
+	case errors.Is(err, organizations.ErrForbidden):
+		return status.Error(codes.PermissionDenied, "not enough rights in organization")
+	case errors.Is(err, organizations.ErrInvalidRole):
+		return status.Error(codes.InvalidArgument, "invalid role")
+	case errors.Is(err, organizations.ErrOrgNotFound):
+		return status.Error(codes.NotFound, "organization not found")
+	}
+
+	return status.Error(codes.Internal, "internal error")
+}
@@ -0,0 +1,100 @@
+package magiclinks
+
+import (
+	authgrpc "SSO/internal/grpc/auth"
+	"SSO/internal/lib/validations"
+	"SSO/internal/services/auth"
+	"SSO/internal/services/magiclinks"
+	"context"
+	"errors"
+	ssov1 "github.com/futod4m4/protos/gen/go/sso"
+	"github.com/go-playground/validator/v10"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type serverAPI struct {
+	ssov1.UnimplementedMagicLinksServer
+	magicLinks MagicLinks
+}
+
+type MagicLinks interface {
+	Request(ctx context.Context, email string, appID int, scopes []string) error
+	Consume(ctx context.Context, token string, appID int) (string, error)
+}
+
+var (
+	validate = validator.New(validator.WithRequiredStructEnabled())
+)
+
+func Register(gRPC *grpc.Server, magicLinks MagicLinks) {
+	ssov1.RegisterMagicLinksServer(gRPC, &serverAPI{magicLinks: magicLinks})
+}
+
+func (s *serverAPI) RequestMagicLink(
+	ctx context.Context,
+	req *ssov1.RequestMagicLinkRequest,
+) (*ssov1.RequestMagicLinkResponse, error) {
+
+	if err := validations.ValidateRequestMagicLink(req.GetEmail(), req.GetAppId(), req.GetScopes(), validate); err != nil {
+		return nil, err
+	}
+
+	if err := s.magicLinks.Request(ctx, req.GetEmail(), int(req.GetAppId()), req.GetScopes()); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.RequestMagicLinkResponse{}, nil
+}
+
+func (s *serverAPI) ConsumeMagicLink(
+	ctx context.Context,
+	req *ssov1.ConsumeMagicLinkRequest,
+) (*ssov1.ConsumeMagicLinkResponse, error) {
+
+	if err := validations.ValidateConsumeMagicLink(req.GetToken(), req.GetAppId(), validate); err != nil {
+		return nil, err
+	}
+
+	token, err := s.magicLinks.Consume(ctx, req.GetToken(), int(req.GetAppId()))
+	if err != nil {
+		var secondFactor *auth.SecondFactorRequiredError
+		if errors.As(err, &secondFactor) {
+			return &ssov1.ConsumeMagicLinkResponse{
+				SecondFactorChallenge: secondFactor.Challenge,
+				SecondFactors:         authgrpc.ToSecondFactors(secondFactor),
+			}, nil
+		}
+
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.ConsumeMagicLinkResponse{
+		Token: token,
+	}, nil
+}
+
+// toStatus maps errors of the service and of issuing token for the app.
+func toStatus(err error) error {
+	switch {
+	case errors.Is(err, magiclinks.ErrAppNotFound), errors.Is(err, auth.ErrInvalidAppID):
+		return status.Error(codes.NotFound, "app not found")
+	case errors.Is(err, magiclinks.ErrAppDisabled), errors.Is(err, auth.ErrAppDisabled):
+		return status.Error(codes.FailedPrecondition, "app is disabled")
+	case errors.Is(err, magiclinks.ErrGrantNotAllowed), errors.Is(err, auth.ErrGrantNotAllowed):
+		return status.Error(codes.PermissionDenied, "magic link login is not allowed for the app")
+	case errors.Is(err, magiclinks.ErrInvalidLink), errors.Is(err, auth.ErrUserNotFound):
+		return status.Error(codes.Unauthenticated, "invalid or expired magic link")
+	case errors.Is(err, auth.ErrScopeRequired):
+		return status.Error(codes.InvalidArgument, "app must request scopes")
+	case errors.Is(err, auth.ErrUnknownScope):
+		return status.Error(codes.InvalidArgument, "scope is not defined for the app")
+	case errors.Is(err, auth.ErrConsentRequired):
+		return status.Error(codes.FailedPrecondition, "user hasn't consented to the requested scopes")
+	case errors.Is(err, auth.ErrSecondFactorTooSoon):
+		return status.Error(codes.ResourceExhausted, "second factor code was sent recently")
+	}
+
+	return status.Error(codes.Internal, "internal error")
+}
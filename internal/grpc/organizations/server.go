@@ -0,0 +1,371 @@
+package organizations
+
+import (
+	"SSO/internal/domain/models"
+	"SSO/internal/grpc/interceptors"
+	"SSO/internal/lib/validations"
+	"SSO/internal/services/organizations"
+	"context"
+	"errors"
+	ssov1 "github.com/futod4m4/protos/gen/go/sso"
+	"github.com/go-playground/validator/v10"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type serverAPI struct {
+	ssov1.UnimplementedOrganizationsServer
+	orgs Organizations
+}
+
+type Organizations interface {
+	CreateOrganization(ctx context.Context, ownerID int64, name string) (orgID int64, err error)
+	Organization(ctx context.Context, actorID, orgID int64) (models.Organization, error)
+	UserOrganizations(ctx context.Context, userID int64) ([]models.OrganizationMember, error)
+	DeleteOrganization(ctx context.Context, actorID, orgID int64) error
+	AddMember(ctx context.Context, actorID, orgID, userID int64, role string) error
+	UpdateMemberRole(ctx context.Context, actorID, orgID, userID int64, role string) error
+	RemoveMember(ctx context.Context, actorID, orgID, userID int64) error
+	Members(ctx context.Context, actorID, orgID int64) ([]models.OrganizationMember, error)
+	GrantAppAccess(ctx context.Context, actorID, orgID int64, appID int) error
+	RevokeAppAccess(ctx context.Context, actorID, orgID int64, appID int) error
+	Apps(ctx context.Context, actorID, orgID int64) ([]models.App, error)
+}
+
+var (
+	validate = validator.New(validator.WithRequiredStructEnabled())
+)
+
+func Register(gRPC *grpc.Server, orgs Organizations) {
+	ssov1.RegisterOrganizationsServer(gRPC, &serverAPI{orgs: orgs})
+}
+
+func (s *serverAPI) CreateOrganization(
+	ctx context.Context,
+	req *ssov1.CreateOrganizationRequest,
+) (*ssov1.CreateOrganizationResponse, error) {
+
+	claims, err := interceptors.RequireClaims(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validations.ValidateOrganizationName(req.GetName(), validate); err != nil {
+		return nil, err
+	}
+
+	orgID, err := s.orgs.CreateOrganization(ctx, claims.UserID, req.GetName())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.CreateOrganizationResponse{
+		OrganizationId: orgID,
+	}, nil
+}
+
+func (s *serverAPI) GetOrganization(
+	ctx context.Context,
+	req *ssov1.GetOrganizationRequest,
+) (*ssov1.GetOrganizationResponse, error) {
+
+	claims, err := interceptors.RequireClaims(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validations.ValidateOrganizationId(req.GetOrganizationId(), validate); err != nil {
+		return nil, err
+	}
+
+	org, err := s.orgs.Organization(ctx, claims.UserID, req.GetOrganizationId())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.GetOrganizationResponse{
+		Organization: &ssov1.Organization{
+			Id:        org.ID,
+			Name:      org.Name,
+			CreatedAt: org.CreatedAt.Unix(),
+		},
+	}, nil
+}
+
+func (s *serverAPI) ListOrganizations(
+	ctx context.Context,
+	req *ssov1.ListOrganizationsRequest,
+) (*ssov1.ListOrganizationsResponse, error) {
+
+	claims, err := interceptors.RequireClaims(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	memberships, err := s.orgs.UserOrganizations(ctx, claims.UserID)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp := &ssov1.ListOrganizationsResponse{
+		Memberships: make([]*ssov1.Membership, 0, len(memberships)),
+	}
+	for _, m := range memberships {
+		resp.Memberships = append(resp.Memberships, &ssov1.Membership{
+			Organization: &ssov1.Organization{
+				Id:   m.OrganizationID,
+				Name: m.OrganizationName,
+			},
+			Role: m.Role,
+		})
+	}
+
+	return resp, nil
+}
+
+func (s *serverAPI) DeleteOrganization(
+	ctx context.Context,
+	req *ssov1.DeleteOrganizationRequest,
+) (*ssov1.DeleteOrganizationResponse, error) {
+
+	claims, err := interceptors.RequireClaims(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validations.ValidateOrganizationId(req.GetOrganizationId(), validate); err != nil {
+		return nil, err
+	}
+
+	if err := s.orgs.DeleteOrganization(ctx, claims.UserID, req.GetOrganizationId()); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.DeleteOrganizationResponse{}, nil
+}
+
+func (s *serverAPI) AddMember(
+	ctx context.Context,
+	req *ssov1.AddMemberRequest,
+) (*ssov1.AddMemberResponse, error) {
+
+	claims, err := interceptors.RequireClaims(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateMemberRequest(req.GetOrganizationId(), req.GetUserId(), req.GetRole()); err != nil {
+		return nil, err
+	}
+
+	err = s.orgs.AddMember(ctx, claims.UserID, req.GetOrganizationId(), req.GetUserId(), req.GetRole())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.AddMemberResponse{}, nil
+}
+
+func (s *serverAPI) UpdateMemberRole(
+	ctx context.Context,
+	req *ssov1.UpdateMemberRoleRequest,
+) (*ssov1.UpdateMemberRoleResponse, error) {
+
+	claims, err := interceptors.RequireClaims(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateMemberRequest(req.GetOrganizationId(), req.GetUserId(), req.GetRole()); err != nil {
+		return nil, err
+	}
+
+	err = s.orgs.UpdateMemberRole(ctx, claims.UserID, req.GetOrganizationId(), req.GetUserId(), req.GetRole())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.UpdateMemberRoleResponse{}, nil
+}
+
+func (s *serverAPI) RemoveMember(
+	ctx context.Context,
+	req *ssov1.RemoveMemberRequest,
+) (*ssov1.RemoveMemberResponse, error) {
+
+	claims, err := interceptors.RequireClaims(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validations.ValidateOrganizationId(req.GetOrganizationId(), validate); err != nil {
+		return nil, err
+	}
+
+	if err := validations.ValidateUserId(req.GetUserId(), validate); err != nil {
+		return nil, err
+	}
+
+	if err := s.orgs.RemoveMember(ctx, claims.UserID, req.GetOrganizationId(), req.GetUserId()); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.RemoveMemberResponse{}, nil
+}
+
+func (s *serverAPI) ListMembers(
+	ctx context.Context,
+	req *ssov1.ListMembersRequest,
+) (*ssov1.ListMembersResponse, error) {
+
+	claims, err := interceptors.RequireClaims(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validations.ValidateOrganizationId(req.GetOrganizationId(), validate); err != nil {
+		return nil, err
+	}
+
+	members, err := s.orgs.Members(ctx, claims.UserID, req.GetOrganizationId())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp := &ssov1.ListMembersResponse{
+		Members: make([]*ssov1.Member, 0, len(members)),
+	}
+	for _, m := range members {
+		resp.Members = append(resp.Members, &ssov1.Member{
+			OrganizationId: m.OrganizationID,
+			UserId:         m.UserID,
+			Email:          m.Email,
+			Role:           m.Role,
+			CreatedAt:      m.CreatedAt.Unix(),
+		})
+	}
+
+	return resp, nil
+}
+
+func (s *serverAPI) GrantAppAccess(
+	ctx context.Context,
+	req *ssov1.GrantAppAccessRequest,
+) (*ssov1.GrantAppAccessResponse, error) {
+
+	claims, err := interceptors.RequireClaims(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validations.ValidateOrganizationId(req.GetOrganizationId(), validate); err != nil {
+		return nil, err
+	}
+
+	if err := validations.ValidateAppId(req.GetAppId(), validate); err != nil {
+		return nil, err
+	}
+
+	if err := s.orgs.GrantAppAccess(ctx, claims.UserID, req.GetOrganizationId(), int(req.GetAppId())); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.GrantAppAccessResponse{}, nil
+}
+
+func (s *serverAPI) RevokeAppAccess(
+	ctx context.Context,
+	req *ssov1.RevokeAppAccessRequest,
+) (*ssov1.RevokeAppAccessResponse, error) {
+
+	claims, err := interceptors.RequireClaims(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validations.ValidateOrganizationId(req.GetOrganizationId(), validate); err != nil {
+		return nil, err
+	}
+
+	if err := validations.ValidateAppId(req.GetAppId(), validate); err != nil {
+		return nil, err
+	}
+
+	if err := s.orgs.RevokeAppAccess(ctx, claims.UserID, req.GetOrganizationId(), int(req.GetAppId())); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.RevokeAppAccessResponse{}, nil
+}
+
+func (s *serverAPI) ListOrganizationApps(
+	ctx context.Context,
+	req *ssov1.ListOrganizationAppsRequest,
+) (*ssov1.ListOrganizationAppsResponse, error) {
+
+	claims, err := interceptors.RequireClaims(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validations.ValidateOrganizationId(req.GetOrganizationId(), validate); err != nil {
+		return nil, err
+	}
+
+	apps, err := s.orgs.Apps(ctx, claims.UserID, req.GetOrganizationId())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp := &ssov1.ListOrganizationAppsResponse{
+		Apps: make([]*ssov1.OrganizationApp, 0, len(apps)),
+	}
+	for _, app := range apps {
+		resp.Apps = append(resp.Apps, &ssov1.OrganizationApp{
+			Id:   int32(app.ID),
+			Name: app.Name,
+		})
+	}
+
+	return resp, nil
+}
+
+func validateMemberRequest(orgID, userID int64, role string) error {
+	if err := validations.ValidateOrganizationId(orgID, validate); err != nil {
+		return err
+	}
+
+	if err := validations.ValidateUserId(userID, validate); err != nil {
+		return err
+	}
+
+	return validations.ValidateOrganizationRole(role, validate)
+}
+
+func toStatus(err error) error {
+	switch {
+	case errors.Is(err, organizations.ErrForbidden):
+		return status.Error(codes.PermissionDenied, "not enough rights in organization")
+	case errors.Is(err, organizations.ErrNotAppAdmin):
+		return status.Error(codes.PermissionDenied, "not an admin of the app")
+	case errors.Is(err, organizations.ErrLastOwner):
+		return status.Error(codes.FailedPrecondition, "organization must have at least one owner")
+	case errors.Is(err, organizations.ErrInvalidRole):
+		return status.Error(codes.InvalidArgument, "invalid role")
+	case errors.Is(err, organizations.ErrOrgExists):
+		return status.Error(codes.AlreadyExists, "organization already exists")
+	case errors.Is(err, organizations.ErrMemberExists):
+		return status.Error(codes.AlreadyExists, "user is already a member")
+	case errors.Is(err, organizations.ErrOrgNotFound):
+		return status.Error(codes.NotFound, "organization not found")
+	case errors.Is(err, organizations.ErrMemberNotFound):
+		return status.Error(codes.NotFound, "member not found")
+	case errors.Is(err, organizations.ErrUserNotFound):
+		return status.Error(codes.NotFound, "user not found")
+	case errors.Is(err, organizations.ErrAppNotFound):
+		return status.Error(codes.NotFound, "app not found")
+	}
+
+	return status.Error(codes.Internal, "internal error")
+}
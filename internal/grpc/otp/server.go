@@ -0,0 +1,274 @@
+package otp
+
+import (
+	"SSO/internal/domain/models"
+	"SSO/internal/grpc/interceptors"
+	"SSO/internal/lib/jwt"
+	"SSO/internal/lib/validations"
+	"SSO/internal/services/auth"
+	"SSO/internal/services/otp"
+	"context"
+	"errors"
+	ssov1 "github.com/futod4m4/protos/gen/go/sso"
+	"github.com/go-playground/validator/v10"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"time"
+)
+
+type serverAPI struct {
+	ssov1.UnimplementedOTPServer
+	otp OTP
+}
+
+type OTP interface {
+	StartLogin(ctx context.Context, email, channel string, appID int, orgID int64, scopes []string) (string, error)
+	Send(ctx context.Context, token string, factorID int64) (otp.Sent, error)
+	Verify(ctx context.Context, token, code string) (string, error)
+	Enroll(ctx context.Context, caller jwt.Claims, channel, destination string) (string, otp.Sent, error)
+	Confirm(ctx context.Context, caller jwt.Claims, token, code string) (models.OTPFactor, error)
+	Factors(ctx context.Context, caller jwt.Claims) ([]models.OTPFactor, error)
+	DeleteFactor(ctx context.Context, caller jwt.Claims, factorID int64) error
+}
+
+var (
+	validate = validator.New(validator.WithRequiredStructEnabled())
+)
+
+func Register(gRPC *grpc.Server, otp OTP) {
+	ssov1.RegisterOTPServer(gRPC, &serverAPI{otp: otp})
+}
+
+func (s *serverAPI) StartOTPLogin(
+	ctx context.Context,
+	req *ssov1.StartOTPLoginRequest,
+) (*ssov1.StartOTPLoginResponse, error) {
+
+	err := validations.ValidateStartOTPLogin(req.GetEmail(), req.GetChannel(), req.GetAppId(), req.GetScopes(), validate)
+	if err != nil {
+		return nil, err
+	}
+
+	challenge, err := s.otp.StartLogin(
+		ctx,
+		req.GetEmail(),
+		req.GetChannel(),
+		int(req.GetAppId()),
+		req.GetOrganizationId(),
+		req.GetScopes(),
+	)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.StartOTPLoginResponse{
+		Challenge: challenge,
+	}, nil
+}
+
+func (s *serverAPI) SendOTP(
+	ctx context.Context,
+	req *ssov1.SendOTPRequest,
+) (*ssov1.SendOTPResponse, error) {
+
+	if err := validations.ValidateSendOTP(req.GetChallenge(), req.GetFactorId(), validate); err != nil {
+		return nil, err
+	}
+
+	sent, err := s.otp.Send(ctx, req.GetChallenge(), req.GetFactorId())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.SendOTPResponse{
+		Sent: toSent(sent),
+	}, nil
+}
+
+func (s *serverAPI) VerifyOTP(
+	ctx context.Context,
+	req *ssov1.VerifyOTPRequest,
+) (*ssov1.VerifyOTPResponse, error) {
+
+	if err := validations.ValidateOTPCode(req.GetChallenge(), req.GetCode(), validate); err != nil {
+		return nil, err
+	}
+
+	token, err := s.otp.Verify(ctx, req.GetChallenge(), req.GetCode())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.VerifyOTPResponse{
+		Token: token,
+	}, nil
+}
+
+func (s *serverAPI) EnrollOTPFactor(
+	ctx context.Context,
+	req *ssov1.EnrollOTPFactorRequest,
+) (*ssov1.EnrollOTPFactorResponse, error) {
+
+	claims, err := interceptors.RequireClaims(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validations.ValidateEnrollOTPFactor(req.GetChannel(), req.GetDestination(), validate); err != nil {
+		return nil, err
+	}
+
+	challenge, sent, err := s.otp.Enroll(ctx, claims, req.GetChannel(), req.GetDestination())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.EnrollOTPFactorResponse{
+		Challenge: challenge,
+		Sent:      toSent(sent),
+	}, nil
+}
+
+func (s *serverAPI) ConfirmOTPFactor(
+	ctx context.Context,
+	req *ssov1.ConfirmOTPFactorRequest,
+) (*ssov1.ConfirmOTPFactorResponse, error) {
+
+	claims, err := interceptors.RequireClaims(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validations.ValidateOTPCode(req.GetChallenge(), req.GetCode(), validate); err != nil {
+		return nil, err
+	}
+
+	factor, err := s.otp.Confirm(ctx, claims, req.GetChallenge(), req.GetCode())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.ConfirmOTPFactorResponse{
+		Factor: toFactor(factor),
+	}, nil
+}
+
+func (s *serverAPI) ListOTPFactors(
+	ctx context.Context,
+	req *ssov1.ListOTPFactorsRequest,
+) (*ssov1.ListOTPFactorsResponse, error) {
+
+	claims, err := interceptors.RequireClaims(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	factors, err := s.otp.Factors(ctx, claims)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp := &ssov1.ListOTPFactorsResponse{
+		Factors: make([]*ssov1.OTPFactor, 0, len(factors)),
+	}
+	for _, factor := range factors {
+		resp.Factors = append(resp.Factors, toFactor(factor))
+	}
+
+	return resp, nil
+}
+
+func (s *serverAPI) DeleteOTPFactor(
+	ctx context.Context,
+	req *ssov1.DeleteOTPFactorRequest,
+) (*ssov1.DeleteOTPFactorResponse, error) {
+
+	claims, err := interceptors.RequireClaims(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validations.ValidateOTPFactorId(req.GetId(), validate); err != nil {
+		return nil, err
+	}
+
+	if err := s.otp.DeleteFactor(ctx, claims, req.GetId()); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.DeleteOTPFactorResponse{}, nil
+}
+
+// toStatus maps errors of the service and of issuing token for the app.
+func toStatus(err error) error {
+	switch {
+	case errors.Is(err, otp.ErrUnknownChannel):
+		return status.Error(codes.InvalidArgument, "channel must be email or sms")
+	case errors.Is(err, otp.ErrAppNotFound), errors.Is(err, auth.ErrInvalidAppID):
+		return status.Error(codes.NotFound, "app not found")
+	case errors.Is(err, otp.ErrAppDisabled), errors.Is(err, auth.ErrAppDisabled):
+		return status.Error(codes.FailedPrecondition, "app is disabled")
+	case errors.Is(err, otp.ErrGrantNotAllowed), errors.Is(err, auth.ErrGrantNotAllowed):
+		return status.Error(codes.PermissionDenied, "login is not allowed for the app")
+	case errors.Is(err, otp.ErrInvalidChallenge), errors.Is(err, auth.ErrInvalidChallenge),
+		errors.Is(err, auth.ErrUserNotFound):
+		return status.Error(codes.Unauthenticated, "invalid or expired challenge")
+	case errors.Is(err, otp.ErrInvalidCode):
+		return status.Error(codes.Unauthenticated, "invalid code")
+	case errors.Is(err, otp.ErrTooManyAttempts):
+		return status.Error(codes.ResourceExhausted, "too many attempts, send another code")
+	case errors.Is(err, otp.ErrResendTooSoon):
+		return status.Error(codes.ResourceExhausted, "code was sent recently")
+	case errors.Is(err, otp.ErrTooManySends):
+		return status.Error(codes.ResourceExhausted, "too many codes were sent")
+	case errors.Is(err, otp.ErrCodeNotSent):
+		return status.Error(codes.FailedPrecondition, "no code was sent, call SendOTP first")
+	case errors.Is(err, otp.ErrFactorRequired):
+		return status.Error(codes.InvalidArgument, "factor_id is required")
+	case errors.Is(err, otp.ErrFactorNotFound):
+		return status.Error(codes.NotFound, "factor not found")
+	case errors.Is(err, otp.ErrCallerNotAllowed):
+		return status.Error(codes.PermissionDenied, "factors must be managed by the user themselves")
+	case errors.Is(err, otp.ErrReauthRequired):
+		return status.Error(codes.Unauthenticated, "re-authentication required")
+	case errors.Is(err, auth.ErrNotOrgMember):
+		return status.Error(codes.PermissionDenied, "user is not a member of the organization")
+	case errors.Is(err, auth.ErrOrgAppNotAllowed):
+		return status.Error(codes.PermissionDenied, "organization has no access to the app")
+	case errors.Is(err, auth.ErrScopeRequired):
+		return status.Error(codes.InvalidArgument, "app must request scopes")
+	case errors.Is(err, auth.ErrUnknownScope):
+		return status.Error(codes.InvalidArgument, "scope is not defined for the app")
+	case errors.Is(err, auth.ErrConsentRequired):
+		return status.Error(codes.FailedPrecondition, "user hasn't consented to the requested scopes")
+	}
+
+	return status.Error(codes.Internal, "internal error")
+}
+
+func toSent(sent otp.Sent) *ssov1.SentOTP {
+	return &ssov1.SentOTP{
+		Channel:     sent.Channel,
+		Destination: sent.Destination,
+		ExpiresAt:   sent.ExpiresAt.Unix(),
+		ResendAt:    unixOrZero(sent.ResendAt),
+	}
+}
+
+func toFactor(factor models.OTPFactor) *ssov1.OTPFactor {
+	return &ssov1.OTPFactor{
+		Id:          factor.ID,
+		Channel:     factor.Channel,
+		Destination: factor.Destination,
+		CreatedAt:   unixOrZero(factor.CreatedAt),
+	}
+}
+
+func unixOrZero(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+
+	return t.Unix()
+}
@@ -0,0 +1,374 @@
+package permissions
+
+import (
+	"SSO/internal/domain/models"
+	"SSO/internal/grpc/interceptors"
+	"SSO/internal/lib/validations"
+	"SSO/internal/services/permissions"
+	"context"
+	"errors"
+	ssov1 "github.com/futod4m4/protos/gen/go/sso"
+	"github.com/go-playground/validator/v10"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type serverAPI struct {
+	ssov1.UnimplementedPermissionsServer
+	permissions Permissions
+}
+
+type Permissions interface {
+	CreateRole(ctx context.Context, appID int, name string) (roleID int64, err error)
+	DeleteRole(ctx context.Context, appID int, roleID int64) error
+	Roles(ctx context.Context, appID int) ([]models.Role, error)
+	CreatePermission(ctx context.Context, appID int, name string) (permissionID int64, err error)
+	DeletePermission(ctx context.Context, appID int, permissionID int64) error
+	Permissions(ctx context.Context, appID int) ([]models.Permission, error)
+	GrantPermission(ctx context.Context, appID int, roleID, permissionID int64) error
+	RevokePermission(ctx context.Context, appID int, roleID, permissionID int64) error
+	AssignRole(ctx context.Context, appID int, userID, roleID int64) error
+	UnassignRole(ctx context.Context, appID int, userID, roleID int64) error
+	UserAccess(ctx context.Context, appID int, userID int64) (roles []models.Role, permissions []string, err error)
+	CheckPermission(ctx context.Context, appID int, userID int64, permission string) (bool, error)
+	IsAppAdmin(ctx context.Context, appID int, userID int64) (bool, error)
+}
+
+var (
+	validate = validator.New(validator.WithRequiredStructEnabled())
+)
+
+func Register(gRPC *grpc.Server, permissions Permissions) {
+	ssov1.RegisterPermissionsServer(gRPC, &serverAPI{permissions: permissions})
+}
+
+func (s *serverAPI) CreateRole(
+	ctx context.Context,
+	req *ssov1.CreateRoleRequest,
+) (*ssov1.CreateRoleResponse, error) {
+
+	if err := validations.ValidateAccessName(req.GetName(), validate); err != nil {
+		return nil, err
+	}
+
+	if err := s.requireAdmin(ctx, req.GetAppId()); err != nil {
+		return nil, err
+	}
+
+	roleID, err := s.permissions.CreateRole(ctx, int(req.GetAppId()), req.GetName())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.CreateRoleResponse{
+		RoleId: roleID,
+	}, nil
+}
+
+func (s *serverAPI) DeleteRole(
+	ctx context.Context,
+	req *ssov1.DeleteRoleRequest,
+) (*ssov1.DeleteRoleResponse, error) {
+
+	if err := validations.ValidateRoleId(req.GetRoleId(), validate); err != nil {
+		return nil, err
+	}
+
+	if err := s.requireAdmin(ctx, req.GetAppId()); err != nil {
+		return nil, err
+	}
+
+	if err := s.permissions.DeleteRole(ctx, int(req.GetAppId()), req.GetRoleId()); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.DeleteRoleResponse{}, nil
+}
+
+func (s *serverAPI) ListRoles(
+	ctx context.Context,
+	req *ssov1.ListRolesRequest,
+) (*ssov1.ListRolesResponse, error) {
+
+	if err := s.requireAdmin(ctx, req.GetAppId()); err != nil {
+		return nil, err
+	}
+
+	roles, err := s.permissions.Roles(ctx, int(req.GetAppId()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.ListRolesResponse{
+		Roles: toRoles(roles),
+	}, nil
+}
+
+func (s *serverAPI) CreatePermission(
+	ctx context.Context,
+	req *ssov1.CreatePermissionRequest,
+) (*ssov1.CreatePermissionResponse, error) {
+
+	if err := validations.ValidateAccessName(req.GetName(), validate); err != nil {
+		return nil, err
+	}
+
+	if err := s.requireAdmin(ctx, req.GetAppId()); err != nil {
+		return nil, err
+	}
+
+	permissionID, err := s.permissions.CreatePermission(ctx, int(req.GetAppId()), req.GetName())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.CreatePermissionResponse{
+		PermissionId: permissionID,
+	}, nil
+}
+
+func (s *serverAPI) DeletePermission(
+	ctx context.Context,
+	req *ssov1.DeletePermissionRequest,
+) (*ssov1.DeletePermissionResponse, error) {
+
+	if err := validations.ValidatePermissionId(req.GetPermissionId(), validate); err != nil {
+		return nil, err
+	}
+
+	if err := s.requireAdmin(ctx, req.GetAppId()); err != nil {
+		return nil, err
+	}
+
+	if err := s.permissions.DeletePermission(ctx, int(req.GetAppId()), req.GetPermissionId()); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.DeletePermissionResponse{}, nil
+}
+
+func (s *serverAPI) ListPermissions(
+	ctx context.Context,
+	req *ssov1.ListPermissionsRequest,
+) (*ssov1.ListPermissionsResponse, error) {
+
+	if err := s.requireAdmin(ctx, req.GetAppId()); err != nil {
+		return nil, err
+	}
+
+	permissions, err := s.permissions.Permissions(ctx, int(req.GetAppId()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp := &ssov1.ListPermissionsResponse{
+		Permissions: make([]*ssov1.Permission, 0, len(permissions)),
+	}
+	for _, p := range permissions {
+		resp.Permissions = append(resp.Permissions, &ssov1.Permission{
+			Id:    p.ID,
+			AppId: int32(p.AppID),
+			Name:  p.Name,
+		})
+	}
+
+	return resp, nil
+}
+
+func (s *serverAPI) GrantPermission(
+	ctx context.Context,
+	req *ssov1.GrantPermissionRequest,
+) (*ssov1.GrantPermissionResponse, error) {
+
+	if err := validations.ValidateRoleId(req.GetRoleId(), validate); err != nil {
+		return nil, err
+	}
+
+	if err := validations.ValidatePermissionId(req.GetPermissionId(), validate); err != nil {
+		return nil, err
+	}
+
+	if err := s.requireAdmin(ctx, req.GetAppId()); err != nil {
+		return nil, err
+	}
+
+	err := s.permissions.GrantPermission(ctx, int(req.GetAppId()), req.GetRoleId(), req.GetPermissionId())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.GrantPermissionResponse{}, nil
+}
+
+func (s *serverAPI) RevokePermission(
+	ctx context.Context,
+	req *ssov1.RevokePermissionRequest,
+) (*ssov1.RevokePermissionResponse, error) {
+
+	if err := validations.ValidateRoleId(req.GetRoleId(), validate); err != nil {
+		return nil, err
+	}
+
+	if err := validations.ValidatePermissionId(req.GetPermissionId(), validate); err != nil {
+		return nil, err
+	}
+
+	if err := s.requireAdmin(ctx, req.GetAppId()); err != nil {
+		return nil, err
+	}
+
+	err := s.permissions.RevokePermission(ctx, int(req.GetAppId()), req.GetRoleId(), req.GetPermissionId())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.RevokePermissionResponse{}, nil
+}
+
+func (s *serverAPI) AssignRole(
+	ctx context.Context,
+	req *ssov1.AssignRoleRequest,
+) (*ssov1.AssignRoleResponse, error) {
+
+	if err := validations.ValidateUserId(req.GetUserId(), validate); err != nil {
+		return nil, err
+	}
+
+	if err := validations.ValidateRoleId(req.GetRoleId(), validate); err != nil {
+		return nil, err
+	}
+
+	if err := s.requireAdmin(ctx, req.GetAppId()); err != nil {
+		return nil, err
+	}
+
+	if err := s.permissions.AssignRole(ctx, int(req.GetAppId()), req.GetUserId(), req.GetRoleId()); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.AssignRoleResponse{}, nil
+}
+
+func (s *serverAPI) UnassignRole(
+	ctx context.Context,
+	req *ssov1.UnassignRoleRequest,
+) (*ssov1.UnassignRoleResponse, error) {
+
+	if err := validations.ValidateUserId(req.GetUserId(), validate); err != nil {
+		return nil, err
+	}
+
+	if err := validations.ValidateRoleId(req.GetRoleId(), validate); err != nil {
+		return nil, err
+	}
+
+	if err := s.requireAdmin(ctx, req.GetAppId()); err != nil {
+		return nil, err
+	}
+
+	if err := s.permissions.UnassignRole(ctx, int(req.GetAppId()), req.GetUserId(), req.GetRoleId()); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.UnassignRoleResponse{}, nil
+}
+
+func (s *serverAPI) ListUserRoles(
+	ctx context.Context,
+	req *ssov1.ListUserRolesRequest,
+) (*ssov1.ListUserRolesResponse, error) {
+
+	if err := validations.ValidateUserId(req.GetUserId(), validate); err != nil {
+		return nil, err
+	}
+
+	if err := s.requireAdmin(ctx, req.GetAppId()); err != nil {
+		return nil, err
+	}
+
+	roles, permissions, err := s.permissions.UserAccess(ctx, int(req.GetAppId()), req.GetUserId())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.ListUserRolesResponse{
+		Roles:       toRoles(roles),
+		Permissions: permissions,
+	}, nil
+}
+
+func (s *serverAPI) CheckPermission(
+	ctx context.Context,
+	req *ssov1.CheckPermissionRequest,
+) (*ssov1.CheckPermissionResponse, error) {
+
+	if err := validations.ValidateAppId(req.GetAppId(), validate); err != nil {
+		return nil, err
+	}
+
+	if err := validations.ValidateUserId(req.GetUserId(), validate); err != nil {
+		return nil, err
+	}
+
+	if err := validations.ValidateAccessName(req.GetPermission(), validate); err != nil {
+		return nil, err
+	}
+
+	// Answers reveal what users may do in the app, so only the app itself
+	// may ask for them.
+	if _, err := interceptors.RequireApp(ctx, int(req.GetAppId())); err != nil {
+		return nil, err
+	}
+
+	allowed, err := s.permissions.CheckPermission(ctx, int(req.GetAppId()), req.GetUserId(), req.GetPermission())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.CheckPermissionResponse{
+		Allowed: allowed,
+	}, nil
+}
+
+// requireAdmin checks that caller is an admin of the app.
+func (s *serverAPI) requireAdmin(ctx context.Context, appID int32) error {
+	if err := validations.ValidateAppId(appID, validate); err != nil {
+		return err
+	}
+
+	return interceptors.RequireAppAdmin(ctx, int(appID), s.permissions)
+}
+
+func toStatus(err error) error {
+	switch {
+	case errors.Is(err, permissions.ErrAppNotFound):
+		return status.Error(codes.NotFound, "app not found")
+	case errors.Is(err, permissions.ErrUserNotFound):
+		return status.Error(codes.NotFound, "user not found")
+	case errors.Is(err, permissions.ErrRoleNotFound):
+		return status.Error(codes.NotFound, "role not found")
+	case errors.Is(err, permissions.ErrPermissionNotFound):
+		return status.Error(codes.NotFound, "permission not found")
+	case errors.Is(err, permissions.ErrRoleExists):
+		return status.Error(codes.AlreadyExists, "role already exists")
+	case errors.Is(err, permissions.ErrPermissionExists):
+		return status.Error(codes.AlreadyExists, "permission already exists")
+	}
+
+	return status.Error(codes.Internal, "internal error")
+}
+
+func toRoles(roles []models.Role) []*ssov1.Role {
+	res := make([]*ssov1.Role, 0, len(roles))
+	for _, role := range roles {
+		res = append(res, &ssov1.Role{
+			Id:          role.ID,
+			AppId:       int32(role.AppID),
+			Name:        role.Name,
+			Permissions: role.Permissions,
+		})
+	}
+
+	return res
+}
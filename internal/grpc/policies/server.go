@@ -0,0 +1,296 @@
+package policies
+
+import (
+	"SSO/internal/domain/models"
+	"SSO/internal/grpc/interceptors"
+	"SSO/internal/lib/policy"
+	"SSO/internal/lib/validations"
+	"SSO/internal/services/policies"
+	"context"
+	"errors"
+	ssov1 "github.com/futod4m4/protos/gen/go/sso"
+	"github.com/go-playground/validator/v10"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type serverAPI struct {
+	ssov1.UnimplementedPoliciesServer
+	policies Policies
+	admins   interceptors.AppAdminChecker
+}
+
+type Policies interface {
+	CreatePolicy(ctx context.Context, p models.Policy) (policyID int64, err error)
+	UpdatePolicy(ctx context.Context, p models.Policy) error
+	DeletePolicy(ctx context.Context, appID int, policyID int64) error
+	Policies(ctx context.Context, appID int) ([]models.Policy, error)
+	Authorize(
+		ctx context.Context,
+		appID int,
+		userID int64,
+		action string,
+		resource models.Resource,
+		attributes map[string]string,
+	) (policy.Decision, error)
+	DryRun(
+		ctx context.Context,
+		appID int,
+		drafts []models.Policy,
+		removed []int64,
+		limit int,
+	) (policies.DryRunReport, error)
+}
+
+var (
+	validate = validator.New(validator.WithRequiredStructEnabled())
+)
+
+func Register(gRPC *grpc.Server, policies Policies, admins interceptors.AppAdminChecker) {
+	ssov1.RegisterPoliciesServer(gRPC, &serverAPI{policies: policies, admins: admins})
+}
+
+func (s *serverAPI) CreatePolicy(
+	ctx context.Context,
+	req *ssov1.CreatePolicyRequest,
+) (*ssov1.CreatePolicyResponse, error) {
+
+	if err := s.requireAdmin(ctx, req.GetAppId()); err != nil {
+		return nil, err
+	}
+
+	policyID, err := s.policies.CreatePolicy(ctx, fromPolicy(req.GetAppId(), req.GetPolicy()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.CreatePolicyResponse{
+		PolicyId: policyID,
+	}, nil
+}
+
+func (s *serverAPI) UpdatePolicy(
+	ctx context.Context,
+	req *ssov1.UpdatePolicyRequest,
+) (*ssov1.UpdatePolicyResponse, error) {
+
+	if err := validations.ValidatePolicyId(req.GetPolicy().GetId(), validate); err != nil {
+		return nil, err
+	}
+
+	if err := s.requireAdmin(ctx, req.GetAppId()); err != nil {
+		return nil, err
+	}
+
+	if err := s.policies.UpdatePolicy(ctx, fromPolicy(req.GetAppId(), req.GetPolicy())); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.UpdatePolicyResponse{}, nil
+}
+
+func (s *serverAPI) DeletePolicy(
+	ctx context.Context,
+	req *ssov1.DeletePolicyRequest,
+) (*ssov1.DeletePolicyResponse, error) {
+
+	if err := validations.ValidatePolicyId(req.GetPolicyId(), validate); err != nil {
+		return nil, err
+	}
+
+	if err := s.requireAdmin(ctx, req.GetAppId()); err != nil {
+		return nil, err
+	}
+
+	if err := s.policies.DeletePolicy(ctx, int(req.GetAppId()), req.GetPolicyId()); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.DeletePolicyResponse{}, nil
+}
+
+func (s *serverAPI) ListPolicies(
+	ctx context.Context,
+	req *ssov1.ListPoliciesRequest,
+) (*ssov1.ListPoliciesResponse, error) {
+
+	if err := s.requireAdmin(ctx, req.GetAppId()); err != nil {
+		return nil, err
+	}
+
+	list, err := s.policies.Policies(ctx, int(req.GetAppId()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp := &ssov1.ListPoliciesResponse{
+		Policies: make([]*ssov1.Policy, 0, len(list)),
+	}
+	for _, p := range list {
+		resp.Policies = append(resp.Policies, toPolicy(p))
+	}
+
+	return resp, nil
+}
+
+func (s *serverAPI) Authorize(
+	ctx context.Context,
+	req *ssov1.AuthorizeRequest,
+) (*ssov1.AuthorizeResponse, error) {
+
+	if err := validations.ValidateAuthorize(req, validate); err != nil {
+		return nil, err
+	}
+
+	// Decisions reveal attributes of users and every one is recorded, so
+	// only the app itself may ask for them.
+	if _, err := interceptors.RequireApp(ctx, int(req.GetAppId())); err != nil {
+		return nil, err
+	}
+
+	decision, err := s.policies.Authorize(
+		ctx,
+		int(req.GetAppId()),
+		req.GetUserId(),
+		req.GetAction(),
+		models.Resource{
+			Type:       req.GetResource().GetType(),
+			ID:         req.GetResource().GetId(),
+			Attributes: req.GetResource().GetAttributes(),
+		},
+		req.GetAttributes(),
+	)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.AuthorizeResponse{
+		Allowed:     decision.Allowed,
+		Reason:      decision.Reason,
+		Evaluations: toEvaluations(decision.Evaluations),
+	}, nil
+}
+
+func (s *serverAPI) DryRunPolicies(
+	ctx context.Context,
+	req *ssov1.DryRunPoliciesRequest,
+) (*ssov1.DryRunPoliciesResponse, error) {
+
+	if err := s.requireAdmin(ctx, req.GetAppId()); err != nil {
+		return nil, err
+	}
+
+	drafts := make([]models.Policy, 0, len(req.GetPolicies()))
+	for _, p := range req.GetPolicies() {
+		drafts = append(drafts, fromPolicy(req.GetAppId(), p))
+	}
+
+	report, err := s.policies.DryRun(ctx, int(req.GetAppId()), drafts, req.GetRemovedPolicyIds(), int(req.GetLimit()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp := &ssov1.DryRunPoliciesResponse{
+		Evaluated: int32(report.Evaluated),
+		Changed:   make([]*ssov1.DryRunResult, 0, len(report.Changed)),
+	}
+	for _, r := range report.Changed {
+		resp.Changed = append(resp.Changed, &ssov1.DryRunResult{
+			RequestId:       r.Request.ID,
+			UserId:          r.Request.UserID,
+			Action:          r.Request.Action,
+			ResourceType:    r.Request.ResourceType,
+			RecordedAllowed: r.Request.Allowed,
+			Allowed:         r.Decision.Allowed,
+			Reason:          r.Decision.Reason,
+			Evaluations:     toEvaluations(r.Decision.Evaluations),
+		})
+	}
+
+	return resp, nil
+}
+
+// requireAdmin checks that caller is an admin of the app.
+func (s *serverAPI) requireAdmin(ctx context.Context, appID int32) error {
+	if err := validations.ValidateAppId(appID, validate); err != nil {
+		return err
+	}
+
+	return interceptors.RequireAppAdmin(ctx, int(appID), s.admins)
+}
+
+func toStatus(err error) error {
+	switch {
+	case errors.Is(err, policies.ErrInvalidPolicy):
+		return status.Error(codes.InvalidArgument, errors.Unwrap(err).Error())
+	case errors.Is(err, policies.ErrAppNotFound):
+		return status.Error(codes.NotFound, "app not found")
+	case errors.Is(err, policies.ErrUserNotFound):
+		return status.Error(codes.NotFound, "user not found")
+	case errors.Is(err, policies.ErrPolicyNotFound):
+		return status.Error(codes.NotFound, "policy not found")
+	case errors.Is(err, policies.ErrPolicyExists):
+		return status.Error(codes.AlreadyExists, "policy already exists")
+	}
+
+	return status.Error(codes.Internal, "internal error")
+}
+
+func fromPolicy(appID int32, p *ssov1.Policy) models.Policy {
+	conditions := make([]models.PolicyCondition, 0, len(p.GetConditions()))
+	for _, c := range p.GetConditions() {
+		conditions = append(conditions, models.PolicyCondition{
+			Attribute: c.GetAttribute(),
+			Operator:  c.GetOperator(),
+			Value:     c.GetValue(),
+		})
+	}
+
+	return models.Policy{
+		ID:          p.GetId(),
+		AppID:       int(appID),
+		Name:        p.GetName(),
+		Description: p.GetDescription(),
+		Effect:      p.GetEffect(),
+		Actions:     p.GetActions(),
+		Resources:   p.GetResources(),
+		Conditions:  conditions,
+	}
+}
+
+func toPolicy(p models.Policy) *ssov1.Policy {
+	conditions := make([]*ssov1.PolicyCondition, 0, len(p.Conditions))
+	for _, c := range p.Conditions {
+		conditions = append(conditions, &ssov1.PolicyCondition{
+			Attribute: c.Attribute,
+			Operator:  c.Operator,
+			Value:     c.Value,
+		})
+	}
+
+	return &ssov1.Policy{
+		Id:          p.ID,
+		Name:        p.Name,
+		Description: p.Description,
+		Effect:      p.Effect,
+		Actions:     p.Actions,
+		Resources:   p.Resources,
+		Conditions:  conditions,
+	}
+}
+
+func toEvaluations(evaluations []policy.Evaluation) []*ssov1.PolicyEvaluation {
+	res := make([]*ssov1.PolicyEvaluation, 0, len(evaluations))
+	for _, e := range evaluations {
+		res = append(res, &ssov1.PolicyEvaluation{
+			PolicyId:   e.PolicyID,
+			PolicyName: e.PolicyName,
+			Effect:     e.Effect,
+			Matched:    e.Matched,
+			Reason:     e.Reason,
+		})
+	}
+
+	return res
+}
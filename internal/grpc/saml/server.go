@@ -0,0 +1,214 @@
+package saml
+
+import (
+	"SSO/internal/domain/models"
+	"SSO/internal/grpc/interceptors"
+	"SSO/internal/lib/jwt"
+	"SSO/internal/lib/validations"
+	"SSO/internal/services/saml"
+	"context"
+	"errors"
+	ssov1 "github.com/futod4m4/protos/gen/go/sso"
+	"github.com/go-playground/validator/v10"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type serverAPI struct {
+	ssov1.UnimplementedSAMLServer
+	saml   SAML
+	admins interceptors.AppAdminChecker
+}
+
+type SAML interface {
+	Metadata() []byte
+	Request(ctx context.Context, samlRequest, binding string) (saml.Request, error)
+	Respond(ctx context.Context, caller jwt.Claims, samlRequest, binding string) (saml.Response, error)
+	SaveProvider(ctx context.Context, sp models.ServiceProvider) (models.ServiceProvider, error)
+	Provider(ctx context.Context, appID int) (models.ServiceProvider, error)
+	DeleteProvider(ctx context.Context, appID int) error
+}
+
+var (
+	validate = validator.New(validator.WithRequiredStructEnabled())
+)
+
+func Register(gRPC *grpc.Server, saml SAML, admins interceptors.AppAdminChecker) {
+	ssov1.RegisterSAMLServer(gRPC, &serverAPI{saml: saml, admins: admins})
+}
+
+func (s *serverAPI) GetIdPMetadata(
+	ctx context.Context,
+	req *ssov1.GetIdPMetadataRequest,
+) (*ssov1.GetIdPMetadataResponse, error) {
+
+	return &ssov1.GetIdPMetadataResponse{
+		Metadata: string(s.saml.Metadata()),
+	}, nil
+}
+
+func (s *serverAPI) GetSAMLRequest(
+	ctx context.Context,
+	req *ssov1.GetSAMLRequestRequest,
+) (*ssov1.GetSAMLRequestResponse, error) {
+
+	if err := validations.ValidateSAMLRequest(req.GetSamlRequest(), req.GetBinding(), validate); err != nil {
+		return nil, err
+	}
+
+	samlReq, err := s.saml.Request(ctx, req.GetSamlRequest(), req.GetBinding())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.GetSAMLRequestResponse{
+		RequestId: samlReq.AuthnRequest.ID,
+		EntityId:  samlReq.Provider.EntityID,
+		AppId:     int32(samlReq.App.ID),
+		AppName:   samlReq.App.Name,
+		AcsUrl:    samlReq.ACSURL,
+	}, nil
+}
+
+func (s *serverAPI) CompleteSAMLLogin(
+	ctx context.Context,
+	req *ssov1.CompleteSAMLLoginRequest,
+) (*ssov1.CompleteSAMLLoginResponse, error) {
+
+	claims, err := interceptors.RequireClaims(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validations.ValidateSAMLRequest(req.GetSamlRequest(), req.GetBinding(), validate); err != nil {
+		return nil, err
+	}
+
+	resp, err := s.saml.Respond(ctx, claims, req.GetSamlRequest(), req.GetBinding())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.CompleteSAMLLoginResponse{
+		AcsUrl:       resp.ACSURL,
+		SamlResponse: resp.SAMLResponse,
+		RelayState:   req.GetRelayState(),
+	}, nil
+}
+
+func (s *serverAPI) SetServiceProvider(
+	ctx context.Context,
+	req *ssov1.SetServiceProviderRequest,
+) (*ssov1.SetServiceProviderResponse, error) {
+
+	if err := validations.ValidateServiceProvider(req.GetProvider(), validate); err != nil {
+		return nil, err
+	}
+
+	if err := s.requireAdmin(ctx, req.GetProvider().GetAppId()); err != nil {
+		return nil, err
+	}
+
+	sp, err := s.saml.SaveProvider(ctx, models.ServiceProvider{
+		AppID:        int(req.GetProvider().GetAppId()),
+		EntityID:     req.GetProvider().GetEntityId(),
+		ACSURLs:      req.GetProvider().GetAcsUrls(),
+		NameIDFormat: req.GetProvider().GetNameIdFormat(),
+		Attributes:   req.GetProvider().GetAttributes(),
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.SetServiceProviderResponse{
+		Provider: toServiceProvider(sp),
+	}, nil
+}
+
+func (s *serverAPI) GetServiceProvider(
+	ctx context.Context,
+	req *ssov1.GetServiceProviderRequest,
+) (*ssov1.GetServiceProviderResponse, error) {
+
+	if err := s.requireAdmin(ctx, req.GetAppId()); err != nil {
+		return nil, err
+	}
+
+	sp, err := s.saml.Provider(ctx, int(req.GetAppId()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.GetServiceProviderResponse{
+		Provider: toServiceProvider(sp),
+	}, nil
+}
+
+func (s *serverAPI) DeleteServiceProvider(
+	ctx context.Context,
+	req *ssov1.DeleteServiceProviderRequest,
+) (*ssov1.DeleteServiceProviderResponse, error) {
+
+	if err := s.requireAdmin(ctx, req.GetAppId()); err != nil {
+		return nil, err
+	}
+
+	if err := s.saml.DeleteProvider(ctx, int(req.GetAppId())); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.DeleteServiceProviderResponse{}, nil
+}
+
+// requireAdmin checks that caller is an admin of the app.
+func (s *serverAPI) requireAdmin(ctx context.Context, appID int32) error {
+	if err := validations.ValidateAppId(appID, validate); err != nil {
+		return err
+	}
+
+	return interceptors.RequireAppAdmin(ctx, int(appID), s.admins)
+}
+
+func toStatus(err error) error {
+	var invalidErr *saml.InvalidProviderError
+	if errors.As(err, &invalidErr) {
+		return status.Error(codes.InvalidArgument, invalidErr.Error())
+	}
+
+	switch {
+	case errors.Is(err, saml.ErrProviderNotFound):
+		return status.Error(codes.NotFound, "service provider not found")
+	case errors.Is(err, saml.ErrProviderExists):
+		return status.Error(codes.AlreadyExists, "entity ID is registered for another app")
+	case errors.Is(err, saml.ErrInvalidRequest):
+		return status.Error(codes.InvalidArgument, "invalid SAML request")
+	case errors.Is(err, saml.ErrUnsupported):
+		return status.Error(codes.InvalidArgument, "unsupported SAML binding")
+	case errors.Is(err, saml.ErrInvalidACSURL):
+		return status.Error(codes.InvalidArgument, "assertion consumer service is not registered")
+	case errors.Is(err, saml.ErrAppNotFound):
+		return status.Error(codes.NotFound, "app not found")
+	case errors.Is(err, saml.ErrAppDisabled):
+		return status.Error(codes.FailedPrecondition, "app is disabled")
+	case errors.Is(err, saml.ErrUserNotFound):
+		return status.Error(codes.NotFound, "user not found")
+	case errors.Is(err, saml.ErrCallerNotAllowed):
+		return status.Error(codes.PermissionDenied, "user must sign in themselves")
+	case errors.Is(err, saml.ErrWrongApp):
+		return status.Error(codes.PermissionDenied, "token is issued for another app")
+	}
+
+	return status.Error(codes.Internal, "internal error")
+}
+
+func toServiceProvider(sp models.ServiceProvider) *ssov1.ServiceProvider {
+	return &ssov1.ServiceProvider{
+		AppId:        int32(sp.AppID),
+		EntityId:     sp.EntityID,
+		AcsUrls:      sp.ACSURLs,
+		NameIdFormat: sp.NameIDFormat,
+		Attributes:   sp.Attributes,
+		CreatedAt:    sp.CreatedAt.Unix(),
+	}
+}
@@ -0,0 +1,438 @@
+package serviceaccounts
+
+import (
+	"SSO/internal/domain/models"
+	apikeysgrpc "SSO/internal/grpc/apikeys"
+	"SSO/internal/grpc/interceptors"
+	"SSO/internal/lib/validations"
+	"SSO/internal/services/apikeys"
+	"SSO/internal/services/auth"
+	"SSO/internal/services/organizations"
+	"SSO/internal/services/serviceaccounts"
+	"context"
+	"errors"
+	ssov1 "github.com/futod4m4/protos/gen/go/sso"
+	"github.com/go-playground/validator/v10"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"time"
+)
+
+type serverAPI struct {
+	ssov1.UnimplementedServiceAccountsServer
+	accounts ServiceAccounts
+	admins   interceptors.AppAdminChecker
+	orgs     OrganizationManagers
+}
+
+type ServiceAccounts interface {
+	Create(
+		ctx context.Context,
+		actorID int64,
+		appID int,
+		orgID int64,
+		name string,
+		description string,
+	) (models.ServiceAccount, error)
+	ServiceAccount(ctx context.Context, id int64) (models.ServiceAccount, error)
+	ServiceAccounts(ctx context.Context, appID int, orgID int64) ([]models.ServiceAccount, error)
+	SetDisabled(ctx context.Context, id int64, disabled bool) error
+	Delete(ctx context.Context, id int64) error
+	AddKey(ctx context.Context, id int64, kid, publicKey string, expiresAt time.Time) (models.ServiceAccountKey, error)
+	Keys(ctx context.Context, id int64) ([]models.ServiceAccountKey, error)
+	RemoveKey(ctx context.Context, id, keyID int64) error
+	CreateAPIKey(
+		ctx context.Context,
+		id int64,
+		appID int,
+		name string,
+		scopes []string,
+		expiresAt time.Time,
+	) (key string, apiKey models.APIKey, err error)
+	Token(ctx context.Context, assertion string, appID int) (token string, err error)
+}
+
+// OrganizationManagers checks rights to manage organizations.
+type OrganizationManagers interface {
+	CheckManager(ctx context.Context, actorID, orgID int64) error
+}
+
+var (
+	validate = validator.New(validator.WithRequiredStructEnabled())
+)
+
+func Register(
+	gRPC *grpc.Server,
+	accounts ServiceAccounts,
+	admins interceptors.AppAdminChecker,
+	orgs OrganizationManagers,
+) {
+	ssov1.RegisterServiceAccountsServer(gRPC, &serverAPI{accounts: accounts, admins: admins, orgs: orgs})
+}
+
+func (s *serverAPI) CreateServiceAccount(
+	ctx context.Context,
+	req *ssov1.CreateServiceAccountRequest,
+) (*ssov1.CreateServiceAccountResponse, error) {
+
+	if err := validations.ValidateServiceAccountOwner(req.GetAppId(), req.GetOrganizationId()); err != nil {
+		return nil, err
+	}
+
+	if err := validations.ValidateServiceAccountName(req.GetName(), validate); err != nil {
+		return nil, err
+	}
+
+	actorID, err := s.authorize(ctx, int(req.GetAppId()), req.GetOrganizationId())
+	if err != nil {
+		return nil, err
+	}
+
+	sa, err := s.accounts.Create(
+		ctx, actorID, int(req.GetAppId()), req.GetOrganizationId(), req.GetName(), req.GetDescription(),
+	)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.CreateServiceAccountResponse{
+		ServiceAccount: toServiceAccount(sa),
+	}, nil
+}
+
+func (s *serverAPI) GetServiceAccount(
+	ctx context.Context,
+	req *ssov1.GetServiceAccountRequest,
+) (*ssov1.GetServiceAccountResponse, error) {
+
+	sa, err := s.authorizedAccount(ctx, req.GetServiceAccountId())
+	if err != nil {
+		return nil, err
+	}
+
+	return &ssov1.GetServiceAccountResponse{
+		ServiceAccount: toServiceAccount(sa),
+	}, nil
+}
+
+func (s *serverAPI) ListServiceAccounts(
+	ctx context.Context,
+	req *ssov1.ListServiceAccountsRequest,
+) (*ssov1.ListServiceAccountsResponse, error) {
+
+	if err := validations.ValidateServiceAccountOwner(req.GetAppId(), req.GetOrganizationId()); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.authorize(ctx, int(req.GetAppId()), req.GetOrganizationId()); err != nil {
+		return nil, err
+	}
+
+	accounts, err := s.accounts.ServiceAccounts(ctx, int(req.GetAppId()), req.GetOrganizationId())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp := &ssov1.ListServiceAccountsResponse{
+		ServiceAccounts: make([]*ssov1.ServiceAccount, 0, len(accounts)),
+	}
+	for _, sa := range accounts {
+		resp.ServiceAccounts = append(resp.ServiceAccounts, toServiceAccount(sa))
+	}
+
+	return resp, nil
+}
+
+func (s *serverAPI) DisableServiceAccount(
+	ctx context.Context,
+	req *ssov1.DisableServiceAccountRequest,
+) (*ssov1.DisableServiceAccountResponse, error) {
+
+	if _, err := s.authorizedAccount(ctx, req.GetServiceAccountId()); err != nil {
+		return nil, err
+	}
+
+	if err := s.accounts.SetDisabled(ctx, req.GetServiceAccountId(), true); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.DisableServiceAccountResponse{}, nil
+}
+
+func (s *serverAPI) EnableServiceAccount(
+	ctx context.Context,
+	req *ssov1.EnableServiceAccountRequest,
+) (*ssov1.EnableServiceAccountResponse, error) {
+
+	if _, err := s.authorizedAccount(ctx, req.GetServiceAccountId()); err != nil {
+		return nil, err
+	}
+
+	if err := s.accounts.SetDisabled(ctx, req.GetServiceAccountId(), false); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.EnableServiceAccountResponse{}, nil
+}
+
+func (s *serverAPI) DeleteServiceAccount(
+	ctx context.Context,
+	req *ssov1.DeleteServiceAccountRequest,
+) (*ssov1.DeleteServiceAccountResponse, error) {
+
+	if _, err := s.authorizedAccount(ctx, req.GetServiceAccountId()); err != nil {
+		return nil, err
+	}
+
+	if err := s.accounts.Delete(ctx, req.GetServiceAccountId()); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.DeleteServiceAccountResponse{}, nil
+}
+
+func (s *serverAPI) AddServiceAccountKey(
+	ctx context.Context,
+	req *ssov1.AddServiceAccountKeyRequest,
+) (*ssov1.AddServiceAccountKeyResponse, error) {
+
+	if err := validate.Var(req.GetPublicKey(), "required"); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "public_key is required")
+	}
+
+	if _, err := s.authorizedAccount(ctx, req.GetServiceAccountId()); err != nil {
+		return nil, err
+	}
+
+	key, err := s.accounts.AddKey(
+		ctx, req.GetServiceAccountId(), req.GetKid(), req.GetPublicKey(), unixTime(req.GetExpiresAt()),
+	)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.AddServiceAccountKeyResponse{
+		Key: toKey(key),
+	}, nil
+}
+
+func (s *serverAPI) ListServiceAccountKeys(
+	ctx context.Context,
+	req *ssov1.ListServiceAccountKeysRequest,
+) (*ssov1.ListServiceAccountKeysResponse, error) {
+
+	if _, err := s.authorizedAccount(ctx, req.GetServiceAccountId()); err != nil {
+		return nil, err
+	}
+
+	keys, err := s.accounts.Keys(ctx, req.GetServiceAccountId())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp := &ssov1.ListServiceAccountKeysResponse{
+		Keys: make([]*ssov1.ServiceAccountKey, 0, len(keys)),
+	}
+	for _, key := range keys {
+		resp.Keys = append(resp.Keys, toKey(key))
+	}
+
+	return resp, nil
+}
+
+func (s *serverAPI) RemoveServiceAccountKey(
+	ctx context.Context,
+	req *ssov1.RemoveServiceAccountKeyRequest,
+) (*ssov1.RemoveServiceAccountKeyResponse, error) {
+
+	if err := validate.Var(req.GetKeyId(), "required"); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "key_id is required")
+	}
+
+	if _, err := s.authorizedAccount(ctx, req.GetServiceAccountId()); err != nil {
+		return nil, err
+	}
+
+	if err := s.accounts.RemoveKey(ctx, req.GetServiceAccountId(), req.GetKeyId()); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.RemoveServiceAccountKeyResponse{}, nil
+}
+
+func (s *serverAPI) CreateServiceAccountAPIKey(
+	ctx context.Context,
+	req *ssov1.CreateServiceAccountAPIKeyRequest,
+) (*ssov1.CreateServiceAccountAPIKeyResponse, error) {
+
+	if err := validations.ValidateAPIKeyName(req.GetName(), validate); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.authorizedAccount(ctx, req.GetServiceAccountId()); err != nil {
+		return nil, err
+	}
+
+	key, apiKey, err := s.accounts.CreateAPIKey(
+		ctx,
+		req.GetServiceAccountId(),
+		int(req.GetAppId()),
+		req.GetName(),
+		req.GetScopes(),
+		unixTime(req.GetExpiresAt()),
+	)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.CreateServiceAccountAPIKeyResponse{
+		ApiKey: apikeysgrpc.ToAPIKey(apiKey),
+		Key:    key,
+	}, nil
+}
+
+func (s *serverAPI) ServiceAccountToken(
+	ctx context.Context,
+	req *ssov1.ServiceAccountTokenRequest,
+) (*ssov1.ServiceAccountTokenResponse, error) {
+
+	if err := validate.Var(req.GetAssertion(), "required"); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "assertion is required")
+	}
+
+	token, err := s.accounts.Token(ctx, req.GetAssertion(), int(req.GetAppId()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ssov1.ServiceAccountTokenResponse{
+		Token: token,
+	}, nil
+}
+
+// authorize checks that caller manages service accounts of the app, if
+// appID is not 0, or of the organization, and returns user ID of the caller.
+func (s *serverAPI) authorize(ctx context.Context, appID int, orgID int64) (int64, error) {
+	if appID != 0 {
+		if err := interceptors.RequireAppAdmin(ctx, appID, s.admins); err != nil {
+			return 0, err
+		}
+
+		claims, _ := interceptors.ClaimsFromContext(ctx)
+
+		return claims.UserID, nil
+	}
+
+	claims, err := interceptors.RequireClaims(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := s.orgs.CheckManager(ctx, claims.UserID, orgID); err != nil {
+		return 0, toStatus(err)
+	}
+
+	return claims.UserID, nil
+}
+
+// authorizedAccount returns service account if caller manages it.
+func (s *serverAPI) authorizedAccount(ctx context.Context, id int64) (models.ServiceAccount, error) {
+	if err := validations.ValidateServiceAccountId(id, validate); err != nil {
+		return models.ServiceAccount{}, err
+	}
+
+	if _, err := interceptors.RequireClaims(ctx); err != nil {
+		return models.ServiceAccount{}, err
+	}
+
+	sa, err := s.accounts.ServiceAccount(ctx, id)
+	if err != nil {
+		return models.ServiceAccount{}, toStatus(err)
+	}
+
+	if _, err := s.authorize(ctx, sa.AppID, sa.OrganizationID); err != nil {
+		return models.ServiceAccount{}, err
+	}
+
+	return sa, nil
+}
+
+func toStatus(err error) error {
+	switch {
+	case errors.Is(err, serviceaccounts.ErrAccountNotFound):
+		return status.Error(codes.NotFound, "service account not found")
+	case errors.Is(err, serviceaccounts.ErrKeyNotFound):
+		return status.Error(codes.NotFound, "service account key not found")
+	case errors.Is(err, serviceaccounts.ErrAppNotFound), errors.Is(err, auth.ErrInvalidAppID):
+		return status.Error(codes.NotFound, "app not found")
+	case errors.Is(err, serviceaccounts.ErrOrgNotFound), errors.Is(err, organizations.ErrOrgNotFound):
+		return status.Error(codes.NotFound, "organization not found")
+	case errors.Is(err, serviceaccounts.ErrAccountExists):
+		return status.Error(codes.AlreadyExists, "service account already exists")
+	case errors.Is(err, serviceaccounts.ErrKeyExists):
+		return status.Error(codes.AlreadyExists, "service account key already exists")
+	case errors.Is(err, apikeys.ErrKeyExists):
+		return status.Error(codes.AlreadyExists, "api key with this name already exists")
+	case errors.Is(err, serviceaccounts.ErrInvalidOwner):
+		return status.Error(codes.InvalidArgument, "either app_id or organization_id is required")
+	case errors.Is(err, serviceaccounts.ErrInvalidPublicKey):
+		return status.Error(codes.InvalidArgument, "public_key must be a PEM encoded RSA or EC public key")
+	case errors.Is(err, serviceaccounts.ErrInvalidExpiry), errors.Is(err, apikeys.ErrInvalidExpiry):
+		return status.Error(codes.InvalidArgument, "expires_at must be in the future")
+	case errors.Is(err, apikeys.ErrInvalidScope):
+		return status.Error(codes.InvalidArgument, "scopes must not be empty")
+	case errors.Is(err, serviceaccounts.ErrAppRequired):
+		return status.Error(codes.InvalidArgument, "app_id is required for service account of organization")
+	case errors.Is(err, serviceaccounts.ErrAppNotAllowed), errors.Is(err, auth.ErrAccountAppNotAllowed):
+		return status.Error(codes.PermissionDenied, "service account has no access to the app")
+	case errors.Is(err, serviceaccounts.ErrInvalidAssertion), errors.Is(err, auth.ErrUserNotFound):
+		return status.Error(codes.Unauthenticated, "invalid assertion")
+	case errors.Is(err, auth.ErrAccountDisabled):
+		return status.Error(codes.FailedPrecondition, "service account is disabled")
+	case errors.Is(err, auth.ErrAppDisabled):
+		return status.Error(codes.FailedPrecondition, "app is disabled")
+	case errors.Is(err, auth.ErrGrantNotAllowed):
+		return status.Error(codes.PermissionDenied, "jwt bearer grant is not allowed for the app")
+	case errors.Is(err, organizations.ErrForbidden):
+		return status.Error(codes.PermissionDenied, "not enough rights in organization")
+	}
+
+	return status.Error(codes.Internal, "internal error")
+}
+
+func toServiceAccount(sa models.ServiceAccount) *ssov1.ServiceAccount {
+	return &ssov1.ServiceAccount{
+		Id:             sa.ID,
+		AppId:          int32(sa.AppID),
+		OrganizationId: sa.OrganizationID,
+		Name:           sa.Name,
+		Description:    sa.Description,
+		Disabled:       sa.Disabled,
+		CreatedBy:      sa.CreatedBy,
+		CreatedAt:      sa.CreatedAt.Unix(),
+	}
+}
+
+func toKey(key models.ServiceAccountKey) *ssov1.ServiceAccountKey {
+	var expiresAt int64
+	if !key.ExpiresAt.IsZero() {
+		expiresAt = key.ExpiresAt.Unix()
+	}
+
+	return &ssov1.ServiceAccountKey{
+		Id:        key.ID,
+		Kid:       key.KID,
+		PublicKey: key.PublicKey,
+		ExpiresAt: expiresAt,
+		CreatedAt: key.CreatedAt.Unix(),
+	}
+}
+
+func unixTime(sec int64) time.Time {
+	if sec == 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(sec, 0)
+}
@@ -0,0 +1,182 @@
+// Package envelope encrypts sensitive values with envelope encryption:
+// every value is encrypted with its own data key, and the data key is
+// encrypted (wrapped) with a key encryption key (KEK).
+package envelope
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// prefix marks encrypted values, values without it are plaintext
+// written before encryption was enabled. Values with legacyPrefix were
+// encrypted without associated data.
+const (
+	prefix       = "enc:v2:"
+	legacyPrefix = "enc:v1:"
+)
+
+const dataKeySize = 32
+
+var (
+	ErrUnknownKEK = errors.New("unknown key encryption key")
+	ErrMalformed  = errors.New("malformed encrypted value")
+)
+
+// KEK wraps and unwraps data keys. It's implemented by FileKEK and may be
+// implemented by a KMS client.
+type KEK interface {
+	// ID identifies the KEK in encrypted values.
+	ID() string
+	Wrap(dataKey []byte) ([]byte, error)
+	Unwrap(wrapped []byte) ([]byte, error)
+}
+
+// Encryptor encrypts values with the primary KEK and decrypts values
+// encrypted with any of its KEKs.
+type Encryptor struct {
+	primary KEK
+	keks    map[string]KEK
+}
+
+// New returns Encryptor encrypting with primary KEK. Previous KEKs are
+// only used to decrypt values encrypted before primary KEK rotated.
+func New(primary KEK, previous ...KEK) *Encryptor {
+	keks := make(map[string]KEK, len(previous)+1)
+	for _, kek := range previous {
+		keks[kek.ID()] = kek
+	}
+	keks[primary.ID()] = primary
+
+	return &Encryptor{
+		primary: primary,
+		keks:    keks,
+	}
+}
+
+// Encrypt encrypts plaintext with a new data key wrapped by the primary KEK.
+// The value is bound to aad, e.g. the place it's stored in, and only
+// decrypts with the same aad.
+func (e *Encryptor) Encrypt(plaintext string, aad []byte) (string, error) {
+	const op = "envelope.Encrypt"
+
+	dataKey := make([]byte, dataKeySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	ciphertext, err := seal(dataKey, []byte(plaintext), aad)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	wrapped, err := e.primary.Wrap(dataKey)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	return prefix + strings.Join([]string{
+		e.primary.ID(),
+		base64.RawStdEncoding.EncodeToString(wrapped),
+		base64.RawStdEncoding.EncodeToString(ciphertext),
+	}, ":"), nil
+}
+
+// Decrypt decrypts value returned by Encrypt with the same aad. Plaintext
+// values are returned as is, values encrypted without associated data
+// are decrypted ignoring aad.
+func (e *Encryptor) Decrypt(value string, aad []byte) (string, error) {
+	const op = "envelope.Decrypt"
+
+	rest, ok := strings.CutPrefix(value, prefix)
+	if !ok {
+		rest, ok = strings.CutPrefix(value, legacyPrefix)
+		if !ok {
+			return value, nil
+		}
+		aad = nil
+	}
+
+	parts := strings.Split(rest, ":")
+	if len(parts) != 3 {
+		return "", fmt.Errorf("%s: %w", op, ErrMalformed)
+	}
+
+	kek, ok := e.keks[parts[0]]
+	if !ok {
+		return "", fmt.Errorf("%s: %w: %s", op, ErrUnknownKEK, parts[0])
+	}
+
+	wrapped, err := base64.RawStdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, ErrMalformed)
+	}
+
+	ciphertext, err := base64.RawStdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, ErrMalformed)
+	}
+
+	dataKey, err := kek.Unwrap(wrapped)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	plaintext, err := open(dataKey, ciphertext, aad)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	return string(plaintext), nil
+}
+
+// Stale reports whether value is plaintext, encrypted without associated
+// data or with a KEK other than the primary one and should be re-encrypted.
+func (e *Encryptor) Stale(value string) bool {
+	return !strings.HasPrefix(value, prefix+e.primary.ID()+":")
+}
+
+// seal encrypts plaintext with AES-256-GCM and prepends the nonce.
+func seal(key, plaintext, aad []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	return gcm.Seal(nonce, nonce, plaintext, aad), nil
+}
+
+// open decrypts ciphertext produced by seal with the same aad.
+func open(key, ciphertext, aad []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ciphertext) < gcm.NonceSize() {
+		return nil, ErrMalformed
+	}
+
+	nonce, ciphertext := ciphertext[:gcm.NonceSize()], ciphertext[gcm.NonceSize():]
+
+	return gcm.Open(nil, nonce, ciphertext, aad)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
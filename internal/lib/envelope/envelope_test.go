@@ -0,0 +1,107 @@
+package envelope
+
+import (
+	"bytes"
+	"encoding/base64"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+var aad = []byte("apps.secret:1")
+
+func TestEncryptor_RoundTrip(t *testing.T) {
+	e := New(NewFileKEK(bytes.Repeat([]byte{1}, KEKSize)))
+
+	encrypted, err := e.Encrypt("app-secret", aad)
+	require.NoError(t, err)
+	assert.NotContains(t, encrypted, "app-secret")
+	assert.False(t, e.Stale(encrypted))
+
+	other, err := e.Encrypt("app-secret", aad)
+	require.NoError(t, err)
+	assert.NotEqual(t, encrypted, other, "every value must get its own data key")
+
+	decrypted, err := e.Decrypt(encrypted, aad)
+	require.NoError(t, err)
+	assert.Equal(t, "app-secret", decrypted)
+}
+
+func TestEncryptor_Plaintext(t *testing.T) {
+	e := New(NewFileKEK(bytes.Repeat([]byte{1}, KEKSize)))
+
+	decrypted, err := e.Decrypt("test-secret", aad)
+	require.NoError(t, err)
+	assert.Equal(t, "test-secret", decrypted)
+	assert.True(t, e.Stale("test-secret"))
+}
+
+func TestEncryptor_KEKRotation(t *testing.T) {
+	oldKEK := NewFileKEK(bytes.Repeat([]byte{1}, KEKSize))
+	newKEK := NewFileKEK(bytes.Repeat([]byte{2}, KEKSize))
+
+	encrypted, err := New(oldKEK).Encrypt("app-secret", aad)
+	require.NoError(t, err)
+
+	_, err = New(newKEK).Decrypt(encrypted, aad)
+	assert.ErrorIs(t, err, ErrUnknownKEK)
+
+	rotated := New(newKEK, oldKEK)
+	assert.True(t, rotated.Stale(encrypted))
+
+	decrypted, err := rotated.Decrypt(encrypted, aad)
+	require.NoError(t, err)
+	assert.Equal(t, "app-secret", decrypted)
+
+	reencrypted, err := rotated.Encrypt(decrypted, aad)
+	require.NoError(t, err)
+	assert.False(t, rotated.Stale(reencrypted))
+}
+
+func TestEncryptor_Tampered(t *testing.T) {
+	e := New(NewFileKEK(bytes.Repeat([]byte{1}, KEKSize)))
+
+	encrypted, err := e.Encrypt("app-secret", aad)
+	require.NoError(t, err)
+
+	tampered := encrypted[:len(encrypted)-2] + "AA"
+	if tampered == encrypted {
+		tampered = encrypted[:len(encrypted)-2] + "BB"
+	}
+
+	_, err = e.Decrypt(tampered, aad)
+	assert.Error(t, err)
+}
+
+func TestEncryptor_AssociatedData(t *testing.T) {
+	e := New(NewFileKEK(bytes.Repeat([]byte{1}, KEKSize)))
+
+	encrypted, err := e.Encrypt("app-secret", aad)
+	require.NoError(t, err)
+
+	_, err = e.Decrypt(encrypted, []byte("apps.secret:2"))
+	assert.Error(t, err, "value moved to another row must not decrypt")
+}
+
+func TestEncryptor_Legacy(t *testing.T) {
+	kek := NewFileKEK(bytes.Repeat([]byte{1}, KEKSize))
+	e := New(kek)
+
+	dataKey := bytes.Repeat([]byte{3}, dataKeySize)
+	ciphertext, err := seal(dataKey, []byte("app-secret"), nil)
+	require.NoError(t, err)
+	wrapped, err := kek.Wrap(dataKey)
+	require.NoError(t, err)
+
+	legacy := legacyPrefix + strings.Join([]string{
+		kek.ID(),
+		base64.RawStdEncoding.EncodeToString(wrapped),
+		base64.RawStdEncoding.EncodeToString(ciphertext),
+	}, ":")
+	assert.True(t, e.Stale(legacy), "values without associated data are re-encrypted")
+
+	decrypted, err := e.Decrypt(legacy, aad)
+	require.NoError(t, err)
+	assert.Equal(t, "app-secret", decrypted)
+}
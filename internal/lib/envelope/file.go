@@ -0,0 +1,82 @@
+package envelope
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// KEKSize is the size of the key in KEK files.
+const KEKSize = 32
+
+// FileKEK is a KEK stored in a local file as base64 encoded 32 random bytes.
+type FileKEK struct {
+	id  string
+	key []byte
+}
+
+// LoadFileKEK reads KEK from file.
+func LoadFileKEK(path string) (*FileKEK, error) {
+	const op = "envelope.LoadFileKEK"
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s: %w", op, path, err)
+	}
+
+	if len(key) != KEKSize {
+		return nil, fmt.Errorf("%s: %s: key must be %d bytes, got %d", op, path, KEKSize, len(key))
+	}
+
+	return NewFileKEK(key), nil
+}
+
+// NewFileKEK returns KEK with given key. ID of the KEK is derived from the key.
+func NewFileKEK(key []byte) *FileKEK {
+	sum := sha256.Sum256(key)
+
+	return &FileKEK{
+		id:  hex.EncodeToString(sum[:4]),
+		key: key,
+	}
+}
+
+func (k *FileKEK) ID() string {
+	return k.id
+}
+
+func (k *FileKEK) Wrap(dataKey []byte) ([]byte, error) {
+	return seal(k.key, dataKey, nil)
+}
+
+func (k *FileKEK) Unwrap(wrapped []byte) ([]byte, error) {
+	return open(k.key, wrapped, nil)
+}
+
+// LoadFiles returns Encryptor with the primary KEK and previous KEKs read
+// from files.
+func LoadFiles(primaryPath string, previousPaths ...string) (*Encryptor, error) {
+	primary, err := LoadFileKEK(primaryPath)
+	if err != nil {
+		return nil, err
+	}
+
+	previous := make([]KEK, 0, len(previousPaths))
+	for _, path := range previousPaths {
+		kek, err := LoadFileKEK(path)
+		if err != nil {
+			return nil, err
+		}
+		previous = append(previous, kek)
+	}
+
+	return New(primary, previous...), nil
+}
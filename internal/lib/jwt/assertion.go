@@ -0,0 +1,85 @@
+package jwt
+
+import (
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt"
+	"time"
+)
+
+var ErrInvalidAssertion = errors.New("invalid assertion")
+
+// ParseAssertion verifies JWT assertion a client signed with its private
+// key to authenticate (RFC 7523) and returns its subject.
+//
+// Assertion must be signed with RS256 or ES256, name the same client in
+// iss and sub, be addressed to audience and expire within maxTTL. key is
+// called with the subject and the kid header and returns PEM encoded
+// public key of the client.
+func ParseAssertion(
+	assertion string,
+	audience string,
+	maxTTL time.Duration,
+	key func(subject, kid string) (string, error),
+) (string, error) {
+	parsed, err := jwt.Parse(assertion, func(token *jwt.Token) (interface{}, error) {
+		claims, ok := token.Claims.(jwt.MapClaims)
+		if !ok {
+			return nil, ErrInvalidAssertion
+		}
+
+		sub, _ := claims["sub"].(string)
+		iss, _ := claims["iss"].(string)
+		if sub == "" || iss != sub {
+			return nil, ErrInvalidAssertion
+		}
+
+		kid, _ := token.Header["kid"].(string)
+
+		pem, err := key(sub, kid)
+		if err != nil {
+			return nil, err
+		}
+
+		switch token.Method {
+		case jwt.SigningMethodRS256:
+			return jwt.ParseRSAPublicKeyFromPEM([]byte(pem))
+		case jwt.SigningMethodES256:
+			return jwt.ParseECPublicKeyFromPEM([]byte(pem))
+		}
+
+		return nil, fmt.Errorf("unexpected signing method %v", token.Header["alg"])
+	})
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrInvalidAssertion, err)
+	}
+
+	claims, ok := parsed.Claims.(jwt.MapClaims)
+	if !ok || !parsed.Valid {
+		return "", ErrInvalidAssertion
+	}
+
+	if !claims.VerifyAudience(audience, true) {
+		return "", fmt.Errorf("%w: unexpected audience", ErrInvalidAssertion)
+	}
+
+	exp, ok := claims["exp"].(float64)
+	if !ok || time.Unix(int64(exp), 0).After(time.Now().Add(maxTTL)) {
+		return "", fmt.Errorf("%w: assertion must expire within %s", ErrInvalidAssertion, maxTTL)
+	}
+
+	return claims["sub"].(string), nil
+}
+
+// ValidatePublicKey checks that key is a PEM encoded RSA or EC public key.
+func ValidatePublicKey(pem string) error {
+	if _, err := jwt.ParseRSAPublicKeyFromPEM([]byte(pem)); err == nil {
+		return nil
+	}
+
+	if _, err := jwt.ParseECPublicKeyFromPEM([]byte(pem)); err == nil {
+		return nil
+	}
+
+	return errors.New("key must be a PEM encoded RSA or EC public key")
+}
@@ -0,0 +1,60 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"github.com/golang-jwt/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestParseAssertion(t *testing.T) {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	der, err := x509.MarshalPKIXPublicKey(&priv.PublicKey)
+	require.NoError(t, err)
+	pub := string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+	require.NoError(t, ValidatePublicKey(pub))
+
+	key := func(subject, kid string) (string, error) {
+		if subject != "7" || kid != "k1" {
+			return "", ErrInvalidAssertion
+		}
+
+		return pub, nil
+	}
+
+	sign := func(claims jwt.MapClaims) string {
+		token := jwt.NewWithClaims(jwt.SigningMethodES256, claims)
+		token.Header["kid"] = "k1"
+
+		s, err := token.SignedString(priv)
+		require.NoError(t, err)
+
+		return s
+	}
+
+	exp := time.Now().Add(time.Minute).Unix()
+
+	sub, err := ParseAssertion(sign(jwt.MapClaims{"iss": "7", "sub": "7", "aud": "sso", "exp": exp}), "sso", 5*time.Minute, key)
+	require.NoError(t, err)
+	assert.Equal(t, "7", sub)
+
+	tests := map[string]jwt.MapClaims{
+		"wrong audience":  {"iss": "7", "sub": "7", "aud": "other", "exp": exp},
+		"issuer mismatch": {"iss": "8", "sub": "7", "aud": "sso", "exp": exp},
+		"no expiry":       {"iss": "7", "sub": "7", "aud": "sso"},
+		"too long ttl":    {"iss": "7", "sub": "7", "aud": "sso", "exp": time.Now().Add(time.Hour).Unix()},
+		"expired":         {"iss": "7", "sub": "7", "aud": "sso", "exp": time.Now().Add(-time.Minute).Unix()},
+	}
+	for name, claims := range tests {
+		_, err := ParseAssertion(sign(claims), "sso", 5*time.Minute, key)
+		assert.ErrorIs(t, err, ErrInvalidAssertion, name)
+	}
+}
@@ -2,18 +2,140 @@ package jwt
 
 import (
 	"SSO/internal/domain/models"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"github.com/golang-jwt/jwt"
+	"slices"
+	"strconv"
 	"time"
 )
 
-func NewToken(user models.User, app models.App, duration time.Duration) (string, error) {
-	token := jwt.New(jwt.SigningMethodHS256)
+var ErrInvalidToken = errors.New("invalid token")
 
-	claims := token.Claims.(jwt.MapClaims)
-	claims["uid"] = user.ID
-	claims["email"] = user.Email
-	claims["exp"] = time.Now().Add(duration).Unix()
-	claims["app_id"] = app.ID
+// ErrInvalidSignature is returned for tokens not signed with the secret
+// ParseToken was given. It's also ErrInvalidToken.
+var ErrInvalidSignature = fmt.Errorf("%w: signature is invalid", ErrInvalidToken)
+
+// subjectServiceAccount is the sub_type claim of tokens issued to service accounts.
+const subjectServiceAccount = "service_account"
+
+// Claims are the claims of a verified token issued by NewToken.
+type Claims struct {
+	UserID      int64
+	Email       string
+	AppID       int
+	Roles       []string
+	Permissions []string
+	Groups      []string
+	OrgID       int64
+	OrgRole     string
+	// Scopes restrict what the token may be used for, empty scopes don't restrict it.
+	Scopes []string
+	// KeyID is ID of the API key the token was issued for, 0 for tokens issued on login.
+	KeyID int64
+	// ServiceAccount is set for tokens issued to service accounts.
+	ServiceAccount bool
+	// Actor is the admin acting as the user, set for impersonation tokens.
+	Actor Actor
+	// IssuedAt is when the token was issued, zero for tokens issued before it was set.
+	IssuedAt  time.Time
+	ExpiresAt time.Time
+}
+
+// Actor identifies the admin who impersonates the token subject and the
+// impersonation session the token belongs to.
+type Actor struct {
+	UserID    int64
+	SessionID string
+}
+
+// Impersonated reports whether the token was issued to an admin acting as the user.
+func (c Claims) Impersonated() bool {
+	return c.Actor.UserID != 0
+}
+
+// HasRole reports whether the token carries the role.
+func (c Claims) HasRole(role string) bool {
+	return slices.Contains(c.Roles, role)
+}
+
+// HasPermission reports whether the token carries the permission.
+func (c Claims) HasPermission(permission string) bool {
+	return slices.Contains(c.Permissions, permission)
+}
+
+// AllowsScope reports whether the token may be used for scope.
+func (c Claims) AllowsScope(scope string) bool {
+	return len(c.Scopes) == 0 || slices.Contains(c.Scopes, scope)
+}
+
+// Option adds optional claims to the token.
+type Option func(claims jwt.MapClaims)
+
+// WithAccess adds roles and permissions the user holds in the app.
+func WithAccess(roles, permissions []string) Option {
+	return func(claims jwt.MapClaims) {
+		claims["roles"] = roles
+		claims["permissions"] = permissions
+	}
+}
+
+// WithGroups adds names of the groups the user belongs to in the app.
+func WithGroups(groups []string) Option {
+	return func(claims jwt.MapClaims) {
+		claims["groups"] = groups
+	}
+}
+
+// WithOrganization adds organization the user logged in within and its role there.
+func WithOrganization(orgID int64, role string) Option {
+	return func(claims jwt.MapClaims) {
+		claims["org_id"] = orgID
+		claims["org_role"] = role
+	}
+}
+
+// WithScopes restricts what the token may be used for.
+func WithScopes(scopes []string) Option {
+	return func(claims jwt.MapClaims) {
+		if len(scopes) > 0 {
+			claims["scopes"] = scopes
+		}
+	}
+}
+
+// WithAPIKey adds ID and scopes of the API key the token is issued for.
+func WithAPIKey(keyID int64, scopes []string) Option {
+	return func(claims jwt.MapClaims) {
+		claims["key_id"] = keyID
+		WithScopes(scopes)(claims)
+	}
+}
+
+// WithActor adds the act claim (RFC 8693) identifying the admin who
+// impersonates the user and the impersonation session.
+func WithActor(actorID int64, sessionID string) Option {
+	return func(claims jwt.MapClaims) {
+		claims["act"] = map[string]interface{}{
+			"sub": strconv.FormatInt(actorID, 10),
+			"sid": sessionID,
+		}
+	}
+}
+
+// WithServiceAccount marks the token as issued to a service account.
+func WithServiceAccount() Option {
+	return func(claims jwt.MapClaims) {
+		claims["sub_type"] = subjectServiceAccount
+	}
+}
+
+func NewToken(user models.User, app models.App, duration time.Duration, opts ...Option) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, newMapClaims(user, app, duration, opts...))
+	if app.SecretKID != "" {
+		token.Header["kid"] = app.SecretKID
+	}
 
 	tokenString, err := token.SignedString([]byte(app.Secret))
 	if err != nil {
@@ -22,3 +144,149 @@ func NewToken(user models.User, app models.App, duration time.Duration) (string,
 
 	return tokenString, nil
 }
+
+// NewClaims returns claims a token issued by NewToken with the same
+// arguments carries, for requests authenticated without a token.
+func NewClaims(user models.User, app models.App, duration time.Duration, opts ...Option) (Claims, error) {
+	// Round trip through JSON for claims to have the types of parsed ones.
+	b, err := json.Marshal(newMapClaims(user, app, duration, opts...))
+	if err != nil {
+		return Claims{}, err
+	}
+
+	var mapClaims jwt.MapClaims
+	if err := json.Unmarshal(b, &mapClaims); err != nil {
+		return Claims{}, err
+	}
+
+	return claimsFromMap(mapClaims), nil
+}
+
+func newMapClaims(user models.User, app models.App, duration time.Duration, opts ...Option) jwt.MapClaims {
+	now := time.Now()
+
+	claims := jwt.MapClaims{
+		"uid":    user.ID,
+		"email":  user.Email,
+		"iat":    now.Unix(),
+		"exp":    now.Add(duration).Unix(),
+		"app_id": app.ID,
+	}
+
+	for _, opt := range opts {
+		opt(claims)
+	}
+
+	return claims
+}
+
+// ParseToken verifies token with the secret of the app it was issued for
+// and returns its claims. secret is called with the app_id claim and the kid
+// header of the token, kid is empty for tokens signed before secret rotation.
+func ParseToken(tokenString string, secret func(appID int, kid string) (string, error)) (Claims, error) {
+	parsed, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method %v", token.Header["alg"])
+		}
+
+		claims, ok := token.Claims.(jwt.MapClaims)
+		if !ok {
+			return nil, ErrInvalidToken
+		}
+
+		appID, ok := claims["app_id"].(float64)
+		if !ok {
+			return nil, ErrInvalidToken
+		}
+
+		kid, _ := token.Header["kid"].(string)
+
+		s, err := secret(int(appID), kid)
+		if err != nil {
+			return nil, err
+		}
+
+		return []byte(s), nil
+	})
+	if err != nil {
+		var ve *jwt.ValidationError
+		if errors.As(err, &ve) && ve.Errors&jwt.ValidationErrorSignatureInvalid != 0 {
+			return Claims{}, fmt.Errorf("%w: %w", ErrInvalidSignature, err)
+		}
+
+		return Claims{}, fmt.Errorf("%w: %w", ErrInvalidToken, err)
+	}
+
+	mapClaims, ok := parsed.Claims.(jwt.MapClaims)
+	if !ok || !parsed.Valid {
+		return Claims{}, ErrInvalidToken
+	}
+
+	return claimsFromMap(mapClaims), nil
+}
+
+func claimsFromMap(mapClaims jwt.MapClaims) Claims {
+	uid, _ := mapClaims["uid"].(float64)
+	appID, _ := mapClaims["app_id"].(float64)
+	exp, _ := mapClaims["exp"].(float64)
+	iat, _ := mapClaims["iat"].(float64)
+	email, _ := mapClaims["email"].(string)
+	orgID, _ := mapClaims["org_id"].(float64)
+	orgRole, _ := mapClaims["org_role"].(string)
+	keyID, _ := mapClaims["key_id"].(float64)
+	subType, _ := mapClaims["sub_type"].(string)
+	act, _ := mapClaims["act"].(map[string]interface{})
+
+	return Claims{
+		UserID:         int64(uid),
+		Email:          email,
+		AppID:          int(appID),
+		Roles:          stringSlice(mapClaims["roles"]),
+		Permissions:    stringSlice(mapClaims["permissions"]),
+		Groups:         stringSlice(mapClaims["groups"]),
+		OrgID:          int64(orgID),
+		OrgRole:        orgRole,
+		Scopes:         stringSlice(mapClaims["scopes"]),
+		KeyID:          int64(keyID),
+		ServiceAccount: subType == subjectServiceAccount,
+		Actor:          actorFromMap(act),
+		IssuedAt:       unixOrZero(iat),
+		ExpiresAt:      time.Unix(int64(exp), 0),
+	}
+}
+
+func unixOrZero(sec float64) time.Time {
+	if sec == 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(int64(sec), 0)
+}
+
+func actorFromMap(act map[string]interface{}) Actor {
+	sub, _ := act["sub"].(string)
+	sid, _ := act["sid"].(string)
+
+	userID, err := strconv.ParseInt(sub, 10, 64)
+	if err != nil {
+		return Actor{}
+	}
+
+	return Actor{UserID: userID, SessionID: sid}
+}
+
+func stringSlice(v interface{}) []string {
+	items, ok := v.([]interface{})
+	if !ok {
+		return nil
+	}
+
+	res := make([]string, 0, len(items))
+	for _, item := range items {
+		if s, ok := item.(string); ok {
+			res = append(res, s)
+		}
+	}
+
+	return res
+}
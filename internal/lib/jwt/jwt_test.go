@@ -0,0 +1,112 @@
+package jwt
+
+import (
+	"SSO/internal/domain/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestParseToken_PicksSecretByKid(t *testing.T) {
+	user := models.User{ID: 42, Email: "user@example.com"}
+	old := models.App{ID: 1, Secret: "old-secret", SecretKID: "old"}
+	current := models.App{ID: 1, Secret: "new-secret", SecretKID: "new"}
+
+	secrets := map[string]string{
+		old.SecretKID:     old.Secret,
+		current.SecretKID: current.Secret,
+	}
+	secret := func(appID int, kid string) (string, error) {
+		assert.Equal(t, 1, appID)
+
+		s, ok := secrets[kid]
+		if !ok {
+			return "", ErrInvalidToken
+		}
+
+		return s, nil
+	}
+
+	for _, app := range []models.App{old, current} {
+		token, err := NewToken(user, app, time.Minute)
+		require.NoError(t, err)
+
+		claims, err := ParseToken(token, secret)
+		require.NoError(t, err, app.SecretKID)
+		assert.Equal(t, user.ID, claims.UserID)
+		assert.Equal(t, user.Email, claims.Email)
+	}
+
+	delete(secrets, old.SecretKID)
+
+	token, err := NewToken(user, old, time.Minute)
+	require.NoError(t, err)
+
+	_, err = ParseToken(token, secret)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestNewClaims_MatchesParsedToken(t *testing.T) {
+	user := models.User{ID: 42, Email: "user@example.com"}
+	app := models.App{ID: 3, Secret: "secret"}
+	opts := []Option{
+		WithAccess([]string{"editor"}, []string{"posts.write"}),
+		WithAPIKey(7, []string{"posts.write"}),
+	}
+
+	token, err := NewToken(user, app, time.Minute, opts...)
+	require.NoError(t, err)
+
+	parsed, err := ParseToken(token, func(int, string) (string, error) { return app.Secret, nil })
+	require.NoError(t, err)
+
+	claims, err := NewClaims(user, app, time.Minute, opts...)
+	require.NoError(t, err)
+
+	assert.WithinDuration(t, parsed.ExpiresAt, claims.ExpiresAt, time.Second)
+	assert.WithinDuration(t, time.Now(), parsed.IssuedAt, time.Second)
+	assert.WithinDuration(t, parsed.IssuedAt, claims.IssuedAt, time.Second)
+	claims.ExpiresAt, claims.IssuedAt = parsed.ExpiresAt, parsed.IssuedAt
+	assert.Equal(t, parsed, claims)
+	assert.True(t, claims.AllowsScope("posts.write"))
+	assert.False(t, claims.AllowsScope("posts.delete"))
+}
+
+func TestParseToken_Actor(t *testing.T) {
+	user := models.User{ID: 42, Email: "user@example.com"}
+	app := models.App{ID: 3, Secret: "secret"}
+
+	token, err := NewToken(user, app, time.Minute, WithActor(7, "session"), WithScopes([]string{"posts.read"}))
+	require.NoError(t, err)
+
+	claims, err := ParseToken(token, func(int, string) (string, error) { return app.Secret, nil })
+	require.NoError(t, err)
+
+	assert.True(t, claims.Impersonated())
+	assert.Equal(t, Actor{UserID: 7, SessionID: "session"}, claims.Actor)
+	assert.Equal(t, []string{"posts.read"}, claims.Scopes)
+	assert.Zero(t, claims.KeyID)
+
+	token, err = NewToken(user, app, time.Minute)
+	require.NoError(t, err)
+
+	claims, err = ParseToken(token, func(int, string) (string, error) { return app.Secret, nil })
+	require.NoError(t, err)
+
+	assert.False(t, claims.Impersonated())
+}
+
+func TestParseToken_InvalidSignature(t *testing.T) {
+	app := models.App{ID: 1, Secret: "secret"}
+
+	token, err := NewToken(models.User{ID: 42}, app, time.Minute)
+	require.NoError(t, err)
+
+	_, err = ParseToken(token, func(int, string) (string, error) { return "other secret", nil })
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+
+	_, err = ParseToken(token, func(int, string) (string, error) { return "", ErrInvalidToken })
+	assert.NotErrorIs(t, err, ErrInvalidSignature, "unknown secret is not a signature mismatch")
+}
@@ -0,0 +1,258 @@
+package ldap
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Classes of BER tags.
+const (
+	classUniversal   byte = 0x00
+	classApplication byte = 0x40
+	classContext     byte = 0x80
+)
+
+// Universal tags.
+const (
+	tagBoolean     byte = 0x01
+	tagInteger     byte = 0x02
+	tagOctetString byte = 0x04
+	tagEnumerated  byte = 0x0a
+	tagSequence    byte = 0x10
+	tagSet         byte = 0x11
+)
+
+// maxPacketSize limits packets read from the connection.
+const maxPacketSize = 1 << 20
+
+var ErrMalformedPacket = errors.New("malformed BER packet")
+
+// packet is a BER encoded element, either primitive with value or
+// constructed of children.
+type packet struct {
+	class       byte
+	constructed bool
+	tag         byte
+	value       []byte
+	children    []*packet
+}
+
+func constructed(class, tag byte, children ...*packet) *packet {
+	return &packet{class: class, constructed: true, tag: tag, children: children}
+}
+
+func primitive(class, tag byte, value []byte) *packet {
+	return &packet{class: class, tag: tag, value: value}
+}
+
+func sequence(children ...*packet) *packet {
+	return constructed(classUniversal, tagSequence, children...)
+}
+
+func octetString(s string) *packet {
+	return primitive(classUniversal, tagOctetString, []byte(s))
+}
+
+func integer(n int64) *packet {
+	return primitive(classUniversal, tagInteger, encodeInt(n))
+}
+
+func enumerated(n int64) *packet {
+	return primitive(classUniversal, tagEnumerated, encodeInt(n))
+}
+
+func boolean(b bool) *packet {
+	if b {
+		return primitive(classUniversal, tagBoolean, []byte{0xff})
+	}
+
+	return primitive(classUniversal, tagBoolean, []byte{0x00})
+}
+
+// is reports whether the packet has the class and tag.
+func (p *packet) is(class, tag byte) bool {
+	return p.class == class && p.tag == tag
+}
+
+// int decodes value of integer or enumerated packet.
+func (p *packet) int() (int64, error) {
+	if p.constructed || len(p.value) == 0 || len(p.value) > 8 {
+		return 0, ErrMalformedPacket
+	}
+
+	n := int64(int8(p.value[0]))
+	for _, b := range p.value[1:] {
+		n = n<<8 | int64(b)
+	}
+
+	return n, nil
+}
+
+// str decodes value of octet string packet.
+func (p *packet) str() (string, error) {
+	if p.constructed {
+		return "", ErrMalformedPacket
+	}
+
+	return string(p.value), nil
+}
+
+// encode returns BER encoding of the packet with definite lengths.
+func (p *packet) encode() []byte {
+	content := p.value
+	if p.constructed {
+		content = nil
+		for _, c := range p.children {
+			content = append(content, c.encode()...)
+		}
+	}
+
+	id := p.class | p.tag
+	if p.constructed {
+		id |= 0x20
+	}
+
+	return append(append([]byte{id}, encodeLength(len(content))...), content...)
+}
+
+func encodeInt(n int64) []byte {
+	b := []byte{byte(n)}
+	for (n > 127 || n < -128) && len(b) < 8 {
+		n >>= 8
+		b = append([]byte{byte(n)}, b...)
+	}
+
+	return b
+}
+
+func encodeLength(n int) []byte {
+	if n < 0x80 {
+		return []byte{byte(n)}
+	}
+
+	var b []byte
+	for ; n > 0; n >>= 8 {
+		b = append([]byte{byte(n)}, b...)
+	}
+
+	return append([]byte{0x80 | byte(len(b))}, b...)
+}
+
+// readPacket reads the next packet from r. Only low tag numbers and
+// definite lengths are supported, which is all LDAP uses.
+func readPacket(r *bufio.Reader) (*packet, error) {
+	id, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+
+	length, err := readLength(r)
+	if err != nil {
+		return nil, err
+	}
+
+	content := make([]byte, length)
+	if _, err := io.ReadFull(r, content); err != nil {
+		return nil, err
+	}
+
+	return decode(id, content)
+}
+
+func readLength(r *bufio.Reader) (int, error) {
+	b, err := r.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+
+	if b < 0x80 {
+		return int(b), nil
+	}
+
+	size := int(b & 0x7f)
+	if size == 0 || size > 4 {
+		return 0, fmt.Errorf("%w: unsupported length", ErrMalformedPacket)
+	}
+
+	length := 0
+	for i := 0; i < size; i++ {
+		b, err := r.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		length = length<<8 | int(b)
+	}
+
+	if length > maxPacketSize {
+		return 0, fmt.Errorf("%w: packet is too large", ErrMalformedPacket)
+	}
+
+	return length, nil
+}
+
+// parse decodes the packets content consists of.
+func parse(content []byte) ([]*packet, error) {
+	var packets []*packet
+	for len(content) > 0 {
+		if len(content) < 2 {
+			return nil, ErrMalformedPacket
+		}
+
+		id := content[0]
+		length, n := int(content[1]), 2
+		if length >= 0x80 {
+			size := length & 0x7f
+			if size == 0 || size > 4 || len(content) < 2+size {
+				return nil, ErrMalformedPacket
+			}
+
+			length = 0
+			for _, b := range content[2 : 2+size] {
+				length = length<<8 | int(b)
+			}
+			n += size
+		}
+
+		if length > len(content)-n {
+			return nil, ErrMalformedPacket
+		}
+
+		p, err := decode(id, content[n:n+length])
+		if err != nil {
+			return nil, err
+		}
+
+		packets = append(packets, p)
+		content = content[n+length:]
+	}
+
+	return packets, nil
+}
+
+func decode(id byte, content []byte) (*packet, error) {
+	if id&0x1f == 0x1f {
+		return nil, fmt.Errorf("%w: unsupported tag", ErrMalformedPacket)
+	}
+
+	p := &packet{
+		class:       id & 0xc0,
+		constructed: id&0x20 != 0,
+		tag:         id & 0x1f,
+	}
+
+	if !p.constructed {
+		p.value = content
+
+		return p, nil
+	}
+
+	children, err := parse(content)
+	if err != nil {
+		return nil, err
+	}
+	p.children = children
+
+	return p, nil
+}
@@ -0,0 +1,438 @@
+// Package ldap verifies credentials of users against an LDAP directory:
+// the entry of the user is searched for with a service account and the
+// password is checked by binding as the entry.
+package ldap
+
+import (
+	"SSO/internal/domain/models"
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Operations of LDAP messages.
+const (
+	opBindRequest       byte = 0
+	opBindResponse      byte = 1
+	opUnbindRequest     byte = 2
+	opSearchRequest     byte = 3
+	opSearchResultEntry byte = 4
+	opSearchResultDone  byte = 5
+	opSearchResultRef   byte = 19
+)
+
+// Result codes of LDAP operations.
+const (
+	resultSuccess            = 0
+	resultSizeLimitExceeded  = 4
+	resultInvalidCredentials = 49
+)
+
+// Search parameters.
+const (
+	scopeWholeSubtree = 2
+	derefNever        = 0
+	// filterAnd and filterEqualityMatch are context tags of search filters.
+	filterAnd           byte = 0
+	filterEqualityMatch byte = 3
+	// authSimple is the context tag of simple bind password.
+	authSimple byte = 0
+)
+
+const defaultTimeout = 10 * time.Second
+
+var (
+	ErrUnsupportedURL  = errors.New("unsupported ldap url")
+	ErrOperationFailed = errors.New("ldap operation failed")
+	ErrAmbiguousLogin  = errors.New("login matches several entries")
+	ErrNoEmail         = errors.New("entry has no email")
+)
+
+// Config is the directory and how its entries map to users.
+type Config struct {
+	// Name identifies the directory in linked identities, it must not change.
+	Name string
+	// URL is ldap://host[:port] or ldaps://host[:port].
+	URL string
+	// BindDN and BindPassword are the service account entries are searched
+	// with. Search is anonymous if BindDN is empty.
+	BindDN       string
+	BindPassword string
+	// BaseDN is the subtree entries of users are searched in.
+	BaseDN string
+	// ObjectClass limits search to entries of the class if it's not empty.
+	ObjectClass string
+	// LoginAttribute is the attribute users log in with, e.g. "uid" or "mail".
+	LoginAttribute string
+	Attributes     Mapping
+	Timeout        time.Duration
+	// TLS configures ldaps connections, server name is set from the URL.
+	TLS *tls.Config
+}
+
+// Mapping names attributes of entries user fields are taken from. Fields
+// with empty attribute names are left empty.
+type Mapping struct {
+	Email       string
+	Username    string
+	Sex         string
+	Location    string
+	DateOfBirth string
+}
+
+// Directory verifies credentials against the LDAP directory.
+type Directory struct {
+	cfg Config
+}
+
+// New returns directory with the config.
+func New(cfg Config) *Directory {
+	if cfg.Timeout == 0 {
+		cfg.Timeout = defaultTimeout
+	}
+
+	return &Directory{cfg: cfg}
+}
+
+// Name returns name of the directory.
+func (d *Directory) Name() string {
+	return d.cfg.Name
+}
+
+// Verify checks password of the user with the login. It returns DN of the
+// user's entry and the user mapped from its attributes, or empty DN if
+// there is no such entry or the password is wrong.
+func (d *Directory) Verify(ctx context.Context, login, password string) (string, models.User, error) {
+	// Bind with empty password is unauthenticated and always succeeds.
+	if login == "" || password == "" {
+		return "", models.User{}, nil
+	}
+
+	c, err := d.dial(ctx)
+	if err != nil {
+		return "", models.User{}, err
+	}
+	defer c.close()
+
+	if d.cfg.BindDN != "" {
+		code, err := c.bind(d.cfg.BindDN, d.cfg.BindPassword)
+		if err != nil {
+			return "", models.User{}, err
+		}
+		if code != resultSuccess {
+			return "", models.User{}, fmt.Errorf("%w: service account bind: result code %d", ErrOperationFailed, code)
+		}
+	}
+
+	entries, err := c.search(d.cfg.BaseDN, d.filter(login), d.attributes())
+	if err != nil {
+		return "", models.User{}, err
+	}
+
+	switch len(entries) {
+	case 0:
+		return "", models.User{}, nil
+	case 1:
+	default:
+		return "", models.User{}, ErrAmbiguousLogin
+	}
+
+	code, err := c.bind(entries[0].dn, password)
+	if err != nil {
+		return "", models.User{}, err
+	}
+	switch code {
+	case resultSuccess:
+	case resultInvalidCredentials:
+		return "", models.User{}, nil
+	default:
+		return "", models.User{}, fmt.Errorf("%w: user bind: result code %d", ErrOperationFailed, code)
+	}
+
+	user := d.user(entries[0])
+	if user.Email == "" {
+		return "", models.User{}, ErrNoEmail
+	}
+
+	return entries[0].dn, user, nil
+}
+
+// filter returns search filter of the user's entry. Login is an assertion
+// value rather than a part of filter string, so it needs no escaping.
+func (d *Directory) filter(login string) *packet {
+	byLogin := equalityMatch(d.cfg.LoginAttribute, login)
+	if d.cfg.ObjectClass == "" {
+		return byLogin
+	}
+
+	return constructed(classContext, filterAnd, equalityMatch("objectClass", d.cfg.ObjectClass), byLogin)
+}
+
+// attributes returns attributes of the entry to fetch.
+func (d *Directory) attributes() []string {
+	m := d.cfg.Attributes
+
+	var attrs []string
+	for _, a := range []string{m.Email, m.Username, m.Sex, m.Location, m.DateOfBirth} {
+		if a != "" {
+			attrs = append(attrs, a)
+		}
+	}
+
+	return attrs
+}
+
+// user maps attributes of the entry to user fields.
+func (d *Directory) user(e entry) models.User {
+	m := d.cfg.Attributes
+
+	return models.User{
+		Email:       e.first(m.Email),
+		Username:    e.first(m.Username),
+		Sex:         e.first(m.Sex),
+		Location:    e.first(m.Location),
+		DateOfBirth: e.first(m.DateOfBirth),
+	}
+}
+
+func (d *Directory) dial(ctx context.Context) (*conn, error) {
+	u, err := url.Parse(d.cfg.URL)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrUnsupportedURL, err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, d.cfg.Timeout)
+	defer cancel()
+
+	var nc net.Conn
+	switch u.Scheme {
+	case "ldap":
+		nc, err = (&net.Dialer{}).DialContext(ctx, "tcp", hostPort(u, "389"))
+	case "ldaps":
+		cfg := &tls.Config{}
+		if d.cfg.TLS != nil {
+			cfg = d.cfg.TLS.Clone()
+		}
+		cfg.ServerName = u.Hostname()
+
+		nc, err = (&tls.Dialer{Config: cfg}).DialContext(ctx, "tcp", hostPort(u, "636"))
+	default:
+		return nil, fmt.Errorf("%w: scheme %q", ErrUnsupportedURL, u.Scheme)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	deadline, _ := ctx.Deadline()
+	if err := nc.SetDeadline(deadline); err != nil {
+		nc.Close()
+
+		return nil, err
+	}
+
+	return &conn{nc: nc, r: bufio.NewReader(nc)}, nil
+}
+
+func hostPort(u *url.URL, defaultPort string) string {
+	if u.Port() != "" {
+		return u.Host
+	}
+
+	return net.JoinHostPort(u.Hostname(), defaultPort)
+}
+
+func equalityMatch(attr, value string) *packet {
+	return constructed(classContext, filterEqualityMatch, octetString(attr), octetString(value))
+}
+
+// entry is a search result entry. Attribute names are case-insensitive,
+// they are kept in lower case.
+type entry struct {
+	dn         string
+	attributes map[string][]string
+}
+
+// first returns the first value of the attribute.
+func (e entry) first(attr string) string {
+	values := e.attributes[strings.ToLower(attr)]
+	if len(values) == 0 {
+		return ""
+	}
+
+	return values[0]
+}
+
+// conn is a connection to the directory. Operations are sent one at a time.
+type conn struct {
+	nc    net.Conn
+	r     *bufio.Reader
+	msgID int64
+}
+
+func (c *conn) close() {
+	_ = c.send(primitive(classApplication, opUnbindRequest, nil))
+	c.nc.Close()
+}
+
+func (c *conn) send(op *packet) error {
+	c.msgID++
+
+	_, err := c.nc.Write(sequence(integer(c.msgID), op).encode())
+
+	return err
+}
+
+// receive reads the next message of the current operation and returns its
+// protocol op.
+func (c *conn) receive() (*packet, error) {
+	for {
+		msg, err := readPacket(c.r)
+		if err != nil {
+			return nil, err
+		}
+
+		if !msg.is(classUniversal, tagSequence) || len(msg.children) < 2 {
+			return nil, ErrMalformedPacket
+		}
+
+		id, err := msg.children[0].int()
+		if err != nil {
+			return nil, err
+		}
+
+		// Unsolicited notifications have id 0, the only one defined is
+		// notice of disconnection.
+		if id == 0 {
+			return nil, fmt.Errorf("%w: server closed connection", ErrOperationFailed)
+		}
+
+		if id == c.msgID {
+			return msg.children[1], nil
+		}
+	}
+}
+
+// bind authenticates the connection and returns result code.
+func (c *conn) bind(dn, password string) (int64, error) {
+	err := c.send(constructed(classApplication, opBindRequest,
+		integer(3),
+		octetString(dn),
+		primitive(classContext, authSimple, []byte(password)),
+	))
+	if err != nil {
+		return 0, err
+	}
+
+	op, err := c.receive()
+	if err != nil {
+		return 0, err
+	}
+
+	if !op.is(classApplication, opBindResponse) {
+		return 0, ErrMalformedPacket
+	}
+
+	return resultCode(op)
+}
+
+// search returns entries in the subtree of base matching the filter. At most
+// two entries are requested, which is enough to tell a login is ambiguous.
+func (c *conn) search(base string, filter *packet, attrs []string) ([]entry, error) {
+	attrList := make([]*packet, 0, len(attrs))
+	for _, a := range attrs {
+		attrList = append(attrList, octetString(a))
+	}
+
+	err := c.send(constructed(classApplication, opSearchRequest,
+		octetString(base),
+		enumerated(scopeWholeSubtree),
+		enumerated(derefNever),
+		integer(2),
+		integer(0),
+		boolean(false),
+		filter,
+		sequence(attrList...),
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []entry
+	for {
+		op, err := c.receive()
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case op.is(classApplication, opSearchResultEntry):
+			e, err := parseEntry(op)
+			if err != nil {
+				return nil, err
+			}
+			entries = append(entries, e)
+		case op.is(classApplication, opSearchResultRef):
+			// Referrals to other servers aren't followed.
+		case op.is(classApplication, opSearchResultDone):
+			code, err := resultCode(op)
+			if err != nil {
+				return nil, err
+			}
+			if code != resultSuccess && code != resultSizeLimitExceeded {
+				return nil, fmt.Errorf("%w: search: result code %d", ErrOperationFailed, code)
+			}
+
+			return entries, nil
+		default:
+			return nil, ErrMalformedPacket
+		}
+	}
+}
+
+func resultCode(op *packet) (int64, error) {
+	if !op.constructed || len(op.children) < 3 {
+		return 0, ErrMalformedPacket
+	}
+
+	return op.children[0].int()
+}
+
+func parseEntry(op *packet) (entry, error) {
+	if !op.constructed || len(op.children) != 2 {
+		return entry{}, ErrMalformedPacket
+	}
+
+	dn, err := op.children[0].str()
+	if err != nil {
+		return entry{}, err
+	}
+
+	e := entry{dn: dn, attributes: make(map[string][]string)}
+	for _, attr := range op.children[1].children {
+		if !attr.constructed || len(attr.children) != 2 {
+			return entry{}, ErrMalformedPacket
+		}
+
+		name, err := attr.children[0].str()
+		if err != nil {
+			return entry{}, err
+		}
+		name = strings.ToLower(name)
+
+		for _, v := range attr.children[1].children {
+			value, err := v.str()
+			if err != nil {
+				return entry{}, err
+			}
+			e.attributes[name] = append(e.attributes[name], value)
+		}
+	}
+
+	return e, nil
+}
@@ -0,0 +1,127 @@
+package ldap
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPacket(t *testing.T) {
+	for _, n := range []int64{0, 1, 127, 128, -1, -129, 300, 1 << 40} {
+		p, err := readPacket(bufio.NewReader(bytes.NewReader(integer(n).encode())))
+		require.NoError(t, err)
+
+		got, err := p.int()
+		require.NoError(t, err)
+		assert.Equal(t, n, got)
+	}
+
+	long := octetString(string(bytes.Repeat([]byte("a"), 300)))
+	seq := sequence(long, boolean(true))
+
+	p, err := readPacket(bufio.NewReader(bytes.NewReader(seq.encode())))
+	require.NoError(t, err)
+	require.Len(t, p.children, 2)
+	assert.Equal(t, long.value, p.children[0].value)
+
+	_, err = parse([]byte{0x04, 0x05, 'a'})
+	assert.ErrorIs(t, err, ErrMalformedPacket)
+}
+
+func TestVerify(t *testing.T) {
+	server := newTestServer(t,
+		testEntry{dn: "cn=sso,dc=example,dc=com", password: "service"},
+		testEntry{
+			dn:       "uid=jdoe,ou=people,dc=example,dc=com",
+			password: "secret",
+			attributes: map[string][]string{
+				"objectClass": {"person"},
+				"uid":         {"jdoe"},
+				"Mail":        {"jdoe@example.com"},
+				"l":           {"Berlin", "Paris"},
+			},
+		},
+		testEntry{
+			dn:       "uid=nomail,ou=people,dc=example,dc=com",
+			password: "secret",
+			attributes: map[string][]string{
+				"objectClass": {"person"},
+				"uid":         {"nomail"},
+			},
+		},
+		testEntry{
+			dn:       "cn=printer,ou=devices,dc=example,dc=com",
+			password: "secret",
+			attributes: map[string][]string{
+				"objectClass": {"device"},
+				"uid":         {"printer"},
+				"mail":        {"printer@example.com"},
+			},
+		},
+		testEntry{
+			dn:         "uid=twin,ou=people,dc=example,dc=com",
+			attributes: map[string][]string{"objectClass": {"person"}, "uid": {"twin"}},
+		},
+		testEntry{
+			dn:         "uid=twin,ou=staff,dc=example,dc=com",
+			attributes: map[string][]string{"objectClass": {"person"}, "uid": {"twin"}},
+		},
+	)
+
+	cfg := Config{
+		Name:           "corp",
+		URL:            server.url(),
+		BindDN:         "cn=sso,dc=example,dc=com",
+		BindPassword:   "service",
+		BaseDN:         "dc=example,dc=com",
+		ObjectClass:    "person",
+		LoginAttribute: "uid",
+		Attributes:     Mapping{Email: "mail", Username: "uid", Location: "l"},
+	}
+	dir := New(cfg)
+	ctx := context.Background()
+
+	dn, user, err := dir.Verify(ctx, "jdoe", "secret")
+	require.NoError(t, err)
+	assert.Equal(t, "uid=jdoe,ou=people,dc=example,dc=com", dn)
+	assert.Equal(t, "jdoe@example.com", user.Email)
+	assert.Equal(t, "jdoe", user.Username)
+	assert.Equal(t, "Berlin", user.Location)
+	assert.Empty(t, user.Sex)
+
+	dn, _, err = dir.Verify(ctx, "jdoe", "wrong")
+	require.NoError(t, err)
+	assert.Empty(t, dn, "wrong password")
+
+	dn, _, err = dir.Verify(ctx, "nobody", "secret")
+	require.NoError(t, err)
+	assert.Empty(t, dn, "unknown login")
+
+	dn, _, err = dir.Verify(ctx, "printer", "secret")
+	require.NoError(t, err)
+	assert.Empty(t, dn, "entry of another class")
+
+	searches := server.searches
+	dn, _, err = dir.Verify(ctx, "jdoe", "")
+	require.NoError(t, err)
+	assert.Empty(t, dn, "empty password")
+	assert.Equal(t, searches, server.searches, "directory isn't queried without password")
+
+	_, _, err = dir.Verify(ctx, "twin", "secret")
+	assert.ErrorIs(t, err, ErrAmbiguousLogin)
+
+	_, _, err = dir.Verify(ctx, "nomail", "secret")
+	assert.ErrorIs(t, err, ErrNoEmail)
+
+	cfg.BindPassword = "wrong"
+	_, _, err = New(cfg).Verify(ctx, "jdoe", "secret")
+	assert.ErrorIs(t, err, ErrOperationFailed)
+
+	cfg.URL = "http://localhost"
+	_, _, err = New(cfg).Verify(ctx, "jdoe", "secret")
+	assert.ErrorIs(t, err, ErrUnsupportedURL)
+}
@@ -0,0 +1,170 @@
+package ldap
+
+import (
+	"bufio"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// testEntry is an entry of the test directory.
+type testEntry struct {
+	dn         string
+	password   string
+	attributes map[string][]string
+}
+
+// testServer is an in-process LDAP directory supporting simple bind and
+// search with equality and "and" filters.
+type testServer struct {
+	listener net.Listener
+	entries  []testEntry
+
+	mu       sync.Mutex
+	searches int
+}
+
+func newTestServer(t *testing.T, entries ...testEntry) *testServer {
+	t.Helper()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := &testServer{listener: l, entries: entries}
+	go s.serve()
+	t.Cleanup(func() { l.Close() })
+
+	return s
+}
+
+func (s *testServer) url() string {
+	return "ldap://" + s.listener.Addr().String()
+}
+
+func (s *testServer) serve() {
+	for {
+		c, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		go s.handle(c)
+	}
+}
+
+func (s *testServer) handle(c net.Conn) {
+	defer c.Close()
+
+	r := bufio.NewReader(c)
+	for {
+		msg, err := readPacket(r)
+		if err != nil || len(msg.children) < 2 {
+			return
+		}
+
+		id := msg.children[0]
+		op := msg.children[1]
+
+		var replies []*packet
+		switch {
+		case op.is(classApplication, opBindRequest):
+			replies = []*packet{s.bind(op)}
+		case op.is(classApplication, opSearchRequest):
+			replies = s.search(op)
+		default:
+			return
+		}
+
+		for _, reply := range replies {
+			if _, err := c.Write(sequence(id, reply).encode()); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (s *testServer) bind(op *packet) *packet {
+	dn, _ := op.children[1].str()
+	password := string(op.children[2].value)
+
+	code := int64(resultInvalidCredentials)
+	for _, e := range s.entries {
+		if strings.EqualFold(e.dn, dn) && e.password == password {
+			code = resultSuccess
+		}
+	}
+
+	return result(opBindResponse, code)
+}
+
+func (s *testServer) search(op *packet) []*packet {
+	s.mu.Lock()
+	s.searches++
+	s.mu.Unlock()
+
+	base, _ := op.children[0].str()
+	sizeLimit, _ := op.children[3].int()
+	filter := op.children[6]
+
+	var replies []*packet
+	for _, e := range s.entries {
+		if !strings.HasSuffix(strings.ToLower(e.dn), strings.ToLower(base)) || !matches(filter, e) {
+			continue
+		}
+
+		if int64(len(replies)) == sizeLimit {
+			return append(replies, result(opSearchResultDone, resultSizeLimitExceeded))
+		}
+
+		var attrs []*packet
+		for name, values := range e.attributes {
+			vals := make([]*packet, 0, len(values))
+			for _, v := range values {
+				vals = append(vals, octetString(v))
+			}
+			attrs = append(attrs, sequence(octetString(name), constructed(classUniversal, tagSet, vals...)))
+		}
+
+		replies = append(replies, constructed(classApplication, opSearchResultEntry,
+			octetString(e.dn),
+			sequence(attrs...),
+		))
+	}
+
+	return append(replies, result(opSearchResultDone, resultSuccess))
+}
+
+func matches(filter *packet, e testEntry) bool {
+	switch {
+	case filter.is(classContext, filterAnd):
+		for _, f := range filter.children {
+			if !matches(f, e) {
+				return false
+			}
+		}
+
+		return true
+	case filter.is(classContext, filterEqualityMatch):
+		attr, _ := filter.children[0].str()
+		value, _ := filter.children[1].str()
+		for name, values := range e.attributes {
+			if !strings.EqualFold(name, attr) {
+				continue
+			}
+			for _, v := range values {
+				if strings.EqualFold(v, value) {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+func result(op byte, code int64) *packet {
+	return constructed(classApplication, op, enumerated(code), octetString(""), octetString(""))
+}
@@ -0,0 +1,119 @@
+package mailer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/smtp"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Mailer delivers emails to users.
+type Mailer interface {
+	Send(ctx context.Context, to, subject, body string) error
+}
+
+// LogMailer writes emails to the log instead of sending them.
+// It's meant for local runs and tests.
+type LogMailer struct {
+	log *slog.Logger
+}
+
+func NewLogMailer(log *slog.Logger) *LogMailer {
+	return &LogMailer{log: log}
+}
+
+func (m *LogMailer) Send(_ context.Context, to, subject, body string) error {
+	m.log.Info("email",
+		slog.String("to", to),
+		slog.String("subject", subject),
+		slog.String("body", body),
+	)
+
+	return nil
+}
+
+// FileMailer appends emails to a file, one JSON object per line, for local
+// runs where links and codes are read by scripts.
+type FileMailer struct {
+	path string
+	mu   sync.Mutex
+}
+
+func NewFileMailer(path string) *FileMailer {
+	return &FileMailer{path: path}
+}
+
+func (m *FileMailer) Send(_ context.Context, to, subject, body string) error {
+	const op = "mailer.FileMailer.Send"
+
+	line, err := json.Marshal(struct {
+		To      string    `json:"to"`
+		Subject string    `json:"subject"`
+		Body    string    `json:"body"`
+		SentAt  time.Time `json:"sent_at"`
+	}{to, subject, body, time.Now().UTC()})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	f, err := os.OpenFile(m.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// SMTPMailer sends emails through SMTP server with PLAIN auth.
+type SMTPMailer struct {
+	addr string
+	from string
+	auth smtp.Auth
+}
+
+func NewSMTPMailer(host string, port int, username, password, from string) *SMTPMailer {
+	var auth smtp.Auth
+	if username != "" {
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+
+	return &SMTPMailer{
+		addr: net.JoinHostPort(host, strconv.Itoa(port)),
+		from: from,
+		auth: auth,
+	}
+}
+
+func (m *SMTPMailer) Send(_ context.Context, to, subject, body string) error {
+	const op = "mailer.SMTPMailer.Send"
+
+	var msg strings.Builder
+	msg.WriteString("From: " + m.from + "\r\n")
+	msg.WriteString("To: " + to + "\r\n")
+	msg.WriteString("Subject: " + subject + "\r\n")
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=\"utf-8\"\r\n")
+	msg.WriteString("\r\n")
+	msg.WriteString(body)
+
+	if err := smtp.SendMail(m.addr, m.auth, m.from, []string{to}, []byte(msg.String())); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,406 @@
+// Package oidc implements the relying party side of OpenID Connect: the
+// authorization code flow with PKCE against an upstream provider and
+// validation of the ID tokens it issues.
+package oidc
+
+import (
+	"context"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	ErrDiscovery      = errors.New("failed to discover provider")
+	ErrExchange       = errors.New("failed to exchange code")
+	ErrInvalidIDToken = errors.New("invalid id token")
+)
+
+// DefaultScopes are requested if the provider config has none.
+var DefaultScopes = []string{"openid", "email", "profile"}
+
+// keysRefreshInterval limits how often keys are refetched for unknown kids.
+const keysRefreshInterval = time.Minute
+
+// maxResponseSize limits responses read from the provider.
+const maxResponseSize = 1 << 20
+
+// Config is the registration of this service as a client of the provider.
+type Config struct {
+	// Issuer is the provider URL, its metadata is discovered at
+	// Issuer + "/.well-known/openid-configuration".
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	// RedirectURI is the callback the provider redirects users back to.
+	RedirectURI string
+	Scopes      []string
+}
+
+// Claims are the claims of a verified ID token.
+type Claims struct {
+	Subject           string
+	Email             string
+	EmailVerified     bool
+	Name              string
+	PreferredUsername string
+}
+
+// Provider is an upstream OpenID Connect provider. Its metadata and keys
+// are fetched on first use and cached.
+type Provider struct {
+	cfg    Config
+	client *http.Client
+
+	mu            sync.Mutex
+	metadata      *metadata
+	keys          map[string]*rsa.PublicKey
+	keysFetchedAt time.Time
+}
+
+type metadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// New returns provider for the config. Requests to the provider are made
+// with client, http.DefaultClient if nil.
+func New(cfg Config, client *http.Client) *Provider {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	if len(cfg.Scopes) == 0 {
+		cfg.Scopes = DefaultScopes
+	}
+	cfg.Issuer = strings.TrimSuffix(cfg.Issuer, "/")
+
+	return &Provider{cfg: cfg, client: client}
+}
+
+// AuthCodeURL returns URL of the provider the user is redirected to for
+// sign in. The provider redirects back with the state, the nonce ends up
+// in the ID token and verifier is the PKCE code verifier.
+func (p *Provider) AuthCodeURL(ctx context.Context, state, nonce, verifier string) (string, error) {
+	md, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	q := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {p.cfg.ClientID},
+		"redirect_uri":          {p.cfg.RedirectURI},
+		"scope":                 {strings.Join(p.cfg.Scopes, " ")},
+		"state":                 {state},
+		"nonce":                 {nonce},
+		"code_challenge":        {Challenge(verifier)},
+		"code_challenge_method": {"S256"},
+	}
+
+	sep := "?"
+	if strings.Contains(md.AuthorizationEndpoint, "?") {
+		sep = "&"
+	}
+
+	return md.AuthorizationEndpoint + sep + q.Encode(), nil
+}
+
+// Exchange exchanges the authorization code for tokens and returns claims
+// of the verified ID token.
+func (p *Provider) Exchange(ctx context.Context, code, verifier, nonce string) (Claims, error) {
+	md, err := p.discover(ctx)
+	if err != nil {
+		return Claims{}, err
+	}
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {p.cfg.RedirectURI},
+		"code_verifier": {verifier},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, md.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return Claims{}, fmt.Errorf("%w: %w", ErrExchange, err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(p.cfg.ClientID), url.QueryEscape(p.cfg.ClientSecret))
+
+	var resp struct {
+		IDToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	status, err := p.do(req, &resp)
+	if err != nil {
+		return Claims{}, fmt.Errorf("%w: %w", ErrExchange, err)
+	}
+
+	if status != http.StatusOK {
+		return Claims{}, fmt.Errorf("%w: status %d: %s %s", ErrExchange, status, resp.Error, resp.ErrorDescription)
+	}
+
+	if resp.IDToken == "" {
+		return Claims{}, fmt.Errorf("%w: no id_token in response", ErrExchange)
+	}
+
+	return p.Verify(ctx, resp.IDToken, nonce)
+}
+
+// Verify verifies ID token: its RS256 signature with a key of the
+// provider, issuer, audience, expiry and nonce.
+func (p *Provider) Verify(ctx context.Context, rawIDToken, nonce string) (Claims, error) {
+	md, err := p.discover(ctx)
+	if err != nil {
+		return Claims{}, err
+	}
+
+	mc := jwt.MapClaims{}
+	_, err = jwt.ParseWithClaims(rawIDToken, mc, func(token *jwt.Token) (interface{}, error) {
+		if token.Method.Alg() != jwt.SigningMethodRS256.Alg() {
+			return nil, fmt.Errorf("unexpected signing method %s", token.Method.Alg())
+		}
+
+		kid, _ := token.Header["kid"].(string)
+
+		return p.key(ctx, md, kid)
+	})
+	if err != nil {
+		return Claims{}, fmt.Errorf("%w: %w", ErrInvalidIDToken, err)
+	}
+
+	if iss, _ := mc["iss"].(string); iss != md.Issuer {
+		return Claims{}, fmt.Errorf("%w: unexpected issuer %q", ErrInvalidIDToken, iss)
+	}
+
+	aud := audience(mc["aud"])
+	if !slices.Contains(aud, p.cfg.ClientID) {
+		return Claims{}, fmt.Errorf("%w: not issued for the client", ErrInvalidIDToken)
+	}
+	if azp, ok := mc["azp"].(string); (len(aud) > 1 || ok) && azp != p.cfg.ClientID {
+		return Claims{}, fmt.Errorf("%w: unexpected authorized party %q", ErrInvalidIDToken, azp)
+	}
+
+	// MapClaims.Valid accepts tokens without exp.
+	if _, ok := mc["exp"].(float64); !ok {
+		return Claims{}, fmt.Errorf("%w: no expiry", ErrInvalidIDToken)
+	}
+
+	if n, _ := mc["nonce"].(string); n != nonce {
+		return Claims{}, fmt.Errorf("%w: nonce mismatch", ErrInvalidIDToken)
+	}
+
+	claims := Claims{
+		EmailVerified: emailVerified(mc["email_verified"]),
+	}
+	claims.Subject, _ = mc["sub"].(string)
+	claims.Email, _ = mc["email"].(string)
+	claims.Name, _ = mc["name"].(string)
+	claims.PreferredUsername, _ = mc["preferred_username"].(string)
+
+	if claims.Subject == "" {
+		return Claims{}, fmt.Errorf("%w: no subject", ErrInvalidIDToken)
+	}
+
+	return claims, nil
+}
+
+// discover returns metadata of the provider, fetching it on first use.
+func (p *Provider) discover(ctx context.Context) (*metadata, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.metadata != nil {
+		return p.metadata, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.cfg.Issuer+"/.well-known/openid-configuration", nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrDiscovery, err)
+	}
+
+	var md metadata
+	status, err := p.do(req, &md)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrDiscovery, err)
+	}
+
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("%w: status %d", ErrDiscovery, status)
+	}
+
+	if md.Issuer != p.cfg.Issuer {
+		return nil, fmt.Errorf("%w: issuer %q doesn't match %q", ErrDiscovery, md.Issuer, p.cfg.Issuer)
+	}
+
+	if md.AuthorizationEndpoint == "" || md.TokenEndpoint == "" || md.JWKSURI == "" {
+		return nil, fmt.Errorf("%w: incomplete metadata", ErrDiscovery)
+	}
+
+	p.metadata = &md
+
+	return p.metadata, nil
+}
+
+// key returns signing key of the provider by kid. Keys are refetched if
+// kid is unknown, to pick up rotated keys, but not more often than
+// keysRefreshInterval.
+func (p *Provider) key(ctx context.Context, md *metadata, kid string) (*rsa.PublicKey, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if key, ok := p.lookupKey(kid); ok {
+		return key, nil
+	}
+
+	if time.Since(p.keysFetchedAt) < keysRefreshInterval {
+		return nil, fmt.Errorf("unknown key %q", kid)
+	}
+
+	keys, err := p.fetchKeys(ctx, md.JWKSURI)
+	if err != nil {
+		return nil, err
+	}
+	p.keys, p.keysFetchedAt = keys, time.Now()
+
+	if key, ok := p.lookupKey(kid); ok {
+		return key, nil
+	}
+
+	return nil, fmt.Errorf("unknown key %q", kid)
+}
+
+// lookupKey returns cached key by kid, or the only key if token has no kid.
+func (p *Provider) lookupKey(kid string) (*rsa.PublicKey, bool) {
+	if kid == "" && len(p.keys) == 1 {
+		for _, key := range p.keys {
+			return key, true
+		}
+	}
+
+	key, ok := p.keys[kid]
+
+	return key, ok
+}
+
+func (p *Provider) fetchKeys(ctx context.Context, jwksURI string) (map[string]*rsa.PublicKey, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, jwksURI, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var set struct {
+		Keys []struct {
+			Kty string `json:"kty"`
+			Kid string `json:"kid"`
+			Use string `json:"use"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+		} `json:"keys"`
+	}
+	status, err := p.do(req, &set)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch keys: %w", err)
+	}
+
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch keys: status %d", status)
+	}
+
+	keys := make(map[string]*rsa.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Kty != "RSA" || (k.Use != "" && k.Use != "sig") {
+			continue
+		}
+
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			continue
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil || len(e) > 4 {
+			continue
+		}
+
+		keys[k.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+
+	return keys, nil
+}
+
+// do sends request and decodes JSON response into v, returning the status.
+func (p *Provider) do(req *http.Request, v any) (int, error) {
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxResponseSize)).Decode(v); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return resp.StatusCode, nil
+		}
+
+		return 0, fmt.Errorf("invalid response: %w", err)
+	}
+
+	return resp.StatusCode, nil
+}
+
+// Challenge returns the S256 PKCE code challenge of the verifier.
+func Challenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// audience returns the aud claim, which is a string or an array of strings.
+func audience(v any) []string {
+	switch aud := v.(type) {
+	case string:
+		return []string{aud}
+	case []any:
+		res := make([]string, 0, len(aud))
+		for _, a := range aud {
+			if s, ok := a.(string); ok {
+				res = append(res, s)
+			}
+		}
+
+		return res
+	}
+
+	return nil
+}
+
+// emailVerified returns the email_verified claim, some providers send it
+// as a string.
+func emailVerified(v any) bool {
+	switch verified := v.(type) {
+	case bool:
+		return verified
+	case string:
+		return verified == "true"
+	}
+
+	return false
+}
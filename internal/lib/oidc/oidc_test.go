@@ -0,0 +1,117 @@
+package oidc_test
+
+import (
+	"SSO/internal/lib/oidc"
+	"SSO/internal/lib/oidc/oidctest"
+	"context"
+	"github.com/golang-jwt/jwt"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const redirectURI = "https://sso.example/federation/callback"
+
+func newProvider(t *testing.T) (*oidctest.Provider, *oidc.Provider) {
+	t.Helper()
+
+	upstream, err := oidctest.New("sso", "secret")
+	require.NoError(t, err)
+	t.Cleanup(upstream.Close)
+
+	return upstream, oidc.New(oidc.Config{
+		Issuer:       upstream.Issuer(),
+		ClientID:     "sso",
+		ClientSecret: "secret",
+		RedirectURI:  redirectURI,
+	}, nil)
+}
+
+func TestCodeFlow(t *testing.T) {
+	ctx := context.Background()
+	upstream, provider := newProvider(t)
+
+	authURL, err := provider.AuthCodeURL(ctx, "state", "nonce", "verifier")
+	require.NoError(t, err)
+
+	u, err := url.Parse(authURL)
+	require.NoError(t, err)
+	assert.Equal(t, redirectURI, u.Query().Get("redirect_uri"))
+	assert.Equal(t, "openid email profile", u.Query().Get("scope"))
+	assert.Equal(t, oidc.Challenge("verifier"), u.Query().Get("code_challenge"))
+
+	user := oidctest.User{Subject: "42", Email: "jane@corp.example", EmailVerified: true, Name: "Jane"}
+	code, state, err := upstream.Authorize(authURL, user)
+	require.NoError(t, err)
+	assert.Equal(t, "state", state)
+
+	// Wrong PKCE verifier is rejected by the provider.
+	_, err = provider.Exchange(ctx, code, "other", "nonce")
+	assert.ErrorIs(t, err, oidc.ErrExchange)
+
+	code, _, err = upstream.Authorize(authURL, user)
+	require.NoError(t, err)
+
+	claims, err := provider.Exchange(ctx, code, "verifier", "nonce")
+	require.NoError(t, err)
+	assert.Equal(t, oidc.Claims{Subject: "42", Email: "jane@corp.example", EmailVerified: true, Name: "Jane"}, claims)
+
+	// Codes are single use.
+	_, err = provider.Exchange(ctx, code, "verifier", "nonce")
+	assert.ErrorIs(t, err, oidc.ErrExchange)
+}
+
+func TestVerify(t *testing.T) {
+	ctx := context.Background()
+	upstream, provider := newProvider(t)
+
+	user := oidctest.User{Subject: "42", Email: "jane@corp.example"}
+
+	tests := []struct {
+		name   string
+		modify func(jwt.MapClaims)
+	}{
+		{"wrong issuer", func(c jwt.MapClaims) { c["iss"] = "https://evil.example" }},
+		{"wrong audience", func(c jwt.MapClaims) { c["aud"] = "other" }},
+		{"foreign authorized party", func(c jwt.MapClaims) { c["aud"] = []string{"sso", "other"}; c["azp"] = "other" }},
+		{"expired", func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-time.Minute).Unix() }},
+		{"no expiry", func(c jwt.MapClaims) { delete(c, "exp") }},
+		{"wrong nonce", func(c jwt.MapClaims) { c["nonce"] = "other" }},
+		{"no subject", func(c jwt.MapClaims) { delete(c, "sub") }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := upstream.IDTokenClaims(user, "nonce")
+			tt.modify(claims)
+
+			token, err := upstream.SignIDToken(claims)
+			require.NoError(t, err)
+
+			_, err = provider.Verify(ctx, token, "nonce")
+			assert.ErrorIs(t, err, oidc.ErrInvalidIDToken)
+		})
+	}
+
+	t.Run("multiple audiences", func(t *testing.T) {
+		claims := upstream.IDTokenClaims(user, "nonce")
+		claims["aud"], claims["azp"] = []string{"sso", "other"}, "sso"
+
+		token, err := upstream.SignIDToken(claims)
+		require.NoError(t, err)
+
+		_, err = provider.Verify(ctx, token, "nonce")
+		assert.NoError(t, err)
+	})
+
+	t.Run("symmetric signature", func(t *testing.T) {
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, upstream.IDTokenClaims(user, "nonce")).
+			SignedString([]byte("secret"))
+		require.NoError(t, err)
+
+		_, err = provider.Verify(ctx, token, "nonce")
+		assert.ErrorIs(t, err, oidc.ErrInvalidIDToken)
+	})
+}
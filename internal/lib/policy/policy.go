@@ -0,0 +1,235 @@
+package policy
+
+import (
+	"SSO/internal/domain/models"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+const (
+	EffectAllow = "allow"
+	EffectDeny  = "deny"
+)
+
+// Condition operators.
+const (
+	OpEquals    = "eq"
+	OpNotEquals = "ne"
+	OpIn        = "in"
+	OpNotIn     = "not_in"
+	OpContains  = "contains"
+	OpExists    = "exists"
+)
+
+// Attribute prefixes of the evaluation input.
+const (
+	SubjectPrefix  = "subject."
+	ResourcePrefix = "resource."
+	EnvPrefix      = "env."
+)
+
+// ListSeparator separates items of list attributes and values,
+// e.g. "admin,editor" for subject.roles.
+const ListSeparator = ","
+
+var ErrInvalidPolicy = errors.New("invalid policy")
+
+// Request is the input of an authorization decision.
+type Request struct {
+	Action       string
+	ResourceType string
+	// Attributes are prefixed with SubjectPrefix, ResourcePrefix or EnvPrefix.
+	Attributes map[string]string
+}
+
+// Evaluation explains how a single policy applied to the request.
+type Evaluation struct {
+	PolicyID   int64
+	PolicyName string
+	Effect     string
+	Matched    bool
+	Reason     string
+}
+
+// Decision is the result of evaluating policies against the request.
+type Decision struct {
+	Allowed     bool
+	Reason      string
+	Evaluations []Evaluation
+}
+
+// Validate checks that policy can be evaluated.
+func Validate(p models.Policy) error {
+	if p.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidPolicy)
+	}
+
+	if p.Effect != EffectAllow && p.Effect != EffectDeny {
+		return fmt.Errorf("%w: effect must be %q or %q", ErrInvalidPolicy, EffectAllow, EffectDeny)
+	}
+
+	if len(p.Actions) == 0 {
+		return fmt.Errorf("%w: at least one action is required", ErrInvalidPolicy)
+	}
+
+	if len(p.Resources) == 0 {
+		return fmt.Errorf("%w: at least one resource is required", ErrInvalidPolicy)
+	}
+
+	for _, c := range p.Conditions {
+		if !hasKnownPrefix(c.Attribute) {
+			return fmt.Errorf("%w: unknown attribute %q", ErrInvalidPolicy, c.Attribute)
+		}
+
+		switch c.Operator {
+		case OpEquals, OpNotEquals, OpIn, OpNotIn, OpContains, OpExists:
+		default:
+			return fmt.Errorf("%w: unknown operator %q", ErrInvalidPolicy, c.Operator)
+		}
+
+		if ref, ok := strings.CutPrefix(c.Value, "$"); ok && !hasKnownPrefix(ref) {
+			return fmt.Errorf("%w: unknown attribute %q", ErrInvalidPolicy, ref)
+		}
+	}
+
+	return nil
+}
+
+// Evaluate evaluates policies against the request.
+//
+// A matching deny policy overrides any matching allow policy.
+// Requests no policy matches are denied.
+func Evaluate(policies []models.Policy, req Request) Decision {
+	var (
+		decision = Decision{Evaluations: make([]Evaluation, 0, len(policies))}
+		allowBy  string
+		denyBy   string
+	)
+
+	for _, p := range policies {
+		matched, reason := match(p, req)
+
+		decision.Evaluations = append(decision.Evaluations, Evaluation{
+			PolicyID:   p.ID,
+			PolicyName: p.Name,
+			Effect:     p.Effect,
+			Matched:    matched,
+			Reason:     reason,
+		})
+
+		if !matched {
+			continue
+		}
+
+		switch {
+		case p.Effect == EffectDeny && denyBy == "":
+			denyBy = p.Name
+		case p.Effect == EffectAllow && allowBy == "":
+			allowBy = p.Name
+		}
+	}
+
+	switch {
+	case denyBy != "":
+		decision.Reason = fmt.Sprintf("denied by policy %q", denyBy)
+	case allowBy != "":
+		decision.Allowed = true
+		decision.Reason = fmt.Sprintf("allowed by policy %q", allowBy)
+	default:
+		decision.Reason = "no policy allows the request"
+	}
+
+	return decision
+}
+
+func match(p models.Policy, req Request) (bool, string) {
+	if !slices.ContainsFunc(p.Actions, func(pattern string) bool { return wildcardMatch(pattern, req.Action) }) {
+		return false, fmt.Sprintf("action %q is not covered", req.Action)
+	}
+
+	if !slices.ContainsFunc(p.Resources, func(pattern string) bool { return wildcardMatch(pattern, req.ResourceType) }) {
+		return false, fmt.Sprintf("resource %q is not covered", req.ResourceType)
+	}
+
+	for _, c := range p.Conditions {
+		if ok, reason := check(c, req.Attributes); !ok {
+			return false, reason
+		}
+	}
+
+	return true, "all conditions are met"
+}
+
+func check(c models.PolicyCondition, attrs map[string]string) (bool, string) {
+	actual, exists := attrs[c.Attribute]
+
+	if c.Operator == OpExists {
+		if !exists {
+			return false, fmt.Sprintf("%s is not set", c.Attribute)
+		}
+
+		return true, ""
+	}
+
+	expected := c.Value
+	if ref, ok := strings.CutPrefix(c.Value, "$"); ok {
+		expected = attrs[ref]
+	}
+
+	var ok bool
+	switch c.Operator {
+	case OpEquals:
+		ok = exists && actual == expected
+	case OpNotEquals:
+		ok = actual != expected
+	case OpIn:
+		ok = exists && slices.Contains(splitList(expected), actual)
+	case OpNotIn:
+		ok = !slices.Contains(splitList(expected), actual)
+	case OpContains:
+		ok = slices.Contains(splitList(actual), expected)
+	}
+
+	if !ok {
+		// Attribute values may be personal data and reasons are logged and
+		// returned to callers, so only the condition itself is named.
+		return false, fmt.Sprintf("condition %s %s %q failed", c.Attribute, c.Operator, c.Value)
+	}
+
+	return true, ""
+}
+
+// wildcardMatch matches value against pattern which is either "*",
+// an exact value or a prefix ending with ".*", e.g. "posts.*".
+func wildcardMatch(pattern, value string) bool {
+	if pattern == "*" || pattern == value {
+		return true
+	}
+
+	if prefix, ok := strings.CutSuffix(pattern, ".*"); ok {
+		return strings.HasPrefix(value, prefix+".")
+	}
+
+	return false
+}
+
+func splitList(s string) []string {
+	if s == "" {
+		return nil
+	}
+
+	items := strings.Split(s, ListSeparator)
+	for i := range items {
+		items[i] = strings.TrimSpace(items[i])
+	}
+
+	return items
+}
+
+func hasKnownPrefix(attribute string) bool {
+	return strings.HasPrefix(attribute, SubjectPrefix) ||
+		strings.HasPrefix(attribute, ResourcePrefix) ||
+		strings.HasPrefix(attribute, EnvPrefix)
+}
@@ -0,0 +1,130 @@
+package policy
+
+import (
+	"SSO/internal/domain/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+var ownResourcesInLocation = models.Policy{
+	ID:        1,
+	Name:      "edit own in location",
+	Effect:    EffectAllow,
+	Actions:   []string{"posts.edit"},
+	Resources: []string{"post"},
+	Conditions: []models.PolicyCondition{
+		{Attribute: "resource.owner_id", Operator: OpEquals, Value: "$subject.id"},
+		{Attribute: "resource.location", Operator: OpEquals, Value: "$subject.location"},
+	},
+}
+
+func TestEvaluate_OwnResourceInLocation(t *testing.T) {
+	tests := []struct {
+		name    string
+		attrs   map[string]string
+		allowed bool
+	}{
+		{
+			name: "owner in location",
+			attrs: map[string]string{
+				"subject.id": "7", "subject.location": "Berlin",
+				"resource.owner_id": "7", "resource.location": "Berlin",
+			},
+			allowed: true,
+		},
+		{
+			name: "not owner",
+			attrs: map[string]string{
+				"subject.id": "8", "subject.location": "Berlin",
+				"resource.owner_id": "7", "resource.location": "Berlin",
+			},
+		},
+		{
+			name: "another location",
+			attrs: map[string]string{
+				"subject.id": "7", "subject.location": "Paris",
+				"resource.owner_id": "7", "resource.location": "Berlin",
+			},
+		},
+		{
+			name:  "missing attributes",
+			attrs: map[string]string{"subject.id": "7"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decision := Evaluate([]models.Policy{ownResourcesInLocation}, Request{
+				Action:       "posts.edit",
+				ResourceType: "post",
+				Attributes:   tt.attrs,
+			})
+
+			assert.Equal(t, tt.allowed, decision.Allowed)
+			require.Len(t, decision.Evaluations, 1)
+			assert.Equal(t, tt.allowed, decision.Evaluations[0].Matched)
+			assert.NotEmpty(t, decision.Evaluations[0].Reason)
+			for _, value := range tt.attrs {
+				assert.NotContains(t, decision.Evaluations[0].Reason, value, "reason has no attribute values")
+			}
+		})
+	}
+}
+
+func TestEvaluate_DenyOverridesAllow(t *testing.T) {
+	policies := []models.Policy{
+		{ID: 1, Name: "allow all", Effect: EffectAllow, Actions: []string{"*"}, Resources: []string{"*"}},
+		{
+			ID: 2, Name: "no banned", Effect: EffectDeny, Actions: []string{"posts.*"}, Resources: []string{"*"},
+			Conditions: []models.PolicyCondition{{Attribute: "subject.roles", Operator: OpContains, Value: "banned"}},
+		},
+	}
+
+	decision := Evaluate(policies, Request{
+		Action:       "posts.create",
+		ResourceType: "post",
+		Attributes:   map[string]string{"subject.roles": "member,banned"},
+	})
+	assert.False(t, decision.Allowed)
+	assert.Equal(t, `denied by policy "no banned"`, decision.Reason)
+
+	decision = Evaluate(policies, Request{
+		Action:       "posts.create",
+		ResourceType: "post",
+		Attributes:   map[string]string{"subject.roles": "member"},
+	})
+	assert.True(t, decision.Allowed)
+}
+
+func TestEvaluate_NoPolicies(t *testing.T) {
+	decision := Evaluate(nil, Request{Action: "posts.edit", ResourceType: "post"})
+
+	assert.False(t, decision.Allowed)
+	assert.Empty(t, decision.Evaluations)
+}
+
+func TestWildcardMatch(t *testing.T) {
+	assert.True(t, wildcardMatch("*", "posts.edit"))
+	assert.True(t, wildcardMatch("posts.edit", "posts.edit"))
+	assert.True(t, wildcardMatch("posts.*", "posts.edit"))
+	assert.False(t, wildcardMatch("posts.*", "posts"))
+	assert.False(t, wildcardMatch("posts.*", "postsecret.edit"))
+	assert.False(t, wildcardMatch("posts*", "posts.edit"), "only whole segments are matched")
+}
+
+func TestValidate(t *testing.T) {
+	require.NoError(t, Validate(ownResourcesInLocation))
+
+	invalid := ownResourcesInLocation
+	invalid.Effect = "maybe"
+	assert.ErrorIs(t, Validate(invalid), ErrInvalidPolicy)
+
+	invalid = ownResourcesInLocation
+	invalid.Conditions = []models.PolicyCondition{{Attribute: "owner_id", Operator: OpEquals}}
+	assert.ErrorIs(t, Validate(invalid), ErrInvalidPolicy)
+
+	invalid = ownResourcesInLocation
+	invalid.Conditions = []models.PolicyCondition{{Attribute: "resource.owner_id", Operator: "like"}}
+	assert.ErrorIs(t, Validate(invalid), ErrInvalidPolicy)
+}
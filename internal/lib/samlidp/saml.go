@@ -0,0 +1,404 @@
+// Package samlidp implements the identity provider side of SAML 2.0 Web
+// Browser SSO: IdP metadata, AuthnRequests received with the HTTP-Redirect
+// and HTTP-POST bindings and signed responses sent with the HTTP-POST
+// binding.
+package samlidp
+
+import (
+	"bytes"
+	"compress/flate"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"time"
+)
+
+// Bindings requests are received with.
+const (
+	BindingRedirect = "urn:oasis:names:tc:SAML:2.0:bindings:HTTP-Redirect"
+	BindingPOST     = "urn:oasis:names:tc:SAML:2.0:bindings:HTTP-POST"
+)
+
+// Formats of the subject name identifier.
+const (
+	NameIDFormatEmail       = "urn:oasis:names:tc:SAML:1.1:nameid-format:emailAddress"
+	NameIDFormatPersistent  = "urn:oasis:names:tc:SAML:2.0:nameid-format:persistent"
+	NameIDFormatUnspecified = "urn:oasis:names:tc:SAML:1.1:nameid-format:unspecified"
+)
+
+// NameIDFormats are the supported name identifier formats.
+var NameIDFormats = []string{NameIDFormatEmail, NameIDFormatPersistent, NameIDFormatUnspecified}
+
+const (
+	nsMetadata  = "urn:oasis:names:tc:SAML:2.0:metadata"
+	nsAssertion = "urn:oasis:names:tc:SAML:2.0:assertion"
+	nsProtocol  = "urn:oasis:names:tc:SAML:2.0:protocol"
+	nsSignature = "http://www.w3.org/2000/09/xmldsig#"
+
+	algExcC14N    = "http://www.w3.org/2001/10/xml-exc-c14n#"
+	algEnveloped  = "http://www.w3.org/2000/09/xmldsig#enveloped-signature"
+	algRSASHA256  = "http://www.w3.org/2001/04/xmldsig-more#rsa-sha256"
+	algSHA256     = "http://www.w3.org/2001/04/xmlenc#sha256"
+	statusSuccess = "urn:oasis:names:tc:SAML:2.0:status:Success"
+	confBearer    = "urn:oasis:names:tc:SAML:2.0:cm:bearer"
+	attrNameBasic = "urn:oasis:names:tc:SAML:2.0:attrname-format:basic"
+	authnPassword = "urn:oasis:names:tc:SAML:2.0:ac:classes:PasswordProtectedTransport"
+
+	timeLayout = "2006-01-02T15:04:05Z"
+	// maxRequestSize limits decoded AuthnRequests, inflated ones included.
+	maxRequestSize = 64 << 10
+	// clockSkew is how much clocks of service providers may be off.
+	clockSkew = time.Minute
+)
+
+var (
+	ErrInvalidRequest     = errors.New("invalid SAML request")
+	ErrUnsupportedBinding = errors.New("unsupported SAML binding")
+)
+
+// IdentityProvider is the identity provider issuing signed assertions.
+type IdentityProvider struct {
+	// EntityID identifies the identity provider, usually its metadata URL.
+	EntityID string
+	// SSOURL is the page service providers send AuthnRequests to.
+	SSOURL      string
+	Key         *rsa.PrivateKey
+	Certificate *x509.Certificate
+}
+
+// AuthnRequest is an authentication request of a service provider.
+type AuthnRequest struct {
+	ID           string
+	Issuer       string
+	IssueInstant time.Time
+	// AssertionConsumerServiceURL is where the response is posted, empty
+	// for the default one of the service provider.
+	AssertionConsumerServiceURL string
+	ProtocolBinding             string
+}
+
+// Attribute is an attribute of the subject put into assertions.
+type Attribute struct {
+	Name   string
+	Values []string
+}
+
+// Assertion describes the assertion about the authenticated user issued
+// in response to AuthnRequest.
+type Assertion struct {
+	InResponseTo string
+	// Audience is entity ID of the service provider.
+	Audience     string
+	Recipient    string
+	NameID       string
+	NameIDFormat string
+	SessionIndex string
+	Attributes   []Attribute
+	AuthnInstant time.Time
+	IssueInstant time.Time
+	TTL          time.Duration
+}
+
+// LoadKeyPair loads PEM encoded RSA key and certificate of the identity provider.
+func LoadKeyPair(certPath, keyPath string) (*rsa.PrivateKey, *x509.Certificate, error) {
+	pair, err := tls.LoadX509KeyPair(certPath, keyPath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	key, ok := pair.PrivateKey.(*rsa.PrivateKey)
+	if !ok {
+		return nil, nil, errors.New("samlidp: key must be RSA")
+	}
+
+	cert, err := x509.ParseCertificate(pair.Certificate[0])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return key, cert, nil
+}
+
+// GenerateKeyPair generates RSA key and self-signed certificate, for
+// local runs without configured ones.
+func GenerateKeyPair(commonName string) (*rsa.PrivateKey, *x509.Certificate, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(now.UnixNano()),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    now.Add(-clockSkew),
+		NotAfter:     now.AddDate(1, 0, 0),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return key, cert, nil
+}
+
+// Metadata returns metadata of the identity provider.
+func (idp *IdentityProvider) Metadata() []byte {
+	descriptor := newElement("md:IDPSSODescriptor").
+		attr("WantAuthnRequestsSigned", "false").
+		attr("protocolSupportEnumeration", nsProtocol).
+		add(
+			newElement("md:KeyDescriptor").attr("use", "signing").add(idp.keyInfo()),
+		)
+
+	for _, format := range NameIDFormats {
+		descriptor.add(newElement("md:NameIDFormat").setText(format))
+	}
+
+	for _, binding := range []string{BindingRedirect, BindingPOST} {
+		descriptor.add(
+			newElement("md:SingleSignOnService").attr("Binding", binding).attr("Location", idp.SSOURL),
+		)
+	}
+
+	entity := newElement("md:EntityDescriptor").
+		declare("md", nsMetadata).
+		attr("entityID", idp.EntityID).
+		add(descriptor)
+
+	return append([]byte(xml.Header), entity.bytes()...)
+}
+
+// ParseRequest decodes AuthnRequest received with the binding: deflated
+// and base64 encoded for HTTP-Redirect, base64 encoded for HTTP-POST.
+// Signatures of requests are not checked, responses are only sent to
+// assertion consumer services registered for the service provider.
+func ParseRequest(samlRequest, binding string) (AuthnRequest, error) {
+	raw, err := base64.StdEncoding.DecodeString(samlRequest)
+	if err != nil {
+		return AuthnRequest{}, fmt.Errorf("%w: %w", ErrInvalidRequest, err)
+	}
+
+	switch binding {
+	case BindingRedirect:
+		raw, err = io.ReadAll(io.LimitReader(flate.NewReader(bytes.NewReader(raw)), maxRequestSize+1))
+		if err != nil {
+			return AuthnRequest{}, fmt.Errorf("%w: %w", ErrInvalidRequest, err)
+		}
+	case BindingPOST:
+	default:
+		return AuthnRequest{}, ErrUnsupportedBinding
+	}
+
+	if len(raw) > maxRequestSize {
+		return AuthnRequest{}, fmt.Errorf("%w: request is too large", ErrInvalidRequest)
+	}
+
+	var req struct {
+		XMLName                     xml.Name  `xml:"urn:oasis:names:tc:SAML:2.0:protocol AuthnRequest"`
+		ID                          string    `xml:"ID,attr"`
+		Version                     string    `xml:"Version,attr"`
+		IssueInstant                time.Time `xml:"IssueInstant,attr"`
+		AssertionConsumerServiceURL string    `xml:"AssertionConsumerServiceURL,attr"`
+		ProtocolBinding             string    `xml:"ProtocolBinding,attr"`
+		Issuer                      string    `xml:"urn:oasis:names:tc:SAML:2.0:assertion Issuer"`
+	}
+	if err := xml.Unmarshal(raw, &req); err != nil {
+		return AuthnRequest{}, fmt.Errorf("%w: %w", ErrInvalidRequest, err)
+	}
+
+	switch {
+	case req.Version != "2.0":
+		return AuthnRequest{}, fmt.Errorf("%w: unsupported version %q", ErrInvalidRequest, req.Version)
+	case req.ID == "":
+		return AuthnRequest{}, fmt.Errorf("%w: ID is required", ErrInvalidRequest)
+	case req.Issuer == "":
+		return AuthnRequest{}, fmt.Errorf("%w: Issuer is required", ErrInvalidRequest)
+	case req.ProtocolBinding != "" && req.ProtocolBinding != BindingPOST:
+		return AuthnRequest{}, fmt.Errorf("%w: response binding %q", ErrUnsupportedBinding, req.ProtocolBinding)
+	}
+
+	return AuthnRequest{
+		ID:                          req.ID,
+		Issuer:                      req.Issuer,
+		IssueInstant:                req.IssueInstant,
+		AssertionConsumerServiceURL: req.AssertionConsumerServiceURL,
+		ProtocolBinding:             req.ProtocolBinding,
+	}, nil
+}
+
+// Response returns the response with the signed assertion, to be posted
+// base64 encoded to the recipient.
+func (idp *IdentityProvider) Response(a Assertion) ([]byte, error) {
+	responseID, err := newID()
+	if err != nil {
+		return nil, err
+	}
+
+	assertion, err := idp.assertion(a)
+	if err != nil {
+		return nil, err
+	}
+
+	issueInstant := a.IssueInstant.UTC().Format(timeLayout)
+
+	response := newElement("samlp:Response").
+		declare("samlp", nsProtocol).
+		attr("ID", responseID).
+		attr("Version", "2.0").
+		attr("IssueInstant", issueInstant).
+		attr("Destination", a.Recipient).
+		attr("InResponseTo", a.InResponseTo).
+		add(
+			newElement("saml:Issuer").declare("saml", nsAssertion).setText(idp.EntityID),
+			newElement("samlp:Status").add(
+				newElement("samlp:StatusCode").attr("Value", statusSuccess),
+			),
+			assertion,
+		)
+
+	return response.bytes(), nil
+}
+
+// assertion returns the assertion signed with enveloped signature.
+func (idp *IdentityProvider) assertion(a Assertion) (*element, error) {
+	id, err := newID()
+	if err != nil {
+		return nil, err
+	}
+
+	issueInstant := a.IssueInstant.UTC().Format(timeLayout)
+	notBefore := a.IssueInstant.Add(-clockSkew).UTC().Format(timeLayout)
+	notOnOrAfter := a.IssueInstant.Add(a.TTL).UTC().Format(timeLayout)
+
+	subject := newElement("saml:Subject").add(
+		newElement("saml:NameID").attr("Format", a.NameIDFormat).setText(a.NameID),
+		newElement("saml:SubjectConfirmation").attr("Method", confBearer).add(
+			newElement("saml:SubjectConfirmationData").
+				attr("InResponseTo", a.InResponseTo).
+				attr("NotOnOrAfter", notOnOrAfter).
+				attr("Recipient", a.Recipient),
+		),
+	)
+
+	conditions := newElement("saml:Conditions").
+		attr("NotBefore", notBefore).
+		attr("NotOnOrAfter", notOnOrAfter).
+		add(
+			newElement("saml:AudienceRestriction").add(
+				newElement("saml:Audience").setText(a.Audience),
+			),
+		)
+
+	authn := newElement("saml:AuthnStatement").
+		attr("AuthnInstant", a.AuthnInstant.UTC().Format(timeLayout)).
+		attr("SessionIndex", a.SessionIndex).
+		add(
+			newElement("saml:AuthnContext").add(
+				newElement("saml:AuthnContextClassRef").setText(authnPassword),
+			),
+		)
+
+	assertion := newElement("saml:Assertion").
+		declare("saml", nsAssertion).
+		attr("ID", id).
+		attr("Version", "2.0").
+		attr("IssueInstant", issueInstant).
+		add(
+			newElement("saml:Issuer").setText(idp.EntityID),
+			subject,
+			conditions,
+			authn,
+		)
+
+	if len(a.Attributes) > 0 {
+		statement := newElement("saml:AttributeStatement")
+		for _, attribute := range a.Attributes {
+			el := newElement("saml:Attribute").attr("Name", attribute.Name).attr("NameFormat", attrNameBasic)
+			for _, value := range attribute.Values {
+				el.add(newElement("saml:AttributeValue").setText(value))
+			}
+			statement.add(el)
+		}
+		assertion.add(statement)
+	}
+
+	signature, err := idp.sign(id, assertion.bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	// Signature goes right after Issuer, the enveloped-signature transform
+	// removes it before digesting.
+	return assertion.insert(1, signature), nil
+}
+
+// sign returns enveloped signature of the canonical element with the ID.
+func (idp *IdentityProvider) sign(id string, canonical []byte) (*element, error) {
+	digest := sha256.Sum256(canonical)
+
+	signedInfo := newElement("ds:SignedInfo").
+		declare("ds", nsSignature).
+		add(
+			newElement("ds:CanonicalizationMethod").attr("Algorithm", algExcC14N),
+			newElement("ds:SignatureMethod").attr("Algorithm", algRSASHA256),
+			newElement("ds:Reference").attr("URI", "#"+id).add(
+				newElement("ds:Transforms").add(
+					newElement("ds:Transform").attr("Algorithm", algEnveloped),
+					newElement("ds:Transform").attr("Algorithm", algExcC14N),
+				),
+				newElement("ds:DigestMethod").attr("Algorithm", algSHA256),
+				newElement("ds:DigestValue").setText(base64.StdEncoding.EncodeToString(digest[:])),
+			),
+		)
+
+	hashed := sha256.Sum256(signedInfo.bytes())
+	sig, err := rsa.SignPKCS1v15(rand.Reader, idp.Key, crypto.SHA256, hashed[:])
+	if err != nil {
+		return nil, err
+	}
+
+	return newElement("ds:Signature").
+		declare("ds", nsSignature).
+		add(
+			signedInfo,
+			newElement("ds:SignatureValue").setText(base64.StdEncoding.EncodeToString(sig)),
+			idp.keyInfo(),
+		), nil
+}
+
+func (idp *IdentityProvider) keyInfo() *element {
+	return newElement("ds:KeyInfo").declare("ds", nsSignature).add(
+		newElement("ds:X509Data").add(
+			newElement("ds:X509Certificate").setText(base64.StdEncoding.EncodeToString(idp.Certificate.Raw)),
+		),
+	)
+}
+
+// newID returns random ID, IDs must not start with a digit.
+func newID() (string, error) {
+	b := make([]byte, 20)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return "_" + hex.EncodeToString(b), nil
+}
@@ -0,0 +1,157 @@
+package samlidp
+
+import (
+	"bytes"
+	"compress/flate"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/xml"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const authnRequest = `<samlp:AuthnRequest xmlns:samlp="urn:oasis:names:tc:SAML:2.0:protocol"
+	xmlns:saml="urn:oasis:names:tc:SAML:2.0:assertion" ID="_req1" Version="2.0"
+	IssueInstant="2024-01-02T03:04:05Z" AssertionConsumerServiceURL="https://sp.example/acs"
+	ProtocolBinding="urn:oasis:names:tc:SAML:2.0:bindings:HTTP-POST">
+	<saml:Issuer>https://sp.example/metadata</saml:Issuer>
+</samlp:AuthnRequest>`
+
+func TestParseRequest(t *testing.T) {
+	want := AuthnRequest{
+		ID:                          "_req1",
+		Issuer:                      "https://sp.example/metadata",
+		IssueInstant:                time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		AssertionConsumerServiceURL: "https://sp.example/acs",
+		ProtocolBinding:             BindingPOST,
+	}
+
+	req, err := ParseRequest(base64.StdEncoding.EncodeToString([]byte(authnRequest)), BindingPOST)
+	require.NoError(t, err)
+	assert.Equal(t, want, req)
+
+	var deflated bytes.Buffer
+	w, err := flate.NewWriter(&deflated, flate.BestCompression)
+	require.NoError(t, err)
+	_, err = w.Write([]byte(authnRequest))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	req, err = ParseRequest(base64.StdEncoding.EncodeToString(deflated.Bytes()), BindingRedirect)
+	require.NoError(t, err)
+	assert.Equal(t, want, req)
+
+	_, err = ParseRequest(base64.StdEncoding.EncodeToString([]byte(authnRequest)), "urn:other")
+	assert.ErrorIs(t, err, ErrUnsupportedBinding)
+
+	_, err = ParseRequest(base64.StdEncoding.EncodeToString([]byte("<AuthnRequest/>")), BindingPOST)
+	assert.ErrorIs(t, err, ErrInvalidRequest)
+
+	_, err = ParseRequest("not base64", BindingPOST)
+	assert.ErrorIs(t, err, ErrInvalidRequest)
+}
+
+func TestResponse(t *testing.T) {
+	key, cert, err := GenerateKeyPair("idp")
+	require.NoError(t, err)
+
+	idp := &IdentityProvider{
+		EntityID:    "https://sso.example/saml/metadata",
+		SSOURL:      "https://sso.example/saml/sso",
+		Key:         key,
+		Certificate: cert,
+	}
+
+	now := time.Now()
+	raw, err := idp.Response(Assertion{
+		InResponseTo: "_req1",
+		Audience:     "https://sp.example/metadata",
+		Recipient:    "https://sp.example/acs",
+		NameID:       "jane@corp.example",
+		NameIDFormat: NameIDFormatEmail,
+		SessionIndex: "_session",
+		Attributes: []Attribute{
+			{Name: "email", Values: []string{"jane@corp.example"}},
+			{Name: "roles", Values: []string{"admin", "a&b <c>"}},
+		},
+		AuthnInstant: now,
+		IssueInstant: now,
+		TTL:          5 * time.Minute,
+	})
+	require.NoError(t, err)
+
+	var response struct {
+		InResponseTo string `xml:"InResponseTo,attr"`
+		Status       struct {
+			StatusCode struct {
+				Value string `xml:"Value,attr"`
+			}
+		}
+		Assertion struct {
+			ID      string `xml:"ID,attr"`
+			Subject struct {
+				NameID string
+			}
+			Conditions struct {
+				Audience string `xml:"AudienceRestriction>Audience"`
+			}
+			Attributes []struct {
+				Name   string   `xml:"Name,attr"`
+				Values []string `xml:"AttributeValue"`
+			} `xml:"AttributeStatement>Attribute"`
+		}
+	}
+	require.NoError(t, xml.Unmarshal(raw, &response))
+	assert.Equal(t, "_req1", response.InResponseTo)
+	assert.Equal(t, statusSuccess, response.Status.StatusCode.Value)
+	assert.Equal(t, "jane@corp.example", response.Assertion.Subject.NameID)
+	assert.Equal(t, "https://sp.example/metadata", response.Assertion.Conditions.Audience)
+	require.Len(t, response.Assertion.Attributes, 2)
+	assert.Equal(t, []string{"admin", "a&b <c>"}, response.Assertion.Attributes[1].Values)
+
+	// The document is rendered canonical, so the signed parts are verified
+	// on the bytes as they are.
+	assertion := regexp.MustCompile(`<saml:Assertion .*</saml:Assertion>`).Find(raw)
+	signature := regexp.MustCompile(`<ds:Signature .*</ds:Signature>`).Find(assertion)
+	signedInfo := regexp.MustCompile(`<ds:SignedInfo .*</ds:SignedInfo>`).Find(signature)
+	require.NotEmpty(t, signedInfo)
+
+	digest := sha256.Sum256(bytes.Replace(assertion, signature, nil, 1))
+	digestValue := regexp.MustCompile(`<ds:DigestValue>(.*)</ds:DigestValue>`).FindSubmatch(signedInfo)[1]
+	assert.Equal(t, base64.StdEncoding.EncodeToString(digest[:]), string(digestValue))
+	assert.Contains(t, string(signedInfo), `URI="#`+response.Assertion.ID+`"`)
+
+	signatureValue := regexp.MustCompile(`<ds:SignatureValue>(.*)</ds:SignatureValue>`).FindSubmatch(signature)[1]
+	sig, err := base64.StdEncoding.DecodeString(string(signatureValue))
+	require.NoError(t, err)
+
+	hashed := sha256.Sum256(signedInfo)
+	assert.NoError(t, rsa.VerifyPKCS1v15(cert.PublicKey.(*rsa.PublicKey), crypto.SHA256, hashed[:], sig))
+}
+
+func TestMetadata(t *testing.T) {
+	key, cert, err := GenerateKeyPair("idp")
+	require.NoError(t, err)
+
+	idp := &IdentityProvider{EntityID: "https://sso.example/saml/metadata", SSOURL: "https://sso.example/saml/sso", Key: key, Certificate: cert}
+
+	var metadata struct {
+		EntityID    string `xml:"entityID,attr"`
+		Certificate string `xml:"IDPSSODescriptor>KeyDescriptor>KeyInfo>X509Data>X509Certificate"`
+		SSO         []struct {
+			Binding  string `xml:"Binding,attr"`
+			Location string `xml:"Location,attr"`
+		} `xml:"IDPSSODescriptor>SingleSignOnService"`
+	}
+	require.NoError(t, xml.Unmarshal(idp.Metadata(), &metadata))
+	assert.Equal(t, idp.EntityID, metadata.EntityID)
+	assert.Equal(t, base64.StdEncoding.EncodeToString(cert.Raw), metadata.Certificate)
+	require.Len(t, metadata.SSO, 2)
+	assert.Equal(t, idp.SSOURL, metadata.SSO[0].Location)
+}
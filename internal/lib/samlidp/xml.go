@@ -0,0 +1,111 @@
+package samlidp
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+)
+
+// element is an XML element rendered in exclusive canonical form
+// (xml-exc-c14n), so signatures over rendered elements verify without
+// canonicalizing them again. Namespaces must be declared on the elements
+// that are signed or that first use them below a signed element.
+type element struct {
+	name     string
+	ns       []attr
+	attrs    []attr
+	children []*element
+	text     string
+}
+
+type attr struct {
+	name, value string
+}
+
+func newElement(name string) *element {
+	return &element{name: name}
+}
+
+// declare declares namespace of the prefix on the element.
+func (e *element) declare(prefix, uri string) *element {
+	e.ns = append(e.ns, attr{name: "xmlns:" + prefix, value: uri})
+	return e
+}
+
+// attr sets unqualified attribute, empty values are omitted.
+func (e *element) attr(name, value string) *element {
+	if value != "" {
+		e.attrs = append(e.attrs, attr{name: name, value: value})
+	}
+	return e
+}
+
+// add appends children to the element.
+func (e *element) add(children ...*element) *element {
+	e.children = append(e.children, children...)
+	return e
+}
+
+// insert inserts child at the position among the children.
+func (e *element) insert(i int, child *element) *element {
+	e.children = slices.Insert(e.children, i, child)
+	return e
+}
+
+// setText sets text content of the element.
+func (e *element) setText(text string) *element {
+	e.text = text
+	return e
+}
+
+// bytes renders the element.
+func (e *element) bytes() []byte {
+	var b bytes.Buffer
+	e.write(&b)
+
+	return b.Bytes()
+}
+
+func (e *element) write(b *bytes.Buffer) {
+	b.WriteByte('<')
+	b.WriteString(e.name)
+
+	// Namespace declarations go first sorted by prefix, then unqualified
+	// attributes sorted by name.
+	for _, list := range [][]attr{e.ns, e.attrs} {
+		sorted := slices.Clone(list)
+		slices.SortFunc(sorted, func(a, b attr) int { return strings.Compare(a.name, b.name) })
+
+		for _, a := range sorted {
+			b.WriteByte(' ')
+			b.WriteString(a.name)
+			b.WriteString(`="`)
+			b.WriteString(attrEscaper.Replace(a.value))
+			b.WriteByte('"')
+		}
+	}
+
+	b.WriteByte('>')
+	b.WriteString(textEscaper.Replace(e.text))
+
+	for _, child := range e.children {
+		child.write(b)
+	}
+
+	// Canonical form has no empty-element tags.
+	b.WriteString("</")
+	b.WriteString(e.name)
+	b.WriteByte('>')
+}
+
+var (
+	textEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", "\r", "&#xD;")
+	attrEscaper = strings.NewReplacer(
+		"&", "&amp;",
+		"<", "&lt;",
+		`"`, "&quot;",
+		"\t", "&#x9;",
+		"\n", "&#xA;",
+		"\r", "&#xD;",
+	)
+)
@@ -0,0 +1,29 @@
+package secrets
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+)
+
+// DefaultSize is the number of random bytes in tokens generated for users.
+const DefaultSize = 32
+
+// Generate returns url safe random string built from size random bytes.
+func Generate(size int) (string, error) {
+	b := make([]byte, size)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// Hash returns hex encoded SHA-256 of the secret. Secrets handed out to
+// users are stored only hashed.
+func Hash(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+
+	return hex.EncodeToString(sum[:])
+}
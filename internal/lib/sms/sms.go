@@ -0,0 +1,128 @@
+package sms
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// Sender delivers text messages to phone numbers.
+type Sender interface {
+	Send(ctx context.Context, to, text string) error
+}
+
+// LogSender writes messages to the log instead of sending them.
+// It's meant for local runs and tests.
+type LogSender struct {
+	log *slog.Logger
+}
+
+func NewLogSender(log *slog.Logger) *LogSender {
+	return &LogSender{log: log}
+}
+
+func (s *LogSender) Send(_ context.Context, to, text string) error {
+	s.log.Info("sms",
+		slog.String("to", to),
+		slog.String("text", text),
+	)
+
+	return nil
+}
+
+// FileSender appends messages to a file, one JSON object per line, for
+// local runs where codes are read by scripts.
+type FileSender struct {
+	path string
+	mu   sync.Mutex
+}
+
+func NewFileSender(path string) *FileSender {
+	return &FileSender{path: path}
+}
+
+func (s *FileSender) Send(_ context.Context, to, text string) error {
+	const op = "sms.FileSender.Send"
+
+	line, err := json.Marshal(struct {
+		message
+		SentAt time.Time `json:"sent_at"`
+	}{message{To: to, Text: text}, time.Now().UTC()})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// WebhookSender posts messages as JSON to a gateway, which delivers them
+// through an SMS provider.
+type WebhookSender struct {
+	url    string
+	token  string
+	client *http.Client
+}
+
+// NewWebhookSender returns sender posting to the URL. Token, if set, is
+// sent as bearer token.
+func NewWebhookSender(url, token string, timeout time.Duration) *WebhookSender {
+	return &WebhookSender{
+		url:    url,
+		token:  token,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+func (s *WebhookSender) Send(ctx context.Context, to, text string) error {
+	const op = "sms.WebhookSender.Send"
+
+	body, err := json.Marshal(message{To: to, Text: text})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.token)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("%s: gateway responded with %s", op, resp.Status)
+	}
+
+	return nil
+}
+
+type message struct {
+	To   string `json:"to"`
+	Text string `json:"text"`
+}
@@ -0,0 +1,27 @@
+package validations
+
+import (
+	"github.com/go-playground/validator/v10"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// APIKeys Handler validations
+
+// ValidateAPIKeyName validates if API key name is set and shorter than 128
+func ValidateAPIKeyName(name string, validate *validator.Validate) error {
+	if err := validate.Var(name, "required,lt=128"); err != nil {
+		return status.Error(codes.InvalidArgument, "name is required and should be shorter than 128")
+	}
+
+	return nil
+}
+
+// ValidateAPIKeyId validates if API key id is set
+func ValidateAPIKeyId(keyID int64, validate *validator.Validate) error {
+	if err := validate.Var(keyID, "required"); err != nil {
+		return status.Error(codes.InvalidArgument, "key_id is required")
+	}
+
+	return nil
+}
@@ -0,0 +1,29 @@
+package validations
+
+import (
+	"github.com/go-playground/validator/v10"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Apps Handler validations
+
+// ValidateAppName validates if app name is set and shorter than 128
+func ValidateAppName(name string, validate *validator.Validate) error {
+	if err := validate.Var(name, "required,lt=128"); err != nil {
+		return status.Error(codes.InvalidArgument, "name is required and should be shorter than 128")
+	}
+
+	return nil
+}
+
+// ValidateRedirectURIs validates if every redirect URI is an absolute URL
+func ValidateRedirectURIs(uris []string, validate *validator.Validate) error {
+	for _, uri := range uris {
+		if err := validate.Var(uri, "url"); err != nil {
+			return status.Error(codes.InvalidArgument, "redirect URI should be an absolute URL: "+uri)
+		}
+	}
+
+	return nil
+}
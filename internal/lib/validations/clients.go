@@ -0,0 +1,71 @@
+package validations
+
+import (
+	ssov1 "github.com/futod4m4/protos/gen/go/sso"
+	"github.com/go-playground/validator/v10"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ClientRegistration Handler validations
+
+// ValidateClientMetadata validates if metadata is set and client name is set and shorter than 128
+func ValidateClientMetadata(metadata *ssov1.ClientMetadata, validate *validator.Validate) error {
+	if metadata == nil {
+		return status.Error(codes.InvalidArgument, "metadata is required")
+	}
+
+	if err := validate.Var(metadata.GetClientName(), "required,lt=128"); err != nil {
+		return status.Error(codes.InvalidArgument, "client_name is required and should be shorter than 128")
+	}
+
+	if err := validate.Var(metadata.GetGrantTypes(), "dive,required"); err != nil {
+		return status.Error(codes.InvalidArgument, "grant types must not be empty")
+	}
+
+	return nil
+}
+
+// ValidateInitialAccessToken validates if initial access token is set
+func ValidateInitialAccessToken(token string, validate *validator.Validate) error {
+	if err := validate.Var(token, "required"); err != nil {
+		return status.Error(codes.Unauthenticated, "initial_access_token is required")
+	}
+
+	return nil
+}
+
+// ValidateRegistrationAccessToken validates if client ID and registration access token are set
+func ValidateRegistrationAccessToken(clientID int32, token string, validate *validator.Validate) error {
+	if err := validate.Var(clientID, "gt=0"); err != nil {
+		return status.Error(codes.InvalidArgument, "client_id is required")
+	}
+
+	if err := validate.Var(token, "required"); err != nil {
+		return status.Error(codes.Unauthenticated, "registration_access_token is required")
+	}
+
+	return nil
+}
+
+// ValidateInitialAccessTokenName validates if name is set and shorter than 128 and max uses is not negative
+func ValidateInitialAccessTokenName(name string, maxUses int32, validate *validator.Validate) error {
+	if err := validate.Var(name, "required,lt=128"); err != nil {
+		return status.Error(codes.InvalidArgument, "name is required and should be shorter than 128")
+	}
+
+	if err := validate.Var(maxUses, "gte=0"); err != nil {
+		return status.Error(codes.InvalidArgument, "max_uses must not be negative")
+	}
+
+	return nil
+}
+
+// ValidateInitialAccessTokenId validates if initial access token id is set
+func ValidateInitialAccessTokenId(id int64, validate *validator.Validate) error {
+	if err := validate.Var(id, "required"); err != nil {
+		return status.Error(codes.InvalidArgument, "id is required")
+	}
+
+	return nil
+}
@@ -0,0 +1,27 @@
+package validations
+
+import (
+	"github.com/go-playground/validator/v10"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Consents Handler validations
+
+// ValidateScopeName validates if scope name is set, shorter than 128 and has no spaces
+func ValidateScopeName(name string, validate *validator.Validate) error {
+	if err := validate.Var(name, "required,lt=128,excludesall= \t\n"); err != nil {
+		return status.Error(codes.InvalidArgument, "name is required, should be shorter than 128 and have no spaces")
+	}
+
+	return nil
+}
+
+// ValidateScopes validates if scopes are set
+func ValidateScopes(scopes []string, validate *validator.Validate) error {
+	if err := validate.Var(scopes, "required,min=1,dive,required"); err != nil {
+		return status.Error(codes.InvalidArgument, "scopes are required")
+	}
+
+	return nil
+}
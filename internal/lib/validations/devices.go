@@ -0,0 +1,36 @@
+package validations
+
+import (
+	"github.com/go-playground/validator/v10"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// DeviceAuthorization Handler validations
+
+// ValidateDeviceScopes validates if scopes, when set, are not empty
+func ValidateDeviceScopes(scopes []string, validate *validator.Validate) error {
+	if err := validate.Var(scopes, "dive,required"); err != nil {
+		return status.Error(codes.InvalidArgument, "scopes must not be empty")
+	}
+
+	return nil
+}
+
+// ValidateDeviceCode validates if device code is set
+func ValidateDeviceCode(deviceCode string, validate *validator.Validate) error {
+	if err := validate.Var(deviceCode, "required"); err != nil {
+		return status.Error(codes.InvalidArgument, "device_code is required")
+	}
+
+	return nil
+}
+
+// ValidateUserCode validates if user code is set and shorter than 32
+func ValidateUserCode(userCode string, validate *validator.Validate) error {
+	if err := validate.Var(userCode, "required,lt=32"); err != nil {
+		return status.Error(codes.InvalidArgument, "user_code is required and should be shorter than 32")
+	}
+
+	return nil
+}
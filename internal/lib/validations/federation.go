@@ -0,0 +1,40 @@
+package validations
+
+import (
+	"github.com/go-playground/validator/v10"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Federation Handler validations
+
+// ValidateStartFederatedLogin validates if provider and app_id are set and
+// scopes, when set, are not empty
+func ValidateStartFederatedLogin(provider string, appId int32, scopes []string, validate *validator.Validate) error {
+	if err := validate.Var(provider, "required"); err != nil {
+		return status.Error(codes.InvalidArgument, "provider is required")
+	}
+
+	if err := ValidateAppId(appId, validate); err != nil {
+		return err
+	}
+
+	if err := validate.Var(scopes, "dive,required"); err != nil {
+		return status.Error(codes.InvalidArgument, "scopes must not be empty")
+	}
+
+	return nil
+}
+
+// ValidateCompleteFederatedLogin validates if state and code are set
+func ValidateCompleteFederatedLogin(state, code string, validate *validator.Validate) error {
+	if err := validate.Var(state, "required"); err != nil {
+		return status.Error(codes.InvalidArgument, "state is required")
+	}
+
+	if err := validate.Var(code, "required"); err != nil {
+		return status.Error(codes.InvalidArgument, "code is required")
+	}
+
+	return nil
+}
@@ -0,0 +1,41 @@
+package validations
+
+import (
+	"github.com/go-playground/validator/v10"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Followers Handler validations
+
+// ValidateFollowPage validates if user_id is set and cursor and limit are
+// not negative
+func ValidateFollowPage(userId, cursor int64, limit int32, validate *validator.Validate) error {
+	if err := ValidateUserId(userId, validate); err != nil {
+		return err
+	}
+
+	return ValidatePage(cursor, limit, validate)
+}
+
+// ValidatePage validates if cursor and limit are not negative
+func ValidatePage(cursor int64, limit int32, validate *validator.Validate) error {
+	if err := validate.Var(cursor, "gte=0"); err != nil {
+		return status.Error(codes.InvalidArgument, "cursor must not be negative")
+	}
+
+	if err := validate.Var(limit, "gte=0"); err != nil {
+		return status.Error(codes.InvalidArgument, "limit must not be negative")
+	}
+
+	return nil
+}
+
+// ValidateUserIds validates if user ids are set and positive
+func ValidateUserIds(userIds []int64, validate *validator.Validate) error {
+	if err := validate.Var(userIds, "required,dive,gt=0"); err != nil {
+		return status.Error(codes.InvalidArgument, "user ids are required and must be positive")
+	}
+
+	return nil
+}
@@ -0,0 +1,35 @@
+package validations
+
+import (
+	"github.com/go-playground/validator/v10"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Groups Handler validations
+
+// ValidateGroupId validates if group_id is set
+func ValidateGroupId(groupId int64, validate *validator.Validate) error {
+	if err := validate.Var(groupId, "required"); err != nil {
+		return status.Error(codes.InvalidArgument, "group_id is required")
+	}
+
+	return nil
+}
+
+// ValidateSubgroup validates if parent_id and child_id are set and differ
+func ValidateSubgroup(parentId, childId int64, validate *validator.Validate) error {
+	if err := validate.Var(parentId, "required"); err != nil {
+		return status.Error(codes.InvalidArgument, "parent_id is required")
+	}
+
+	if err := validate.Var(childId, "required"); err != nil {
+		return status.Error(codes.InvalidArgument, "child_id is required")
+	}
+
+	if err := validate.VarWithValue(childId, parentId, "nefield"); err != nil {
+		return status.Error(codes.InvalidArgument, "group can't be nested into itself")
+	}
+
+	return nil
+}
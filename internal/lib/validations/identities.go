@@ -0,0 +1,27 @@
+package validations
+
+import (
+	"github.com/go-playground/validator/v10"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Identities Handler validations
+
+// ValidateLinkIdentity validates if provider is set
+func ValidateLinkIdentity(provider string, validate *validator.Validate) error {
+	if err := validate.Var(provider, "required"); err != nil {
+		return status.Error(codes.InvalidArgument, "provider is required")
+	}
+
+	return nil
+}
+
+// ValidateIdentityId validates if identity_id is positive
+func ValidateIdentityId(identityId int64, validate *validator.Validate) error {
+	if err := validate.Var(identityId, "gt=0"); err != nil {
+		return status.Error(codes.InvalidArgument, "incorrect identity_id")
+	}
+
+	return nil
+}
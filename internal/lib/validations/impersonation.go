@@ -0,0 +1,27 @@
+package validations
+
+import (
+	"github.com/go-playground/validator/v10"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Impersonation Handler validations
+
+// ValidateImpersonationReason validates if reason is set and shorter than 512
+func ValidateImpersonationReason(reason string, validate *validator.Validate) error {
+	if err := validate.Var(reason, "required,lt=512"); err != nil {
+		return status.Error(codes.InvalidArgument, "reason is required and should be shorter than 512")
+	}
+
+	return nil
+}
+
+// ValidateImpersonationScopes validates if scopes are set
+func ValidateImpersonationScopes(scopes []string, validate *validator.Validate) error {
+	if err := validate.Var(scopes, "required,min=1"); err != nil {
+		return status.Error(codes.InvalidArgument, "scopes are required")
+	}
+
+	return nil
+}
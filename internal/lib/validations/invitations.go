@@ -0,0 +1,45 @@
+package validations
+
+import (
+	ssov1 "github.com/futod4m4/protos/gen/go/sso"
+	"github.com/go-playground/validator/v10"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Invitations Handler validations
+
+// ValidateCreateInvitation validates CreateInvitation Handler
+func ValidateCreateInvitation(req *ssov1.CreateInvitationRequest, validate *validator.Validate) error {
+	if err := ValidateOrganizationId(req.GetOrganizationId(), validate); err != nil {
+		return err
+	}
+
+	if err := validateRegisterEmail(req.GetEmail(), validate); err != nil {
+		return err
+	}
+
+	return ValidateOrganizationRole(req.GetRole(), validate)
+}
+
+// ValidateAcceptInvitation validates AcceptInvitation Handler.
+// Profile of the new user is validated only if it's going to be registered
+func ValidateAcceptInvitation(req *ssov1.AcceptInvitationRequest, register bool, validate *validator.Validate) error {
+	if err := validate.Var(req.GetToken(), "required"); err != nil {
+		return status.Error(codes.InvalidArgument, "token is required")
+	}
+
+	if !register {
+		return nil
+	}
+
+	if err := validateRegisterPassword(req.GetPassword(), validate); err != nil {
+		return err
+	}
+
+	if err := validateRegisterSex(req.GetSex(), validate); err != nil {
+		return err
+	}
+
+	return validateRegisterUsername(req.GetUsername(), validate)
+}
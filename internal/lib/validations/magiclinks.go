@@ -0,0 +1,36 @@
+package validations
+
+import (
+	"github.com/go-playground/validator/v10"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// MagicLinks Handler validations
+
+// ValidateRequestMagicLink validates if email is correct, app_id is set and
+// scopes, when set, are not empty
+func ValidateRequestMagicLink(email string, appId int32, scopes []string, validate *validator.Validate) error {
+	if err := validateLoginEmail(email, validate); err != nil {
+		return err
+	}
+
+	if err := ValidateAppId(appId, validate); err != nil {
+		return err
+	}
+
+	if err := validate.Var(scopes, "dive,required"); err != nil {
+		return status.Error(codes.InvalidArgument, "scopes must not be empty")
+	}
+
+	return nil
+}
+
+// ValidateConsumeMagicLink validates if token and app_id are set
+func ValidateConsumeMagicLink(token string, appId int32, validate *validator.Validate) error {
+	if err := validate.Var(token, "required"); err != nil {
+		return status.Error(codes.InvalidArgument, "token is required")
+	}
+
+	return ValidateAppId(appId, validate)
+}
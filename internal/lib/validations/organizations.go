@@ -0,0 +1,40 @@
+package validations
+
+import (
+	"github.com/go-playground/validator/v10"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Organizations Handler validations
+
+// ValidateOrganizationId validates if organization_id is set
+func ValidateOrganizationId(orgId int64, validate *validator.Validate) error {
+	if err := validate.Var(orgId, "required"); err != nil {
+		return status.Error(codes.InvalidArgument, "organization_id is required")
+	}
+
+	return nil
+}
+
+// ValidateOrganizationName validates if organization name is set and not longer than 100
+func ValidateOrganizationName(name string, validate *validator.Validate) error {
+	if err := validate.Var(name, "required"); err != nil {
+		return status.Error(codes.InvalidArgument, "name is required")
+	}
+
+	if err := validate.Var(name, "max=100"); err != nil {
+		return status.Error(codes.InvalidArgument, "name is too long")
+	}
+
+	return nil
+}
+
+// ValidateOrganizationRole validates if role is one of owner, admin or member
+func ValidateOrganizationRole(role string, validate *validator.Validate) error {
+	if err := validate.Var(role, "oneof=owner admin member"); err != nil {
+		return status.Error(codes.InvalidArgument, "role can be [owner, admin or member]")
+	}
+
+	return nil
+}
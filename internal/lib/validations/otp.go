@@ -0,0 +1,107 @@
+package validations
+
+import (
+	"SSO/internal/domain/models"
+	"github.com/go-playground/validator/v10"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// OTP Handler validations
+
+// ValidateStartOTPLogin validates if email and channel are correct, app_id is
+// set and scopes, when set, are not empty
+func ValidateStartOTPLogin(email, channel string, appId int32, scopes []string, validate *validator.Validate) error {
+	if err := validateLoginEmail(email, validate); err != nil {
+		return err
+	}
+
+	if err := validateOTPChannel(channel, validate); err != nil {
+		return err
+	}
+
+	if err := ValidateAppId(appId, validate); err != nil {
+		return err
+	}
+
+	if err := validate.Var(scopes, "dive,required"); err != nil {
+		return status.Error(codes.InvalidArgument, "scopes must not be empty")
+	}
+
+	return nil
+}
+
+// ValidateSendOTP validates if challenge is set and factor_id is not negative
+func ValidateSendOTP(challenge string, factorId int64, validate *validator.Validate) error {
+	if err := validateOTPChallenge(challenge, validate); err != nil {
+		return err
+	}
+
+	if err := validate.Var(factorId, "gte=0"); err != nil {
+		return status.Error(codes.InvalidArgument, "incorrect factor_id")
+	}
+
+	return nil
+}
+
+// ValidateOTPCode validates if challenge and code are set
+func ValidateOTPCode(challenge, code string, validate *validator.Validate) error {
+	if err := validateOTPChallenge(challenge, validate); err != nil {
+		return err
+	}
+
+	if err := validate.Var(code, "required,numeric"); err != nil {
+		return status.Error(codes.InvalidArgument, "incorrect code")
+	}
+
+	return nil
+}
+
+// ValidateEnrollOTPFactor validates if channel is correct and destination is
+// an email for the email channel or a phone number in E.164 format for sms
+func ValidateEnrollOTPFactor(channel, destination string, validate *validator.Validate) error {
+	if err := validateOTPChannel(channel, validate); err != nil {
+		return err
+	}
+
+	if channel == models.OTPChannelEmail {
+		if err := validate.Var(destination, "required,email"); err != nil {
+			return status.Error(codes.InvalidArgument, "incorrect email")
+		}
+
+		return nil
+	}
+
+	if err := validate.Var(destination, "required,e164"); err != nil {
+		return status.Error(codes.InvalidArgument, "phone number must be in E.164 format")
+	}
+
+	return nil
+}
+
+// ValidateOTPFactorId validates if factor id is positive
+func ValidateOTPFactorId(factorId int64, validate *validator.Validate) error {
+	if err := validate.Var(factorId, "gt=0"); err != nil {
+		return status.Error(codes.InvalidArgument, "incorrect id")
+	}
+
+	return nil
+}
+
+// validateOTPChannel validates if channel is email or sms
+func validateOTPChannel(channel string, validate *validator.Validate) error {
+	if err := validate.Var(channel, "required,oneof=email sms"); err != nil {
+		return status.Error(codes.InvalidArgument, "channel must be email or sms")
+	}
+
+	return nil
+}
+
+// validateOTPChallenge validates if challenge is set
+func validateOTPChallenge(challenge string, validate *validator.Validate) error {
+	if err := validate.Var(challenge, "required"); err != nil {
+		return status.Error(codes.InvalidArgument, "challenge is required")
+	}
+
+	return nil
+}
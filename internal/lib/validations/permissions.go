@@ -0,0 +1,59 @@
+package validations
+
+import (
+	"github.com/go-playground/validator/v10"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Permissions Handler validations
+
+// ValidateAppId validates if app_id is not 0
+func ValidateAppId(appId int32, validate *validator.Validate) error {
+	if err := validate.Var(appId, "ne=0"); err != nil {
+		return status.Error(codes.InvalidArgument, "incorrect app_id")
+	}
+
+	return nil
+}
+
+// ValidateUserId validates if user_id is set
+func ValidateUserId(userId int64, validate *validator.Validate) error {
+	if err := validate.Var(userId, "required"); err != nil {
+		return status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	return nil
+}
+
+// ValidateRoleId validates if role_id is set
+func ValidateRoleId(roleId int64, validate *validator.Validate) error {
+	if err := validate.Var(roleId, "required"); err != nil {
+		return status.Error(codes.InvalidArgument, "role_id is required")
+	}
+
+	return nil
+}
+
+// ValidatePermissionId validates if permission_id is set
+func ValidatePermissionId(permissionId int64, validate *validator.Validate) error {
+	if err := validate.Var(permissionId, "required"); err != nil {
+		return status.Error(codes.InvalidArgument, "permission_id is required")
+	}
+
+	return nil
+}
+
+// ValidateAccessName validates role or permission name: it's required,
+// shorter than 64 and doesn't contain spaces
+func ValidateAccessName(name string, validate *validator.Validate) error {
+	if err := validate.Var(name, "required"); err != nil {
+		return status.Error(codes.InvalidArgument, "name is required")
+	}
+
+	if err := validate.Var(name, "lt=64,excludes= "); err != nil {
+		return status.Error(codes.InvalidArgument, "name should be shorter than 64 and shouldn't contain spaces")
+	}
+
+	return nil
+}
@@ -0,0 +1,40 @@
+package validations
+
+import (
+	ssov1 "github.com/futod4m4/protos/gen/go/sso"
+	"github.com/go-playground/validator/v10"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Policies Handler validations
+
+// ValidatePolicyId validates if policy_id is set
+func ValidatePolicyId(policyId int64, validate *validator.Validate) error {
+	if err := validate.Var(policyId, "required"); err != nil {
+		return status.Error(codes.InvalidArgument, "policy_id is required")
+	}
+
+	return nil
+}
+
+// ValidateAuthorize validates Authorize Handler
+func ValidateAuthorize(req *ssov1.AuthorizeRequest, validate *validator.Validate) error {
+	if err := ValidateAppId(req.GetAppId(), validate); err != nil {
+		return err
+	}
+
+	if err := ValidateUserId(req.GetUserId(), validate); err != nil {
+		return err
+	}
+
+	if err := validate.Var(req.GetAction(), "required"); err != nil {
+		return status.Error(codes.InvalidArgument, "action is required")
+	}
+
+	if err := validate.Var(req.GetResource().GetType(), "required"); err != nil {
+		return status.Error(codes.InvalidArgument, "resource type is required")
+	}
+
+	return nil
+}
@@ -0,0 +1,46 @@
+package validations
+
+import (
+	ssov1 "github.com/futod4m4/protos/gen/go/sso"
+	"github.com/go-playground/validator/v10"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// SAML Handler validations
+
+// ValidateSAMLRequest validates if SAML request is set and binding is
+// HTTP-Redirect or HTTP-POST
+func ValidateSAMLRequest(samlRequest, binding string, validate *validator.Validate) error {
+	if err := validate.Var(samlRequest, "required"); err != nil {
+		return status.Error(codes.InvalidArgument, "saml_request is required")
+	}
+
+	err := validate.Var(
+		binding,
+		"oneof=urn:oasis:names:tc:SAML:2.0:bindings:HTTP-Redirect urn:oasis:names:tc:SAML:2.0:bindings:HTTP-POST",
+	)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, "binding must be HTTP-Redirect or HTTP-POST")
+	}
+
+	return nil
+}
+
+// ValidateServiceProvider validates if provider is set with entity ID and
+// assertion consumer service URLs
+func ValidateServiceProvider(sp *ssov1.ServiceProvider, validate *validator.Validate) error {
+	if sp == nil {
+		return status.Error(codes.InvalidArgument, "provider is required")
+	}
+
+	if err := validate.Var(sp.GetEntityId(), "required"); err != nil {
+		return status.Error(codes.InvalidArgument, "entity_id is required")
+	}
+
+	if err := validate.Var(sp.GetAcsUrls(), "min=1,dive,url"); err != nil {
+		return status.Error(codes.InvalidArgument, "acs_urls must be non-empty list of URLs")
+	}
+
+	return nil
+}
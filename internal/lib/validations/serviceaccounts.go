@@ -0,0 +1,37 @@
+package validations
+
+import (
+	"github.com/go-playground/validator/v10"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ServiceAccounts Handler validations
+
+// ValidateServiceAccountName validates if service account name is a lowercase
+// DNS label shorter than 64, it's used in the email of the account
+func ValidateServiceAccountName(name string, validate *validator.Validate) error {
+	if err := validate.Var(name, "required,lt=64,lowercase,hostname_rfc1123,excludes=."); err != nil {
+		return status.Error(codes.InvalidArgument, "name is required and should be a lowercase DNS label shorter than 64")
+	}
+
+	return nil
+}
+
+// ValidateServiceAccountId validates if service account id is set
+func ValidateServiceAccountId(id int64, validate *validator.Validate) error {
+	if err := validate.Var(id, "required"); err != nil {
+		return status.Error(codes.InvalidArgument, "service_account_id is required")
+	}
+
+	return nil
+}
+
+// ValidateServiceAccountOwner validates if exactly one of app id and organization id is set
+func ValidateServiceAccountOwner(appID int32, orgID int64) error {
+	if (appID == 0) == (orgID == 0) {
+		return status.Error(codes.InvalidArgument, "either app_id or organization_id is required")
+	}
+
+	return nil
+}
@@ -1,6 +1,7 @@
 package validations
 
 import (
+	"SSO/internal/domain/models"
 	ssov1 "github.com/futod4m4/protos/gen/go/sso"
 	"github.com/go-playground/validator/v10"
 	"google.golang.org/grpc/codes"
@@ -22,6 +23,10 @@ func ValidateLogin(req *ssov1.LoginRequest, validate *validator.Validate) error
 		return err
 	}
 
+	if err := validate.Var(req.GetScopes(), "dive,required"); err != nil {
+		return status.Error(codes.InvalidArgument, "scopes must not be empty")
+	}
+
 	return nil
 }
 
@@ -79,6 +84,10 @@ func ValidateRegister(req *ssov1.RegisterRequest, validate *validator.Validate)
 		return err
 	}
 
+	if err := ValidateAppId(req.GetAppId(), validate); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -152,3 +161,25 @@ func ValidateIsUserExists(email string, validate *validator.Validate) error {
 
 	return nil
 }
+
+// ExchangeToken Handler validations
+
+func ValidateExchangeToken(req *ssov1.ExchangeTokenRequest, validate *validator.Validate) error {
+	if err := validate.Var(req.GetSubjectToken(), "required"); err != nil {
+		return status.Error(codes.InvalidArgument, "subject_token is required")
+	}
+
+	if t := req.GetSubjectTokenType(); t != "" && t != models.TokenTypeAccessToken {
+		return status.Error(codes.InvalidArgument, "unsupported subject_token_type")
+	}
+
+	if err := validate.Var(req.GetAppId(), "ne=0"); err != nil {
+		return status.Error(codes.InvalidArgument, "app_id is required")
+	}
+
+	if err := validate.Var(req.GetScopes(), "dive,required"); err != nil {
+		return status.Error(codes.InvalidArgument, "scopes must not be empty")
+	}
+
+	return nil
+}
@@ -0,0 +1,291 @@
+package apikeys
+
+import (
+	"SSO/internal/domain/models"
+	"SSO/internal/lib/jwt"
+	"SSO/internal/lib/secrets"
+	"SSO/internal/storage"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Prefix starts every API key, so that keys are recognizable in requests
+// and leaked keys are easy to find by secret scanners.
+const Prefix = "sso_pat_"
+
+// displayPrefixSize is the number of characters of the key shown to identify it.
+const displayPrefixSize = len(Prefix) + 6
+
+type APIKeys struct {
+	log         *slog.Logger
+	keySaver    KeySaver
+	keyProvider KeyProvider
+	issuer      TokenIssuer
+}
+
+type KeySaver interface {
+	SaveAPIKey(ctx context.Context, key models.APIKey, keyHash string) (models.APIKey, error)
+	TouchAPIKey(ctx context.Context, keyID int64, usedAt time.Time) error
+	DeleteAPIKey(ctx context.Context, userID, keyID int64) error
+}
+
+type KeyProvider interface {
+	APIKeys(ctx context.Context, userID int64) ([]models.APIKey, error)
+	APIKeyByHash(ctx context.Context, keyHash string) (models.APIKey, error)
+}
+
+// TokenIssuer issues tokens and claims carrying access of the key owner.
+type TokenIssuer interface {
+	APIKeyToken(ctx context.Context, key models.APIKey) (string, error)
+	APIKeyClaims(ctx context.Context, key models.APIKey) (jwt.Claims, error)
+}
+
+var (
+	ErrKeyExists     = errors.New("api key with this name already exists")
+	ErrKeyNotFound   = errors.New("api key not found")
+	ErrInvalidKey    = errors.New("invalid api key")
+	ErrInvalidExpiry = errors.New("expiry must be in the future")
+	ErrInvalidScope  = errors.New("invalid scope")
+	ErrKeyFromKey    = errors.New("api keys can't be created with api key")
+	ErrImpersonated  = errors.New("api keys can't be created with impersonation token")
+	ErrScopedToken   = errors.New("api keys can't be created with scoped token")
+	ErrAppNotFound   = errors.New("app not found")
+)
+
+// New returns a new instance of APIKeys service.
+func New(
+	log *slog.Logger,
+	keySaver KeySaver,
+	keyProvider KeyProvider,
+	issuer TokenIssuer,
+) *APIKeys {
+	return &APIKeys{
+		log:         log,
+		keySaver:    keySaver,
+		keyProvider: keyProvider,
+		issuer:      issuer,
+	}
+}
+
+// IsAPIKey reports whether the credential looks like an API key.
+func IsAPIKey(credential string) bool {
+	return strings.HasPrefix(credential, Prefix)
+}
+
+// Create creates API key of the caller for the app the caller's token is
+// issued for. Scopes name roles and permissions of the app the key is
+// limited to, empty scopes give the key full access of the user.
+// Zero expiresAt creates a key that never expires.
+//
+// The returned key is the only place it's shown, only its hash is stored.
+func (k *APIKeys) Create(
+	ctx context.Context,
+	caller jwt.Claims,
+	name string,
+	scopes []string,
+	expiresAt time.Time,
+) (string, models.APIKey, error) {
+	const op = "APIKeys.Create"
+
+	if caller.KeyID != 0 {
+		return "", models.APIKey{}, fmt.Errorf("%s: %w", op, ErrKeyFromKey)
+	}
+
+	if caller.Impersonated() {
+		return "", models.APIKey{}, fmt.Errorf("%s: %w", op, ErrImpersonated)
+	}
+
+	// Keys carry full access of the user or roles and permissions, so a
+	// token restricted by scopes could create a key escaping them.
+	if len(caller.Scopes) != 0 {
+		return "", models.APIKey{}, fmt.Errorf("%s: %w", op, ErrScopedToken)
+	}
+
+	key, apiKey, err := k.CreateFor(ctx, caller.UserID, caller.AppID, name, scopes, expiresAt)
+	if err != nil {
+		return "", models.APIKey{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return key, apiKey, nil
+}
+
+// CreateFor creates API key of the user for the app like Create does.
+// Caller is responsible for checking rights to create it.
+func (k *APIKeys) CreateFor(
+	ctx context.Context,
+	userID int64,
+	appID int,
+	name string,
+	scopes []string,
+	expiresAt time.Time,
+) (string, models.APIKey, error) {
+	const op = "APIKeys.CreateFor"
+
+	log := k.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", userID),
+		slog.Int("app_id", appID),
+	)
+
+	if !expiresAt.IsZero() && !expiresAt.After(time.Now()) {
+		return "", models.APIKey{}, fmt.Errorf("%s: %w", op, ErrInvalidExpiry)
+	}
+
+	scopes, err := normalizeScopes(scopes)
+	if err != nil {
+		return "", models.APIKey{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	secret, err := secrets.Generate(secrets.DefaultSize)
+	if err != nil {
+		return "", models.APIKey{}, fmt.Errorf("%s: %w", op, err)
+	}
+	key := Prefix + secret
+
+	saved, err := k.keySaver.SaveAPIKey(ctx, models.APIKey{
+		UserID:    userID,
+		AppID:     appID,
+		Name:      name,
+		Prefix:    key[:displayPrefixSize],
+		Scopes:    scopes,
+		ExpiresAt: expiresAt,
+	}, secrets.Hash(key))
+	if err != nil {
+		log.Error("failed to save api key", slog.String("error", err.Error()))
+
+		return "", models.APIKey{}, fmt.Errorf("%s: %w", op, mapStorageErr(err))
+	}
+
+	log.Info("api key created", slog.Int64("key_id", saved.ID))
+
+	return key, saved, nil
+}
+
+// Keys returns API keys of the user.
+func (k *APIKeys) Keys(ctx context.Context, userID int64) ([]models.APIKey, error) {
+	const op = "APIKeys.Keys"
+
+	keys, err := k.keyProvider.APIKeys(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return keys, nil
+}
+
+// Revoke deletes API key of the user. Access tokens already exchanged for
+// the key stay valid until they expire.
+func (k *APIKeys) Revoke(ctx context.Context, userID, keyID int64) error {
+	const op = "APIKeys.Revoke"
+
+	log := k.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", userID),
+		slog.Int64("key_id", keyID),
+	)
+
+	if err := k.keySaver.DeleteAPIKey(ctx, userID, keyID); err != nil {
+		log.Error("failed to revoke api key", slog.String("error", err.Error()))
+
+		return fmt.Errorf("%s: %w", op, mapStorageErr(err))
+	}
+
+	log.Info("api key revoked")
+
+	return nil
+}
+
+// Exchange returns access token for API key.
+func (k *APIKeys) Exchange(ctx context.Context, key string) (string, error) {
+	const op = "APIKeys.Exchange"
+
+	apiKey, err := k.use(ctx, key)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	token, err := k.issuer.APIKeyToken(ctx, apiKey)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	return token, nil
+}
+
+// VerifyAPIKey returns claims for request authenticated with API key.
+func (k *APIKeys) VerifyAPIKey(ctx context.Context, key string) (jwt.Claims, error) {
+	const op = "APIKeys.VerifyAPIKey"
+
+	apiKey, err := k.use(ctx, key)
+	if err != nil {
+		return jwt.Claims{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	claims, err := k.issuer.APIKeyClaims(ctx, apiKey)
+	if err != nil {
+		return jwt.Claims{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return claims, nil
+}
+
+// use looks up not expired API key and records its use.
+func (k *APIKeys) use(ctx context.Context, key string) (models.APIKey, error) {
+	if !IsAPIKey(key) {
+		return models.APIKey{}, ErrInvalidKey
+	}
+
+	apiKey, err := k.keyProvider.APIKeyByHash(ctx, secrets.Hash(key))
+	if err != nil {
+		if errors.Is(err, storage.ErrAPIKeyNotFound) {
+			return models.APIKey{}, ErrInvalidKey
+		}
+
+		return models.APIKey{}, err
+	}
+
+	now := time.Now()
+	if apiKey.Expired(now) {
+		return models.APIKey{}, ErrInvalidKey
+	}
+
+	if err := k.keySaver.TouchAPIKey(ctx, apiKey.ID, now); err != nil {
+		k.log.Warn("failed to record api key use", slog.Int64("key_id", apiKey.ID), slog.String("error", err.Error()))
+	}
+
+	return apiKey, nil
+}
+
+// normalizeScopes trims scopes and removes duplicates.
+func normalizeScopes(scopes []string) ([]string, error) {
+	res := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		scope = strings.TrimSpace(scope)
+		if scope == "" {
+			return nil, ErrInvalidScope
+		}
+		if !slices.Contains(res, scope) {
+			res = append(res, scope)
+		}
+	}
+
+	return res, nil
+}
+
+func mapStorageErr(err error) error {
+	switch {
+	case errors.Is(err, storage.ErrAPIKeyExists):
+		return ErrKeyExists
+	case errors.Is(err, storage.ErrAPIKeyNotFound):
+		return ErrKeyNotFound
+	case errors.Is(err, storage.ErrAppNotFound):
+		return ErrAppNotFound
+	}
+
+	return err
+}
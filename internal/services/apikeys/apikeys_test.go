@@ -0,0 +1,126 @@
+package apikeys
+
+import (
+	"SSO/internal/domain/models"
+	"SSO/internal/lib/jwt"
+	"SSO/internal/storage"
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memStorage keeps API keys by hash in memory.
+type memStorage struct {
+	keys   map[string]models.APIKey
+	lastID int64
+}
+
+func (s *memStorage) SaveAPIKey(_ context.Context, key models.APIKey, keyHash string) (models.APIKey, error) {
+	for _, k := range s.keys {
+		if k.UserID == key.UserID && k.Name == key.Name {
+			return models.APIKey{}, storage.ErrAPIKeyExists
+		}
+	}
+	s.lastID++
+	key.ID = s.lastID
+	s.keys[keyHash] = key
+
+	return key, nil
+}
+
+func (s *memStorage) TouchAPIKey(context.Context, int64, time.Time) error {
+	return nil
+}
+
+func (s *memStorage) DeleteAPIKey(_ context.Context, userID, keyID int64) error {
+	for hash, k := range s.keys {
+		if k.UserID == userID && k.ID == keyID {
+			delete(s.keys, hash)
+
+			return nil
+		}
+	}
+
+	return storage.ErrAPIKeyNotFound
+}
+
+func (s *memStorage) APIKeys(_ context.Context, userID int64) ([]models.APIKey, error) {
+	var keys []models.APIKey
+	for _, k := range s.keys {
+		if k.UserID == userID {
+			keys = append(keys, k)
+		}
+	}
+
+	return keys, nil
+}
+
+func (s *memStorage) APIKeyByHash(_ context.Context, keyHash string) (models.APIKey, error) {
+	k, ok := s.keys[keyHash]
+	if !ok {
+		return models.APIKey{}, storage.ErrAPIKeyNotFound
+	}
+
+	return k, nil
+}
+
+// issuer returns claims of the key owner.
+type issuer struct{}
+
+func (issuer) APIKeyToken(context.Context, models.APIKey) (string, error) {
+	return "token", nil
+}
+
+func (issuer) APIKeyClaims(_ context.Context, key models.APIKey) (jwt.Claims, error) {
+	return jwt.Claims{UserID: key.UserID, AppID: key.AppID, KeyID: key.ID}, nil
+}
+
+func newTestService() *APIKeys {
+	s := &memStorage{keys: make(map[string]models.APIKey)}
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	return New(log, s, s, issuer{})
+}
+
+func TestCreate(t *testing.T) {
+	k := newTestService()
+	ctx := context.Background()
+	caller := jwt.Claims{UserID: 1, AppID: 1}
+
+	key, apiKey, err := k.Create(ctx, caller, "ci", []string{" deploy ", "deploy"}, time.Time{})
+	require.NoError(t, err)
+	assert.True(t, IsAPIKey(key))
+	assert.Equal(t, []string{"deploy"}, apiKey.Scopes)
+
+	claims, err := k.VerifyAPIKey(ctx, key)
+	require.NoError(t, err)
+	assert.Equal(t, apiKey.ID, claims.KeyID)
+
+	_, _, err = k.Create(ctx, caller, "ci", nil, time.Time{})
+	assert.ErrorIs(t, err, ErrKeyExists)
+	_, _, err = k.Create(ctx, caller, "old", nil, time.Now().Add(-time.Minute))
+	assert.ErrorIs(t, err, ErrInvalidExpiry)
+}
+
+func TestCreate_RestrictedCaller(t *testing.T) {
+	k := newTestService()
+	ctx := context.Background()
+
+	_, _, err := k.Create(ctx, jwt.Claims{UserID: 1, AppID: 1, KeyID: 1}, "ci", nil, time.Time{})
+	assert.ErrorIs(t, err, ErrKeyFromKey)
+
+	impersonated := jwt.Claims{UserID: 1, AppID: 1, Actor: jwt.Actor{UserID: 2}}
+	_, _, err = k.Create(ctx, impersonated, "ci", nil, time.Time{})
+	assert.ErrorIs(t, err, ErrImpersonated)
+
+	scoped := jwt.Claims{UserID: 1, AppID: 1, Scopes: []string{"profile"}}
+	_, _, err = k.Create(ctx, scoped, "ci", nil, time.Time{})
+	assert.ErrorIs(t, err, ErrScopedToken, "scoped token can't create a key with full access")
+	_, _, err = k.Create(ctx, scoped, "ci", []string{"profile"}, time.Time{})
+	assert.ErrorIs(t, err, ErrScopedToken)
+}
@@ -0,0 +1,520 @@
+package apps
+
+import (
+	"SSO/internal/domain/models"
+	"SSO/internal/lib/secrets"
+	"SSO/internal/storage"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+)
+
+type Apps struct {
+	log         *slog.Logger
+	appSaver    AppSaver
+	appProvider AppProvider
+	adminAppID  int
+	overlap     time.Duration
+}
+
+type AppSaver interface {
+	SaveApp(ctx context.Context, app models.App) (models.App, error)
+	UpdateApp(ctx context.Context, app models.App) error
+	SetAppDisabled(ctx context.Context, appID int, disabled bool) error
+	SetAppConsentRequired(ctx context.Context, appID int, required bool) error
+	UpdateRegistrationRules(ctx context.Context, appID int, rules models.RegistrationRules) error
+	DeleteApp(ctx context.Context, appID int) error
+	RotateAppSecret(ctx context.Context, appID int, secret, kid string, expiresAt time.Time) error
+	DeleteAppSecret(ctx context.Context, appID int, kid string) error
+	SaveTokenExchangeRule(ctx context.Context, rule models.TokenExchangeRule) error
+	DeleteTokenExchangeRule(ctx context.Context, sourceAppID, targetAppID int) error
+}
+
+type AppProvider interface {
+	App(ctx context.Context, appID int) (models.App, error)
+	Apps(ctx context.Context) ([]models.App, error)
+	AppSecrets(ctx context.Context, appID int) ([]models.AppSecret, error)
+	TokenExchangeRules(ctx context.Context, targetAppID int) ([]models.TokenExchangeRule, error)
+}
+
+var (
+	ErrAppExists        = errors.New("app already exists")
+	ErrAppNotFound      = errors.New("app not found")
+	ErrAdminApp         = errors.New("admin app can't be disabled or deleted")
+	ErrInvalidGrantType = errors.New("invalid grant type")
+	ErrSecretNotFound   = errors.New("app secret not found")
+	ErrInvalidRules     = errors.New("invalid registration rules")
+	ErrRuleNotFound     = errors.New("token exchange rule not found")
+	ErrInvalidScope     = errors.New("invalid scope")
+)
+
+// kidSize is the number of random bytes in ids of app secrets.
+const kidSize = 8
+
+// New returns a new instance of Apps service.
+// Admins of the app with adminAppID manage all apps. Rotated secrets are
+// still accepted for verification during overlap.
+func New(
+	log *slog.Logger,
+	appSaver AppSaver,
+	appProvider AppProvider,
+	adminAppID int,
+	overlap time.Duration,
+) *Apps {
+	return &Apps{
+		log:         log,
+		appSaver:    appSaver,
+		appProvider: appProvider,
+		adminAppID:  adminAppID,
+		overlap:     overlap,
+	}
+}
+
+// AdminAppID returns ID of the app whose admins manage all apps.
+func (a *Apps) AdminAppID() int {
+	return a.adminAppID
+}
+
+// CreateApp creates app with a generated secret. The returned app is the
+// only place the secret is shown, it's never returned again.
+// Without grant types the app is allowed the password grant.
+func (a *Apps) CreateApp(ctx context.Context, name string, redirectURIs, grantTypes []string) (models.App, error) {
+	const op = "Apps.CreateApp"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.String("name", name),
+	)
+
+	grantTypes, err := normalizeGrantTypes(grantTypes)
+	if err != nil {
+		return models.App{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	secret, kid, err := newSecret()
+	if err != nil {
+		return models.App{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	app, err := a.appSaver.SaveApp(ctx, models.App{
+		Name:         name,
+		Secret:       secret,
+		SecretKID:    kid,
+		RedirectURIs: redirectURIs,
+		GrantTypes:   grantTypes,
+	})
+	if err != nil {
+		log.Error("failed to save app", slog.String("error", err.Error()))
+
+		return models.App{}, fmt.Errorf("%s: %w", op, mapStorageErr(err))
+	}
+
+	log.Info("app created", slog.Int("app_id", app.ID))
+
+	return app, nil
+}
+
+// UpdateApp replaces name, redirect URIs and grant types of the app.
+func (a *Apps) UpdateApp(ctx context.Context, appID int, name string, redirectURIs, grantTypes []string) error {
+	const op = "Apps.UpdateApp"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int("app_id", appID),
+	)
+
+	grantTypes, err := normalizeGrantTypes(grantTypes)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = a.appSaver.UpdateApp(ctx, models.App{
+		ID:           appID,
+		Name:         name,
+		RedirectURIs: redirectURIs,
+		GrantTypes:   grantTypes,
+	})
+	if err != nil {
+		log.Error("failed to update app", slog.String("error", err.Error()))
+
+		return fmt.Errorf("%s: %w", op, mapStorageErr(err))
+	}
+
+	log.Info("app updated")
+
+	return nil
+}
+
+// App returns the app without its secret.
+func (a *Apps) App(ctx context.Context, appID int) (models.App, error) {
+	const op = "Apps.App"
+
+	app, err := a.appProvider.App(ctx, appID)
+	if err != nil {
+		return models.App{}, fmt.Errorf("%s: %w", op, mapStorageErr(err))
+	}
+
+	app.Secret = ""
+
+	return app, nil
+}
+
+// Apps returns all apps without their secrets.
+func (a *Apps) Apps(ctx context.Context) ([]models.App, error) {
+	const op = "Apps.Apps"
+
+	apps, err := a.appProvider.Apps(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	for i := range apps {
+		apps[i].Secret = ""
+	}
+
+	return apps, nil
+}
+
+// SetDisabled disables or enables the app. Disabled app can't be logged in
+// to and tokens issued for it are no longer accepted.
+func (a *Apps) SetDisabled(ctx context.Context, appID int, disabled bool) error {
+	const op = "Apps.SetDisabled"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int("app_id", appID),
+		slog.Bool("disabled", disabled),
+	)
+
+	if appID == a.adminAppID && disabled {
+		return fmt.Errorf("%s: %w", op, ErrAdminApp)
+	}
+
+	if err := a.appSaver.SetAppDisabled(ctx, appID, disabled); err != nil {
+		log.Error("failed to set app disabled", slog.String("error", err.Error()))
+
+		return fmt.Errorf("%s: %w", op, mapStorageErr(err))
+	}
+
+	log.Info("app disabled state changed")
+
+	return nil
+}
+
+// SetRegistrationRules replaces registration rules of the app.
+// Empty mode means open registration.
+func (a *Apps) SetRegistrationRules(ctx context.Context, appID int, rules models.RegistrationRules) error {
+	const op = "Apps.SetRegistrationRules"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int("app_id", appID),
+	)
+
+	rules, err := normalizeRegistrationRules(rules)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := a.appSaver.UpdateRegistrationRules(ctx, appID, rules); err != nil {
+		log.Error("failed to update registration rules", slog.String("error", err.Error()))
+
+		return fmt.Errorf("%s: %w", op, mapStorageErr(err))
+	}
+
+	log.Info("registration rules updated", slog.String("mode", rules.Mode))
+
+	return nil
+}
+
+// SetConsentRequired sets whether the app must request scopes at login that
+// users granted it. It's set for third-party apps.
+func (a *Apps) SetConsentRequired(ctx context.Context, appID int, required bool) error {
+	const op = "Apps.SetConsentRequired"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int("app_id", appID),
+	)
+
+	if err := a.appSaver.SetAppConsentRequired(ctx, appID, required); err != nil {
+		log.Error("failed to set consent required", slog.String("error", err.Error()))
+
+		return fmt.Errorf("%s: %w", op, mapStorageErr(err))
+	}
+
+	log.Info("consent required changed", slog.Bool("required", required))
+
+	return nil
+}
+
+// DeleteApp deletes the app together with its roles, groups and policies.
+func (a *Apps) DeleteApp(ctx context.Context, appID int) error {
+	const op = "Apps.DeleteApp"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int("app_id", appID),
+	)
+
+	if appID == a.adminAppID {
+		return fmt.Errorf("%s: %w", op, ErrAdminApp)
+	}
+
+	if err := a.appSaver.DeleteApp(ctx, appID); err != nil {
+		log.Error("failed to delete app", slog.String("error", err.Error()))
+
+		return fmt.Errorf("%s: %w", op, mapStorageErr(err))
+	}
+
+	log.Info("app deleted")
+
+	return nil
+}
+
+// RotateSecret generates a new primary secret of the app and returns it with
+// its kid. The secret is shown only once. Tokens signed with the former
+// primary secret are accepted until the overlap window ends.
+func (a *Apps) RotateSecret(ctx context.Context, appID int) (secret string, kid string, err error) {
+	const op = "Apps.RotateSecret"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int("app_id", appID),
+	)
+
+	secret, kid, err = newSecret()
+	if err != nil {
+		return "", "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := a.appSaver.RotateAppSecret(ctx, appID, secret, kid, time.Now().Add(a.overlap)); err != nil {
+		log.Error("failed to rotate secret", slog.String("error", err.Error()))
+
+		return "", "", fmt.Errorf("%s: %w", op, mapStorageErr(err))
+	}
+
+	log.Info("secret rotated", slog.String("kid", kid))
+
+	return secret, kid, nil
+}
+
+// Secrets returns kid of the primary secret of the app and former secrets
+// still accepted for verification, without their values.
+func (a *Apps) Secrets(ctx context.Context, appID int) (primaryKID string, rotated []models.AppSecret, err error) {
+	const op = "Apps.Secrets"
+
+	app, err := a.appProvider.App(ctx, appID)
+	if err != nil {
+		return "", nil, fmt.Errorf("%s: %w", op, mapStorageErr(err))
+	}
+
+	rotated, err = a.appProvider.AppSecrets(ctx, appID)
+	if err != nil {
+		return "", nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	for i := range rotated {
+		rotated[i].Secret = ""
+	}
+
+	return app.SecretKID, rotated, nil
+}
+
+// RevokeSecret ends the overlap window of a former secret of the app
+// before it expires.
+func (a *Apps) RevokeSecret(ctx context.Context, appID int, kid string) error {
+	const op = "Apps.RevokeSecret"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int("app_id", appID),
+		slog.String("kid", kid),
+	)
+
+	if err := a.appSaver.DeleteAppSecret(ctx, appID, kid); err != nil {
+		log.Error("failed to revoke secret", slog.String("error", err.Error()))
+
+		return fmt.Errorf("%s: %w", op, mapStorageErr(err))
+	}
+
+	log.Info("secret revoked")
+
+	return nil
+}
+
+// SetTokenExchangeRule permits exchanging tokens of the source app for
+// tokens of the target app carrying at most the rule scopes, replacing the
+// former rule for the pair of apps. The target app must also allow the
+// token exchange grant type.
+func (a *Apps) SetTokenExchangeRule(ctx context.Context, rule models.TokenExchangeRule) error {
+	const op = "Apps.SetTokenExchangeRule"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int("source_app_id", rule.SourceAppID),
+		slog.Int("target_app_id", rule.TargetAppID),
+	)
+
+	scopes, err := normalizeScopes(rule.Scopes)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	rule.Scopes = scopes
+
+	if err := a.appSaver.SaveTokenExchangeRule(ctx, rule); err != nil {
+		log.Error("failed to save token exchange rule", slog.String("error", err.Error()))
+
+		return fmt.Errorf("%s: %w", op, mapStorageErr(err))
+	}
+
+	log.Info("token exchange rule saved")
+
+	return nil
+}
+
+// TokenExchangeRules returns rules permitting exchange of tokens for
+// tokens of the target app.
+func (a *Apps) TokenExchangeRules(ctx context.Context, targetAppID int) ([]models.TokenExchangeRule, error) {
+	const op = "Apps.TokenExchangeRules"
+
+	rules, err := a.appProvider.TokenExchangeRules(ctx, targetAppID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return rules, nil
+}
+
+// DeleteTokenExchangeRule forbids exchanging tokens of the source app for
+// tokens of the target app. Tokens already exchanged stay valid until they
+// expire.
+func (a *Apps) DeleteTokenExchangeRule(ctx context.Context, sourceAppID, targetAppID int) error {
+	const op = "Apps.DeleteTokenExchangeRule"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int("source_app_id", sourceAppID),
+		slog.Int("target_app_id", targetAppID),
+	)
+
+	if err := a.appSaver.DeleteTokenExchangeRule(ctx, sourceAppID, targetAppID); err != nil {
+		log.Error("failed to delete token exchange rule", slog.String("error", err.Error()))
+
+		return fmt.Errorf("%s: %w", op, mapStorageErr(err))
+	}
+
+	log.Info("token exchange rule deleted")
+
+	return nil
+}
+
+// newSecret generates app secret and its kid.
+func newSecret() (secret string, kid string, err error) {
+	secret, err = secrets.Generate(secrets.DefaultSize)
+	if err != nil {
+		return "", "", err
+	}
+
+	kid, err = secrets.Generate(kidSize)
+	if err != nil {
+		return "", "", err
+	}
+
+	return secret, kid, nil
+}
+
+// normalizeGrantTypes checks grant types and removes duplicates.
+func normalizeGrantTypes(grantTypes []string) ([]string, error) {
+	if len(grantTypes) == 0 {
+		return []string{models.GrantPassword}, nil
+	}
+
+	res := make([]string, 0, len(grantTypes))
+	for _, gt := range grantTypes {
+		if !slices.Contains(models.GrantTypes, gt) {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidGrantType, gt)
+		}
+		if !slices.Contains(res, gt) {
+			res = append(res, gt)
+		}
+	}
+
+	return res, nil
+}
+
+// normalizeRegistrationRules checks registration rules, lowercases email
+// domains and removes duplicates.
+func normalizeRegistrationRules(rules models.RegistrationRules) (models.RegistrationRules, error) {
+	switch rules.Mode {
+	case "":
+		rules.Mode = models.RegistrationOpen
+	case models.RegistrationOpen, models.RegistrationClosed, models.RegistrationInviteOnly:
+	default:
+		return models.RegistrationRules{}, fmt.Errorf("%w: unknown mode %s", ErrInvalidRules, rules.Mode)
+	}
+
+	if rules.MinAge < 0 {
+		return models.RegistrationRules{}, fmt.Errorf("%w: negative min age", ErrInvalidRules)
+	}
+
+	domains := make([]string, 0, len(rules.AllowedEmailDomains))
+	for _, d := range rules.AllowedEmailDomains {
+		d = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(d), "@"))
+		if d == "" || strings.Contains(d, "@") {
+			return models.RegistrationRules{}, fmt.Errorf("%w: invalid email domain %q", ErrInvalidRules, d)
+		}
+		if !slices.Contains(domains, d) {
+			domains = append(domains, d)
+		}
+	}
+	rules.AllowedEmailDomains = domains
+
+	fields := make([]string, 0, len(rules.RequiredFields))
+	for _, f := range rules.RequiredFields {
+		if !slices.Contains(models.ProfileFields, f) {
+			return models.RegistrationRules{}, fmt.Errorf("%w: unknown field %s", ErrInvalidRules, f)
+		}
+		if !slices.Contains(fields, f) {
+			fields = append(fields, f)
+		}
+	}
+	rules.RequiredFields = fields
+
+	return rules, nil
+}
+
+// normalizeScopes trims scopes and removes duplicates.
+func normalizeScopes(scopes []string) ([]string, error) {
+	res := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		scope = strings.TrimSpace(scope)
+		if scope == "" {
+			return nil, ErrInvalidScope
+		}
+		if !slices.Contains(res, scope) {
+			res = append(res, scope)
+		}
+	}
+
+	return res, nil
+}
+
+func mapStorageErr(err error) error {
+	switch {
+	case errors.Is(err, storage.ErrAppExists):
+		return ErrAppExists
+	case errors.Is(err, storage.ErrAppNotFound):
+		return ErrAppNotFound
+	case errors.Is(err, storage.ErrSecretNotFound):
+		return ErrSecretNotFound
+	case errors.Is(err, storage.ErrRuleNotFound):
+		return ErrRuleNotFound
+	}
+
+	return err
+}
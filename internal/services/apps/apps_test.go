@@ -0,0 +1,213 @@
+package apps
+
+import (
+	"SSO/internal/domain/models"
+	"SSO/internal/storage"
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const adminAppID = 1
+
+// memStorage keeps apps and their rotated secrets in memory.
+type memStorage struct {
+	apps    map[int]models.App
+	secrets map[int][]models.AppSecret
+	lastID  int
+}
+
+func (s *memStorage) SaveApp(_ context.Context, app models.App) (models.App, error) {
+	for _, a := range s.apps {
+		if a.Name == app.Name {
+			return models.App{}, storage.ErrAppExists
+		}
+	}
+	s.lastID++
+	app.ID = s.lastID
+	s.apps[app.ID] = app
+
+	return app, nil
+}
+
+func (s *memStorage) UpdateApp(_ context.Context, app models.App) error {
+	stored, ok := s.apps[app.ID]
+	if !ok {
+		return storage.ErrAppNotFound
+	}
+	stored.Name, stored.RedirectURIs, stored.GrantTypes = app.Name, app.RedirectURIs, app.GrantTypes
+	s.apps[app.ID] = stored
+
+	return nil
+}
+
+func (s *memStorage) SetAppDisabled(_ context.Context, appID int, disabled bool) error {
+	return s.update(appID, func(app *models.App) { app.Disabled = disabled })
+}
+
+func (s *memStorage) SetAppConsentRequired(_ context.Context, appID int, required bool) error {
+	return s.update(appID, func(app *models.App) { app.ConsentRequired = required })
+}
+
+func (s *memStorage) UpdateRegistrationRules(_ context.Context, appID int, rules models.RegistrationRules) error {
+	return s.update(appID, func(app *models.App) { app.Registration = rules })
+}
+
+func (s *memStorage) DeleteApp(_ context.Context, appID int) error {
+	if _, ok := s.apps[appID]; !ok {
+		return storage.ErrAppNotFound
+	}
+	delete(s.apps, appID)
+
+	return nil
+}
+
+func (s *memStorage) RotateAppSecret(_ context.Context, appID int, secret, kid string, expiresAt time.Time) error {
+	app, ok := s.apps[appID]
+	if !ok {
+		return storage.ErrAppNotFound
+	}
+	s.secrets[appID] = append(s.secrets[appID], models.AppSecret{
+		AppID:     appID,
+		KID:       app.SecretKID,
+		Secret:    app.Secret,
+		ExpiresAt: expiresAt,
+	})
+	app.Secret, app.SecretKID = secret, kid
+	s.apps[appID] = app
+
+	return nil
+}
+
+func (s *memStorage) DeleteAppSecret(context.Context, int, string) error {
+	return storage.ErrSecretNotFound
+}
+
+func (s *memStorage) SaveTokenExchangeRule(context.Context, models.TokenExchangeRule) error {
+	return nil
+}
+
+func (s *memStorage) DeleteTokenExchangeRule(context.Context, int, int) error {
+	return storage.ErrRuleNotFound
+}
+
+func (s *memStorage) App(_ context.Context, appID int) (models.App, error) {
+	app, ok := s.apps[appID]
+	if !ok {
+		return models.App{}, storage.ErrAppNotFound
+	}
+
+	return app, nil
+}
+
+func (s *memStorage) Apps(context.Context) ([]models.App, error) {
+	var apps []models.App
+	for _, app := range s.apps {
+		apps = append(apps, app)
+	}
+
+	return apps, nil
+}
+
+func (s *memStorage) AppSecrets(_ context.Context, appID int) ([]models.AppSecret, error) {
+	return append([]models.AppSecret(nil), s.secrets[appID]...), nil
+}
+
+func (s *memStorage) TokenExchangeRules(context.Context, int) ([]models.TokenExchangeRule, error) {
+	return nil, nil
+}
+
+func (s *memStorage) update(appID int, change func(app *models.App)) error {
+	app, ok := s.apps[appID]
+	if !ok {
+		return storage.ErrAppNotFound
+	}
+	change(&app)
+	s.apps[appID] = app
+
+	return nil
+}
+
+func newTestService() (*Apps, *memStorage) {
+	s := &memStorage{
+		apps:    map[int]models.App{adminAppID: {ID: adminAppID, Name: "admin", Secret: "admin secret"}},
+		secrets: make(map[int][]models.AppSecret),
+		lastID:  adminAppID,
+	}
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	return New(log, s, s, adminAppID, time.Hour), s
+}
+
+func TestCreateApp_SecretShownOnce(t *testing.T) {
+	a, s := newTestService()
+	ctx := context.Background()
+
+	app, err := a.CreateApp(ctx, "blog", nil, nil)
+	require.NoError(t, err)
+	require.NotEmpty(t, app.Secret)
+	assert.NotEmpty(t, app.SecretKID)
+	assert.Equal(t, []string{models.GrantPassword}, app.GrantTypes, "password grant by default")
+	assert.Equal(t, app.Secret, s.apps[app.ID].Secret)
+
+	_, err = a.CreateApp(ctx, "blog", nil, nil)
+	assert.ErrorIs(t, err, ErrAppExists)
+
+	got, err := a.App(ctx, app.ID)
+	require.NoError(t, err)
+	assert.Empty(t, got.Secret)
+	assert.Equal(t, app.SecretKID, got.SecretKID)
+
+	apps, err := a.Apps(ctx)
+	require.NoError(t, err)
+	require.Len(t, apps, 2)
+	for _, listed := range apps {
+		assert.Empty(t, listed.Secret)
+	}
+}
+
+func TestRotateSecret(t *testing.T) {
+	a, s := newTestService()
+	ctx := context.Background()
+
+	app, err := a.CreateApp(ctx, "blog", nil, nil)
+	require.NoError(t, err)
+
+	secret, kid, err := a.RotateSecret(ctx, app.ID)
+	require.NoError(t, err)
+	assert.NotEqual(t, app.Secret, secret)
+	assert.Equal(t, secret, s.apps[app.ID].Secret)
+
+	primary, rotated, err := a.Secrets(ctx, app.ID)
+	require.NoError(t, err)
+	assert.Equal(t, kid, primary)
+	require.Len(t, rotated, 1)
+	assert.Equal(t, app.SecretKID, rotated[0].KID)
+	assert.Empty(t, rotated[0].Secret, "former secrets are not shown")
+	assert.WithinDuration(t, time.Now().Add(time.Hour), rotated[0].ExpiresAt, time.Minute, "former secret is valid for overlap")
+
+	_, _, err = a.RotateSecret(ctx, 42)
+	assert.ErrorIs(t, err, ErrAppNotFound)
+}
+
+func TestSetDisabled(t *testing.T) {
+	a, s := newTestService()
+	ctx := context.Background()
+
+	app, err := a.CreateApp(ctx, "blog", nil, nil)
+	require.NoError(t, err)
+
+	require.NoError(t, a.SetDisabled(ctx, app.ID, true))
+	assert.True(t, s.apps[app.ID].Disabled)
+	require.NoError(t, a.SetDisabled(ctx, app.ID, false))
+	assert.False(t, s.apps[app.ID].Disabled)
+
+	assert.ErrorIs(t, a.SetDisabled(ctx, adminAppID, true), ErrAdminApp)
+	assert.ErrorIs(t, a.DeleteApp(ctx, adminAppID), ErrAdminApp)
+	assert.ErrorIs(t, a.SetDisabled(ctx, 42, true), ErrAppNotFound)
+}
@@ -0,0 +1,81 @@
+package audit
+
+import (
+	"SSO/internal/domain/models"
+	"context"
+	"fmt"
+	"log/slog"
+)
+
+const (
+	// DefaultLimit is the number of events returned when limit isn't given.
+	DefaultLimit = 50
+	// MaxLimit is the largest number of events returned at once.
+	MaxLimit = 500
+)
+
+type Audit struct {
+	log           *slog.Logger
+	eventSaver    EventSaver
+	eventProvider EventProvider
+}
+
+type EventSaver interface {
+	SaveAuditEvent(ctx context.Context, event models.AuditEvent) error
+}
+
+type EventProvider interface {
+	AuditEvents(ctx context.Context, filter models.AuditFilter) ([]models.AuditEvent, error)
+}
+
+// New returns a new instance of Audit service.
+func New(
+	log *slog.Logger,
+	eventSaver EventSaver,
+	eventProvider EventProvider,
+) *Audit {
+	return &Audit{
+		log:           log,
+		eventSaver:    eventSaver,
+		eventProvider: eventProvider,
+	}
+}
+
+// Record appends event to the audit trail. Callers must not proceed with
+// the audited action if it fails.
+func (a *Audit) Record(ctx context.Context, event models.AuditEvent) error {
+	const op = "Audit.Record"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.String("action", event.Action),
+		slog.Int("app_id", event.AppID),
+		slog.Int64("actor_id", event.ActorID),
+		slog.Int64("user_id", event.UserID),
+	)
+
+	if err := a.eventSaver.SaveAuditEvent(ctx, event); err != nil {
+		log.Error("failed to record audit event", slog.String("error", err.Error()))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// Events returns audit events matching the filter, newest first.
+func (a *Audit) Events(ctx context.Context, filter models.AuditFilter) ([]models.AuditEvent, error) {
+	const op = "Audit.Events"
+
+	if filter.Limit <= 0 {
+		filter.Limit = DefaultLimit
+	}
+	filter.Limit = min(filter.Limit, MaxLimit)
+
+	events, err := a.eventProvider.AuditEvents(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return events, nil
+}
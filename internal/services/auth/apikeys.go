@@ -0,0 +1,87 @@
+package auth
+
+import (
+	"SSO/internal/domain/models"
+	"SSO/internal/lib/jwt"
+	"SSO/internal/storage"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+)
+
+// APIKeyToken issues access token for API key. The token carries access
+// of the key owner in the app of the key, limited by scopes of the key.
+func (a *Auth) APIKeyToken(ctx context.Context, key models.APIKey) (string, error) {
+	const op = "Auth.APIKeyToken"
+
+	user, app, opts, err := a.apiKeyAccess(ctx, key)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	token, err := jwt.NewToken(user, app, a.tokenTTL, opts...)
+	if err != nil {
+		a.log.Error("failed to create token", slog.String("error", err.Error()))
+
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	return token, nil
+}
+
+// APIKeyClaims returns claims of a token APIKeyToken would issue, for
+// requests authenticated with API key directly.
+func (a *Auth) APIKeyClaims(ctx context.Context, key models.APIKey) (jwt.Claims, error) {
+	const op = "Auth.APIKeyClaims"
+
+	user, app, opts, err := a.apiKeyAccess(ctx, key)
+	if err != nil {
+		return jwt.Claims{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	claims, err := jwt.NewClaims(user, app, a.tokenTTL, opts...)
+	if err != nil {
+		return jwt.Claims{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return claims, nil
+}
+
+func (a *Auth) apiKeyAccess(ctx context.Context, key models.APIKey) (models.User, models.App, []jwt.Option, error) {
+	user, err := a.usrProvider.UserByID(ctx, key.UserID)
+	if err != nil {
+		if errors.Is(err, storage.ErrUserNotFound) {
+			return models.User{}, models.App{}, nil, ErrUserNotFound
+		}
+
+		return models.User{}, models.App{}, nil, err
+	}
+
+	app, err := a.appProvider.App(ctx, key.AppID)
+	if err != nil {
+		if errors.Is(err, storage.ErrAppNotFound) {
+			return models.User{}, models.App{}, nil, ErrInvalidAppID
+		}
+
+		return models.User{}, models.App{}, nil, err
+	}
+
+	if err := checkApp(app, models.GrantAPIKey); err != nil {
+		return models.User{}, models.App{}, nil, err
+	}
+
+	opts, err := a.accessOptions(ctx, user.ID, app, key.Scopes)
+	if err != nil {
+		return models.User{}, models.App{}, nil, err
+	}
+
+	principal, err := a.principalOptions(ctx, user, app.ID)
+	if err != nil {
+		return models.User{}, models.App{}, nil, err
+	}
+
+	opts = append(opts, principal...)
+
+	return user, app, append(opts, jwt.WithAPIKey(key.ID, key.Scopes)), nil
+}
@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"golang.org/x/crypto/bcrypt"
 	"log/slog"
+	"slices"
 	"time"
 )
 
@@ -17,7 +18,19 @@ type Auth struct {
 	usrSaver    UserSaver
 	usrProvider UserProvider
 	appProvider AppProvider
-	tokenTTL    time.Duration
+	accProvider AccessProvider
+	orgProvider OrganizationProvider
+	idSaver     IdentitySaver
+	idProvider  IdentityProvider
+	verifier    CredentialVerifier
+	otpFactors  OTPFactorProvider
+	challenges  OTPChallengeSaver
+	// challengeTTL is how long users have to confirm login with second factor.
+	challengeTTL time.Duration
+	// resendInterval is how long to wait before another login of the user
+	// with second factor, as before sending another code.
+	resendInterval time.Duration
+	tokenTTL       time.Duration
 }
 
 type UserSaver interface {
@@ -34,35 +47,117 @@ type UserSaver interface {
 
 type UserProvider interface {
 	User(ctx context.Context, email string) (models.User, error)
+	UserByID(ctx context.Context, userID int64) (models.User, error)
+	ServiceAccount(ctx context.Context, id int64) (models.ServiceAccount, error)
 	IsAdmin(ctx context.Context, userID int64) (bool, error)
 	IsExists(ctx context.Context, email string) (bool, error)
 }
 
 type AppProvider interface {
 	App(ctx context.Context, appID int) (models.App, error)
+	AppSecret(ctx context.Context, appID int, kid string) (models.AppSecret, error)
+	AppSecrets(ctx context.Context, appID int) ([]models.AppSecret, error)
+	TokenExchangeRule(ctx context.Context, sourceAppID, targetAppID int) (models.TokenExchangeRule, error)
+	AppScopes(ctx context.Context, appID int) ([]models.Scope, error)
+}
+
+type AccessProvider interface {
+	UserRoles(ctx context.Context, userID int64, appID int) ([]models.Role, error)
+	UserPermissions(ctx context.Context, userID int64, appID int) ([]string, error)
+	UserGroups(ctx context.Context, userID int64, appID int) ([]models.Group, error)
+	Consent(ctx context.Context, userID int64, appID int) (models.Consent, error)
+}
+
+type OrganizationProvider interface {
+	Member(ctx context.Context, orgID, userID int64) (models.OrganizationMember, error)
+	HasAppAccess(ctx context.Context, orgID int64, appID int) (bool, error)
+}
+
+type IdentitySaver interface {
+	SaveIdentity(ctx context.Context, identity models.Identity) (models.Identity, error)
+	TouchIdentity(ctx context.Context, id int64, email string, at time.Time) error
+}
+
+type IdentityProvider interface {
+	Identity(ctx context.Context, provider, subject string) (models.Identity, error)
+}
+
+// CredentialVerifier verifies passwords of users kept in an external
+// directory, such as LDAP.
+type CredentialVerifier interface {
+	// Name identifies the directory in linked identities.
+	Name() string
+	// Verify returns ID of the user in the directory and the user mapped
+	// from it, or empty ID if the credentials are invalid.
+	Verify(ctx context.Context, login, password string) (subject string, user models.User, err error)
+}
+
+// OTPFactorProvider returns one-time passcode factors users confirm
+// login with.
+type OTPFactorProvider interface {
+	OTPFactors(ctx context.Context, userID int64) ([]models.OTPFactor, error)
+}
+
+// OTPChallengeSaver saves second factor challenges. Saving a challenge
+// voids former ones of the user with the same purpose.
+type OTPChallengeSaver interface {
+	SaveOTPChallenge(ctx context.Context, challenge models.OTPChallenge, tokenHash string) error
+	LastOTPSentAt(ctx context.Context, userID int64, purpose string) (time.Time, error)
 }
 
 var (
-	ErrInvalidCredentials = errors.New("invalid credentials")
-	ErrInvalidAppID       = errors.New("invalid app_id")
-	ErrUserExists         = errors.New("user already exists")
-	ErrUserNotFound       = errors.New("user not found")
+	ErrInvalidCredentials   = errors.New("invalid credentials")
+	ErrInvalidAppID         = errors.New("invalid app_id")
+	ErrUserExists           = errors.New("user already exists")
+	ErrUserNotFound         = errors.New("user not found")
+	ErrInvalidToken         = errors.New("invalid token")
+	ErrNotOrgMember         = errors.New("user is not a member of the organization")
+	ErrOrgAppNotAllowed     = errors.New("organization has no access to the app")
+	ErrAppDisabled          = errors.New("app is disabled")
+	ErrGrantNotAllowed      = errors.New("grant type is not allowed for the app")
+	ErrAccountDisabled      = errors.New("service account is disabled")
+	ErrAccountAppNotAllowed = errors.New("service account has no access to the app")
+	ErrNotImpersonable      = errors.New("service accounts can't be impersonated")
+	ErrExchangeNotAllowed   = errors.New("token exchange is not allowed for the apps")
+	ErrInvalidScope         = errors.New("scope is not allowed")
+	ErrScopeRequired        = errors.New("app must request scopes")
+	ErrUnknownScope         = errors.New("scope is not defined for the app")
+	ErrConsentRequired      = errors.New("user hasn't consented to the scopes")
 )
 
-// New returns a new instance of Auth service.
+// New returns a new instance of Auth service. Verifier is nil if users
+// are kept only locally.
 func New(
 	log *slog.Logger,
 	userSaver UserSaver,
 	userProvider UserProvider,
 	appProvider AppProvider,
+	accessProvider AccessProvider,
+	orgProvider OrganizationProvider,
+	identitySaver IdentitySaver,
+	identityProvider IdentityProvider,
+	verifier CredentialVerifier,
+	otpFactors OTPFactorProvider,
+	challenges OTPChallengeSaver,
+	challengeTTL time.Duration,
+	resendInterval time.Duration,
 	tokenTTL time.Duration,
 ) *Auth {
 	return &Auth{
-		log:         log,
-		usrSaver:    userSaver,
-		usrProvider: userProvider,
-		appProvider: appProvider,
-		tokenTTL:    tokenTTL,
+		log:            log,
+		usrSaver:       userSaver,
+		usrProvider:    userProvider,
+		appProvider:    appProvider,
+		accProvider:    accessProvider,
+		orgProvider:    orgProvider,
+		idSaver:        identitySaver,
+		idProvider:     identityProvider,
+		verifier:       verifier,
+		otpFactors:     otpFactors,
+		challenges:     challenges,
+		challengeTTL:   challengeTTL,
+		resendInterval: resendInterval,
+		tokenTTL:       tokenTTL,
 	}
 }
 
@@ -70,11 +165,28 @@ func New(
 //
 // If user exists, but password is incorrect, returns error.
 // If user doesn't exist, returns error
+//
+// Users without local password, including ones not registered yet, are
+// verified against the directory if there is one; see directoryUser.
+//
+// Users with one-time passcode factors must confirm login with a passcode:
+// Login returns *SecondFactorRequiredError instead of the token, which is
+// issued by OTPToken once the challenge is passed.
+//
+// If orgID is not 0, user logs in within the organization: user must be
+// its member, organization must have access to the app, and the token
+// carries organization id and user role there.
+//
+// Scopes the app requests must be defined for it and, if the app requires
+// consent, granted by the user. The token carries the scopes and only the
+// roles and permissions named in them.
 func (a *Auth) Login(
 	ctx context.Context,
 	email string,
 	password string,
 	appID int,
+	orgID int64,
+	scopes []string,
 ) (string, error) {
 	const op = "auth.Login"
 
@@ -86,34 +198,46 @@ func (a *Auth) Login(
 	log.Info("attempting to login user")
 
 	user, err := a.usrProvider.User(ctx, email)
-	if err != nil {
-		if errors.Is(err, storage.ErrUserNotFound) {
-			a.log.Warn("user not found", slog.StringValue(err.Error()))
-
-			return "", fmt.Errorf("%s: %w", op, ErrInvalidCredentials)
-		}
-
-		a.log.Error("failed to get user", slog.StringValue(err.Error()))
+	if err != nil && !errors.Is(err, storage.ErrUserNotFound) {
+		a.log.Error("failed to get user", slog.String("error", err.Error()))
 
 		return "", fmt.Errorf("%s: %w", op, err)
 	}
+	found := err == nil
 
-	if err := bcrypt.CompareHashAndPassword(user.PassHash, []byte(password)); err != nil {
-		a.log.Info("invalid credentials", slog.StringValue(err.Error()))
+	if found && user.IsServiceAccount() {
+		log.Warn("service account tried to log in with password", slog.Int64("user_id", user.ID))
 
 		return "", fmt.Errorf("%s: %w", op, ErrInvalidCredentials)
 	}
 
-	app, err := a.appProvider.App(ctx, appID)
+	if found && user.HasPassword() {
+		if err := bcrypt.CompareHashAndPassword(user.PassHash, []byte(password)); err != nil {
+			a.log.Info("invalid credentials", slog.String("error", err.Error()))
+
+			return "", fmt.Errorf("%s: %w", op, ErrInvalidCredentials)
+		}
+	} else {
+		user, err = a.directoryUser(ctx, log, email, password)
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	app, opts, err := a.loginOptions(ctx, log, user, appID, models.GrantPassword, orgID, scopes)
 	if err != nil {
 		return "", fmt.Errorf("%s: %w", op, err)
 	}
 
+	if err := a.requireSecondFactor(ctx, log, user.ID, app.ID, orgID, models.GrantPassword, scopes); err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
 	log.Info("user logged in successfully")
 
-	token, err := jwt.NewToken(user, app, a.tokenTTL)
+	token, err := jwt.NewToken(user, app, a.tokenTTL, opts...)
 	if err != nil {
-		a.log.Error("failed to create token", slog.StringValue(err.Error()))
+		a.log.Error("failed to create token", slog.String("error", err.Error()))
 
 		return "", fmt.Errorf("%s: %w", op, err)
 	}
@@ -121,9 +245,62 @@ func (a *Auth) Login(
 	return token, nil
 }
 
+// loginOptions checks that the user may log in to the app with the grant
+// type, scopes and, if orgID is not 0, within the organization, and returns
+// the app and options of the token to issue.
+func (a *Auth) loginOptions(
+	ctx context.Context,
+	log *slog.Logger,
+	user models.User,
+	appID int,
+	grantType string,
+	orgID int64,
+	scopes []string,
+) (models.App, []jwt.Option, error) {
+	app, err := a.appProvider.App(ctx, appID)
+	if err != nil {
+		return models.App{}, nil, err
+	}
+
+	if err := checkApp(app, grantType); err != nil {
+		log.Warn("login refused", slog.Int("app_id", app.ID), slog.String("error", err.Error()))
+
+		return models.App{}, nil, err
+	}
+
+	if err := a.checkScopes(ctx, user.ID, app, scopes); err != nil {
+		log.Warn("login refused", slog.Int("app_id", app.ID), slog.String("error", err.Error()))
+
+		return models.App{}, nil, err
+	}
+
+	opts, err := a.accessOptions(ctx, user.ID, app, scopes)
+	if err != nil {
+		a.log.Error("failed to get user access", slog.String("error", err.Error()))
+
+		return models.App{}, nil, err
+	}
+	opts = append(opts, jwt.WithScopes(scopes))
+
+	if orgID != 0 {
+		member, err := a.orgMember(ctx, orgID, user.ID, app.ID)
+		if err != nil {
+			log.Warn("login within organization refused", slog.Int64("organization_id", orgID), slog.String("error", err.Error()))
+
+			return models.App{}, nil, err
+		}
+
+		opts = append(opts, jwt.WithOrganization(orgID, member.Role))
+	}
+
+	return app, opts, nil
+}
+
 // RegisterNewUser checks if user with given credentials exists in the system
 // If user exists, but password is incorrect, returns error.
 // If user doesn't exist, returns error
+//
+// User registers for the app and must meet its registration rules.
 func (a *Auth) RegisterNewUser(
 	ctx context.Context,
 	email,
@@ -132,27 +309,54 @@ func (a *Auth) RegisterNewUser(
 	sex,
 	location,
 	dateOfBirth string,
+	appID int,
 ) (int64, error) {
 	const op = "auth.RegisterNewUser"
 
 	log := a.log.With(
 		slog.String("op", op),
+		slog.Int("app_id", appID),
 	)
 
 	log.Info("registering user")
 
+	app, err := a.appProvider.App(ctx, appID)
+	if err != nil {
+		if errors.Is(err, storage.ErrAppNotFound) {
+			return 0, fmt.Errorf("%s: %w", op, ErrInvalidAppID)
+		}
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if app.Disabled {
+		return 0, fmt.Errorf("%s: %w", op, ErrAppDisabled)
+	}
+
+	err = checkRegistration(app.Registration, Profile{
+		Email:       email,
+		Username:    username,
+		Location:    location,
+		DateOfBirth: dateOfBirth,
+	}, time.Now())
+	if err != nil {
+		log.Warn("registration rejected", slog.String("error", err.Error()))
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
 	passHash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 	if err != nil {
-		log.Error("failed to generate password hash", slog.StringValue(err.Error()))
+		log.Error("failed to generate password hash", slog.String("error", err.Error()))
 
 		return 0, fmt.Errorf("%s: %w", op, err)
 	}
 
 	id, err := a.usrSaver.SaveUser(ctx, email, passHash, username, sex, location, dateOfBirth)
 	if err != nil {
-		log.Error("failed to save user", slog.StringValue(err.Error()))
+		log.Error("failed to save user", slog.String("error", err.Error()))
 		if errors.Is(err, storage.ErrUserExists) {
-			log.Warn("user already exists", slog.StringValue(err.Error()))
+			log.Warn("user already exists", slog.String("error", err.Error()))
 
 			return 0, fmt.Errorf("%s: %w", op, ErrUserExists)
 		}
@@ -165,9 +369,107 @@ func (a *Auth) RegisterNewUser(
 	return id, nil
 }
 
-// IsAdmin checks if user with given credentials exists in the system
+// checkApp checks that app is enabled and allowed to use grant type.
+func checkApp(app models.App, grantType string) error {
+	if app.Disabled {
+		return ErrAppDisabled
+	}
+
+	if !app.AllowsGrant(grantType) {
+		return ErrGrantNotAllowed
+	}
+
+	return nil
+}
+
+// accessOptions returns options adding roles, permissions and, if the app
+// asks for them, groups the user holds in the app. Non-empty scopes limit
+// roles and permissions to the ones named in scopes.
+func (a *Auth) accessOptions(ctx context.Context, userID int64, app models.App, scopes []string) ([]jwt.Option, error) {
+	roles, permissions, err := a.userAccess(ctx, userID, app.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(scopes) > 0 {
+		roles = slices.DeleteFunc(roles, func(r string) bool { return !slices.Contains(scopes, r) })
+		permissions = slices.DeleteFunc(permissions, func(p string) bool { return !slices.Contains(scopes, p) })
+	}
+
+	opts := []jwt.Option{jwt.WithAccess(roles, permissions)}
+
+	if app.GroupClaims {
+		groups, err := a.userGroups(ctx, userID, app.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		opts = append(opts, jwt.WithGroups(groups))
+	}
+
+	return opts, nil
+}
+
+// userAccess returns names of the roles and permissions user holds in the app.
+func (a *Auth) userAccess(ctx context.Context, userID int64, appID int) ([]string, []string, error) {
+	roles, err := a.accProvider.UserRoles(ctx, userID, appID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	permissions, err := a.accProvider.UserPermissions(ctx, userID, appID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	roleNames := make([]string, 0, len(roles))
+	for _, role := range roles {
+		roleNames = append(roleNames, role.Name)
+	}
+
+	return roleNames, permissions, nil
+}
+
+// userGroups returns names of the groups user belongs to in the app.
+func (a *Auth) userGroups(ctx context.Context, userID int64, appID int) ([]string, error) {
+	groups, err := a.accProvider.UserGroups(ctx, userID, appID)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(groups))
+	for _, group := range groups {
+		names = append(names, group.Name)
+	}
+
+	return names, nil
+}
+
+// orgMember returns membership of user in organization which has access to the app.
+func (a *Auth) orgMember(ctx context.Context, orgID, userID int64, appID int) (models.OrganizationMember, error) {
+	member, err := a.orgProvider.Member(ctx, orgID, userID)
+	if err != nil {
+		if errors.Is(err, storage.ErrMemberNotFound) {
+			return models.OrganizationMember{}, ErrNotOrgMember
+		}
+
+		return models.OrganizationMember{}, err
+	}
+
+	allowed, err := a.orgProvider.HasAppAccess(ctx, orgID, appID)
+	if err != nil {
+		return models.OrganizationMember{}, err
+	}
+
+	if !allowed {
+		return models.OrganizationMember{}, ErrOrgAppNotAllowed
+	}
+
+	return member, nil
+}
+
+// IsAdmin checks if user holds the admin role in any app.
 //
-// If user exists, but password is incorrect, returns error.
 // If user doesn't exist, returns error
 func (a *Auth) IsAdmin(
 	ctx context.Context,
@@ -182,10 +484,10 @@ func (a *Auth) IsAdmin(
 
 	isAdmin, err := a.usrProvider.IsAdmin(ctx, userID)
 	if err != nil {
-		if errors.Is(err, storage.ErrAppNotFound) {
-			log.Warn("user not found", slog.StringValue(err.Error()))
+		if errors.Is(err, storage.ErrUserNotFound) {
+			log.Warn("user not found", slog.String("error", err.Error()))
 
-			return false, fmt.Errorf("%s: %w", ErrInvalidAppID)
+			return false, fmt.Errorf("%s: %w", op, ErrUserNotFound)
 		}
 		return false, fmt.Errorf("%s: %w", op, err)
 	}
@@ -211,7 +513,7 @@ func (a *Auth) IsUserExists(
 	isExists, err := a.usrProvider.IsExists(ctx, email)
 	if err != nil {
 		if errors.Is(err, storage.ErrUserNotFound) {
-			log.Info("user not found", slog.StringValue(err.Error()))
+			log.Info("user not found", slog.String("error", err.Error()))
 
 			return isExists, err
 		}
@@ -221,3 +523,90 @@ func (a *Auth) IsUserExists(
 
 	return isExists, err
 }
+
+// VerifyToken checks that token was issued by Login for an existing app
+// and hasn't expired yet.
+//
+// Token is verified with the app secret its kid header points to: the
+// primary one or a rotated one still within its overlap window. Tokens
+// without kid are verified with the primary secret and, if it doesn't
+// match, with the rotated ones still within their overlap window.
+func (a *Auth) VerifyToken(
+	ctx context.Context,
+	token string,
+) (jwt.Claims, error) {
+	const op = "Auth.VerifyToken"
+
+	var (
+		appID int
+		kid   string
+	)
+	claims, err := jwt.ParseToken(token, func(tokenAppID int, tokenKID string) (string, error) {
+		appID, kid = tokenAppID, tokenKID
+
+		return a.tokenSecret(ctx, appID, kid)
+	})
+	if kid == "" && errors.Is(err, jwt.ErrInvalidSignature) {
+		claims, err = a.verifyWithRotated(ctx, token, appID)
+	}
+	if err != nil {
+		if errors.Is(err, jwt.ErrInvalidToken) {
+			return jwt.Claims{}, fmt.Errorf("%s: %w", op, ErrInvalidToken)
+		}
+
+		return jwt.Claims{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return claims, nil
+}
+
+// tokenSecret returns the secret of the enabled app kid points to, the
+// primary one if kid is empty.
+func (a *Auth) tokenSecret(ctx context.Context, appID int, kid string) (string, error) {
+	app, err := a.appProvider.App(ctx, appID)
+	if err != nil {
+		return "", err
+	}
+
+	if app.Disabled {
+		return "", ErrAppDisabled
+	}
+
+	if kid == "" || kid == app.SecretKID {
+		return app.Secret, nil
+	}
+
+	secret, err := a.appProvider.AppSecret(ctx, appID, kid)
+	if err != nil {
+		return "", err
+	}
+
+	return secret.Secret, nil
+}
+
+// verifyWithRotated verifies token without kid with rotated secrets of the
+// app still within their overlap window, most recently rotated first.
+func (a *Auth) verifyWithRotated(ctx context.Context, token string, appID int) (jwt.Claims, error) {
+	rotated, err := a.appProvider.AppSecrets(ctx, appID)
+	if err != nil {
+		return jwt.Claims{}, err
+	}
+
+	for _, r := range rotated {
+		secret, err := a.appProvider.AppSecret(ctx, appID, r.KID)
+		if err != nil {
+			if errors.Is(err, storage.ErrSecretNotFound) {
+				continue
+			}
+
+			return jwt.Claims{}, err
+		}
+
+		claims, err := jwt.ParseToken(token, func(int, string) (string, error) { return secret.Secret, nil })
+		if !errors.Is(err, jwt.ErrInvalidSignature) {
+			return claims, err
+		}
+	}
+
+	return jwt.Claims{}, jwt.ErrInvalidSignature
+}
@@ -0,0 +1,419 @@
+package auth
+
+import (
+	"SSO/internal/domain/models"
+	"SSO/internal/lib/secrets"
+	"SSO/internal/storage"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"slices"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	testEmail    = "user@example.com"
+	testPassword = "password"
+)
+
+// memStorage keeps a single user and apps in memory.
+type memStorage struct {
+	user    models.User
+	apps    map[int]models.App
+	members map[int64]models.OrganizationMember
+	// orgApps are apps organizations have access to.
+	orgApps map[int64][]int
+	// rotated are former secrets of apps still within their overlap window.
+	rotated map[int][]models.AppSecret
+	// roles and groups the user holds in any app, directly or through groups.
+	roles  []models.Role
+	groups []models.Group
+	// factors and challenges of one-time passcodes of the user.
+	factors    []models.OTPFactor
+	challenges map[string]models.OTPChallenge
+	// rules of token exchange between apps.
+	rules []models.TokenExchangeRule
+}
+
+func (s *memStorage) User(_ context.Context, email string) (models.User, error) {
+	if email != s.user.Email {
+		return models.User{}, storage.ErrUserNotFound
+	}
+
+	return s.user, nil
+}
+
+func (s *memStorage) UserByID(_ context.Context, userID int64) (models.User, error) {
+	if userID != s.user.ID {
+		return models.User{}, storage.ErrUserNotFound
+	}
+
+	return s.user, nil
+}
+
+func (s *memStorage) ServiceAccount(context.Context, int64) (models.ServiceAccount, error) {
+	return models.ServiceAccount{}, storage.ErrAccountNotFound
+}
+
+func (s *memStorage) IsAdmin(context.Context, int64) (bool, error) {
+	return false, nil
+}
+
+func (s *memStorage) IsExists(_ context.Context, email string) (bool, error) {
+	return email == s.user.Email, nil
+}
+
+func (s *memStorage) App(_ context.Context, appID int) (models.App, error) {
+	app, ok := s.apps[appID]
+	if !ok {
+		return models.App{}, storage.ErrAppNotFound
+	}
+
+	return app, nil
+}
+
+func (s *memStorage) AppSecret(_ context.Context, appID int, kid string) (models.AppSecret, error) {
+	for _, secret := range s.rotated[appID] {
+		if secret.KID == kid {
+			return secret, nil
+		}
+	}
+
+	return models.AppSecret{}, storage.ErrSecretNotFound
+}
+
+func (s *memStorage) AppSecrets(_ context.Context, appID int) ([]models.AppSecret, error) {
+	secrets := make([]models.AppSecret, 0, len(s.rotated[appID]))
+	for _, secret := range s.rotated[appID] {
+		secret.Secret = ""
+		secrets = append(secrets, secret)
+	}
+
+	return secrets, nil
+}
+
+func (s *memStorage) TokenExchangeRule(_ context.Context, sourceAppID, targetAppID int) (models.TokenExchangeRule, error) {
+	for _, rule := range s.rules {
+		if rule.SourceAppID == sourceAppID && rule.TargetAppID == targetAppID {
+			return rule, nil
+		}
+	}
+
+	return models.TokenExchangeRule{}, storage.ErrRuleNotFound
+}
+
+func (s *memStorage) AppScopes(context.Context, int) ([]models.Scope, error) {
+	return nil, nil
+}
+
+func (s *memStorage) UserRoles(context.Context, int64, int) ([]models.Role, error) {
+	return s.roles, nil
+}
+
+func (s *memStorage) UserPermissions(context.Context, int64, int) ([]string, error) {
+	return nil, nil
+}
+
+func (s *memStorage) UserGroups(context.Context, int64, int) ([]models.Group, error) {
+	return s.groups, nil
+}
+
+func (s *memStorage) Consent(context.Context, int64, int) (models.Consent, error) {
+	return models.Consent{}, storage.ErrConsentNotFound
+}
+
+func (s *memStorage) Member(_ context.Context, orgID, userID int64) (models.OrganizationMember, error) {
+	m, ok := s.members[orgID]
+	if !ok || m.UserID != userID {
+		return models.OrganizationMember{}, storage.ErrMemberNotFound
+	}
+
+	return m, nil
+}
+
+func (s *memStorage) HasAppAccess(_ context.Context, orgID int64, appID int) (bool, error) {
+	for _, id := range s.orgApps[orgID] {
+		if id == appID {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (s *memStorage) OTPFactors(context.Context, int64) ([]models.OTPFactor, error) {
+	return s.factors, nil
+}
+
+func (s *memStorage) SaveOTPChallenge(_ context.Context, challenge models.OTPChallenge, tokenHash string) error {
+	for hash, ch := range s.challenges {
+		if ch.UserID == challenge.UserID && ch.Purpose == challenge.Purpose {
+			delete(s.challenges, hash)
+		}
+	}
+	s.challenges[tokenHash] = challenge
+
+	return nil
+}
+
+func (s *memStorage) LastOTPSentAt(_ context.Context, userID int64, purpose string) (time.Time, error) {
+	var last time.Time
+	for _, ch := range s.challenges {
+		if ch.UserID == userID && ch.Purpose == purpose && ch.SentAt.After(last) {
+			last = ch.SentAt
+		}
+	}
+
+	return last, nil
+}
+
+func newTestService(t *testing.T) (*Auth, *memStorage) {
+	t.Helper()
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(testPassword), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := &memStorage{
+		user: models.User{ID: 1, Email: testEmail, PassHash: hash},
+		apps: map[int]models.App{
+			1: {ID: 1, Name: "app", Secret: "secret", GrantTypes: []string{models.GrantPassword}},
+		},
+		members: make(map[int64]models.OrganizationMember),
+		orgApps: make(map[int64][]int),
+		rotated: make(map[int][]models.AppSecret),
+
+		challenges: make(map[string]models.OTPChallenge),
+	}
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	return New(log, nil, s, s, s, s, nil, nil, nil, s, s, time.Minute, time.Minute, time.Hour), s
+}
+
+func TestLogin_Organization(t *testing.T) {
+	a, s := newTestService(t)
+	ctx := context.Background()
+
+	_, err := a.Login(ctx, testEmail, testPassword, 1, 10, nil)
+	if !errors.Is(err, ErrNotOrgMember) {
+		t.Fatalf("got error %v, want %v", err, ErrNotOrgMember)
+	}
+
+	s.members[10] = models.OrganizationMember{OrganizationID: 10, UserID: 1, Role: models.OrgRoleAdmin}
+	_, err = a.Login(ctx, testEmail, testPassword, 1, 10, nil)
+	if !errors.Is(err, ErrOrgAppNotAllowed) {
+		t.Fatalf("got error %v, want %v", err, ErrOrgAppNotAllowed)
+	}
+
+	s.orgApps[10] = []int{1}
+	token, err := a.Login(ctx, testEmail, testPassword, 1, 10, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	claims, err := a.VerifyToken(ctx, token)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if claims.OrgID != 10 || claims.OrgRole != models.OrgRoleAdmin {
+		t.Fatalf("got organization %d with role %q, want 10 with %q", claims.OrgID, claims.OrgRole, models.OrgRoleAdmin)
+	}
+
+	token, err = a.Login(ctx, testEmail, testPassword, 1, 0, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	claims, err = a.VerifyToken(ctx, token)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if claims.OrgID != 0 {
+		t.Fatalf("got organization %d in token issued outside of organization", claims.OrgID)
+	}
+}
+
+func TestLogin_GroupClaims(t *testing.T) {
+	a, s := newTestService(t)
+	ctx := context.Background()
+
+	s.roles = []models.Role{{Name: "editor"}}
+	s.groups = []models.Group{{Name: "staff"}, {Name: "editors"}}
+
+	token, err := a.Login(ctx, testEmail, testPassword, 1, 0, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	claims, err := a.VerifyToken(ctx, token)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(claims.Roles, []string{"editor"}) {
+		t.Fatalf("got roles %v, want roles inherited from groups", claims.Roles)
+	}
+	if len(claims.Groups) != 0 {
+		t.Fatalf("got groups %v for app without group claims", claims.Groups)
+	}
+
+	app := s.apps[1]
+	app.GroupClaims = true
+	s.apps[1] = app
+
+	token, err = a.Login(ctx, testEmail, testPassword, 1, 0, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	claims, err = a.VerifyToken(ctx, token)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(claims.Groups, []string{"staff", "editors"}) {
+		t.Fatalf("got groups %v, want [staff editors]", claims.Groups)
+	}
+}
+
+func TestDisabledApp(t *testing.T) {
+	a, s := newTestService(t)
+	ctx := context.Background()
+
+	token, err := a.Login(ctx, testEmail, testPassword, 1, 0, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	app := s.apps[1]
+	app.Disabled = true
+	s.apps[1] = app
+
+	if _, err := a.Login(ctx, testEmail, testPassword, 1, 0, nil); !errors.Is(err, ErrAppDisabled) {
+		t.Fatalf("got login error %v, want %v", err, ErrAppDisabled)
+	}
+	if _, err := a.VerifyToken(ctx, token); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("got verification error %v, want %v", err, ErrInvalidToken)
+	}
+
+	app.Disabled = false
+	s.apps[1] = app
+
+	if _, err := a.VerifyToken(ctx, token); err != nil {
+		t.Fatalf("token of enabled app is refused: %v", err)
+	}
+}
+
+func TestRegisterNewUser_NoApp(t *testing.T) {
+	a, _ := newTestService(t)
+
+	_, err := a.RegisterNewUser(context.Background(), "new@example.com", testPassword, "new", "", "", "", 0)
+	if !errors.Is(err, ErrInvalidAppID) {
+		t.Fatalf("got error %v, want %v", err, ErrInvalidAppID)
+	}
+}
+
+func TestVerifyToken_RotatedSecrets(t *testing.T) {
+	a, s := newTestService(t)
+	ctx := context.Background()
+
+	// Tokens without kid are signed with the primary secret at the time.
+	withoutKID, err := a.Login(ctx, testEmail, testPassword, 1, 0, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	app := s.apps[1]
+	app.SecretKID = "first"
+	s.apps[1] = app
+	withKID, err := a.Login(ctx, testEmail, testPassword, 1, 0, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	s.rotated[1] = []models.AppSecret{
+		{AppID: 1, KID: "first", Secret: app.Secret, ExpiresAt: time.Now().Add(time.Hour)},
+	}
+	app.Secret, app.SecretKID = "new secret", "second"
+	s.apps[1] = app
+
+	for name, token := range map[string]string{"without kid": withoutKID, "with kid": withKID} {
+		if _, err := a.VerifyToken(ctx, token); err != nil {
+			t.Fatalf("token %s signed with rotated secret is refused: %v", name, err)
+		}
+	}
+
+	s.rotated[1] = nil
+	for name, token := range map[string]string{"without kid": withoutKID, "with kid": withKID} {
+		if _, err := a.VerifyToken(ctx, token); !errors.Is(err, ErrInvalidToken) {
+			t.Fatalf("got error %v for token %s after overlap, want %v", err, name, ErrInvalidToken)
+		}
+	}
+}
+
+func TestLogin_SecondFactorThrottled(t *testing.T) {
+	a, s := newTestService(t)
+	ctx := context.Background()
+
+	s.factors = []models.OTPFactor{{ID: 1, UserID: 1, Channel: models.OTPChannelSMS, Destination: "+15550100"}}
+
+	for i := 0; i < 2; i++ {
+		_, err := a.Login(ctx, testEmail, testPassword, 1, 0, nil)
+		if !errors.Is(err, ErrSecondFactorRequired) {
+			t.Fatalf("got error %v, want %v", err, ErrSecondFactorRequired)
+		}
+	}
+	if len(s.challenges) != 1 {
+		t.Fatalf("got %d open challenges, want the latest one only", len(s.challenges))
+	}
+
+	for hash, ch := range s.challenges {
+		ch.SentAt = time.Now()
+		s.challenges[hash] = ch
+	}
+
+	_, err := a.Login(ctx, testEmail, testPassword, 1, 0, nil)
+	if !errors.Is(err, ErrSecondFactorTooSoon) {
+		t.Fatalf("got error %v right after a code was sent, want %v", err, ErrSecondFactorTooSoon)
+	}
+}
+
+func TestMagicLinkToken_SecondFactor(t *testing.T) {
+	a, s := newTestService(t)
+	ctx := context.Background()
+
+	app := s.apps[1]
+	app.GrantTypes = []string{models.GrantMagicLink}
+	s.apps[1] = app
+
+	if _, err := a.MagicLinkToken(ctx, 1, 1, nil); err != nil {
+		t.Fatalf("unexpected error for user without factors: %v", err)
+	}
+
+	s.factors = []models.OTPFactor{{ID: 1, UserID: 1, Channel: models.OTPChannelSMS, Destination: "+15550100"}}
+
+	token, err := a.MagicLinkToken(ctx, 1, 1, nil)
+	var secondFactor *SecondFactorRequiredError
+	if !errors.As(err, &secondFactor) {
+		t.Fatalf("got token %q and error %v, want second factor challenge", token, err)
+	}
+	if len(secondFactor.Factors) != 1 {
+		t.Fatalf("got factors %v, want the user's factor", secondFactor.Factors)
+	}
+
+	challenge, ok := s.challenges[secrets.Hash(secondFactor.Challenge)]
+	if !ok {
+		t.Fatal("challenge is not saved")
+	}
+	if challenge.GrantType != models.GrantMagicLink {
+		t.Fatalf("got challenge for grant %q, want %q", challenge.GrantType, models.GrantMagicLink)
+	}
+
+	token, err = a.OTPToken(ctx, challenge)
+	if err != nil {
+		t.Fatalf("unexpected error issuing token for passed challenge: %v", err)
+	}
+	if _, err := a.VerifyToken(ctx, token); err != nil {
+		t.Fatalf("token issued for passed challenge is refused: %v", err)
+	}
+}
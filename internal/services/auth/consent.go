@@ -0,0 +1,55 @@
+package auth
+
+import (
+	"SSO/internal/domain/models"
+	"SSO/internal/storage"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// checkScopes checks that scopes the app requests are defined for it and,
+// if the app requires consent, that the user granted them. Apps requiring
+// consent must request scopes.
+func (a *Auth) checkScopes(ctx context.Context, userID int64, app models.App, scopes []string) error {
+	if len(scopes) == 0 {
+		if app.ConsentRequired {
+			return ErrScopeRequired
+		}
+
+		return nil
+	}
+
+	defined, err := a.appProvider.AppScopes(ctx, app.ID)
+	if err != nil {
+		return err
+	}
+
+	for _, scope := range scopes {
+		if !slices.ContainsFunc(defined, func(s models.Scope) bool { return s.Name == scope }) {
+			return fmt.Errorf("%w: %s", ErrUnknownScope, scope)
+		}
+	}
+
+	if !app.ConsentRequired {
+		return nil
+	}
+
+	consent, err := a.accProvider.Consent(ctx, userID, app.ID)
+	if err != nil {
+		if errors.Is(err, storage.ErrConsentNotFound) {
+			return ErrConsentRequired
+		}
+
+		return err
+	}
+
+	for _, scope := range scopes {
+		if !slices.Contains(consent.Scopes, scope) {
+			return fmt.Errorf("%w: %s", ErrConsentRequired, scope)
+		}
+	}
+
+	return nil
+}
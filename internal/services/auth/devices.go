@@ -0,0 +1,98 @@
+package auth
+
+import (
+	"SSO/internal/domain/models"
+	"SSO/internal/lib/jwt"
+	"SSO/internal/storage"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+)
+
+// DeviceToken issues token for device authorization request the user
+// approved. Scopes and second factor are checked as on Login.
+func (a *Auth) DeviceToken(ctx context.Context, userID int64, appID int, scopes []string) (string, error) {
+	const op = "Auth.DeviceToken"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", userID),
+		slog.Int("app_id", appID),
+	)
+
+	token, err := a.userToken(ctx, log, userID, appID, models.GrantDeviceCode, scopes)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("token issued to device")
+
+	return token, nil
+}
+
+// userToken issues token for the app to the user who signed in some other
+// way than with password. The app must allow the grant type and scopes are
+// checked as on Login. Users with one-time passcode factors must confirm
+// the login as on Login, *SecondFactorRequiredError is returned then.
+func (a *Auth) userToken(
+	ctx context.Context,
+	log *slog.Logger,
+	userID int64,
+	appID int,
+	grantType string,
+	scopes []string,
+) (string, error) {
+	user, err := a.usrProvider.UserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, storage.ErrUserNotFound) {
+			return "", ErrUserNotFound
+		}
+
+		return "", err
+	}
+
+	app, err := a.appProvider.App(ctx, appID)
+	if err != nil {
+		if errors.Is(err, storage.ErrAppNotFound) {
+			return "", ErrInvalidAppID
+		}
+
+		return "", err
+	}
+
+	if err := checkApp(app, grantType); err != nil {
+		return "", err
+	}
+
+	if err := a.checkScopes(ctx, user.ID, app, scopes); err != nil {
+		log.Warn("token refused", slog.String("error", err.Error()))
+
+		return "", err
+	}
+
+	opts, err := a.accessOptions(ctx, user.ID, app, scopes)
+	if err != nil {
+		return "", err
+	}
+
+	principal, err := a.principalOptions(ctx, user, app.ID)
+	if err != nil {
+		return "", err
+	}
+
+	opts = append(append(opts, principal...), jwt.WithScopes(scopes))
+
+	if err := a.requireSecondFactor(ctx, log, user.ID, app.ID, 0, grantType, scopes); err != nil {
+		return "", err
+	}
+
+	token, err := jwt.NewToken(user, app, a.tokenTTL, opts...)
+	if err != nil {
+		log.Error("failed to create token", slog.String("error", err.Error()))
+
+		return "", err
+	}
+
+	return token, nil
+}
@@ -0,0 +1,114 @@
+package auth
+
+import (
+	"SSO/internal/domain/models"
+	"SSO/internal/storage"
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+)
+
+// directoryUser verifies credentials of the user without local password
+// against the directory and returns the local user the directory entry is
+// linked to.
+//
+// On the first login the entry is linked to the user with its email or, if
+// there is none, a user is provisioned from the entry. Registration rules
+// of apps don't apply, the directory decides who has an account.
+func (a *Auth) directoryUser(ctx context.Context, log *slog.Logger, login, password string) (models.User, error) {
+	if a.verifier == nil {
+		log.Warn("user not found or has no password")
+
+		return models.User{}, ErrInvalidCredentials
+	}
+
+	provider := a.verifier.Name()
+	log = log.With(slog.String("provider", provider))
+
+	subject, entry, err := a.verifier.Verify(ctx, login, password)
+	if err != nil {
+		log.Error("failed to verify credentials in directory", slog.String("error", err.Error()))
+
+		return models.User{}, err
+	}
+
+	if subject == "" {
+		log.Info("invalid credentials")
+
+		return models.User{}, ErrInvalidCredentials
+	}
+
+	var userID int64
+
+	identity, err := a.idProvider.Identity(ctx, provider, subject)
+	switch {
+	case err == nil:
+		userID = identity.UserID
+
+		if err := a.idSaver.TouchIdentity(ctx, identity.ID, entry.Email, time.Now()); err != nil {
+			log.Error("failed to update identity", slog.String("error", err.Error()))
+
+			return models.User{}, err
+		}
+	case errors.Is(err, storage.ErrIdentityNotFound):
+		userID, err = a.directoryAccount(ctx, log, entry)
+		if err != nil {
+			return models.User{}, err
+		}
+
+		_, err = a.idSaver.SaveIdentity(ctx, models.Identity{
+			UserID:      userID,
+			Provider:    provider,
+			Subject:     subject,
+			Email:       entry.Email,
+			LastLoginAt: time.Now(),
+		})
+		if err != nil {
+			log.Error("failed to link identity", slog.String("error", err.Error()))
+
+			return models.User{}, err
+		}
+
+		log.Info("directory entry linked", slog.Int64("user_id", userID))
+	default:
+		log.Error("failed to get identity", slog.String("error", err.Error()))
+
+		return models.User{}, err
+	}
+
+	user, err := a.usrProvider.UserByID(ctx, userID)
+	if err != nil {
+		return models.User{}, err
+	}
+
+	if user.IsServiceAccount() {
+		log.Warn("directory entry is linked to service account", slog.Int64("user_id", user.ID))
+
+		return models.User{}, ErrInvalidCredentials
+	}
+
+	return user, nil
+}
+
+// directoryAccount returns ID of the user with email of the directory
+// entry, provisioning one if there is none.
+func (a *Auth) directoryAccount(ctx context.Context, log *slog.Logger, entry models.User) (int64, error) {
+	user, err := a.usrProvider.User(ctx, entry.Email)
+	if err == nil {
+		return user.ID, nil
+	}
+
+	if !errors.Is(err, storage.ErrUserNotFound) {
+		return 0, err
+	}
+
+	// Date of birth is dropped rather than stored in a layout the rest of
+	// the service can't parse.
+	if _, err := time.Parse(DateOfBirthLayout, entry.DateOfBirth); err != nil {
+		entry.DateOfBirth = ""
+	}
+	entry.Username = usernameFrom(entry.Username, entry.Email)
+
+	return a.saveProvisioned(ctx, log, entry)
+}
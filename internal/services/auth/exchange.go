@@ -0,0 +1,152 @@
+package auth
+
+import (
+	"SSO/internal/domain/models"
+	"SSO/internal/lib/jwt"
+	"SSO/internal/storage"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+)
+
+// ExchangeToken trades access token issued by this service for a token of
+// the user for the app with appID (RFC 8693 token exchange).
+//
+// A token exchange rule must permit exchanging tokens of the subject token's
+// app for tokens of the target app, and the target app must allow the token
+// exchange grant type. Scopes default to the rule scopes and can't go beyond
+// them nor beyond scopes of the subject token. The new token doesn't outlive
+// the subject token and keeps its actor, API key and organization.
+func (a *Auth) ExchangeToken(
+	ctx context.Context,
+	subjectToken string,
+	appID int,
+	scopes []string,
+) (string, time.Time, error) {
+	const op = "Auth.ExchangeToken"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int("app_id", appID),
+	)
+
+	subject, err := a.VerifyToken(ctx, subjectToken)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("%s: %w", op, ErrInvalidToken)
+	}
+
+	log = log.With(slog.Int64("user_id", subject.UserID), slog.Int("source_app_id", subject.AppID))
+
+	app, err := a.appProvider.App(ctx, appID)
+	if err != nil {
+		if errors.Is(err, storage.ErrAppNotFound) {
+			return "", time.Time{}, fmt.Errorf("%s: %w", op, ErrInvalidAppID)
+		}
+
+		return "", time.Time{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := checkApp(app, models.GrantTokenExchange); err != nil {
+		return "", time.Time{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rule, err := a.appProvider.TokenExchangeRule(ctx, subject.AppID, app.ID)
+	if err != nil {
+		if errors.Is(err, storage.ErrRuleNotFound) {
+			log.Warn("token exchange refused, no rule for the apps")
+
+			return "", time.Time{}, fmt.Errorf("%s: %w", op, ErrExchangeNotAllowed)
+		}
+
+		return "", time.Time{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	scopes, err = exchangeScopes(scopes, rule, subject)
+	if err != nil {
+		log.Warn("token exchange refused", slog.String("error", err.Error()))
+
+		return "", time.Time{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	user, err := a.usrProvider.UserByID(ctx, subject.UserID)
+	if err != nil {
+		if errors.Is(err, storage.ErrUserNotFound) {
+			return "", time.Time{}, fmt.Errorf("%s: %w", op, ErrInvalidToken)
+		}
+
+		return "", time.Time{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	opts, err := a.accessOptions(ctx, user.ID, app, scopes)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	principal, err := a.principalOptions(ctx, user, app.ID)
+	if err != nil {
+		log.Warn("token exchange refused", slog.String("error", err.Error()))
+
+		return "", time.Time{}, fmt.Errorf("%s: %w", op, err)
+	}
+	opts = append(opts, principal...)
+
+	if subject.OrgID != 0 && !user.IsServiceAccount() {
+		member, err := a.orgMember(ctx, subject.OrgID, user.ID, app.ID)
+		if err != nil {
+			log.Warn("token exchange refused", slog.Int64("organization_id", subject.OrgID), slog.String("error", err.Error()))
+
+			return "", time.Time{}, fmt.Errorf("%s: %w", op, err)
+		}
+
+		opts = append(opts, jwt.WithOrganization(subject.OrgID, member.Role))
+	}
+
+	opts = append(opts, jwt.WithScopes(scopes))
+	if subject.Impersonated() {
+		opts = append(opts, jwt.WithActor(subject.Actor.UserID, subject.Actor.SessionID))
+	}
+	if subject.KeyID != 0 {
+		opts = append(opts, jwt.WithAPIKey(subject.KeyID, nil))
+	}
+
+	ttl := min(a.tokenTTL, time.Until(subject.ExpiresAt))
+
+	token, err := jwt.NewToken(user, app, ttl, opts...)
+	if err != nil {
+		log.Error("failed to create token", slog.String("error", err.Error()))
+
+		return "", time.Time{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("token exchanged")
+
+	return token, time.Now().Add(ttl), nil
+}
+
+// exchangeScopes returns scopes of the exchanged token: requested scopes
+// or, if none are requested, scopes of the rule. They must be allowed by
+// the rule and by the subject token.
+func exchangeScopes(requested []string, rule models.TokenExchangeRule, subject jwt.Claims) ([]string, error) {
+	scopes := requested
+	if len(scopes) == 0 {
+		scopes = rule.Scopes
+	}
+
+	if len(scopes) == 0 && len(subject.Scopes) > 0 {
+		scopes = subject.Scopes
+	}
+
+	for _, scope := range scopes {
+		if len(rule.Scopes) > 0 && !slices.Contains(rule.Scopes, scope) {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidScope, scope)
+		}
+		if !subject.AllowsScope(scope) {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidScope, scope)
+		}
+	}
+
+	return scopes, nil
+}
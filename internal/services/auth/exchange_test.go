@@ -0,0 +1,108 @@
+package auth
+
+import (
+	"SSO/internal/domain/models"
+	"SSO/internal/lib/jwt"
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestExchangeToken_APIKey(t *testing.T) {
+	a, s := newTestService(t)
+	ctx := context.Background()
+
+	s.apps[2] = models.App{ID: 2, Name: "other", Secret: "other secret", GrantTypes: []string{models.GrantTokenExchange}}
+	s.rules = []models.TokenExchangeRule{{SourceAppID: 1, TargetAppID: 2}}
+
+	subject, err := jwt.NewToken(s.user, s.apps[1], time.Hour, jwt.WithAPIKey(7, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token, _, err := a.ExchangeToken(ctx, subject, 2, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	claims, err := a.VerifyToken(ctx, token)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if claims.AppID != 2 || claims.KeyID != 7 {
+		t.Fatalf("got app %d and key %d, want app 2 and key 7", claims.AppID, claims.KeyID)
+	}
+}
+
+func TestExchangeScopes(t *testing.T) {
+	tests := []struct {
+		name      string
+		requested []string
+		rule      []string
+		subject   []string
+		want      []string
+		wantErr   error
+	}{
+		{
+			name: "unrestricted",
+		},
+		{
+			name: "rule scopes by default",
+			rule: []string{"posts.read", "posts.write"},
+			want: []string{"posts.read", "posts.write"},
+		},
+		{
+			name:    "subject scopes by default",
+			subject: []string{"posts.read"},
+			want:    []string{"posts.read"},
+		},
+		{
+			name:      "subset of rule",
+			requested: []string{"posts.read"},
+			rule:      []string{"posts.read", "posts.write"},
+			want:      []string{"posts.read"},
+		},
+		{
+			name:      "beyond rule",
+			requested: []string{"posts.delete"},
+			rule:      []string{"posts.read"},
+			wantErr:   ErrInvalidScope,
+		},
+		{
+			name:    "rule beyond subject",
+			rule:    []string{"posts.read", "posts.write"},
+			subject: []string{"posts.read"},
+			wantErr: ErrInvalidScope,
+		},
+		{
+			name:      "beyond subject",
+			requested: []string{"posts.write"},
+			subject:   []string{"posts.read"},
+			wantErr:   ErrInvalidScope,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := exchangeScopes(
+				tt.requested,
+				models.TokenExchangeRule{Scopes: tt.rule},
+				jwt.Claims{Scopes: tt.subject},
+			)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("got error %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Fatalf("got scopes %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,157 @@
+package auth
+
+import (
+	"SSO/internal/domain/models"
+	"SSO/internal/lib/secrets"
+	"SSO/internal/storage"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+const (
+	// maxUsernameLen is the longest username users may register with.
+	maxUsernameLen = 19
+	// provisionAttempts is how many usernames are tried when provisioning user.
+	provisionAttempts = 3
+)
+
+// ProvisionUser registers user who signed in through an upstream provider
+// for the first time. The user has no password, username is derived from
+// the hint and made unique if taken. The app's registration rules apply,
+// so the app must be open for registration.
+func (a *Auth) ProvisionUser(ctx context.Context, email, usernameHint string, appID int) (int64, error) {
+	const op = "Auth.ProvisionUser"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int("app_id", appID),
+	)
+
+	app, err := a.appProvider.App(ctx, appID)
+	if err != nil {
+		if errors.Is(err, storage.ErrAppNotFound) {
+			return 0, fmt.Errorf("%s: %w", op, ErrInvalidAppID)
+		}
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if app.Disabled {
+		return 0, fmt.Errorf("%s: %w", op, ErrAppDisabled)
+	}
+
+	username := usernameFrom(usernameHint, email)
+
+	err = checkRegistration(app.Registration, Profile{Email: email, Username: username}, time.Now())
+	if err != nil {
+		log.Warn("provisioning rejected", slog.String("error", err.Error()))
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	id, err := a.saveProvisioned(ctx, log, models.User{Email: email, Username: username})
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+// FederatedToken issues token for the app to the user who signed in
+// through an upstream provider. Scopes and second factor are checked as on
+// Login.
+func (a *Auth) FederatedToken(ctx context.Context, userID int64, appID int, scopes []string) (string, error) {
+	const op = "Auth.FederatedToken"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", userID),
+		slog.Int("app_id", appID),
+	)
+
+	token, err := a.userToken(ctx, log, userID, appID, models.GrantFederated, scopes)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("user logged in through upstream provider")
+
+	return token, nil
+}
+
+// usernameFrom returns username made of the hint or, if it's empty, of the
+// local part of the email. Characters other than letters, digits, dots,
+// dashes and underscores are dropped.
+func usernameFrom(hint, email string) string {
+	if hint == "" {
+		hint, _, _ = strings.Cut(email, "@")
+	}
+
+	username := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '.', r == '-', r == '_':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r + 'a' - 'A'
+		}
+
+		return -1
+	}, hint)
+
+	if username == "" {
+		username = "user"
+	}
+
+	if len(username) > maxUsernameLen {
+		username = username[:maxUsernameLen]
+	}
+
+	return username
+}
+
+// saveProvisioned saves provisioned user without password. If username is
+// taken, random suffix is appended to it and saving is retried.
+func (a *Auth) saveProvisioned(ctx context.Context, log *slog.Logger, user models.User) (int64, error) {
+	for attempt := 0; ; attempt++ {
+		username := user.Username
+		if attempt > 0 {
+			suffix, err := secrets.Generate(3)
+			if err != nil {
+				return 0, err
+			}
+
+			username = withSuffix(user.Username, suffix)
+		}
+
+		id, err := a.usrSaver.SaveUser(ctx, user.Email, nil, username, user.Sex, user.Location, user.DateOfBirth)
+		if err == nil {
+			log.Info("user provisioned", slog.Int64("user_id", id))
+
+			return id, nil
+		}
+
+		if !errors.Is(err, storage.ErrUserExists) {
+			log.Error("failed to save user", slog.String("error", err.Error()))
+
+			return 0, err
+		}
+
+		// Email or username is taken, only the latter can be fixed.
+		if attempt+1 == provisionAttempts {
+			return 0, ErrUserExists
+		}
+	}
+}
+
+// withSuffix appends suffix to the username keeping it short enough.
+func withSuffix(username, suffix string) string {
+	if n := maxUsernameLen - len(suffix) - 1; len(username) > n {
+		username = username[:n]
+	}
+
+	return username + "-" + suffix
+}
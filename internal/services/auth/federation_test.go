@@ -0,0 +1,23 @@
+package auth
+
+import "testing"
+
+func TestUsernameFrom(t *testing.T) {
+	tests := []struct {
+		hint, email, want string
+	}{
+		{"Jane.Doe", "jane@corp.example", "jane.doe"},
+		{"", "john+sso@corp.example", "johnsso"},
+		{"", "Иван@corp.example", "user"},
+		{"a-very-long-preferred-username", "", "a-very-long-preferr"},
+	}
+	for _, tt := range tests {
+		if got := usernameFrom(tt.hint, tt.email); got != tt.want {
+			t.Errorf("usernameFrom(%q, %q) = %q, want %q", tt.hint, tt.email, got, tt.want)
+		}
+	}
+
+	if got := withSuffix("a-very-long-preferr", "x1Y_"); got != "a-very-long-pr-x1Y_" || len(got) > maxUsernameLen {
+		t.Errorf("withSuffix() = %q", got)
+	}
+}
@@ -0,0 +1,29 @@
+package auth
+
+import (
+	"SSO/internal/domain/models"
+	"context"
+	"fmt"
+	"log/slog"
+)
+
+// MagicLinkToken issues token for the app to the user who followed a
+// magic link. Scopes and second factor are checked as on Login.
+func (a *Auth) MagicLinkToken(ctx context.Context, userID int64, appID int, scopes []string) (string, error) {
+	const op = "Auth.MagicLinkToken"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", userID),
+		slog.Int("app_id", appID),
+	)
+
+	token, err := a.userToken(ctx, log, userID, appID, models.GrantMagicLink, scopes)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("user logged in with magic link")
+
+	return token, nil
+}
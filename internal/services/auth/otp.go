@@ -0,0 +1,154 @@
+package auth
+
+import (
+	"SSO/internal/domain/models"
+	"SSO/internal/lib/jwt"
+	"SSO/internal/lib/secrets"
+	"SSO/internal/storage"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+var (
+	ErrSecondFactorRequired = errors.New("second factor is required")
+	ErrInvalidChallenge     = errors.New("invalid otp challenge")
+	ErrSecondFactorTooSoon  = errors.New("second factor code was sent recently")
+)
+
+// SecondFactorRequiredError is returned by Login and other ways to sign in
+// when the user must confirm login with a one-time passcode sent to one of
+// the factors, see otp.OTP.
+type SecondFactorRequiredError struct {
+	// Challenge is the token of the challenge to pass.
+	Challenge string
+	Factors   []models.OTPFactor
+}
+
+func (e *SecondFactorRequiredError) Error() string {
+	return ErrSecondFactorRequired.Error()
+}
+
+func (e *SecondFactorRequiredError) Is(target error) bool {
+	return target == ErrSecondFactorRequired
+}
+
+// requireSecondFactor returns *SecondFactorRequiredError with a new
+// challenge if the user has one-time passcode factors. The new challenge
+// voids the former one, and is refused within resendInterval of the last
+// code sent, so logins can't flood the user with codes nor get more
+// attempts to enter them.
+func (a *Auth) requireSecondFactor(
+	ctx context.Context,
+	log *slog.Logger,
+	userID int64,
+	appID int,
+	orgID int64,
+	grantType string,
+	scopes []string,
+) error {
+	factors, err := a.otpFactors.OTPFactors(ctx, userID)
+	if err != nil {
+		log.Error("failed to get otp factors", slog.String("error", err.Error()))
+
+		return err
+	}
+
+	if len(factors) == 0 {
+		return nil
+	}
+
+	lastSentAt, err := a.challenges.LastOTPSentAt(ctx, userID, models.OTPPurposeSecondFactor)
+	if err != nil {
+		log.Error("failed to get last otp sent", slog.String("error", err.Error()))
+
+		return err
+	}
+
+	if time.Now().Before(lastSentAt.Add(a.resendInterval)) {
+		log.Warn("second factor throttled")
+
+		return ErrSecondFactorTooSoon
+	}
+
+	token, err := secrets.Generate(secrets.DefaultSize)
+	if err != nil {
+		return err
+	}
+
+	err = a.challenges.SaveOTPChallenge(ctx, models.OTPChallenge{
+		Purpose:        models.OTPPurposeSecondFactor,
+		UserID:         userID,
+		AppID:          appID,
+		OrganizationID: orgID,
+		Scopes:         scopes,
+		GrantType:      grantType,
+		ExpiresAt:      time.Now().Add(a.challengeTTL),
+	}, secrets.Hash(token))
+	if err != nil {
+		log.Error("failed to save otp challenge", slog.String("error", err.Error()))
+
+		return err
+	}
+
+	log.Info("second factor required")
+
+	return &SecondFactorRequiredError{Challenge: token, Factors: factors}
+}
+
+// OTPToken issues token for the login the user confirmed with one-time
+// passcode. Second factor challenges complete Login or another way to sign
+// in, which is checked again with its grant type. Passcode logins are
+// checked as on Login with the "otp" grant type.
+func (a *Auth) OTPToken(ctx context.Context, challenge models.OTPChallenge) (string, error) {
+	const op = "Auth.OTPToken"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", challenge.UserID),
+		slog.Int("app_id", challenge.AppID),
+		slog.String("purpose", challenge.Purpose),
+	)
+
+	var grantType string
+	switch challenge.Purpose {
+	case models.OTPPurposeSecondFactor:
+		grantType = challenge.GrantType
+		if grantType == "" {
+			grantType = models.GrantPassword
+		}
+	case models.OTPPurposeLogin:
+		grantType = models.GrantOTP
+	default:
+		return "", fmt.Errorf("%s: %w", op, ErrInvalidChallenge)
+	}
+
+	user, err := a.usrProvider.UserByID(ctx, challenge.UserID)
+	if err != nil {
+		if errors.Is(err, storage.ErrUserNotFound) {
+			return "", fmt.Errorf("%s: %w", op, ErrUserNotFound)
+		}
+
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	app, opts, err := a.loginOptions(
+		ctx, log, user, challenge.AppID, grantType, challenge.OrganizationID, challenge.Scopes,
+	)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	token, err := jwt.NewToken(user, app, a.tokenTTL, opts...)
+	if err != nil {
+		log.Error("failed to create token", slog.String("error", err.Error()))
+
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("user logged in with one-time passcode")
+
+	return token, nil
+}
@@ -0,0 +1,89 @@
+package auth
+
+import (
+	"SSO/internal/domain/models"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// DateOfBirthLayout is the layout of date of birth of the user.
+const DateOfBirthLayout = "2006-01-02"
+
+var (
+	ErrRegistrationClosed    = errors.New("registration is closed for the app")
+	ErrInviteOnly            = errors.New("registration for the app is by invitation only")
+	ErrEmailDomainNotAllowed = errors.New("email domain is not allowed for the app")
+	ErrFieldRequired         = errors.New("profile field is required")
+	ErrInvalidDateOfBirth    = errors.New("invalid date of birth")
+	ErrTooYoung              = errors.New("user is too young to register")
+)
+
+// FieldRequiredError is returned when profile field required by the app is empty.
+type FieldRequiredError struct {
+	Field string
+}
+
+func (e *FieldRequiredError) Error() string {
+	return fmt.Sprintf("%s is required", e.Field)
+}
+
+func (e *FieldRequiredError) Is(target error) bool {
+	return target == ErrFieldRequired
+}
+
+// Profile is a profile of the user to register.
+type Profile struct {
+	Email       string
+	Username    string
+	Location    string
+	DateOfBirth string
+}
+
+// checkRegistration checks that user with the profile may register
+// according to registration rules of the app.
+func checkRegistration(rules models.RegistrationRules, p Profile, now time.Time) error {
+	switch rules.Mode {
+	case models.RegistrationClosed:
+		return ErrRegistrationClosed
+	case models.RegistrationInviteOnly:
+		return ErrInviteOnly
+	}
+
+	if len(rules.AllowedEmailDomains) > 0 {
+		_, domain, _ := strings.Cut(strings.ToLower(p.Email), "@")
+		if !slices.Contains(rules.AllowedEmailDomains, domain) {
+			return ErrEmailDomainNotAllowed
+		}
+	}
+
+	fields := map[string]string{
+		models.FieldUsername:    p.Username,
+		models.FieldLocation:    p.Location,
+		models.FieldDateOfBirth: p.DateOfBirth,
+	}
+	for _, field := range rules.RequiredFields {
+		if strings.TrimSpace(fields[field]) == "" {
+			return &FieldRequiredError{Field: field}
+		}
+	}
+
+	if rules.MinAge > 0 {
+		if p.DateOfBirth == "" {
+			return &FieldRequiredError{Field: models.FieldDateOfBirth}
+		}
+
+		dob, err := time.Parse(DateOfBirthLayout, p.DateOfBirth)
+		if err != nil {
+			return ErrInvalidDateOfBirth
+		}
+
+		if dob.AddDate(rules.MinAge, 0, 0).After(now) {
+			return ErrTooYoung
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,89 @@
+package auth
+
+import (
+	"SSO/internal/domain/models"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCheckRegistration(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		rules   models.RegistrationRules
+		profile Profile
+		wantErr error
+	}{
+		{
+			name:    "open",
+			rules:   models.RegistrationRules{Mode: models.RegistrationOpen},
+			profile: Profile{Email: "a@example.com"},
+		},
+		{
+			name:    "closed",
+			rules:   models.RegistrationRules{Mode: models.RegistrationClosed},
+			profile: Profile{Email: "a@example.com"},
+			wantErr: ErrRegistrationClosed,
+		},
+		{
+			name:    "invite only",
+			rules:   models.RegistrationRules{Mode: models.RegistrationInviteOnly},
+			profile: Profile{Email: "a@example.com"},
+			wantErr: ErrInviteOnly,
+		},
+		{
+			name:    "allowed domain",
+			rules:   models.RegistrationRules{AllowedEmailDomains: []string{"example.com"}},
+			profile: Profile{Email: "A@Example.com"},
+		},
+		{
+			name:    "not allowed domain",
+			rules:   models.RegistrationRules{AllowedEmailDomains: []string{"example.com"}},
+			profile: Profile{Email: "a@sub.example.com"},
+			wantErr: ErrEmailDomainNotAllowed,
+		},
+		{
+			name:    "missing required field",
+			rules:   models.RegistrationRules{RequiredFields: []string{models.FieldLocation}},
+			profile: Profile{Email: "a@example.com", Location: " "},
+			wantErr: ErrFieldRequired,
+		},
+		{
+			name:    "min age requires date of birth",
+			rules:   models.RegistrationRules{MinAge: 18},
+			profile: Profile{Email: "a@example.com"},
+			wantErr: ErrFieldRequired,
+		},
+		{
+			name:    "invalid date of birth",
+			rules:   models.RegistrationRules{MinAge: 18},
+			profile: Profile{Email: "a@example.com", DateOfBirth: "15.06.2006"},
+			wantErr: ErrInvalidDateOfBirth,
+		},
+		{
+			name:    "too young",
+			rules:   models.RegistrationRules{MinAge: 18},
+			profile: Profile{Email: "a@example.com", DateOfBirth: "2006-06-16"},
+			wantErr: ErrTooYoung,
+		},
+		{
+			name:    "old enough on birthday",
+			rules:   models.RegistrationRules{MinAge: 18},
+			profile: Profile{Email: "a@example.com", DateOfBirth: "2006-06-15"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkRegistration(tt.rules, tt.profile, now)
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got error %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
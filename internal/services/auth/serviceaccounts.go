@@ -0,0 +1,112 @@
+package auth
+
+import (
+	"SSO/internal/domain/models"
+	"SSO/internal/lib/jwt"
+	"SSO/internal/storage"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+)
+
+// ServiceAccountToken issues access token for service account which
+// authenticated with JWT assertion.
+func (a *Auth) ServiceAccountToken(ctx context.Context, accountID int64, appID int) (string, error) {
+	const op = "Auth.ServiceAccountToken"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", accountID),
+		slog.Int("app_id", appID),
+	)
+
+	user, err := a.usrProvider.UserByID(ctx, accountID)
+	if err != nil {
+		if errors.Is(err, storage.ErrUserNotFound) {
+			return "", fmt.Errorf("%s: %w", op, ErrUserNotFound)
+		}
+
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	if !user.IsServiceAccount() {
+		return "", fmt.Errorf("%s: %w", op, ErrUserNotFound)
+	}
+
+	app, err := a.appProvider.App(ctx, appID)
+	if err != nil {
+		if errors.Is(err, storage.ErrAppNotFound) {
+			return "", fmt.Errorf("%s: %w", op, ErrInvalidAppID)
+		}
+
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := checkApp(app, models.GrantJWTBearer); err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	opts, err := a.accessOptions(ctx, user.ID, app, nil)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	principal, err := a.principalOptions(ctx, user, app.ID)
+	if err != nil {
+		log.Warn("token refused", slog.String("error", err.Error()))
+
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	token, err := jwt.NewToken(user, app, a.tokenTTL, append(opts, principal...)...)
+	if err != nil {
+		log.Error("failed to create token", slog.String("error", err.Error()))
+
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("token issued to service account")
+
+	return token, nil
+}
+
+// principalOptions returns options describing the subject of the token.
+// Service accounts must be enabled and have access to the app: accounts of
+// an app only to that app, accounts of an organization to apps the
+// organization has access to. Tokens of the latter carry the organization.
+func (a *Auth) principalOptions(ctx context.Context, user models.User, appID int) ([]jwt.Option, error) {
+	if !user.IsServiceAccount() {
+		return nil, nil
+	}
+
+	sa, err := a.usrProvider.ServiceAccount(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if sa.Disabled {
+		return nil, ErrAccountDisabled
+	}
+
+	opts := []jwt.Option{jwt.WithServiceAccount()}
+
+	if sa.AppID != 0 {
+		if sa.AppID != appID {
+			return nil, ErrAccountAppNotAllowed
+		}
+
+		return opts, nil
+	}
+
+	member, err := a.orgMember(ctx, sa.OrganizationID, user.ID, appID)
+	if err != nil {
+		if errors.Is(err, ErrOrgAppNotAllowed) {
+			return nil, ErrAccountAppNotAllowed
+		}
+
+		return nil, err
+	}
+
+	return append(opts, jwt.WithOrganization(sa.OrganizationID, member.Role)), nil
+}
@@ -0,0 +1,434 @@
+package clients
+
+import (
+	"SSO/internal/domain/models"
+	"SSO/internal/lib/secrets"
+	"SSO/internal/storage"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Clients implements dynamic registration of apps (RFC 7591) by their
+// developers and their later management (RFC 7592).
+type Clients struct {
+	log               *slog.Logger
+	clientSaver       ClientSaver
+	clientProvider    ClientProvider
+	tokenSaver        InitialTokenSaver
+	tokenProvider     InitialTokenProvider
+	allowedGrantTypes []string
+}
+
+type ClientSaver interface {
+	RegisterClient(ctx context.Context, app models.App, initialTokenHash, registrationTokenHash string) (models.App, error)
+	UpdateClient(ctx context.Context, appID int, metadata models.ClientMetadata) error
+	DeleteApp(ctx context.Context, appID int) error
+}
+
+type ClientProvider interface {
+	ClientByRegistrationToken(ctx context.Context, appID int, tokenHash string) (models.App, error)
+}
+
+type InitialTokenSaver interface {
+	SaveInitialAccessToken(ctx context.Context, token models.InitialAccessToken, tokenHash string) (models.InitialAccessToken, error)
+	DeleteInitialAccessToken(ctx context.Context, id int64) error
+}
+
+type InitialTokenProvider interface {
+	InitialAccessTokens(ctx context.Context) ([]models.InitialAccessToken, error)
+}
+
+// Registration is a registered app with its credentials, they are shown
+// only once.
+type Registration struct {
+	App models.App
+	// Secret is empty for public clients.
+	Secret            string
+	RegistrationToken string
+}
+
+var (
+	ErrAppExists                = errors.New("app already exists")
+	ErrInitialTokenNotFound     = errors.New("initial access token not found")
+	ErrInvalidInitialToken      = errors.New("invalid initial access token")
+	ErrInvalidRegistrationToken = errors.New("invalid registration access token")
+	ErrInvalidRedirectURI       = errors.New("invalid redirect uri")
+	ErrInvalidMetadata          = errors.New("invalid client metadata")
+	ErrInvalidMaxUses           = errors.New("max uses must not be negative")
+	ErrInvalidExpiry            = errors.New("expiry must be in the future")
+)
+
+// kidSize is the number of random bytes in ids of app secrets.
+const kidSize = 8
+
+// New returns a new instance of Clients service. Dynamically registered
+// apps may use only allowedGrantTypes.
+func New(
+	log *slog.Logger,
+	clientSaver ClientSaver,
+	clientProvider ClientProvider,
+	tokenSaver InitialTokenSaver,
+	tokenProvider InitialTokenProvider,
+	allowedGrantTypes []string,
+) *Clients {
+	return &Clients{
+		log:               log,
+		clientSaver:       clientSaver,
+		clientProvider:    clientProvider,
+		tokenSaver:        tokenSaver,
+		tokenProvider:     tokenProvider,
+		allowedGrantTypes: allowedGrantTypes,
+	}
+}
+
+// CreateInitialToken creates initial access token permitting registration
+// of maxUses apps, unlimited if 0. Zero expiresAt creates a token that
+// never expires. The returned token is the only place it's shown.
+func (c *Clients) CreateInitialToken(
+	ctx context.Context,
+	createdBy int64,
+	name string,
+	maxUses int,
+	expiresAt time.Time,
+) (string, models.InitialAccessToken, error) {
+	const op = "Clients.CreateInitialToken"
+
+	log := c.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", createdBy),
+	)
+
+	if maxUses < 0 {
+		return "", models.InitialAccessToken{}, fmt.Errorf("%s: %w", op, ErrInvalidMaxUses)
+	}
+
+	if !expiresAt.IsZero() && !expiresAt.After(time.Now()) {
+		return "", models.InitialAccessToken{}, fmt.Errorf("%s: %w", op, ErrInvalidExpiry)
+	}
+
+	token, err := secrets.Generate(secrets.DefaultSize)
+	if err != nil {
+		return "", models.InitialAccessToken{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	initial := models.InitialAccessToken{
+		Name:      name,
+		MaxUses:   maxUses,
+		CreatedBy: createdBy,
+		ExpiresAt: expiresAt,
+	}
+
+	initial, err = c.tokenSaver.SaveInitialAccessToken(ctx, initial, secrets.Hash(token))
+	if err != nil {
+		log.Error("failed to save initial access token", slog.String("error", err.Error()))
+
+		return "", models.InitialAccessToken{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("initial access token created", slog.Int64("token_id", initial.ID))
+
+	return token, initial, nil
+}
+
+// InitialTokens returns all initial access tokens without their values.
+func (c *Clients) InitialTokens(ctx context.Context) ([]models.InitialAccessToken, error) {
+	const op = "Clients.InitialTokens"
+
+	tokens, err := c.tokenProvider.InitialAccessTokens(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return tokens, nil
+}
+
+// RevokeInitialToken deletes initial access token, apps already registered
+// with it are kept.
+func (c *Clients) RevokeInitialToken(ctx context.Context, id int64) error {
+	const op = "Clients.RevokeInitialToken"
+
+	log := c.log.With(
+		slog.String("op", op),
+		slog.Int64("token_id", id),
+	)
+
+	if err := c.tokenSaver.DeleteInitialAccessToken(ctx, id); err != nil {
+		log.Error("failed to revoke initial access token", slog.String("error", err.Error()))
+
+		return fmt.Errorf("%s: %w", op, mapStorageErr(err))
+	}
+
+	log.Info("initial access token revoked")
+
+	return nil
+}
+
+// Register registers app with the metadata, using the initial access
+// token. Registered apps are third-party, they require consent of users.
+// Secret is generated for confidential clients only, but every app gets a
+// registration access token to manage it with.
+func (c *Clients) Register(ctx context.Context, initialToken string, metadata models.ClientMetadata) (Registration, error) {
+	const op = "Clients.Register"
+
+	log := c.log.With(
+		slog.String("op", op),
+		slog.String("name", metadata.Name),
+	)
+
+	metadata, err := normalizeMetadata(metadata, c.allowedGrantTypes)
+	if err != nil {
+		return Registration{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	secret, err := secrets.Generate(secrets.DefaultSize)
+	if err != nil {
+		return Registration{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	kid, err := secrets.Generate(kidSize)
+	if err != nil {
+		return Registration{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	registrationToken, err := secrets.Generate(secrets.DefaultSize)
+	if err != nil {
+		return Registration{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	app, err := c.clientSaver.RegisterClient(ctx, models.App{
+		Name:                    metadata.Name,
+		Secret:                  secret,
+		SecretKID:               kid,
+		RedirectURIs:            metadata.RedirectURIs,
+		GrantTypes:              metadata.GrantTypes,
+		TokenEndpointAuthMethod: metadata.TokenEndpointAuthMethod,
+		ConsentRequired:         true,
+	}, secrets.Hash(initialToken), secrets.Hash(registrationToken))
+	if err != nil {
+		if errors.Is(err, storage.ErrInitialTokenNotFound) {
+			return Registration{}, fmt.Errorf("%s: %w", op, ErrInvalidInitialToken)
+		}
+
+		log.Error("failed to register app", slog.String("error", err.Error()))
+
+		return Registration{}, fmt.Errorf("%s: %w", op, mapStorageErr(err))
+	}
+
+	log.Info("app registered", slog.Int("app_id", app.ID))
+
+	app.Secret = ""
+	reg := Registration{App: app, RegistrationToken: registrationToken}
+	if metadata.TokenEndpointAuthMethod != models.AuthMethodNone {
+		reg.Secret = secret
+	}
+
+	return reg, nil
+}
+
+// Client returns dynamically registered app without its secret, if the
+// registration access token is the one of the app.
+func (c *Clients) Client(ctx context.Context, appID int, registrationToken string) (models.App, error) {
+	const op = "Clients.Client"
+
+	app, err := c.client(ctx, appID, registrationToken)
+	if err != nil {
+		return models.App{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return app, nil
+}
+
+// Update replaces metadata of dynamically registered app and returns the
+// updated app. Credentials of the app are kept.
+func (c *Clients) Update(
+	ctx context.Context,
+	appID int,
+	registrationToken string,
+	metadata models.ClientMetadata,
+) (models.App, error) {
+	const op = "Clients.Update"
+
+	log := c.log.With(
+		slog.String("op", op),
+		slog.Int("app_id", appID),
+	)
+
+	app, err := c.client(ctx, appID, registrationToken)
+	if err != nil {
+		return models.App{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	metadata, err = normalizeMetadata(metadata, c.allowedGrantTypes)
+	if err != nil {
+		return models.App{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := c.clientSaver.UpdateClient(ctx, appID, metadata); err != nil {
+		log.Error("failed to update app", slog.String("error", err.Error()))
+
+		return models.App{}, fmt.Errorf("%s: %w", op, mapStorageErr(err))
+	}
+
+	log.Info("app updated")
+
+	app.Name = metadata.Name
+	app.RedirectURIs = metadata.RedirectURIs
+	app.GrantTypes = metadata.GrantTypes
+	app.TokenEndpointAuthMethod = metadata.TokenEndpointAuthMethod
+
+	return app, nil
+}
+
+// Delete deletes dynamically registered app, its tokens are no longer
+// accepted.
+func (c *Clients) Delete(ctx context.Context, appID int, registrationToken string) error {
+	const op = "Clients.Delete"
+
+	log := c.log.With(
+		slog.String("op", op),
+		slog.Int("app_id", appID),
+	)
+
+	if _, err := c.client(ctx, appID, registrationToken); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := c.clientSaver.DeleteApp(ctx, appID); err != nil {
+		log.Error("failed to delete app", slog.String("error", err.Error()))
+
+		return fmt.Errorf("%s: %w", op, mapStorageErr(err))
+	}
+
+	log.Info("app deleted")
+
+	return nil
+}
+
+// client returns app authenticated by the registration access token.
+func (c *Clients) client(ctx context.Context, appID int, registrationToken string) (models.App, error) {
+	app, err := c.clientProvider.ClientByRegistrationToken(ctx, appID, secrets.Hash(registrationToken))
+	if err != nil {
+		if errors.Is(err, storage.ErrAppNotFound) {
+			return models.App{}, ErrInvalidRegistrationToken
+		}
+
+		return models.App{}, err
+	}
+
+	app.Secret = ""
+
+	return app, nil
+}
+
+// normalizeMetadata checks client metadata, fills defaults and removes
+// duplicates. Without grant types the app is allowed the password grant,
+// without authentication method it's client_secret_basic.
+func normalizeMetadata(metadata models.ClientMetadata, allowedGrantTypes []string) (models.ClientMetadata, error) {
+	metadata.Name = strings.TrimSpace(metadata.Name)
+	if metadata.Name == "" {
+		return models.ClientMetadata{}, fmt.Errorf("%w: client_name is required", ErrInvalidMetadata)
+	}
+
+	uris := make([]string, 0, len(metadata.RedirectURIs))
+	for _, uri := range metadata.RedirectURIs {
+		if err := checkRedirectURI(uri); err != nil {
+			return models.ClientMetadata{}, err
+		}
+		if !slices.Contains(uris, uri) {
+			uris = append(uris, uri)
+		}
+	}
+	metadata.RedirectURIs = uris
+
+	if len(metadata.GrantTypes) == 0 {
+		metadata.GrantTypes = []string{models.GrantPassword}
+	}
+
+	grantTypes := make([]string, 0, len(metadata.GrantTypes))
+	for _, gt := range metadata.GrantTypes {
+		if !slices.Contains(allowedGrantTypes, gt) || !slices.Contains(models.GrantTypes, gt) {
+			return models.ClientMetadata{}, fmt.Errorf("%w: grant type %s is not allowed", ErrInvalidMetadata, gt)
+		}
+		if !slices.Contains(grantTypes, gt) {
+			grantTypes = append(grantTypes, gt)
+		}
+	}
+	metadata.GrantTypes = grantTypes
+
+	switch metadata.TokenEndpointAuthMethod {
+	case "":
+		metadata.TokenEndpointAuthMethod = models.AuthMethodClientSecretBasic
+	case models.AuthMethodClientSecretBasic, models.AuthMethodClientSecretPost:
+	case models.AuthMethodNone:
+		// Public clients can't prove who they are, so they can't act on
+		// their own or trade tokens of other apps.
+		for _, gt := range []string{models.GrantJWTBearer, models.GrantTokenExchange} {
+			if slices.Contains(metadata.GrantTypes, gt) {
+				return models.ClientMetadata{}, fmt.Errorf("%w: grant type %s requires client authentication", ErrInvalidMetadata, gt)
+			}
+		}
+	default:
+		return models.ClientMetadata{}, fmt.Errorf(
+			"%w: unknown token_endpoint_auth_method %s", ErrInvalidMetadata, metadata.TokenEndpointAuthMethod,
+		)
+	}
+
+	return metadata, nil
+}
+
+// checkRedirectURI checks that redirect URI is absolute and has no
+// fragment. Plain http is allowed only for loopback hosts, custom schemes
+// must be reverse domain names (RFC 8252, section 7).
+func checkRedirectURI(uri string) error {
+	u, err := url.Parse(uri)
+	if err != nil || !u.IsAbs() || u.Fragment != "" {
+		return fmt.Errorf("%w: %s", ErrInvalidRedirectURI, uri)
+	}
+
+	switch u.Scheme {
+	case "https":
+		if u.Host == "" {
+			return fmt.Errorf("%w: %s", ErrInvalidRedirectURI, uri)
+		}
+	case "http":
+		if !isLoopback(u.Hostname()) {
+			return fmt.Errorf("%w: http is allowed only for loopback: %s", ErrInvalidRedirectURI, uri)
+		}
+	default:
+		if !strings.Contains(u.Scheme, ".") {
+			return fmt.Errorf("%w: %s", ErrInvalidRedirectURI, uri)
+		}
+	}
+
+	return nil
+}
+
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+
+	ip := net.ParseIP(host)
+
+	return ip != nil && ip.IsLoopback()
+}
+
+func mapStorageErr(err error) error {
+	switch {
+	case errors.Is(err, storage.ErrAppExists):
+		return ErrAppExists
+	case errors.Is(err, storage.ErrAppNotFound):
+		return ErrInvalidRegistrationToken
+	case errors.Is(err, storage.ErrInitialTokenNotFound):
+		return ErrInitialTokenNotFound
+	}
+
+	return err
+}
@@ -0,0 +1,57 @@
+package clients
+
+import (
+	"SSO/internal/domain/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeMetadata(t *testing.T) {
+	allowed := []string{models.GrantPassword, models.GrantDeviceCode, models.GrantTokenExchange}
+
+	metadata, err := normalizeMetadata(models.ClientMetadata{
+		Name:         " Partner ",
+		RedirectURIs: []string{"https://partner.example/cb", "https://partner.example/cb", "http://127.0.0.1:8080/cb"},
+	}, allowed)
+	require.NoError(t, err)
+	assert.Equal(t, "Partner", metadata.Name)
+	assert.Equal(t, []string{"https://partner.example/cb", "http://127.0.0.1:8080/cb"}, metadata.RedirectURIs)
+	assert.Equal(t, []string{models.GrantPassword}, metadata.GrantTypes)
+	assert.Equal(t, models.AuthMethodClientSecretBasic, metadata.TokenEndpointAuthMethod)
+
+	tests := []struct {
+		name     string
+		metadata models.ClientMetadata
+		err      error
+	}{
+		{"no name", models.ClientMetadata{}, ErrInvalidMetadata},
+		{"http", models.ClientMetadata{Name: "a", RedirectURIs: []string{"http://partner.example/cb"}}, ErrInvalidRedirectURI},
+		{"relative", models.ClientMetadata{Name: "a", RedirectURIs: []string{"/cb"}}, ErrInvalidRedirectURI},
+		{"fragment", models.ClientMetadata{Name: "a", RedirectURIs: []string{"https://partner.example/cb#x"}}, ErrInvalidRedirectURI},
+		{"script", models.ClientMetadata{Name: "a", RedirectURIs: []string{"javascript:alert(1)"}}, ErrInvalidRedirectURI},
+		{"grant not allowed", models.ClientMetadata{Name: "a", GrantTypes: []string{models.GrantAPIKey}}, ErrInvalidMetadata},
+		{"auth method", models.ClientMetadata{Name: "a", TokenEndpointAuthMethod: "private_key_jwt"}, ErrInvalidMetadata},
+		{"public exchange", models.ClientMetadata{
+			Name:                    "a",
+			GrantTypes:              []string{models.GrantTokenExchange},
+			TokenEndpointAuthMethod: models.AuthMethodNone,
+		}, ErrInvalidMetadata},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := normalizeMetadata(tt.metadata, allowed)
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+
+	metadata, err = normalizeMetadata(models.ClientMetadata{
+		Name:                    "cli",
+		RedirectURIs:            []string{"com.partner.app:/cb"},
+		GrantTypes:              []string{models.GrantDeviceCode},
+		TokenEndpointAuthMethod: models.AuthMethodNone,
+	}, allowed)
+	require.NoError(t, err)
+	assert.Equal(t, models.AuthMethodNone, metadata.TokenEndpointAuthMethod)
+}
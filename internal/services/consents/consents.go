@@ -0,0 +1,243 @@
+package consents
+
+import (
+	"SSO/internal/domain/models"
+	"SSO/internal/lib/jwt"
+	"SSO/internal/storage"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+)
+
+type Consents struct {
+	log             *slog.Logger
+	scopeSaver      ScopeSaver
+	scopeProvider   ScopeProvider
+	consentSaver    ConsentSaver
+	consentProvider ConsentProvider
+}
+
+type ScopeSaver interface {
+	SaveScope(ctx context.Context, scope models.Scope) error
+	DeleteScope(ctx context.Context, appID int, name string) error
+}
+
+type ScopeProvider interface {
+	AppScopes(ctx context.Context, appID int) ([]models.Scope, error)
+}
+
+type ConsentSaver interface {
+	SaveConsent(ctx context.Context, userID int64, appID int, scopes []string) (models.Consent, error)
+	DeleteConsent(ctx context.Context, userID int64, appID int) error
+}
+
+type ConsentProvider interface {
+	Consents(ctx context.Context, userID int64) ([]models.Consent, error)
+}
+
+var (
+	ErrAppNotFound      = errors.New("app not found")
+	ErrScopeNotFound    = errors.New("scope not found")
+	ErrConsentNotFound  = errors.New("consent not found")
+	ErrInvalidScope     = errors.New("invalid scope")
+	ErrUnknownScope     = errors.New("scope is not defined for the app")
+	ErrConsentForbidden = errors.New("consent can't be granted with impersonation or api key token")
+)
+
+// New returns a new instance of Consents service.
+func New(
+	log *slog.Logger,
+	scopeSaver ScopeSaver,
+	scopeProvider ScopeProvider,
+	consentSaver ConsentSaver,
+	consentProvider ConsentProvider,
+) *Consents {
+	return &Consents{
+		log:             log,
+		scopeSaver:      scopeSaver,
+		scopeProvider:   scopeProvider,
+		consentSaver:    consentSaver,
+		consentProvider: consentProvider,
+	}
+}
+
+// SetScope defines scope the app may request at login or updates its
+// description shown to users on consent.
+func (c *Consents) SetScope(ctx context.Context, appID int, name, description string) error {
+	const op = "Consents.SetScope"
+
+	log := c.log.With(
+		slog.String("op", op),
+		slog.Int("app_id", appID),
+		slog.String("scope", name),
+	)
+
+	err := c.scopeSaver.SaveScope(ctx, models.Scope{AppID: appID, Name: name, Description: description})
+	if err != nil {
+		log.Error("failed to save scope", slog.String("error", err.Error()))
+
+		return fmt.Errorf("%s: %w", op, mapStorageErr(err))
+	}
+
+	log.Info("scope saved")
+
+	return nil
+}
+
+// Scopes returns scopes defined for the app.
+func (c *Consents) Scopes(ctx context.Context, appID int) ([]models.Scope, error) {
+	const op = "Consents.Scopes"
+
+	scopes, err := c.scopeProvider.AppScopes(ctx, appID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return scopes, nil
+}
+
+// DeleteScope deletes scope of the app, it's removed from consents users
+// granted to the app too. Tokens already issued with it stay valid until
+// they expire.
+func (c *Consents) DeleteScope(ctx context.Context, appID int, name string) error {
+	const op = "Consents.DeleteScope"
+
+	log := c.log.With(
+		slog.String("op", op),
+		slog.Int("app_id", appID),
+		slog.String("scope", name),
+	)
+
+	if err := c.scopeSaver.DeleteScope(ctx, appID, name); err != nil {
+		log.Error("failed to delete scope", slog.String("error", err.Error()))
+
+		return fmt.Errorf("%s: %w", op, mapStorageErr(err))
+	}
+
+	log.Info("scope deleted")
+
+	return nil
+}
+
+// Grant adds scopes to the consent the caller grants to the app and
+// returns the consent. Scopes must be defined for the app. Only the user
+// themselves may grant consent, not an impersonating admin nor an API key.
+func (c *Consents) Grant(ctx context.Context, caller jwt.Claims, appID int, scopes []string) (models.Consent, error) {
+	const op = "Consents.Grant"
+
+	log := c.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", caller.UserID),
+		slog.Int("app_id", appID),
+	)
+
+	if caller.Impersonated() || caller.KeyID != 0 {
+		return models.Consent{}, fmt.Errorf("%s: %w", op, ErrConsentForbidden)
+	}
+
+	scopes, err := normalizeScopes(scopes)
+	if err != nil {
+		return models.Consent{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	defined, err := c.scopeProvider.AppScopes(ctx, appID)
+	if err != nil {
+		return models.Consent{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := checkDefined(scopes, defined); err != nil {
+		return models.Consent{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	consent, err := c.consentSaver.SaveConsent(ctx, caller.UserID, appID, scopes)
+	if err != nil {
+		log.Error("failed to save consent", slog.String("error", err.Error()))
+
+		return models.Consent{}, fmt.Errorf("%s: %w", op, mapStorageErr(err))
+	}
+
+	log.Info("consent granted", slog.Any("scopes", scopes))
+
+	return consent, nil
+}
+
+// Consents returns consents the user granted.
+func (c *Consents) Consents(ctx context.Context, userID int64) ([]models.Consent, error) {
+	const op = "Consents.Consents"
+
+	consents, err := c.consentProvider.Consents(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return consents, nil
+}
+
+// Revoke deletes consent the user granted to the app. Tokens already
+// issued stay valid until they expire.
+func (c *Consents) Revoke(ctx context.Context, userID int64, appID int) error {
+	const op = "Consents.Revoke"
+
+	log := c.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", userID),
+		slog.Int("app_id", appID),
+	)
+
+	if err := c.consentSaver.DeleteConsent(ctx, userID, appID); err != nil {
+		log.Error("failed to revoke consent", slog.String("error", err.Error()))
+
+		return fmt.Errorf("%s: %w", op, mapStorageErr(err))
+	}
+
+	log.Info("consent revoked")
+
+	return nil
+}
+
+// checkDefined checks that every scope is defined.
+func checkDefined(scopes []string, defined []models.Scope) error {
+	for _, scope := range scopes {
+		if !slices.ContainsFunc(defined, func(s models.Scope) bool { return s.Name == scope }) {
+			return fmt.Errorf("%w: %s", ErrUnknownScope, scope)
+		}
+	}
+
+	return nil
+}
+
+// normalizeScopes trims scopes and removes duplicates.
+func normalizeScopes(scopes []string) ([]string, error) {
+	res := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		scope = strings.TrimSpace(scope)
+		if scope == "" {
+			return nil, ErrInvalidScope
+		}
+		if !slices.Contains(res, scope) {
+			res = append(res, scope)
+		}
+	}
+
+	if len(res) == 0 {
+		return nil, ErrInvalidScope
+	}
+
+	return res, nil
+}
+
+func mapStorageErr(err error) error {
+	switch {
+	case errors.Is(err, storage.ErrAppNotFound):
+		return ErrAppNotFound
+	case errors.Is(err, storage.ErrScopeNotFound):
+		return ErrScopeNotFound
+	case errors.Is(err, storage.ErrConsentNotFound):
+		return ErrConsentNotFound
+	}
+
+	return err
+}
@@ -0,0 +1,475 @@
+package devices
+
+import (
+	"SSO/internal/domain/models"
+	"SSO/internal/lib/jwt"
+	"SSO/internal/lib/secrets"
+	"SSO/internal/storage"
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	// userCodeAlphabet has no vowels, to not form words, and no characters
+	// easily confused with each other (RFC 8628, section 6.1).
+	userCodeAlphabet = "BCDFGHJKLMNPQRSTVWXZ"
+	userCodeLength   = 8
+	// slowDownStep is how much the poll interval grows on every too early poll.
+	slowDownStep = 5 * time.Second
+	// saveAttempts is how many times a new user code is generated if it collides.
+	saveAttempts = 3
+)
+
+type Devices struct {
+	log             *slog.Logger
+	codeSaver       CodeSaver
+	codeProvider    CodeProvider
+	appProvider     AppProvider
+	consentSaver    ConsentSaver
+	issuer          TokenIssuer
+	codeTTL         time.Duration
+	interval        time.Duration
+	verificationURI string
+}
+
+type CodeSaver interface {
+	SaveDeviceCode(ctx context.Context, code models.DeviceCode, deviceCodeHash string) (models.DeviceCode, error)
+	DecideDeviceCode(ctx context.Context, id, userID int64, status string) error
+	TouchDeviceCode(ctx context.Context, id int64, polledAt time.Time, interval time.Duration) error
+	DeleteDeviceCode(ctx context.Context, id int64) error
+	DeleteExpiredDeviceCodes(ctx context.Context, before time.Time) (int64, error)
+}
+
+type CodeProvider interface {
+	DeviceCodeByUserCode(ctx context.Context, userCode string) (models.DeviceCode, error)
+	DeviceCodeByHash(ctx context.Context, deviceCodeHash string) (models.DeviceCode, error)
+}
+
+type AppProvider interface {
+	App(ctx context.Context, appID int) (models.App, error)
+	AppScopes(ctx context.Context, appID int) ([]models.Scope, error)
+}
+
+// ConsentSaver records scopes the user granted to the app approving a device.
+type ConsentSaver interface {
+	SaveConsent(ctx context.Context, userID int64, appID int, scopes []string) (models.Consent, error)
+}
+
+// TokenIssuer issues tokens for approved device authorization requests.
+type TokenIssuer interface {
+	DeviceToken(ctx context.Context, userID int64, appID int, scopes []string) (string, error)
+}
+
+// Authorization is a started device authorization the device shows to the user.
+type Authorization struct {
+	DeviceCode              string
+	UserCode                string
+	VerificationURI         string
+	VerificationURIComplete string
+	ExpiresAt               time.Time
+	Interval                time.Duration
+}
+
+var (
+	ErrAppNotFound          = errors.New("app not found")
+	ErrAppDisabled          = errors.New("app is disabled")
+	ErrGrantNotAllowed      = errors.New("device code grant is not allowed for the app")
+	ErrInvalidScope         = errors.New("invalid scope")
+	ErrInvalidUserCode      = errors.New("invalid or expired user code")
+	ErrInvalidDeviceCode    = errors.New("invalid device code")
+	ErrApproverNotAllowed   = errors.New("device must be approved by the user themselves")
+	ErrAuthorizationPending = errors.New("authorization_pending")
+	ErrSlowDown             = errors.New("slow_down")
+	ErrAccessDenied         = errors.New("access_denied")
+	ErrExpiredToken         = errors.New("expired_token")
+)
+
+// New returns a new instance of Devices service. Device codes expire after
+// codeTTL, devices may poll for token once per interval. Users enter user
+// codes at verificationURI.
+func New(
+	log *slog.Logger,
+	codeSaver CodeSaver,
+	codeProvider CodeProvider,
+	appProvider AppProvider,
+	consentSaver ConsentSaver,
+	issuer TokenIssuer,
+	codeTTL time.Duration,
+	interval time.Duration,
+	verificationURI string,
+) *Devices {
+	return &Devices{
+		log:             log,
+		codeSaver:       codeSaver,
+		codeProvider:    codeProvider,
+		appProvider:     appProvider,
+		consentSaver:    consentSaver,
+		issuer:          issuer,
+		codeTTL:         codeTTL,
+		interval:        interval,
+		verificationURI: verificationURI,
+	}
+}
+
+// Authorize starts device authorization for the app. Scopes must be
+// defined for the app. The device code is shown only here, only its hash
+// is stored.
+func (d *Devices) Authorize(ctx context.Context, appID int, scopes []string) (Authorization, error) {
+	const op = "Devices.Authorize"
+
+	log := d.log.With(
+		slog.String("op", op),
+		slog.Int("app_id", appID),
+	)
+
+	app, err := d.appProvider.App(ctx, appID)
+	if err != nil {
+		return Authorization{}, fmt.Errorf("%s: %w", op, mapStorageErr(err))
+	}
+
+	if app.Disabled {
+		return Authorization{}, fmt.Errorf("%s: %w", op, ErrAppDisabled)
+	}
+
+	if !app.AllowsGrant(models.GrantDeviceCode) {
+		return Authorization{}, fmt.Errorf("%s: %w", op, ErrGrantNotAllowed)
+	}
+
+	if err := d.checkScopes(ctx, app.ID, scopes); err != nil {
+		return Authorization{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	deviceCode, err := secrets.Generate(secrets.DefaultSize)
+	if err != nil {
+		return Authorization{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var saved models.DeviceCode
+	for attempt := 1; ; attempt++ {
+		userCode, err := newUserCode()
+		if err != nil {
+			return Authorization{}, fmt.Errorf("%s: %w", op, err)
+		}
+
+		saved, err = d.codeSaver.SaveDeviceCode(ctx, models.DeviceCode{
+			AppID:     app.ID,
+			UserCode:  userCode,
+			Scopes:    scopes,
+			Interval:  d.interval,
+			ExpiresAt: time.Now().Add(d.codeTTL),
+		}, secrets.Hash(deviceCode))
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, storage.ErrDeviceCodeExists) || attempt == saveAttempts {
+			log.Error("failed to save device code", slog.String("error", err.Error()))
+
+			return Authorization{}, fmt.Errorf("%s: %w", op, mapStorageErr(err))
+		}
+	}
+
+	log.Info("device authorization started", slog.Int64("device_code_id", saved.ID))
+
+	return Authorization{
+		DeviceCode:              deviceCode,
+		UserCode:                FormatUserCode(saved.UserCode),
+		VerificationURI:         d.verificationURI,
+		VerificationURIComplete: d.verificationURIComplete(saved.UserCode),
+		ExpiresAt:               saved.ExpiresAt,
+		Interval:                saved.Interval,
+	}, nil
+}
+
+// Request returns pending device authorization request by the user code,
+// for the user to check the app and scopes before approving it.
+func (d *Devices) Request(ctx context.Context, userCode string) (models.DeviceCode, models.App, error) {
+	const op = "Devices.Request"
+
+	code, err := d.pending(ctx, userCode)
+	if err != nil {
+		return models.DeviceCode{}, models.App{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	app, err := d.appProvider.App(ctx, code.AppID)
+	if err != nil {
+		return models.DeviceCode{}, models.App{}, fmt.Errorf("%s: %w", op, mapStorageErr(err))
+	}
+
+	code.UserCode = FormatUserCode(code.UserCode)
+
+	return code, app, nil
+}
+
+// Decide approves or denies pending device authorization request on
+// behalf of the caller. Approving grants the requested scopes to apps
+// requiring consent. Only the user themselves may decide, not an
+// impersonating admin, an API key or a service account.
+func (d *Devices) Decide(ctx context.Context, caller jwt.Claims, userCode string, approve bool) error {
+	const op = "Devices.Decide"
+
+	log := d.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", caller.UserID),
+		slog.Bool("approve", approve),
+	)
+
+	if caller.Impersonated() || caller.KeyID != 0 || caller.ServiceAccount {
+		return fmt.Errorf("%s: %w", op, ErrApproverNotAllowed)
+	}
+
+	code, err := d.pending(ctx, userCode)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	status := models.DeviceCodeDenied
+	if approve {
+		status = models.DeviceCodeApproved
+
+		app, err := d.appProvider.App(ctx, code.AppID)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, mapStorageErr(err))
+		}
+
+		if app.ConsentRequired && len(code.Scopes) > 0 {
+			if _, err := d.consentSaver.SaveConsent(ctx, caller.UserID, app.ID, code.Scopes); err != nil {
+				log.Error("failed to save consent", slog.String("error", err.Error()))
+
+				return fmt.Errorf("%s: %w", op, mapStorageErr(err))
+			}
+		}
+	}
+
+	if err := d.codeSaver.DecideDeviceCode(ctx, code.ID, caller.UserID, status); err != nil {
+		return fmt.Errorf("%s: %w", op, mapStorageErr(err))
+	}
+
+	log.Info("device authorization decided", slog.Int64("device_code_id", code.ID))
+
+	return nil
+}
+
+// Token returns token for the device code once the user approved it.
+// Until then it returns ErrAuthorizationPending, or ErrSlowDown if the
+// device polls more often than the interval, which grows on every such
+// poll. Denied and expired requests return ErrAccessDenied and
+// ErrExpiredToken. The device code can be exchanged only once.
+func (d *Devices) Token(ctx context.Context, appID int, deviceCode string) (string, error) {
+	const op = "Devices.Token"
+
+	code, err := d.codeProvider.DeviceCodeByHash(ctx, secrets.Hash(deviceCode))
+	if err != nil {
+		if errors.Is(err, storage.ErrDeviceCodeNotFound) {
+			return "", fmt.Errorf("%s: %w", op, ErrInvalidDeviceCode)
+		}
+
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	if code.AppID != appID {
+		return "", fmt.Errorf("%s: %w", op, ErrInvalidDeviceCode)
+	}
+
+	log := d.log.With(
+		slog.String("op", op),
+		slog.Int64("device_code_id", code.ID),
+	)
+
+	now := time.Now()
+	if code.Expired(now) {
+		return "", fmt.Errorf("%s: %w", op, ErrExpiredToken)
+	}
+
+	interval, pollErr := pollInterval(code, now)
+	if err := d.codeSaver.TouchDeviceCode(ctx, code.ID, now, interval); err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+	if pollErr != nil {
+		return "", fmt.Errorf("%s: %w", op, pollErr)
+	}
+
+	switch code.Status {
+	case models.DeviceCodePending:
+		return "", fmt.Errorf("%s: %w", op, ErrAuthorizationPending)
+	case models.DeviceCodeDenied:
+		if err := d.codeSaver.DeleteDeviceCode(ctx, code.ID); err != nil {
+			log.Warn("failed to delete denied device code", slog.String("error", err.Error()))
+		}
+
+		return "", fmt.Errorf("%s: %w", op, ErrAccessDenied)
+	}
+
+	// Deleting first makes sure concurrent polls get only one token.
+	if err := d.codeSaver.DeleteDeviceCode(ctx, code.ID); err != nil {
+		if errors.Is(err, storage.ErrDeviceCodeNotFound) {
+			return "", fmt.Errorf("%s: %w", op, ErrInvalidDeviceCode)
+		}
+
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	token, err := d.issuer.DeviceToken(ctx, code.UserID, code.AppID, code.Scopes)
+	if err != nil {
+		log.Warn("failed to issue device token", slog.String("error", err.Error()))
+
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("token issued to device", slog.Int64("user_id", code.UserID))
+
+	return token, nil
+}
+
+// DeleteExpired deletes expired device authorization requests.
+func (d *Devices) DeleteExpired(ctx context.Context) error {
+	const op = "Devices.DeleteExpired"
+
+	n, err := d.codeSaver.DeleteExpiredDeviceCodes(ctx, time.Now())
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if n > 0 {
+		d.log.Info("expired device codes deleted", slog.String("op", op), slog.Int64("count", n))
+	}
+
+	return nil
+}
+
+// RunCleanup deletes expired device authorization requests every period
+// until ctx is done.
+func (d *Devices) RunCleanup(ctx context.Context, every time.Duration) {
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := d.DeleteExpired(ctx); err != nil {
+				d.log.Error("failed to delete expired device codes", slog.String("error", err.Error()))
+			}
+		}
+	}
+}
+
+// pending returns not expired pending request by the user code.
+func (d *Devices) pending(ctx context.Context, userCode string) (models.DeviceCode, error) {
+	code, err := d.codeProvider.DeviceCodeByUserCode(ctx, NormalizeUserCode(userCode))
+	if err != nil {
+		if errors.Is(err, storage.ErrDeviceCodeNotFound) {
+			return models.DeviceCode{}, ErrInvalidUserCode
+		}
+
+		return models.DeviceCode{}, err
+	}
+
+	if code.Status != models.DeviceCodePending || code.Expired(time.Now()) {
+		return models.DeviceCode{}, ErrInvalidUserCode
+	}
+
+	return code, nil
+}
+
+// checkScopes checks that scopes are not empty and defined for the app.
+func (d *Devices) checkScopes(ctx context.Context, appID int, scopes []string) error {
+	if len(scopes) == 0 {
+		return nil
+	}
+
+	defined, err := d.appProvider.AppScopes(ctx, appID)
+	if err != nil {
+		return err
+	}
+
+	for _, scope := range scopes {
+		if !slices.ContainsFunc(defined, func(s models.Scope) bool { return s.Name == scope }) {
+			return fmt.Errorf("%w: %s", ErrInvalidScope, scope)
+		}
+	}
+
+	return nil
+}
+
+func (d *Devices) verificationURIComplete(userCode string) string {
+	if d.verificationURI == "" {
+		return ""
+	}
+
+	sep := "?"
+	if strings.Contains(d.verificationURI, "?") {
+		sep = "&"
+	}
+
+	return d.verificationURI + sep + "user_code=" + url.QueryEscape(FormatUserCode(userCode))
+}
+
+// pollInterval returns interval of the device after poll at the time and
+// ErrSlowDown if the device polled too early.
+func pollInterval(code models.DeviceCode, at time.Time) (time.Duration, error) {
+	if !code.LastPolledAt.IsZero() && at.Sub(code.LastPolledAt) < code.Interval {
+		return code.Interval + slowDownStep, ErrSlowDown
+	}
+
+	return code.Interval, nil
+}
+
+// newUserCode generates user code of userCodeLength characters of userCodeAlphabet.
+func newUserCode() (string, error) {
+	max := big.NewInt(int64(len(userCodeAlphabet)))
+
+	b := make([]byte, userCodeLength)
+	for i := range b {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		b[i] = userCodeAlphabet[n.Int64()]
+	}
+
+	return string(b), nil
+}
+
+// NormalizeUserCode returns user code as it's stored: upper case without
+// separators users may type.
+func NormalizeUserCode(userCode string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r == '-' || r == ' ':
+			return -1
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		}
+
+		return r
+	}, userCode)
+}
+
+// FormatUserCode splits user code in halves with a dash for readability.
+func FormatUserCode(userCode string) string {
+	if len(userCode) != userCodeLength {
+		return userCode
+	}
+
+	return userCode[:userCodeLength/2] + "-" + userCode[userCodeLength/2:]
+}
+
+func mapStorageErr(err error) error {
+	switch {
+	case errors.Is(err, storage.ErrAppNotFound):
+		return ErrAppNotFound
+	case errors.Is(err, storage.ErrDeviceCodeNotFound):
+		return ErrInvalidUserCode
+	}
+
+	return err
+}
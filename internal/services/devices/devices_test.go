@@ -0,0 +1,44 @@
+package devices
+
+import (
+	"SSO/internal/domain/models"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserCode(t *testing.T) {
+	code, err := newUserCode()
+	require.NoError(t, err)
+	require.Len(t, code, userCodeLength)
+	for _, r := range code {
+		assert.True(t, strings.ContainsRune(userCodeAlphabet, r), "unexpected character %q", r)
+	}
+
+	formatted := FormatUserCode(code)
+	assert.Equal(t, code[:4]+"-"+code[4:], formatted)
+	assert.Equal(t, code, NormalizeUserCode(formatted))
+	assert.Equal(t, "BCDFGHJK", NormalizeUserCode(" bcdf-ghjk "))
+}
+
+func TestPollInterval(t *testing.T) {
+	now := time.Now()
+	code := models.DeviceCode{Interval: 5 * time.Second}
+
+	interval, err := pollInterval(code, now)
+	assert.NoError(t, err)
+	assert.Equal(t, 5*time.Second, interval)
+
+	code.LastPolledAt = now.Add(-6 * time.Second)
+	interval, err = pollInterval(code, now)
+	assert.NoError(t, err)
+	assert.Equal(t, 5*time.Second, interval)
+
+	code.LastPolledAt = now.Add(-2 * time.Second)
+	interval, err = pollInterval(code, now)
+	assert.ErrorIs(t, err, ErrSlowDown)
+	assert.Equal(t, 10*time.Second, interval)
+}
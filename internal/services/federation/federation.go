@@ -0,0 +1,468 @@
+package federation
+
+import (
+	"SSO/internal/domain/models"
+	"SSO/internal/lib/oidc"
+	"SSO/internal/lib/secrets"
+	"SSO/internal/storage"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+)
+
+// Federation signs users in through upstream OpenID Connect providers.
+// The user is redirected to the provider and back to the callback, which
+// completes the sign in with the returned state and authorization code.
+type Federation struct {
+	log              *slog.Logger
+	upstreams        []Upstream
+	stateSaver       StateSaver
+	identitySaver    IdentitySaver
+	identityProvider IdentityProvider
+	userProvider     UserProvider
+	appProvider      AppProvider
+	auth             Authenticator
+	stateTTL         time.Duration
+}
+
+// Provider is an upstream OpenID Connect provider, see oidc.Provider.
+type Provider interface {
+	AuthCodeURL(ctx context.Context, state, nonce, verifier string) (string, error)
+	Exchange(ctx context.Context, code, verifier, nonce string) (oidc.Claims, error)
+}
+
+// Upstream is a configured upstream provider.
+type Upstream struct {
+	Name     string
+	Provider Provider
+	// TrustEmail links the identity to the existing user with the email the
+	// provider verified.
+	TrustEmail bool
+	// Provision registers users signing in for the first time.
+	Provision bool
+}
+
+type StateSaver interface {
+	SaveFederationState(ctx context.Context, state models.FederationState, stateHash string) error
+	ConsumeFederationState(ctx context.Context, stateHash string) (models.FederationState, error)
+}
+
+type IdentitySaver interface {
+	SaveIdentity(ctx context.Context, identity models.Identity) (models.Identity, error)
+	TouchIdentity(ctx context.Context, id int64, email string, at time.Time) error
+}
+
+type IdentityProvider interface {
+	Identity(ctx context.Context, provider, subject string) (models.Identity, error)
+}
+
+type UserProvider interface {
+	User(ctx context.Context, email string) (models.User, error)
+}
+
+type AppProvider interface {
+	App(ctx context.Context, appID int) (models.App, error)
+}
+
+// Authenticator provisions users and issues their tokens, see auth.Auth.
+type Authenticator interface {
+	ProvisionUser(ctx context.Context, email, usernameHint string, appID int) (int64, error)
+	FederatedToken(ctx context.Context, userID int64, appID int, scopes []string) (string, error)
+}
+
+// Login is a completed sign in.
+type Login struct {
+	// Token is empty when the identity was linked rather than signed in with.
+	Token string
+	// ReturnTo is the redirect URI of the app the sign in was started with.
+	ReturnTo string
+	// Linked is set when the identity was linked to the user who started StartLink.
+	Linked bool
+}
+
+var (
+	ErrUnknownProvider  = errors.New("unknown identity provider")
+	ErrAppNotFound      = errors.New("app not found")
+	ErrAppDisabled      = errors.New("app is disabled")
+	ErrGrantNotAllowed  = errors.New("federated login is not allowed for the app")
+	ErrInvalidReturnTo  = errors.New("return_to is not a redirect URI of the app")
+	ErrInvalidState     = errors.New("invalid or expired state")
+	ErrUpstream         = errors.New("identity provider rejected the sign in")
+	ErrEmailRequired    = errors.New("identity provider didn't return email")
+	ErrEmailNotVerified = errors.New("email is not verified by identity provider")
+	ErrAccountExists    = errors.New("account with the email exists and can't be linked")
+	ErrNotProvisioned   = errors.New("no account is linked to the identity")
+	ErrIdentityLinked   = errors.New("identity is linked to another account")
+)
+
+// New returns a new instance of Federation service. Sign ins must be
+// completed within stateTTL.
+func New(
+	log *slog.Logger,
+	upstreams []Upstream,
+	stateSaver StateSaver,
+	identitySaver IdentitySaver,
+	identityProvider IdentityProvider,
+	userProvider UserProvider,
+	appProvider AppProvider,
+	auth Authenticator,
+	stateTTL time.Duration,
+) *Federation {
+	return &Federation{
+		log:              log,
+		upstreams:        upstreams,
+		stateSaver:       stateSaver,
+		identitySaver:    identitySaver,
+		identityProvider: identityProvider,
+		userProvider:     userProvider,
+		appProvider:      appProvider,
+		auth:             auth,
+		stateTTL:         stateTTL,
+	}
+}
+
+// Providers returns names of the configured providers.
+func (f *Federation) Providers() []string {
+	names := make([]string, 0, len(f.upstreams))
+	for _, upstream := range f.upstreams {
+		names = append(names, upstream.Name)
+	}
+
+	return names
+}
+
+// Start starts sign in to the app through the provider and returns URL the
+// user is redirected to. The app must allow the "federated" grant type,
+// returnTo, if set, must be one of its redirect URIs. The state is only
+// in the URL, only its hash is stored.
+func (f *Federation) Start(
+	ctx context.Context,
+	provider string,
+	appID int,
+	scopes []string,
+	returnTo string,
+) (string, error) {
+	const op = "Federation.Start"
+
+	log := f.log.With(
+		slog.String("op", op),
+		slog.String("provider", provider),
+		slog.Int("app_id", appID),
+	)
+
+	authURL, err := f.start(ctx, log, models.FederationState{
+		Provider: provider,
+		AppID:    appID,
+		Scopes:   scopes,
+		ReturnTo: returnTo,
+	})
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("federated login started")
+
+	return authURL, nil
+}
+
+// StartLink starts linking identity at the provider to the signed in user
+// and returns URL the user is redirected to. Completing it links the
+// identity, no token is issued. The user must be re-authenticated by the
+// caller.
+func (f *Federation) StartLink(
+	ctx context.Context,
+	userID int64,
+	provider string,
+	appID int,
+	returnTo string,
+) (string, error) {
+	const op = "Federation.StartLink"
+
+	log := f.log.With(
+		slog.String("op", op),
+		slog.String("provider", provider),
+		slog.Int64("user_id", userID),
+	)
+
+	authURL, err := f.start(ctx, log, models.FederationState{
+		Provider:   provider,
+		AppID:      appID,
+		ReturnTo:   returnTo,
+		LinkUserID: userID,
+	})
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("identity linking started")
+
+	return authURL, nil
+}
+
+// start saves the state of sign in and returns URL of the provider.
+func (f *Federation) start(ctx context.Context, log *slog.Logger, st models.FederationState) (string, error) {
+	upstream, ok := f.upstream(st.Provider)
+	if !ok {
+		return "", ErrUnknownProvider
+	}
+
+	app, err := f.appProvider.App(ctx, st.AppID)
+	if err != nil {
+		return "", mapStorageErr(err)
+	}
+
+	switch {
+	case app.Disabled:
+		return "", ErrAppDisabled
+	case st.LinkUserID == 0 && !app.AllowsGrant(models.GrantFederated):
+		return "", ErrGrantNotAllowed
+	case st.ReturnTo != "" && !slices.Contains(app.RedirectURIs, st.ReturnTo):
+		return "", ErrInvalidReturnTo
+	}
+
+	var values [3]string
+	for i := range values {
+		values[i], err = secrets.Generate(secrets.DefaultSize)
+		if err != nil {
+			return "", err
+		}
+	}
+	state := values[0]
+	st.Nonce, st.CodeVerifier = values[1], values[2]
+	st.ExpiresAt = time.Now().Add(f.stateTTL)
+
+	authURL, err := upstream.Provider.AuthCodeURL(ctx, state, st.Nonce, st.CodeVerifier)
+	if err != nil {
+		log.Error("failed to build authorization URL", slog.String("error", err.Error()))
+
+		return "", err
+	}
+
+	if err := f.stateSaver.SaveFederationState(ctx, st, secrets.Hash(state)); err != nil {
+		log.Error("failed to save state", slog.String("error", err.Error()))
+
+		return "", mapStorageErr(err)
+	}
+
+	return authURL, nil
+}
+
+// Complete completes sign in with the state and authorization code the
+// provider redirected the user back with, and issues token for the app.
+// Linking started with StartLink is completed without issuing token. If
+// the user must confirm the sign in with second factor, the error carries
+// the challenge and the returned login has ReturnTo set.
+//
+// The user is the one the identity at the provider is linked to. An
+// identity seen for the first time is linked to the user with the email
+// the provider verified, if the provider is trusted to, or to a newly
+// provisioned user, if the provider allows provisioning.
+func (f *Federation) Complete(ctx context.Context, state, code string) (Login, error) {
+	const op = "Federation.Complete"
+
+	log := f.log.With(
+		slog.String("op", op),
+	)
+
+	st, err := f.stateSaver.ConsumeFederationState(ctx, secrets.Hash(state))
+	if err != nil {
+		if errors.Is(err, storage.ErrStateNotFound) {
+			return Login{}, fmt.Errorf("%s: %w", op, ErrInvalidState)
+		}
+
+		return Login{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log = log.With(
+		slog.String("provider", st.Provider),
+		slog.Int("app_id", st.AppID),
+	)
+
+	if st.Expired(time.Now()) {
+		return Login{}, fmt.Errorf("%s: %w", op, ErrInvalidState)
+	}
+
+	upstream, ok := f.upstream(st.Provider)
+	if !ok {
+		return Login{}, fmt.Errorf("%s: %w", op, ErrUnknownProvider)
+	}
+
+	claims, err := upstream.Provider.Exchange(ctx, code, st.CodeVerifier, st.Nonce)
+	if err != nil {
+		log.Warn("upstream sign in failed", slog.String("error", err.Error()))
+
+		return Login{}, fmt.Errorf("%s: %w: %w", op, ErrUpstream, err)
+	}
+
+	if st.LinkUserID != 0 {
+		if err := f.link(ctx, log, upstream, st.LinkUserID, claims); err != nil {
+			return Login{}, fmt.Errorf("%s: %w", op, err)
+		}
+
+		return Login{ReturnTo: st.ReturnTo, Linked: true}, nil
+	}
+
+	userID, err := f.resolveUser(ctx, log, upstream, st.AppID, claims)
+	if err != nil {
+		return Login{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	token, err := f.auth.FederatedToken(ctx, userID, st.AppID, st.Scopes)
+	if err != nil {
+		log.Warn("failed to issue token", slog.Int64("user_id", userID), slog.String("error", err.Error()))
+
+		return Login{ReturnTo: st.ReturnTo}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("federated login completed", slog.Int64("user_id", userID))
+
+	return Login{Token: token, ReturnTo: st.ReturnTo}, nil
+}
+
+// resolveUser returns ID of the user the identity is linked to, linking it
+// on the first sign in.
+func (f *Federation) resolveUser(
+	ctx context.Context,
+	log *slog.Logger,
+	upstream Upstream,
+	appID int,
+	claims oidc.Claims,
+) (int64, error) {
+	now := time.Now()
+
+	identity, err := f.identityProvider.Identity(ctx, upstream.Name, claims.Subject)
+	switch {
+	case err == nil:
+		if err := f.identitySaver.TouchIdentity(ctx, identity.ID, claims.Email, now); err != nil {
+			return 0, err
+		}
+
+		return identity.UserID, nil
+	case !errors.Is(err, storage.ErrIdentityNotFound):
+		return 0, err
+	}
+
+	if claims.Email == "" {
+		return 0, ErrEmailRequired
+	}
+
+	if !claims.EmailVerified {
+		return 0, ErrEmailNotVerified
+	}
+
+	var userID int64
+
+	user, err := f.userProvider.User(ctx, claims.Email)
+	switch {
+	case err == nil:
+		if !upstream.TrustEmail || user.IsServiceAccount() {
+			log.Warn("identity not linked to existing account", slog.Int64("user_id", user.ID))
+
+			return 0, ErrAccountExists
+		}
+
+		userID = user.ID
+	case errors.Is(err, storage.ErrUserNotFound):
+		if !upstream.Provision {
+			return 0, ErrNotProvisioned
+		}
+
+		userID, err = f.auth.ProvisionUser(ctx, claims.Email, claims.PreferredUsername, appID)
+		if err != nil {
+			return 0, err
+		}
+	default:
+		return 0, err
+	}
+
+	_, err = f.identitySaver.SaveIdentity(ctx, models.Identity{
+		UserID:      userID,
+		Provider:    upstream.Name,
+		Subject:     claims.Subject,
+		Email:       claims.Email,
+		LastLoginAt: now,
+	})
+	if err != nil {
+		// Concurrent first sign in linked the identity already.
+		if errors.Is(err, storage.ErrIdentityExists) {
+			identity, err := f.identityProvider.Identity(ctx, upstream.Name, claims.Subject)
+			if err != nil {
+				return 0, err
+			}
+
+			return identity.UserID, nil
+		}
+
+		return 0, err
+	}
+
+	log.Info("identity linked", slog.Int64("user_id", userID))
+
+	return userID, nil
+}
+
+// link links the identity to the user, the identity must not be linked to
+// another user.
+func (f *Federation) link(
+	ctx context.Context,
+	log *slog.Logger,
+	upstream Upstream,
+	userID int64,
+	claims oidc.Claims,
+) error {
+	now := time.Now()
+
+	identity, err := f.identityProvider.Identity(ctx, upstream.Name, claims.Subject)
+	switch {
+	case err == nil:
+		if identity.UserID != userID {
+			log.Warn("identity is linked to another account", slog.Int64("user_id", userID))
+
+			return ErrIdentityLinked
+		}
+
+		return f.identitySaver.TouchIdentity(ctx, identity.ID, claims.Email, now)
+	case !errors.Is(err, storage.ErrIdentityNotFound):
+		return err
+	}
+
+	_, err = f.identitySaver.SaveIdentity(ctx, models.Identity{
+		UserID:      userID,
+		Provider:    upstream.Name,
+		Subject:     claims.Subject,
+		Email:       claims.Email,
+		LastLoginAt: now,
+	})
+	if err != nil {
+		if errors.Is(err, storage.ErrIdentityExists) {
+			return ErrIdentityLinked
+		}
+
+		return err
+	}
+
+	log.Info("identity linked", slog.Int64("user_id", userID))
+
+	return nil
+}
+
+func (f *Federation) upstream(name string) (Upstream, bool) {
+	for _, upstream := range f.upstreams {
+		if upstream.Name == name {
+			return upstream, true
+		}
+	}
+
+	return Upstream{}, false
+}
+
+func mapStorageErr(err error) error {
+	if errors.Is(err, storage.ErrAppNotFound) {
+		return ErrAppNotFound
+	}
+
+	return err
+}
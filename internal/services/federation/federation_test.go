@@ -0,0 +1,325 @@
+package federation
+
+import (
+	"SSO/internal/domain/models"
+	"SSO/internal/lib/oidc"
+	"SSO/internal/lib/oidc/oidctest"
+	"SSO/internal/storage"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	appID       = 1
+	redirectURI = "https://sso.example/federation/callback"
+	returnTo    = "https://app.example/callback"
+)
+
+// memStorage keeps states, identities, users and apps in memory.
+type memStorage struct {
+	mu         sync.Mutex
+	states     map[string]models.FederationState
+	identities []models.Identity
+	users      map[string]models.User
+	apps       map[int]models.App
+}
+
+func (s *memStorage) SaveFederationState(_ context.Context, state models.FederationState, stateHash string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.states[stateHash] = state
+
+	return nil
+}
+
+func (s *memStorage) ConsumeFederationState(_ context.Context, stateHash string) (models.FederationState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, ok := s.states[stateHash]
+	if !ok {
+		return models.FederationState{}, storage.ErrStateNotFound
+	}
+	delete(s.states, stateHash)
+
+	return state, nil
+}
+
+func (s *memStorage) SaveIdentity(_ context.Context, identity models.Identity) (models.Identity, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, i := range s.identities {
+		if i.Provider == identity.Provider && i.Subject == identity.Subject {
+			return models.Identity{}, storage.ErrIdentityExists
+		}
+	}
+
+	identity.ID = int64(len(s.identities) + 1)
+	s.identities = append(s.identities, identity)
+
+	return identity, nil
+}
+
+func (s *memStorage) TouchIdentity(_ context.Context, id int64, email string, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.identities[id-1].Email = email
+	s.identities[id-1].LastLoginAt = at
+
+	return nil
+}
+
+func (s *memStorage) Identity(_ context.Context, provider, subject string) (models.Identity, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, i := range s.identities {
+		if i.Provider == provider && i.Subject == subject {
+			return i, nil
+		}
+	}
+
+	return models.Identity{}, storage.ErrIdentityNotFound
+}
+
+func (s *memStorage) User(_ context.Context, email string) (models.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, ok := s.users[email]
+	if !ok {
+		return models.User{}, storage.ErrUserNotFound
+	}
+
+	return user, nil
+}
+
+func (s *memStorage) App(_ context.Context, id int) (models.App, error) {
+	app, ok := s.apps[id]
+	if !ok {
+		return models.App{}, storage.ErrAppNotFound
+	}
+
+	return app, nil
+}
+
+// memAuth provisions users into memStorage and issues fake tokens.
+type memAuth struct {
+	storage *memStorage
+}
+
+func (a *memAuth) ProvisionUser(_ context.Context, email, _ string, _ int) (int64, error) {
+	a.storage.mu.Lock()
+	defer a.storage.mu.Unlock()
+
+	user := models.User{ID: int64(100 + len(a.storage.users)), Email: email}
+	a.storage.users[email] = user
+
+	return user.ID, nil
+}
+
+func (a *memAuth) FederatedToken(_ context.Context, userID int64, appID int, _ []string) (string, error) {
+	return fmt.Sprintf("token-%d-%d", userID, appID), nil
+}
+
+type fixture struct {
+	upstream   *oidctest.Provider
+	storage    *memStorage
+	federation *Federation
+}
+
+func newFixture(t *testing.T, trustEmail, provision bool) *fixture {
+	t.Helper()
+
+	upstream, err := oidctest.New("sso", "secret")
+	require.NoError(t, err)
+	t.Cleanup(upstream.Close)
+
+	s := &memStorage{
+		states: make(map[string]models.FederationState),
+		users: map[string]models.User{
+			"jane@corp.example": {ID: 7, Email: "jane@corp.example"},
+		},
+		apps: map[int]models.App{
+			appID: {
+				ID:           appID,
+				RedirectURIs: []string{returnTo},
+				GrantTypes:   []string{models.GrantFederated},
+			},
+			2: {ID: 2, GrantTypes: []string{models.GrantPassword}},
+		},
+	}
+
+	provider := oidc.New(oidc.Config{
+		Issuer:       upstream.Issuer(),
+		ClientID:     "sso",
+		ClientSecret: "secret",
+		RedirectURI:  redirectURI,
+	}, nil)
+
+	f := New(
+		slog.New(slog.NewTextHandler(io.Discard, nil)),
+		[]Upstream{{Name: "corp", Provider: provider, TrustEmail: trustEmail, Provision: provision}},
+		s, s, s, s, s,
+		&memAuth{storage: s},
+		time.Minute,
+	)
+
+	return &fixture{upstream: upstream, storage: s, federation: f}
+}
+
+// signIn goes through the whole flow as the user at the upstream provider.
+func (f *fixture) signIn(t *testing.T, user oidctest.User) (Login, error) {
+	t.Helper()
+
+	ctx := context.Background()
+
+	authURL, err := f.federation.Start(ctx, "corp", appID, nil, returnTo)
+	require.NoError(t, err)
+
+	code, state, err := f.upstream.Authorize(authURL, user)
+	require.NoError(t, err)
+
+	return f.federation.Complete(ctx, state, code)
+}
+
+func TestComplete(t *testing.T) {
+	t.Run("provisions new user", func(t *testing.T) {
+		f := newFixture(t, true, true)
+		user := oidctest.User{Subject: "1", Email: "john@corp.example", EmailVerified: true}
+
+		login, err := f.signIn(t, user)
+		require.NoError(t, err)
+		assert.Equal(t, Login{Token: "token-101-1", ReturnTo: returnTo}, login)
+
+		// The identity is linked, the next sign in doesn't provision again.
+		login, err = f.signIn(t, user)
+		require.NoError(t, err)
+		assert.Equal(t, "token-101-1", login.Token)
+		assert.Len(t, f.storage.users, 2)
+		assert.Len(t, f.storage.identities, 1)
+	})
+
+	t.Run("links existing user by verified email", func(t *testing.T) {
+		f := newFixture(t, true, false)
+
+		login, err := f.signIn(t, oidctest.User{Subject: "2", Email: "jane@corp.example", EmailVerified: true})
+		require.NoError(t, err)
+		assert.Equal(t, "token-7-1", login.Token)
+		require.Len(t, f.storage.identities, 1)
+		assert.Equal(t, int64(7), f.storage.identities[0].UserID)
+	})
+
+	t.Run("untrusted provider", func(t *testing.T) {
+		f := newFixture(t, false, true)
+
+		_, err := f.signIn(t, oidctest.User{Subject: "2", Email: "jane@corp.example", EmailVerified: true})
+		assert.ErrorIs(t, err, ErrAccountExists)
+	})
+
+	t.Run("unverified email", func(t *testing.T) {
+		f := newFixture(t, true, true)
+
+		_, err := f.signIn(t, oidctest.User{Subject: "2", Email: "jane@corp.example"})
+		assert.ErrorIs(t, err, ErrEmailNotVerified)
+		assert.Empty(t, f.storage.identities)
+	})
+
+	t.Run("provisioning disabled", func(t *testing.T) {
+		f := newFixture(t, true, false)
+
+		_, err := f.signIn(t, oidctest.User{Subject: "1", Email: "john@corp.example", EmailVerified: true})
+		assert.ErrorIs(t, err, ErrNotProvisioned)
+	})
+
+	t.Run("state is single use", func(t *testing.T) {
+		f := newFixture(t, true, true)
+		ctx := context.Background()
+
+		authURL, err := f.federation.Start(ctx, "corp", appID, nil, "")
+		require.NoError(t, err)
+
+		user := oidctest.User{Subject: "1", Email: "john@corp.example", EmailVerified: true}
+		code, state, err := f.upstream.Authorize(authURL, user)
+		require.NoError(t, err)
+
+		_, err = f.federation.Complete(ctx, state, "wrong")
+		assert.ErrorIs(t, err, ErrUpstream)
+
+		_, err = f.federation.Complete(ctx, state, code)
+		assert.ErrorIs(t, err, ErrInvalidState)
+	})
+}
+
+func TestStart(t *testing.T) {
+	f := newFixture(t, true, true)
+	ctx := context.Background()
+
+	authURL, err := f.federation.Start(ctx, "corp", appID, nil, "")
+	require.NoError(t, err)
+
+	u, err := url.Parse(authURL)
+	require.NoError(t, err)
+	assert.Equal(t, f.upstream.Issuer()+"/authorize", u.Scheme+"://"+u.Host+u.Path)
+	assert.Equal(t, redirectURI, u.Query().Get("redirect_uri"))
+
+	_, err = f.federation.Start(ctx, "other", appID, nil, "")
+	assert.ErrorIs(t, err, ErrUnknownProvider)
+
+	_, err = f.federation.Start(ctx, "corp", appID, nil, "https://evil.example/callback")
+	assert.ErrorIs(t, err, ErrInvalidReturnTo)
+
+	_, err = f.federation.Start(ctx, "corp", 2, nil, "")
+	assert.ErrorIs(t, err, ErrGrantNotAllowed)
+
+	_, err = f.federation.Start(ctx, "corp", 3, nil, "")
+	assert.ErrorIs(t, err, ErrAppNotFound)
+}
+
+func TestLink(t *testing.T) {
+	f := newFixture(t, false, false)
+	ctx := context.Background()
+
+	link := func(userID int64, user oidctest.User) (Login, error) {
+		authURL, err := f.federation.StartLink(ctx, userID, "corp", appID, returnTo)
+		require.NoError(t, err)
+
+		code, state, err := f.upstream.Authorize(authURL, user)
+		require.NoError(t, err)
+
+		return f.federation.Complete(ctx, state, code)
+	}
+
+	// Linking doesn't need verified email nor the app to allow federated login.
+	user := oidctest.User{Subject: "3", Email: "jane@personal.example"}
+	login, err := link(7, user)
+	require.NoError(t, err)
+	assert.Equal(t, Login{ReturnTo: returnTo, Linked: true}, login)
+	require.Len(t, f.storage.identities, 1)
+	assert.Equal(t, int64(7), f.storage.identities[0].UserID)
+
+	// Linking again is a no-op.
+	_, err = link(7, user)
+	require.NoError(t, err)
+	assert.Len(t, f.storage.identities, 1)
+
+	_, err = link(8, user)
+	assert.ErrorIs(t, err, ErrIdentityLinked)
+
+	// The linked identity signs the user in.
+	login, err = f.signIn(t, user)
+	require.NoError(t, err)
+	assert.Equal(t, "token-7-1", login.Token)
+}
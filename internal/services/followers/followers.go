@@ -0,0 +1,394 @@
+package followers
+
+import (
+	"SSO/internal/domain/models"
+	"SSO/internal/lib/jwt"
+	"SSO/internal/storage"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+)
+
+const (
+	// DefaultLimit is the number of users returned when limit isn't given.
+	DefaultLimit = 50
+	// MaxLimit is the largest number of users returned at once.
+	MaxLimit = 500
+)
+
+// Followers lets users follow each other. Follows of private accounts
+// are requests until the account approves them.
+type Followers struct {
+	log            *slog.Logger
+	followSaver    FollowSaver
+	followProvider FollowProvider
+	usrProvider    UserProvider
+}
+
+type FollowSaver interface {
+	SaveFollow(ctx context.Context, followerID, followingID int64) (string, error)
+	DeleteFollow(ctx context.Context, followerID, followingID int64) error
+	AcceptFollow(ctx context.Context, followerID, followingID int64) error
+	DeleteFollowRequest(ctx context.Context, followerID, followingID int64) error
+	SetUserPrivate(ctx context.Context, userID int64, private bool) (int64, error)
+}
+
+type FollowProvider interface {
+	Followers(ctx context.Context, userID int64, status string, beforeID int64, limit int) ([]models.FollowEntry, error)
+	Following(ctx context.Context, userID int64, status string, beforeID int64, limit int) ([]models.FollowEntry, error)
+	FollowCounts(ctx context.Context, userIDs []int64) ([]models.FollowCounts, error)
+	FollowsBetween(ctx context.Context, userID int64, otherIDs []int64) ([]models.Follow, error)
+}
+
+type UserProvider interface {
+	UserByID(ctx context.Context, userID int64) (models.User, error)
+}
+
+var (
+	ErrUserNotFound     = errors.New("user not found")
+	ErrSelfFollow       = errors.New("users can't follow themselves")
+	ErrCallerNotAllowed = errors.New("service accounts can't follow or be followed")
+	ErrTooManyUsers     = errors.New("too many users requested at once")
+	ErrRequestNotFound  = errors.New("follow request not found")
+)
+
+// New returns a new instance of Followers service.
+func New(
+	log *slog.Logger,
+	followSaver FollowSaver,
+	followProvider FollowProvider,
+	userProvider UserProvider,
+) *Followers {
+	return &Followers{
+		log:            log,
+		followSaver:    followSaver,
+		followProvider: followProvider,
+		usrProvider:    userProvider,
+	}
+}
+
+// Follow makes the caller follow the user and returns status of the
+// follow, models.FollowPending if the user's account is private. Following
+// the user again is not an error. Service accounts can neither follow nor
+// be followed.
+func (f *Followers) Follow(ctx context.Context, caller jwt.Claims, userID int64) (string, error) {
+	const op = "Followers.Follow"
+
+	log := f.log.With(
+		slog.String("op", op),
+		slog.Int64("follower_id", caller.UserID),
+		slog.Int64("following_id", userID),
+	)
+
+	if caller.ServiceAccount {
+		return "", fmt.Errorf("%s: %w", op, ErrCallerNotAllowed)
+	}
+
+	if userID == caller.UserID {
+		return "", fmt.Errorf("%s: %w", op, ErrSelfFollow)
+	}
+
+	if _, err := f.user(ctx, userID); err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	status, err := f.followSaver.SaveFollow(ctx, caller.UserID, userID)
+	if err != nil {
+		log.Error("failed to save follow", slog.String("error", err.Error()))
+
+		return "", fmt.Errorf("%s: %w", op, mapStorageErr(err))
+	}
+
+	log.Info("user followed", slog.String("status", status))
+
+	return status, nil
+}
+
+// Unfollow makes the caller stop following the user or cancels the
+// request to follow. Unfollowing a user who isn't followed is not an error.
+func (f *Followers) Unfollow(ctx context.Context, caller jwt.Claims, userID int64) error {
+	const op = "Followers.Unfollow"
+
+	log := f.log.With(
+		slog.String("op", op),
+		slog.Int64("follower_id", caller.UserID),
+		slog.Int64("following_id", userID),
+	)
+
+	if err := f.followSaver.DeleteFollow(ctx, caller.UserID, userID); err != nil {
+		log.Error("failed to delete follow", slog.String("error", err.Error()))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("user unfollowed")
+
+	return nil
+}
+
+// Followers returns a page of followers of the user, most recent first.
+// Cursor is NextCursor of the previous page, 0 for the first one.
+func (f *Followers) Followers(ctx context.Context, userID, cursor int64, limit int) (models.FollowPage, error) {
+	const op = "Followers.Followers"
+
+	page, err := f.page(ctx, userID, models.FollowAccepted, cursor, limit, f.followProvider.Followers)
+	if err != nil {
+		return models.FollowPage{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return page, nil
+}
+
+// Following returns a page of users the user follows, most recently
+// followed first. Cursor is NextCursor of the previous page, 0 for the
+// first one.
+func (f *Followers) Following(ctx context.Context, userID, cursor int64, limit int) (models.FollowPage, error) {
+	const op = "Followers.Following"
+
+	page, err := f.page(ctx, userID, models.FollowAccepted, cursor, limit, f.followProvider.Following)
+	if err != nil {
+		return models.FollowPage{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return page, nil
+}
+
+// SetPrivate sets whether follows of the caller need the caller's
+// approval. Pending requests are approved when the account goes public.
+func (f *Followers) SetPrivate(ctx context.Context, caller jwt.Claims, private bool) error {
+	const op = "Followers.SetPrivate"
+
+	log := f.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", caller.UserID),
+		slog.Bool("private", private),
+	)
+
+	if caller.ServiceAccount {
+		return fmt.Errorf("%s: %w", op, ErrCallerNotAllowed)
+	}
+
+	approved, err := f.followSaver.SetUserPrivate(ctx, caller.UserID, private)
+	if err != nil {
+		log.Error("failed to set account privacy", slog.String("error", err.Error()))
+
+		return fmt.Errorf("%s: %w", op, mapStorageErr(err))
+	}
+
+	log.Info("account privacy set", slog.Int64("approved_requests", approved))
+
+	return nil
+}
+
+// Approve approves the request of the follower to follow the caller.
+func (f *Followers) Approve(ctx context.Context, caller jwt.Claims, followerID int64) error {
+	const op = "Followers.Approve"
+
+	log := f.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", caller.UserID),
+		slog.Int64("follower_id", followerID),
+	)
+
+	if err := f.followSaver.AcceptFollow(ctx, followerID, caller.UserID); err != nil {
+		return fmt.Errorf("%s: %w", op, mapStorageErr(err))
+	}
+
+	log.Info("follow request approved")
+
+	return nil
+}
+
+// Deny denies the request of the follower to follow the caller. The
+// follower may request again.
+func (f *Followers) Deny(ctx context.Context, caller jwt.Claims, followerID int64) error {
+	const op = "Followers.Deny"
+
+	log := f.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", caller.UserID),
+		slog.Int64("follower_id", followerID),
+	)
+
+	if err := f.followSaver.DeleteFollowRequest(ctx, followerID, caller.UserID); err != nil {
+		return fmt.Errorf("%s: %w", op, mapStorageErr(err))
+	}
+
+	log.Info("follow request denied")
+
+	return nil
+}
+
+// IncomingRequests returns a page of pending requests to follow the
+// caller, most recent first.
+func (f *Followers) IncomingRequests(ctx context.Context, caller jwt.Claims, cursor int64, limit int) (models.FollowPage, error) {
+	const op = "Followers.IncomingRequests"
+
+	page, err := f.page(ctx, caller.UserID, models.FollowPending, cursor, limit, f.followProvider.Followers)
+	if err != nil {
+		return models.FollowPage{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return page, nil
+}
+
+// OutgoingRequests returns a page of pending requests of the caller to
+// follow users, most recent first.
+func (f *Followers) OutgoingRequests(ctx context.Context, caller jwt.Claims, cursor int64, limit int) (models.FollowPage, error) {
+	const op = "Followers.OutgoingRequests"
+
+	page, err := f.page(ctx, caller.UserID, models.FollowPending, cursor, limit, f.followProvider.Following)
+	if err != nil {
+		return models.FollowPage{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return page, nil
+}
+
+// Counts returns follower and following counts of the users, in the order
+// of userIDs. Users that don't exist are skipped. At most MaxLimit users
+// can be requested at once.
+func (f *Followers) Counts(ctx context.Context, userIDs []int64) ([]models.FollowCounts, error) {
+	const op = "Followers.Counts"
+
+	if len(userIDs) > MaxLimit {
+		return nil, fmt.Errorf("%s: %w", op, ErrTooManyUsers)
+	}
+
+	counts, err := f.followProvider.FollowCounts(ctx, userIDs)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	byUser := make(map[int64]models.FollowCounts, len(counts))
+	for _, c := range counts {
+		byUser[c.UserID] = c
+	}
+
+	result := make([]models.FollowCounts, 0, len(counts))
+	for _, id := range userIDs {
+		if c, ok := byUser[id]; ok {
+			result = append(result, c)
+		}
+	}
+
+	return result, nil
+}
+
+// Relationship returns how the user and the other user follow each other.
+func (f *Followers) Relationship(ctx context.Context, caller jwt.Claims, userID, otherID int64) (models.Relationship, error) {
+	const op = "Followers.Relationship"
+
+	relationships, err := f.Relationships(ctx, caller, userID, []int64{otherID})
+	if err != nil {
+		return models.Relationship{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return relationships[0], nil
+}
+
+// Relationships returns how the user and each of the other users follow
+// each other, in the order of otherIDs. Users that don't exist neither
+// follow nor are followed. At most MaxLimit users can be requested at once.
+//
+// Pending requests are private to the users they are between, so they are
+// only reported to the caller if it is one of the two users.
+func (f *Followers) Relationships(ctx context.Context, caller jwt.Claims, userID int64, otherIDs []int64) ([]models.Relationship, error) {
+	const op = "Followers.Relationships"
+
+	if len(otherIDs) > MaxLimit {
+		return nil, fmt.Errorf("%s: %w", op, ErrTooManyUsers)
+	}
+
+	follows, err := f.followProvider.FollowsBetween(ctx, userID, otherIDs)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// Statuses of follows by the user and of the user, by the other user.
+	outgoing := make(map[int64]string, len(follows))
+	incoming := make(map[int64]string, len(follows))
+	for _, follow := range follows {
+		if follow.FollowerID == userID {
+			outgoing[follow.FollowingID] = follow.Status
+		} else {
+			incoming[follow.FollowerID] = follow.Status
+		}
+	}
+
+	relationships := make([]models.Relationship, 0, len(otherIDs))
+	for _, id := range otherIDs {
+		relationship := models.Relationship{
+			UserID:      userID,
+			OtherUserID: id,
+			Following:   outgoing[id] == models.FollowAccepted,
+			FollowedBy:  incoming[id] == models.FollowAccepted,
+		}
+		if caller.UserID == userID || caller.UserID == id {
+			relationship.Requested = outgoing[id] == models.FollowPending
+			relationship.RequestedBy = incoming[id] == models.FollowPending
+		}
+		relationships = append(relationships, relationship)
+	}
+
+	return relationships, nil
+}
+
+// page returns a page of the list of the user, fetching one more entry
+// than the limit to tell whether there is a next page.
+func (f *Followers) page(
+	ctx context.Context,
+	userID int64,
+	status string,
+	cursor int64,
+	limit int,
+	list func(ctx context.Context, userID int64, status string, beforeID int64, limit int) ([]models.FollowEntry, error),
+) (models.FollowPage, error) {
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+	limit = min(limit, MaxLimit)
+
+	if _, err := f.user(ctx, userID); err != nil {
+		return models.FollowPage{}, err
+	}
+
+	entries, err := list(ctx, userID, status, cursor, limit+1)
+	if err != nil {
+		return models.FollowPage{}, err
+	}
+
+	page := models.FollowPage{Entries: entries}
+	if len(entries) > limit {
+		page.Entries = entries[:limit]
+		page.NextCursor = page.Entries[limit-1].ID
+	}
+
+	return page, nil
+}
+
+// user returns the user, service accounts are reported as not found.
+func (f *Followers) user(ctx context.Context, userID int64) (models.User, error) {
+	user, err := f.usrProvider.UserByID(ctx, userID)
+	if err != nil {
+		return models.User{}, mapStorageErr(err)
+	}
+
+	if user.IsServiceAccount() {
+		return models.User{}, ErrUserNotFound
+	}
+
+	return user, nil
+}
+
+func mapStorageErr(err error) error {
+	switch {
+	case errors.Is(err, storage.ErrUserNotFound):
+		return ErrUserNotFound
+	case errors.Is(err, storage.ErrFollowRequestNotFound):
+		return ErrRequestNotFound
+	}
+
+	return err
+}
@@ -0,0 +1,342 @@
+package followers
+
+import (
+	"SSO/internal/domain/models"
+	"SSO/internal/lib/jwt"
+	"SSO/internal/storage"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memStorage keeps follows and users in memory.
+type memStorage struct {
+	follows []models.Follow
+	users   map[int64]models.User
+	private map[int64]bool
+	lastID  int64
+}
+
+func (s *memStorage) SaveFollow(_ context.Context, followerID, followingID int64) (string, error) {
+	for _, f := range s.follows {
+		if f.FollowerID == followerID && f.FollowingID == followingID {
+			return f.Status, nil
+		}
+	}
+	status := models.FollowAccepted
+	if s.private[followingID] {
+		status = models.FollowPending
+	}
+	s.lastID++
+	s.follows = append(s.follows, models.Follow{
+		ID:          s.lastID,
+		FollowerID:  followerID,
+		FollowingID: followingID,
+		Status:      status,
+	})
+
+	return status, nil
+}
+
+func (s *memStorage) DeleteFollow(_ context.Context, followerID, followingID int64) error {
+	for i, f := range s.follows {
+		if f.FollowerID == followerID && f.FollowingID == followingID {
+			s.follows = append(s.follows[:i], s.follows[i+1:]...)
+
+			return nil
+		}
+	}
+
+	return nil
+}
+
+func (s *memStorage) AcceptFollow(_ context.Context, followerID, followingID int64) error {
+	for i, f := range s.follows {
+		if f.FollowerID == followerID && f.FollowingID == followingID && f.Status == models.FollowPending {
+			s.follows[i].Status = models.FollowAccepted
+
+			return nil
+		}
+	}
+
+	return storage.ErrFollowRequestNotFound
+}
+
+func (s *memStorage) DeleteFollowRequest(_ context.Context, followerID, followingID int64) error {
+	for i, f := range s.follows {
+		if f.FollowerID == followerID && f.FollowingID == followingID && f.Status == models.FollowPending {
+			s.follows = append(s.follows[:i], s.follows[i+1:]...)
+
+			return nil
+		}
+	}
+
+	return storage.ErrFollowRequestNotFound
+}
+
+func (s *memStorage) SetUserPrivate(_ context.Context, userID int64, private bool) (int64, error) {
+	s.private[userID] = private
+
+	var approved int64
+	for i, f := range s.follows {
+		if !private && f.FollowingID == userID && f.Status == models.FollowPending {
+			s.follows[i].Status = models.FollowAccepted
+			approved++
+		}
+	}
+
+	return approved, nil
+}
+
+func (s *memStorage) Followers(
+	_ context.Context,
+	userID int64,
+	status string,
+	beforeID int64,
+	limit int,
+) ([]models.FollowEntry, error) {
+	return s.list(beforeID, limit, func(f models.Follow) (int64, bool) {
+		return f.FollowerID, f.FollowingID == userID && f.Status == status
+	}), nil
+}
+
+func (s *memStorage) Following(
+	_ context.Context,
+	userID int64,
+	status string,
+	beforeID int64,
+	limit int,
+) ([]models.FollowEntry, error) {
+	return s.list(beforeID, limit, func(f models.Follow) (int64, bool) {
+		return f.FollowingID, f.FollowerID == userID && f.Status == status
+	}), nil
+}
+
+func (s *memStorage) list(beforeID int64, limit int, match func(models.Follow) (int64, bool)) []models.FollowEntry {
+	var entries []models.FollowEntry
+	for i := len(s.follows) - 1; i >= 0 && len(entries) < limit; i-- {
+		f := s.follows[i]
+		if beforeID != 0 && f.ID >= beforeID {
+			continue
+		}
+		if userID, ok := match(f); ok {
+			entries = append(entries, models.FollowEntry{ID: f.ID, UserID: userID, Username: s.users[userID].Username})
+		}
+	}
+
+	return entries
+}
+
+func (s *memStorage) FollowCounts(_ context.Context, userIDs []int64) ([]models.FollowCounts, error) {
+	var counts []models.FollowCounts
+	for _, id := range userIDs {
+		if _, ok := s.users[id]; !ok {
+			continue
+		}
+		c := models.FollowCounts{UserID: id, Private: s.private[id]}
+		for _, f := range s.follows {
+			if f.Status != models.FollowAccepted {
+				continue
+			}
+			if f.FollowingID == id {
+				c.Followers++
+			}
+			if f.FollowerID == id {
+				c.Following++
+			}
+		}
+		counts = append(counts, c)
+	}
+
+	return counts, nil
+}
+
+func (s *memStorage) FollowsBetween(_ context.Context, userID int64, otherIDs []int64) ([]models.Follow, error) {
+	var follows []models.Follow
+	for _, f := range s.follows {
+		if f.FollowerID == userID && slices.Contains(otherIDs, f.FollowingID) ||
+			f.FollowingID == userID && slices.Contains(otherIDs, f.FollowerID) {
+			follows = append(follows, f)
+		}
+	}
+
+	return follows, nil
+}
+
+func (s *memStorage) UserByID(_ context.Context, userID int64) (models.User, error) {
+	user, ok := s.users[userID]
+	if !ok {
+		return models.User{}, storage.ErrUserNotFound
+	}
+
+	return user, nil
+}
+
+func newTestService() (*Followers, *memStorage) {
+	s := &memStorage{
+		users: map[int64]models.User{
+			100: {ID: 100, Username: "bot", Kind: models.UserKindService},
+		},
+		private: make(map[int64]bool),
+	}
+	for id := int64(1); id <= 5; id++ {
+		s.users[id] = models.User{ID: id, Username: fmt.Sprintf("user%d", id)}
+	}
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	return New(log, s, s, s), s
+}
+
+// follow makes the follower follow the user and returns status of the follow.
+func follow(t *testing.T, f *Followers, followerID, userID int64) string {
+	t.Helper()
+
+	status, err := f.Follow(context.Background(), jwt.Claims{UserID: followerID}, userID)
+	require.NoError(t, err)
+
+	return status
+}
+
+func TestFollow(t *testing.T) {
+	f, s := newTestService()
+	ctx := context.Background()
+	caller := jwt.Claims{UserID: 1}
+
+	_, err := f.Follow(ctx, caller, 1)
+	assert.ErrorIs(t, err, ErrSelfFollow)
+	_, err = f.Follow(ctx, caller, 42)
+	assert.ErrorIs(t, err, ErrUserNotFound)
+	_, err = f.Follow(ctx, caller, 100)
+	assert.ErrorIs(t, err, ErrUserNotFound, "service accounts can't be followed")
+	_, err = f.Follow(ctx, jwt.Claims{UserID: 100, ServiceAccount: true}, 1)
+	assert.ErrorIs(t, err, ErrCallerNotAllowed)
+
+	follow(t, f, 1, 2)
+	follow(t, f, 1, 2)
+	assert.Len(t, s.follows, 1, "follow is idempotent")
+
+	require.NoError(t, f.Unfollow(ctx, caller, 2))
+	require.NoError(t, f.Unfollow(ctx, caller, 2), "unfollow is idempotent")
+	assert.Empty(t, s.follows)
+}
+
+func TestPages(t *testing.T) {
+	f, _ := newTestService()
+	ctx := context.Background()
+
+	for id := int64(2); id <= 5; id++ {
+		follow(t, f, id, 1)
+	}
+	follow(t, f, 1, 3)
+
+	var usernames []string
+	var cursor int64
+	for {
+		page, err := f.Followers(ctx, 1, cursor, 3)
+		require.NoError(t, err)
+		for _, e := range page.Entries {
+			usernames = append(usernames, e.Username)
+		}
+		if page.NextCursor == 0 {
+			break
+		}
+		cursor = page.NextCursor
+	}
+	assert.Equal(t, []string{"user5", "user4", "user3", "user2"}, usernames, "most recent first")
+
+	page, err := f.Following(ctx, 1, 0, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []models.FollowEntry{{ID: 5, UserID: 3, Username: "user3"}}, page.Entries)
+	assert.Zero(t, page.NextCursor)
+
+	_, err = f.Followers(ctx, 42, 0, 0)
+	assert.ErrorIs(t, err, ErrUserNotFound)
+}
+
+func TestRelationships(t *testing.T) {
+	f, _ := newTestService()
+	ctx := context.Background()
+
+	follow(t, f, 1, 2)
+	follow(t, f, 2, 1)
+	follow(t, f, 1, 3)
+	follow(t, f, 4, 1)
+
+	rel, err := f.Relationship(ctx, jwt.Claims{UserID: 1}, 1, 2)
+	require.NoError(t, err)
+	assert.True(t, rel.Mutual())
+
+	rels, err := f.Relationships(ctx, jwt.Claims{UserID: 1}, 1, []int64{3, 4, 5, 42})
+	require.NoError(t, err)
+	assert.Equal(t, []models.Relationship{
+		{UserID: 1, OtherUserID: 3, Following: true},
+		{UserID: 1, OtherUserID: 4, FollowedBy: true},
+		{UserID: 1, OtherUserID: 5},
+		{UserID: 1, OtherUserID: 42},
+	}, rels)
+
+	counts, err := f.Counts(ctx, []int64{3, 42, 1})
+	require.NoError(t, err)
+	assert.Equal(t, []models.FollowCounts{
+		{UserID: 3, Followers: 1},
+		{UserID: 1, Followers: 2, Following: 2},
+	}, counts, "counts keep the requested order and skip unknown users")
+
+	_, err = f.Counts(ctx, make([]int64, MaxLimit+1))
+	assert.ErrorIs(t, err, ErrTooManyUsers)
+}
+
+func TestFollowRequests(t *testing.T) {
+	f, _ := newTestService()
+	ctx := context.Background()
+	owner := jwt.Claims{UserID: 1}
+
+	require.NoError(t, f.SetPrivate(ctx, owner, true))
+	assert.Equal(t, models.FollowPending, follow(t, f, 2, 1))
+	assert.Equal(t, models.FollowPending, follow(t, f, 3, 1))
+	assert.Equal(t, models.FollowPending, follow(t, f, 4, 1))
+	assert.Equal(t, models.FollowPending, follow(t, f, 2, 1), "request is idempotent")
+
+	rel, err := f.Relationship(ctx, jwt.Claims{UserID: 2}, 2, 1)
+	require.NoError(t, err)
+	assert.Equal(t, models.Relationship{UserID: 2, OtherUserID: 1, Requested: true}, rel)
+
+	rel, err = f.Relationship(ctx, owner, 2, 1)
+	require.NoError(t, err)
+	assert.True(t, rel.Requested, "request is reported to the requested user")
+
+	rel, err = f.Relationship(ctx, jwt.Claims{UserID: 3}, 2, 1)
+	require.NoError(t, err)
+	assert.Equal(t, models.Relationship{UserID: 2, OtherUserID: 1}, rel, "request is hidden from other users")
+
+	page, err := f.IncomingRequests(ctx, owner, 0, 0)
+	require.NoError(t, err)
+	require.Len(t, page.Entries, 3)
+	assert.Equal(t, "user4", page.Entries[0].Username)
+
+	page, err = f.OutgoingRequests(ctx, jwt.Claims{UserID: 2}, 0, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []models.FollowEntry{{ID: 1, UserID: 1, Username: "user1"}}, page.Entries)
+
+	require.NoError(t, f.Approve(ctx, owner, 2))
+	assert.ErrorIs(t, f.Approve(ctx, owner, 2), ErrRequestNotFound, "request is approved once")
+	require.NoError(t, f.Deny(ctx, owner, 3))
+	assert.ErrorIs(t, f.Deny(ctx, owner, 5), ErrRequestNotFound)
+
+	followers, err := f.Followers(ctx, 1, 0, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []models.FollowEntry{{ID: 1, UserID: 2, Username: "user2"}}, followers.Entries)
+
+	require.NoError(t, f.SetPrivate(ctx, owner, false))
+	counts, err := f.Counts(ctx, []int64{1})
+	require.NoError(t, err)
+	assert.Equal(t, []models.FollowCounts{{UserID: 1, Followers: 2}}, counts, "pending request is approved when account goes public")
+
+	assert.Equal(t, models.FollowAccepted, follow(t, f, 3, 1))
+}
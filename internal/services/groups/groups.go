@@ -0,0 +1,312 @@
+package groups
+
+import (
+	"SSO/internal/domain/models"
+	"SSO/internal/storage"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+)
+
+type Groups struct {
+	log           *slog.Logger
+	groupSaver    GroupSaver
+	groupProvider GroupProvider
+}
+
+type GroupSaver interface {
+	SaveGroup(ctx context.Context, appID int, name string) (int64, error)
+	DeleteGroup(ctx context.Context, appID int, groupID int64) error
+	SaveGroupMember(ctx context.Context, appID int, groupID, userID int64) error
+	DeleteGroupMember(ctx context.Context, appID int, groupID, userID int64) error
+	SaveSubgroup(ctx context.Context, appID int, parentID, childID int64) error
+	DeleteSubgroup(ctx context.Context, appID int, parentID, childID int64) error
+	AssignGroupRole(ctx context.Context, appID int, groupID, roleID int64) error
+	UnassignGroupRole(ctx context.Context, appID int, groupID, roleID int64) error
+	SetGroupClaims(ctx context.Context, appID int, enabled bool) error
+}
+
+type GroupProvider interface {
+	Groups(ctx context.Context, appID int) ([]models.Group, error)
+	GroupMembers(ctx context.Context, appID int, groupID int64) ([]models.GroupMember, error)
+	UserGroups(ctx context.Context, userID int64, appID int) ([]models.Group, error)
+}
+
+var (
+	ErrAppNotFound    = errors.New("app not found")
+	ErrUserNotFound   = errors.New("user not found")
+	ErrRoleNotFound   = errors.New("role not found")
+	ErrGroupExists    = errors.New("group already exists")
+	ErrGroupNotFound  = errors.New("group not found")
+	ErrMemberNotFound = errors.New("user is not a member of the group")
+	ErrGroupCycle     = errors.New("nesting would create a cycle of groups")
+)
+
+// New returns a new instance of Groups service.
+func New(
+	log *slog.Logger,
+	groupSaver GroupSaver,
+	groupProvider GroupProvider,
+) *Groups {
+	return &Groups{
+		log:           log,
+		groupSaver:    groupSaver,
+		groupProvider: groupProvider,
+	}
+}
+
+// CreateGroup creates group with given name in the app.
+func (g *Groups) CreateGroup(ctx context.Context, appID int, name string) (int64, error) {
+	const op = "Groups.CreateGroup"
+
+	log := g.log.With(
+		slog.String("op", op),
+		slog.Int("app_id", appID),
+		slog.String("group", name),
+	)
+
+	id, err := g.groupSaver.SaveGroup(ctx, appID, name)
+	if err != nil {
+		log.Error("failed to save group", slog.String("error", err.Error()))
+
+		return 0, fmt.Errorf("%s: %w", op, mapStorageErr(err))
+	}
+
+	log.Info("group created", slog.Int64("group_id", id))
+
+	return id, nil
+}
+
+// DeleteGroup deletes group of the app, its memberships and role assignments.
+func (g *Groups) DeleteGroup(ctx context.Context, appID int, groupID int64) error {
+	const op = "Groups.DeleteGroup"
+
+	log := g.log.With(
+		slog.String("op", op),
+		slog.Int("app_id", appID),
+		slog.Int64("group_id", groupID),
+	)
+
+	if err := g.groupSaver.DeleteGroup(ctx, appID, groupID); err != nil {
+		log.Error("failed to delete group", slog.String("error", err.Error()))
+
+		return fmt.Errorf("%s: %w", op, mapStorageErr(err))
+	}
+
+	log.Info("group deleted")
+
+	return nil
+}
+
+// Groups returns groups of the app.
+func (g *Groups) Groups(ctx context.Context, appID int) ([]models.Group, error) {
+	const op = "Groups.Groups"
+
+	groups, err := g.groupProvider.Groups(ctx, appID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return groups, nil
+}
+
+// AddMember adds user to group of the app.
+func (g *Groups) AddMember(ctx context.Context, appID int, groupID, userID int64) error {
+	const op = "Groups.AddMember"
+
+	log := g.log.With(
+		slog.String("op", op),
+		slog.Int("app_id", appID),
+		slog.Int64("group_id", groupID),
+		slog.Int64("user_id", userID),
+	)
+
+	if err := g.groupSaver.SaveGroupMember(ctx, appID, groupID, userID); err != nil {
+		log.Error("failed to add member", slog.String("error", err.Error()))
+
+		return fmt.Errorf("%s: %w", op, mapStorageErr(err))
+	}
+
+	log.Info("member added")
+
+	return nil
+}
+
+// RemoveMember removes user from group of the app.
+func (g *Groups) RemoveMember(ctx context.Context, appID int, groupID, userID int64) error {
+	const op = "Groups.RemoveMember"
+
+	log := g.log.With(
+		slog.String("op", op),
+		slog.Int("app_id", appID),
+		slog.Int64("group_id", groupID),
+		slog.Int64("user_id", userID),
+	)
+
+	if err := g.groupSaver.DeleteGroupMember(ctx, appID, groupID, userID); err != nil {
+		log.Error("failed to remove member", slog.String("error", err.Error()))
+
+		return fmt.Errorf("%s: %w", op, mapStorageErr(err))
+	}
+
+	log.Info("member removed")
+
+	return nil
+}
+
+// Members returns members of group of the app, including members of nested groups.
+func (g *Groups) Members(ctx context.Context, appID int, groupID int64) ([]models.GroupMember, error) {
+	const op = "Groups.Members"
+
+	members, err := g.groupProvider.GroupMembers(ctx, appID, groupID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, mapStorageErr(err))
+	}
+
+	return members, nil
+}
+
+// AddSubgroup nests child group into parent group so that members of the
+// child become members of the parent.
+func (g *Groups) AddSubgroup(ctx context.Context, appID int, parentID, childID int64) error {
+	const op = "Groups.AddSubgroup"
+
+	log := g.log.With(
+		slog.String("op", op),
+		slog.Int("app_id", appID),
+		slog.Int64("parent_id", parentID),
+		slog.Int64("child_id", childID),
+	)
+
+	if err := g.groupSaver.SaveSubgroup(ctx, appID, parentID, childID); err != nil {
+		log.Error("failed to add subgroup", slog.String("error", err.Error()))
+
+		return fmt.Errorf("%s: %w", op, mapStorageErr(err))
+	}
+
+	log.Info("subgroup added")
+
+	return nil
+}
+
+// RemoveSubgroup removes child group from parent group.
+func (g *Groups) RemoveSubgroup(ctx context.Context, appID int, parentID, childID int64) error {
+	const op = "Groups.RemoveSubgroup"
+
+	log := g.log.With(
+		slog.String("op", op),
+		slog.Int("app_id", appID),
+		slog.Int64("parent_id", parentID),
+		slog.Int64("child_id", childID),
+	)
+
+	if err := g.groupSaver.DeleteSubgroup(ctx, appID, parentID, childID); err != nil {
+		log.Error("failed to remove subgroup", slog.String("error", err.Error()))
+
+		return fmt.Errorf("%s: %w", op, mapStorageErr(err))
+	}
+
+	log.Info("subgroup removed")
+
+	return nil
+}
+
+// AssignRole assigns role of the app to group. Members of the group and
+// of its nested groups hold the role.
+func (g *Groups) AssignRole(ctx context.Context, appID int, groupID, roleID int64) error {
+	const op = "Groups.AssignRole"
+
+	log := g.log.With(
+		slog.String("op", op),
+		slog.Int("app_id", appID),
+		slog.Int64("group_id", groupID),
+		slog.Int64("role_id", roleID),
+	)
+
+	if err := g.groupSaver.AssignGroupRole(ctx, appID, groupID, roleID); err != nil {
+		log.Error("failed to assign role", slog.String("error", err.Error()))
+
+		return fmt.Errorf("%s: %w", op, mapStorageErr(err))
+	}
+
+	log.Info("role assigned")
+
+	return nil
+}
+
+// UnassignRole removes role of the app from group.
+func (g *Groups) UnassignRole(ctx context.Context, appID int, groupID, roleID int64) error {
+	const op = "Groups.UnassignRole"
+
+	log := g.log.With(
+		slog.String("op", op),
+		slog.Int("app_id", appID),
+		slog.Int64("group_id", groupID),
+		slog.Int64("role_id", roleID),
+	)
+
+	if err := g.groupSaver.UnassignGroupRole(ctx, appID, groupID, roleID); err != nil {
+		log.Error("failed to unassign role", slog.String("error", err.Error()))
+
+		return fmt.Errorf("%s: %w", op, mapStorageErr(err))
+	}
+
+	log.Info("role unassigned")
+
+	return nil
+}
+
+// UserGroups returns groups of the app user belongs to directly or through nested groups.
+func (g *Groups) UserGroups(ctx context.Context, appID int, userID int64) ([]models.Group, error) {
+	const op = "Groups.UserGroups"
+
+	groups, err := g.groupProvider.UserGroups(ctx, userID, appID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return groups, nil
+}
+
+// SetGroupClaims sets whether tokens issued for the app carry groups of the user.
+func (g *Groups) SetGroupClaims(ctx context.Context, appID int, enabled bool) error {
+	const op = "Groups.SetGroupClaims"
+
+	log := g.log.With(
+		slog.String("op", op),
+		slog.Int("app_id", appID),
+		slog.Bool("enabled", enabled),
+	)
+
+	if err := g.groupSaver.SetGroupClaims(ctx, appID, enabled); err != nil {
+		log.Error("failed to set group claims", slog.String("error", err.Error()))
+
+		return fmt.Errorf("%s: %w", op, mapStorageErr(err))
+	}
+
+	log.Info("group claims set")
+
+	return nil
+}
+
+func mapStorageErr(err error) error {
+	switch {
+	case errors.Is(err, storage.ErrAppNotFound):
+		return ErrAppNotFound
+	case errors.Is(err, storage.ErrUserNotFound):
+		return ErrUserNotFound
+	case errors.Is(err, storage.ErrRoleNotFound):
+		return ErrRoleNotFound
+	case errors.Is(err, storage.ErrGroupExists):
+		return ErrGroupExists
+	case errors.Is(err, storage.ErrGroupNotFound):
+		return ErrGroupNotFound
+	case errors.Is(err, storage.ErrMemberNotFound):
+		return ErrMemberNotFound
+	case errors.Is(err, storage.ErrGroupCycle):
+		return ErrGroupCycle
+	}
+
+	return err
+}
@@ -0,0 +1,262 @@
+package groups
+
+import (
+	"SSO/internal/domain/models"
+	"SSO/internal/storage"
+	"context"
+	"io"
+	"log/slog"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type subgroup struct {
+	parentID, childID int64
+}
+
+// memStorage keeps groups of apps in memory and refuses nesting cycles
+// as the storage does.
+type memStorage struct {
+	groups      map[int64]models.Group
+	subgroups   []subgroup
+	members     map[int64][]int64
+	groupClaims map[int]bool
+	lastID      int64
+}
+
+func (s *memStorage) SaveGroup(_ context.Context, appID int, name string) (int64, error) {
+	for _, g := range s.groups {
+		if g.AppID == appID && g.Name == name {
+			return 0, storage.ErrGroupExists
+		}
+	}
+	s.lastID++
+	s.groups[s.lastID] = models.Group{ID: s.lastID, AppID: appID, Name: name}
+
+	return s.lastID, nil
+}
+
+func (s *memStorage) DeleteGroup(_ context.Context, appID int, groupID int64) error {
+	if err := s.check(appID, groupID); err != nil {
+		return err
+	}
+	delete(s.groups, groupID)
+
+	return nil
+}
+
+func (s *memStorage) SaveGroupMember(_ context.Context, appID int, groupID, userID int64) error {
+	if err := s.check(appID, groupID); err != nil {
+		return err
+	}
+	s.members[groupID] = append(s.members[groupID], userID)
+
+	return nil
+}
+
+func (s *memStorage) DeleteGroupMember(_ context.Context, appID int, groupID, userID int64) error {
+	if err := s.check(appID, groupID); err != nil {
+		return err
+	}
+	i := slices.Index(s.members[groupID], userID)
+	if i == -1 {
+		return storage.ErrMemberNotFound
+	}
+	s.members[groupID] = slices.Delete(s.members[groupID], i, i+1)
+
+	return nil
+}
+
+func (s *memStorage) SaveSubgroup(_ context.Context, appID int, parentID, childID int64) error {
+	if err := s.check(appID, parentID, childID); err != nil {
+		return err
+	}
+	if slices.Contains(s.descendants(childID), parentID) {
+		return storage.ErrGroupCycle
+	}
+	s.subgroups = append(s.subgroups, subgroup{parentID, childID})
+
+	return nil
+}
+
+func (s *memStorage) DeleteSubgroup(_ context.Context, appID int, parentID, childID int64) error {
+	if err := s.check(appID, parentID); err != nil {
+		return err
+	}
+	i := slices.Index(s.subgroups, subgroup{parentID, childID})
+	if i == -1 {
+		return storage.ErrGroupNotFound
+	}
+	s.subgroups = slices.Delete(s.subgroups, i, i+1)
+
+	return nil
+}
+
+func (s *memStorage) AssignGroupRole(_ context.Context, appID int, groupID, _ int64) error {
+	return s.check(appID, groupID)
+}
+
+func (s *memStorage) UnassignGroupRole(_ context.Context, appID int, groupID, _ int64) error {
+	return s.check(appID, groupID)
+}
+
+func (s *memStorage) SetGroupClaims(_ context.Context, appID int, enabled bool) error {
+	s.groupClaims[appID] = enabled
+
+	return nil
+}
+
+func (s *memStorage) Groups(_ context.Context, appID int) ([]models.Group, error) {
+	var groups []models.Group
+	for _, g := range s.groups {
+		if g.AppID == appID {
+			groups = append(groups, g)
+		}
+	}
+
+	return groups, nil
+}
+
+func (s *memStorage) GroupMembers(_ context.Context, appID int, groupID int64) ([]models.GroupMember, error) {
+	if err := s.check(appID, groupID); err != nil {
+		return nil, err
+	}
+
+	var members []models.GroupMember
+	for _, userID := range s.members[groupID] {
+		members = append(members, models.GroupMember{UserID: userID})
+	}
+
+	return members, nil
+}
+
+func (s *memStorage) UserGroups(_ context.Context, userID int64, appID int) ([]models.Group, error) {
+	var groups []models.Group
+	for id, g := range s.groups {
+		if g.AppID != appID {
+			continue
+		}
+		for _, d := range s.descendants(id) {
+			if slices.Contains(s.members[d], userID) {
+				groups = append(groups, g)
+
+				break
+			}
+		}
+	}
+	slices.SortFunc(groups, func(a, b models.Group) int { return int(a.ID - b.ID) })
+
+	return groups, nil
+}
+
+// descendants returns the group and all groups nested into it.
+func (s *memStorage) descendants(groupID int64) []int64 {
+	ids := []int64{groupID}
+	for i := 0; i < len(ids); i++ {
+		for _, sg := range s.subgroups {
+			if sg.parentID == ids[i] && !slices.Contains(ids, sg.childID) {
+				ids = append(ids, sg.childID)
+			}
+		}
+	}
+
+	return ids
+}
+
+func (s *memStorage) check(appID int, groupIDs ...int64) error {
+	for _, id := range groupIDs {
+		if g, ok := s.groups[id]; !ok || g.AppID != appID {
+			return storage.ErrGroupNotFound
+		}
+	}
+
+	return nil
+}
+
+func newTestService() (*Groups, *memStorage) {
+	s := &memStorage{
+		groups:      make(map[int64]models.Group),
+		members:     make(map[int64][]int64),
+		groupClaims: make(map[int]bool),
+	}
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	return New(log, s, s), s
+}
+
+// createGroups creates groups with given names in the app and returns their ids.
+func createGroups(t *testing.T, g *Groups, appID int, names ...string) []int64 {
+	t.Helper()
+
+	ids := make([]int64, 0, len(names))
+	for _, name := range names {
+		id, err := g.CreateGroup(context.Background(), appID, name)
+		require.NoError(t, err)
+		ids = append(ids, id)
+	}
+
+	return ids
+}
+
+func TestCreateGroup(t *testing.T) {
+	g, _ := newTestService()
+	ctx := context.Background()
+
+	createGroups(t, g, 1, "staff")
+	_, err := g.CreateGroup(ctx, 1, "staff")
+	assert.ErrorIs(t, err, ErrGroupExists)
+	_, err = g.CreateGroup(ctx, 2, "staff")
+	assert.NoError(t, err, "names are unique within the app")
+}
+
+func TestAddSubgroup_Cycles(t *testing.T) {
+	g, _ := newTestService()
+	ctx := context.Background()
+
+	ids := createGroups(t, g, 1, "a", "b", "c")
+	a, b, c := ids[0], ids[1], ids[2]
+
+	require.NoError(t, g.AddSubgroup(ctx, 1, a, b))
+	require.NoError(t, g.AddSubgroup(ctx, 1, b, c))
+	assert.ErrorIs(t, g.AddSubgroup(ctx, 1, a, a), ErrGroupCycle)
+	assert.ErrorIs(t, g.AddSubgroup(ctx, 1, c, a), ErrGroupCycle)
+
+	other := createGroups(t, g, 2, "a")[0]
+	assert.ErrorIs(t, g.AddSubgroup(ctx, 1, a, other), ErrGroupNotFound)
+
+	require.NoError(t, g.RemoveSubgroup(ctx, 1, b, c))
+	assert.ErrorIs(t, g.RemoveSubgroup(ctx, 1, b, c), ErrGroupNotFound)
+	require.NoError(t, g.AddSubgroup(ctx, 1, c, a))
+}
+
+func TestUserGroups_Nested(t *testing.T) {
+	g, _ := newTestService()
+	ctx := context.Background()
+
+	ids := createGroups(t, g, 1, "g0", "g1", "g2", "g3")
+	for i := 1; i < len(ids); i++ {
+		require.NoError(t, g.AddSubgroup(ctx, 1, ids[i-1], ids[i]))
+	}
+	require.NoError(t, g.AddMember(ctx, 1, ids[2], 7))
+
+	groups, err := g.UserGroups(ctx, 1, 7)
+	require.NoError(t, err)
+	require.Len(t, groups, 3)
+	assert.Equal(t, []string{"g0", "g1", "g2"}, []string{groups[0].Name, groups[1].Name, groups[2].Name})
+
+	require.NoError(t, g.RemoveMember(ctx, 1, ids[2], 7))
+	assert.ErrorIs(t, g.RemoveMember(ctx, 1, ids[2], 7), ErrMemberNotFound)
+	groups, err = g.UserGroups(ctx, 1, 7)
+	require.NoError(t, err)
+	assert.Empty(t, groups)
+}
+
+func TestSetGroupClaims(t *testing.T) {
+	g, s := newTestService()
+
+	require.NoError(t, g.SetGroupClaims(context.Background(), 1, true))
+	assert.True(t, s.groupClaims[1])
+}
@@ -0,0 +1,199 @@
+package identities
+
+import (
+	"SSO/internal/domain/models"
+	"SSO/internal/lib/jwt"
+	"SSO/internal/storage"
+	"context"
+	"errors"
+	"fmt"
+	"golang.org/x/crypto/bcrypt"
+	"log/slog"
+	"time"
+)
+
+// Identities manages login methods of users: password and identities at
+// upstream providers linked to the account.
+type Identities struct {
+	log              *slog.Logger
+	identitySaver    IdentitySaver
+	identityProvider IdentityProvider
+	userProvider     UserProvider
+	linker           Linker
+	reauthMaxAge     time.Duration
+}
+
+type IdentitySaver interface {
+	DeleteIdentity(ctx context.Context, userID, id int64) error
+}
+
+type IdentityProvider interface {
+	Identities(ctx context.Context, userID int64) ([]models.Identity, error)
+}
+
+type UserProvider interface {
+	UserByID(ctx context.Context, userID int64) (models.User, error)
+}
+
+// Linker starts linking identity at the provider, see federation.Federation.
+type Linker interface {
+	StartLink(ctx context.Context, userID int64, provider string, appID int, returnTo string) (string, error)
+}
+
+// LoginMethods are the ways the user can log in.
+type LoginMethods struct {
+	HasPassword bool
+	Identities  []models.Identity
+}
+
+var (
+	ErrUserNotFound     = errors.New("user not found")
+	ErrIdentityNotFound = errors.New("identity not found")
+	ErrCallerNotAllowed = errors.New("login methods must be managed by the user themselves")
+	ErrReauthRequired   = errors.New("re-authentication required")
+	ErrInvalidPassword  = errors.New("invalid password")
+	ErrLastLoginMethod  = errors.New("can't unlink the last login method")
+)
+
+// New returns a new instance of Identities service. Users re-authenticate
+// with password or with token issued within reauthMaxAge.
+func New(
+	log *slog.Logger,
+	identitySaver IdentitySaver,
+	identityProvider IdentityProvider,
+	userProvider UserProvider,
+	linker Linker,
+	reauthMaxAge time.Duration,
+) *Identities {
+	return &Identities{
+		log:              log,
+		identitySaver:    identitySaver,
+		identityProvider: identityProvider,
+		userProvider:     userProvider,
+		linker:           linker,
+		reauthMaxAge:     reauthMaxAge,
+	}
+}
+
+// List returns login methods of the caller.
+func (i *Identities) List(ctx context.Context, caller jwt.Claims) (LoginMethods, error) {
+	const op = "Identities.List"
+
+	user, err := i.userProvider.UserByID(ctx, caller.UserID)
+	if err != nil {
+		return LoginMethods{}, fmt.Errorf("%s: %w", op, mapStorageErr(err))
+	}
+
+	identities, err := i.identityProvider.Identities(ctx, user.ID)
+	if err != nil {
+		return LoginMethods{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return LoginMethods{
+		HasPassword: user.HasPassword(),
+		Identities:  identities,
+	}, nil
+}
+
+// Link starts linking identity at the provider to the caller and returns
+// URL the caller is redirected to. It's completed with
+// federation.Federation.Complete. returnTo must be a redirect URI of the
+// app the caller's token is issued for.
+func (i *Identities) Link(
+	ctx context.Context,
+	caller jwt.Claims,
+	password string,
+	provider string,
+	returnTo string,
+) (string, error) {
+	const op = "Identities.Link"
+
+	log := i.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", caller.UserID),
+		slog.String("provider", provider),
+	)
+
+	if err := i.reauthenticate(ctx, caller, password); err != nil {
+		log.Warn("re-authentication failed", slog.String("error", err.Error()))
+
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	authURL, err := i.linker.StartLink(ctx, caller.UserID, provider, caller.AppID, returnTo)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	return authURL, nil
+}
+
+// Unlink unlinks identity from the caller. The last login method of the
+// caller can't be unlinked.
+func (i *Identities) Unlink(ctx context.Context, caller jwt.Claims, password string, identityID int64) error {
+	const op = "Identities.Unlink"
+
+	log := i.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", caller.UserID),
+		slog.Int64("identity_id", identityID),
+	)
+
+	if err := i.reauthenticate(ctx, caller, password); err != nil {
+		log.Warn("re-authentication failed", slog.String("error", err.Error()))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := i.identitySaver.DeleteIdentity(ctx, caller.UserID, identityID); err != nil {
+		if !errors.Is(err, storage.ErrLastLoginMethod) && !errors.Is(err, storage.ErrIdentityNotFound) {
+			log.Error("failed to unlink identity", slog.String("error", err.Error()))
+		}
+
+		return fmt.Errorf("%s: %w", op, mapStorageErr(err))
+	}
+
+	log.Info("identity unlinked")
+
+	return nil
+}
+
+// reauthenticate checks that the caller is the user themselves and has
+// just proven it: with password or, without one, with a fresh token.
+func (i *Identities) reauthenticate(ctx context.Context, caller jwt.Claims, password string) error {
+	if caller.Impersonated() || caller.KeyID != 0 || caller.ServiceAccount {
+		return ErrCallerNotAllowed
+	}
+
+	if password == "" {
+		if caller.IssuedAt.IsZero() || time.Since(caller.IssuedAt) > i.reauthMaxAge {
+			return ErrReauthRequired
+		}
+
+		return nil
+	}
+
+	user, err := i.userProvider.UserByID(ctx, caller.UserID)
+	if err != nil {
+		return mapStorageErr(err)
+	}
+
+	if !user.HasPassword() || bcrypt.CompareHashAndPassword(user.PassHash, []byte(password)) != nil {
+		return ErrInvalidPassword
+	}
+
+	return nil
+}
+
+func mapStorageErr(err error) error {
+	switch {
+	case errors.Is(err, storage.ErrUserNotFound):
+		return ErrUserNotFound
+	case errors.Is(err, storage.ErrIdentityNotFound):
+		return ErrIdentityNotFound
+	case errors.Is(err, storage.ErrLastLoginMethod):
+		return ErrLastLoginMethod
+	}
+
+	return err
+}
@@ -0,0 +1,63 @@
+package identities
+
+import (
+	"SSO/internal/domain/models"
+	"SSO/internal/lib/jwt"
+	"context"
+	"golang.org/x/crypto/bcrypt"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type users map[int64]models.User
+
+func (u users) UserByID(_ context.Context, userID int64) (models.User, error) {
+	return u[userID], nil
+}
+
+func TestReauthenticate(t *testing.T) {
+	passHash, err := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	require.NoError(t, err)
+
+	i := New(
+		slog.New(slog.NewTextHandler(io.Discard, nil)),
+		nil,
+		nil,
+		users{1: {ID: 1, PassHash: passHash}, 2: {ID: 2}},
+		nil,
+		5*time.Minute,
+	)
+
+	fresh, stale := time.Now().Add(-time.Minute), time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name     string
+		caller   jwt.Claims
+		password string
+		want     error
+	}{
+		{"password", jwt.Claims{UserID: 1, IssuedAt: stale}, "secret", nil},
+		{"wrong password", jwt.Claims{UserID: 1, IssuedAt: fresh}, "wrong", ErrInvalidPassword},
+		{"password of user without one", jwt.Claims{UserID: 2, IssuedAt: fresh}, "secret", ErrInvalidPassword},
+		{"fresh token", jwt.Claims{UserID: 2, IssuedAt: fresh}, "", nil},
+		{"stale token", jwt.Claims{UserID: 2, IssuedAt: stale}, "", ErrReauthRequired},
+		{"token without iat", jwt.Claims{UserID: 2}, "", ErrReauthRequired},
+		{"impersonation", jwt.Claims{UserID: 1, Actor: jwt.Actor{UserID: 3}}, "secret", ErrCallerNotAllowed},
+		{"api key", jwt.Claims{UserID: 1, KeyID: 4, IssuedAt: fresh}, "", ErrCallerNotAllowed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := i.reauthenticate(context.Background(), tt.caller, tt.password)
+			if tt.want == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.want)
+			}
+		})
+	}
+}
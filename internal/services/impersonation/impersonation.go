@@ -0,0 +1,185 @@
+package impersonation
+
+import (
+	"SSO/internal/domain/models"
+	"SSO/internal/lib/jwt"
+	"SSO/internal/lib/secrets"
+	"SSO/internal/services/permissions"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+)
+
+// sessionIDSize is the number of random bytes in impersonation session IDs.
+const sessionIDSize = 16
+
+type Impersonation struct {
+	log      *slog.Logger
+	issuer   TokenIssuer
+	recorder Recorder
+	tokenTTL time.Duration
+}
+
+// TokenIssuer issues tokens of impersonated users.
+type TokenIssuer interface {
+	ImpersonationToken(
+		ctx context.Context,
+		userID int64,
+		appID int,
+		actor jwt.Actor,
+		scopes []string,
+		ttl time.Duration,
+	) (string, error)
+}
+
+// Recorder appends events to the audit trail.
+type Recorder interface {
+	Record(ctx context.Context, event models.AuditEvent) error
+}
+
+// Session is a started impersonation.
+type Session struct {
+	ID        string
+	Token     string
+	ExpiresAt time.Time
+}
+
+var (
+	ErrActorNotAllowed = errors.New("impersonation must be started with a login token")
+	ErrSelf            = errors.New("admin can't impersonate themselves")
+	ErrInvalidScope    = errors.New("invalid scope")
+)
+
+// New returns a new instance of Impersonation service. Tokens it issues
+// expire after tokenTTL.
+func New(
+	log *slog.Logger,
+	issuer TokenIssuer,
+	recorder Recorder,
+	tokenTTL time.Duration,
+) *Impersonation {
+	return &Impersonation{
+		log:      log,
+		issuer:   issuer,
+		recorder: recorder,
+		tokenTTL: tokenTTL,
+	}
+}
+
+// Start lets the admin the claims belong to act as the user in the app of
+// the claims. Caller is responsible for checking the admin rights.
+//
+// Scopes name roles and permissions of the user the token carries, they
+// are required and can't include the admin role. The start is recorded to
+// the audit trail with the reason before the token is issued.
+func (i *Impersonation) Start(
+	ctx context.Context,
+	admin jwt.Claims,
+	userID int64,
+	scopes []string,
+	reason string,
+) (Session, error) {
+	const op = "Impersonation.Start"
+
+	log := i.log.With(
+		slog.String("op", op),
+		slog.Int64("actor_id", admin.UserID),
+		slog.Int64("user_id", userID),
+		slog.Int("app_id", admin.AppID),
+	)
+
+	if admin.Impersonated() || admin.KeyID != 0 {
+		return Session{}, fmt.Errorf("%s: %w", op, ErrActorNotAllowed)
+	}
+
+	if admin.UserID == userID {
+		return Session{}, fmt.Errorf("%s: %w", op, ErrSelf)
+	}
+
+	scopes, err := normalizeScopes(scopes)
+	if err != nil {
+		return Session{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	sessionID, err := secrets.Generate(sessionIDSize)
+	if err != nil {
+		return Session{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = i.recorder.Record(ctx, models.AuditEvent{
+		Action:    models.AuditImpersonationStart,
+		AppID:     admin.AppID,
+		ActorID:   admin.UserID,
+		UserID:    userID,
+		SessionID: sessionID,
+		Details:   fmt.Sprintf("scopes=%s reason=%s", strings.Join(scopes, ","), reason),
+	})
+	if err != nil {
+		return Session{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	expiresAt := time.Now().Add(i.tokenTTL)
+
+	token, err := i.issuer.ImpersonationToken(
+		ctx,
+		userID,
+		admin.AppID,
+		jwt.Actor{UserID: admin.UserID, SessionID: sessionID},
+		scopes,
+		i.tokenTTL,
+	)
+	if err != nil {
+		log.Warn("impersonation refused", slog.String("error", err.Error()))
+
+		return Session{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("impersonation started", slog.String("session_id", sessionID))
+
+	return Session{ID: sessionID, Token: token, ExpiresAt: expiresAt}, nil
+}
+
+// RecordUse records request to method made with impersonation token to the
+// audit trail. The request must be refused if it fails.
+func (i *Impersonation) RecordUse(ctx context.Context, claims jwt.Claims, method string) error {
+	const op = "Impersonation.RecordUse"
+
+	err := i.recorder.Record(ctx, models.AuditEvent{
+		Action:    models.AuditImpersonationUse,
+		AppID:     claims.AppID,
+		ActorID:   claims.Actor.UserID,
+		UserID:    claims.UserID,
+		SessionID: claims.Actor.SessionID,
+		Details:   method,
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// normalizeScopes trims scopes and removes duplicates. Impersonation
+// tokens must be scoped and never carry the admin role.
+func normalizeScopes(scopes []string) ([]string, error) {
+	res := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		scope = strings.TrimSpace(scope)
+		if scope == "" || scope == permissions.AdminRole {
+			return nil, ErrInvalidScope
+		}
+		if !slices.Contains(res, scope) {
+			res = append(res, scope)
+		}
+	}
+
+	if len(res) == 0 {
+		return nil, ErrInvalidScope
+	}
+
+	return res, nil
+}
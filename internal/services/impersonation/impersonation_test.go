@@ -0,0 +1,88 @@
+package impersonation
+
+import (
+	"SSO/internal/domain/models"
+	"SSO/internal/lib/jwt"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeIssuer struct {
+	actor  jwt.Actor
+	scopes []string
+}
+
+func (f *fakeIssuer) ImpersonationToken(
+	_ context.Context,
+	_ int64,
+	_ int,
+	actor jwt.Actor,
+	scopes []string,
+	_ time.Duration,
+) (string, error) {
+	f.actor, f.scopes = actor, scopes
+
+	return "token", nil
+}
+
+type fakeRecorder struct {
+	events []models.AuditEvent
+	err    error
+}
+
+func (f *fakeRecorder) Record(_ context.Context, event models.AuditEvent) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.events = append(f.events, event)
+
+	return nil
+}
+
+func TestStart(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	admin := jwt.Claims{UserID: 1, AppID: 3}
+
+	issuer, recorder := &fakeIssuer{}, &fakeRecorder{}
+	i := New(log, issuer, recorder, time.Minute)
+
+	session, err := i.Start(context.Background(), admin, 2, []string{" posts.read ", "posts.read"}, "ticket 1")
+	require.NoError(t, err)
+	assert.Equal(t, "token", session.Token)
+	assert.Equal(t, jwt.Actor{UserID: 1, SessionID: session.ID}, issuer.actor)
+	assert.Equal(t, []string{"posts.read"}, issuer.scopes)
+
+	require.Len(t, recorder.events, 1)
+	assert.Equal(t, models.AuditImpersonationStart, recorder.events[0].Action)
+	assert.Equal(t, session.ID, recorder.events[0].SessionID)
+	assert.Contains(t, recorder.events[0].Details, "ticket 1")
+
+	_, err = i.Start(context.Background(), admin, 1, []string{"posts.read"}, "ticket 1")
+	assert.ErrorIs(t, err, ErrSelf)
+
+	_, err = i.Start(context.Background(), admin, 2, nil, "ticket 1")
+	assert.ErrorIs(t, err, ErrInvalidScope)
+
+	_, err = i.Start(context.Background(), admin, 2, []string{"admin"}, "ticket 1")
+	assert.ErrorIs(t, err, ErrInvalidScope)
+
+	impersonated := admin
+	impersonated.Actor = jwt.Actor{UserID: 5, SessionID: "s"}
+	_, err = i.Start(context.Background(), impersonated, 2, []string{"posts.read"}, "ticket 1")
+	assert.ErrorIs(t, err, ErrActorNotAllowed)
+
+	// No token is issued if the start can't be audited.
+	issuer, recorder = &fakeIssuer{}, &fakeRecorder{err: errors.New("db is down")}
+	i = New(log, issuer, recorder, time.Minute)
+
+	_, err = i.Start(context.Background(), admin, 2, []string{"posts.read"}, "ticket 1")
+	assert.Error(t, err)
+	assert.Zero(t, issuer.actor)
+}
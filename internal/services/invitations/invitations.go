@@ -0,0 +1,285 @@
+package invitations
+
+import (
+	"SSO/internal/domain/models"
+	"SSO/internal/lib/mailer"
+	"SSO/internal/lib/secrets"
+	"SSO/internal/storage"
+	"context"
+	"errors"
+	"fmt"
+	"golang.org/x/crypto/bcrypt"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+type Invitations struct {
+	log            *slog.Logger
+	inviteSaver    InvitationSaver
+	inviteProvider InvitationProvider
+	usrProvider    UserProvider
+	orgs           Organizations
+	mailer         mailer.Mailer
+	ttl            time.Duration
+	acceptURL      string
+}
+
+type InvitationSaver interface {
+	SaveInvitation(ctx context.Context, inv models.Invitation, tokenHash string) (int64, error)
+	RevokeInvitation(ctx context.Context, orgID, invitationID int64) error
+	AcceptInvitation(ctx context.Context, invitationID, userID int64) error
+	AcceptInvitationAsNewUser(ctx context.Context, invitationID int64, user models.User) (int64, error)
+}
+
+type InvitationProvider interface {
+	PendingInvitations(ctx context.Context, orgID int64) ([]models.Invitation, error)
+	PendingInvitationByToken(ctx context.Context, tokenHash string) (models.Invitation, error)
+}
+
+type UserProvider interface {
+	User(ctx context.Context, email string) (models.User, error)
+	UserByID(ctx context.Context, userID int64) (models.User, error)
+}
+
+// Organizations returns organizations and checks rights of their members.
+type Organizations interface {
+	Organization(ctx context.Context, actorID, orgID int64) (models.Organization, error)
+	CheckGrant(ctx context.Context, actorID, orgID int64, role string) error
+	CheckManager(ctx context.Context, actorID, orgID int64) error
+}
+
+// Registration holds profile of the user registered by accepting invitation.
+type Registration struct {
+	Password    string
+	Username    string
+	Sex         string
+	Location    string
+	DateOfBirth string
+}
+
+var (
+	ErrInviteNotFound = errors.New("invitation not found or expired")
+	ErrEmailMismatch  = errors.New("invitation was sent to another email")
+	ErrLoginRequired  = errors.New("account with invited email exists, log in to accept invitation")
+	ErrAlreadyMember  = errors.New("user is already a member")
+)
+
+// New returns a new instance of Invitations service.
+func New(
+	log *slog.Logger,
+	inviteSaver InvitationSaver,
+	inviteProvider InvitationProvider,
+	userProvider UserProvider,
+	orgs Organizations,
+	mailer mailer.Mailer,
+	ttl time.Duration,
+	acceptURL string,
+) *Invitations {
+	return &Invitations{
+		log:            log,
+		inviteSaver:    inviteSaver,
+		inviteProvider: inviteProvider,
+		usrProvider:    userProvider,
+		orgs:           orgs,
+		mailer:         mailer,
+		ttl:            ttl,
+		acceptURL:      acceptURL,
+	}
+}
+
+// Invite creates invitation to organization and emails its token.
+// Only the hash of the token is stored.
+func (i *Invitations) Invite(ctx context.Context, actorID, orgID int64, email, role string) (int64, error) {
+	const op = "Invitations.Invite"
+
+	log := i.log.With(
+		slog.String("op", op),
+		slog.Int64("actor_id", actorID),
+		slog.Int64("organization_id", orgID),
+		slog.String("role", role),
+	)
+
+	if err := i.orgs.CheckGrant(ctx, actorID, orgID, role); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	org, err := i.orgs.Organization(ctx, actorID, orgID)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	token, err := secrets.Generate(secrets.DefaultSize)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	id, err := i.inviteSaver.SaveInvitation(ctx, models.Invitation{
+		OrganizationID: orgID,
+		Email:          email,
+		Role:           role,
+		InvitedBy:      actorID,
+		ExpiresAt:      time.Now().Add(i.ttl),
+	}, secrets.Hash(token))
+	if err != nil {
+		log.Error("failed to save invitation", slog.String("error", err.Error()))
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	body := fmt.Sprintf(
+		"You have been invited to join %s as %s.\n\nAccept the invitation: %s%s\n\nThe link expires in %s.",
+		org.Name, role, i.acceptURL, token, i.ttl,
+	)
+	if err := i.mailer.Send(ctx, email, "Invitation to "+org.Name, body); err != nil {
+		log.Error("failed to send invitation", slog.String("error", err.Error()))
+
+		// The token is lost, don't leave a pending invitation nobody can accept.
+		_ = i.inviteSaver.RevokeInvitation(ctx, orgID, id)
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("invitation sent", slog.Int64("invitation_id", id))
+
+	return id, nil
+}
+
+// PendingInvitations returns invitations to organization which can still be accepted.
+func (i *Invitations) PendingInvitations(ctx context.Context, actorID, orgID int64) ([]models.Invitation, error) {
+	const op = "Invitations.PendingInvitations"
+
+	if err := i.orgs.CheckManager(ctx, actorID, orgID); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	invitations, err := i.inviteProvider.PendingInvitations(ctx, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return invitations, nil
+}
+
+// Revoke revokes pending invitation to organization.
+func (i *Invitations) Revoke(ctx context.Context, actorID, orgID, invitationID int64) error {
+	const op = "Invitations.Revoke"
+
+	log := i.log.With(
+		slog.String("op", op),
+		slog.Int64("actor_id", actorID),
+		slog.Int64("organization_id", orgID),
+		slog.Int64("invitation_id", invitationID),
+	)
+
+	if err := i.orgs.CheckManager(ctx, actorID, orgID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := i.inviteSaver.RevokeInvitation(ctx, orgID, invitationID); err != nil {
+		if errors.Is(err, storage.ErrInviteNotFound) {
+			return fmt.Errorf("%s: %w", op, ErrInviteNotFound)
+		}
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("invitation revoked")
+
+	return nil
+}
+
+// Accept accepts invitation by its token.
+//
+// If userID is not 0, the authenticated user joins the organization, its
+// email must match the invited one. Otherwise a new account is registered
+// for the invited email, unless it already exists.
+func (i *Invitations) Accept(
+	ctx context.Context,
+	token string,
+	userID int64,
+	reg Registration,
+) (orgID int64, uid int64, err error) {
+	const op = "Invitations.Accept"
+
+	log := i.log.With(
+		slog.String("op", op),
+	)
+
+	inv, err := i.inviteProvider.PendingInvitationByToken(ctx, secrets.Hash(token))
+	if err != nil {
+		if errors.Is(err, storage.ErrInviteNotFound) {
+			return 0, 0, fmt.Errorf("%s: %w", op, ErrInviteNotFound)
+		}
+
+		return 0, 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log = log.With(
+		slog.Int64("invitation_id", inv.ID),
+		slog.Int64("organization_id", inv.OrganizationID),
+	)
+
+	if userID != 0 {
+		user, err := i.usrProvider.UserByID(ctx, userID)
+		if err != nil {
+			return 0, 0, fmt.Errorf("%s: %w", op, err)
+		}
+
+		if !strings.EqualFold(user.Email, inv.Email) {
+			return 0, 0, fmt.Errorf("%s: %w", op, ErrEmailMismatch)
+		}
+
+		if err := i.inviteSaver.AcceptInvitation(ctx, inv.ID, userID); err != nil {
+			return 0, 0, fmt.Errorf("%s: %w", op, mapAcceptErr(err))
+		}
+	} else {
+		_, err := i.usrProvider.User(ctx, inv.Email)
+		switch {
+		case err == nil:
+			return 0, 0, fmt.Errorf("%s: %w", op, ErrLoginRequired)
+		case !errors.Is(err, storage.ErrUserNotFound):
+			return 0, 0, fmt.Errorf("%s: %w", op, err)
+		}
+
+		passHash, err := bcrypt.GenerateFromPassword([]byte(reg.Password), bcrypt.DefaultCost)
+		if err != nil {
+			log.Error("failed to generate password hash", slog.String("error", err.Error()))
+
+			return 0, 0, fmt.Errorf("%s: %w", op, err)
+		}
+
+		// The account is saved in the transaction accepting the invitation,
+		// an invitation used meanwhile registers nobody.
+		userID, err = i.inviteSaver.AcceptInvitationAsNewUser(ctx, inv.ID, models.User{
+			Email:       inv.Email,
+			PassHash:    passHash,
+			Username:    reg.Username,
+			Sex:         reg.Sex,
+			Location:    reg.Location,
+			DateOfBirth: reg.DateOfBirth,
+		})
+		if err != nil {
+			return 0, 0, fmt.Errorf("%s: %w", op, mapAcceptErr(err))
+		}
+
+		log.Info("user registered by invitation", slog.Int64("user_id", userID))
+	}
+
+	log.Info("invitation accepted", slog.Int64("user_id", userID))
+
+	return inv.OrganizationID, userID, nil
+}
+
+func mapAcceptErr(err error) error {
+	switch {
+	case errors.Is(err, storage.ErrInviteNotFound):
+		return ErrInviteNotFound
+	case errors.Is(err, storage.ErrMemberExists):
+		return ErrAlreadyMember
+	case errors.Is(err, storage.ErrUserExists):
+		return ErrLoginRequired
+	}
+
+	return err
+}
@@ -0,0 +1,254 @@
+package invitations
+
+import (
+	"SSO/internal/domain/models"
+	"SSO/internal/lib/secrets"
+	"SSO/internal/storage"
+	"context"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const acceptURL = "https://example.com/invitations/accept?token="
+
+type invitation struct {
+	models.Invitation
+	tokenHash string
+	accepted  bool
+	revoked   bool
+}
+
+// memStorage keeps invitations, users and members of organizations in memory.
+type memStorage struct {
+	invitations []*invitation
+	users       map[int64]models.User
+	members     map[int64]string
+	// stale makes lookups return used invitations as if they were made
+	// before the invitation was accepted by a concurrent request.
+	stale bool
+}
+
+func (s *memStorage) SaveInvitation(_ context.Context, inv models.Invitation, tokenHash string) (int64, error) {
+	inv.ID = int64(len(s.invitations) + 1)
+	s.invitations = append(s.invitations, &invitation{Invitation: inv, tokenHash: tokenHash})
+
+	return inv.ID, nil
+}
+
+func (s *memStorage) RevokeInvitation(_ context.Context, _, invitationID int64) error {
+	inv, err := s.pending(invitationID)
+	if err != nil {
+		return err
+	}
+	inv.revoked = true
+
+	return nil
+}
+
+func (s *memStorage) AcceptInvitation(_ context.Context, invitationID, userID int64) error {
+	inv, err := s.pending(invitationID)
+	if err != nil {
+		return err
+	}
+	if _, ok := s.members[userID]; ok {
+		return storage.ErrMemberExists
+	}
+	inv.accepted = true
+	s.members[userID] = inv.Role
+
+	return nil
+}
+
+func (s *memStorage) AcceptInvitationAsNewUser(ctx context.Context, invitationID int64, user models.User) (int64, error) {
+	if _, err := s.pending(invitationID); err != nil {
+		return 0, err
+	}
+	for _, u := range s.users {
+		if u.Email == user.Email {
+			return 0, storage.ErrUserExists
+		}
+	}
+	user.ID = int64(len(s.users) + 1)
+	s.users[user.ID] = user
+
+	return user.ID, s.AcceptInvitation(ctx, invitationID, user.ID)
+}
+
+func (s *memStorage) pending(invitationID int64) (*invitation, error) {
+	for _, inv := range s.invitations {
+		if inv.ID == invitationID && !inv.accepted && !inv.revoked {
+			return inv, nil
+		}
+	}
+
+	return nil, storage.ErrInviteNotFound
+}
+
+func (s *memStorage) PendingInvitations(context.Context, int64) ([]models.Invitation, error) {
+	var invitations []models.Invitation
+	for _, inv := range s.invitations {
+		if !inv.accepted && !inv.revoked {
+			invitations = append(invitations, inv.Invitation)
+		}
+	}
+
+	return invitations, nil
+}
+
+func (s *memStorage) PendingInvitationByToken(_ context.Context, tokenHash string) (models.Invitation, error) {
+	for _, inv := range s.invitations {
+		if inv.tokenHash == tokenHash && (s.stale || !inv.accepted && !inv.revoked) {
+			return inv.Invitation, nil
+		}
+	}
+
+	return models.Invitation{}, storage.ErrInviteNotFound
+}
+
+func (s *memStorage) User(_ context.Context, email string) (models.User, error) {
+	for _, u := range s.users {
+		if u.Email == email {
+			return u, nil
+		}
+	}
+
+	return models.User{}, storage.ErrUserNotFound
+}
+
+func (s *memStorage) UserByID(_ context.Context, userID int64) (models.User, error) {
+	u, ok := s.users[userID]
+	if !ok {
+		return models.User{}, storage.ErrUserNotFound
+	}
+
+	return u, nil
+}
+
+// orgs lets everyone manage the organization.
+type orgs struct{}
+
+func (orgs) Organization(_ context.Context, _, orgID int64) (models.Organization, error) {
+	return models.Organization{ID: orgID, Name: "Org"}, nil
+}
+
+func (orgs) CheckGrant(context.Context, int64, int64, string) error {
+	return nil
+}
+
+func (orgs) CheckManager(context.Context, int64, int64) error {
+	return nil
+}
+
+// inbox keeps sent emails by recipient.
+type inbox map[string][]string
+
+func (i inbox) Send(_ context.Context, to, _, body string) error {
+	i[to] = append(i[to], body)
+
+	return nil
+}
+
+// token returns token of the latest invitation sent to the recipient.
+func (i inbox) token(t *testing.T, to string) string {
+	t.Helper()
+
+	require.NotEmpty(t, i[to])
+	body := i[to][len(i[to])-1]
+
+	start := strings.Index(body, acceptURL)
+	require.NotEqual(t, -1, start)
+
+	return strings.Fields(body[start+len(acceptURL):])[0]
+}
+
+func newTestService() (*Invitations, *memStorage, inbox) {
+	s := &memStorage{
+		users: map[int64]models.User{
+			1: {ID: 1, Email: "owner@example.com"},
+			2: {ID: 2, Email: "member@example.com"},
+		},
+		members: map[int64]string{1: models.OrgRoleOwner},
+	}
+	mail := make(inbox)
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	return New(log, s, s, s, orgs{}, mail, time.Hour, acceptURL), s, mail
+}
+
+func TestAccept_NewUser(t *testing.T) {
+	i, s, mail := newTestService()
+	ctx := context.Background()
+
+	_, err := i.Invite(ctx, 1, 1, "new@example.com", models.OrgRoleAdmin)
+	require.NoError(t, err)
+	token := mail.token(t, "new@example.com")
+	assert.Equal(t, secrets.Hash(token), s.invitations[0].tokenHash, "only hash of the token is stored")
+
+	orgID, userID, err := i.Accept(ctx, token, 0, Registration{Password: "password", Username: "new"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), orgID)
+	assert.Equal(t, "new@example.com", s.users[userID].Email)
+	assert.NotEqual(t, "password", string(s.users[userID].PassHash))
+	assert.Equal(t, models.OrgRoleAdmin, s.members[userID])
+
+	_, _, err = i.Accept(ctx, token, 0, Registration{Password: "password"})
+	assert.ErrorIs(t, err, ErrInviteNotFound, "invitation is accepted once")
+}
+
+func TestAccept_UsedMeanwhile(t *testing.T) {
+	i, s, mail := newTestService()
+	ctx := context.Background()
+
+	_, err := i.Invite(ctx, 1, 1, "new@example.com", models.OrgRoleMember)
+	require.NoError(t, err)
+	token := mail.token(t, "new@example.com")
+
+	s.invitations[0].accepted = true
+	s.stale = true
+
+	_, _, err = i.Accept(ctx, token, 0, Registration{Password: "password"})
+	assert.ErrorIs(t, err, ErrInviteNotFound)
+	assert.Len(t, s.users, 2, "no account is registered by a used invitation")
+}
+
+func TestAccept_ExistingUser(t *testing.T) {
+	i, s, mail := newTestService()
+	ctx := context.Background()
+
+	_, err := i.Invite(ctx, 1, 1, "member@example.com", models.OrgRoleMember)
+	require.NoError(t, err)
+	token := mail.token(t, "member@example.com")
+
+	_, _, err = i.Accept(ctx, token, 0, Registration{Password: "password"})
+	assert.ErrorIs(t, err, ErrLoginRequired)
+	_, _, err = i.Accept(ctx, token, 1, Registration{})
+	assert.ErrorIs(t, err, ErrEmailMismatch)
+
+	_, userID, err := i.Accept(ctx, token, 2, Registration{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), userID)
+	assert.Equal(t, models.OrgRoleMember, s.members[2])
+}
+
+func TestRevoke(t *testing.T) {
+	i, _, mail := newTestService()
+	ctx := context.Background()
+
+	id, err := i.Invite(ctx, 1, 1, "new@example.com", models.OrgRoleMember)
+	require.NoError(t, err)
+	require.NoError(t, i.Revoke(ctx, 1, 1, id))
+	assert.ErrorIs(t, i.Revoke(ctx, 1, 1, id), ErrInviteNotFound)
+
+	_, _, err = i.Accept(ctx, mail.token(t, "new@example.com"), 0, Registration{Password: "password"})
+	assert.ErrorIs(t, err, ErrInviteNotFound)
+
+	pending, err := i.PendingInvitations(ctx, 1, 1)
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+}
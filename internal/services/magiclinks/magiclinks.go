@@ -0,0 +1,200 @@
+package magiclinks
+
+import (
+	"SSO/internal/domain/models"
+	"SSO/internal/lib/mailer"
+	"SSO/internal/lib/secrets"
+	"SSO/internal/storage"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// MagicLinks logs users in to apps with links emailed to them instead of
+// password.
+type MagicLinks struct {
+	log         *slog.Logger
+	linkSaver   LinkSaver
+	usrProvider UserProvider
+	appProvider AppProvider
+	issuer      TokenIssuer
+	mailer      mailer.Mailer
+	ttl         time.Duration
+	linkURL     string
+}
+
+type LinkSaver interface {
+	SaveMagicLink(ctx context.Context, link models.MagicLink, tokenHash string) error
+	ConsumeMagicLink(ctx context.Context, tokenHash string) (models.MagicLink, error)
+}
+
+type UserProvider interface {
+	User(ctx context.Context, email string) (models.User, error)
+}
+
+type AppProvider interface {
+	App(ctx context.Context, appID int) (models.App, error)
+}
+
+// TokenIssuer issues tokens to users who followed links, see auth.Auth.
+type TokenIssuer interface {
+	MagicLinkToken(ctx context.Context, userID int64, appID int, scopes []string) (string, error)
+}
+
+var (
+	ErrAppNotFound     = errors.New("app not found")
+	ErrAppDisabled     = errors.New("app is disabled")
+	ErrGrantNotAllowed = errors.New("magic link login is not allowed for the app")
+	ErrInvalidLink     = errors.New("invalid or expired magic link")
+)
+
+// New returns a new instance of MagicLinks service. Links expire after ttl,
+// their tokens are appended to linkURL.
+func New(
+	log *slog.Logger,
+	linkSaver LinkSaver,
+	userProvider UserProvider,
+	appProvider AppProvider,
+	issuer TokenIssuer,
+	mailer mailer.Mailer,
+	ttl time.Duration,
+	linkURL string,
+) *MagicLinks {
+	return &MagicLinks{
+		log:         log,
+		linkSaver:   linkSaver,
+		usrProvider: userProvider,
+		appProvider: appProvider,
+		issuer:      issuer,
+		mailer:      mailer,
+		ttl:         ttl,
+		linkURL:     linkURL,
+	}
+}
+
+// Request emails link to log in to the app to the user with the email.
+// Only the hash of its token is stored and requesting a new link voids the
+// former one. Scopes are checked as on Login when the link is consumed.
+//
+// Nothing is sent if there is no such user, but no error is returned
+// either, so the request doesn't tell whether the email is registered.
+func (m *MagicLinks) Request(ctx context.Context, email string, appID int, scopes []string) error {
+	const op = "MagicLinks.Request"
+
+	log := m.log.With(
+		slog.String("op", op),
+		slog.Int("app_id", appID),
+	)
+
+	app, err := m.appProvider.App(ctx, appID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, mapStorageErr(err))
+	}
+
+	if app.Disabled {
+		return fmt.Errorf("%s: %w", op, ErrAppDisabled)
+	}
+
+	if !app.AllowsGrant(models.GrantMagicLink) {
+		return fmt.Errorf("%s: %w", op, ErrGrantNotAllowed)
+	}
+
+	user, err := m.usrProvider.User(ctx, email)
+	if err != nil {
+		if errors.Is(err, storage.ErrUserNotFound) {
+			log.Info("magic link requested for unknown email")
+
+			return nil
+		}
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log = log.With(slog.Int64("user_id", user.ID))
+
+	if user.IsServiceAccount() {
+		log.Warn("magic link requested for service account")
+
+		return nil
+	}
+
+	token, err := secrets.Generate(secrets.DefaultSize)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = m.linkSaver.SaveMagicLink(ctx, models.MagicLink{
+		UserID:    user.ID,
+		AppID:     app.ID,
+		Scopes:    scopes,
+		ExpiresAt: time.Now().Add(m.ttl),
+	}, secrets.Hash(token))
+	if err != nil {
+		log.Error("failed to save magic link", slog.String("error", err.Error()))
+
+		return fmt.Errorf("%s: %w", op, mapStorageErr(err))
+	}
+
+	body := fmt.Sprintf(
+		"Log in to %s: %s%s\n\nThe link works once and expires in %s. If you didn't request it, ignore this email.",
+		app.Name, m.linkURL, token, m.ttl,
+	)
+	if err := m.mailer.Send(ctx, user.Email, "Log in to "+app.Name, body); err != nil {
+		log.Error("failed to send magic link", slog.String("error", err.Error()))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("magic link sent")
+
+	return nil
+}
+
+// Consume logs the user in to the app with the link token and returns
+// token for the app, as Login does. The link is void afterwards, even if
+// it was issued for another app.
+func (m *MagicLinks) Consume(ctx context.Context, token string, appID int) (string, error) {
+	const op = "MagicLinks.Consume"
+
+	log := m.log.With(
+		slog.String("op", op),
+		slog.Int("app_id", appID),
+	)
+
+	link, err := m.linkSaver.ConsumeMagicLink(ctx, secrets.Hash(token))
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, mapStorageErr(err))
+	}
+
+	log = log.With(slog.Int64("user_id", link.UserID))
+
+	if link.Expired(time.Now()) {
+		return "", fmt.Errorf("%s: %w", op, ErrInvalidLink)
+	}
+
+	if link.AppID != appID {
+		log.Warn("magic link used for another app", slog.Int("link_app_id", link.AppID))
+
+		return "", fmt.Errorf("%s: %w", op, ErrInvalidLink)
+	}
+
+	jwtToken, err := m.issuer.MagicLinkToken(ctx, link.UserID, link.AppID, link.Scopes)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	return jwtToken, nil
+}
+
+func mapStorageErr(err error) error {
+	switch {
+	case errors.Is(err, storage.ErrAppNotFound):
+		return ErrAppNotFound
+	case errors.Is(err, storage.ErrMagicLinkNotFound):
+		return ErrInvalidLink
+	}
+
+	return err
+}
@@ -0,0 +1,164 @@
+package magiclinks
+
+import (
+	"SSO/internal/domain/models"
+	"SSO/internal/storage"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	appID   = 1
+	linkURL = "https://app.example/magic?token="
+)
+
+// memStorage keeps links, users and apps in memory.
+type memStorage struct {
+	links map[string]models.MagicLink
+	users map[string]models.User
+	apps  map[int]models.App
+}
+
+func (s *memStorage) SaveMagicLink(_ context.Context, link models.MagicLink, tokenHash string) error {
+	for hash, l := range s.links {
+		if l.UserID == link.UserID && l.AppID == link.AppID {
+			delete(s.links, hash)
+		}
+	}
+	s.links[tokenHash] = link
+
+	return nil
+}
+
+func (s *memStorage) ConsumeMagicLink(_ context.Context, tokenHash string) (models.MagicLink, error) {
+	link, ok := s.links[tokenHash]
+	if !ok {
+		return models.MagicLink{}, storage.ErrMagicLinkNotFound
+	}
+	delete(s.links, tokenHash)
+
+	return link, nil
+}
+
+func (s *memStorage) User(_ context.Context, email string) (models.User, error) {
+	user, ok := s.users[email]
+	if !ok {
+		return models.User{}, storage.ErrUserNotFound
+	}
+
+	return user, nil
+}
+
+func (s *memStorage) App(_ context.Context, appID int) (models.App, error) {
+	app, ok := s.apps[appID]
+	if !ok {
+		return models.App{}, storage.ErrAppNotFound
+	}
+
+	return app, nil
+}
+
+type issuer struct{}
+
+func (issuer) MagicLinkToken(_ context.Context, userID int64, appID int, scopes []string) (string, error) {
+	return fmt.Sprintf("token:%d:%d:%s", userID, appID, strings.Join(scopes, " ")), nil
+}
+
+// inbox keeps sent emails by recipient.
+type inbox map[string][]string
+
+func (i inbox) Send(_ context.Context, to, _, body string) error {
+	i[to] = append(i[to], body)
+
+	return nil
+}
+
+// token returns token of the latest link sent to the recipient.
+func (i inbox) token(t *testing.T, to string) string {
+	t.Helper()
+
+	require.NotEmpty(t, i[to])
+	body := i[to][len(i[to])-1]
+
+	start := strings.Index(body, linkURL)
+	require.NotEqual(t, -1, start)
+
+	return strings.Fields(body[start+len(linkURL):])[0]
+}
+
+func newTestService(ttl time.Duration) (*MagicLinks, *memStorage, inbox) {
+	s := &memStorage{
+		links: make(map[string]models.MagicLink),
+		users: map[string]models.User{
+			"user@example.com": {ID: 7, Email: "user@example.com"},
+			"bot@example.com":  {ID: 8, Email: "bot@example.com", Kind: models.UserKindService},
+		},
+		apps: map[int]models.App{
+			appID: {ID: appID, Name: "App", GrantTypes: []string{models.GrantMagicLink}},
+			2:     {ID: 2, Name: "Other", GrantTypes: []string{models.GrantMagicLink}},
+			3:     {ID: 3, Name: "Password only", GrantTypes: []string{models.GrantPassword}},
+		},
+	}
+	mail := make(inbox)
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	return New(log, s, s, s, issuer{}, mail, ttl, linkURL), s, mail
+}
+
+func TestRequest(t *testing.T) {
+	m, s, mail := newTestService(time.Minute)
+	ctx := context.Background()
+
+	require.NoError(t, m.Request(ctx, "nobody@example.com", appID, nil))
+	require.NoError(t, m.Request(ctx, "bot@example.com", appID, nil))
+	assert.Empty(t, mail, "nothing is sent to unknown users and service accounts")
+
+	assert.ErrorIs(t, m.Request(ctx, "user@example.com", 3, nil), ErrGrantNotAllowed)
+	assert.ErrorIs(t, m.Request(ctx, "user@example.com", 4, nil), ErrAppNotFound)
+
+	require.NoError(t, m.Request(ctx, "user@example.com", appID, []string{"profile"}))
+	first := mail.token(t, "user@example.com")
+	require.NoError(t, m.Request(ctx, "user@example.com", appID, []string{"profile"}))
+	second := mail.token(t, "user@example.com")
+
+	require.Len(t, s.links, 1, "only the hash of the latest link is kept")
+	for hash := range s.links {
+		assert.NotEqual(t, second, hash)
+	}
+
+	_, err := m.Consume(ctx, first, appID)
+	assert.ErrorIs(t, err, ErrInvalidLink, "former link is void")
+
+	token, err := m.Consume(ctx, second, appID)
+	require.NoError(t, err)
+	assert.Equal(t, "token:7:1:profile", token)
+
+	_, err = m.Consume(ctx, second, appID)
+	assert.ErrorIs(t, err, ErrInvalidLink, "link is used once")
+}
+
+func TestConsume(t *testing.T) {
+	m, _, mail := newTestService(time.Minute)
+	ctx := context.Background()
+
+	require.NoError(t, m.Request(ctx, "user@example.com", appID, nil))
+	token := mail.token(t, "user@example.com")
+
+	_, err := m.Consume(ctx, token, 2)
+	assert.ErrorIs(t, err, ErrInvalidLink, "link is bound to the app")
+	_, err = m.Consume(ctx, token, appID)
+	assert.ErrorIs(t, err, ErrInvalidLink, "link is void after use for another app")
+
+	expired, _, mail := newTestService(-time.Second)
+	require.NoError(t, expired.Request(ctx, "user@example.com", appID, nil))
+	_, err = expired.Consume(ctx, mail.token(t, "user@example.com"), appID)
+	assert.ErrorIs(t, err, ErrInvalidLink, "link is expired")
+}
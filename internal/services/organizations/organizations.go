@@ -0,0 +1,456 @@
+package organizations
+
+import (
+	"SSO/internal/domain/models"
+	"SSO/internal/storage"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+)
+
+type Organizations struct {
+	log         *slog.Logger
+	orgSaver    OrganizationSaver
+	orgProvider OrganizationProvider
+	admins      AppAdminChecker
+}
+
+type OrganizationSaver interface {
+	SaveOrganization(ctx context.Context, name string, ownerID int64) (int64, error)
+	DeleteOrganization(ctx context.Context, orgID int64) error
+	SaveMember(ctx context.Context, orgID, userID int64, role string) error
+	UpdateMemberRole(ctx context.Context, orgID, userID int64, role string) error
+	DeleteMember(ctx context.Context, orgID, userID int64) error
+	SaveOrganizationApp(ctx context.Context, orgID int64, appID int) error
+	DeleteOrganizationApp(ctx context.Context, orgID int64, appID int) error
+}
+
+type OrganizationProvider interface {
+	Organization(ctx context.Context, orgID int64) (models.Organization, error)
+	UserOrganizations(ctx context.Context, userID int64) ([]models.OrganizationMember, error)
+	Member(ctx context.Context, orgID, userID int64) (models.OrganizationMember, error)
+	Members(ctx context.Context, orgID int64) ([]models.OrganizationMember, error)
+	OrganizationApps(ctx context.Context, orgID int64) ([]models.App, error)
+}
+
+type AppAdminChecker interface {
+	IsAppAdmin(ctx context.Context, appID int, userID int64) (bool, error)
+}
+
+var (
+	ErrOrgExists      = errors.New("organization already exists")
+	ErrOrgNotFound    = errors.New("organization not found")
+	ErrMemberExists   = errors.New("user is already a member")
+	ErrMemberNotFound = errors.New("member not found")
+	ErrUserNotFound   = errors.New("user not found")
+	ErrAppNotFound    = errors.New("app not found")
+	ErrInvalidRole    = errors.New("invalid role")
+	ErrForbidden      = errors.New("not enough rights in organization")
+	ErrNotAppAdmin    = errors.New("not an admin of the app")
+	ErrLastOwner      = errors.New("organization must have at least one owner")
+)
+
+// managers are roles allowed to manage members and apps of organization.
+var managers = []string{models.OrgRoleOwner, models.OrgRoleAdmin}
+
+// New returns a new instance of Organizations service.
+func New(
+	log *slog.Logger,
+	orgSaver OrganizationSaver,
+	orgProvider OrganizationProvider,
+	admins AppAdminChecker,
+) *Organizations {
+	return &Organizations{
+		log:         log,
+		orgSaver:    orgSaver,
+		orgProvider: orgProvider,
+		admins:      admins,
+	}
+}
+
+// CreateOrganization creates organization owned by ownerID.
+func (o *Organizations) CreateOrganization(ctx context.Context, ownerID int64, name string) (int64, error) {
+	const op = "Organizations.CreateOrganization"
+
+	log := o.log.With(
+		slog.String("op", op),
+		slog.Int64("owner_id", ownerID),
+		slog.String("name", name),
+	)
+
+	log.Info("creating organization")
+
+	id, err := o.orgSaver.SaveOrganization(ctx, name, ownerID)
+	if err != nil {
+		log.Error("failed to save organization", slog.String("error", err.Error()))
+
+		return 0, fmt.Errorf("%s: %w", op, mapStorageErr(err))
+	}
+
+	log.Info("organization created", slog.Int64("organization_id", id))
+
+	return id, nil
+}
+
+// Organization returns organization actor is a member of.
+func (o *Organizations) Organization(ctx context.Context, actorID, orgID int64) (models.Organization, error) {
+	const op = "Organizations.Organization"
+
+	if _, err := o.member(ctx, orgID, actorID); err != nil {
+		return models.Organization{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	org, err := o.orgProvider.Organization(ctx, orgID)
+	if err != nil {
+		return models.Organization{}, fmt.Errorf("%s: %w", op, mapStorageErr(err))
+	}
+
+	return org, nil
+}
+
+// UserOrganizations returns memberships of user.
+func (o *Organizations) UserOrganizations(ctx context.Context, userID int64) ([]models.OrganizationMember, error) {
+	const op = "Organizations.UserOrganizations"
+
+	members, err := o.orgProvider.UserOrganizations(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return members, nil
+}
+
+// DeleteOrganization deletes organization. Only owners may do it.
+func (o *Organizations) DeleteOrganization(ctx context.Context, actorID, orgID int64) error {
+	const op = "Organizations.DeleteOrganization"
+
+	log := o.log.With(
+		slog.String("op", op),
+		slog.Int64("actor_id", actorID),
+		slog.Int64("organization_id", orgID),
+	)
+
+	if _, err := o.requireRole(ctx, orgID, actorID, models.OrgRoleOwner); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := o.orgSaver.DeleteOrganization(ctx, orgID); err != nil {
+		log.Error("failed to delete organization", slog.String("error", err.Error()))
+
+		return fmt.Errorf("%s: %w", op, mapStorageErr(err))
+	}
+
+	log.Info("organization deleted")
+
+	return nil
+}
+
+// AddMember adds user to organization.
+//
+// Owners and admins may add members, only owners may add owners and admins.
+func (o *Organizations) AddMember(ctx context.Context, actorID, orgID, userID int64, role string) error {
+	const op = "Organizations.AddMember"
+
+	log := o.log.With(
+		slog.String("op", op),
+		slog.Int64("actor_id", actorID),
+		slog.Int64("organization_id", orgID),
+		slog.Int64("user_id", userID),
+		slog.String("role", role),
+	)
+
+	if err := o.CheckGrant(ctx, actorID, orgID, role); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := o.orgSaver.SaveMember(ctx, orgID, userID, role); err != nil {
+		log.Error("failed to save member", slog.String("error", err.Error()))
+
+		return fmt.Errorf("%s: %w", op, mapStorageErr(err))
+	}
+
+	log.Info("member added")
+
+	return nil
+}
+
+// UpdateMemberRole changes role of organization member.
+//
+// Only owners may grant or take away owner and admin roles.
+// The last owner can't be demoted.
+func (o *Organizations) UpdateMemberRole(ctx context.Context, actorID, orgID, userID int64, role string) error {
+	const op = "Organizations.UpdateMemberRole"
+
+	log := o.log.With(
+		slog.String("op", op),
+		slog.Int64("actor_id", actorID),
+		slog.Int64("organization_id", orgID),
+		slog.Int64("user_id", userID),
+		slog.String("role", role),
+	)
+
+	if err := o.CheckGrant(ctx, actorID, orgID, role); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	target, err := o.orgProvider.Member(ctx, orgID, userID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, mapStorageErr(err))
+	}
+
+	if err := o.checkTarget(ctx, orgID, actorID, target); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := o.orgSaver.UpdateMemberRole(ctx, orgID, userID, role); err != nil {
+		log.Error("failed to update member role", slog.String("error", err.Error()))
+
+		return fmt.Errorf("%s: %w", op, mapStorageErr(err))
+	}
+
+	log.Info("member role updated")
+
+	return nil
+}
+
+// RemoveMember removes user from organization.
+//
+// Members may leave organization themselves. The last owner can't be removed.
+func (o *Organizations) RemoveMember(ctx context.Context, actorID, orgID, userID int64) error {
+	const op = "Organizations.RemoveMember"
+
+	log := o.log.With(
+		slog.String("op", op),
+		slog.Int64("actor_id", actorID),
+		slog.Int64("organization_id", orgID),
+		slog.Int64("user_id", userID),
+	)
+
+	target, err := o.member(ctx, orgID, userID)
+	if err != nil {
+		if errors.Is(err, ErrForbidden) {
+			err = ErrMemberNotFound
+		}
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if actorID != userID {
+		if _, err := o.requireRole(ctx, orgID, actorID, managers...); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		if err := o.checkTarget(ctx, orgID, actorID, target); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err := o.orgSaver.DeleteMember(ctx, orgID, userID); err != nil {
+		log.Error("failed to delete member", slog.String("error", err.Error()))
+
+		return fmt.Errorf("%s: %w", op, mapStorageErr(err))
+	}
+
+	log.Info("member removed")
+
+	return nil
+}
+
+// Members returns members of organization actor is a member of.
+func (o *Organizations) Members(ctx context.Context, actorID, orgID int64) ([]models.OrganizationMember, error) {
+	const op = "Organizations.Members"
+
+	if _, err := o.member(ctx, orgID, actorID); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	members, err := o.orgProvider.Members(ctx, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return members, nil
+}
+
+// GrantAppAccess allows members of organization to log in to the app within it.
+//
+// Actor must manage the organization and be an admin of the app.
+func (o *Organizations) GrantAppAccess(ctx context.Context, actorID, orgID int64, appID int) error {
+	const op = "Organizations.GrantAppAccess"
+
+	log := o.log.With(
+		slog.String("op", op),
+		slog.Int64("actor_id", actorID),
+		slog.Int64("organization_id", orgID),
+		slog.Int("app_id", appID),
+	)
+
+	if _, err := o.requireRole(ctx, orgID, actorID, managers...); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	isAdmin, err := o.admins.IsAppAdmin(ctx, appID, actorID)
+	if err != nil {
+		log.Error("failed to check app admin", slog.String("error", err.Error()))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if !isAdmin {
+		return fmt.Errorf("%s: %w", op, ErrNotAppAdmin)
+	}
+
+	if err := o.orgSaver.SaveOrganizationApp(ctx, orgID, appID); err != nil {
+		log.Error("failed to grant app access", slog.String("error", err.Error()))
+
+		return fmt.Errorf("%s: %w", op, mapStorageErr(err))
+	}
+
+	log.Info("app access granted")
+
+	return nil
+}
+
+// RevokeAppAccess revokes access of organization to the app.
+func (o *Organizations) RevokeAppAccess(ctx context.Context, actorID, orgID int64, appID int) error {
+	const op = "Organizations.RevokeAppAccess"
+
+	log := o.log.With(
+		slog.String("op", op),
+		slog.Int64("actor_id", actorID),
+		slog.Int64("organization_id", orgID),
+		slog.Int("app_id", appID),
+	)
+
+	if _, err := o.requireRole(ctx, orgID, actorID, managers...); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := o.orgSaver.DeleteOrganizationApp(ctx, orgID, appID); err != nil {
+		log.Error("failed to revoke app access", slog.String("error", err.Error()))
+
+		return fmt.Errorf("%s: %w", op, mapStorageErr(err))
+	}
+
+	log.Info("app access revoked")
+
+	return nil
+}
+
+// Apps returns apps organization has access to.
+func (o *Organizations) Apps(ctx context.Context, actorID, orgID int64) ([]models.App, error) {
+	const op = "Organizations.Apps"
+
+	if _, err := o.member(ctx, orgID, actorID); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	apps, err := o.orgProvider.OrganizationApps(ctx, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return apps, nil
+}
+
+// member returns membership of user, users outside of organization get ErrForbidden.
+func (o *Organizations) member(ctx context.Context, orgID, userID int64) (models.OrganizationMember, error) {
+	m, err := o.orgProvider.Member(ctx, orgID, userID)
+	if err != nil {
+		if errors.Is(err, storage.ErrMemberNotFound) {
+			return models.OrganizationMember{}, ErrForbidden
+		}
+
+		return models.OrganizationMember{}, err
+	}
+
+	return m, nil
+}
+
+// requireRole checks that user is a member of organization with one of roles.
+func (o *Organizations) requireRole(ctx context.Context, orgID, userID int64, roles ...string) (models.OrganizationMember, error) {
+	m, err := o.member(ctx, orgID, userID)
+	if err != nil {
+		return models.OrganizationMember{}, err
+	}
+
+	if !slices.Contains(roles, m.Role) {
+		return models.OrganizationMember{}, ErrForbidden
+	}
+
+	return m, nil
+}
+
+// CheckGrant checks that actor may give role to members of organization:
+// owners may give any role, admins may only add plain members.
+func (o *Organizations) CheckGrant(ctx context.Context, actorID, orgID int64, role string) error {
+	if !IsValidRole(role) {
+		return ErrInvalidRole
+	}
+
+	actor, err := o.requireRole(ctx, orgID, actorID, managers...)
+	if err != nil {
+		return err
+	}
+
+	if role != models.OrgRoleMember && actor.Role != models.OrgRoleOwner {
+		return ErrForbidden
+	}
+
+	return nil
+}
+
+// CheckManager checks that actor is an owner or an admin of organization.
+func (o *Organizations) CheckManager(ctx context.Context, actorID, orgID int64) error {
+	_, err := o.requireRole(ctx, orgID, actorID, managers...)
+
+	return err
+}
+
+// checkTarget checks that actor may manage target member: admins may
+// manage only plain members.
+func (o *Organizations) checkTarget(ctx context.Context, orgID, actorID int64, target models.OrganizationMember) error {
+	actor, err := o.requireRole(ctx, orgID, actorID, managers...)
+	if err != nil {
+		return err
+	}
+
+	if target.Role != models.OrgRoleMember && actor.Role != models.OrgRoleOwner {
+		return ErrForbidden
+	}
+
+	return nil
+}
+
+// IsValidRole reports whether role is a known organization role.
+func IsValidRole(role string) bool {
+	switch role {
+	case models.OrgRoleOwner, models.OrgRoleAdmin, models.OrgRoleMember:
+		return true
+	}
+
+	return false
+}
+
+func mapStorageErr(err error) error {
+	switch {
+	case errors.Is(err, storage.ErrOrgExists):
+		return ErrOrgExists
+	case errors.Is(err, storage.ErrOrgNotFound):
+		return ErrOrgNotFound
+	case errors.Is(err, storage.ErrMemberExists):
+		return ErrMemberExists
+	case errors.Is(err, storage.ErrMemberNotFound):
+		return ErrMemberNotFound
+	case errors.Is(err, storage.ErrLastOwner):
+		return ErrLastOwner
+	case errors.Is(err, storage.ErrUserNotFound):
+		return ErrUserNotFound
+	case errors.Is(err, storage.ErrAppNotFound):
+		return ErrAppNotFound
+	}
+
+	return err
+}
@@ -0,0 +1,191 @@
+package organizations
+
+import (
+	"SSO/internal/domain/models"
+	"SSO/internal/storage"
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memStorage keeps memberships of a single organization in memory and
+// refuses to demote or remove its last owner as the storage does.
+type memStorage struct {
+	roles     map[int64]string
+	apps      []int
+	appAdmins map[int64]bool
+}
+
+func (s *memStorage) SaveOrganization(context.Context, string, int64) (int64, error) {
+	return 1, nil
+}
+
+func (s *memStorage) DeleteOrganization(context.Context, int64) error {
+	return nil
+}
+
+func (s *memStorage) SaveMember(_ context.Context, _, userID int64, role string) error {
+	if _, ok := s.roles[userID]; ok {
+		return storage.ErrMemberExists
+	}
+	s.roles[userID] = role
+
+	return nil
+}
+
+func (s *memStorage) UpdateMemberRole(_ context.Context, _, userID int64, role string) error {
+	if _, ok := s.roles[userID]; !ok {
+		return storage.ErrMemberNotFound
+	}
+	if role != models.OrgRoleOwner && s.lastOwner(userID) {
+		return storage.ErrLastOwner
+	}
+	s.roles[userID] = role
+
+	return nil
+}
+
+func (s *memStorage) DeleteMember(_ context.Context, _, userID int64) error {
+	if _, ok := s.roles[userID]; !ok {
+		return storage.ErrMemberNotFound
+	}
+	if s.lastOwner(userID) {
+		return storage.ErrLastOwner
+	}
+	delete(s.roles, userID)
+
+	return nil
+}
+
+func (s *memStorage) lastOwner(userID int64) bool {
+	if s.roles[userID] != models.OrgRoleOwner {
+		return false
+	}
+	for id, role := range s.roles {
+		if id != userID && role == models.OrgRoleOwner {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (s *memStorage) SaveOrganizationApp(_ context.Context, _ int64, appID int) error {
+	s.apps = append(s.apps, appID)
+
+	return nil
+}
+
+func (s *memStorage) DeleteOrganizationApp(context.Context, int64, int) error {
+	return nil
+}
+
+func (s *memStorage) Organization(_ context.Context, orgID int64) (models.Organization, error) {
+	return models.Organization{ID: orgID}, nil
+}
+
+func (s *memStorage) UserOrganizations(context.Context, int64) ([]models.OrganizationMember, error) {
+	return nil, nil
+}
+
+func (s *memStorage) Member(_ context.Context, orgID, userID int64) (models.OrganizationMember, error) {
+	role, ok := s.roles[userID]
+	if !ok {
+		return models.OrganizationMember{}, storage.ErrMemberNotFound
+	}
+
+	return models.OrganizationMember{OrganizationID: orgID, UserID: userID, Role: role}, nil
+}
+
+func (s *memStorage) Members(context.Context, int64) ([]models.OrganizationMember, error) {
+	return nil, nil
+}
+
+func (s *memStorage) OrganizationApps(context.Context, int64) ([]models.App, error) {
+	return nil, nil
+}
+
+func (s *memStorage) IsAppAdmin(_ context.Context, _ int, userID int64) (bool, error) {
+	return s.appAdmins[userID], nil
+}
+
+const (
+	orgID  = 1
+	owner  = 1
+	admin  = 2
+	member = 3
+	other  = 4
+)
+
+func newTestService() (*Organizations, *memStorage) {
+	s := &memStorage{
+		roles: map[int64]string{
+			owner:  models.OrgRoleOwner,
+			admin:  models.OrgRoleAdmin,
+			member: models.OrgRoleMember,
+		},
+		appAdmins: make(map[int64]bool),
+	}
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	return New(log, s, s, s), s
+}
+
+func TestRoles(t *testing.T) {
+	o, s := newTestService()
+	ctx := context.Background()
+
+	assert.ErrorIs(t, o.AddMember(ctx, member, orgID, other, models.OrgRoleMember), ErrForbidden, "members can't add members")
+	assert.ErrorIs(t, o.AddMember(ctx, other, orgID, 5, models.OrgRoleMember), ErrForbidden, "outsiders can't add members")
+	assert.ErrorIs(t, o.AddMember(ctx, admin, orgID, other, models.OrgRoleAdmin), ErrForbidden, "admins can't add admins")
+	assert.ErrorIs(t, o.AddMember(ctx, owner, orgID, other, "boss"), ErrInvalidRole)
+
+	require.NoError(t, o.AddMember(ctx, admin, orgID, other, models.OrgRoleMember))
+	assert.ErrorIs(t, o.AddMember(ctx, admin, orgID, other, models.OrgRoleMember), ErrMemberExists)
+
+	assert.ErrorIs(t, o.UpdateMemberRole(ctx, admin, orgID, other, models.OrgRoleAdmin), ErrForbidden)
+	assert.ErrorIs(t, o.RemoveMember(ctx, admin, orgID, owner), ErrForbidden, "admins can't remove owners")
+	assert.ErrorIs(t, o.RemoveMember(ctx, member, orgID, other), ErrForbidden)
+
+	require.NoError(t, o.UpdateMemberRole(ctx, owner, orgID, other, models.OrgRoleAdmin))
+	assert.Equal(t, models.OrgRoleAdmin, s.roles[other])
+
+	require.NoError(t, o.RemoveMember(ctx, admin, orgID, member))
+	require.NoError(t, o.RemoveMember(ctx, other, orgID, other), "members may leave")
+	assert.Equal(t, map[int64]string{owner: models.OrgRoleOwner, admin: models.OrgRoleAdmin}, s.roles)
+
+	assert.ErrorIs(t, o.DeleteOrganization(ctx, admin, orgID), ErrForbidden, "only owners delete organization")
+	require.NoError(t, o.DeleteOrganization(ctx, owner, orgID))
+}
+
+func TestLastOwner(t *testing.T) {
+	o, s := newTestService()
+	ctx := context.Background()
+
+	assert.ErrorIs(t, o.UpdateMemberRole(ctx, owner, orgID, owner, models.OrgRoleAdmin), ErrLastOwner)
+	assert.ErrorIs(t, o.RemoveMember(ctx, owner, orgID, owner), ErrLastOwner)
+
+	require.NoError(t, o.UpdateMemberRole(ctx, owner, orgID, admin, models.OrgRoleOwner))
+	require.NoError(t, o.UpdateMemberRole(ctx, admin, orgID, owner, models.OrgRoleMember), "owners may demote each other")
+	assert.ErrorIs(t, o.RemoveMember(ctx, admin, orgID, admin), ErrLastOwner)
+	assert.Equal(t, models.OrgRoleOwner, s.roles[admin])
+}
+
+func TestGrantAppAccess(t *testing.T) {
+	o, s := newTestService()
+	ctx := context.Background()
+
+	assert.ErrorIs(t, o.GrantAppAccess(ctx, member, orgID, 7), ErrForbidden)
+	assert.ErrorIs(t, o.GrantAppAccess(ctx, owner, orgID, 7), ErrNotAppAdmin, "organization owners can't grant access to any app")
+
+	s.appAdmins[member] = true
+	assert.ErrorIs(t, o.GrantAppAccess(ctx, member, orgID, 7), ErrForbidden, "app admins must manage the organization")
+
+	s.appAdmins[admin] = true
+	require.NoError(t, o.GrantAppAccess(ctx, admin, orgID, 7))
+	assert.Equal(t, []int{7}, s.apps)
+}
@@ -0,0 +1,732 @@
+package otp
+
+import (
+	"SSO/internal/domain/models"
+	"SSO/internal/lib/jwt"
+	"SSO/internal/lib/mailer"
+	"SSO/internal/lib/secrets"
+	"SSO/internal/lib/sms"
+	"SSO/internal/storage"
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"slices"
+	"strings"
+	"time"
+)
+
+// codeDigits is the length of one-time passcodes.
+const codeDigits = 6
+
+// OTP sends one-time passcodes by email and SMS, which users log in with
+// instead of password or confirm login with password as a second factor.
+//
+// Every code belongs to a challenge identified by a random token the
+// client holds. Only hashes of tokens and codes are stored; codes are
+// hashed with the token, so short codes can't be recovered from storage
+// without it.
+type OTP struct {
+	log               *slog.Logger
+	factorSaver       FactorSaver
+	factorProvider    FactorProvider
+	challengeSaver    ChallengeSaver
+	challengeProvider ChallengeProvider
+	usrProvider       UserProvider
+	appProvider       AppProvider
+	issuer            TokenIssuer
+	mailer            mailer.Mailer
+	sms               sms.Sender
+	limits            Limits
+	reauthMaxAge      time.Duration
+}
+
+type FactorSaver interface {
+	SaveOTPFactor(ctx context.Context, factor models.OTPFactor) (models.OTPFactor, error)
+	DeleteOTPFactor(ctx context.Context, userID, id int64) error
+}
+
+type FactorProvider interface {
+	OTPFactors(ctx context.Context, userID int64) ([]models.OTPFactor, error)
+}
+
+type ChallengeSaver interface {
+	SaveOTPChallenge(ctx context.Context, challenge models.OTPChallenge, tokenHash string) error
+	SendOTPChallenge(ctx context.Context, tokenHash string, challenge models.OTPChallenge, prevSentAt time.Time) error
+	AttemptOTPChallenge(ctx context.Context, tokenHash string) (models.OTPChallenge, error)
+	DeleteOTPChallenge(ctx context.Context, tokenHash string) error
+}
+
+type ChallengeProvider interface {
+	OTPChallenge(ctx context.Context, tokenHash string) (models.OTPChallenge, error)
+	LastOTPSentAt(ctx context.Context, userID int64, purpose string) (time.Time, error)
+}
+
+type UserProvider interface {
+	User(ctx context.Context, email string) (models.User, error)
+}
+
+type AppProvider interface {
+	App(ctx context.Context, appID int) (models.App, error)
+}
+
+// TokenIssuer issues tokens for passed challenges, see auth.Auth.
+type TokenIssuer interface {
+	OTPToken(ctx context.Context, challenge models.OTPChallenge) (string, error)
+}
+
+// Limits protect codes from guessing and users from floods of messages.
+type Limits struct {
+	// CodeTTL is how long a code can be entered after it was sent.
+	CodeTTL time.Duration
+	// MaxAttempts is how many times a code can be entered.
+	MaxAttempts int
+	// ResendInterval is how long to wait before sending another code.
+	ResendInterval time.Duration
+	// MaxSends is how many codes can be sent for a challenge.
+	MaxSends int
+}
+
+// Sent is a code sent for a challenge.
+type Sent struct {
+	Channel string
+	// Destination is the email address or phone number the code was sent
+	// to, masked unless the caller entered it.
+	Destination string
+	ExpiresAt   time.Time
+	// ResendAt is when another code can be sent, zero if no more can.
+	ResendAt time.Time
+}
+
+var (
+	ErrUnknownChannel   = errors.New("unknown otp channel")
+	ErrAppNotFound      = errors.New("app not found")
+	ErrAppDisabled      = errors.New("app is disabled")
+	ErrGrantNotAllowed  = errors.New("otp login is not allowed for the app")
+	ErrInvalidChallenge = errors.New("invalid or expired challenge")
+	ErrFactorRequired   = errors.New("factor to send the code to is required")
+	ErrFactorNotFound   = errors.New("otp factor not found")
+	ErrCodeNotSent      = errors.New("no code was sent for the challenge")
+	ErrInvalidCode      = errors.New("invalid code")
+	ErrTooManyAttempts  = errors.New("too many attempts, send another code")
+	ErrResendTooSoon    = errors.New("code was sent recently")
+	ErrTooManySends     = errors.New("too many codes were sent for the challenge")
+	ErrCallerNotAllowed = errors.New("factors can be managed only by the user themselves")
+	ErrReauthRequired   = errors.New("recent login is required")
+)
+
+// New returns a new instance of OTP service. Removing factors requires a
+// token issued within reauthMaxAge.
+func New(
+	log *slog.Logger,
+	factorSaver FactorSaver,
+	factorProvider FactorProvider,
+	challengeSaver ChallengeSaver,
+	challengeProvider ChallengeProvider,
+	userProvider UserProvider,
+	appProvider AppProvider,
+	issuer TokenIssuer,
+	mailer mailer.Mailer,
+	sms sms.Sender,
+	limits Limits,
+	reauthMaxAge time.Duration,
+) *OTP {
+	return &OTP{
+		log:               log,
+		factorSaver:       factorSaver,
+		factorProvider:    factorProvider,
+		challengeSaver:    challengeSaver,
+		challengeProvider: challengeProvider,
+		usrProvider:       userProvider,
+		appProvider:       appProvider,
+		issuer:            issuer,
+		mailer:            mailer,
+		sms:               sms,
+		limits:            limits,
+		reauthMaxAge:      reauthMaxAge,
+	}
+}
+
+// StartLogin sends code to log in to the app to the user with the email,
+// through the channel: to the account email or the confirmed email factor,
+// or to the confirmed phone number. It returns token of the challenge to
+// pass with Verify.
+//
+// The result is the same if there is no such user, the user has no factor
+// of the channel or a code was sent too recently, so it doesn't tell
+// whether the email is registered. No code is sent then.
+func (o *OTP) StartLogin(
+	ctx context.Context,
+	email string,
+	channel string,
+	appID int,
+	orgID int64,
+	scopes []string,
+) (string, error) {
+	const op = "OTP.StartLogin"
+
+	log := o.log.With(
+		slog.String("op", op),
+		slog.String("channel", channel),
+		slog.Int("app_id", appID),
+	)
+
+	if !slices.Contains(models.OTPChannels, channel) {
+		return "", fmt.Errorf("%s: %w", op, ErrUnknownChannel)
+	}
+
+	if err := o.checkApp(ctx, appID); err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	token, err := secrets.Generate(secrets.DefaultSize)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	user, err := o.usrProvider.User(ctx, email)
+	if err != nil {
+		if errors.Is(err, storage.ErrUserNotFound) {
+			log.Info("otp login requested for unknown email")
+
+			return token, nil
+		}
+
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	log = log.With(slog.Int64("user_id", user.ID))
+
+	if user.IsServiceAccount() {
+		log.Warn("otp login requested for service account")
+
+		return token, nil
+	}
+
+	challenge := models.OTPChallenge{
+		Purpose:        models.OTPPurposeLogin,
+		UserID:         user.ID,
+		Channel:        channel,
+		AppID:          appID,
+		OrganizationID: orgID,
+		Scopes:         scopes,
+	}
+
+	factor, err := o.factor(ctx, user.ID, channel)
+	switch {
+	case err == nil:
+		challenge.FactorID = factor.ID
+		challenge.Destination = factor.Destination
+	case errors.Is(err, ErrFactorNotFound) && channel == models.OTPChannelEmail:
+		challenge.Destination = user.Email
+	case errors.Is(err, ErrFactorNotFound):
+		log.Info("user has no factor of the channel")
+
+		return token, nil
+	default:
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	lastSentAt, err := o.challengeProvider.LastOTPSentAt(ctx, user.ID, models.OTPPurposeLogin)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	if time.Now().Before(lastSentAt.Add(o.limits.ResendInterval)) {
+		log.Warn("otp login throttled")
+
+		return token, nil
+	}
+
+	if _, err := o.create(ctx, log, token, challenge); err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("otp login started")
+
+	return token, nil
+}
+
+// Send sends another code for the challenge. Second factor challenges
+// need factorID of the first code, after that it may be 0 to send to the
+// same factor. Factors of other challenges can't change.
+func (o *OTP) Send(ctx context.Context, token string, factorID int64) (Sent, error) {
+	const op = "OTP.Send"
+
+	log := o.log.With(
+		slog.String("op", op),
+	)
+
+	hash := secrets.Hash(token)
+
+	challenge, err := o.challengeProvider.OTPChallenge(ctx, hash)
+	if err != nil {
+		return Sent{}, fmt.Errorf("%s: %w", op, mapStorageErr(err))
+	}
+
+	log = log.With(
+		slog.Int64("user_id", challenge.UserID),
+		slog.String("purpose", challenge.Purpose),
+	)
+
+	now := time.Now()
+	if challenge.Expired(now) {
+		return Sent{}, fmt.Errorf("%s: %w", op, ErrInvalidChallenge)
+	}
+
+	if challenge.Sends >= o.limits.MaxSends {
+		return Sent{}, fmt.Errorf("%s: %w", op, ErrTooManySends)
+	}
+
+	// Codes are throttled per user, not only per challenge, as new
+	// challenges can be opened by logging in again.
+	lastSentAt, err := o.challengeProvider.LastOTPSentAt(ctx, challenge.UserID, challenge.Purpose)
+	if err != nil {
+		return Sent{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if now.Before(lastSentAt.Add(o.limits.ResendInterval)) {
+		return Sent{}, fmt.Errorf("%s: %w", op, ErrResendTooSoon)
+	}
+
+	if challenge.Purpose == models.OTPPurposeSecondFactor && factorID != 0 {
+		factor, err := o.factorByID(ctx, challenge.UserID, factorID)
+		if err != nil {
+			return Sent{}, fmt.Errorf("%s: %w", op, err)
+		}
+
+		challenge.FactorID = factor.ID
+		challenge.Channel = factor.Channel
+		challenge.Destination = factor.Destination
+	}
+
+	if challenge.Destination == "" {
+		return Sent{}, fmt.Errorf("%s: %w", op, ErrFactorRequired)
+	}
+
+	code, err := newCode()
+	if err != nil {
+		return Sent{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	prevSentAt := challenge.SentAt
+	challenge.CodeHash = codeHash(token, code)
+	challenge.Sends++
+	challenge.SentAt = now
+	challenge.ExpiresAt = now.Add(o.limits.CodeTTL)
+
+	if err := o.challengeSaver.SendOTPChallenge(ctx, hash, challenge, prevSentAt); err != nil {
+		if errors.Is(err, storage.ErrOTPChallengeNotFound) {
+			// Another code was sent meanwhile or the challenge was passed.
+			return Sent{}, fmt.Errorf("%s: %w", op, ErrResendTooSoon)
+		}
+
+		log.Error("failed to save otp challenge", slog.String("error", err.Error()))
+
+		return Sent{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := o.deliver(ctx, challenge.Channel, challenge.Destination, code); err != nil {
+		log.Error("failed to send code", slog.String("error", err.Error()))
+
+		return Sent{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("code sent", slog.String("channel", challenge.Channel))
+
+	sent := o.sent(challenge)
+	sent.Destination = MaskDestination(challenge.Channel, challenge.Destination)
+
+	return sent, nil
+}
+
+// Verify passes login or second factor challenge with the code and returns
+// token for the app, as Login does.
+func (o *OTP) Verify(ctx context.Context, token, code string) (string, error) {
+	const op = "OTP.Verify"
+
+	challenge, err := o.pass(ctx, token, code)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	if challenge.Purpose != models.OTPPurposeLogin && challenge.Purpose != models.OTPPurposeSecondFactor {
+		return "", fmt.Errorf("%s: %w", op, ErrInvalidChallenge)
+	}
+
+	jwtToken, err := o.issuer.OTPToken(ctx, challenge)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	return jwtToken, nil
+}
+
+// Enroll sends code to the email address or phone number the caller adds
+// as a factor of the channel. It returns token of the challenge to pass
+// with Confirm. A confirmed factor replaces the former one of the channel.
+func (o *OTP) Enroll(ctx context.Context, caller jwt.Claims, channel, destination string) (string, Sent, error) {
+	const op = "OTP.Enroll"
+
+	log := o.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", caller.UserID),
+		slog.String("channel", channel),
+	)
+
+	if err := checkCaller(caller); err != nil {
+		return "", Sent{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if !slices.Contains(models.OTPChannels, channel) {
+		return "", Sent{}, fmt.Errorf("%s: %w", op, ErrUnknownChannel)
+	}
+
+	lastSentAt, err := o.challengeProvider.LastOTPSentAt(ctx, caller.UserID, models.OTPPurposeEnroll)
+	if err != nil {
+		return "", Sent{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if time.Now().Before(lastSentAt.Add(o.limits.ResendInterval)) {
+		return "", Sent{}, fmt.Errorf("%s: %w", op, ErrResendTooSoon)
+	}
+
+	token, err := secrets.Generate(secrets.DefaultSize)
+	if err != nil {
+		return "", Sent{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	challenge, err := o.create(ctx, log, token, models.OTPChallenge{
+		Purpose:     models.OTPPurposeEnroll,
+		UserID:      caller.UserID,
+		Channel:     channel,
+		Destination: normalizeDestination(channel, destination),
+	})
+	if err != nil {
+		return "", Sent{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("factor enrollment started")
+
+	return token, o.sent(challenge), nil
+}
+
+// Confirm passes enrollment challenge of the caller with the code and
+// saves the factor.
+func (o *OTP) Confirm(ctx context.Context, caller jwt.Claims, token, code string) (models.OTPFactor, error) {
+	const op = "OTP.Confirm"
+
+	log := o.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", caller.UserID),
+	)
+
+	if err := checkCaller(caller); err != nil {
+		return models.OTPFactor{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	challenge, err := o.pass(ctx, token, code)
+	if err != nil {
+		return models.OTPFactor{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if challenge.Purpose != models.OTPPurposeEnroll || challenge.UserID != caller.UserID {
+		return models.OTPFactor{}, fmt.Errorf("%s: %w", op, ErrInvalidChallenge)
+	}
+
+	factor, err := o.factorSaver.SaveOTPFactor(ctx, models.OTPFactor{
+		UserID:      challenge.UserID,
+		Channel:     challenge.Channel,
+		Destination: challenge.Destination,
+	})
+	if err != nil {
+		log.Error("failed to save otp factor", slog.String("error", err.Error()))
+
+		return models.OTPFactor{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("factor enrolled", slog.Int64("factor_id", factor.ID), slog.String("channel", factor.Channel))
+
+	return factor, nil
+}
+
+// Factors returns factors of the caller.
+func (o *OTP) Factors(ctx context.Context, caller jwt.Claims) ([]models.OTPFactor, error) {
+	const op = "OTP.Factors"
+
+	if err := checkCaller(caller); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	factors, err := o.factorProvider.OTPFactors(ctx, caller.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return factors, nil
+}
+
+// DeleteFactor deletes factor of the caller, whose token must have been
+// issued within reauthMaxAge. Login no longer requires second factor once
+// the last factor is deleted.
+func (o *OTP) DeleteFactor(ctx context.Context, caller jwt.Claims, factorID int64) error {
+	const op = "OTP.DeleteFactor"
+
+	log := o.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", caller.UserID),
+		slog.Int64("factor_id", factorID),
+	)
+
+	if err := checkCaller(caller); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if caller.IssuedAt.IsZero() || time.Since(caller.IssuedAt) > o.reauthMaxAge {
+		return fmt.Errorf("%s: %w", op, ErrReauthRequired)
+	}
+
+	if err := o.factorSaver.DeleteOTPFactor(ctx, caller.UserID, factorID); err != nil {
+		return fmt.Errorf("%s: %w", op, mapStorageErr(err))
+	}
+
+	log.Info("factor deleted")
+
+	return nil
+}
+
+// create saves challenge with a new code and sends the code.
+func (o *OTP) create(
+	ctx context.Context,
+	log *slog.Logger,
+	token string,
+	challenge models.OTPChallenge,
+) (models.OTPChallenge, error) {
+	code, err := newCode()
+	if err != nil {
+		return models.OTPChallenge{}, err
+	}
+
+	now := time.Now()
+	challenge.CodeHash = codeHash(token, code)
+	challenge.Sends = 1
+	challenge.SentAt = now
+	challenge.ExpiresAt = now.Add(o.limits.CodeTTL)
+
+	if err := o.challengeSaver.SaveOTPChallenge(ctx, challenge, secrets.Hash(token)); err != nil {
+		log.Error("failed to save otp challenge", slog.String("error", err.Error()))
+
+		return models.OTPChallenge{}, err
+	}
+
+	if err := o.deliver(ctx, challenge.Channel, challenge.Destination, code); err != nil {
+		log.Error("failed to send code", slog.String("error", err.Error()))
+
+		// The code is lost, don't leave a challenge nobody can pass.
+		_ = o.challengeSaver.DeleteOTPChallenge(ctx, secrets.Hash(token))
+
+		return models.OTPChallenge{}, err
+	}
+
+	return challenge, nil
+}
+
+// pass counts an attempt to enter the code of the challenge and, if the
+// code is right, deletes the challenge and returns it.
+func (o *OTP) pass(ctx context.Context, token, code string) (models.OTPChallenge, error) {
+	hash := secrets.Hash(token)
+
+	challenge, err := o.challengeSaver.AttemptOTPChallenge(ctx, hash)
+	if err != nil {
+		return models.OTPChallenge{}, mapStorageErr(err)
+	}
+
+	if err := o.check(challenge, token, code, time.Now()); err != nil {
+		return models.OTPChallenge{}, err
+	}
+
+	// Deleting fails if a concurrent attempt passed the challenge first.
+	if err := o.challengeSaver.DeleteOTPChallenge(ctx, hash); err != nil {
+		return models.OTPChallenge{}, mapStorageErr(err)
+	}
+
+	return challenge, nil
+}
+
+// check checks the code of the challenge with the attempt counted.
+func (o *OTP) check(challenge models.OTPChallenge, token, code string, now time.Time) error {
+	if challenge.Expired(now) {
+		return ErrInvalidChallenge
+	}
+
+	if challenge.CodeHash == "" {
+		return ErrCodeNotSent
+	}
+
+	if challenge.Attempts > o.limits.MaxAttempts {
+		return ErrTooManyAttempts
+	}
+
+	if subtle.ConstantTimeCompare([]byte(codeHash(token, code)), []byte(challenge.CodeHash)) != 1 {
+		return ErrInvalidCode
+	}
+
+	return nil
+}
+
+// sent describes the code sent for the challenge.
+func (o *OTP) sent(challenge models.OTPChallenge) Sent {
+	sent := Sent{
+		Channel:     challenge.Channel,
+		Destination: challenge.Destination,
+		ExpiresAt:   challenge.ExpiresAt,
+	}
+
+	if challenge.Sends < o.limits.MaxSends {
+		sent.ResendAt = challenge.SentAt.Add(o.limits.ResendInterval)
+	}
+
+	return sent
+}
+
+func (o *OTP) checkApp(ctx context.Context, appID int) error {
+	app, err := o.appProvider.App(ctx, appID)
+	if err != nil {
+		return mapStorageErr(err)
+	}
+
+	if app.Disabled {
+		return ErrAppDisabled
+	}
+
+	if !app.AllowsGrant(models.GrantOTP) {
+		return ErrGrantNotAllowed
+	}
+
+	return nil
+}
+
+// factor returns factor of the user of the channel.
+func (o *OTP) factor(ctx context.Context, userID int64, channel string) (models.OTPFactor, error) {
+	factors, err := o.factorProvider.OTPFactors(ctx, userID)
+	if err != nil {
+		return models.OTPFactor{}, err
+	}
+
+	for _, f := range factors {
+		if f.Channel == channel {
+			return f, nil
+		}
+	}
+
+	return models.OTPFactor{}, ErrFactorNotFound
+}
+
+// factorByID returns factor of the user by its ID.
+func (o *OTP) factorByID(ctx context.Context, userID, factorID int64) (models.OTPFactor, error) {
+	factors, err := o.factorProvider.OTPFactors(ctx, userID)
+	if err != nil {
+		return models.OTPFactor{}, err
+	}
+
+	for _, f := range factors {
+		if f.ID == factorID {
+			return f, nil
+		}
+	}
+
+	return models.OTPFactor{}, ErrFactorNotFound
+}
+
+func (o *OTP) deliver(ctx context.Context, channel, destination, code string) error {
+	text := fmt.Sprintf(
+		"Your verification code is %s. It expires in %s. If you didn't request it, ignore this message.",
+		code, o.limits.CodeTTL,
+	)
+
+	switch channel {
+	case models.OTPChannelEmail:
+		return o.mailer.Send(ctx, destination, "Your verification code", text)
+	case models.OTPChannelSMS:
+		return o.sms.Send(ctx, destination, text)
+	}
+
+	return ErrUnknownChannel
+}
+
+// checkCaller checks that factors are managed by the user themselves, not
+// an impersonating admin, an API key or a service account.
+func checkCaller(caller jwt.Claims) error {
+	if caller.Impersonated() || caller.KeyID != 0 || caller.ServiceAccount {
+		return ErrCallerNotAllowed
+	}
+
+	return nil
+}
+
+// newCode returns random code of codeDigits digits.
+func newCode() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1_000_000))
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%0*d", codeDigits, n.Int64()), nil
+}
+
+// codeHash returns hash of the code bound to the challenge token.
+func codeHash(token, code string) string {
+	return secrets.Hash(token + ":" + code)
+}
+
+// normalizeDestination lowercases emails and drops formatting of phone numbers.
+func normalizeDestination(channel, destination string) string {
+	destination = strings.TrimSpace(destination)
+
+	if channel == models.OTPChannelEmail {
+		return strings.ToLower(destination)
+	}
+
+	return strings.Map(func(r rune) rune {
+		if r == '+' || r >= '0' && r <= '9' {
+			return r
+		}
+
+		return -1
+	}, destination)
+}
+
+// MaskDestination hides most of the email address or phone number, to show
+// which factor the code was sent to.
+func MaskDestination(channel, destination string) string {
+	if channel == models.OTPChannelEmail {
+		local, domain, ok := strings.Cut(destination, "@")
+		if !ok || local == "" {
+			return "***"
+		}
+
+		return local[:1] + "***@" + domain
+	}
+
+	if len(destination) <= 4 {
+		return strings.Repeat("*", len(destination))
+	}
+
+	return strings.Repeat("*", len(destination)-4) + destination[len(destination)-4:]
+}
+
+func mapStorageErr(err error) error {
+	switch {
+	case errors.Is(err, storage.ErrAppNotFound):
+		return ErrAppNotFound
+	case errors.Is(err, storage.ErrOTPChallengeNotFound):
+		return ErrInvalidChallenge
+	case errors.Is(err, storage.ErrOTPFactorNotFound):
+		return ErrFactorNotFound
+	}
+
+	return err
+}
@@ -0,0 +1,348 @@
+package otp
+
+import (
+	"SSO/internal/domain/models"
+	"SSO/internal/lib/jwt"
+	"SSO/internal/lib/secrets"
+	"SSO/internal/storage"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const appID = 1
+
+// memStorage keeps factors, challenges, users and apps in memory.
+type memStorage struct {
+	factors    []models.OTPFactor
+	challenges map[string]models.OTPChallenge
+	users      map[string]models.User
+	apps       map[int]models.App
+}
+
+func (s *memStorage) SaveOTPFactor(_ context.Context, factor models.OTPFactor) (models.OTPFactor, error) {
+	for i, f := range s.factors {
+		if f.UserID == factor.UserID && f.Channel == factor.Channel {
+			s.factors[i].Destination = factor.Destination
+
+			return s.factors[i], nil
+		}
+	}
+	factor.ID = int64(len(s.factors) + 1)
+	s.factors = append(s.factors, factor)
+
+	return factor, nil
+}
+
+func (s *memStorage) DeleteOTPFactor(_ context.Context, userID, id int64) error {
+	for i, f := range s.factors {
+		if f.UserID == userID && f.ID == id {
+			s.factors = append(s.factors[:i], s.factors[i+1:]...)
+
+			return nil
+		}
+	}
+
+	return storage.ErrOTPFactorNotFound
+}
+
+func (s *memStorage) OTPFactors(_ context.Context, userID int64) ([]models.OTPFactor, error) {
+	var factors []models.OTPFactor
+	for _, f := range s.factors {
+		if f.UserID == userID {
+			factors = append(factors, f)
+		}
+	}
+
+	return factors, nil
+}
+
+func (s *memStorage) SaveOTPChallenge(_ context.Context, challenge models.OTPChallenge, tokenHash string) error {
+	for hash, ch := range s.challenges {
+		if ch.UserID == challenge.UserID && ch.Purpose == challenge.Purpose {
+			delete(s.challenges, hash)
+		}
+	}
+	s.challenges[tokenHash] = challenge
+
+	return nil
+}
+
+func (s *memStorage) SendOTPChallenge(
+	_ context.Context,
+	tokenHash string,
+	challenge models.OTPChallenge,
+	prevSentAt time.Time,
+) error {
+	ch, ok := s.challenges[tokenHash]
+	if !ok || !ch.SentAt.Equal(prevSentAt) {
+		return storage.ErrOTPChallengeNotFound
+	}
+	challenge.Attempts = 0
+	s.challenges[tokenHash] = challenge
+
+	return nil
+}
+
+func (s *memStorage) AttemptOTPChallenge(_ context.Context, tokenHash string) (models.OTPChallenge, error) {
+	ch, ok := s.challenges[tokenHash]
+	if !ok {
+		return models.OTPChallenge{}, storage.ErrOTPChallengeNotFound
+	}
+	ch.Attempts++
+	s.challenges[tokenHash] = ch
+
+	return ch, nil
+}
+
+func (s *memStorage) DeleteOTPChallenge(_ context.Context, tokenHash string) error {
+	if _, ok := s.challenges[tokenHash]; !ok {
+		return storage.ErrOTPChallengeNotFound
+	}
+	delete(s.challenges, tokenHash)
+
+	return nil
+}
+
+func (s *memStorage) OTPChallenge(_ context.Context, tokenHash string) (models.OTPChallenge, error) {
+	ch, ok := s.challenges[tokenHash]
+	if !ok {
+		return models.OTPChallenge{}, storage.ErrOTPChallengeNotFound
+	}
+
+	return ch, nil
+}
+
+func (s *memStorage) LastOTPSentAt(_ context.Context, userID int64, purpose string) (time.Time, error) {
+	var last time.Time
+	for _, ch := range s.challenges {
+		if ch.UserID == userID && ch.Purpose == purpose && ch.SentAt.After(last) {
+			last = ch.SentAt
+		}
+	}
+
+	return last, nil
+}
+
+func (s *memStorage) User(_ context.Context, email string) (models.User, error) {
+	user, ok := s.users[email]
+	if !ok {
+		return models.User{}, storage.ErrUserNotFound
+	}
+
+	return user, nil
+}
+
+func (s *memStorage) App(_ context.Context, appID int) (models.App, error) {
+	app, ok := s.apps[appID]
+	if !ok {
+		return models.App{}, storage.ErrAppNotFound
+	}
+
+	return app, nil
+}
+
+type issuer struct{}
+
+func (issuer) OTPToken(_ context.Context, challenge models.OTPChallenge) (string, error) {
+	return fmt.Sprintf("token:%s:%d:%d", challenge.Purpose, challenge.UserID, challenge.AppID), nil
+}
+
+// outbox keeps texts of sent messages by recipient, for both channels.
+type outbox map[string][]string
+
+func (o outbox) Send(_ context.Context, to, _, body string) error {
+	o[to] = append(o[to], body)
+
+	return nil
+}
+
+var codeRe = regexp.MustCompile(`\d{6}`)
+
+// code returns the latest code sent to the recipient.
+func (o outbox) code(t *testing.T, to string) string {
+	t.Helper()
+
+	require.NotEmpty(t, o[to])
+
+	code := codeRe.FindString(o[to][len(o[to])-1])
+	require.NotEmpty(t, code)
+
+	return code
+}
+
+// smsOutbox adapts outbox to sms.Sender.
+type smsOutbox struct{ outbox }
+
+func (o smsOutbox) Send(ctx context.Context, to, text string) error {
+	return o.outbox.Send(ctx, to, "", text)
+}
+
+func newTestService(limits Limits) (*OTP, *memStorage, outbox) {
+	s := &memStorage{
+		challenges: make(map[string]models.OTPChallenge),
+		users: map[string]models.User{
+			"user@example.com": {ID: 7, Email: "user@example.com"},
+			"bot@example.com":  {ID: 8, Email: "bot@example.com", Kind: models.UserKindService},
+		},
+		apps: map[int]models.App{
+			appID: {ID: appID, Name: "App", GrantTypes: []string{models.GrantOTP}},
+			2:     {ID: 2, Name: "Password only", GrantTypes: []string{models.GrantPassword}},
+		},
+	}
+	out := make(outbox)
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	return New(log, s, s, s, s, s, s, issuer{}, out, smsOutbox{out}, limits, time.Minute), s, out
+}
+
+var testLimits = Limits{
+	CodeTTL:        time.Minute,
+	MaxAttempts:    2,
+	ResendInterval: time.Hour,
+	MaxSends:       2,
+}
+
+func TestStartLogin(t *testing.T) {
+	o, s, out := newTestService(testLimits)
+	ctx := context.Background()
+
+	_, err := o.StartLogin(ctx, "user@example.com", "pigeon", appID, 0, nil)
+	assert.ErrorIs(t, err, ErrUnknownChannel)
+	_, err = o.StartLogin(ctx, "user@example.com", models.OTPChannelEmail, 2, 0, nil)
+	assert.ErrorIs(t, err, ErrGrantNotAllowed)
+
+	for _, email := range []string{"nobody@example.com", "bot@example.com"} {
+		token, err := o.StartLogin(ctx, email, models.OTPChannelEmail, appID, 0, nil)
+		require.NoError(t, err)
+		assert.NotEmpty(t, token)
+	}
+	token, err := o.StartLogin(ctx, "user@example.com", models.OTPChannelSMS, appID, 0, nil)
+	require.NoError(t, err)
+	assert.NotEmpty(t, token, "user without phone gets a token too")
+	assert.Empty(t, out, "nothing is sent to unknown users, service accounts and missing factors")
+	assert.Empty(t, s.challenges)
+
+	token, err = o.StartLogin(ctx, "user@example.com", models.OTPChannelEmail, appID, 0, nil)
+	require.NoError(t, err)
+	code := out.code(t, "user@example.com")
+
+	_, err = o.StartLogin(ctx, "user@example.com", models.OTPChannelEmail, appID, 0, nil)
+	require.NoError(t, err)
+	assert.Len(t, out["user@example.com"], 1, "login is throttled")
+
+	_, err = o.Verify(ctx, token, "x"+code)
+	assert.ErrorIs(t, err, ErrInvalidCode)
+
+	jwtToken, err := o.Verify(ctx, token, code)
+	require.NoError(t, err)
+	assert.Equal(t, "token:login:7:1", jwtToken)
+
+	_, err = o.Verify(ctx, token, code)
+	assert.ErrorIs(t, err, ErrInvalidChallenge, "code is used once")
+}
+
+func TestVerifyAttempts(t *testing.T) {
+	limits := testLimits
+	limits.ResendInterval = 0
+	o, _, out := newTestService(limits)
+	ctx := context.Background()
+
+	token, err := o.StartLogin(ctx, "user@example.com", models.OTPChannelEmail, appID, 0, nil)
+	require.NoError(t, err)
+	code := out.code(t, "user@example.com")
+
+	for range limits.MaxAttempts {
+		_, err = o.Verify(ctx, token, "wrong")
+		assert.ErrorIs(t, err, ErrInvalidCode)
+	}
+	_, err = o.Verify(ctx, token, code)
+	assert.ErrorIs(t, err, ErrTooManyAttempts)
+
+	sent, err := o.Send(ctx, token, 0)
+	require.NoError(t, err)
+	assert.Equal(t, "u***@example.com", sent.Destination)
+	assert.True(t, sent.ResendAt.IsZero(), "no more codes can be sent")
+
+	_, err = o.Send(ctx, token, 0)
+	assert.ErrorIs(t, err, ErrTooManySends)
+
+	_, err = o.Verify(ctx, token, code)
+	assert.ErrorIs(t, err, ErrInvalidCode, "former code is void")
+
+	jwtToken, err := o.Verify(ctx, token, out.code(t, "user@example.com"))
+	require.NoError(t, err)
+	assert.Equal(t, "token:login:7:1", jwtToken)
+}
+
+func TestEnrollAndSecondFactor(t *testing.T) {
+	o, s, out := newTestService(testLimits)
+	ctx := context.Background()
+	caller := jwt.Claims{UserID: 7, IssuedAt: time.Now()}
+
+	_, _, err := o.Enroll(ctx, jwt.Claims{UserID: 7, KeyID: 1}, models.OTPChannelSMS, "+15550100")
+	assert.ErrorIs(t, err, ErrCallerNotAllowed)
+
+	token, sent, err := o.Enroll(ctx, caller, models.OTPChannelSMS, "+1 (555) 0100")
+	require.NoError(t, err)
+	assert.Equal(t, "+15550100", sent.Destination)
+
+	_, _, err = o.Enroll(ctx, caller, models.OTPChannelSMS, "+15550199")
+	assert.ErrorIs(t, err, ErrResendTooSoon)
+
+	_, err = o.Confirm(ctx, jwt.Claims{UserID: 9}, token, out.code(t, "+15550100"))
+	assert.ErrorIs(t, err, ErrInvalidChallenge, "challenge belongs to another user")
+
+	token, _, err = o.Enroll(ctx, caller, models.OTPChannelSMS, "+15550100")
+	require.NoError(t, err, "failed confirmation void the challenge")
+
+	_, err = o.Verify(ctx, token, out.code(t, "+15550100"))
+	assert.ErrorIs(t, err, ErrInvalidChallenge, "enrollment doesn't log in")
+
+	s.challenges = make(map[string]models.OTPChallenge)
+	token, _, err = o.Enroll(ctx, caller, models.OTPChannelSMS, "+15550100")
+	require.NoError(t, err)
+
+	factor, err := o.Confirm(ctx, caller, token, out.code(t, "+15550100"))
+	require.NoError(t, err)
+	assert.Equal(t, models.OTPFactor{ID: 1, UserID: 7, Channel: models.OTPChannelSMS, Destination: "+15550100"}, factor)
+
+	// Login with password saves a challenge without code, see auth.Auth.
+	s.challenges[secrets.Hash("second")] = models.OTPChallenge{
+		Purpose:   models.OTPPurposeSecondFactor,
+		UserID:    7,
+		AppID:     appID,
+		ExpiresAt: time.Now().Add(time.Minute),
+	}
+	_, err = o.Verify(ctx, "second", "000000")
+	assert.ErrorIs(t, err, ErrCodeNotSent)
+}
+
+func TestDeleteFactor(t *testing.T) {
+	o, s, _ := newTestService(testLimits)
+	ctx := context.Background()
+
+	s.factors = []models.OTPFactor{{ID: 1, UserID: 7, Channel: models.OTPChannelSMS, Destination: "+15550100"}}
+
+	err := o.DeleteFactor(ctx, jwt.Claims{UserID: 7, IssuedAt: time.Now().Add(-time.Hour)}, 1)
+	assert.ErrorIs(t, err, ErrReauthRequired)
+
+	err = o.DeleteFactor(ctx, jwt.Claims{UserID: 8, IssuedAt: time.Now()}, 1)
+	assert.ErrorIs(t, err, ErrFactorNotFound)
+
+	require.NoError(t, o.DeleteFactor(ctx, jwt.Claims{UserID: 7, IssuedAt: time.Now()}, 1))
+	assert.Empty(t, s.factors)
+}
+
+func TestMaskDestination(t *testing.T) {
+	assert.Equal(t, "j***@example.com", MaskDestination(models.OTPChannelEmail, "jane@example.com"))
+	assert.Equal(t, "********0100", MaskDestination(models.OTPChannelSMS, "+15555550100"))
+	assert.Equal(t, "***", MaskDestination(models.OTPChannelSMS, "123"))
+}
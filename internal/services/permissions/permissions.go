@@ -0,0 +1,333 @@
+package permissions
+
+import (
+	"SSO/internal/domain/models"
+	"SSO/internal/storage"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+)
+
+// AdminRole is the role allowed to manage roles and permissions of its app.
+const AdminRole = "admin"
+
+type Permissions struct {
+	log          *slog.Logger
+	roleSaver    RoleSaver
+	roleProvider RoleProvider
+}
+
+type RoleSaver interface {
+	SaveRole(ctx context.Context, appID int, name string) (int64, error)
+	DeleteRole(ctx context.Context, appID int, roleID int64) error
+	SavePermission(ctx context.Context, appID int, name string) (int64, error)
+	DeletePermission(ctx context.Context, appID int, permissionID int64) error
+	GrantPermission(ctx context.Context, appID int, roleID, permissionID int64) error
+	RevokePermission(ctx context.Context, appID int, roleID, permissionID int64) error
+	AssignRole(ctx context.Context, appID int, userID, roleID int64) error
+	UnassignRole(ctx context.Context, appID int, userID, roleID int64) error
+}
+
+type RoleProvider interface {
+	Roles(ctx context.Context, appID int) ([]models.Role, error)
+	Permissions(ctx context.Context, appID int) ([]models.Permission, error)
+	UserRoles(ctx context.Context, userID int64, appID int) ([]models.Role, error)
+	UserPermissions(ctx context.Context, userID int64, appID int) ([]string, error)
+	HasPermission(ctx context.Context, userID int64, appID int, permission string) (bool, error)
+	HasRole(ctx context.Context, userID int64, appID int, role string) (bool, error)
+}
+
+var (
+	ErrAppNotFound        = errors.New("app not found")
+	ErrUserNotFound       = errors.New("user not found")
+	ErrRoleExists         = errors.New("role already exists")
+	ErrRoleNotFound       = errors.New("role not found")
+	ErrPermissionExists   = errors.New("permission already exists")
+	ErrPermissionNotFound = errors.New("permission not found")
+)
+
+// New returns a new instance of Permissions service.
+func New(
+	log *slog.Logger,
+	roleSaver RoleSaver,
+	roleProvider RoleProvider,
+) *Permissions {
+	return &Permissions{
+		log:          log,
+		roleSaver:    roleSaver,
+		roleProvider: roleProvider,
+	}
+}
+
+// CreateRole creates role with given name in the app.
+func (p *Permissions) CreateRole(ctx context.Context, appID int, name string) (int64, error) {
+	const op = "Permissions.CreateRole"
+
+	log := p.log.With(
+		slog.String("op", op),
+		slog.Int("app_id", appID),
+		slog.String("role", name),
+	)
+
+	log.Info("creating role")
+
+	id, err := p.roleSaver.SaveRole(ctx, appID, name)
+	if err != nil {
+		log.Error("failed to save role", slog.String("error", err.Error()))
+
+		return 0, fmt.Errorf("%s: %w", op, mapStorageErr(err))
+	}
+
+	log.Info("role created", slog.Int64("role_id", id))
+
+	return id, nil
+}
+
+// DeleteRole deletes role of the app and unassigns it from all users.
+func (p *Permissions) DeleteRole(ctx context.Context, appID int, roleID int64) error {
+	const op = "Permissions.DeleteRole"
+
+	log := p.log.With(
+		slog.String("op", op),
+		slog.Int("app_id", appID),
+		slog.Int64("role_id", roleID),
+	)
+
+	if err := p.roleSaver.DeleteRole(ctx, appID, roleID); err != nil {
+		log.Error("failed to delete role", slog.String("error", err.Error()))
+
+		return fmt.Errorf("%s: %w", op, mapStorageErr(err))
+	}
+
+	log.Info("role deleted")
+
+	return nil
+}
+
+// Roles returns roles of the app.
+func (p *Permissions) Roles(ctx context.Context, appID int) ([]models.Role, error) {
+	const op = "Permissions.Roles"
+
+	roles, err := p.roleProvider.Roles(ctx, appID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return roles, nil
+}
+
+// CreatePermission creates permission with given name in the app.
+func (p *Permissions) CreatePermission(ctx context.Context, appID int, name string) (int64, error) {
+	const op = "Permissions.CreatePermission"
+
+	log := p.log.With(
+		slog.String("op", op),
+		slog.Int("app_id", appID),
+		slog.String("permission", name),
+	)
+
+	log.Info("creating permission")
+
+	id, err := p.roleSaver.SavePermission(ctx, appID, name)
+	if err != nil {
+		log.Error("failed to save permission", slog.String("error", err.Error()))
+
+		return 0, fmt.Errorf("%s: %w", op, mapStorageErr(err))
+	}
+
+	log.Info("permission created", slog.Int64("permission_id", id))
+
+	return id, nil
+}
+
+// DeletePermission deletes permission of the app and revokes it from all roles.
+func (p *Permissions) DeletePermission(ctx context.Context, appID int, permissionID int64) error {
+	const op = "Permissions.DeletePermission"
+
+	log := p.log.With(
+		slog.String("op", op),
+		slog.Int("app_id", appID),
+		slog.Int64("permission_id", permissionID),
+	)
+
+	if err := p.roleSaver.DeletePermission(ctx, appID, permissionID); err != nil {
+		log.Error("failed to delete permission", slog.String("error", err.Error()))
+
+		return fmt.Errorf("%s: %w", op, mapStorageErr(err))
+	}
+
+	log.Info("permission deleted")
+
+	return nil
+}
+
+// Permissions returns permissions of the app.
+func (p *Permissions) Permissions(ctx context.Context, appID int) ([]models.Permission, error) {
+	const op = "Permissions.Permissions"
+
+	permissions, err := p.roleProvider.Permissions(ctx, appID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return permissions, nil
+}
+
+// GrantPermission grants permission to role of the same app.
+func (p *Permissions) GrantPermission(ctx context.Context, appID int, roleID, permissionID int64) error {
+	const op = "Permissions.GrantPermission"
+
+	log := p.log.With(
+		slog.String("op", op),
+		slog.Int("app_id", appID),
+		slog.Int64("role_id", roleID),
+		slog.Int64("permission_id", permissionID),
+	)
+
+	if err := p.roleSaver.GrantPermission(ctx, appID, roleID, permissionID); err != nil {
+		log.Error("failed to grant permission", slog.String("error", err.Error()))
+
+		return fmt.Errorf("%s: %w", op, mapStorageErr(err))
+	}
+
+	log.Info("permission granted")
+
+	return nil
+}
+
+// RevokePermission revokes permission from role of the same app.
+func (p *Permissions) RevokePermission(ctx context.Context, appID int, roleID, permissionID int64) error {
+	const op = "Permissions.RevokePermission"
+
+	log := p.log.With(
+		slog.String("op", op),
+		slog.Int("app_id", appID),
+		slog.Int64("role_id", roleID),
+		slog.Int64("permission_id", permissionID),
+	)
+
+	if err := p.roleSaver.RevokePermission(ctx, appID, roleID, permissionID); err != nil {
+		log.Error("failed to revoke permission", slog.String("error", err.Error()))
+
+		return fmt.Errorf("%s: %w", op, mapStorageErr(err))
+	}
+
+	log.Info("permission revoked")
+
+	return nil
+}
+
+// AssignRole assigns role of the app to user.
+func (p *Permissions) AssignRole(ctx context.Context, appID int, userID, roleID int64) error {
+	const op = "Permissions.AssignRole"
+
+	log := p.log.With(
+		slog.String("op", op),
+		slog.Int("app_id", appID),
+		slog.Int64("user_id", userID),
+		slog.Int64("role_id", roleID),
+	)
+
+	if err := p.roleSaver.AssignRole(ctx, appID, userID, roleID); err != nil {
+		log.Error("failed to assign role", slog.String("error", err.Error()))
+
+		return fmt.Errorf("%s: %w", op, mapStorageErr(err))
+	}
+
+	log.Info("role assigned")
+
+	return nil
+}
+
+// UnassignRole removes role of the app from user.
+func (p *Permissions) UnassignRole(ctx context.Context, appID int, userID, roleID int64) error {
+	const op = "Permissions.UnassignRole"
+
+	log := p.log.With(
+		slog.String("op", op),
+		slog.Int("app_id", appID),
+		slog.Int64("user_id", userID),
+		slog.Int64("role_id", roleID),
+	)
+
+	if err := p.roleSaver.UnassignRole(ctx, appID, userID, roleID); err != nil {
+		log.Error("failed to unassign role", slog.String("error", err.Error()))
+
+		return fmt.Errorf("%s: %w", op, mapStorageErr(err))
+	}
+
+	log.Info("role unassigned")
+
+	return nil
+}
+
+// UserAccess returns roles assigned to user in the app
+// and the effective permissions granted through them.
+func (p *Permissions) UserAccess(ctx context.Context, appID int, userID int64) ([]models.Role, []string, error) {
+	const op = "Permissions.UserAccess"
+
+	roles, err := p.roleProvider.UserRoles(ctx, userID, appID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	permissions, err := p.roleProvider.UserPermissions(ctx, userID, appID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return roles, permissions, nil
+}
+
+// CheckPermission reports whether user holds permission in the app.
+func (p *Permissions) CheckPermission(ctx context.Context, appID int, userID int64, permission string) (bool, error) {
+	const op = "Permissions.CheckPermission"
+
+	log := p.log.With(
+		slog.String("op", op),
+		slog.Int("app_id", appID),
+		slog.Int64("user_id", userID),
+		slog.String("permission", permission),
+	)
+
+	allowed, err := p.roleProvider.HasPermission(ctx, userID, appID, permission)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("checked permission", slog.Bool("allowed", allowed))
+
+	return allowed, nil
+}
+
+// IsAppAdmin reports whether user holds the admin role in the app.
+func (p *Permissions) IsAppAdmin(ctx context.Context, appID int, userID int64) (bool, error) {
+	const op = "Permissions.IsAppAdmin"
+
+	isAdmin, err := p.roleProvider.HasRole(ctx, userID, appID, AdminRole)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return isAdmin, nil
+}
+
+func mapStorageErr(err error) error {
+	switch {
+	case errors.Is(err, storage.ErrAppNotFound):
+		return ErrAppNotFound
+	case errors.Is(err, storage.ErrUserNotFound):
+		return ErrUserNotFound
+	case errors.Is(err, storage.ErrRoleExists):
+		return ErrRoleExists
+	case errors.Is(err, storage.ErrRoleNotFound):
+		return ErrRoleNotFound
+	case errors.Is(err, storage.ErrPermissionExists):
+		return ErrPermissionExists
+	case errors.Is(err, storage.ErrPermissionNotFound):
+		return ErrPermissionNotFound
+	}
+
+	return err
+}
@@ -0,0 +1,388 @@
+package policies
+
+import (
+	"SSO/internal/domain/models"
+	"SSO/internal/lib/policy"
+	"SSO/internal/storage"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	DefaultDryRunLimit = 100
+	MaxDryRunLimit     = 1000
+)
+
+type Policies struct {
+	log            *slog.Logger
+	policySaver    PolicySaver
+	policyProvider PolicyProvider
+	usrProvider    UserProvider
+	roleProvider   RoleProvider
+}
+
+type PolicySaver interface {
+	SavePolicy(ctx context.Context, p models.Policy) (int64, error)
+	UpdatePolicy(ctx context.Context, p models.Policy) error
+	DeletePolicy(ctx context.Context, appID int, policyID int64) error
+	SaveAuthorizationRequest(ctx context.Context, r models.AuthorizationRequest) (int64, error)
+}
+
+type PolicyProvider interface {
+	Policies(ctx context.Context, appID int) ([]models.Policy, error)
+	AuthorizationRequests(ctx context.Context, appID int, limit int) ([]models.AuthorizationRequest, error)
+}
+
+type UserProvider interface {
+	UserByID(ctx context.Context, userID int64) (models.User, error)
+}
+
+type RoleProvider interface {
+	UserRoles(ctx context.Context, userID int64, appID int) ([]models.Role, error)
+}
+
+// DryRunResult is the decision of a recorded request under draft policies.
+type DryRunResult struct {
+	Request  models.AuthorizationRequest
+	Decision policy.Decision
+}
+
+// DryRunReport summarises replaying recorded requests against draft policies.
+type DryRunReport struct {
+	Evaluated int
+	// Changed holds requests whose decision differs from the recorded one.
+	Changed []DryRunResult
+}
+
+var (
+	ErrAppNotFound    = errors.New("app not found")
+	ErrUserNotFound   = errors.New("user not found")
+	ErrPolicyExists   = errors.New("policy already exists")
+	ErrPolicyNotFound = errors.New("policy not found")
+	ErrInvalidPolicy  = policy.ErrInvalidPolicy
+)
+
+// New returns a new instance of Policies service.
+func New(
+	log *slog.Logger,
+	policySaver PolicySaver,
+	policyProvider PolicyProvider,
+	userProvider UserProvider,
+	roleProvider RoleProvider,
+) *Policies {
+	return &Policies{
+		log:            log,
+		policySaver:    policySaver,
+		policyProvider: policyProvider,
+		usrProvider:    userProvider,
+		roleProvider:   roleProvider,
+	}
+}
+
+// CreatePolicy validates and saves policy of the app.
+func (p *Policies) CreatePolicy(ctx context.Context, pol models.Policy) (int64, error) {
+	const op = "Policies.CreatePolicy"
+
+	log := p.log.With(
+		slog.String("op", op),
+		slog.Int("app_id", pol.AppID),
+		slog.String("policy", pol.Name),
+	)
+
+	if err := policy.Validate(pol); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	id, err := p.policySaver.SavePolicy(ctx, pol)
+	if err != nil {
+		log.Error("failed to save policy", slog.String("error", err.Error()))
+
+		return 0, fmt.Errorf("%s: %w", op, mapStorageErr(err))
+	}
+
+	log.Info("policy created", slog.Int64("policy_id", id))
+
+	return id, nil
+}
+
+// UpdatePolicy validates and replaces policy of the app.
+func (p *Policies) UpdatePolicy(ctx context.Context, pol models.Policy) error {
+	const op = "Policies.UpdatePolicy"
+
+	log := p.log.With(
+		slog.String("op", op),
+		slog.Int("app_id", pol.AppID),
+		slog.Int64("policy_id", pol.ID),
+	)
+
+	if err := policy.Validate(pol); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := p.policySaver.UpdatePolicy(ctx, pol); err != nil {
+		log.Error("failed to update policy", slog.String("error", err.Error()))
+
+		return fmt.Errorf("%s: %w", op, mapStorageErr(err))
+	}
+
+	log.Info("policy updated")
+
+	return nil
+}
+
+// DeletePolicy deletes policy of the app.
+func (p *Policies) DeletePolicy(ctx context.Context, appID int, policyID int64) error {
+	const op = "Policies.DeletePolicy"
+
+	log := p.log.With(
+		slog.String("op", op),
+		slog.Int("app_id", appID),
+		slog.Int64("policy_id", policyID),
+	)
+
+	if err := p.policySaver.DeletePolicy(ctx, appID, policyID); err != nil {
+		log.Error("failed to delete policy", slog.String("error", err.Error()))
+
+		return fmt.Errorf("%s: %w", op, mapStorageErr(err))
+	}
+
+	log.Info("policy deleted")
+
+	return nil
+}
+
+// Policies returns policies of the app.
+func (p *Policies) Policies(ctx context.Context, appID int) ([]models.Policy, error) {
+	const op = "Policies.Policies"
+
+	policies, err := p.policyProvider.Policies(ctx, appID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return policies, nil
+}
+
+// Authorize decides whether user may perform action on resource in the app.
+//
+// Subject attributes are taken from the user profile and roles in the app,
+// attributes describe the request environment. Every decision is recorded
+// so that draft policies can be tested with DryRun later.
+func (p *Policies) Authorize(
+	ctx context.Context,
+	appID int,
+	userID int64,
+	action string,
+	resource models.Resource,
+	attributes map[string]string,
+) (policy.Decision, error) {
+	const op = "Policies.Authorize"
+
+	log := p.log.With(
+		slog.String("op", op),
+		slog.Int("app_id", appID),
+		slog.Int64("user_id", userID),
+		slog.String("action", action),
+		slog.String("resource", resource.Type),
+	)
+
+	req, err := p.request(ctx, appID, userID, action, resource, attributes)
+	if err != nil {
+		return policy.Decision{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	policies, err := p.policyProvider.Policies(ctx, appID)
+	if err != nil {
+		return policy.Decision{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	decision := policy.Evaluate(policies, req)
+
+	_, err = p.policySaver.SaveAuthorizationRequest(ctx, models.AuthorizationRequest{
+		AppID:        appID,
+		UserID:       userID,
+		Action:       action,
+		ResourceType: resource.Type,
+		Attributes:   req.Attributes,
+		Allowed:      decision.Allowed,
+	})
+	if err != nil {
+		if errors.Is(err, storage.ErrAppNotFound) {
+			return policy.Decision{}, fmt.Errorf("%s: %w", op, ErrAppNotFound)
+		}
+
+		// The decision is still valid, losing it for dry runs is not fatal.
+		log.Error("failed to record authorization request", slog.String("error", err.Error()))
+	}
+
+	log.Info("authorization decided", slog.Bool("allowed", decision.Allowed), slog.String("reason", decision.Reason))
+
+	return decision, nil
+}
+
+// DryRun replays the latest recorded requests of the app against its
+// policies with drafts applied: drafts with id replace existing policies,
+// drafts without id are added, policies listed in removed are dropped.
+func (p *Policies) DryRun(
+	ctx context.Context,
+	appID int,
+	drafts []models.Policy,
+	removed []int64,
+	limit int,
+) (DryRunReport, error) {
+	const op = "Policies.DryRun"
+
+	if limit <= 0 {
+		limit = DefaultDryRunLimit
+	}
+	limit = min(limit, MaxDryRunLimit)
+
+	for _, d := range drafts {
+		if err := policy.Validate(d); err != nil {
+			return DryRunReport{}, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	current, err := p.policyProvider.Policies(ctx, appID)
+	if err != nil {
+		return DryRunReport{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	policies, err := applyDrafts(current, drafts, removed)
+	if err != nil {
+		return DryRunReport{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	requests, err := p.policyProvider.AuthorizationRequests(ctx, appID, limit)
+	if err != nil {
+		return DryRunReport{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	report := DryRunReport{Evaluated: len(requests)}
+	for _, r := range requests {
+		decision := policy.Evaluate(policies, policy.Request{
+			Action:       r.Action,
+			ResourceType: r.ResourceType,
+			Attributes:   r.Attributes,
+		})
+
+		if decision.Allowed != r.Allowed {
+			report.Changed = append(report.Changed, DryRunResult{Request: r, Decision: decision})
+		}
+	}
+
+	p.log.Info("policies dry run finished",
+		slog.String("op", op),
+		slog.Int("app_id", appID),
+		slog.Int("evaluated", report.Evaluated),
+		slog.Int("changed", len(report.Changed)),
+	)
+
+	return report, nil
+}
+
+// request builds evaluation input from the user profile, resource and environment attributes.
+func (p *Policies) request(
+	ctx context.Context,
+	appID int,
+	userID int64,
+	action string,
+	resource models.Resource,
+	attributes map[string]string,
+) (policy.Request, error) {
+	user, err := p.usrProvider.UserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, storage.ErrUserNotFound) {
+			return policy.Request{}, ErrUserNotFound
+		}
+
+		return policy.Request{}, err
+	}
+
+	roles, err := p.roleProvider.UserRoles(ctx, userID, appID)
+	if err != nil {
+		return policy.Request{}, err
+	}
+
+	roleNames := make([]string, 0, len(roles))
+	for _, role := range roles {
+		roleNames = append(roleNames, role.Name)
+	}
+
+	attrs := map[string]string{
+		policy.SubjectPrefix + "id":            strconv.FormatInt(user.ID, 10),
+		policy.SubjectPrefix + "email":         user.Email,
+		policy.SubjectPrefix + "username":      user.Username,
+		policy.SubjectPrefix + "location":      user.Location,
+		policy.SubjectPrefix + "sex":           user.Sex,
+		policy.SubjectPrefix + "date_of_birth": user.DateOfBirth,
+		policy.SubjectPrefix + "roles":         strings.Join(roleNames, policy.ListSeparator),
+		policy.ResourcePrefix + "type":         resource.Type,
+		policy.ResourcePrefix + "id":           resource.ID,
+	}
+	for k, v := range resource.Attributes {
+		attrs[policy.ResourcePrefix+k] = v
+	}
+	for k, v := range attributes {
+		attrs[policy.EnvPrefix+k] = v
+	}
+
+	return policy.Request{
+		Action:       action,
+		ResourceType: resource.Type,
+		Attributes:   attrs,
+	}, nil
+}
+
+func applyDrafts(current, drafts []models.Policy, removed []int64) ([]models.Policy, error) {
+	byID := make(map[int64]int, len(current))
+	for i, c := range current {
+		byID[c.ID] = i
+	}
+
+	policies := append([]models.Policy(nil), current...)
+	for _, d := range drafts {
+		if d.ID == 0 {
+			policies = append(policies, d)
+			continue
+		}
+
+		i, ok := byID[d.ID]
+		if !ok {
+			return nil, ErrPolicyNotFound
+		}
+		policies[i] = d
+	}
+
+	if len(removed) == 0 {
+		return policies, nil
+	}
+
+	res := policies[:0]
+	for _, pol := range policies {
+		if pol.ID != 0 && slices.Contains(removed, pol.ID) {
+			continue
+		}
+		res = append(res, pol)
+	}
+
+	return res, nil
+}
+
+func mapStorageErr(err error) error {
+	switch {
+	case errors.Is(err, storage.ErrAppNotFound):
+		return ErrAppNotFound
+	case errors.Is(err, storage.ErrPolicyExists):
+		return ErrPolicyExists
+	case errors.Is(err, storage.ErrPolicyNotFound):
+		return ErrPolicyNotFound
+	}
+
+	return err
+}
@@ -0,0 +1,433 @@
+package saml
+
+import (
+	"SSO/internal/domain/models"
+	"SSO/internal/lib/jwt"
+	"SSO/internal/lib/samlidp"
+	"SSO/internal/storage"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// SAML is the SAML identity provider of service providers registered for
+// apps. Users sign in to a service provider with the login of its app.
+type SAML struct {
+	log            *slog.Logger
+	idp            *samlidp.IdentityProvider
+	spSaver        ServiceProviderSaver
+	spProvider     ServiceProviderProvider
+	appProvider    AppProvider
+	userProvider   UserProvider
+	accessProvider AccessProvider
+	assertionTTL   time.Duration
+}
+
+type ServiceProviderSaver interface {
+	SaveServiceProvider(ctx context.Context, sp models.ServiceProvider) (models.ServiceProvider, error)
+	DeleteServiceProvider(ctx context.Context, appID int) error
+}
+
+type ServiceProviderProvider interface {
+	ServiceProvider(ctx context.Context, appID int) (models.ServiceProvider, error)
+	ServiceProviderByEntityID(ctx context.Context, entityID string) (models.ServiceProvider, error)
+}
+
+type AppProvider interface {
+	App(ctx context.Context, appID int) (models.App, error)
+}
+
+type UserProvider interface {
+	UserByID(ctx context.Context, userID int64) (models.User, error)
+}
+
+type AccessProvider interface {
+	UserRoles(ctx context.Context, userID int64, appID int) ([]models.Role, error)
+	UserGroups(ctx context.Context, userID int64, appID int) ([]models.Group, error)
+}
+
+// Request is an AuthnRequest of the registered service provider.
+type Request struct {
+	AuthnRequest samlidp.AuthnRequest
+	Provider     models.ServiceProvider
+	App          models.App
+	// ACSURL is the assertion consumer service the response is posted to.
+	ACSURL string
+}
+
+// Response is a response to AuthnRequest to be posted to ACSURL.
+type Response struct {
+	ACSURL string
+	// SAMLResponse is the base64 encoded response, the SAMLResponse form field.
+	SAMLResponse string
+}
+
+var (
+	ErrProviderNotFound = errors.New("service provider not found")
+	ErrProviderExists   = errors.New("entity ID is registered for another app")
+	ErrInvalidProvider  = errors.New("invalid service provider")
+	ErrInvalidRequest   = errors.New("invalid SAML request")
+	ErrUnsupported      = errors.New("unsupported SAML binding")
+	ErrInvalidACSURL    = errors.New("assertion consumer service is not registered")
+	ErrAppNotFound      = errors.New("app not found")
+	ErrAppDisabled      = errors.New("app is disabled")
+	ErrUserNotFound     = errors.New("user not found")
+	ErrCallerNotAllowed = errors.New("user must sign in themselves")
+	ErrWrongApp         = errors.New("token is issued for another app")
+)
+
+// InvalidProviderError is returned when service provider to save is invalid.
+type InvalidProviderError struct {
+	Reason string
+}
+
+func (e *InvalidProviderError) Error() string {
+	return fmt.Sprintf("invalid service provider: %s", e.Reason)
+}
+
+func (e *InvalidProviderError) Is(target error) bool {
+	return target == ErrInvalidProvider
+}
+
+// New returns a new instance of SAML service. Assertions are valid for
+// assertionTTL.
+func New(
+	log *slog.Logger,
+	idp *samlidp.IdentityProvider,
+	spSaver ServiceProviderSaver,
+	spProvider ServiceProviderProvider,
+	appProvider AppProvider,
+	userProvider UserProvider,
+	accessProvider AccessProvider,
+	assertionTTL time.Duration,
+) *SAML {
+	return &SAML{
+		log:            log,
+		idp:            idp,
+		spSaver:        spSaver,
+		spProvider:     spProvider,
+		appProvider:    appProvider,
+		userProvider:   userProvider,
+		accessProvider: accessProvider,
+		assertionTTL:   assertionTTL,
+	}
+}
+
+// Metadata returns metadata of the identity provider.
+func (s *SAML) Metadata() []byte {
+	return s.idp.Metadata()
+}
+
+// SaveProvider registers SAML service provider of the app or replaces it.
+// Name ID format defaults to the email address.
+func (s *SAML) SaveProvider(ctx context.Context, sp models.ServiceProvider) (models.ServiceProvider, error) {
+	const op = "SAML.SaveProvider"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int("app_id", sp.AppID),
+	)
+
+	if sp.NameIDFormat == "" {
+		sp.NameIDFormat = samlidp.NameIDFormatEmail
+	}
+
+	if err := validateProvider(sp); err != nil {
+		return models.ServiceProvider{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	sp, err := s.spSaver.SaveServiceProvider(ctx, sp)
+	if err != nil {
+		return models.ServiceProvider{}, fmt.Errorf("%s: %w", op, mapStorageErr(err))
+	}
+
+	log.Info("service provider saved", slog.String("entity_id", sp.EntityID))
+
+	return sp, nil
+}
+
+// Provider returns SAML service provider of the app.
+func (s *SAML) Provider(ctx context.Context, appID int) (models.ServiceProvider, error) {
+	const op = "SAML.Provider"
+
+	sp, err := s.spProvider.ServiceProvider(ctx, appID)
+	if err != nil {
+		return models.ServiceProvider{}, fmt.Errorf("%s: %w", op, mapStorageErr(err))
+	}
+
+	return sp, nil
+}
+
+// DeleteProvider deletes SAML service provider of the app.
+func (s *SAML) DeleteProvider(ctx context.Context, appID int) error {
+	const op = "SAML.DeleteProvider"
+
+	if err := s.spSaver.DeleteServiceProvider(ctx, appID); err != nil {
+		return fmt.Errorf("%s: %w", op, mapStorageErr(err))
+	}
+
+	s.log.Info("service provider deleted", slog.String("op", op), slog.Int("app_id", appID))
+
+	return nil
+}
+
+// Request decodes AuthnRequest received with the binding and resolves the
+// service provider, its app and the assertion consumer service. The user
+// then signs in to the app.
+func (s *SAML) Request(ctx context.Context, samlRequest, binding string) (Request, error) {
+	const op = "SAML.Request"
+
+	req, err := samlidp.ParseRequest(samlRequest, binding)
+	if err != nil {
+		if errors.Is(err, samlidp.ErrUnsupportedBinding) {
+			return Request{}, fmt.Errorf("%s: %w: %w", op, ErrUnsupported, err)
+		}
+
+		return Request{}, fmt.Errorf("%s: %w: %w", op, ErrInvalidRequest, err)
+	}
+
+	sp, err := s.spProvider.ServiceProviderByEntityID(ctx, req.Issuer)
+	if err != nil {
+		return Request{}, fmt.Errorf("%s: %w", op, mapStorageErr(err))
+	}
+
+	acsURL := sp.ACSURLs[0]
+	if req.AssertionConsumerServiceURL != "" {
+		if !slices.Contains(sp.ACSURLs, req.AssertionConsumerServiceURL) {
+			s.log.Warn(
+				"unregistered assertion consumer service requested",
+				slog.String("op", op),
+				slog.String("entity_id", sp.EntityID),
+				slog.String("acs_url", req.AssertionConsumerServiceURL),
+			)
+
+			return Request{}, fmt.Errorf("%s: %w", op, ErrInvalidACSURL)
+		}
+
+		acsURL = req.AssertionConsumerServiceURL
+	}
+
+	app, err := s.appProvider.App(ctx, sp.AppID)
+	if err != nil {
+		return Request{}, fmt.Errorf("%s: %w", op, mapStorageErr(err))
+	}
+
+	if app.Disabled {
+		return Request{}, fmt.Errorf("%s: %w", op, ErrAppDisabled)
+	}
+
+	return Request{AuthnRequest: req, Provider: sp, App: app, ACSURL: acsURL}, nil
+}
+
+// Respond returns signed response to AuthnRequest for the caller, who
+// must have signed in to the app of the service provider themselves.
+// Attributes of the assertion are user fields mapped by the provider.
+func (s *SAML) Respond(ctx context.Context, caller jwt.Claims, samlRequest, binding string) (Response, error) {
+	const op = "SAML.Respond"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", caller.UserID),
+	)
+
+	if caller.Impersonated() || caller.KeyID != 0 || caller.ServiceAccount {
+		return Response{}, fmt.Errorf("%s: %w", op, ErrCallerNotAllowed)
+	}
+
+	req, err := s.Request(ctx, samlRequest, binding)
+	if err != nil {
+		return Response{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log = log.With(slog.String("entity_id", req.Provider.EntityID))
+
+	if caller.AppID != req.Provider.AppID {
+		return Response{}, fmt.Errorf("%s: %w", op, ErrWrongApp)
+	}
+
+	user, err := s.userProvider.UserByID(ctx, caller.UserID)
+	if err != nil {
+		return Response{}, fmt.Errorf("%s: %w", op, mapStorageErr(err))
+	}
+
+	attributes, err := s.attributes(ctx, user, req.Provider)
+	if err != nil {
+		log.Error("failed to get user attributes", slog.String("error", err.Error()))
+
+		return Response{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	now := time.Now()
+	authnInstant := caller.IssuedAt
+	if authnInstant.IsZero() {
+		authnInstant = now
+	}
+
+	raw, err := s.idp.Response(samlidp.Assertion{
+		InResponseTo: req.AuthnRequest.ID,
+		Audience:     req.Provider.EntityID,
+		Recipient:    req.ACSURL,
+		NameID:       nameID(user, req.Provider.NameIDFormat),
+		NameIDFormat: req.Provider.NameIDFormat,
+		Attributes:   attributes,
+		AuthnInstant: authnInstant,
+		IssueInstant: now,
+		TTL:          s.assertionTTL,
+	})
+	if err != nil {
+		log.Error("failed to sign response", slog.String("error", err.Error()))
+
+		return Response{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("SAML assertion issued")
+
+	return Response{
+		ACSURL:       req.ACSURL,
+		SAMLResponse: base64.StdEncoding.EncodeToString(raw),
+	}, nil
+}
+
+// attributes returns SAML attributes of the user mapped by the provider,
+// sorted by name.
+func (s *SAML) attributes(ctx context.Context, user models.User, sp models.ServiceProvider) ([]samlidp.Attribute, error) {
+	names := make([]string, 0, len(sp.Attributes))
+	for name := range sp.Attributes {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	attributes := make([]samlidp.Attribute, 0, len(names))
+	for _, name := range names {
+		var values []string
+
+		switch sp.Attributes[name] {
+		case models.FieldID:
+			values = []string{strconv.FormatInt(user.ID, 10)}
+		case models.FieldEmail:
+			values = []string{user.Email}
+		case models.FieldUsername:
+			values = []string{user.Username}
+		case models.FieldSex:
+			values = []string{user.Sex}
+		case models.FieldLocation:
+			values = []string{user.Location}
+		case models.FieldDateOfBirth:
+			values = []string{user.DateOfBirth}
+		case models.FieldRoles:
+			roles, err := s.accessProvider.UserRoles(ctx, user.ID, sp.AppID)
+			if err != nil {
+				return nil, err
+			}
+			for _, role := range roles {
+				values = append(values, role.Name)
+			}
+		case models.FieldGroups:
+			groups, err := s.accessProvider.UserGroups(ctx, user.ID, sp.AppID)
+			if err != nil {
+				return nil, err
+			}
+			for _, group := range groups {
+				values = append(values, group.Name)
+			}
+		}
+
+		// Empty profile fields are left out.
+		values = slices.DeleteFunc(values, func(v string) bool { return v == "" })
+		if len(values) > 0 {
+			attributes = append(attributes, samlidp.Attribute{Name: name, Values: values})
+		}
+	}
+
+	return attributes, nil
+}
+
+// nameID returns name identifier of the user in the format.
+func nameID(user models.User, format string) string {
+	if format == samlidp.NameIDFormatEmail {
+		return user.Email
+	}
+
+	return strconv.FormatInt(user.ID, 10)
+}
+
+// validateProvider checks entity ID, assertion consumer services, name ID
+// format and attribute mapping of the provider.
+func validateProvider(sp models.ServiceProvider) error {
+	if sp.EntityID == "" {
+		return &InvalidProviderError{Reason: "entity ID is required"}
+	}
+
+	if len(sp.ACSURLs) == 0 {
+		return &InvalidProviderError{Reason: "assertion consumer service is required"}
+	}
+
+	for _, acsURL := range sp.ACSURLs {
+		if err := checkACSURL(acsURL); err != nil {
+			return &InvalidProviderError{Reason: fmt.Sprintf("assertion consumer service %q %s", acsURL, err)}
+		}
+	}
+
+	if !slices.Contains(samlidp.NameIDFormats, sp.NameIDFormat) {
+		return &InvalidProviderError{Reason: fmt.Sprintf("unsupported name ID format %q", sp.NameIDFormat)}
+	}
+
+	for name, field := range sp.Attributes {
+		if name == "" {
+			return &InvalidProviderError{Reason: "attribute name is required"}
+		}
+
+		if !slices.Contains(models.SAMLFields, field) {
+			return &InvalidProviderError{Reason: fmt.Sprintf("unknown user field %q", field)}
+		}
+	}
+
+	return nil
+}
+
+// checkACSURL checks that responses are posted over https, or http to
+// loopback addresses for local development.
+func checkACSURL(acsURL string) error {
+	u, err := url.Parse(acsURL)
+	if err != nil || !u.IsAbs() || u.Host == "" {
+		return errors.New("must be an absolute URL")
+	}
+
+	if u.Fragment != "" {
+		return errors.New("must not have fragment")
+	}
+
+	switch u.Scheme {
+	case "https":
+		return nil
+	case "http":
+		host := u.Hostname()
+		if ip := net.ParseIP(host); host == "localhost" || (ip != nil && ip.IsLoopback()) {
+			return nil
+		}
+	}
+
+	return errors.New("must use https")
+}
+
+func mapStorageErr(err error) error {
+	switch {
+	case errors.Is(err, storage.ErrServiceProviderNotFound):
+		return ErrProviderNotFound
+	case errors.Is(err, storage.ErrServiceProviderExists):
+		return ErrProviderExists
+	case errors.Is(err, storage.ErrAppNotFound):
+		return ErrAppNotFound
+	case errors.Is(err, storage.ErrUserNotFound):
+		return ErrUserNotFound
+	}
+
+	return err
+}
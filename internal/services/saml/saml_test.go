@@ -0,0 +1,153 @@
+package saml
+
+import (
+	"SSO/internal/domain/models"
+	"SSO/internal/lib/jwt"
+	"SSO/internal/lib/samlidp"
+	"SSO/internal/storage"
+	"context"
+	"encoding/base64"
+	"encoding/xml"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateProvider(t *testing.T) {
+	valid := models.ServiceProvider{
+		EntityID:     "https://sp.example/metadata",
+		ACSURLs:      []string{"https://sp.example/acs", "http://localhost:8080/acs"},
+		NameIDFormat: samlidp.NameIDFormatEmail,
+		Attributes:   map[string]string{"mail": models.FieldEmail, "memberOf": models.FieldGroups},
+	}
+	require.NoError(t, validateProvider(valid))
+
+	tests := map[string]func(sp *models.ServiceProvider){
+		"no entity ID":       func(sp *models.ServiceProvider) { sp.EntityID = "" },
+		"no ACS":             func(sp *models.ServiceProvider) { sp.ACSURLs = nil },
+		"plain http":         func(sp *models.ServiceProvider) { sp.ACSURLs = []string{"http://sp.example/acs"} },
+		"relative ACS":       func(sp *models.ServiceProvider) { sp.ACSURLs = []string{"/acs"} },
+		"unknown format":     func(sp *models.ServiceProvider) { sp.NameIDFormat = "urn:other" },
+		"unknown user field": func(sp *models.ServiceProvider) { sp.Attributes = map[string]string{"pw": "pass_hash"} },
+	}
+	for name, modify := range tests {
+		t.Run(name, func(t *testing.T) {
+			sp := valid
+			modify(&sp)
+			assert.ErrorIs(t, validateProvider(sp), ErrInvalidProvider)
+		})
+	}
+}
+
+// fakeStorage serves the single service provider, its app and user.
+type fakeStorage struct {
+	sp   models.ServiceProvider
+	user models.User
+}
+
+func (f *fakeStorage) ServiceProvider(_ context.Context, appID int) (models.ServiceProvider, error) {
+	return f.sp, nil
+}
+
+func (f *fakeStorage) ServiceProviderByEntityID(_ context.Context, entityID string) (models.ServiceProvider, error) {
+	if entityID != f.sp.EntityID {
+		return models.ServiceProvider{}, storage.ErrServiceProviderNotFound
+	}
+
+	return f.sp, nil
+}
+
+func (f *fakeStorage) App(_ context.Context, appID int) (models.App, error) {
+	return models.App{ID: appID}, nil
+}
+
+func (f *fakeStorage) UserByID(_ context.Context, userID int64) (models.User, error) {
+	return f.user, nil
+}
+
+func (f *fakeStorage) UserRoles(_ context.Context, userID int64, appID int) ([]models.Role, error) {
+	return []models.Role{{Name: "admin"}, {Name: "editor"}}, nil
+}
+
+func (f *fakeStorage) UserGroups(_ context.Context, userID int64, appID int) ([]models.Group, error) {
+	return nil, nil
+}
+
+func TestRespond(t *testing.T) {
+	key, cert, err := samlidp.GenerateKeyPair("idp")
+	require.NoError(t, err)
+
+	f := &fakeStorage{
+		sp: models.ServiceProvider{
+			AppID:        3,
+			EntityID:     "https://sp.example/metadata",
+			ACSURLs:      []string{"https://sp.example/acs", "https://sp.example/acs2"},
+			NameIDFormat: samlidp.NameIDFormatEmail,
+			Attributes: map[string]string{
+				"mail":     models.FieldEmail,
+				"role":     models.FieldRoles,
+				"memberOf": models.FieldGroups,
+				"city":     models.FieldLocation,
+			},
+		},
+		user: models.User{ID: 7, Email: "jane@corp.example", Username: "jane"},
+	}
+
+	s := New(
+		slog.New(slog.NewTextHandler(io.Discard, nil)),
+		&samlidp.IdentityProvider{EntityID: "https://sso.example", SSOURL: "https://sso.example/sso", Key: key, Certificate: cert},
+		nil,
+		f,
+		f,
+		f,
+		f,
+		5*time.Minute,
+	)
+
+	request := func(issuer, acsURL string) string {
+		return base64.StdEncoding.EncodeToString([]byte(`<samlp:AuthnRequest
+			xmlns:samlp="urn:oasis:names:tc:SAML:2.0:protocol" xmlns:saml="urn:oasis:names:tc:SAML:2.0:assertion"
+			ID="_req1" Version="2.0" AssertionConsumerServiceURL="` + acsURL + `">
+			<saml:Issuer>` + issuer + `</saml:Issuer></samlp:AuthnRequest>`))
+	}
+
+	ctx := context.Background()
+	caller := jwt.Claims{UserID: 7, AppID: 3}
+
+	resp, err := s.Respond(ctx, caller, request(f.sp.EntityID, "https://sp.example/acs2"), samlidp.BindingPOST)
+	require.NoError(t, err)
+	assert.Equal(t, "https://sp.example/acs2", resp.ACSURL)
+
+	raw, err := base64.StdEncoding.DecodeString(resp.SAMLResponse)
+	require.NoError(t, err)
+
+	var response struct {
+		NameID     string `xml:"Assertion>Subject>NameID"`
+		Attributes []struct {
+			Name   string   `xml:"Name,attr"`
+			Values []string `xml:"AttributeValue"`
+		} `xml:"Assertion>AttributeStatement>Attribute"`
+	}
+	require.NoError(t, xml.Unmarshal(raw, &response))
+	assert.Equal(t, "jane@corp.example", response.NameID)
+	// Empty fields are left out, attributes are sorted by name.
+	require.Len(t, response.Attributes, 2)
+	assert.Equal(t, "mail", response.Attributes[0].Name)
+	assert.Equal(t, []string{"admin", "editor"}, response.Attributes[1].Values)
+
+	_, err = s.Respond(ctx, caller, request(f.sp.EntityID, "https://evil.example/acs"), samlidp.BindingPOST)
+	assert.ErrorIs(t, err, ErrInvalidACSURL)
+
+	_, err = s.Respond(ctx, caller, request("https://other.example", ""), samlidp.BindingPOST)
+	assert.ErrorIs(t, err, ErrProviderNotFound)
+
+	_, err = s.Respond(ctx, jwt.Claims{UserID: 7, AppID: 4}, request(f.sp.EntityID, ""), samlidp.BindingPOST)
+	assert.ErrorIs(t, err, ErrWrongApp)
+
+	_, err = s.Respond(ctx, jwt.Claims{UserID: 7, AppID: 3, KeyID: 1}, request(f.sp.EntityID, ""), samlidp.BindingPOST)
+	assert.ErrorIs(t, err, ErrCallerNotAllowed)
+}
@@ -0,0 +1,418 @@
+package serviceaccounts
+
+import (
+	"SSO/internal/domain/models"
+	"SSO/internal/lib/jwt"
+	"SSO/internal/lib/secrets"
+	"SSO/internal/storage"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+)
+
+type ServiceAccounts struct {
+	log             *slog.Logger
+	accSaver        AccountSaver
+	accProvider     AccountProvider
+	keys            KeyCreator
+	issuer          TokenIssuer
+	audience        string
+	maxAssertionTTL time.Duration
+}
+
+type AccountSaver interface {
+	SaveServiceAccount(ctx context.Context, sa models.ServiceAccount, email, username string) (models.ServiceAccount, error)
+	SetServiceAccountDisabled(ctx context.Context, id int64, disabled bool) error
+	DeleteServiceAccount(ctx context.Context, id int64) error
+	SaveServiceAccountKey(ctx context.Context, key models.ServiceAccountKey) (models.ServiceAccountKey, error)
+	DeleteServiceAccountKey(ctx context.Context, id, keyID int64) error
+}
+
+type AccountProvider interface {
+	ServiceAccount(ctx context.Context, id int64) (models.ServiceAccount, error)
+	ServiceAccounts(ctx context.Context, appID int, orgID int64) ([]models.ServiceAccount, error)
+	ServiceAccountKeys(ctx context.Context, id int64) ([]models.ServiceAccountKey, error)
+	ServiceAccountKey(ctx context.Context, id int64, kid string) (models.ServiceAccountKey, error)
+}
+
+// KeyCreator creates API keys.
+type KeyCreator interface {
+	CreateFor(
+		ctx context.Context,
+		userID int64,
+		appID int,
+		name string,
+		scopes []string,
+		expiresAt time.Time,
+	) (string, models.APIKey, error)
+}
+
+// TokenIssuer issues access tokens to service accounts.
+type TokenIssuer interface {
+	ServiceAccountToken(ctx context.Context, accountID int64, appID int) (string, error)
+}
+
+var (
+	ErrAccountExists      = errors.New("service account already exists")
+	ErrAccountNotFound    = errors.New("service account not found")
+	ErrKeyExists          = errors.New("service account key already exists")
+	ErrKeyNotFound        = errors.New("service account key not found")
+	ErrInvalidOwner       = errors.New("service account must belong to either an app or an organization")
+	ErrInvalidPublicKey   = errors.New("invalid public key")
+	ErrInvalidExpiry      = errors.New("expiry must be in the future")
+	ErrInvalidAssertion   = errors.New("invalid assertion")
+	ErrAppRequired        = errors.New("app is required for service account of organization")
+	ErrAppNotAllowed      = errors.New("service account belongs to another app")
+	ErrAppNotFound        = errors.New("app not found")
+	ErrOrgNotFound        = errors.New("organization not found")
+	errUnknownKeyOrClient = errors.New("unknown client or key")
+)
+
+// kidSize is the number of random bytes in generated ids of public keys.
+const kidSize = 8
+
+// New returns a new instance of ServiceAccounts service. JWT assertions
+// must be addressed to audience and expire within maxAssertionTTL.
+func New(
+	log *slog.Logger,
+	accSaver AccountSaver,
+	accProvider AccountProvider,
+	keys KeyCreator,
+	issuer TokenIssuer,
+	audience string,
+	maxAssertionTTL time.Duration,
+) *ServiceAccounts {
+	return &ServiceAccounts{
+		log:             log,
+		accSaver:        accSaver,
+		accProvider:     accProvider,
+		keys:            keys,
+		issuer:          issuer,
+		audience:        audience,
+		maxAssertionTTL: maxAssertionTTL,
+	}
+}
+
+// Create creates service account of the app, if appID is not 0, or of the
+// organization. Service account of an organization becomes its member.
+func (s *ServiceAccounts) Create(
+	ctx context.Context,
+	actorID int64,
+	appID int,
+	orgID int64,
+	name string,
+	description string,
+) (models.ServiceAccount, error) {
+	const op = "ServiceAccounts.Create"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("actor_id", actorID),
+		slog.Int("app_id", appID),
+		slog.Int64("organization_id", orgID),
+		slog.String("name", name),
+	)
+
+	if (appID == 0) == (orgID == 0) {
+		return models.ServiceAccount{}, fmt.Errorf("%s: %w", op, ErrInvalidOwner)
+	}
+
+	owner := "app-" + strconv.Itoa(appID)
+	if orgID != 0 {
+		owner = "org-" + strconv.FormatInt(orgID, 10)
+	}
+
+	sa, err := s.accSaver.SaveServiceAccount(ctx, models.ServiceAccount{
+		AppID:          appID,
+		OrganizationID: orgID,
+		Name:           name,
+		Description:    description,
+		CreatedBy:      actorID,
+	}, name+"@"+owner+".service-accounts.invalid", owner+"/"+name)
+	if err != nil {
+		log.Error("failed to save service account", slog.String("error", err.Error()))
+
+		return models.ServiceAccount{}, fmt.Errorf("%s: %w", op, mapStorageErr(err))
+	}
+
+	log.Info("service account created", slog.Int64("service_account_id", sa.ID))
+
+	return sa, nil
+}
+
+// ServiceAccount returns service account by ID.
+func (s *ServiceAccounts) ServiceAccount(ctx context.Context, id int64) (models.ServiceAccount, error) {
+	const op = "ServiceAccounts.ServiceAccount"
+
+	sa, err := s.accProvider.ServiceAccount(ctx, id)
+	if err != nil {
+		return models.ServiceAccount{}, fmt.Errorf("%s: %w", op, mapStorageErr(err))
+	}
+
+	return sa, nil
+}
+
+// ServiceAccounts returns service accounts of the app, if appID is not 0,
+// or of the organization.
+func (s *ServiceAccounts) ServiceAccounts(ctx context.Context, appID int, orgID int64) ([]models.ServiceAccount, error) {
+	const op = "ServiceAccounts.ServiceAccounts"
+
+	if (appID == 0) == (orgID == 0) {
+		return nil, fmt.Errorf("%s: %w", op, ErrInvalidOwner)
+	}
+
+	accounts, err := s.accProvider.ServiceAccounts(ctx, appID, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return accounts, nil
+}
+
+// SetDisabled disables or enables service account. Disabled account can't
+// get tokens and its API keys aren't accepted.
+func (s *ServiceAccounts) SetDisabled(ctx context.Context, id int64, disabled bool) error {
+	const op = "ServiceAccounts.SetDisabled"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("service_account_id", id),
+		slog.Bool("disabled", disabled),
+	)
+
+	if err := s.accSaver.SetServiceAccountDisabled(ctx, id, disabled); err != nil {
+		log.Error("failed to set service account disabled", slog.String("error", err.Error()))
+
+		return fmt.Errorf("%s: %w", op, mapStorageErr(err))
+	}
+
+	log.Info("service account disabled state changed")
+
+	return nil
+}
+
+// Delete deletes service account with its keys, roles and memberships.
+func (s *ServiceAccounts) Delete(ctx context.Context, id int64) error {
+	const op = "ServiceAccounts.Delete"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("service_account_id", id),
+	)
+
+	if err := s.accSaver.DeleteServiceAccount(ctx, id); err != nil {
+		log.Error("failed to delete service account", slog.String("error", err.Error()))
+
+		return fmt.Errorf("%s: %w", op, mapStorageErr(err))
+	}
+
+	log.Info("service account deleted")
+
+	return nil
+}
+
+// AddKey adds public key service account signs JWT assertions with.
+// A random kid is generated if kid is empty. Zero expiresAt adds a key
+// that never expires.
+func (s *ServiceAccounts) AddKey(
+	ctx context.Context,
+	id int64,
+	kid string,
+	publicKey string,
+	expiresAt time.Time,
+) (models.ServiceAccountKey, error) {
+	const op = "ServiceAccounts.AddKey"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("service_account_id", id),
+	)
+
+	if err := jwt.ValidatePublicKey(publicKey); err != nil {
+		return models.ServiceAccountKey{}, fmt.Errorf("%s: %w: %w", op, ErrInvalidPublicKey, err)
+	}
+
+	if !expiresAt.IsZero() && !expiresAt.After(time.Now()) {
+		return models.ServiceAccountKey{}, fmt.Errorf("%s: %w", op, ErrInvalidExpiry)
+	}
+
+	if kid == "" {
+		var err error
+		if kid, err = secrets.Generate(kidSize); err != nil {
+			return models.ServiceAccountKey{}, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	key, err := s.accSaver.SaveServiceAccountKey(ctx, models.ServiceAccountKey{
+		ServiceAccountID: id,
+		KID:              kid,
+		PublicKey:        publicKey,
+		ExpiresAt:        expiresAt,
+	})
+	if err != nil {
+		log.Error("failed to save service account key", slog.String("error", err.Error()))
+
+		return models.ServiceAccountKey{}, fmt.Errorf("%s: %w", op, mapStorageErr(err))
+	}
+
+	log.Info("service account key added", slog.String("kid", kid))
+
+	return key, nil
+}
+
+// Keys returns public keys of service account.
+func (s *ServiceAccounts) Keys(ctx context.Context, id int64) ([]models.ServiceAccountKey, error) {
+	const op = "ServiceAccounts.Keys"
+
+	keys, err := s.accProvider.ServiceAccountKeys(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return keys, nil
+}
+
+// RemoveKey removes public key of service account.
+func (s *ServiceAccounts) RemoveKey(ctx context.Context, id, keyID int64) error {
+	const op = "ServiceAccounts.RemoveKey"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("service_account_id", id),
+		slog.Int64("key_id", keyID),
+	)
+
+	if err := s.accSaver.DeleteServiceAccountKey(ctx, id, keyID); err != nil {
+		log.Error("failed to remove service account key", slog.String("error", err.Error()))
+
+		return fmt.Errorf("%s: %w", op, mapStorageErr(err))
+	}
+
+	log.Info("service account key removed")
+
+	return nil
+}
+
+// CreateAPIKey creates API key of service account. Keys of an app's
+// account are for that app, appID may be 0 for them. Keys of an
+// organization's account need appID of an app the organization has access to.
+func (s *ServiceAccounts) CreateAPIKey(
+	ctx context.Context,
+	id int64,
+	appID int,
+	name string,
+	scopes []string,
+	expiresAt time.Time,
+) (string, models.APIKey, error) {
+	const op = "ServiceAccounts.CreateAPIKey"
+
+	sa, err := s.accProvider.ServiceAccount(ctx, id)
+	if err != nil {
+		return "", models.APIKey{}, fmt.Errorf("%s: %w", op, mapStorageErr(err))
+	}
+
+	appID, err = accountApp(sa, appID)
+	if err != nil {
+		return "", models.APIKey{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	key, apiKey, err := s.keys.CreateFor(ctx, sa.ID, appID, name, scopes, expiresAt)
+	if err != nil {
+		return "", models.APIKey{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return key, apiKey, nil
+}
+
+// Token returns access token for service account authenticated with JWT
+// assertion signed with one of its keys. The assertion names ID of the
+// account in iss and sub. appID is resolved as in CreateAPIKey.
+func (s *ServiceAccounts) Token(ctx context.Context, assertion string, appID int) (string, error) {
+	const op = "ServiceAccounts.Token"
+
+	var accountID int64
+	_, err := jwt.ParseAssertion(assertion, s.audience, s.maxAssertionTTL, func(subject, kid string) (string, error) {
+		id, err := strconv.ParseInt(subject, 10, 64)
+		if err != nil {
+			return "", errUnknownKeyOrClient
+		}
+
+		key, err := s.accProvider.ServiceAccountKey(ctx, id, kid)
+		if err != nil {
+			if errors.Is(err, storage.ErrAccountKeyNotFound) {
+				return "", errUnknownKeyOrClient
+			}
+
+			return "", err
+		}
+
+		accountID = id
+
+		return key.PublicKey, nil
+	})
+	if err != nil {
+		s.log.Warn("assertion rejected", slog.String("op", op), slog.String("error", err.Error()))
+
+		if errors.Is(err, jwt.ErrInvalidAssertion) {
+			return "", fmt.Errorf("%s: %w", op, ErrInvalidAssertion)
+		}
+
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	sa, err := s.accProvider.ServiceAccount(ctx, accountID)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, mapStorageErr(err))
+	}
+
+	appID, err = accountApp(sa, appID)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	token, err := s.issuer.ServiceAccountToken(ctx, sa.ID, appID)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	return token, nil
+}
+
+// accountApp returns the app service account gets credentials for.
+func accountApp(sa models.ServiceAccount, appID int) (int, error) {
+	if sa.AppID == 0 {
+		if appID == 0 {
+			return 0, ErrAppRequired
+		}
+
+		return appID, nil
+	}
+
+	if appID != 0 && appID != sa.AppID {
+		return 0, ErrAppNotAllowed
+	}
+
+	return sa.AppID, nil
+}
+
+func mapStorageErr(err error) error {
+	switch {
+	case errors.Is(err, storage.ErrAccountExists):
+		return ErrAccountExists
+	case errors.Is(err, storage.ErrAccountNotFound):
+		return ErrAccountNotFound
+	case errors.Is(err, storage.ErrAccountKeyExists):
+		return ErrKeyExists
+	case errors.Is(err, storage.ErrAccountKeyNotFound):
+		return ErrKeyNotFound
+	case errors.Is(err, storage.ErrAppNotFound):
+		return ErrAppNotFound
+	case errors.Is(err, storage.ErrOrgNotFound):
+		return ErrOrgNotFound
+	}
+
+	return err
+}
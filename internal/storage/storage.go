@@ -3,7 +3,46 @@ package storage
 import "errors"
 
 var (
-	ErrUserExists   = errors.New("user already exists")
-	ErrUserNotFound = errors.New("user not found")
-	ErrAppNotFound  = errors.New("app not found")
+	ErrUserExists              = errors.New("user already exists")
+	ErrUserNotFound            = errors.New("user not found")
+	ErrAppNotFound             = errors.New("app not found")
+	ErrAppExists               = errors.New("app already exists")
+	ErrSecretNotFound          = errors.New("app secret not found")
+	ErrRoleExists              = errors.New("role already exists")
+	ErrRoleNotFound            = errors.New("role not found")
+	ErrPermissionExists        = errors.New("permission already exists")
+	ErrPermissionNotFound      = errors.New("permission not found")
+	ErrPolicyExists            = errors.New("policy already exists")
+	ErrPolicyNotFound          = errors.New("policy not found")
+	ErrOrgExists               = errors.New("organization already exists")
+	ErrOrgNotFound             = errors.New("organization not found")
+	ErrMemberExists            = errors.New("member already exists")
+	ErrMemberNotFound          = errors.New("member not found")
+	ErrLastOwner               = errors.New("member is the last owner of the organization")
+	ErrInviteNotFound          = errors.New("invitation not found")
+	ErrGroupExists             = errors.New("group already exists")
+	ErrGroupNotFound           = errors.New("group not found")
+	ErrGroupCycle              = errors.New("group membership cycle")
+	ErrAPIKeyExists            = errors.New("api key already exists")
+	ErrAPIKeyNotFound          = errors.New("api key not found")
+	ErrAccountExists           = errors.New("service account already exists")
+	ErrAccountNotFound         = errors.New("service account not found")
+	ErrAccountKeyExists        = errors.New("service account key already exists")
+	ErrAccountKeyNotFound      = errors.New("service account key not found")
+	ErrRuleNotFound            = errors.New("token exchange rule not found")
+	ErrScopeNotFound           = errors.New("scope not found")
+	ErrConsentNotFound         = errors.New("consent not found")
+	ErrDeviceCodeExists        = errors.New("device code already exists")
+	ErrDeviceCodeNotFound      = errors.New("device code not found")
+	ErrInitialTokenNotFound    = errors.New("initial access token not found")
+	ErrIdentityExists          = errors.New("identity already exists")
+	ErrIdentityNotFound        = errors.New("identity not found")
+	ErrStateNotFound           = errors.New("federation state not found")
+	ErrLastLoginMethod         = errors.New("identity is the last login method of the user")
+	ErrServiceProviderExists   = errors.New("service provider already exists")
+	ErrServiceProviderNotFound = errors.New("service provider not found")
+	ErrMagicLinkNotFound       = errors.New("magic link not found")
+	ErrOTPFactorNotFound       = errors.New("otp factor not found")
+	ErrOTPChallengeNotFound    = errors.New("otp challenge not found")
+	ErrFollowRequestNotFound   = errors.New("follow request not found")
 )
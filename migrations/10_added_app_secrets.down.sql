@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS app_secrets;
+
+ALTER TABLE apps
+    DROP COLUMN IF EXISTS secret_kid;
@@ -0,0 +1,14 @@
+-- apps.secret is the primary secret tokens are signed with, secret_kid identifies it in the token header.
+ALTER TABLE apps
+    ADD COLUMN IF NOT EXISTS secret_kid TEXT NOT NULL DEFAULT substr(md5(random()::text), 1, 16);
+
+-- Former primary secrets still accepted for verification until they expire.
+CREATE TABLE IF NOT EXISTS app_secrets
+(
+    app_id INTEGER NOT NULL REFERENCES apps(id) ON DELETE CASCADE,
+    kid TEXT NOT NULL,
+    secret TEXT NOT NULL,
+    rotated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    expires_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY(app_id, kid)
+);
@@ -0,0 +1,5 @@
+ALTER TABLE apps
+    DROP COLUMN IF EXISTS min_age,
+    DROP COLUMN IF EXISTS required_fields,
+    DROP COLUMN IF EXISTS allowed_email_domains,
+    DROP COLUMN IF EXISTS registration;
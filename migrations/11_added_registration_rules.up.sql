@@ -0,0 +1,6 @@
+ALTER TABLE apps
+    ADD COLUMN IF NOT EXISTS registration TEXT NOT NULL DEFAULT 'open'
+        CHECK (registration IN ('open', 'closed', 'invite_only')),
+    ADD COLUMN IF NOT EXISTS allowed_email_domains TEXT[] NOT NULL DEFAULT '{}',
+    ADD COLUMN IF NOT EXISTS required_fields TEXT[] NOT NULL DEFAULT '{}',
+    ADD COLUMN IF NOT EXISTS min_age INTEGER NOT NULL DEFAULT 0 CHECK (min_age >= 0);
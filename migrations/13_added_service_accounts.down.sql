@@ -0,0 +1,13 @@
+DROP TABLE IF EXISTS service_account_keys;
+DROP TABLE IF EXISTS service_accounts;
+
+DELETE FROM users WHERE kind = 'service';
+
+-- Users without password, e.g. signed up through an upstream provider, get
+-- an empty hash no password matches, so they keep their accounts but can't
+-- log in with password until they set one.
+UPDATE users SET pass_hash = '' WHERE pass_hash IS NULL;
+
+ALTER TABLE users
+    ALTER COLUMN pass_hash SET NOT NULL,
+    DROP COLUMN IF EXISTS kind;
@@ -0,0 +1,31 @@
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS kind TEXT NOT NULL DEFAULT 'human' CHECK (kind IN ('human', 'service')),
+    ALTER COLUMN pass_hash DROP NOT NULL;
+
+CREATE TABLE IF NOT EXISTS service_accounts
+(
+    user_id INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    app_id INTEGER REFERENCES apps(id) ON DELETE CASCADE,
+    organization_id INTEGER REFERENCES organizations(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    disabled BOOLEAN NOT NULL DEFAULT FALSE,
+    created_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CHECK ((app_id IS NULL) <> (organization_id IS NULL))
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_service_accounts_app_id_name
+    ON service_accounts(app_id, name) WHERE app_id IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_service_accounts_organization_id_name
+    ON service_accounts(organization_id, name) WHERE organization_id IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS service_account_keys
+(
+    id SERIAL PRIMARY KEY,
+    service_account_id INTEGER NOT NULL REFERENCES service_accounts(user_id) ON DELETE CASCADE,
+    kid TEXT NOT NULL,
+    public_key TEXT NOT NULL,
+    expires_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE(service_account_id, kid)
+);
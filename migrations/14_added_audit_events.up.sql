@@ -0,0 +1,16 @@
+-- Audit events reference users and apps by plain IDs, so that the trail
+-- outlives deleted users and apps.
+CREATE TABLE IF NOT EXISTS audit_events
+(
+    id BIGSERIAL PRIMARY KEY,
+    action TEXT NOT NULL,
+    app_id INTEGER NOT NULL,
+    actor_id INTEGER NOT NULL,
+    user_id INTEGER NOT NULL,
+    session_id TEXT NOT NULL DEFAULT '',
+    details TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS audit_events_app_id_idx ON audit_events (app_id, id);
+CREATE INDEX IF NOT EXISTS audit_events_session_id_idx ON audit_events (session_id) WHERE session_id <> '';
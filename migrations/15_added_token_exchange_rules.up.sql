@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS token_exchange_rules
+(
+    source_app_id INTEGER NOT NULL REFERENCES apps(id) ON DELETE CASCADE,
+    target_app_id INTEGER NOT NULL REFERENCES apps(id) ON DELETE CASCADE,
+    scopes TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (source_app_id, target_app_id)
+);
+
+CREATE INDEX IF NOT EXISTS token_exchange_rules_target_app_id_idx ON token_exchange_rules (target_app_id);
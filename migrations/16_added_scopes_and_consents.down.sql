@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS consents;
+DROP TABLE IF EXISTS app_scopes;
+
+ALTER TABLE apps
+    DROP COLUMN IF EXISTS consent_required;
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS device_codes
+(
+    id BIGSERIAL PRIMARY KEY,
+    app_id INTEGER NOT NULL REFERENCES apps(id) ON DELETE CASCADE,
+    device_code_hash TEXT NOT NULL UNIQUE,
+    user_code TEXT NOT NULL UNIQUE,
+    scopes TEXT[] NOT NULL DEFAULT '{}',
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'denied')),
+    user_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
+    poll_interval INTEGER NOT NULL, -- seconds
+    last_polled_at TIMESTAMPTZ,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS device_codes_expires_at_idx ON device_codes (expires_at);
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS initial_access_tokens;
+
+ALTER TABLE apps
+    DROP COLUMN IF EXISTS registration_token_hash,
+    DROP COLUMN IF EXISTS token_endpoint_auth_method;
@@ -0,0 +1,20 @@
+-- registration_token_hash is set for apps registered dynamically (RFC 7591),
+-- it's the hash of the registration access token their developers manage
+-- them with.
+ALTER TABLE apps
+    ADD COLUMN IF NOT EXISTS token_endpoint_auth_method TEXT NOT NULL DEFAULT 'client_secret_basic',
+    ADD COLUMN IF NOT EXISTS registration_token_hash TEXT UNIQUE;
+
+-- Initial access tokens admins hand out to partners to register apps with.
+-- max_uses of 0 means unlimited uses.
+CREATE TABLE IF NOT EXISTS initial_access_tokens
+(
+    id SERIAL PRIMARY KEY,
+    name TEXT NOT NULL,
+    token_hash TEXT NOT NULL UNIQUE,
+    max_uses INTEGER NOT NULL DEFAULT 0,
+    uses INTEGER NOT NULL DEFAULT 0,
+    created_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    expires_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
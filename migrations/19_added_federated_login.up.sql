@@ -0,0 +1,26 @@
+-- Accounts of users at upstream identity providers, provider is its name in the config.
+CREATE TABLE IF NOT EXISTS identities
+(
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    provider TEXT NOT NULL,
+    subject TEXT NOT NULL,
+    email TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    last_login_at TIMESTAMPTZ,
+    UNIQUE (provider, subject)
+);
+CREATE INDEX IF NOT EXISTS idx_identities_user_id ON identities(user_id);
+
+-- Sign ins through upstream providers in progress, identified by hash of the state.
+CREATE TABLE IF NOT EXISTS federation_states
+(
+    state_hash TEXT PRIMARY KEY,
+    provider TEXT NOT NULL,
+    app_id INTEGER NOT NULL REFERENCES apps(id) ON DELETE CASCADE,
+    scopes TEXT[] NOT NULL DEFAULT '{}',
+    nonce TEXT NOT NULL,
+    code_verifier TEXT NOT NULL,
+    return_to TEXT NOT NULL DEFAULT '',
+    expires_at TIMESTAMPTZ NOT NULL
+);
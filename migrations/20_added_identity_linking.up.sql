@@ -0,0 +1,2 @@
+-- Set for sign ins started to link identity to the signed in user.
+ALTER TABLE federation_states ADD COLUMN IF NOT EXISTS link_user_id INTEGER REFERENCES users(id) ON DELETE CASCADE;
@@ -0,0 +1,10 @@
+-- SAML service providers users sign in to with the login of the app.
+CREATE TABLE IF NOT EXISTS saml_service_providers
+(
+    app_id INTEGER PRIMARY KEY REFERENCES apps(id) ON DELETE CASCADE,
+    entity_id TEXT NOT NULL UNIQUE,
+    acs_urls TEXT[] NOT NULL,
+    name_id_format TEXT NOT NULL,
+    attributes JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
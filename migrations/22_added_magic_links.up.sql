@@ -0,0 +1,11 @@
+-- Links emailed to users to log in to apps without password, identified by hash of the token.
+CREATE TABLE IF NOT EXISTS magic_links
+(
+    token_hash TEXT PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    app_id INTEGER NOT NULL REFERENCES apps(id) ON DELETE CASCADE,
+    scopes TEXT[] NOT NULL DEFAULT '{}',
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS idx_magic_links_user_id_app_id ON magic_links(user_id, app_id);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS otp_challenges;
+DROP TABLE IF EXISTS otp_factors;
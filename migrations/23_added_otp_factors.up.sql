@@ -0,0 +1,30 @@
+-- Confirmed email addresses and phone numbers users receive one-time passcodes at.
+CREATE TABLE IF NOT EXISTS otp_factors
+(
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    channel TEXT NOT NULL CHECK (channel IN ('email', 'sms')),
+    destination TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (user_id, channel)
+);
+
+-- One-time passcodes pending entry, identified by hash of the challenge token.
+CREATE TABLE IF NOT EXISTS otp_challenges
+(
+    token_hash TEXT PRIMARY KEY,
+    purpose TEXT NOT NULL CHECK (purpose IN ('login', 'second_factor', 'enroll')),
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    factor_id INTEGER REFERENCES otp_factors(id) ON DELETE CASCADE,
+    channel TEXT NOT NULL DEFAULT '',
+    destination TEXT NOT NULL DEFAULT '',
+    app_id INTEGER REFERENCES apps(id) ON DELETE CASCADE,
+    organization_id INTEGER REFERENCES organizations(id) ON DELETE CASCADE,
+    scopes TEXT[] NOT NULL DEFAULT '{}',
+    code_hash TEXT NOT NULL DEFAULT '',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    sends INTEGER NOT NULL DEFAULT 0,
+    sent_at TIMESTAMPTZ,
+    expires_at TIMESTAMPTZ NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_otp_challenges_expires_at ON otp_challenges(expires_at);
@@ -0,0 +1,7 @@
+ALTER TABLE users
+    ADD COLUMN followers_count INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN following_count INTEGER NOT NULL DEFAULT 0;
+
+UPDATE users u SET
+    followers_count = (SELECT count(*) FROM followers f WHERE f.following_id = u.id),
+    following_count = (SELECT count(*) FROM followers f WHERE f.follower_id = u.id);
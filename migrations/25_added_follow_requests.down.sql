@@ -0,0 +1,7 @@
+DELETE FROM followers WHERE status = 'pending';
+
+DROP INDEX IF EXISTS idx_followers_pending;
+
+ALTER TABLE followers DROP COLUMN IF EXISTS status;
+
+ALTER TABLE users DROP COLUMN IF EXISTS is_private;
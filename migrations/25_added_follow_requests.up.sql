@@ -0,0 +1,6 @@
+ALTER TABLE users ADD COLUMN is_private BOOLEAN NOT NULL DEFAULT FALSE;
+
+ALTER TABLE followers
+    ADD COLUMN status TEXT NOT NULL DEFAULT 'accepted' CHECK (status IN ('pending', 'accepted'));
+
+CREATE INDEX idx_followers_pending ON followers(following_id, id) WHERE status = 'pending';
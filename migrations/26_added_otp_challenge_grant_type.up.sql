@@ -0,0 +1,3 @@
+-- Grant type of the login a second factor challenge confirms, empty for
+-- challenges of other purposes.
+ALTER TABLE otp_challenges ADD COLUMN IF NOT EXISTS grant_type TEXT NOT NULL DEFAULT '';
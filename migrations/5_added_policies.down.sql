@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS authorization_requests;
+DROP TABLE IF EXISTS policies;
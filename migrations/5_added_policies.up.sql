@@ -0,0 +1,25 @@
+CREATE TABLE IF NOT EXISTS policies
+(
+    id SERIAL PRIMARY KEY,
+    app_id INTEGER NOT NULL REFERENCES apps(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    effect TEXT NOT NULL,
+    actions TEXT[] NOT NULL,
+    resources TEXT[] NOT NULL,
+    conditions JSONB NOT NULL DEFAULT '[]',
+    UNIQUE(app_id, name)
+);
+
+CREATE TABLE IF NOT EXISTS authorization_requests
+(
+    id BIGSERIAL PRIMARY KEY,
+    app_id INTEGER NOT NULL REFERENCES apps(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL,
+    action TEXT NOT NULL,
+    resource_type TEXT NOT NULL,
+    attributes JSONB NOT NULL,
+    allowed BOOLEAN NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS idx_authorization_requests_app_id ON authorization_requests(app_id, id DESC);
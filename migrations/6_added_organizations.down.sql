@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS organization_apps;
+DROP TABLE IF EXISTS organization_members;
+DROP TABLE IF EXISTS organizations;
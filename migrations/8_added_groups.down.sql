@@ -0,0 +1,7 @@
+ALTER TABLE apps
+    DROP COLUMN IF EXISTS group_claims;
+
+DROP TABLE IF EXISTS group_roles;
+DROP TABLE IF EXISTS group_subgroups;
+DROP TABLE IF EXISTS group_members;
+DROP TABLE IF EXISTS groups;
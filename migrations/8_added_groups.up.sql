@@ -0,0 +1,37 @@
+CREATE TABLE IF NOT EXISTS groups
+(
+    id SERIAL PRIMARY KEY,
+    app_id INTEGER NOT NULL REFERENCES apps(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE(app_id, name)
+);
+
+CREATE TABLE IF NOT EXISTS group_members
+(
+    group_id INTEGER NOT NULL REFERENCES groups(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    PRIMARY KEY(group_id, user_id)
+);
+CREATE INDEX IF NOT EXISTS idx_group_members_user_id ON group_members(user_id);
+
+-- Members of the child group are transitively members of the parent group.
+CREATE TABLE IF NOT EXISTS group_subgroups
+(
+    parent_id INTEGER NOT NULL REFERENCES groups(id) ON DELETE CASCADE,
+    child_id INTEGER NOT NULL REFERENCES groups(id) ON DELETE CASCADE,
+    PRIMARY KEY(parent_id, child_id),
+    CHECK (parent_id <> child_id)
+);
+CREATE INDEX IF NOT EXISTS idx_group_subgroups_child_id ON group_subgroups(child_id);
+
+CREATE TABLE IF NOT EXISTS group_roles
+(
+    group_id INTEGER NOT NULL REFERENCES groups(id) ON DELETE CASCADE,
+    role_id INTEGER NOT NULL REFERENCES roles(id) ON DELETE CASCADE,
+    PRIMARY KEY(group_id, role_id)
+);
+CREATE INDEX IF NOT EXISTS idx_group_roles_role_id ON group_roles(role_id);
+
+ALTER TABLE apps
+    ADD COLUMN IF NOT EXISTS group_claims BOOLEAN NOT NULL DEFAULT FALSE;
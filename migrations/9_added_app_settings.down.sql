@@ -0,0 +1,5 @@
+ALTER TABLE apps
+    DROP COLUMN IF EXISTS created_at,
+    DROP COLUMN IF EXISTS disabled,
+    DROP COLUMN IF EXISTS grant_types,
+    DROP COLUMN IF EXISTS redirect_uris;
@@ -0,0 +1,8 @@
+ALTER TABLE apps
+    ADD COLUMN IF NOT EXISTS redirect_uris TEXT[] NOT NULL DEFAULT '{}',
+    ADD COLUMN IF NOT EXISTS grant_types TEXT[] NOT NULL DEFAULT '{password}',
+    ADD COLUMN IF NOT EXISTS disabled BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS created_at TIMESTAMPTZ NOT NULL DEFAULT now();
+
+-- Apps were inserted by hand with explicit ids.
+SELECT setval('apps_id_seq', COALESCE((SELECT MAX(id) FROM apps), 0) + 1, false);
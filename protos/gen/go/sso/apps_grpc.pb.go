@@ -0,0 +1,663 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.28.1
+// source: sso/apps.proto
+
+package ssov1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Apps_CreateApp_FullMethodName               = "/auth.Apps/CreateApp"
+	Apps_GetApp_FullMethodName                  = "/auth.Apps/GetApp"
+	Apps_ListApps_FullMethodName                = "/auth.Apps/ListApps"
+	Apps_UpdateApp_FullMethodName               = "/auth.Apps/UpdateApp"
+	Apps_DisableApp_FullMethodName              = "/auth.Apps/DisableApp"
+	Apps_EnableApp_FullMethodName               = "/auth.Apps/EnableApp"
+	Apps_DeleteApp_FullMethodName               = "/auth.Apps/DeleteApp"
+	Apps_RotateAppSecret_FullMethodName         = "/auth.Apps/RotateAppSecret"
+	Apps_ListAppSecrets_FullMethodName          = "/auth.Apps/ListAppSecrets"
+	Apps_RevokeAppSecret_FullMethodName         = "/auth.Apps/RevokeAppSecret"
+	Apps_SetRegistrationRules_FullMethodName    = "/auth.Apps/SetRegistrationRules"
+	Apps_SetTokenExchangeRule_FullMethodName    = "/auth.Apps/SetTokenExchangeRule"
+	Apps_ListTokenExchangeRules_FullMethodName  = "/auth.Apps/ListTokenExchangeRules"
+	Apps_DeleteTokenExchangeRule_FullMethodName = "/auth.Apps/DeleteTokenExchangeRule"
+	Apps_SetConsentRequired_FullMethodName      = "/auth.Apps/SetConsentRequired"
+)
+
+// AppsClient is the client API for Apps service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Apps manages apps (clients) users log in to.
+//
+// Every RPC requires a token issued for the admin app to a user holding
+// the "admin" role in it.
+type AppsClient interface {
+	CreateApp(ctx context.Context, in *CreateAppRequest, opts ...grpc.CallOption) (*CreateAppResponse, error)
+	GetApp(ctx context.Context, in *GetAppRequest, opts ...grpc.CallOption) (*GetAppResponse, error)
+	ListApps(ctx context.Context, in *ListAppsRequest, opts ...grpc.CallOption) (*ListAppsResponse, error)
+	UpdateApp(ctx context.Context, in *UpdateAppRequest, opts ...grpc.CallOption) (*UpdateAppResponse, error)
+	DisableApp(ctx context.Context, in *DisableAppRequest, opts ...grpc.CallOption) (*DisableAppResponse, error)
+	EnableApp(ctx context.Context, in *EnableAppRequest, opts ...grpc.CallOption) (*EnableAppResponse, error)
+	DeleteApp(ctx context.Context, in *DeleteAppRequest, opts ...grpc.CallOption) (*DeleteAppResponse, error)
+	RotateAppSecret(ctx context.Context, in *RotateAppSecretRequest, opts ...grpc.CallOption) (*RotateAppSecretResponse, error)
+	ListAppSecrets(ctx context.Context, in *ListAppSecretsRequest, opts ...grpc.CallOption) (*ListAppSecretsResponse, error)
+	RevokeAppSecret(ctx context.Context, in *RevokeAppSecretRequest, opts ...grpc.CallOption) (*RevokeAppSecretResponse, error)
+	SetRegistrationRules(ctx context.Context, in *SetRegistrationRulesRequest, opts ...grpc.CallOption) (*SetRegistrationRulesResponse, error)
+	SetTokenExchangeRule(ctx context.Context, in *SetTokenExchangeRuleRequest, opts ...grpc.CallOption) (*SetTokenExchangeRuleResponse, error)
+	ListTokenExchangeRules(ctx context.Context, in *ListTokenExchangeRulesRequest, opts ...grpc.CallOption) (*ListTokenExchangeRulesResponse, error)
+	DeleteTokenExchangeRule(ctx context.Context, in *DeleteTokenExchangeRuleRequest, opts ...grpc.CallOption) (*DeleteTokenExchangeRuleResponse, error)
+	SetConsentRequired(ctx context.Context, in *SetConsentRequiredRequest, opts ...grpc.CallOption) (*SetConsentRequiredResponse, error)
+}
+
+type appsClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAppsClient(cc grpc.ClientConnInterface) AppsClient {
+	return &appsClient{cc}
+}
+
+func (c *appsClient) CreateApp(ctx context.Context, in *CreateAppRequest, opts ...grpc.CallOption) (*CreateAppResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateAppResponse)
+	err := c.cc.Invoke(ctx, Apps_CreateApp_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *appsClient) GetApp(ctx context.Context, in *GetAppRequest, opts ...grpc.CallOption) (*GetAppResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetAppResponse)
+	err := c.cc.Invoke(ctx, Apps_GetApp_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *appsClient) ListApps(ctx context.Context, in *ListAppsRequest, opts ...grpc.CallOption) (*ListAppsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListAppsResponse)
+	err := c.cc.Invoke(ctx, Apps_ListApps_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *appsClient) UpdateApp(ctx context.Context, in *UpdateAppRequest, opts ...grpc.CallOption) (*UpdateAppResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdateAppResponse)
+	err := c.cc.Invoke(ctx, Apps_UpdateApp_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *appsClient) DisableApp(ctx context.Context, in *DisableAppRequest, opts ...grpc.CallOption) (*DisableAppResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DisableAppResponse)
+	err := c.cc.Invoke(ctx, Apps_DisableApp_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *appsClient) EnableApp(ctx context.Context, in *EnableAppRequest, opts ...grpc.CallOption) (*EnableAppResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EnableAppResponse)
+	err := c.cc.Invoke(ctx, Apps_EnableApp_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *appsClient) DeleteApp(ctx context.Context, in *DeleteAppRequest, opts ...grpc.CallOption) (*DeleteAppResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteAppResponse)
+	err := c.cc.Invoke(ctx, Apps_DeleteApp_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *appsClient) RotateAppSecret(ctx context.Context, in *RotateAppSecretRequest, opts ...grpc.CallOption) (*RotateAppSecretResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RotateAppSecretResponse)
+	err := c.cc.Invoke(ctx, Apps_RotateAppSecret_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *appsClient) ListAppSecrets(ctx context.Context, in *ListAppSecretsRequest, opts ...grpc.CallOption) (*ListAppSecretsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListAppSecretsResponse)
+	err := c.cc.Invoke(ctx, Apps_ListAppSecrets_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *appsClient) RevokeAppSecret(ctx context.Context, in *RevokeAppSecretRequest, opts ...grpc.CallOption) (*RevokeAppSecretResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RevokeAppSecretResponse)
+	err := c.cc.Invoke(ctx, Apps_RevokeAppSecret_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *appsClient) SetRegistrationRules(ctx context.Context, in *SetRegistrationRulesRequest, opts ...grpc.CallOption) (*SetRegistrationRulesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetRegistrationRulesResponse)
+	err := c.cc.Invoke(ctx, Apps_SetRegistrationRules_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *appsClient) SetTokenExchangeRule(ctx context.Context, in *SetTokenExchangeRuleRequest, opts ...grpc.CallOption) (*SetTokenExchangeRuleResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetTokenExchangeRuleResponse)
+	err := c.cc.Invoke(ctx, Apps_SetTokenExchangeRule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *appsClient) ListTokenExchangeRules(ctx context.Context, in *ListTokenExchangeRulesRequest, opts ...grpc.CallOption) (*ListTokenExchangeRulesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListTokenExchangeRulesResponse)
+	err := c.cc.Invoke(ctx, Apps_ListTokenExchangeRules_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *appsClient) DeleteTokenExchangeRule(ctx context.Context, in *DeleteTokenExchangeRuleRequest, opts ...grpc.CallOption) (*DeleteTokenExchangeRuleResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteTokenExchangeRuleResponse)
+	err := c.cc.Invoke(ctx, Apps_DeleteTokenExchangeRule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *appsClient) SetConsentRequired(ctx context.Context, in *SetConsentRequiredRequest, opts ...grpc.CallOption) (*SetConsentRequiredResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetConsentRequiredResponse)
+	err := c.cc.Invoke(ctx, Apps_SetConsentRequired_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AppsServer is the server API for Apps service.
+// All implementations must embed UnimplementedAppsServer
+// for forward compatibility.
+//
+// Apps manages apps (clients) users log in to.
+//
+// Every RPC requires a token issued for the admin app to a user holding
+// the "admin" role in it.
+type AppsServer interface {
+	CreateApp(context.Context, *CreateAppRequest) (*CreateAppResponse, error)
+	GetApp(context.Context, *GetAppRequest) (*GetAppResponse, error)
+	ListApps(context.Context, *ListAppsRequest) (*ListAppsResponse, error)
+	UpdateApp(context.Context, *UpdateAppRequest) (*UpdateAppResponse, error)
+	DisableApp(context.Context, *DisableAppRequest) (*DisableAppResponse, error)
+	EnableApp(context.Context, *EnableAppRequest) (*EnableAppResponse, error)
+	DeleteApp(context.Context, *DeleteAppRequest) (*DeleteAppResponse, error)
+	RotateAppSecret(context.Context, *RotateAppSecretRequest) (*RotateAppSecretResponse, error)
+	ListAppSecrets(context.Context, *ListAppSecretsRequest) (*ListAppSecretsResponse, error)
+	RevokeAppSecret(context.Context, *RevokeAppSecretRequest) (*RevokeAppSecretResponse, error)
+	SetRegistrationRules(context.Context, *SetRegistrationRulesRequest) (*SetRegistrationRulesResponse, error)
+	SetTokenExchangeRule(context.Context, *SetTokenExchangeRuleRequest) (*SetTokenExchangeRuleResponse, error)
+	ListTokenExchangeRules(context.Context, *ListTokenExchangeRulesRequest) (*ListTokenExchangeRulesResponse, error)
+	DeleteTokenExchangeRule(context.Context, *DeleteTokenExchangeRuleRequest) (*DeleteTokenExchangeRuleResponse, error)
+	SetConsentRequired(context.Context, *SetConsentRequiredRequest) (*SetConsentRequiredResponse, error)
+	mustEmbedUnimplementedAppsServer()
+}
+
+// UnimplementedAppsServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedAppsServer struct{}
+
+func (UnimplementedAppsServer) CreateApp(context.Context, *CreateAppRequest) (*CreateAppResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateApp not implemented")
+}
+func (UnimplementedAppsServer) GetApp(context.Context, *GetAppRequest) (*GetAppResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetApp not implemented")
+}
+func (UnimplementedAppsServer) ListApps(context.Context, *ListAppsRequest) (*ListAppsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListApps not implemented")
+}
+func (UnimplementedAppsServer) UpdateApp(context.Context, *UpdateAppRequest) (*UpdateAppResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateApp not implemented")
+}
+func (UnimplementedAppsServer) DisableApp(context.Context, *DisableAppRequest) (*DisableAppResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DisableApp not implemented")
+}
+func (UnimplementedAppsServer) EnableApp(context.Context, *EnableAppRequest) (*EnableAppResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EnableApp not implemented")
+}
+func (UnimplementedAppsServer) DeleteApp(context.Context, *DeleteAppRequest) (*DeleteAppResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteApp not implemented")
+}
+func (UnimplementedAppsServer) RotateAppSecret(context.Context, *RotateAppSecretRequest) (*RotateAppSecretResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RotateAppSecret not implemented")
+}
+func (UnimplementedAppsServer) ListAppSecrets(context.Context, *ListAppSecretsRequest) (*ListAppSecretsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAppSecrets not implemented")
+}
+func (UnimplementedAppsServer) RevokeAppSecret(context.Context, *RevokeAppSecretRequest) (*RevokeAppSecretResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeAppSecret not implemented")
+}
+func (UnimplementedAppsServer) SetRegistrationRules(context.Context, *SetRegistrationRulesRequest) (*SetRegistrationRulesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetRegistrationRules not implemented")
+}
+func (UnimplementedAppsServer) SetTokenExchangeRule(context.Context, *SetTokenExchangeRuleRequest) (*SetTokenExchangeRuleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetTokenExchangeRule not implemented")
+}
+func (UnimplementedAppsServer) ListTokenExchangeRules(context.Context, *ListTokenExchangeRulesRequest) (*ListTokenExchangeRulesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTokenExchangeRules not implemented")
+}
+func (UnimplementedAppsServer) DeleteTokenExchangeRule(context.Context, *DeleteTokenExchangeRuleRequest) (*DeleteTokenExchangeRuleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteTokenExchangeRule not implemented")
+}
+func (UnimplementedAppsServer) SetConsentRequired(context.Context, *SetConsentRequiredRequest) (*SetConsentRequiredResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetConsentRequired not implemented")
+}
+func (UnimplementedAppsServer) mustEmbedUnimplementedAppsServer() {}
+func (UnimplementedAppsServer) testEmbeddedByValue()              {}
+
+// UnsafeAppsServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AppsServer will
+// result in compilation errors.
+type UnsafeAppsServer interface {
+	mustEmbedUnimplementedAppsServer()
+}
+
+func RegisterAppsServer(s grpc.ServiceRegistrar, srv AppsServer) {
+	// If the following call pancis, it indicates UnimplementedAppsServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Apps_ServiceDesc, srv)
+}
+
+func _Apps_CreateApp_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateAppRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AppsServer).CreateApp(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Apps_CreateApp_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AppsServer).CreateApp(ctx, req.(*CreateAppRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Apps_GetApp_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAppRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AppsServer).GetApp(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Apps_GetApp_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AppsServer).GetApp(ctx, req.(*GetAppRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Apps_ListApps_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAppsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AppsServer).ListApps(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Apps_ListApps_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AppsServer).ListApps(ctx, req.(*ListAppsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Apps_UpdateApp_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateAppRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AppsServer).UpdateApp(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Apps_UpdateApp_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AppsServer).UpdateApp(ctx, req.(*UpdateAppRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Apps_DisableApp_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DisableAppRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AppsServer).DisableApp(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Apps_DisableApp_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AppsServer).DisableApp(ctx, req.(*DisableAppRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Apps_EnableApp_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EnableAppRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AppsServer).EnableApp(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Apps_EnableApp_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AppsServer).EnableApp(ctx, req.(*EnableAppRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Apps_DeleteApp_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteAppRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AppsServer).DeleteApp(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Apps_DeleteApp_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AppsServer).DeleteApp(ctx, req.(*DeleteAppRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Apps_RotateAppSecret_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RotateAppSecretRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AppsServer).RotateAppSecret(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Apps_RotateAppSecret_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AppsServer).RotateAppSecret(ctx, req.(*RotateAppSecretRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Apps_ListAppSecrets_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAppSecretsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AppsServer).ListAppSecrets(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Apps_ListAppSecrets_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AppsServer).ListAppSecrets(ctx, req.(*ListAppSecretsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Apps_RevokeAppSecret_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeAppSecretRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AppsServer).RevokeAppSecret(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Apps_RevokeAppSecret_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AppsServer).RevokeAppSecret(ctx, req.(*RevokeAppSecretRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Apps_SetRegistrationRules_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetRegistrationRulesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AppsServer).SetRegistrationRules(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Apps_SetRegistrationRules_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AppsServer).SetRegistrationRules(ctx, req.(*SetRegistrationRulesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Apps_SetTokenExchangeRule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetTokenExchangeRuleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AppsServer).SetTokenExchangeRule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Apps_SetTokenExchangeRule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AppsServer).SetTokenExchangeRule(ctx, req.(*SetTokenExchangeRuleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Apps_ListTokenExchangeRules_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTokenExchangeRulesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AppsServer).ListTokenExchangeRules(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Apps_ListTokenExchangeRules_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AppsServer).ListTokenExchangeRules(ctx, req.(*ListTokenExchangeRulesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Apps_DeleteTokenExchangeRule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteTokenExchangeRuleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AppsServer).DeleteTokenExchangeRule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Apps_DeleteTokenExchangeRule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AppsServer).DeleteTokenExchangeRule(ctx, req.(*DeleteTokenExchangeRuleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Apps_SetConsentRequired_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetConsentRequiredRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AppsServer).SetConsentRequired(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Apps_SetConsentRequired_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AppsServer).SetConsentRequired(ctx, req.(*SetConsentRequiredRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Apps_ServiceDesc is the grpc.ServiceDesc for Apps service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Apps_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "auth.Apps",
+	HandlerType: (*AppsServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateApp",
+			Handler:    _Apps_CreateApp_Handler,
+		},
+		{
+			MethodName: "GetApp",
+			Handler:    _Apps_GetApp_Handler,
+		},
+		{
+			MethodName: "ListApps",
+			Handler:    _Apps_ListApps_Handler,
+		},
+		{
+			MethodName: "UpdateApp",
+			Handler:    _Apps_UpdateApp_Handler,
+		},
+		{
+			MethodName: "DisableApp",
+			Handler:    _Apps_DisableApp_Handler,
+		},
+		{
+			MethodName: "EnableApp",
+			Handler:    _Apps_EnableApp_Handler,
+		},
+		{
+			MethodName: "DeleteApp",
+			Handler:    _Apps_DeleteApp_Handler,
+		},
+		{
+			MethodName: "RotateAppSecret",
+			Handler:    _Apps_RotateAppSecret_Handler,
+		},
+		{
+			MethodName: "ListAppSecrets",
+			Handler:    _Apps_ListAppSecrets_Handler,
+		},
+		{
+			MethodName: "RevokeAppSecret",
+			Handler:    _Apps_RevokeAppSecret_Handler,
+		},
+		{
+			MethodName: "SetRegistrationRules",
+			Handler:    _Apps_SetRegistrationRules_Handler,
+		},
+		{
+			MethodName: "SetTokenExchangeRule",
+			Handler:    _Apps_SetTokenExchangeRule_Handler,
+		},
+		{
+			MethodName: "ListTokenExchangeRules",
+			Handler:    _Apps_ListTokenExchangeRules_Handler,
+		},
+		{
+			MethodName: "DeleteTokenExchangeRule",
+			Handler:    _Apps_DeleteTokenExchangeRule_Handler,
+		},
+		{
+			MethodName: "SetConsentRequired",
+			Handler:    _Apps_SetConsentRequired_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "sso/apps.proto",
+}
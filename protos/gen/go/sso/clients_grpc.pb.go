@@ -0,0 +1,375 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.28.1
+// source: sso/clients.proto
+
+package ssov1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	ClientRegistration_RegisterClient_FullMethodName           = "/auth.ClientRegistration/RegisterClient"
+	ClientRegistration_GetClient_FullMethodName                = "/auth.ClientRegistration/GetClient"
+	ClientRegistration_UpdateClient_FullMethodName             = "/auth.ClientRegistration/UpdateClient"
+	ClientRegistration_DeleteClient_FullMethodName             = "/auth.ClientRegistration/DeleteClient"
+	ClientRegistration_CreateInitialAccessToken_FullMethodName = "/auth.ClientRegistration/CreateInitialAccessToken"
+	ClientRegistration_ListInitialAccessTokens_FullMethodName  = "/auth.ClientRegistration/ListInitialAccessTokens"
+	ClientRegistration_RevokeInitialAccessToken_FullMethodName = "/auth.ClientRegistration/RevokeInitialAccessToken"
+)
+
+// ClientRegistrationClient is the client API for ClientRegistration service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// ClientRegistration lets partners register apps (clients) themselves
+// (RFC 7591) and manage them later (RFC 7592).
+//
+// RegisterClient requires an initial access token admins hand out. The
+// registered app gets a registration access token GetClient, UpdateClient
+// and DeleteClient require. Both tokens are passed in requests, not in the
+// authorization metadata. Registered apps are third-party, they require
+// consent of users.
+//
+// CreateInitialAccessToken, ListInitialAccessTokens and
+// RevokeInitialAccessToken require a token issued for the admin app to a
+// user holding the "admin" role in it.
+type ClientRegistrationClient interface {
+	RegisterClient(ctx context.Context, in *RegisterClientRequest, opts ...grpc.CallOption) (*RegisterClientResponse, error)
+	GetClient(ctx context.Context, in *GetClientRequest, opts ...grpc.CallOption) (*GetClientResponse, error)
+	UpdateClient(ctx context.Context, in *UpdateClientRequest, opts ...grpc.CallOption) (*UpdateClientResponse, error)
+	DeleteClient(ctx context.Context, in *DeleteClientRequest, opts ...grpc.CallOption) (*DeleteClientResponse, error)
+	CreateInitialAccessToken(ctx context.Context, in *CreateInitialAccessTokenRequest, opts ...grpc.CallOption) (*CreateInitialAccessTokenResponse, error)
+	ListInitialAccessTokens(ctx context.Context, in *ListInitialAccessTokensRequest, opts ...grpc.CallOption) (*ListInitialAccessTokensResponse, error)
+	RevokeInitialAccessToken(ctx context.Context, in *RevokeInitialAccessTokenRequest, opts ...grpc.CallOption) (*RevokeInitialAccessTokenResponse, error)
+}
+
+type clientRegistrationClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewClientRegistrationClient(cc grpc.ClientConnInterface) ClientRegistrationClient {
+	return &clientRegistrationClient{cc}
+}
+
+func (c *clientRegistrationClient) RegisterClient(ctx context.Context, in *RegisterClientRequest, opts ...grpc.CallOption) (*RegisterClientResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RegisterClientResponse)
+	err := c.cc.Invoke(ctx, ClientRegistration_RegisterClient_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clientRegistrationClient) GetClient(ctx context.Context, in *GetClientRequest, opts ...grpc.CallOption) (*GetClientResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetClientResponse)
+	err := c.cc.Invoke(ctx, ClientRegistration_GetClient_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clientRegistrationClient) UpdateClient(ctx context.Context, in *UpdateClientRequest, opts ...grpc.CallOption) (*UpdateClientResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdateClientResponse)
+	err := c.cc.Invoke(ctx, ClientRegistration_UpdateClient_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clientRegistrationClient) DeleteClient(ctx context.Context, in *DeleteClientRequest, opts ...grpc.CallOption) (*DeleteClientResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteClientResponse)
+	err := c.cc.Invoke(ctx, ClientRegistration_DeleteClient_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clientRegistrationClient) CreateInitialAccessToken(ctx context.Context, in *CreateInitialAccessTokenRequest, opts ...grpc.CallOption) (*CreateInitialAccessTokenResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateInitialAccessTokenResponse)
+	err := c.cc.Invoke(ctx, ClientRegistration_CreateInitialAccessToken_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clientRegistrationClient) ListInitialAccessTokens(ctx context.Context, in *ListInitialAccessTokensRequest, opts ...grpc.CallOption) (*ListInitialAccessTokensResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListInitialAccessTokensResponse)
+	err := c.cc.Invoke(ctx, ClientRegistration_ListInitialAccessTokens_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clientRegistrationClient) RevokeInitialAccessToken(ctx context.Context, in *RevokeInitialAccessTokenRequest, opts ...grpc.CallOption) (*RevokeInitialAccessTokenResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RevokeInitialAccessTokenResponse)
+	err := c.cc.Invoke(ctx, ClientRegistration_RevokeInitialAccessToken_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ClientRegistrationServer is the server API for ClientRegistration service.
+// All implementations must embed UnimplementedClientRegistrationServer
+// for forward compatibility.
+//
+// ClientRegistration lets partners register apps (clients) themselves
+// (RFC 7591) and manage them later (RFC 7592).
+//
+// RegisterClient requires an initial access token admins hand out. The
+// registered app gets a registration access token GetClient, UpdateClient
+// and DeleteClient require. Both tokens are passed in requests, not in the
+// authorization metadata. Registered apps are third-party, they require
+// consent of users.
+//
+// CreateInitialAccessToken, ListInitialAccessTokens and
+// RevokeInitialAccessToken require a token issued for the admin app to a
+// user holding the "admin" role in it.
+type ClientRegistrationServer interface {
+	RegisterClient(context.Context, *RegisterClientRequest) (*RegisterClientResponse, error)
+	GetClient(context.Context, *GetClientRequest) (*GetClientResponse, error)
+	UpdateClient(context.Context, *UpdateClientRequest) (*UpdateClientResponse, error)
+	DeleteClient(context.Context, *DeleteClientRequest) (*DeleteClientResponse, error)
+	CreateInitialAccessToken(context.Context, *CreateInitialAccessTokenRequest) (*CreateInitialAccessTokenResponse, error)
+	ListInitialAccessTokens(context.Context, *ListInitialAccessTokensRequest) (*ListInitialAccessTokensResponse, error)
+	RevokeInitialAccessToken(context.Context, *RevokeInitialAccessTokenRequest) (*RevokeInitialAccessTokenResponse, error)
+	mustEmbedUnimplementedClientRegistrationServer()
+}
+
+// UnimplementedClientRegistrationServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedClientRegistrationServer struct{}
+
+func (UnimplementedClientRegistrationServer) RegisterClient(context.Context, *RegisterClientRequest) (*RegisterClientResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RegisterClient not implemented")
+}
+func (UnimplementedClientRegistrationServer) GetClient(context.Context, *GetClientRequest) (*GetClientResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetClient not implemented")
+}
+func (UnimplementedClientRegistrationServer) UpdateClient(context.Context, *UpdateClientRequest) (*UpdateClientResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateClient not implemented")
+}
+func (UnimplementedClientRegistrationServer) DeleteClient(context.Context, *DeleteClientRequest) (*DeleteClientResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteClient not implemented")
+}
+func (UnimplementedClientRegistrationServer) CreateInitialAccessToken(context.Context, *CreateInitialAccessTokenRequest) (*CreateInitialAccessTokenResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateInitialAccessToken not implemented")
+}
+func (UnimplementedClientRegistrationServer) ListInitialAccessTokens(context.Context, *ListInitialAccessTokensRequest) (*ListInitialAccessTokensResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListInitialAccessTokens not implemented")
+}
+func (UnimplementedClientRegistrationServer) RevokeInitialAccessToken(context.Context, *RevokeInitialAccessTokenRequest) (*RevokeInitialAccessTokenResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeInitialAccessToken not implemented")
+}
+func (UnimplementedClientRegistrationServer) mustEmbedUnimplementedClientRegistrationServer() {}
+func (UnimplementedClientRegistrationServer) testEmbeddedByValue()                            {}
+
+// UnsafeClientRegistrationServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ClientRegistrationServer will
+// result in compilation errors.
+type UnsafeClientRegistrationServer interface {
+	mustEmbedUnimplementedClientRegistrationServer()
+}
+
+func RegisterClientRegistrationServer(s grpc.ServiceRegistrar, srv ClientRegistrationServer) {
+	// If the following call pancis, it indicates UnimplementedClientRegistrationServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&ClientRegistration_ServiceDesc, srv)
+}
+
+func _ClientRegistration_RegisterClient_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegisterClientRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientRegistrationServer).RegisterClient(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ClientRegistration_RegisterClient_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientRegistrationServer).RegisterClient(ctx, req.(*RegisterClientRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClientRegistration_GetClient_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetClientRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientRegistrationServer).GetClient(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ClientRegistration_GetClient_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientRegistrationServer).GetClient(ctx, req.(*GetClientRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClientRegistration_UpdateClient_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateClientRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientRegistrationServer).UpdateClient(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ClientRegistration_UpdateClient_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientRegistrationServer).UpdateClient(ctx, req.(*UpdateClientRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClientRegistration_DeleteClient_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteClientRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientRegistrationServer).DeleteClient(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ClientRegistration_DeleteClient_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientRegistrationServer).DeleteClient(ctx, req.(*DeleteClientRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClientRegistration_CreateInitialAccessToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateInitialAccessTokenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientRegistrationServer).CreateInitialAccessToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ClientRegistration_CreateInitialAccessToken_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientRegistrationServer).CreateInitialAccessToken(ctx, req.(*CreateInitialAccessTokenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClientRegistration_ListInitialAccessTokens_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListInitialAccessTokensRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientRegistrationServer).ListInitialAccessTokens(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ClientRegistration_ListInitialAccessTokens_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientRegistrationServer).ListInitialAccessTokens(ctx, req.(*ListInitialAccessTokensRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClientRegistration_RevokeInitialAccessToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeInitialAccessTokenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientRegistrationServer).RevokeInitialAccessToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ClientRegistration_RevokeInitialAccessToken_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientRegistrationServer).RevokeInitialAccessToken(ctx, req.(*RevokeInitialAccessTokenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// ClientRegistration_ServiceDesc is the grpc.ServiceDesc for ClientRegistration service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ClientRegistration_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "auth.ClientRegistration",
+	HandlerType: (*ClientRegistrationServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "RegisterClient",
+			Handler:    _ClientRegistration_RegisterClient_Handler,
+		},
+		{
+			MethodName: "GetClient",
+			Handler:    _ClientRegistration_GetClient_Handler,
+		},
+		{
+			MethodName: "UpdateClient",
+			Handler:    _ClientRegistration_UpdateClient_Handler,
+		},
+		{
+			MethodName: "DeleteClient",
+			Handler:    _ClientRegistration_DeleteClient_Handler,
+		},
+		{
+			MethodName: "CreateInitialAccessToken",
+			Handler:    _ClientRegistration_CreateInitialAccessToken_Handler,
+		},
+		{
+			MethodName: "ListInitialAccessTokens",
+			Handler:    _ClientRegistration_ListInitialAccessTokens_Handler,
+		},
+		{
+			MethodName: "RevokeInitialAccessToken",
+			Handler:    _ClientRegistration_RevokeInitialAccessToken_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "sso/clients.proto",
+}
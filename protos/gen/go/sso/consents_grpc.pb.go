@@ -0,0 +1,327 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.28.1
+// source: sso/consents.proto
+
+package ssov1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Consents_SetScope_FullMethodName      = "/auth.Consents/SetScope"
+	Consents_ListScopes_FullMethodName    = "/auth.Consents/ListScopes"
+	Consents_DeleteScope_FullMethodName   = "/auth.Consents/DeleteScope"
+	Consents_GrantConsent_FullMethodName  = "/auth.Consents/GrantConsent"
+	Consents_ListConsents_FullMethodName  = "/auth.Consents/ListConsents"
+	Consents_RevokeConsent_FullMethodName = "/auth.Consents/RevokeConsent"
+)
+
+// ConsentsClient is the client API for Consents service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Consents manages scopes apps may request at login and consents users
+// grant to apps.
+//
+// SetScope and DeleteScope require a token of an admin of the app.
+// ListScopes is public, for consent screens to describe scopes.
+// GrantConsent, ListConsents and RevokeConsent require a token of the user,
+// consent can't be granted with impersonation tokens or API keys.
+type ConsentsClient interface {
+	SetScope(ctx context.Context, in *SetScopeRequest, opts ...grpc.CallOption) (*SetScopeResponse, error)
+	ListScopes(ctx context.Context, in *ListScopesRequest, opts ...grpc.CallOption) (*ListScopesResponse, error)
+	DeleteScope(ctx context.Context, in *DeleteScopeRequest, opts ...grpc.CallOption) (*DeleteScopeResponse, error)
+	GrantConsent(ctx context.Context, in *GrantConsentRequest, opts ...grpc.CallOption) (*GrantConsentResponse, error)
+	ListConsents(ctx context.Context, in *ListConsentsRequest, opts ...grpc.CallOption) (*ListConsentsResponse, error)
+	RevokeConsent(ctx context.Context, in *RevokeConsentRequest, opts ...grpc.CallOption) (*RevokeConsentResponse, error)
+}
+
+type consentsClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewConsentsClient(cc grpc.ClientConnInterface) ConsentsClient {
+	return &consentsClient{cc}
+}
+
+func (c *consentsClient) SetScope(ctx context.Context, in *SetScopeRequest, opts ...grpc.CallOption) (*SetScopeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetScopeResponse)
+	err := c.cc.Invoke(ctx, Consents_SetScope_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *consentsClient) ListScopes(ctx context.Context, in *ListScopesRequest, opts ...grpc.CallOption) (*ListScopesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListScopesResponse)
+	err := c.cc.Invoke(ctx, Consents_ListScopes_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *consentsClient) DeleteScope(ctx context.Context, in *DeleteScopeRequest, opts ...grpc.CallOption) (*DeleteScopeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteScopeResponse)
+	err := c.cc.Invoke(ctx, Consents_DeleteScope_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *consentsClient) GrantConsent(ctx context.Context, in *GrantConsentRequest, opts ...grpc.CallOption) (*GrantConsentResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GrantConsentResponse)
+	err := c.cc.Invoke(ctx, Consents_GrantConsent_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *consentsClient) ListConsents(ctx context.Context, in *ListConsentsRequest, opts ...grpc.CallOption) (*ListConsentsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListConsentsResponse)
+	err := c.cc.Invoke(ctx, Consents_ListConsents_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *consentsClient) RevokeConsent(ctx context.Context, in *RevokeConsentRequest, opts ...grpc.CallOption) (*RevokeConsentResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RevokeConsentResponse)
+	err := c.cc.Invoke(ctx, Consents_RevokeConsent_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ConsentsServer is the server API for Consents service.
+// All implementations must embed UnimplementedConsentsServer
+// for forward compatibility.
+//
+// Consents manages scopes apps may request at login and consents users
+// grant to apps.
+//
+// SetScope and DeleteScope require a token of an admin of the app.
+// ListScopes is public, for consent screens to describe scopes.
+// GrantConsent, ListConsents and RevokeConsent require a token of the user,
+// consent can't be granted with impersonation tokens or API keys.
+type ConsentsServer interface {
+	SetScope(context.Context, *SetScopeRequest) (*SetScopeResponse, error)
+	ListScopes(context.Context, *ListScopesRequest) (*ListScopesResponse, error)
+	DeleteScope(context.Context, *DeleteScopeRequest) (*DeleteScopeResponse, error)
+	GrantConsent(context.Context, *GrantConsentRequest) (*GrantConsentResponse, error)
+	ListConsents(context.Context, *ListConsentsRequest) (*ListConsentsResponse, error)
+	RevokeConsent(context.Context, *RevokeConsentRequest) (*RevokeConsentResponse, error)
+	mustEmbedUnimplementedConsentsServer()
+}
+
+// UnimplementedConsentsServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedConsentsServer struct{}
+
+func (UnimplementedConsentsServer) SetScope(context.Context, *SetScopeRequest) (*SetScopeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetScope not implemented")
+}
+func (UnimplementedConsentsServer) ListScopes(context.Context, *ListScopesRequest) (*ListScopesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListScopes not implemented")
+}
+func (UnimplementedConsentsServer) DeleteScope(context.Context, *DeleteScopeRequest) (*DeleteScopeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteScope not implemented")
+}
+func (UnimplementedConsentsServer) GrantConsent(context.Context, *GrantConsentRequest) (*GrantConsentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GrantConsent not implemented")
+}
+func (UnimplementedConsentsServer) ListConsents(context.Context, *ListConsentsRequest) (*ListConsentsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListConsents not implemented")
+}
+func (UnimplementedConsentsServer) RevokeConsent(context.Context, *RevokeConsentRequest) (*RevokeConsentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeConsent not implemented")
+}
+func (UnimplementedConsentsServer) mustEmbedUnimplementedConsentsServer() {}
+func (UnimplementedConsentsServer) testEmbeddedByValue()                  {}
+
+// UnsafeConsentsServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ConsentsServer will
+// result in compilation errors.
+type UnsafeConsentsServer interface {
+	mustEmbedUnimplementedConsentsServer()
+}
+
+func RegisterConsentsServer(s grpc.ServiceRegistrar, srv ConsentsServer) {
+	// If the following call pancis, it indicates UnimplementedConsentsServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Consents_ServiceDesc, srv)
+}
+
+func _Consents_SetScope_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetScopeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConsentsServer).SetScope(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Consents_SetScope_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConsentsServer).SetScope(ctx, req.(*SetScopeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Consents_ListScopes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListScopesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConsentsServer).ListScopes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Consents_ListScopes_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConsentsServer).ListScopes(ctx, req.(*ListScopesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Consents_DeleteScope_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteScopeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConsentsServer).DeleteScope(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Consents_DeleteScope_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConsentsServer).DeleteScope(ctx, req.(*DeleteScopeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Consents_GrantConsent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GrantConsentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConsentsServer).GrantConsent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Consents_GrantConsent_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConsentsServer).GrantConsent(ctx, req.(*GrantConsentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Consents_ListConsents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListConsentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConsentsServer).ListConsents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Consents_ListConsents_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConsentsServer).ListConsents(ctx, req.(*ListConsentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Consents_RevokeConsent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeConsentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConsentsServer).RevokeConsent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Consents_RevokeConsent_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConsentsServer).RevokeConsent(ctx, req.(*RevokeConsentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Consents_ServiceDesc is the grpc.ServiceDesc for Consents service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Consents_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "auth.Consents",
+	HandlerType: (*ConsentsServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "SetScope",
+			Handler:    _Consents_SetScope_Handler,
+		},
+		{
+			MethodName: "ListScopes",
+			Handler:    _Consents_ListScopes_Handler,
+		},
+		{
+			MethodName: "DeleteScope",
+			Handler:    _Consents_DeleteScope_Handler,
+		},
+		{
+			MethodName: "GrantConsent",
+			Handler:    _Consents_GrantConsent_Handler,
+		},
+		{
+			MethodName: "ListConsents",
+			Handler:    _Consents_ListConsents_Handler,
+		},
+		{
+			MethodName: "RevokeConsent",
+			Handler:    _Consents_RevokeConsent_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "sso/consents.proto",
+}
@@ -0,0 +1,309 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.28.1
+// source: sso/devices.proto
+
+package ssov1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	DeviceAuthorization_AuthorizeDevice_FullMethodName  = "/auth.DeviceAuthorization/AuthorizeDevice"
+	DeviceAuthorization_DeviceToken_FullMethodName      = "/auth.DeviceAuthorization/DeviceToken"
+	DeviceAuthorization_GetDeviceRequest_FullMethodName = "/auth.DeviceAuthorization/GetDeviceRequest"
+	DeviceAuthorization_ApproveDevice_FullMethodName    = "/auth.DeviceAuthorization/ApproveDevice"
+	DeviceAuthorization_DenyDevice_FullMethodName       = "/auth.DeviceAuthorization/DenyDevice"
+)
+
+// DeviceAuthorizationClient is the client API for DeviceAuthorization service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// DeviceAuthorization implements the device authorization grant (RFC 8628)
+// for devices without a browser or with limited input.
+//
+// The device starts authorization with AuthorizeDevice and shows the user
+// code and verification URI to the user. The user signs in on another
+// device, checks the request with GetDeviceRequest and approves or denies
+// it. Meanwhile the device polls DeviceToken no more often than the
+// interval.
+//
+// AuthorizeDevice and DeviceToken are public. GetDeviceRequest,
+// ApproveDevice and DenyDevice require a token of the user, requests can't
+// be decided with impersonation tokens, API keys or service accounts.
+//
+// DeviceToken errors carry the RFC 8628 error codes as status messages:
+// "authorization_pending" (FailedPrecondition), "slow_down"
+// (ResourceExhausted), "access_denied" (PermissionDenied) and
+// "expired_token" (FailedPrecondition).
+type DeviceAuthorizationClient interface {
+	AuthorizeDevice(ctx context.Context, in *AuthorizeDeviceRequest, opts ...grpc.CallOption) (*AuthorizeDeviceResponse, error)
+	DeviceToken(ctx context.Context, in *DeviceTokenRequest, opts ...grpc.CallOption) (*DeviceTokenResponse, error)
+	GetDeviceRequest(ctx context.Context, in *GetDeviceRequestRequest, opts ...grpc.CallOption) (*GetDeviceRequestResponse, error)
+	ApproveDevice(ctx context.Context, in *ApproveDeviceRequest, opts ...grpc.CallOption) (*ApproveDeviceResponse, error)
+	DenyDevice(ctx context.Context, in *DenyDeviceRequest, opts ...grpc.CallOption) (*DenyDeviceResponse, error)
+}
+
+type deviceAuthorizationClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewDeviceAuthorizationClient(cc grpc.ClientConnInterface) DeviceAuthorizationClient {
+	return &deviceAuthorizationClient{cc}
+}
+
+func (c *deviceAuthorizationClient) AuthorizeDevice(ctx context.Context, in *AuthorizeDeviceRequest, opts ...grpc.CallOption) (*AuthorizeDeviceResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AuthorizeDeviceResponse)
+	err := c.cc.Invoke(ctx, DeviceAuthorization_AuthorizeDevice_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *deviceAuthorizationClient) DeviceToken(ctx context.Context, in *DeviceTokenRequest, opts ...grpc.CallOption) (*DeviceTokenResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeviceTokenResponse)
+	err := c.cc.Invoke(ctx, DeviceAuthorization_DeviceToken_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *deviceAuthorizationClient) GetDeviceRequest(ctx context.Context, in *GetDeviceRequestRequest, opts ...grpc.CallOption) (*GetDeviceRequestResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetDeviceRequestResponse)
+	err := c.cc.Invoke(ctx, DeviceAuthorization_GetDeviceRequest_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *deviceAuthorizationClient) ApproveDevice(ctx context.Context, in *ApproveDeviceRequest, opts ...grpc.CallOption) (*ApproveDeviceResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ApproveDeviceResponse)
+	err := c.cc.Invoke(ctx, DeviceAuthorization_ApproveDevice_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *deviceAuthorizationClient) DenyDevice(ctx context.Context, in *DenyDeviceRequest, opts ...grpc.CallOption) (*DenyDeviceResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DenyDeviceResponse)
+	err := c.cc.Invoke(ctx, DeviceAuthorization_DenyDevice_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// DeviceAuthorizationServer is the server API for DeviceAuthorization service.
+// All implementations must embed UnimplementedDeviceAuthorizationServer
+// for forward compatibility.
+//
+// DeviceAuthorization implements the device authorization grant (RFC 8628)
+// for devices without a browser or with limited input.
+//
+// The device starts authorization with AuthorizeDevice and shows the user
+// code and verification URI to the user. The user signs in on another
+// device, checks the request with GetDeviceRequest and approves or denies
+// it. Meanwhile the device polls DeviceToken no more often than the
+// interval.
+//
+// AuthorizeDevice and DeviceToken are public. GetDeviceRequest,
+// ApproveDevice and DenyDevice require a token of the user, requests can't
+// be decided with impersonation tokens, API keys or service accounts.
+//
+// DeviceToken errors carry the RFC 8628 error codes as status messages:
+// "authorization_pending" (FailedPrecondition), "slow_down"
+// (ResourceExhausted), "access_denied" (PermissionDenied) and
+// "expired_token" (FailedPrecondition).
+type DeviceAuthorizationServer interface {
+	AuthorizeDevice(context.Context, *AuthorizeDeviceRequest) (*AuthorizeDeviceResponse, error)
+	DeviceToken(context.Context, *DeviceTokenRequest) (*DeviceTokenResponse, error)
+	GetDeviceRequest(context.Context, *GetDeviceRequestRequest) (*GetDeviceRequestResponse, error)
+	ApproveDevice(context.Context, *ApproveDeviceRequest) (*ApproveDeviceResponse, error)
+	DenyDevice(context.Context, *DenyDeviceRequest) (*DenyDeviceResponse, error)
+	mustEmbedUnimplementedDeviceAuthorizationServer()
+}
+
+// UnimplementedDeviceAuthorizationServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedDeviceAuthorizationServer struct{}
+
+func (UnimplementedDeviceAuthorizationServer) AuthorizeDevice(context.Context, *AuthorizeDeviceRequest) (*AuthorizeDeviceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AuthorizeDevice not implemented")
+}
+func (UnimplementedDeviceAuthorizationServer) DeviceToken(context.Context, *DeviceTokenRequest) (*DeviceTokenResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeviceToken not implemented")
+}
+func (UnimplementedDeviceAuthorizationServer) GetDeviceRequest(context.Context, *GetDeviceRequestRequest) (*GetDeviceRequestResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDeviceRequest not implemented")
+}
+func (UnimplementedDeviceAuthorizationServer) ApproveDevice(context.Context, *ApproveDeviceRequest) (*ApproveDeviceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ApproveDevice not implemented")
+}
+func (UnimplementedDeviceAuthorizationServer) DenyDevice(context.Context, *DenyDeviceRequest) (*DenyDeviceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DenyDevice not implemented")
+}
+func (UnimplementedDeviceAuthorizationServer) mustEmbedUnimplementedDeviceAuthorizationServer() {}
+func (UnimplementedDeviceAuthorizationServer) testEmbeddedByValue()                             {}
+
+// UnsafeDeviceAuthorizationServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to DeviceAuthorizationServer will
+// result in compilation errors.
+type UnsafeDeviceAuthorizationServer interface {
+	mustEmbedUnimplementedDeviceAuthorizationServer()
+}
+
+func RegisterDeviceAuthorizationServer(s grpc.ServiceRegistrar, srv DeviceAuthorizationServer) {
+	// If the following call pancis, it indicates UnimplementedDeviceAuthorizationServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&DeviceAuthorization_ServiceDesc, srv)
+}
+
+func _DeviceAuthorization_AuthorizeDevice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AuthorizeDeviceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeviceAuthorizationServer).AuthorizeDevice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DeviceAuthorization_AuthorizeDevice_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeviceAuthorizationServer).AuthorizeDevice(ctx, req.(*AuthorizeDeviceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DeviceAuthorization_DeviceToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeviceTokenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeviceAuthorizationServer).DeviceToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DeviceAuthorization_DeviceToken_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeviceAuthorizationServer).DeviceToken(ctx, req.(*DeviceTokenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DeviceAuthorization_GetDeviceRequest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDeviceRequestRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeviceAuthorizationServer).GetDeviceRequest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DeviceAuthorization_GetDeviceRequest_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeviceAuthorizationServer).GetDeviceRequest(ctx, req.(*GetDeviceRequestRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DeviceAuthorization_ApproveDevice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ApproveDeviceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeviceAuthorizationServer).ApproveDevice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DeviceAuthorization_ApproveDevice_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeviceAuthorizationServer).ApproveDevice(ctx, req.(*ApproveDeviceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DeviceAuthorization_DenyDevice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DenyDeviceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeviceAuthorizationServer).DenyDevice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DeviceAuthorization_DenyDevice_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeviceAuthorizationServer).DenyDevice(ctx, req.(*DenyDeviceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// DeviceAuthorization_ServiceDesc is the grpc.ServiceDesc for DeviceAuthorization service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var DeviceAuthorization_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "auth.DeviceAuthorization",
+	HandlerType: (*DeviceAuthorizationServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "AuthorizeDevice",
+			Handler:    _DeviceAuthorization_AuthorizeDevice_Handler,
+		},
+		{
+			MethodName: "DeviceToken",
+			Handler:    _DeviceAuthorization_DeviceToken_Handler,
+		},
+		{
+			MethodName: "GetDeviceRequest",
+			Handler:    _DeviceAuthorization_GetDeviceRequest_Handler,
+		},
+		{
+			MethodName: "ApproveDevice",
+			Handler:    _DeviceAuthorization_ApproveDevice_Handler,
+		},
+		{
+			MethodName: "DenyDevice",
+			Handler:    _DeviceAuthorization_DenyDevice_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "sso/devices.proto",
+}
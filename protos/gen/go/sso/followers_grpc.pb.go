@@ -0,0 +1,573 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.28.1
+// source: sso/followers.proto
+
+package ssov1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Followers_Follow_FullMethodName                     = "/auth.Followers/Follow"
+	Followers_Unfollow_FullMethodName                   = "/auth.Followers/Unfollow"
+	Followers_ListFollowers_FullMethodName              = "/auth.Followers/ListFollowers"
+	Followers_ListFollowing_FullMethodName              = "/auth.Followers/ListFollowing"
+	Followers_GetFollowCounts_FullMethodName            = "/auth.Followers/GetFollowCounts"
+	Followers_GetRelationship_FullMethodName            = "/auth.Followers/GetRelationship"
+	Followers_GetRelationships_FullMethodName           = "/auth.Followers/GetRelationships"
+	Followers_SetAccountPrivacy_FullMethodName          = "/auth.Followers/SetAccountPrivacy"
+	Followers_ApproveFollowRequest_FullMethodName       = "/auth.Followers/ApproveFollowRequest"
+	Followers_DenyFollowRequest_FullMethodName          = "/auth.Followers/DenyFollowRequest"
+	Followers_ListIncomingFollowRequests_FullMethodName = "/auth.Followers/ListIncomingFollowRequests"
+	Followers_ListOutgoingFollowRequests_FullMethodName = "/auth.Followers/ListOutgoingFollowRequests"
+)
+
+// FollowersClient is the client API for Followers service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Followers lets users follow each other.
+//
+// Every RPC requires a token. Follow and Unfollow act on behalf of the
+// user the token is issued to and are idempotent: following a followed
+// user or unfollowing one who isn't followed succeeds. Users can't follow
+// themselves; service accounts can neither follow nor be followed.
+//
+// Following a private account sends a request the account approves or
+// denies; requests aren't listed or counted as follows. Unfollow cancels
+// requests too. Pending requests are approved when the account goes
+// public. Request RPCs act on requests to or of the token's user.
+//
+// Lists are paged with cursors, most recent follows first: pass
+// next_cursor of the response to get the next page until it's 0.
+// GetFollowCounts and GetRelationships take up to 500 users at once, to
+// render lists of users without a request per user.
+type FollowersClient interface {
+	Follow(ctx context.Context, in *FollowRequest, opts ...grpc.CallOption) (*FollowResponse, error)
+	Unfollow(ctx context.Context, in *UnfollowRequest, opts ...grpc.CallOption) (*UnfollowResponse, error)
+	ListFollowers(ctx context.Context, in *ListFollowersRequest, opts ...grpc.CallOption) (*ListFollowersResponse, error)
+	ListFollowing(ctx context.Context, in *ListFollowingRequest, opts ...grpc.CallOption) (*ListFollowingResponse, error)
+	GetFollowCounts(ctx context.Context, in *GetFollowCountsRequest, opts ...grpc.CallOption) (*GetFollowCountsResponse, error)
+	GetRelationship(ctx context.Context, in *GetRelationshipRequest, opts ...grpc.CallOption) (*GetRelationshipResponse, error)
+	GetRelationships(ctx context.Context, in *GetRelationshipsRequest, opts ...grpc.CallOption) (*GetRelationshipsResponse, error)
+	SetAccountPrivacy(ctx context.Context, in *SetAccountPrivacyRequest, opts ...grpc.CallOption) (*SetAccountPrivacyResponse, error)
+	ApproveFollowRequest(ctx context.Context, in *ApproveFollowRequestRequest, opts ...grpc.CallOption) (*ApproveFollowRequestResponse, error)
+	DenyFollowRequest(ctx context.Context, in *DenyFollowRequestRequest, opts ...grpc.CallOption) (*DenyFollowRequestResponse, error)
+	ListIncomingFollowRequests(ctx context.Context, in *ListIncomingFollowRequestsRequest, opts ...grpc.CallOption) (*ListIncomingFollowRequestsResponse, error)
+	ListOutgoingFollowRequests(ctx context.Context, in *ListOutgoingFollowRequestsRequest, opts ...grpc.CallOption) (*ListOutgoingFollowRequestsResponse, error)
+}
+
+type followersClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewFollowersClient(cc grpc.ClientConnInterface) FollowersClient {
+	return &followersClient{cc}
+}
+
+func (c *followersClient) Follow(ctx context.Context, in *FollowRequest, opts ...grpc.CallOption) (*FollowResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FollowResponse)
+	err := c.cc.Invoke(ctx, Followers_Follow_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *followersClient) Unfollow(ctx context.Context, in *UnfollowRequest, opts ...grpc.CallOption) (*UnfollowResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UnfollowResponse)
+	err := c.cc.Invoke(ctx, Followers_Unfollow_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *followersClient) ListFollowers(ctx context.Context, in *ListFollowersRequest, opts ...grpc.CallOption) (*ListFollowersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListFollowersResponse)
+	err := c.cc.Invoke(ctx, Followers_ListFollowers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *followersClient) ListFollowing(ctx context.Context, in *ListFollowingRequest, opts ...grpc.CallOption) (*ListFollowingResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListFollowingResponse)
+	err := c.cc.Invoke(ctx, Followers_ListFollowing_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *followersClient) GetFollowCounts(ctx context.Context, in *GetFollowCountsRequest, opts ...grpc.CallOption) (*GetFollowCountsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetFollowCountsResponse)
+	err := c.cc.Invoke(ctx, Followers_GetFollowCounts_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *followersClient) GetRelationship(ctx context.Context, in *GetRelationshipRequest, opts ...grpc.CallOption) (*GetRelationshipResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetRelationshipResponse)
+	err := c.cc.Invoke(ctx, Followers_GetRelationship_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *followersClient) GetRelationships(ctx context.Context, in *GetRelationshipsRequest, opts ...grpc.CallOption) (*GetRelationshipsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetRelationshipsResponse)
+	err := c.cc.Invoke(ctx, Followers_GetRelationships_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *followersClient) SetAccountPrivacy(ctx context.Context, in *SetAccountPrivacyRequest, opts ...grpc.CallOption) (*SetAccountPrivacyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetAccountPrivacyResponse)
+	err := c.cc.Invoke(ctx, Followers_SetAccountPrivacy_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *followersClient) ApproveFollowRequest(ctx context.Context, in *ApproveFollowRequestRequest, opts ...grpc.CallOption) (*ApproveFollowRequestResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ApproveFollowRequestResponse)
+	err := c.cc.Invoke(ctx, Followers_ApproveFollowRequest_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *followersClient) DenyFollowRequest(ctx context.Context, in *DenyFollowRequestRequest, opts ...grpc.CallOption) (*DenyFollowRequestResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DenyFollowRequestResponse)
+	err := c.cc.Invoke(ctx, Followers_DenyFollowRequest_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *followersClient) ListIncomingFollowRequests(ctx context.Context, in *ListIncomingFollowRequestsRequest, opts ...grpc.CallOption) (*ListIncomingFollowRequestsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListIncomingFollowRequestsResponse)
+	err := c.cc.Invoke(ctx, Followers_ListIncomingFollowRequests_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *followersClient) ListOutgoingFollowRequests(ctx context.Context, in *ListOutgoingFollowRequestsRequest, opts ...grpc.CallOption) (*ListOutgoingFollowRequestsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListOutgoingFollowRequestsResponse)
+	err := c.cc.Invoke(ctx, Followers_ListOutgoingFollowRequests_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// FollowersServer is the server API for Followers service.
+// All implementations must embed UnimplementedFollowersServer
+// for forward compatibility.
+//
+// Followers lets users follow each other.
+//
+// Every RPC requires a token. Follow and Unfollow act on behalf of the
+// user the token is issued to and are idempotent: following a followed
+// user or unfollowing one who isn't followed succeeds. Users can't follow
+// themselves; service accounts can neither follow nor be followed.
+//
+// Following a private account sends a request the account approves or
+// denies; requests aren't listed or counted as follows. Unfollow cancels
+// requests too. Pending requests are approved when the account goes
+// public. Request RPCs act on requests to or of the token's user.
+//
+// Lists are paged with cursors, most recent follows first: pass
+// next_cursor of the response to get the next page until it's 0.
+// GetFollowCounts and GetRelationships take up to 500 users at once, to
+// render lists of users without a request per user.
+type FollowersServer interface {
+	Follow(context.Context, *FollowRequest) (*FollowResponse, error)
+	Unfollow(context.Context, *UnfollowRequest) (*UnfollowResponse, error)
+	ListFollowers(context.Context, *ListFollowersRequest) (*ListFollowersResponse, error)
+	ListFollowing(context.Context, *ListFollowingRequest) (*ListFollowingResponse, error)
+	GetFollowCounts(context.Context, *GetFollowCountsRequest) (*GetFollowCountsResponse, error)
+	GetRelationship(context.Context, *GetRelationshipRequest) (*GetRelationshipResponse, error)
+	GetRelationships(context.Context, *GetRelationshipsRequest) (*GetRelationshipsResponse, error)
+	SetAccountPrivacy(context.Context, *SetAccountPrivacyRequest) (*SetAccountPrivacyResponse, error)
+	ApproveFollowRequest(context.Context, *ApproveFollowRequestRequest) (*ApproveFollowRequestResponse, error)
+	DenyFollowRequest(context.Context, *DenyFollowRequestRequest) (*DenyFollowRequestResponse, error)
+	ListIncomingFollowRequests(context.Context, *ListIncomingFollowRequestsRequest) (*ListIncomingFollowRequestsResponse, error)
+	ListOutgoingFollowRequests(context.Context, *ListOutgoingFollowRequestsRequest) (*ListOutgoingFollowRequestsResponse, error)
+	mustEmbedUnimplementedFollowersServer()
+}
+
+// UnimplementedFollowersServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedFollowersServer struct{}
+
+func (UnimplementedFollowersServer) Follow(context.Context, *FollowRequest) (*FollowResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Follow not implemented")
+}
+func (UnimplementedFollowersServer) Unfollow(context.Context, *UnfollowRequest) (*UnfollowResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Unfollow not implemented")
+}
+func (UnimplementedFollowersServer) ListFollowers(context.Context, *ListFollowersRequest) (*ListFollowersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListFollowers not implemented")
+}
+func (UnimplementedFollowersServer) ListFollowing(context.Context, *ListFollowingRequest) (*ListFollowingResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListFollowing not implemented")
+}
+func (UnimplementedFollowersServer) GetFollowCounts(context.Context, *GetFollowCountsRequest) (*GetFollowCountsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetFollowCounts not implemented")
+}
+func (UnimplementedFollowersServer) GetRelationship(context.Context, *GetRelationshipRequest) (*GetRelationshipResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRelationship not implemented")
+}
+func (UnimplementedFollowersServer) GetRelationships(context.Context, *GetRelationshipsRequest) (*GetRelationshipsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRelationships not implemented")
+}
+func (UnimplementedFollowersServer) SetAccountPrivacy(context.Context, *SetAccountPrivacyRequest) (*SetAccountPrivacyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetAccountPrivacy not implemented")
+}
+func (UnimplementedFollowersServer) ApproveFollowRequest(context.Context, *ApproveFollowRequestRequest) (*ApproveFollowRequestResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ApproveFollowRequest not implemented")
+}
+func (UnimplementedFollowersServer) DenyFollowRequest(context.Context, *DenyFollowRequestRequest) (*DenyFollowRequestResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DenyFollowRequest not implemented")
+}
+func (UnimplementedFollowersServer) ListIncomingFollowRequests(context.Context, *ListIncomingFollowRequestsRequest) (*ListIncomingFollowRequestsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListIncomingFollowRequests not implemented")
+}
+func (UnimplementedFollowersServer) ListOutgoingFollowRequests(context.Context, *ListOutgoingFollowRequestsRequest) (*ListOutgoingFollowRequestsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListOutgoingFollowRequests not implemented")
+}
+func (UnimplementedFollowersServer) mustEmbedUnimplementedFollowersServer() {}
+func (UnimplementedFollowersServer) testEmbeddedByValue()                   {}
+
+// UnsafeFollowersServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to FollowersServer will
+// result in compilation errors.
+type UnsafeFollowersServer interface {
+	mustEmbedUnimplementedFollowersServer()
+}
+
+func RegisterFollowersServer(s grpc.ServiceRegistrar, srv FollowersServer) {
+	// If the following call pancis, it indicates UnimplementedFollowersServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Followers_ServiceDesc, srv)
+}
+
+func _Followers_Follow_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FollowRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FollowersServer).Follow(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Followers_Follow_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FollowersServer).Follow(ctx, req.(*FollowRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Followers_Unfollow_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnfollowRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FollowersServer).Unfollow(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Followers_Unfollow_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FollowersServer).Unfollow(ctx, req.(*UnfollowRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Followers_ListFollowers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListFollowersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FollowersServer).ListFollowers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Followers_ListFollowers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FollowersServer).ListFollowers(ctx, req.(*ListFollowersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Followers_ListFollowing_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListFollowingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FollowersServer).ListFollowing(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Followers_ListFollowing_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FollowersServer).ListFollowing(ctx, req.(*ListFollowingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Followers_GetFollowCounts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetFollowCountsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FollowersServer).GetFollowCounts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Followers_GetFollowCounts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FollowersServer).GetFollowCounts(ctx, req.(*GetFollowCountsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Followers_GetRelationship_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRelationshipRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FollowersServer).GetRelationship(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Followers_GetRelationship_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FollowersServer).GetRelationship(ctx, req.(*GetRelationshipRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Followers_GetRelationships_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRelationshipsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FollowersServer).GetRelationships(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Followers_GetRelationships_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FollowersServer).GetRelationships(ctx, req.(*GetRelationshipsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Followers_SetAccountPrivacy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetAccountPrivacyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FollowersServer).SetAccountPrivacy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Followers_SetAccountPrivacy_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FollowersServer).SetAccountPrivacy(ctx, req.(*SetAccountPrivacyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Followers_ApproveFollowRequest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ApproveFollowRequestRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FollowersServer).ApproveFollowRequest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Followers_ApproveFollowRequest_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FollowersServer).ApproveFollowRequest(ctx, req.(*ApproveFollowRequestRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Followers_DenyFollowRequest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DenyFollowRequestRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FollowersServer).DenyFollowRequest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Followers_DenyFollowRequest_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FollowersServer).DenyFollowRequest(ctx, req.(*DenyFollowRequestRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Followers_ListIncomingFollowRequests_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListIncomingFollowRequestsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FollowersServer).ListIncomingFollowRequests(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Followers_ListIncomingFollowRequests_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FollowersServer).ListIncomingFollowRequests(ctx, req.(*ListIncomingFollowRequestsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Followers_ListOutgoingFollowRequests_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListOutgoingFollowRequestsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FollowersServer).ListOutgoingFollowRequests(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Followers_ListOutgoingFollowRequests_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FollowersServer).ListOutgoingFollowRequests(ctx, req.(*ListOutgoingFollowRequestsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Followers_ServiceDesc is the grpc.ServiceDesc for Followers service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Followers_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "auth.Followers",
+	HandlerType: (*FollowersServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Follow",
+			Handler:    _Followers_Follow_Handler,
+		},
+		{
+			MethodName: "Unfollow",
+			Handler:    _Followers_Unfollow_Handler,
+		},
+		{
+			MethodName: "ListFollowers",
+			Handler:    _Followers_ListFollowers_Handler,
+		},
+		{
+			MethodName: "ListFollowing",
+			Handler:    _Followers_ListFollowing_Handler,
+		},
+		{
+			MethodName: "GetFollowCounts",
+			Handler:    _Followers_GetFollowCounts_Handler,
+		},
+		{
+			MethodName: "GetRelationship",
+			Handler:    _Followers_GetRelationship_Handler,
+		},
+		{
+			MethodName: "GetRelationships",
+			Handler:    _Followers_GetRelationships_Handler,
+		},
+		{
+			MethodName: "SetAccountPrivacy",
+			Handler:    _Followers_SetAccountPrivacy_Handler,
+		},
+		{
+			MethodName: "ApproveFollowRequest",
+			Handler:    _Followers_ApproveFollowRequest_Handler,
+		},
+		{
+			MethodName: "DenyFollowRequest",
+			Handler:    _Followers_DenyFollowRequest_Handler,
+		},
+		{
+			MethodName: "ListIncomingFollowRequests",
+			Handler:    _Followers_ListIncomingFollowRequests_Handler,
+		},
+		{
+			MethodName: "ListOutgoingFollowRequests",
+			Handler:    _Followers_ListOutgoingFollowRequests_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "sso/followers.proto",
+}
@@ -0,0 +1,177 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.28.1
+// source: sso/magiclinks.proto
+
+package ssov1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	MagicLinks_RequestMagicLink_FullMethodName = "/auth.MagicLinks/RequestMagicLink"
+	MagicLinks_ConsumeMagicLink_FullMethodName = "/auth.MagicLinks/ConsumeMagicLink"
+)
+
+// MagicLinksClient is the client API for MagicLinks service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// MagicLinks logs users in without password, with links emailed to them.
+//
+// RequestMagicLink emails the user a link to the page configured on the
+// server with a token appended. The page passes the token to
+// ConsumeMagicLink, which returns token for the app. Links work once,
+// expire shortly and are bound to the app they were requested for;
+// requesting a new link voids the former one. The app must allow the
+// "magic_link" grant type. All RPCs are public.
+type MagicLinksClient interface {
+	RequestMagicLink(ctx context.Context, in *RequestMagicLinkRequest, opts ...grpc.CallOption) (*RequestMagicLinkResponse, error)
+	ConsumeMagicLink(ctx context.Context, in *ConsumeMagicLinkRequest, opts ...grpc.CallOption) (*ConsumeMagicLinkResponse, error)
+}
+
+type magicLinksClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewMagicLinksClient(cc grpc.ClientConnInterface) MagicLinksClient {
+	return &magicLinksClient{cc}
+}
+
+func (c *magicLinksClient) RequestMagicLink(ctx context.Context, in *RequestMagicLinkRequest, opts ...grpc.CallOption) (*RequestMagicLinkResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RequestMagicLinkResponse)
+	err := c.cc.Invoke(ctx, MagicLinks_RequestMagicLink_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *magicLinksClient) ConsumeMagicLink(ctx context.Context, in *ConsumeMagicLinkRequest, opts ...grpc.CallOption) (*ConsumeMagicLinkResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ConsumeMagicLinkResponse)
+	err := c.cc.Invoke(ctx, MagicLinks_ConsumeMagicLink_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// MagicLinksServer is the server API for MagicLinks service.
+// All implementations must embed UnimplementedMagicLinksServer
+// for forward compatibility.
+//
+// MagicLinks logs users in without password, with links emailed to them.
+//
+// RequestMagicLink emails the user a link to the page configured on the
+// server with a token appended. The page passes the token to
+// ConsumeMagicLink, which returns token for the app. Links work once,
+// expire shortly and are bound to the app they were requested for;
+// requesting a new link voids the former one. The app must allow the
+// "magic_link" grant type. All RPCs are public.
+type MagicLinksServer interface {
+	RequestMagicLink(context.Context, *RequestMagicLinkRequest) (*RequestMagicLinkResponse, error)
+	ConsumeMagicLink(context.Context, *ConsumeMagicLinkRequest) (*ConsumeMagicLinkResponse, error)
+	mustEmbedUnimplementedMagicLinksServer()
+}
+
+// UnimplementedMagicLinksServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedMagicLinksServer struct{}
+
+func (UnimplementedMagicLinksServer) RequestMagicLink(context.Context, *RequestMagicLinkRequest) (*RequestMagicLinkResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RequestMagicLink not implemented")
+}
+func (UnimplementedMagicLinksServer) ConsumeMagicLink(context.Context, *ConsumeMagicLinkRequest) (*ConsumeMagicLinkResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ConsumeMagicLink not implemented")
+}
+func (UnimplementedMagicLinksServer) mustEmbedUnimplementedMagicLinksServer() {}
+func (UnimplementedMagicLinksServer) testEmbeddedByValue()                    {}
+
+// UnsafeMagicLinksServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to MagicLinksServer will
+// result in compilation errors.
+type UnsafeMagicLinksServer interface {
+	mustEmbedUnimplementedMagicLinksServer()
+}
+
+func RegisterMagicLinksServer(s grpc.ServiceRegistrar, srv MagicLinksServer) {
+	// If the following call pancis, it indicates UnimplementedMagicLinksServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&MagicLinks_ServiceDesc, srv)
+}
+
+func _MagicLinks_RequestMagicLink_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RequestMagicLinkRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MagicLinksServer).RequestMagicLink(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MagicLinks_RequestMagicLink_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MagicLinksServer).RequestMagicLink(ctx, req.(*RequestMagicLinkRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MagicLinks_ConsumeMagicLink_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConsumeMagicLinkRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MagicLinksServer).ConsumeMagicLink(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MagicLinks_ConsumeMagicLink_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MagicLinksServer).ConsumeMagicLink(ctx, req.(*ConsumeMagicLinkRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// MagicLinks_ServiceDesc is the grpc.ServiceDesc for MagicLinks service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var MagicLinks_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "auth.MagicLinks",
+	HandlerType: (*MagicLinksServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "RequestMagicLink",
+			Handler:    _MagicLinks_RequestMagicLink_Handler,
+		},
+		{
+			MethodName: "ConsumeMagicLink",
+			Handler:    _MagicLinks_ConsumeMagicLink_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "sso/magiclinks.proto",
+}
@@ -0,0 +1,1272 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.34.2
+// 	protoc        v5.28.1
+// source: sso/otp.proto
+
+package ssov1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type OTPFactor struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id          int64  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Channel     string `protobuf:"bytes,2,opt,name=channel,proto3" json:"channel,omitempty"`                       // "email" or "sms".
+	Destination string `protobuf:"bytes,3,opt,name=destination,proto3" json:"destination,omitempty"`               // Email address or phone number in E.164 format.
+	CreatedAt   int64  `protobuf:"varint,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"` // Unix time.
+}
+
+func (x *OTPFactor) Reset() {
+	*x = OTPFactor{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_sso_otp_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *OTPFactor) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OTPFactor) ProtoMessage() {}
+
+func (x *OTPFactor) ProtoReflect() protoreflect.Message {
+	mi := &file_sso_otp_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OTPFactor.ProtoReflect.Descriptor instead.
+func (*OTPFactor) Descriptor() ([]byte, []int) {
+	return file_sso_otp_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *OTPFactor) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *OTPFactor) GetChannel() string {
+	if x != nil {
+		return x.Channel
+	}
+	return ""
+}
+
+func (x *OTPFactor) GetDestination() string {
+	if x != nil {
+		return x.Destination
+	}
+	return ""
+}
+
+func (x *OTPFactor) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+// SentOTP describes the code sent for a challenge.
+type SentOTP struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Channel     string `protobuf:"bytes,1,opt,name=channel,proto3" json:"channel,omitempty"`
+	Destination string `protobuf:"bytes,2,opt,name=destination,proto3" json:"destination,omitempty"`               // Masked, except on enrollment.
+	ExpiresAt   int64  `protobuf:"varint,3,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"` // Unix time the code expires.
+	ResendAt    int64  `protobuf:"varint,4,opt,name=resend_at,json=resendAt,proto3" json:"resend_at,omitempty"`    // Unix time another code can be sent, 0 if no more can.
+}
+
+func (x *SentOTP) Reset() {
+	*x = SentOTP{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_sso_otp_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SentOTP) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SentOTP) ProtoMessage() {}
+
+func (x *SentOTP) ProtoReflect() protoreflect.Message {
+	mi := &file_sso_otp_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SentOTP.ProtoReflect.Descriptor instead.
+func (*SentOTP) Descriptor() ([]byte, []int) {
+	return file_sso_otp_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *SentOTP) GetChannel() string {
+	if x != nil {
+		return x.Channel
+	}
+	return ""
+}
+
+func (x *SentOTP) GetDestination() string {
+	if x != nil {
+		return x.Destination
+	}
+	return ""
+}
+
+func (x *SentOTP) GetExpiresAt() int64 {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return 0
+}
+
+func (x *SentOTP) GetResendAt() int64 {
+	if x != nil {
+		return x.ResendAt
+	}
+	return 0
+}
+
+type StartOTPLoginRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Email          string   `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
+	Channel        string   `protobuf:"bytes,2,opt,name=channel,proto3" json:"channel,omitempty"` // "email" or "sms".
+	AppId          int32    `protobuf:"varint,3,opt,name=app_id,json=appId,proto3" json:"app_id,omitempty"`
+	OrganizationId int64    `protobuf:"varint,4,opt,name=organization_id,json=organizationId,proto3" json:"organization_id,omitempty"` // Optional, as on Auth.Login.
+	Scopes         []string `protobuf:"bytes,5,rep,name=scopes,proto3" json:"scopes,omitempty"`                                        // Optional, checked as on Auth.Login.
+}
+
+func (x *StartOTPLoginRequest) Reset() {
+	*x = StartOTPLoginRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_sso_otp_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StartOTPLoginRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StartOTPLoginRequest) ProtoMessage() {}
+
+func (x *StartOTPLoginRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sso_otp_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StartOTPLoginRequest.ProtoReflect.Descriptor instead.
+func (*StartOTPLoginRequest) Descriptor() ([]byte, []int) {
+	return file_sso_otp_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *StartOTPLoginRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *StartOTPLoginRequest) GetChannel() string {
+	if x != nil {
+		return x.Channel
+	}
+	return ""
+}
+
+func (x *StartOTPLoginRequest) GetAppId() int32 {
+	if x != nil {
+		return x.AppId
+	}
+	return 0
+}
+
+func (x *StartOTPLoginRequest) GetOrganizationId() int64 {
+	if x != nil {
+		return x.OrganizationId
+	}
+	return 0
+}
+
+func (x *StartOTPLoginRequest) GetScopes() []string {
+	if x != nil {
+		return x.Scopes
+	}
+	return nil
+}
+
+// The response is the same whether or not the email is registered and has
+// a factor of the channel.
+type StartOTPLoginResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Challenge string `protobuf:"bytes,1,opt,name=challenge,proto3" json:"challenge,omitempty"`
+}
+
+func (x *StartOTPLoginResponse) Reset() {
+	*x = StartOTPLoginResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_sso_otp_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StartOTPLoginResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StartOTPLoginResponse) ProtoMessage() {}
+
+func (x *StartOTPLoginResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_sso_otp_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StartOTPLoginResponse.ProtoReflect.Descriptor instead.
+func (*StartOTPLoginResponse) Descriptor() ([]byte, []int) {
+	return file_sso_otp_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *StartOTPLoginResponse) GetChallenge() string {
+	if x != nil {
+		return x.Challenge
+	}
+	return ""
+}
+
+type SendOTPRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Challenge string `protobuf:"bytes,1,opt,name=challenge,proto3" json:"challenge,omitempty"`
+	FactorId  int64  `protobuf:"varint,2,opt,name=factor_id,json=factorId,proto3" json:"factor_id,omitempty"` // Factor to send the code to, required for the first code of second factor challenges.
+}
+
+func (x *SendOTPRequest) Reset() {
+	*x = SendOTPRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_sso_otp_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SendOTPRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SendOTPRequest) ProtoMessage() {}
+
+func (x *SendOTPRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sso_otp_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SendOTPRequest.ProtoReflect.Descriptor instead.
+func (*SendOTPRequest) Descriptor() ([]byte, []int) {
+	return file_sso_otp_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *SendOTPRequest) GetChallenge() string {
+	if x != nil {
+		return x.Challenge
+	}
+	return ""
+}
+
+func (x *SendOTPRequest) GetFactorId() int64 {
+	if x != nil {
+		return x.FactorId
+	}
+	return 0
+}
+
+type SendOTPResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Sent *SentOTP `protobuf:"bytes,1,opt,name=sent,proto3" json:"sent,omitempty"`
+}
+
+func (x *SendOTPResponse) Reset() {
+	*x = SendOTPResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_sso_otp_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SendOTPResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SendOTPResponse) ProtoMessage() {}
+
+func (x *SendOTPResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_sso_otp_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SendOTPResponse.ProtoReflect.Descriptor instead.
+func (*SendOTPResponse) Descriptor() ([]byte, []int) {
+	return file_sso_otp_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *SendOTPResponse) GetSent() *SentOTP {
+	if x != nil {
+		return x.Sent
+	}
+	return nil
+}
+
+type VerifyOTPRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Challenge string `protobuf:"bytes,1,opt,name=challenge,proto3" json:"challenge,omitempty"`
+	Code      string `protobuf:"bytes,2,opt,name=code,proto3" json:"code,omitempty"`
+}
+
+func (x *VerifyOTPRequest) Reset() {
+	*x = VerifyOTPRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_sso_otp_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *VerifyOTPRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyOTPRequest) ProtoMessage() {}
+
+func (x *VerifyOTPRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sso_otp_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyOTPRequest.ProtoReflect.Descriptor instead.
+func (*VerifyOTPRequest) Descriptor() ([]byte, []int) {
+	return file_sso_otp_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *VerifyOTPRequest) GetChallenge() string {
+	if x != nil {
+		return x.Challenge
+	}
+	return ""
+}
+
+func (x *VerifyOTPRequest) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+type VerifyOTPResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Token string `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"` // Token for the app, as returned by Auth.Login.
+}
+
+func (x *VerifyOTPResponse) Reset() {
+	*x = VerifyOTPResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_sso_otp_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *VerifyOTPResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyOTPResponse) ProtoMessage() {}
+
+func (x *VerifyOTPResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_sso_otp_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyOTPResponse.ProtoReflect.Descriptor instead.
+func (*VerifyOTPResponse) Descriptor() ([]byte, []int) {
+	return file_sso_otp_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *VerifyOTPResponse) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+// EnrollOTPFactorRequest sends code to the destination, which is added as
+// factor once confirmed. It replaces the former factor of the channel.
+type EnrollOTPFactorRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Channel     string `protobuf:"bytes,1,opt,name=channel,proto3" json:"channel,omitempty"`         // "email" or "sms".
+	Destination string `protobuf:"bytes,2,opt,name=destination,proto3" json:"destination,omitempty"` // Email address or phone number in E.164 format.
+}
+
+func (x *EnrollOTPFactorRequest) Reset() {
+	*x = EnrollOTPFactorRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_sso_otp_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EnrollOTPFactorRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EnrollOTPFactorRequest) ProtoMessage() {}
+
+func (x *EnrollOTPFactorRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sso_otp_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EnrollOTPFactorRequest.ProtoReflect.Descriptor instead.
+func (*EnrollOTPFactorRequest) Descriptor() ([]byte, []int) {
+	return file_sso_otp_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *EnrollOTPFactorRequest) GetChannel() string {
+	if x != nil {
+		return x.Channel
+	}
+	return ""
+}
+
+func (x *EnrollOTPFactorRequest) GetDestination() string {
+	if x != nil {
+		return x.Destination
+	}
+	return ""
+}
+
+type EnrollOTPFactorResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Challenge string   `protobuf:"bytes,1,opt,name=challenge,proto3" json:"challenge,omitempty"`
+	Sent      *SentOTP `protobuf:"bytes,2,opt,name=sent,proto3" json:"sent,omitempty"`
+}
+
+func (x *EnrollOTPFactorResponse) Reset() {
+	*x = EnrollOTPFactorResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_sso_otp_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EnrollOTPFactorResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EnrollOTPFactorResponse) ProtoMessage() {}
+
+func (x *EnrollOTPFactorResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_sso_otp_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EnrollOTPFactorResponse.ProtoReflect.Descriptor instead.
+func (*EnrollOTPFactorResponse) Descriptor() ([]byte, []int) {
+	return file_sso_otp_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *EnrollOTPFactorResponse) GetChallenge() string {
+	if x != nil {
+		return x.Challenge
+	}
+	return ""
+}
+
+func (x *EnrollOTPFactorResponse) GetSent() *SentOTP {
+	if x != nil {
+		return x.Sent
+	}
+	return nil
+}
+
+type ConfirmOTPFactorRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Challenge string `protobuf:"bytes,1,opt,name=challenge,proto3" json:"challenge,omitempty"`
+	Code      string `protobuf:"bytes,2,opt,name=code,proto3" json:"code,omitempty"`
+}
+
+func (x *ConfirmOTPFactorRequest) Reset() {
+	*x = ConfirmOTPFactorRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_sso_otp_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ConfirmOTPFactorRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfirmOTPFactorRequest) ProtoMessage() {}
+
+func (x *ConfirmOTPFactorRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sso_otp_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfirmOTPFactorRequest.ProtoReflect.Descriptor instead.
+func (*ConfirmOTPFactorRequest) Descriptor() ([]byte, []int) {
+	return file_sso_otp_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ConfirmOTPFactorRequest) GetChallenge() string {
+	if x != nil {
+		return x.Challenge
+	}
+	return ""
+}
+
+func (x *ConfirmOTPFactorRequest) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+type ConfirmOTPFactorResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Factor *OTPFactor `protobuf:"bytes,1,opt,name=factor,proto3" json:"factor,omitempty"`
+}
+
+func (x *ConfirmOTPFactorResponse) Reset() {
+	*x = ConfirmOTPFactorResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_sso_otp_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ConfirmOTPFactorResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfirmOTPFactorResponse) ProtoMessage() {}
+
+func (x *ConfirmOTPFactorResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_sso_otp_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfirmOTPFactorResponse.ProtoReflect.Descriptor instead.
+func (*ConfirmOTPFactorResponse) Descriptor() ([]byte, []int) {
+	return file_sso_otp_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ConfirmOTPFactorResponse) GetFactor() *OTPFactor {
+	if x != nil {
+		return x.Factor
+	}
+	return nil
+}
+
+type ListOTPFactorsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *ListOTPFactorsRequest) Reset() {
+	*x = ListOTPFactorsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_sso_otp_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListOTPFactorsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListOTPFactorsRequest) ProtoMessage() {}
+
+func (x *ListOTPFactorsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sso_otp_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListOTPFactorsRequest.ProtoReflect.Descriptor instead.
+func (*ListOTPFactorsRequest) Descriptor() ([]byte, []int) {
+	return file_sso_otp_proto_rawDescGZIP(), []int{12}
+}
+
+type ListOTPFactorsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Factors []*OTPFactor `protobuf:"bytes,1,rep,name=factors,proto3" json:"factors,omitempty"`
+}
+
+func (x *ListOTPFactorsResponse) Reset() {
+	*x = ListOTPFactorsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_sso_otp_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListOTPFactorsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListOTPFactorsResponse) ProtoMessage() {}
+
+func (x *ListOTPFactorsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_sso_otp_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListOTPFactorsResponse.ProtoReflect.Descriptor instead.
+func (*ListOTPFactorsResponse) Descriptor() ([]byte, []int) {
+	return file_sso_otp_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ListOTPFactorsResponse) GetFactors() []*OTPFactor {
+	if x != nil {
+		return x.Factors
+	}
+	return nil
+}
+
+type DeleteOTPFactorRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id int64 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *DeleteOTPFactorRequest) Reset() {
+	*x = DeleteOTPFactorRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_sso_otp_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeleteOTPFactorRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteOTPFactorRequest) ProtoMessage() {}
+
+func (x *DeleteOTPFactorRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sso_otp_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteOTPFactorRequest.ProtoReflect.Descriptor instead.
+func (*DeleteOTPFactorRequest) Descriptor() ([]byte, []int) {
+	return file_sso_otp_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *DeleteOTPFactorRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type DeleteOTPFactorResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *DeleteOTPFactorResponse) Reset() {
+	*x = DeleteOTPFactorResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_sso_otp_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeleteOTPFactorResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteOTPFactorResponse) ProtoMessage() {}
+
+func (x *DeleteOTPFactorResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_sso_otp_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteOTPFactorResponse.ProtoReflect.Descriptor instead.
+func (*DeleteOTPFactorResponse) Descriptor() ([]byte, []int) {
+	return file_sso_otp_proto_rawDescGZIP(), []int{15}
+}
+
+var File_sso_otp_proto protoreflect.FileDescriptor
+
+var file_sso_otp_proto_rawDesc = []byte{
+	0x0a, 0x0d, 0x73, 0x73, 0x6f, 0x2f, 0x6f, 0x74, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12,
+	0x04, 0x61, 0x75, 0x74, 0x68, 0x22, 0x76, 0x0a, 0x09, 0x4f, 0x54, 0x50, 0x46, 0x61, 0x63, 0x74,
+	0x6f, 0x72, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x02,
+	0x69, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x07, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x12, 0x20, 0x0a, 0x0b,
+	0x64, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0b, 0x64, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1d,
+	0x0a, 0x0a, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x09, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x22, 0x81, 0x01,
+	0x0a, 0x07, 0x53, 0x65, 0x6e, 0x74, 0x4f, 0x54, 0x50, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x68, 0x61,
+	0x6e, 0x6e, 0x65, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x63, 0x68, 0x61, 0x6e,
+	0x6e, 0x65, 0x6c, 0x12, 0x20, 0x0a, 0x0b, 0x64, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x73, 0x74, 0x69, 0x6e,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1d, 0x0a, 0x0a, 0x65, 0x78, 0x70, 0x69, 0x72, 0x65, 0x73,
+	0x5f, 0x61, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x65, 0x78, 0x70, 0x69, 0x72,
+	0x65, 0x73, 0x41, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x72, 0x65, 0x73, 0x65, 0x6e, 0x64, 0x5f, 0x61,
+	0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x72, 0x65, 0x73, 0x65, 0x6e, 0x64, 0x41,
+	0x74, 0x22, 0x9e, 0x01, 0x0a, 0x14, 0x53, 0x74, 0x61, 0x72, 0x74, 0x4f, 0x54, 0x50, 0x4c, 0x6f,
+	0x67, 0x69, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x6d,
+	0x61, 0x69, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x6d, 0x61, 0x69, 0x6c,
+	0x12, 0x18, 0x0a, 0x07, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x07, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x12, 0x15, 0x0a, 0x06, 0x61, 0x70,
+	0x70, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x61, 0x70, 0x70, 0x49,
+	0x64, 0x12, 0x27, 0x0a, 0x0f, 0x6f, 0x72, 0x67, 0x61, 0x6e, 0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x5f, 0x69, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0e, 0x6f, 0x72, 0x67, 0x61,
+	0x6e, 0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x63,
+	0x6f, 0x70, 0x65, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x09, 0x52, 0x06, 0x73, 0x63, 0x6f, 0x70,
+	0x65, 0x73, 0x22, 0x35, 0x0a, 0x15, 0x53, 0x74, 0x61, 0x72, 0x74, 0x4f, 0x54, 0x50, 0x4c, 0x6f,
+	0x67, 0x69, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x63,
+	0x68, 0x61, 0x6c, 0x6c, 0x65, 0x6e, 0x67, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09,
+	0x63, 0x68, 0x61, 0x6c, 0x6c, 0x65, 0x6e, 0x67, 0x65, 0x22, 0x4b, 0x0a, 0x0e, 0x53, 0x65, 0x6e,
+	0x64, 0x4f, 0x54, 0x50, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x63,
+	0x68, 0x61, 0x6c, 0x6c, 0x65, 0x6e, 0x67, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09,
+	0x63, 0x68, 0x61, 0x6c, 0x6c, 0x65, 0x6e, 0x67, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x66, 0x61, 0x63,
+	0x74, 0x6f, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x66, 0x61,
+	0x63, 0x74, 0x6f, 0x72, 0x49, 0x64, 0x22, 0x34, 0x0a, 0x0f, 0x53, 0x65, 0x6e, 0x64, 0x4f, 0x54,
+	0x50, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x21, 0x0a, 0x04, 0x73, 0x65, 0x6e,
+	0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x61, 0x75, 0x74, 0x68, 0x2e, 0x53,
+	0x65, 0x6e, 0x74, 0x4f, 0x54, 0x50, 0x52, 0x04, 0x73, 0x65, 0x6e, 0x74, 0x22, 0x44, 0x0a, 0x10,
+	0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x4f, 0x54, 0x50, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x1c, 0x0a, 0x09, 0x63, 0x68, 0x61, 0x6c, 0x6c, 0x65, 0x6e, 0x67, 0x65, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x09, 0x63, 0x68, 0x61, 0x6c, 0x6c, 0x65, 0x6e, 0x67, 0x65, 0x12, 0x12,
+	0x0a, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x63, 0x6f,
+	0x64, 0x65, 0x22, 0x29, 0x0a, 0x11, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x4f, 0x54, 0x50, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x22, 0x54, 0x0a,
+	0x16, 0x45, 0x6e, 0x72, 0x6f, 0x6c, 0x6c, 0x4f, 0x54, 0x50, 0x46, 0x61, 0x63, 0x74, 0x6f, 0x72,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x68, 0x61, 0x6e, 0x6e,
+	0x65, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65,
+	0x6c, 0x12, 0x20, 0x0a, 0x0b, 0x64, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x22, 0x5a, 0x0a, 0x17, 0x45, 0x6e, 0x72, 0x6f, 0x6c, 0x6c, 0x4f, 0x54, 0x50,
+	0x46, 0x61, 0x63, 0x74, 0x6f, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1c,
+	0x0a, 0x09, 0x63, 0x68, 0x61, 0x6c, 0x6c, 0x65, 0x6e, 0x67, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x09, 0x63, 0x68, 0x61, 0x6c, 0x6c, 0x65, 0x6e, 0x67, 0x65, 0x12, 0x21, 0x0a, 0x04,
+	0x73, 0x65, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x61, 0x75, 0x74,
+	0x68, 0x2e, 0x53, 0x65, 0x6e, 0x74, 0x4f, 0x54, 0x50, 0x52, 0x04, 0x73, 0x65, 0x6e, 0x74, 0x22,
+	0x4b, 0x0a, 0x17, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x4f, 0x54, 0x50, 0x46, 0x61, 0x63,
+	0x74, 0x6f, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x63, 0x68,
+	0x61, 0x6c, 0x6c, 0x65, 0x6e, 0x67, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x63,
+	0x68, 0x61, 0x6c, 0x6c, 0x65, 0x6e, 0x67, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x63, 0x6f, 0x64, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x22, 0x43, 0x0a, 0x18,
+	0x43, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x4f, 0x54, 0x50, 0x46, 0x61, 0x63, 0x74, 0x6f, 0x72,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x27, 0x0a, 0x06, 0x66, 0x61, 0x63, 0x74,
+	0x6f, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x61, 0x75, 0x74, 0x68, 0x2e,
+	0x4f, 0x54, 0x50, 0x46, 0x61, 0x63, 0x74, 0x6f, 0x72, 0x52, 0x06, 0x66, 0x61, 0x63, 0x74, 0x6f,
+	0x72, 0x22, 0x17, 0x0a, 0x15, 0x4c, 0x69, 0x73, 0x74, 0x4f, 0x54, 0x50, 0x46, 0x61, 0x63, 0x74,
+	0x6f, 0x72, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x43, 0x0a, 0x16, 0x4c, 0x69,
+	0x73, 0x74, 0x4f, 0x54, 0x50, 0x46, 0x61, 0x63, 0x74, 0x6f, 0x72, 0x73, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x29, 0x0a, 0x07, 0x66, 0x61, 0x63, 0x74, 0x6f, 0x72, 0x73, 0x18,
+	0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x61, 0x75, 0x74, 0x68, 0x2e, 0x4f, 0x54, 0x50,
+	0x46, 0x61, 0x63, 0x74, 0x6f, 0x72, 0x52, 0x07, 0x66, 0x61, 0x63, 0x74, 0x6f, 0x72, 0x73, 0x22,
+	0x28, 0x0a, 0x16, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x4f, 0x54, 0x50, 0x46, 0x61, 0x63, 0x74,
+	0x6f, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x02, 0x69, 0x64, 0x22, 0x19, 0x0a, 0x17, 0x44, 0x65, 0x6c,
+	0x65, 0x74, 0x65, 0x4f, 0x54, 0x50, 0x46, 0x61, 0x63, 0x74, 0x6f, 0x72, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x32, 0x85, 0x04, 0x0a, 0x03, 0x4f, 0x54, 0x50, 0x12, 0x48, 0x0a, 0x0d,
+	0x53, 0x74, 0x61, 0x72, 0x74, 0x4f, 0x54, 0x50, 0x4c, 0x6f, 0x67, 0x69, 0x6e, 0x12, 0x1a, 0x2e,
+	0x61, 0x75, 0x74, 0x68, 0x2e, 0x53, 0x74, 0x61, 0x72, 0x74, 0x4f, 0x54, 0x50, 0x4c, 0x6f, 0x67,
+	0x69, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x61, 0x75, 0x74, 0x68,
+	0x2e, 0x53, 0x74, 0x61, 0x72, 0x74, 0x4f, 0x54, 0x50, 0x4c, 0x6f, 0x67, 0x69, 0x6e, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x36, 0x0a, 0x07, 0x53, 0x65, 0x6e, 0x64, 0x4f, 0x54,
+	0x50, 0x12, 0x14, 0x2e, 0x61, 0x75, 0x74, 0x68, 0x2e, 0x53, 0x65, 0x6e, 0x64, 0x4f, 0x54, 0x50,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x15, 0x2e, 0x61, 0x75, 0x74, 0x68, 0x2e, 0x53,
+	0x65, 0x6e, 0x64, 0x4f, 0x54, 0x50, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3c,
+	0x0a, 0x09, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x4f, 0x54, 0x50, 0x12, 0x16, 0x2e, 0x61, 0x75,
+	0x74, 0x68, 0x2e, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x4f, 0x54, 0x50, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x61, 0x75, 0x74, 0x68, 0x2e, 0x56, 0x65, 0x72, 0x69, 0x66,
+	0x79, 0x4f, 0x54, 0x50, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4e, 0x0a, 0x0f,
+	0x45, 0x6e, 0x72, 0x6f, 0x6c, 0x6c, 0x4f, 0x54, 0x50, 0x46, 0x61, 0x63, 0x74, 0x6f, 0x72, 0x12,
+	0x1c, 0x2e, 0x61, 0x75, 0x74, 0x68, 0x2e, 0x45, 0x6e, 0x72, 0x6f, 0x6c, 0x6c, 0x4f, 0x54, 0x50,
+	0x46, 0x61, 0x63, 0x74, 0x6f, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d, 0x2e,
+	0x61, 0x75, 0x74, 0x68, 0x2e, 0x45, 0x6e, 0x72, 0x6f, 0x6c, 0x6c, 0x4f, 0x54, 0x50, 0x46, 0x61,
+	0x63, 0x74, 0x6f, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x51, 0x0a, 0x10,
+	0x43, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x4f, 0x54, 0x50, 0x46, 0x61, 0x63, 0x74, 0x6f, 0x72,
+	0x12, 0x1d, 0x2e, 0x61, 0x75, 0x74, 0x68, 0x2e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x4f,
+	0x54, 0x50, 0x46, 0x61, 0x63, 0x74, 0x6f, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x1e, 0x2e, 0x61, 0x75, 0x74, 0x68, 0x2e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x4f, 0x54,
+	0x50, 0x46, 0x61, 0x63, 0x74, 0x6f, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x4b, 0x0a, 0x0e, 0x4c, 0x69, 0x73, 0x74, 0x4f, 0x54, 0x50, 0x46, 0x61, 0x63, 0x74, 0x6f, 0x72,
+	0x73, 0x12, 0x1b, 0x2e, 0x61, 0x75, 0x74, 0x68, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x4f, 0x54, 0x50,
+	0x46, 0x61, 0x63, 0x74, 0x6f, 0x72, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c,
+	0x2e, 0x61, 0x75, 0x74, 0x68, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x4f, 0x54, 0x50, 0x46, 0x61, 0x63,
+	0x74, 0x6f, 0x72, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4e, 0x0a, 0x0f,
+	0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x4f, 0x54, 0x50, 0x46, 0x61, 0x63, 0x74, 0x6f, 0x72, 0x12,
+	0x1c, 0x2e, 0x61, 0x75, 0x74, 0x68, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x4f, 0x54, 0x50,
+	0x46, 0x61, 0x63, 0x74, 0x6f, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d, 0x2e,
+	0x61, 0x75, 0x74, 0x68, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x4f, 0x54, 0x50, 0x46, 0x61,
+	0x63, 0x74, 0x6f, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x17, 0x5a, 0x15,
+	0x66, 0x75, 0x74, 0x6f, 0x64, 0x61, 0x6d, 0x61, 0x2e, 0x73, 0x73, 0x6f, 0x2e, 0x76, 0x31, 0x3b,
+	0x73, 0x73, 0x6f, 0x76, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_sso_otp_proto_rawDescOnce sync.Once
+	file_sso_otp_proto_rawDescData = file_sso_otp_proto_rawDesc
+)
+
+func file_sso_otp_proto_rawDescGZIP() []byte {
+	file_sso_otp_proto_rawDescOnce.Do(func() {
+		file_sso_otp_proto_rawDescData = protoimpl.X.CompressGZIP(file_sso_otp_proto_rawDescData)
+	})
+	return file_sso_otp_proto_rawDescData
+}
+
+var file_sso_otp_proto_msgTypes = make([]protoimpl.MessageInfo, 16)
+var file_sso_otp_proto_goTypes = []any{
+	(*OTPFactor)(nil),                // 0: auth.OTPFactor
+	(*SentOTP)(nil),                  // 1: auth.SentOTP
+	(*StartOTPLoginRequest)(nil),     // 2: auth.StartOTPLoginRequest
+	(*StartOTPLoginResponse)(nil),    // 3: auth.StartOTPLoginResponse
+	(*SendOTPRequest)(nil),           // 4: auth.SendOTPRequest
+	(*SendOTPResponse)(nil),          // 5: auth.SendOTPResponse
+	(*VerifyOTPRequest)(nil),         // 6: auth.VerifyOTPRequest
+	(*VerifyOTPResponse)(nil),        // 7: auth.VerifyOTPResponse
+	(*EnrollOTPFactorRequest)(nil),   // 8: auth.EnrollOTPFactorRequest
+	(*EnrollOTPFactorResponse)(nil),  // 9: auth.EnrollOTPFactorResponse
+	(*ConfirmOTPFactorRequest)(nil),  // 10: auth.ConfirmOTPFactorRequest
+	(*ConfirmOTPFactorResponse)(nil), // 11: auth.ConfirmOTPFactorResponse
+	(*ListOTPFactorsRequest)(nil),    // 12: auth.ListOTPFactorsRequest
+	(*ListOTPFactorsResponse)(nil),   // 13: auth.ListOTPFactorsResponse
+	(*DeleteOTPFactorRequest)(nil),   // 14: auth.DeleteOTPFactorRequest
+	(*DeleteOTPFactorResponse)(nil),  // 15: auth.DeleteOTPFactorResponse
+}
+var file_sso_otp_proto_depIdxs = []int32{
+	1,  // 0: auth.SendOTPResponse.sent:type_name -> auth.SentOTP
+	1,  // 1: auth.EnrollOTPFactorResponse.sent:type_name -> auth.SentOTP
+	0,  // 2: auth.ConfirmOTPFactorResponse.factor:type_name -> auth.OTPFactor
+	0,  // 3: auth.ListOTPFactorsResponse.factors:type_name -> auth.OTPFactor
+	2,  // 4: auth.OTP.StartOTPLogin:input_type -> auth.StartOTPLoginRequest
+	4,  // 5: auth.OTP.SendOTP:input_type -> auth.SendOTPRequest
+	6,  // 6: auth.OTP.VerifyOTP:input_type -> auth.VerifyOTPRequest
+	8,  // 7: auth.OTP.EnrollOTPFactor:input_type -> auth.EnrollOTPFactorRequest
+	10, // 8: auth.OTP.ConfirmOTPFactor:input_type -> auth.ConfirmOTPFactorRequest
+	12, // 9: auth.OTP.ListOTPFactors:input_type -> auth.ListOTPFactorsRequest
+	14, // 10: auth.OTP.DeleteOTPFactor:input_type -> auth.DeleteOTPFactorRequest
+	3,  // 11: auth.OTP.StartOTPLogin:output_type -> auth.StartOTPLoginResponse
+	5,  // 12: auth.OTP.SendOTP:output_type -> auth.SendOTPResponse
+	7,  // 13: auth.OTP.VerifyOTP:output_type -> auth.VerifyOTPResponse
+	9,  // 14: auth.OTP.EnrollOTPFactor:output_type -> auth.EnrollOTPFactorResponse
+	11, // 15: auth.OTP.ConfirmOTPFactor:output_type -> auth.ConfirmOTPFactorResponse
+	13, // 16: auth.OTP.ListOTPFactors:output_type -> auth.ListOTPFactorsResponse
+	15, // 17: auth.OTP.DeleteOTPFactor:output_type -> auth.DeleteOTPFactorResponse
+	11, // [11:18] is the sub-list for method output_type
+	4,  // [4:11] is the sub-list for method input_type
+	4,  // [4:4] is the sub-list for extension type_name
+	4,  // [4:4] is the sub-list for extension extendee
+	0,  // [0:4] is the sub-list for field type_name
+}
+
+func init() { file_sso_otp_proto_init() }
+func file_sso_otp_proto_init() {
+	if File_sso_otp_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_sso_otp_proto_msgTypes[0].Exporter = func(v any, i int) any {
+			switch v := v.(*OTPFactor); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_sso_otp_proto_msgTypes[1].Exporter = func(v any, i int) any {
+			switch v := v.(*SentOTP); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_sso_otp_proto_msgTypes[2].Exporter = func(v any, i int) any {
+			switch v := v.(*StartOTPLoginRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_sso_otp_proto_msgTypes[3].Exporter = func(v any, i int) any {
+			switch v := v.(*StartOTPLoginResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_sso_otp_proto_msgTypes[4].Exporter = func(v any, i int) any {
+			switch v := v.(*SendOTPRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_sso_otp_proto_msgTypes[5].Exporter = func(v any, i int) any {
+			switch v := v.(*SendOTPResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_sso_otp_proto_msgTypes[6].Exporter = func(v any, i int) any {
+			switch v := v.(*VerifyOTPRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_sso_otp_proto_msgTypes[7].Exporter = func(v any, i int) any {
+			switch v := v.(*VerifyOTPResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_sso_otp_proto_msgTypes[8].Exporter = func(v any, i int) any {
+			switch v := v.(*EnrollOTPFactorRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_sso_otp_proto_msgTypes[9].Exporter = func(v any, i int) any {
+			switch v := v.(*EnrollOTPFactorResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_sso_otp_proto_msgTypes[10].Exporter = func(v any, i int) any {
+			switch v := v.(*ConfirmOTPFactorRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_sso_otp_proto_msgTypes[11].Exporter = func(v any, i int) any {
+			switch v := v.(*ConfirmOTPFactorResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_sso_otp_proto_msgTypes[12].Exporter = func(v any, i int) any {
+			switch v := v.(*ListOTPFactorsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_sso_otp_proto_msgTypes[13].Exporter = func(v any, i int) any {
+			switch v := v.(*ListOTPFactorsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_sso_otp_proto_msgTypes[14].Exporter = func(v any, i int) any {
+			switch v := v.(*DeleteOTPFactorRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_sso_otp_proto_msgTypes[15].Exporter = func(v any, i int) any {
+			switch v := v.(*DeleteOTPFactorResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_sso_otp_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   16,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_sso_otp_proto_goTypes,
+		DependencyIndexes: file_sso_otp_proto_depIdxs,
+		MessageInfos:      file_sso_otp_proto_msgTypes,
+	}.Build()
+	File_sso_otp_proto = out.File
+	file_sso_otp_proto_rawDesc = nil
+	file_sso_otp_proto_goTypes = nil
+	file_sso_otp_proto_depIdxs = nil
+}
@@ -0,0 +1,383 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.28.1
+// source: sso/otp.proto
+
+package ssov1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	OTP_StartOTPLogin_FullMethodName    = "/auth.OTP/StartOTPLogin"
+	OTP_SendOTP_FullMethodName          = "/auth.OTP/SendOTP"
+	OTP_VerifyOTP_FullMethodName        = "/auth.OTP/VerifyOTP"
+	OTP_EnrollOTPFactor_FullMethodName  = "/auth.OTP/EnrollOTPFactor"
+	OTP_ConfirmOTPFactor_FullMethodName = "/auth.OTP/ConfirmOTPFactor"
+	OTP_ListOTPFactors_FullMethodName   = "/auth.OTP/ListOTPFactors"
+	OTP_DeleteOTPFactor_FullMethodName  = "/auth.OTP/DeleteOTPFactor"
+)
+
+// OTPClient is the client API for OTP service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// OTP logs users in with one-time passcodes sent by email or SMS, and
+// confirms password logins with them as a second factor.
+//
+// Codes belong to challenges identified by tokens the client holds. A
+// passcode login starts with StartOTPLogin, which sends a code to the
+// account email, the confirmed email factor or the confirmed phone; the
+// app must allow the "otp" grant type. Auth.Login of a user with factors
+// returns a second factor challenge instead of token; SendOTP sends its
+// code to the factor the user picks. VerifyOTP passes either challenge with
+// the code and returns token for the app. Codes expire shortly, can be
+// entered a few times and resent a few times after a pause.
+//
+// StartOTPLogin, SendOTP and VerifyOTP are public. Factor RPCs require a
+// token of the user, factors can't be managed with impersonation tokens,
+// API keys or service accounts. DeleteOTPFactor requires a token issued
+// within the last few minutes.
+type OTPClient interface {
+	StartOTPLogin(ctx context.Context, in *StartOTPLoginRequest, opts ...grpc.CallOption) (*StartOTPLoginResponse, error)
+	SendOTP(ctx context.Context, in *SendOTPRequest, opts ...grpc.CallOption) (*SendOTPResponse, error)
+	VerifyOTP(ctx context.Context, in *VerifyOTPRequest, opts ...grpc.CallOption) (*VerifyOTPResponse, error)
+	EnrollOTPFactor(ctx context.Context, in *EnrollOTPFactorRequest, opts ...grpc.CallOption) (*EnrollOTPFactorResponse, error)
+	ConfirmOTPFactor(ctx context.Context, in *ConfirmOTPFactorRequest, opts ...grpc.CallOption) (*ConfirmOTPFactorResponse, error)
+	ListOTPFactors(ctx context.Context, in *ListOTPFactorsRequest, opts ...grpc.CallOption) (*ListOTPFactorsResponse, error)
+	DeleteOTPFactor(ctx context.Context, in *DeleteOTPFactorRequest, opts ...grpc.CallOption) (*DeleteOTPFactorResponse, error)
+}
+
+type oTPClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewOTPClient(cc grpc.ClientConnInterface) OTPClient {
+	return &oTPClient{cc}
+}
+
+func (c *oTPClient) StartOTPLogin(ctx context.Context, in *StartOTPLoginRequest, opts ...grpc.CallOption) (*StartOTPLoginResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StartOTPLoginResponse)
+	err := c.cc.Invoke(ctx, OTP_StartOTPLogin_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *oTPClient) SendOTP(ctx context.Context, in *SendOTPRequest, opts ...grpc.CallOption) (*SendOTPResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SendOTPResponse)
+	err := c.cc.Invoke(ctx, OTP_SendOTP_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *oTPClient) VerifyOTP(ctx context.Context, in *VerifyOTPRequest, opts ...grpc.CallOption) (*VerifyOTPResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(VerifyOTPResponse)
+	err := c.cc.Invoke(ctx, OTP_VerifyOTP_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *oTPClient) EnrollOTPFactor(ctx context.Context, in *EnrollOTPFactorRequest, opts ...grpc.CallOption) (*EnrollOTPFactorResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EnrollOTPFactorResponse)
+	err := c.cc.Invoke(ctx, OTP_EnrollOTPFactor_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *oTPClient) ConfirmOTPFactor(ctx context.Context, in *ConfirmOTPFactorRequest, opts ...grpc.CallOption) (*ConfirmOTPFactorResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ConfirmOTPFactorResponse)
+	err := c.cc.Invoke(ctx, OTP_ConfirmOTPFactor_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *oTPClient) ListOTPFactors(ctx context.Context, in *ListOTPFactorsRequest, opts ...grpc.CallOption) (*ListOTPFactorsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListOTPFactorsResponse)
+	err := c.cc.Invoke(ctx, OTP_ListOTPFactors_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *oTPClient) DeleteOTPFactor(ctx context.Context, in *DeleteOTPFactorRequest, opts ...grpc.CallOption) (*DeleteOTPFactorResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteOTPFactorResponse)
+	err := c.cc.Invoke(ctx, OTP_DeleteOTPFactor_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// OTPServer is the server API for OTP service.
+// All implementations must embed UnimplementedOTPServer
+// for forward compatibility.
+//
+// OTP logs users in with one-time passcodes sent by email or SMS, and
+// confirms password logins with them as a second factor.
+//
+// Codes belong to challenges identified by tokens the client holds. A
+// passcode login starts with StartOTPLogin, which sends a code to the
+// account email, the confirmed email factor or the confirmed phone; the
+// app must allow the "otp" grant type. Auth.Login of a user with factors
+// returns a second factor challenge instead of token; SendOTP sends its
+// code to the factor the user picks. VerifyOTP passes either challenge with
+// the code and returns token for the app. Codes expire shortly, can be
+// entered a few times and resent a few times after a pause.
+//
+// StartOTPLogin, SendOTP and VerifyOTP are public. Factor RPCs require a
+// token of the user, factors can't be managed with impersonation tokens,
+// API keys or service accounts. DeleteOTPFactor requires a token issued
+// within the last few minutes.
+type OTPServer interface {
+	StartOTPLogin(context.Context, *StartOTPLoginRequest) (*StartOTPLoginResponse, error)
+	SendOTP(context.Context, *SendOTPRequest) (*SendOTPResponse, error)
+	VerifyOTP(context.Context, *VerifyOTPRequest) (*VerifyOTPResponse, error)
+	EnrollOTPFactor(context.Context, *EnrollOTPFactorRequest) (*EnrollOTPFactorResponse, error)
+	ConfirmOTPFactor(context.Context, *ConfirmOTPFactorRequest) (*ConfirmOTPFactorResponse, error)
+	ListOTPFactors(context.Context, *ListOTPFactorsRequest) (*ListOTPFactorsResponse, error)
+	DeleteOTPFactor(context.Context, *DeleteOTPFactorRequest) (*DeleteOTPFactorResponse, error)
+	mustEmbedUnimplementedOTPServer()
+}
+
+// UnimplementedOTPServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedOTPServer struct{}
+
+func (UnimplementedOTPServer) StartOTPLogin(context.Context, *StartOTPLoginRequest) (*StartOTPLoginResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StartOTPLogin not implemented")
+}
+func (UnimplementedOTPServer) SendOTP(context.Context, *SendOTPRequest) (*SendOTPResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SendOTP not implemented")
+}
+func (UnimplementedOTPServer) VerifyOTP(context.Context, *VerifyOTPRequest) (*VerifyOTPResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method VerifyOTP not implemented")
+}
+func (UnimplementedOTPServer) EnrollOTPFactor(context.Context, *EnrollOTPFactorRequest) (*EnrollOTPFactorResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EnrollOTPFactor not implemented")
+}
+func (UnimplementedOTPServer) ConfirmOTPFactor(context.Context, *ConfirmOTPFactorRequest) (*ConfirmOTPFactorResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ConfirmOTPFactor not implemented")
+}
+func (UnimplementedOTPServer) ListOTPFactors(context.Context, *ListOTPFactorsRequest) (*ListOTPFactorsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListOTPFactors not implemented")
+}
+func (UnimplementedOTPServer) DeleteOTPFactor(context.Context, *DeleteOTPFactorRequest) (*DeleteOTPFactorResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteOTPFactor not implemented")
+}
+func (UnimplementedOTPServer) mustEmbedUnimplementedOTPServer() {}
+func (UnimplementedOTPServer) testEmbeddedByValue()             {}
+
+// UnsafeOTPServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to OTPServer will
+// result in compilation errors.
+type UnsafeOTPServer interface {
+	mustEmbedUnimplementedOTPServer()
+}
+
+func RegisterOTPServer(s grpc.ServiceRegistrar, srv OTPServer) {
+	// If the following call pancis, it indicates UnimplementedOTPServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&OTP_ServiceDesc, srv)
+}
+
+func _OTP_StartOTPLogin_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StartOTPLoginRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OTPServer).StartOTPLogin(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OTP_StartOTPLogin_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OTPServer).StartOTPLogin(ctx, req.(*StartOTPLoginRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OTP_SendOTP_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SendOTPRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OTPServer).SendOTP(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OTP_SendOTP_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OTPServer).SendOTP(ctx, req.(*SendOTPRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OTP_VerifyOTP_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifyOTPRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OTPServer).VerifyOTP(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OTP_VerifyOTP_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OTPServer).VerifyOTP(ctx, req.(*VerifyOTPRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OTP_EnrollOTPFactor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EnrollOTPFactorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OTPServer).EnrollOTPFactor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OTP_EnrollOTPFactor_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OTPServer).EnrollOTPFactor(ctx, req.(*EnrollOTPFactorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OTP_ConfirmOTPFactor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConfirmOTPFactorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OTPServer).ConfirmOTPFactor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OTP_ConfirmOTPFactor_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OTPServer).ConfirmOTPFactor(ctx, req.(*ConfirmOTPFactorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OTP_ListOTPFactors_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListOTPFactorsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OTPServer).ListOTPFactors(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OTP_ListOTPFactors_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OTPServer).ListOTPFactors(ctx, req.(*ListOTPFactorsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OTP_DeleteOTPFactor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteOTPFactorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OTPServer).DeleteOTPFactor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OTP_DeleteOTPFactor_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OTPServer).DeleteOTPFactor(ctx, req.(*DeleteOTPFactorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// OTP_ServiceDesc is the grpc.ServiceDesc for OTP service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var OTP_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "auth.OTP",
+	HandlerType: (*OTPServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "StartOTPLogin",
+			Handler:    _OTP_StartOTPLogin_Handler,
+		},
+		{
+			MethodName: "SendOTP",
+			Handler:    _OTP_SendOTP_Handler,
+		},
+		{
+			MethodName: "VerifyOTP",
+			Handler:    _OTP_VerifyOTP_Handler,
+		},
+		{
+			MethodName: "EnrollOTPFactor",
+			Handler:    _OTP_EnrollOTPFactor_Handler,
+		},
+		{
+			MethodName: "ConfirmOTPFactor",
+			Handler:    _OTP_ConfirmOTPFactor_Handler,
+		},
+		{
+			MethodName: "ListOTPFactors",
+			Handler:    _OTP_ListOTPFactors_Handler,
+		},
+		{
+			MethodName: "DeleteOTPFactor",
+			Handler:    _OTP_DeleteOTPFactor_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "sso/otp.proto",
+}
@@ -0,0 +1,347 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.28.1
+// source: sso/saml.proto
+
+package ssov1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	SAML_GetIdPMetadata_FullMethodName        = "/auth.SAML/GetIdPMetadata"
+	SAML_GetSAMLRequest_FullMethodName        = "/auth.SAML/GetSAMLRequest"
+	SAML_CompleteSAMLLogin_FullMethodName     = "/auth.SAML/CompleteSAMLLogin"
+	SAML_SetServiceProvider_FullMethodName    = "/auth.SAML/SetServiceProvider"
+	SAML_GetServiceProvider_FullMethodName    = "/auth.SAML/GetServiceProvider"
+	SAML_DeleteServiceProvider_FullMethodName = "/auth.SAML/DeleteServiceProvider"
+)
+
+// SAMLClient is the client API for SAML service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// SAML makes the server a SAML 2.0 identity provider for service providers
+// registered for apps. Users sign in to a service provider with the login
+// of its app.
+//
+// The service provider sends the user to the SSO URL of the identity
+// provider (a login page) with the SAMLRequest and RelayState parameters,
+// in the query for the HTTP-Redirect binding or in the form for the
+// HTTP-POST binding. The page resolves the request with GetSAMLRequest,
+// signs the user in to the returned app with Auth.Login and calls
+// CompleteSAMLLogin with the token. It then posts SAMLResponse and
+// RelayState to the returned assertion consumer service URL (HTTP-POST
+// binding).
+//
+// GetIdPMetadata and GetSAMLRequest are public, CompleteSAMLLogin requires
+// a token of the user for the app of the service provider, which can't be
+// an impersonation token, API key or service account. Service providers
+// are managed by admins of their apps.
+type SAMLClient interface {
+	GetIdPMetadata(ctx context.Context, in *GetIdPMetadataRequest, opts ...grpc.CallOption) (*GetIdPMetadataResponse, error)
+	GetSAMLRequest(ctx context.Context, in *GetSAMLRequestRequest, opts ...grpc.CallOption) (*GetSAMLRequestResponse, error)
+	CompleteSAMLLogin(ctx context.Context, in *CompleteSAMLLoginRequest, opts ...grpc.CallOption) (*CompleteSAMLLoginResponse, error)
+	SetServiceProvider(ctx context.Context, in *SetServiceProviderRequest, opts ...grpc.CallOption) (*SetServiceProviderResponse, error)
+	GetServiceProvider(ctx context.Context, in *GetServiceProviderRequest, opts ...grpc.CallOption) (*GetServiceProviderResponse, error)
+	DeleteServiceProvider(ctx context.Context, in *DeleteServiceProviderRequest, opts ...grpc.CallOption) (*DeleteServiceProviderResponse, error)
+}
+
+type sAMLClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewSAMLClient(cc grpc.ClientConnInterface) SAMLClient {
+	return &sAMLClient{cc}
+}
+
+func (c *sAMLClient) GetIdPMetadata(ctx context.Context, in *GetIdPMetadataRequest, opts ...grpc.CallOption) (*GetIdPMetadataResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetIdPMetadataResponse)
+	err := c.cc.Invoke(ctx, SAML_GetIdPMetadata_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sAMLClient) GetSAMLRequest(ctx context.Context, in *GetSAMLRequestRequest, opts ...grpc.CallOption) (*GetSAMLRequestResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetSAMLRequestResponse)
+	err := c.cc.Invoke(ctx, SAML_GetSAMLRequest_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sAMLClient) CompleteSAMLLogin(ctx context.Context, in *CompleteSAMLLoginRequest, opts ...grpc.CallOption) (*CompleteSAMLLoginResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CompleteSAMLLoginResponse)
+	err := c.cc.Invoke(ctx, SAML_CompleteSAMLLogin_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sAMLClient) SetServiceProvider(ctx context.Context, in *SetServiceProviderRequest, opts ...grpc.CallOption) (*SetServiceProviderResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetServiceProviderResponse)
+	err := c.cc.Invoke(ctx, SAML_SetServiceProvider_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sAMLClient) GetServiceProvider(ctx context.Context, in *GetServiceProviderRequest, opts ...grpc.CallOption) (*GetServiceProviderResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetServiceProviderResponse)
+	err := c.cc.Invoke(ctx, SAML_GetServiceProvider_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sAMLClient) DeleteServiceProvider(ctx context.Context, in *DeleteServiceProviderRequest, opts ...grpc.CallOption) (*DeleteServiceProviderResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteServiceProviderResponse)
+	err := c.cc.Invoke(ctx, SAML_DeleteServiceProvider_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SAMLServer is the server API for SAML service.
+// All implementations must embed UnimplementedSAMLServer
+// for forward compatibility.
+//
+// SAML makes the server a SAML 2.0 identity provider for service providers
+// registered for apps. Users sign in to a service provider with the login
+// of its app.
+//
+// The service provider sends the user to the SSO URL of the identity
+// provider (a login page) with the SAMLRequest and RelayState parameters,
+// in the query for the HTTP-Redirect binding or in the form for the
+// HTTP-POST binding. The page resolves the request with GetSAMLRequest,
+// signs the user in to the returned app with Auth.Login and calls
+// CompleteSAMLLogin with the token. It then posts SAMLResponse and
+// RelayState to the returned assertion consumer service URL (HTTP-POST
+// binding).
+//
+// GetIdPMetadata and GetSAMLRequest are public, CompleteSAMLLogin requires
+// a token of the user for the app of the service provider, which can't be
+// an impersonation token, API key or service account. Service providers
+// are managed by admins of their apps.
+type SAMLServer interface {
+	GetIdPMetadata(context.Context, *GetIdPMetadataRequest) (*GetIdPMetadataResponse, error)
+	GetSAMLRequest(context.Context, *GetSAMLRequestRequest) (*GetSAMLRequestResponse, error)
+	CompleteSAMLLogin(context.Context, *CompleteSAMLLoginRequest) (*CompleteSAMLLoginResponse, error)
+	SetServiceProvider(context.Context, *SetServiceProviderRequest) (*SetServiceProviderResponse, error)
+	GetServiceProvider(context.Context, *GetServiceProviderRequest) (*GetServiceProviderResponse, error)
+	DeleteServiceProvider(context.Context, *DeleteServiceProviderRequest) (*DeleteServiceProviderResponse, error)
+	mustEmbedUnimplementedSAMLServer()
+}
+
+// UnimplementedSAMLServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedSAMLServer struct{}
+
+func (UnimplementedSAMLServer) GetIdPMetadata(context.Context, *GetIdPMetadataRequest) (*GetIdPMetadataResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetIdPMetadata not implemented")
+}
+func (UnimplementedSAMLServer) GetSAMLRequest(context.Context, *GetSAMLRequestRequest) (*GetSAMLRequestResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSAMLRequest not implemented")
+}
+func (UnimplementedSAMLServer) CompleteSAMLLogin(context.Context, *CompleteSAMLLoginRequest) (*CompleteSAMLLoginResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CompleteSAMLLogin not implemented")
+}
+func (UnimplementedSAMLServer) SetServiceProvider(context.Context, *SetServiceProviderRequest) (*SetServiceProviderResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetServiceProvider not implemented")
+}
+func (UnimplementedSAMLServer) GetServiceProvider(context.Context, *GetServiceProviderRequest) (*GetServiceProviderResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetServiceProvider not implemented")
+}
+func (UnimplementedSAMLServer) DeleteServiceProvider(context.Context, *DeleteServiceProviderRequest) (*DeleteServiceProviderResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteServiceProvider not implemented")
+}
+func (UnimplementedSAMLServer) mustEmbedUnimplementedSAMLServer() {}
+func (UnimplementedSAMLServer) testEmbeddedByValue()              {}
+
+// UnsafeSAMLServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to SAMLServer will
+// result in compilation errors.
+type UnsafeSAMLServer interface {
+	mustEmbedUnimplementedSAMLServer()
+}
+
+func RegisterSAMLServer(s grpc.ServiceRegistrar, srv SAMLServer) {
+	// If the following call pancis, it indicates UnimplementedSAMLServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&SAML_ServiceDesc, srv)
+}
+
+func _SAML_GetIdPMetadata_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetIdPMetadataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SAMLServer).GetIdPMetadata(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SAML_GetIdPMetadata_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SAMLServer).GetIdPMetadata(ctx, req.(*GetIdPMetadataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SAML_GetSAMLRequest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSAMLRequestRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SAMLServer).GetSAMLRequest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SAML_GetSAMLRequest_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SAMLServer).GetSAMLRequest(ctx, req.(*GetSAMLRequestRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SAML_CompleteSAMLLogin_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CompleteSAMLLoginRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SAMLServer).CompleteSAMLLogin(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SAML_CompleteSAMLLogin_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SAMLServer).CompleteSAMLLogin(ctx, req.(*CompleteSAMLLoginRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SAML_SetServiceProvider_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetServiceProviderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SAMLServer).SetServiceProvider(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SAML_SetServiceProvider_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SAMLServer).SetServiceProvider(ctx, req.(*SetServiceProviderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SAML_GetServiceProvider_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetServiceProviderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SAMLServer).GetServiceProvider(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SAML_GetServiceProvider_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SAMLServer).GetServiceProvider(ctx, req.(*GetServiceProviderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SAML_DeleteServiceProvider_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteServiceProviderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SAMLServer).DeleteServiceProvider(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SAML_DeleteServiceProvider_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SAMLServer).DeleteServiceProvider(ctx, req.(*DeleteServiceProviderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// SAML_ServiceDesc is the grpc.ServiceDesc for SAML service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var SAML_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "auth.SAML",
+	HandlerType: (*SAMLServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetIdPMetadata",
+			Handler:    _SAML_GetIdPMetadata_Handler,
+		},
+		{
+			MethodName: "GetSAMLRequest",
+			Handler:    _SAML_GetSAMLRequest_Handler,
+		},
+		{
+			MethodName: "CompleteSAMLLogin",
+			Handler:    _SAML_CompleteSAMLLogin_Handler,
+		},
+		{
+			MethodName: "SetServiceProvider",
+			Handler:    _SAML_SetServiceProvider_Handler,
+		},
+		{
+			MethodName: "GetServiceProvider",
+			Handler:    _SAML_GetServiceProvider_Handler,
+		},
+		{
+			MethodName: "DeleteServiceProvider",
+			Handler:    _SAML_DeleteServiceProvider_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "sso/saml.proto",
+}
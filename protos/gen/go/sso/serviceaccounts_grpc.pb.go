@@ -0,0 +1,525 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.28.1
+// source: sso/serviceaccounts.proto
+
+package ssov1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	ServiceAccounts_CreateServiceAccount_FullMethodName       = "/auth.ServiceAccounts/CreateServiceAccount"
+	ServiceAccounts_GetServiceAccount_FullMethodName          = "/auth.ServiceAccounts/GetServiceAccount"
+	ServiceAccounts_ListServiceAccounts_FullMethodName        = "/auth.ServiceAccounts/ListServiceAccounts"
+	ServiceAccounts_DisableServiceAccount_FullMethodName      = "/auth.ServiceAccounts/DisableServiceAccount"
+	ServiceAccounts_EnableServiceAccount_FullMethodName       = "/auth.ServiceAccounts/EnableServiceAccount"
+	ServiceAccounts_DeleteServiceAccount_FullMethodName       = "/auth.ServiceAccounts/DeleteServiceAccount"
+	ServiceAccounts_AddServiceAccountKey_FullMethodName       = "/auth.ServiceAccounts/AddServiceAccountKey"
+	ServiceAccounts_ListServiceAccountKeys_FullMethodName     = "/auth.ServiceAccounts/ListServiceAccountKeys"
+	ServiceAccounts_RemoveServiceAccountKey_FullMethodName    = "/auth.ServiceAccounts/RemoveServiceAccountKey"
+	ServiceAccounts_CreateServiceAccountAPIKey_FullMethodName = "/auth.ServiceAccounts/CreateServiceAccountAPIKey"
+	ServiceAccounts_ServiceAccountToken_FullMethodName        = "/auth.ServiceAccounts/ServiceAccountToken"
+)
+
+// ServiceAccountsClient is the client API for ServiceAccounts service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// ServiceAccounts manages non-human users of an app or an organization.
+//
+// A service account is a user: its ID is a user ID, roles and groups are
+// assigned to it like to any user. It can't log in with a password and
+// authenticates with API keys or with JWT assertions (RFC 7523) signed
+// with one of its keys. Tokens issued to it carry the "sub_type" claim
+// "service_account".
+//
+// Service accounts of an app are managed by admins of the app, service
+// accounts of an organization by its owners and admins. ServiceAccountToken
+// requires no token.
+type ServiceAccountsClient interface {
+	CreateServiceAccount(ctx context.Context, in *CreateServiceAccountRequest, opts ...grpc.CallOption) (*CreateServiceAccountResponse, error)
+	GetServiceAccount(ctx context.Context, in *GetServiceAccountRequest, opts ...grpc.CallOption) (*GetServiceAccountResponse, error)
+	ListServiceAccounts(ctx context.Context, in *ListServiceAccountsRequest, opts ...grpc.CallOption) (*ListServiceAccountsResponse, error)
+	DisableServiceAccount(ctx context.Context, in *DisableServiceAccountRequest, opts ...grpc.CallOption) (*DisableServiceAccountResponse, error)
+	EnableServiceAccount(ctx context.Context, in *EnableServiceAccountRequest, opts ...grpc.CallOption) (*EnableServiceAccountResponse, error)
+	DeleteServiceAccount(ctx context.Context, in *DeleteServiceAccountRequest, opts ...grpc.CallOption) (*DeleteServiceAccountResponse, error)
+	AddServiceAccountKey(ctx context.Context, in *AddServiceAccountKeyRequest, opts ...grpc.CallOption) (*AddServiceAccountKeyResponse, error)
+	ListServiceAccountKeys(ctx context.Context, in *ListServiceAccountKeysRequest, opts ...grpc.CallOption) (*ListServiceAccountKeysResponse, error)
+	RemoveServiceAccountKey(ctx context.Context, in *RemoveServiceAccountKeyRequest, opts ...grpc.CallOption) (*RemoveServiceAccountKeyResponse, error)
+	CreateServiceAccountAPIKey(ctx context.Context, in *CreateServiceAccountAPIKeyRequest, opts ...grpc.CallOption) (*CreateServiceAccountAPIKeyResponse, error)
+	ServiceAccountToken(ctx context.Context, in *ServiceAccountTokenRequest, opts ...grpc.CallOption) (*ServiceAccountTokenResponse, error)
+}
+
+type serviceAccountsClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewServiceAccountsClient(cc grpc.ClientConnInterface) ServiceAccountsClient {
+	return &serviceAccountsClient{cc}
+}
+
+func (c *serviceAccountsClient) CreateServiceAccount(ctx context.Context, in *CreateServiceAccountRequest, opts ...grpc.CallOption) (*CreateServiceAccountResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateServiceAccountResponse)
+	err := c.cc.Invoke(ctx, ServiceAccounts_CreateServiceAccount_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *serviceAccountsClient) GetServiceAccount(ctx context.Context, in *GetServiceAccountRequest, opts ...grpc.CallOption) (*GetServiceAccountResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetServiceAccountResponse)
+	err := c.cc.Invoke(ctx, ServiceAccounts_GetServiceAccount_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *serviceAccountsClient) ListServiceAccounts(ctx context.Context, in *ListServiceAccountsRequest, opts ...grpc.CallOption) (*ListServiceAccountsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListServiceAccountsResponse)
+	err := c.cc.Invoke(ctx, ServiceAccounts_ListServiceAccounts_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *serviceAccountsClient) DisableServiceAccount(ctx context.Context, in *DisableServiceAccountRequest, opts ...grpc.CallOption) (*DisableServiceAccountResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DisableServiceAccountResponse)
+	err := c.cc.Invoke(ctx, ServiceAccounts_DisableServiceAccount_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *serviceAccountsClient) EnableServiceAccount(ctx context.Context, in *EnableServiceAccountRequest, opts ...grpc.CallOption) (*EnableServiceAccountResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EnableServiceAccountResponse)
+	err := c.cc.Invoke(ctx, ServiceAccounts_EnableServiceAccount_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *serviceAccountsClient) DeleteServiceAccount(ctx context.Context, in *DeleteServiceAccountRequest, opts ...grpc.CallOption) (*DeleteServiceAccountResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteServiceAccountResponse)
+	err := c.cc.Invoke(ctx, ServiceAccounts_DeleteServiceAccount_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *serviceAccountsClient) AddServiceAccountKey(ctx context.Context, in *AddServiceAccountKeyRequest, opts ...grpc.CallOption) (*AddServiceAccountKeyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AddServiceAccountKeyResponse)
+	err := c.cc.Invoke(ctx, ServiceAccounts_AddServiceAccountKey_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *serviceAccountsClient) ListServiceAccountKeys(ctx context.Context, in *ListServiceAccountKeysRequest, opts ...grpc.CallOption) (*ListServiceAccountKeysResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListServiceAccountKeysResponse)
+	err := c.cc.Invoke(ctx, ServiceAccounts_ListServiceAccountKeys_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *serviceAccountsClient) RemoveServiceAccountKey(ctx context.Context, in *RemoveServiceAccountKeyRequest, opts ...grpc.CallOption) (*RemoveServiceAccountKeyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RemoveServiceAccountKeyResponse)
+	err := c.cc.Invoke(ctx, ServiceAccounts_RemoveServiceAccountKey_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *serviceAccountsClient) CreateServiceAccountAPIKey(ctx context.Context, in *CreateServiceAccountAPIKeyRequest, opts ...grpc.CallOption) (*CreateServiceAccountAPIKeyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateServiceAccountAPIKeyResponse)
+	err := c.cc.Invoke(ctx, ServiceAccounts_CreateServiceAccountAPIKey_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *serviceAccountsClient) ServiceAccountToken(ctx context.Context, in *ServiceAccountTokenRequest, opts ...grpc.CallOption) (*ServiceAccountTokenResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ServiceAccountTokenResponse)
+	err := c.cc.Invoke(ctx, ServiceAccounts_ServiceAccountToken_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ServiceAccountsServer is the server API for ServiceAccounts service.
+// All implementations must embed UnimplementedServiceAccountsServer
+// for forward compatibility.
+//
+// ServiceAccounts manages non-human users of an app or an organization.
+//
+// A service account is a user: its ID is a user ID, roles and groups are
+// assigned to it like to any user. It can't log in with a password and
+// authenticates with API keys or with JWT assertions (RFC 7523) signed
+// with one of its keys. Tokens issued to it carry the "sub_type" claim
+// "service_account".
+//
+// Service accounts of an app are managed by admins of the app, service
+// accounts of an organization by its owners and admins. ServiceAccountToken
+// requires no token.
+type ServiceAccountsServer interface {
+	CreateServiceAccount(context.Context, *CreateServiceAccountRequest) (*CreateServiceAccountResponse, error)
+	GetServiceAccount(context.Context, *GetServiceAccountRequest) (*GetServiceAccountResponse, error)
+	ListServiceAccounts(context.Context, *ListServiceAccountsRequest) (*ListServiceAccountsResponse, error)
+	DisableServiceAccount(context.Context, *DisableServiceAccountRequest) (*DisableServiceAccountResponse, error)
+	EnableServiceAccount(context.Context, *EnableServiceAccountRequest) (*EnableServiceAccountResponse, error)
+	DeleteServiceAccount(context.Context, *DeleteServiceAccountRequest) (*DeleteServiceAccountResponse, error)
+	AddServiceAccountKey(context.Context, *AddServiceAccountKeyRequest) (*AddServiceAccountKeyResponse, error)
+	ListServiceAccountKeys(context.Context, *ListServiceAccountKeysRequest) (*ListServiceAccountKeysResponse, error)
+	RemoveServiceAccountKey(context.Context, *RemoveServiceAccountKeyRequest) (*RemoveServiceAccountKeyResponse, error)
+	CreateServiceAccountAPIKey(context.Context, *CreateServiceAccountAPIKeyRequest) (*CreateServiceAccountAPIKeyResponse, error)
+	ServiceAccountToken(context.Context, *ServiceAccountTokenRequest) (*ServiceAccountTokenResponse, error)
+	mustEmbedUnimplementedServiceAccountsServer()
+}
+
+// UnimplementedServiceAccountsServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedServiceAccountsServer struct{}
+
+func (UnimplementedServiceAccountsServer) CreateServiceAccount(context.Context, *CreateServiceAccountRequest) (*CreateServiceAccountResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateServiceAccount not implemented")
+}
+func (UnimplementedServiceAccountsServer) GetServiceAccount(context.Context, *GetServiceAccountRequest) (*GetServiceAccountResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetServiceAccount not implemented")
+}
+func (UnimplementedServiceAccountsServer) ListServiceAccounts(context.Context, *ListServiceAccountsRequest) (*ListServiceAccountsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListServiceAccounts not implemented")
+}
+func (UnimplementedServiceAccountsServer) DisableServiceAccount(context.Context, *DisableServiceAccountRequest) (*DisableServiceAccountResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DisableServiceAccount not implemented")
+}
+func (UnimplementedServiceAccountsServer) EnableServiceAccount(context.Context, *EnableServiceAccountRequest) (*EnableServiceAccountResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EnableServiceAccount not implemented")
+}
+func (UnimplementedServiceAccountsServer) DeleteServiceAccount(context.Context, *DeleteServiceAccountRequest) (*DeleteServiceAccountResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteServiceAccount not implemented")
+}
+func (UnimplementedServiceAccountsServer) AddServiceAccountKey(context.Context, *AddServiceAccountKeyRequest) (*AddServiceAccountKeyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddServiceAccountKey not implemented")
+}
+func (UnimplementedServiceAccountsServer) ListServiceAccountKeys(context.Context, *ListServiceAccountKeysRequest) (*ListServiceAccountKeysResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListServiceAccountKeys not implemented")
+}
+func (UnimplementedServiceAccountsServer) RemoveServiceAccountKey(context.Context, *RemoveServiceAccountKeyRequest) (*RemoveServiceAccountKeyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveServiceAccountKey not implemented")
+}
+func (UnimplementedServiceAccountsServer) CreateServiceAccountAPIKey(context.Context, *CreateServiceAccountAPIKeyRequest) (*CreateServiceAccountAPIKeyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateServiceAccountAPIKey not implemented")
+}
+func (UnimplementedServiceAccountsServer) ServiceAccountToken(context.Context, *ServiceAccountTokenRequest) (*ServiceAccountTokenResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ServiceAccountToken not implemented")
+}
+func (UnimplementedServiceAccountsServer) mustEmbedUnimplementedServiceAccountsServer() {}
+func (UnimplementedServiceAccountsServer) testEmbeddedByValue()                         {}
+
+// UnsafeServiceAccountsServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ServiceAccountsServer will
+// result in compilation errors.
+type UnsafeServiceAccountsServer interface {
+	mustEmbedUnimplementedServiceAccountsServer()
+}
+
+func RegisterServiceAccountsServer(s grpc.ServiceRegistrar, srv ServiceAccountsServer) {
+	// If the following call pancis, it indicates UnimplementedServiceAccountsServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&ServiceAccounts_ServiceDesc, srv)
+}
+
+func _ServiceAccounts_CreateServiceAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateServiceAccountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ServiceAccountsServer).CreateServiceAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ServiceAccounts_CreateServiceAccount_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServiceAccountsServer).CreateServiceAccount(ctx, req.(*CreateServiceAccountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ServiceAccounts_GetServiceAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetServiceAccountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ServiceAccountsServer).GetServiceAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ServiceAccounts_GetServiceAccount_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServiceAccountsServer).GetServiceAccount(ctx, req.(*GetServiceAccountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ServiceAccounts_ListServiceAccounts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListServiceAccountsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ServiceAccountsServer).ListServiceAccounts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ServiceAccounts_ListServiceAccounts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServiceAccountsServer).ListServiceAccounts(ctx, req.(*ListServiceAccountsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ServiceAccounts_DisableServiceAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DisableServiceAccountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ServiceAccountsServer).DisableServiceAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ServiceAccounts_DisableServiceAccount_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServiceAccountsServer).DisableServiceAccount(ctx, req.(*DisableServiceAccountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ServiceAccounts_EnableServiceAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EnableServiceAccountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ServiceAccountsServer).EnableServiceAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ServiceAccounts_EnableServiceAccount_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServiceAccountsServer).EnableServiceAccount(ctx, req.(*EnableServiceAccountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ServiceAccounts_DeleteServiceAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteServiceAccountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ServiceAccountsServer).DeleteServiceAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ServiceAccounts_DeleteServiceAccount_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServiceAccountsServer).DeleteServiceAccount(ctx, req.(*DeleteServiceAccountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ServiceAccounts_AddServiceAccountKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddServiceAccountKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ServiceAccountsServer).AddServiceAccountKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ServiceAccounts_AddServiceAccountKey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServiceAccountsServer).AddServiceAccountKey(ctx, req.(*AddServiceAccountKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ServiceAccounts_ListServiceAccountKeys_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListServiceAccountKeysRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ServiceAccountsServer).ListServiceAccountKeys(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ServiceAccounts_ListServiceAccountKeys_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServiceAccountsServer).ListServiceAccountKeys(ctx, req.(*ListServiceAccountKeysRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ServiceAccounts_RemoveServiceAccountKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveServiceAccountKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ServiceAccountsServer).RemoveServiceAccountKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ServiceAccounts_RemoveServiceAccountKey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServiceAccountsServer).RemoveServiceAccountKey(ctx, req.(*RemoveServiceAccountKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ServiceAccounts_CreateServiceAccountAPIKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateServiceAccountAPIKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ServiceAccountsServer).CreateServiceAccountAPIKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ServiceAccounts_CreateServiceAccountAPIKey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServiceAccountsServer).CreateServiceAccountAPIKey(ctx, req.(*CreateServiceAccountAPIKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ServiceAccounts_ServiceAccountToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ServiceAccountTokenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ServiceAccountsServer).ServiceAccountToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ServiceAccounts_ServiceAccountToken_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServiceAccountsServer).ServiceAccountToken(ctx, req.(*ServiceAccountTokenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// ServiceAccounts_ServiceDesc is the grpc.ServiceDesc for ServiceAccounts service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ServiceAccounts_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "auth.ServiceAccounts",
+	HandlerType: (*ServiceAccountsServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateServiceAccount",
+			Handler:    _ServiceAccounts_CreateServiceAccount_Handler,
+		},
+		{
+			MethodName: "GetServiceAccount",
+			Handler:    _ServiceAccounts_GetServiceAccount_Handler,
+		},
+		{
+			MethodName: "ListServiceAccounts",
+			Handler:    _ServiceAccounts_ListServiceAccounts_Handler,
+		},
+		{
+			MethodName: "DisableServiceAccount",
+			Handler:    _ServiceAccounts_DisableServiceAccount_Handler,
+		},
+		{
+			MethodName: "EnableServiceAccount",
+			Handler:    _ServiceAccounts_EnableServiceAccount_Handler,
+		},
+		{
+			MethodName: "DeleteServiceAccount",
+			Handler:    _ServiceAccounts_DeleteServiceAccount_Handler,
+		},
+		{
+			MethodName: "AddServiceAccountKey",
+			Handler:    _ServiceAccounts_AddServiceAccountKey_Handler,
+		},
+		{
+			MethodName: "ListServiceAccountKeys",
+			Handler:    _ServiceAccounts_ListServiceAccountKeys_Handler,
+		},
+		{
+			MethodName: "RemoveServiceAccountKey",
+			Handler:    _ServiceAccounts_RemoveServiceAccountKey_Handler,
+		},
+		{
+			MethodName: "CreateServiceAccountAPIKey",
+			Handler:    _ServiceAccounts_CreateServiceAccountAPIKey_Handler,
+		},
+		{
+			MethodName: "ServiceAccountToken",
+			Handler:    _ServiceAccounts_ServiceAccountToken_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "sso/serviceaccounts.proto",
+}
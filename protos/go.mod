@@ -0,0 +1,2 @@
+module github.com/futod4m4/protos
+go 1.22
@@ -0,0 +1,150 @@
+package postgresql
+
+import (
+	"SSO/internal/domain/models"
+	"SSO/internal/storage"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/lib/pq"
+	"time"
+)
+
+const selectAPIKeys = `SELECT id, user_id, app_id, name, prefix, scopes, expires_at, last_used_at, created_at FROM api_keys`
+
+// lastUsedPrecision limits how often last use of API key is written.
+const lastUsedPrecision = time.Minute
+
+// SaveAPIKey saves API key identified by hash of the key and returns it
+// with ID and creation time set.
+func (s *Storage) SaveAPIKey(ctx context.Context, key models.APIKey, keyHash string) (models.APIKey, error) {
+	const op = "storage.postgresql.SaveAPIKey"
+
+	err := s.DB.QueryRowContext(
+		ctx,
+		`INSERT INTO api_keys(user_id, app_id, name, prefix, key_hash, scopes, expires_at)
+		VALUES($1, $2, $3, $4, $5, $6, $7) RETURNING id, created_at`,
+		key.UserID, key.AppID, key.Name, key.Prefix, keyHash, pq.Array(key.Scopes), nullTime(key.ExpiresAt),
+	).Scan(&key.ID, &key.CreatedAt)
+	if err != nil {
+		switch pgErrorCode(err) {
+		case codeUniqueViolation:
+			return models.APIKey{}, fmt.Errorf("%s: %w", op, storage.ErrAPIKeyExists)
+		case codeForeignKeyViolation:
+			return models.APIKey{}, fmt.Errorf("%s: %w", op, storage.ErrAppNotFound)
+		}
+
+		return models.APIKey{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return key, nil
+}
+
+// APIKeys returns API keys of the user.
+func (s *Storage) APIKeys(ctx context.Context, userID int64) ([]models.APIKey, error) {
+	const op = "storage.postgresql.APIKeys"
+
+	rows, err := s.DB.QueryContext(ctx, selectAPIKeys+" WHERE user_id = $1 ORDER BY id", userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var keys []models.APIKey
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		keys = append(keys, key)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return keys, nil
+}
+
+// APIKeyByHash returns API key by hash of the key.
+func (s *Storage) APIKeyByHash(ctx context.Context, keyHash string) (models.APIKey, error) {
+	const op = "storage.postgresql.APIKeyByHash"
+
+	key, err := scanAPIKey(s.DB.QueryRowContext(ctx, selectAPIKeys+" WHERE key_hash = $1", keyHash))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.APIKey{}, fmt.Errorf("%s: %w", op, storage.ErrAPIKeyNotFound)
+		}
+
+		return models.APIKey{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return key, nil
+}
+
+// TouchAPIKey records use of API key. Uses closer than lastUsedPrecision
+// to the recorded one are skipped.
+func (s *Storage) TouchAPIKey(ctx context.Context, keyID int64, usedAt time.Time) error {
+	const op = "storage.postgresql.TouchAPIKey"
+
+	_, err := s.DB.ExecContext(
+		ctx,
+		`UPDATE api_keys SET last_used_at = $2
+		WHERE id = $1 AND (last_used_at IS NULL OR last_used_at < $3)`,
+		keyID, usedAt, usedAt.Add(-lastUsedPrecision),
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// DeleteAPIKey deletes API key of the user.
+func (s *Storage) DeleteAPIKey(ctx context.Context, userID, keyID int64) error {
+	const op = "storage.postgresql.DeleteAPIKey"
+
+	res, err := s.DB.ExecContext(ctx, "DELETE FROM api_keys WHERE id = $1 AND user_id = $2", keyID, userID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrAPIKeyNotFound)
+	}
+
+	return nil
+}
+
+func scanAPIKey(row rowScanner) (models.APIKey, error) {
+	var (
+		key      models.APIKey
+		expires  sql.NullTime
+		lastUsed sql.NullTime
+	)
+	err := row.Scan(
+		&key.ID,
+		&key.UserID,
+		&key.AppID,
+		&key.Name,
+		&key.Prefix,
+		pq.Array(&key.Scopes),
+		&expires,
+		&lastUsed,
+		&key.CreatedAt,
+	)
+	if err != nil {
+		return models.APIKey{}, err
+	}
+
+	key.ExpiresAt = expires.Time
+	key.LastUsedAt = lastUsed.Time
+
+	return key, nil
+}
+
+// nullTime stores zero time as NULL.
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
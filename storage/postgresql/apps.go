@@ -0,0 +1,485 @@
+package postgresql
+
+import (
+	"SSO/internal/domain/models"
+	"SSO/internal/storage"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/lib/pq"
+	"time"
+)
+
+const selectApps = `SELECT id, name, secret, secret_kid, group_claims, redirect_uris, grant_types, disabled,
+	registration, allowed_email_domains, required_fields, min_age, consent_required, token_endpoint_auth_method,
+	registration_token_hash IS NOT NULL, created_at FROM apps`
+
+const selectTokenExchangeRules = `SELECT source_app_id, target_app_id, scopes, created_at FROM token_exchange_rules`
+
+func (s *Storage) App(ctx context.Context, appID int) (models.App, error) {
+	const op = "storage.postgresql.App"
+
+	app, err := s.scanApp(s.DB.QueryRowContext(ctx, selectApps+" WHERE id = $1", appID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.App{}, fmt.Errorf("%s: %w", op, storage.ErrAppNotFound)
+		}
+
+		return models.App{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return app, nil
+}
+
+// Apps returns all apps.
+func (s *Storage) Apps(ctx context.Context) ([]models.App, error) {
+	const op = "storage.postgresql.Apps"
+
+	rows, err := s.DB.QueryContext(ctx, selectApps+" ORDER BY id")
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var apps []models.App
+	for rows.Next() {
+		app, err := s.scanApp(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		apps = append(apps, app)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return apps, nil
+}
+
+// SaveApp saves app with its secret and settings to database
+// and returns it with ID and creation time set.
+func (s *Storage) SaveApp(ctx context.Context, app models.App) (models.App, error) {
+	const op = "storage.postgresql.SaveApp"
+
+	id, err := s.nextAppID(ctx)
+	if err != nil {
+		return models.App{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	secret, err := s.encrypt(appSecretColumn, appKey(id), app.Secret)
+	if err != nil {
+		return models.App{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = s.DB.QueryRowContext(
+		ctx,
+		`INSERT INTO apps(id, name, secret, secret_kid, redirect_uris, grant_types)
+		VALUES($1, $2, $3, $4, $5, $6) RETURNING token_endpoint_auth_method, created_at`,
+		id, app.Name, secret, app.SecretKID, pq.Array(app.RedirectURIs), pq.Array(app.GrantTypes),
+	).Scan(&app.TokenEndpointAuthMethod, &app.CreatedAt)
+	if err != nil {
+		if pgErrorCode(err) == codeUniqueViolation {
+			return models.App{}, fmt.Errorf("%s: %w", op, storage.ErrAppExists)
+		}
+
+		return models.App{}, fmt.Errorf("%s: %w", op, err)
+	}
+	app.ID = id
+
+	return app, nil
+}
+
+// nextAppID reserves ID of a new app, so its secret can be encrypted for
+// the row before the row is inserted.
+func (s *Storage) nextAppID(ctx context.Context) (int, error) {
+	var id int
+	err := s.DB.QueryRowContext(ctx, "SELECT nextval('apps_id_seq')").Scan(&id)
+
+	return id, err
+}
+
+// UpdateApp updates name, redirect URIs and grant types of the app.
+func (s *Storage) UpdateApp(ctx context.Context, app models.App) error {
+	const op = "storage.postgresql.UpdateApp"
+
+	res, err := s.DB.ExecContext(
+		ctx,
+		"UPDATE apps SET name = $1, redirect_uris = $2, grant_types = $3 WHERE id = $4",
+		app.Name, pq.Array(app.RedirectURIs), pq.Array(app.GrantTypes), app.ID,
+	)
+	if err != nil {
+		if pgErrorCode(err) == codeUniqueViolation {
+			return fmt.Errorf("%s: %w", op, storage.ErrAppExists)
+		}
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrAppNotFound)
+	}
+
+	return nil
+}
+
+// UpdateRegistrationRules updates registration rules of the app.
+func (s *Storage) UpdateRegistrationRules(ctx context.Context, appID int, rules models.RegistrationRules) error {
+	const op = "storage.postgresql.UpdateRegistrationRules"
+
+	res, err := s.DB.ExecContext(
+		ctx,
+		`UPDATE apps SET registration = $1, allowed_email_domains = $2, required_fields = $3, min_age = $4
+		WHERE id = $5`,
+		rules.Mode, pq.Array(rules.AllowedEmailDomains), pq.Array(rules.RequiredFields), rules.MinAge, appID,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrAppNotFound)
+	}
+
+	return nil
+}
+
+// SetAppConsentRequired sets whether the app requires consent of users.
+func (s *Storage) SetAppConsentRequired(ctx context.Context, appID int, required bool) error {
+	const op = "storage.postgresql.SetAppConsentRequired"
+
+	res, err := s.DB.ExecContext(ctx, "UPDATE apps SET consent_required = $1 WHERE id = $2", required, appID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrAppNotFound)
+	}
+
+	return nil
+}
+
+// SetAppDisabled disables or enables the app.
+func (s *Storage) SetAppDisabled(ctx context.Context, appID int, disabled bool) error {
+	const op = "storage.postgresql.SetAppDisabled"
+
+	res, err := s.DB.ExecContext(ctx, "UPDATE apps SET disabled = $1 WHERE id = $2", disabled, appID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrAppNotFound)
+	}
+
+	return nil
+}
+
+// DeleteApp deletes the app together with its roles, groups and policies.
+func (s *Storage) DeleteApp(ctx context.Context, appID int) error {
+	const op = "storage.postgresql.DeleteApp"
+
+	res, err := s.DB.ExecContext(ctx, "DELETE FROM apps WHERE id = $1", appID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrAppNotFound)
+	}
+
+	return nil
+}
+
+// RotateAppSecret makes secret the primary secret of the app. The former
+// primary secret is still accepted for verification until expiresAt.
+func (s *Storage) RotateAppSecret(ctx context.Context, appID int, secret, kid string, expiresAt time.Time) error {
+	const op = "storage.postgresql.RotateAppSecret"
+
+	secret, err := s.encrypt(appSecretColumn, appKey(appID), secret)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	var oldSecret, oldKID string
+	err = tx.QueryRowContext(
+		ctx,
+		"SELECT secret, secret_kid FROM apps WHERE id = $1 FOR UPDATE",
+		appID,
+	).Scan(&oldSecret, &oldKID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("%s: %w", op, storage.ErrAppNotFound)
+		}
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	// The former secret moves to app_secrets, so it's encrypted for its new row.
+	oldSecret, err = s.decrypt(appSecretColumn, appKey(appID), oldSecret)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	oldSecret, err = s.encrypt(formerAppSecretColumn, formerAppSecretKey(appID, oldKID), oldSecret)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = tx.ExecContext(ctx, "DELETE FROM app_secrets WHERE app_id = $1 AND expires_at <= now()", appID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = tx.ExecContext(
+		ctx,
+		"INSERT INTO app_secrets(app_id, kid, secret, expires_at) VALUES($1, $2, $3, $4)",
+		appID, oldKID, oldSecret, expiresAt,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = tx.ExecContext(
+		ctx,
+		"UPDATE apps SET secret = $1, secret_kid = $2 WHERE id = $3",
+		secret, kid, appID,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// AppSecret returns not expired former secret of the app by its kid.
+func (s *Storage) AppSecret(ctx context.Context, appID int, kid string) (models.AppSecret, error) {
+	const op = "storage.postgresql.AppSecret"
+
+	secret := models.AppSecret{AppID: appID, KID: kid}
+	err := s.DB.QueryRowContext(
+		ctx,
+		`SELECT secret, rotated_at, expires_at FROM app_secrets
+		WHERE app_id = $1 AND kid = $2 AND expires_at > now()`,
+		appID, kid,
+	).Scan(&secret.Secret, &secret.RotatedAt, &secret.ExpiresAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.AppSecret{}, fmt.Errorf("%s: %w", op, storage.ErrSecretNotFound)
+		}
+
+		return models.AppSecret{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	secret.Secret, err = s.decrypt(formerAppSecretColumn, formerAppSecretKey(appID, kid), secret.Secret)
+	if err != nil {
+		return models.AppSecret{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return secret, nil
+}
+
+// AppSecrets returns not expired former secrets of the app without their values.
+func (s *Storage) AppSecrets(ctx context.Context, appID int) ([]models.AppSecret, error) {
+	const op = "storage.postgresql.AppSecrets"
+
+	rows, err := s.DB.QueryContext(
+		ctx,
+		`SELECT kid, rotated_at, expires_at FROM app_secrets
+		WHERE app_id = $1 AND expires_at > now()
+		ORDER BY rotated_at DESC`,
+		appID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var secrets []models.AppSecret
+	for rows.Next() {
+		secret := models.AppSecret{AppID: appID}
+		if err := rows.Scan(&secret.KID, &secret.RotatedAt, &secret.ExpiresAt); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		secrets = append(secrets, secret)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return secrets, nil
+}
+
+// DeleteAppSecret deletes former secret of the app, tokens signed with it
+// are no longer accepted.
+func (s *Storage) DeleteAppSecret(ctx context.Context, appID int, kid string) error {
+	const op = "storage.postgresql.DeleteAppSecret"
+
+	res, err := s.DB.ExecContext(
+		ctx,
+		"DELETE FROM app_secrets WHERE app_id = $1 AND kid = $2 AND expires_at > now()",
+		appID, kid,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrSecretNotFound)
+	}
+
+	return nil
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanApp scans app selected with selectApps and decrypts its secret.
+func (s *Storage) scanApp(row rowScanner) (models.App, error) {
+	var app models.App
+	err := row.Scan(
+		&app.ID,
+		&app.Name,
+		&app.Secret,
+		&app.SecretKID,
+		&app.GroupClaims,
+		pq.Array(&app.RedirectURIs),
+		pq.Array(&app.GrantTypes),
+		&app.Disabled,
+		&app.Registration.Mode,
+		pq.Array(&app.Registration.AllowedEmailDomains),
+		pq.Array(&app.Registration.RequiredFields),
+		&app.Registration.MinAge,
+		&app.ConsentRequired,
+		&app.TokenEndpointAuthMethod,
+		&app.SelfRegistered,
+		&app.CreatedAt,
+	)
+	if err != nil {
+		return models.App{}, err
+	}
+
+	app.Secret, err = s.decrypt(appSecretColumn, appKey(app.ID), app.Secret)
+	if err != nil {
+		return models.App{}, err
+	}
+
+	return app, nil
+}
+
+// SaveTokenExchangeRule creates or replaces the rule for its pair of apps.
+func (s *Storage) SaveTokenExchangeRule(ctx context.Context, rule models.TokenExchangeRule) error {
+	const op = "storage.postgresql.SaveTokenExchangeRule"
+
+	_, err := s.DB.ExecContext(
+		ctx,
+		`INSERT INTO token_exchange_rules(source_app_id, target_app_id, scopes) VALUES($1, $2, $3)
+		ON CONFLICT (source_app_id, target_app_id) DO UPDATE SET scopes = EXCLUDED.scopes`,
+		rule.SourceAppID, rule.TargetAppID, pq.Array(rule.Scopes),
+	)
+	if err != nil {
+		if pgErrorCode(err) == codeForeignKeyViolation {
+			return fmt.Errorf("%s: %w", op, storage.ErrAppNotFound)
+		}
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// TokenExchangeRule returns the rule permitting exchange of tokens of the
+// source app for tokens of the target app.
+func (s *Storage) TokenExchangeRule(ctx context.Context, sourceAppID, targetAppID int) (models.TokenExchangeRule, error) {
+	const op = "storage.postgresql.TokenExchangeRule"
+
+	rule, err := scanTokenExchangeRule(s.DB.QueryRowContext(
+		ctx,
+		selectTokenExchangeRules+" WHERE source_app_id = $1 AND target_app_id = $2",
+		sourceAppID, targetAppID,
+	))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.TokenExchangeRule{}, fmt.Errorf("%s: %w", op, storage.ErrRuleNotFound)
+		}
+
+		return models.TokenExchangeRule{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return rule, nil
+}
+
+// TokenExchangeRules returns rules permitting exchange of tokens for
+// tokens of the target app.
+func (s *Storage) TokenExchangeRules(ctx context.Context, targetAppID int) ([]models.TokenExchangeRule, error) {
+	const op = "storage.postgresql.TokenExchangeRules"
+
+	rows, err := s.DB.QueryContext(
+		ctx,
+		selectTokenExchangeRules+" WHERE target_app_id = $1 ORDER BY source_app_id",
+		targetAppID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var rules []models.TokenExchangeRule
+	for rows.Next() {
+		rule, err := scanTokenExchangeRule(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		rules = append(rules, rule)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return rules, nil
+}
+
+// DeleteTokenExchangeRule deletes the rule for the pair of apps.
+func (s *Storage) DeleteTokenExchangeRule(ctx context.Context, sourceAppID, targetAppID int) error {
+	const op = "storage.postgresql.DeleteTokenExchangeRule"
+
+	res, err := s.DB.ExecContext(
+		ctx,
+		"DELETE FROM token_exchange_rules WHERE source_app_id = $1 AND target_app_id = $2",
+		sourceAppID, targetAppID,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrRuleNotFound)
+	}
+
+	return nil
+}
+
+func scanTokenExchangeRule(row rowScanner) (models.TokenExchangeRule, error) {
+	var rule models.TokenExchangeRule
+	err := row.Scan(&rule.SourceAppID, &rule.TargetAppID, pq.Array(&rule.Scopes), &rule.CreatedAt)
+	if err != nil {
+		return models.TokenExchangeRule{}, err
+	}
+
+	return rule, nil
+}
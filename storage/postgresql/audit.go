@@ -0,0 +1,60 @@
+package postgresql
+
+import (
+	"SSO/internal/domain/models"
+	"context"
+	"fmt"
+)
+
+// SaveAuditEvent appends event to the audit trail.
+func (s *Storage) SaveAuditEvent(ctx context.Context, event models.AuditEvent) error {
+	const op = "storage.postgresql.SaveAuditEvent"
+
+	_, err := s.DB.ExecContext(
+		ctx,
+		`INSERT INTO audit_events(action, app_id, actor_id, user_id, session_id, details)
+		VALUES($1, $2, $3, $4, $5, $6)`,
+		event.Action, event.AppID, event.ActorID, event.UserID, event.SessionID, event.Details,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// AuditEvents returns audit events matching the filter, newest first.
+func (s *Storage) AuditEvents(ctx context.Context, filter models.AuditFilter) ([]models.AuditEvent, error) {
+	const op = "storage.postgresql.AuditEvents"
+
+	rows, err := s.DB.QueryContext(
+		ctx,
+		`SELECT id, action, app_id, actor_id, user_id, session_id, details, created_at FROM audit_events
+		WHERE app_id = $1
+		AND ($2 = 0 OR user_id = $2)
+		AND ($3 = '' OR session_id = $3)
+		AND ($4 = 0 OR id < $4)
+		ORDER BY id DESC LIMIT $5`,
+		filter.AppID, filter.UserID, filter.SessionID, filter.BeforeID, filter.Limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var events []models.AuditEvent
+	for rows.Next() {
+		var e models.AuditEvent
+		err := rows.Scan(&e.ID, &e.Action, &e.AppID, &e.ActorID, &e.UserID, &e.SessionID, &e.Details, &e.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		events = append(events, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return events, nil
+}
@@ -0,0 +1,202 @@
+package postgresql
+
+import (
+	"SSO/internal/domain/models"
+	"SSO/internal/storage"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/lib/pq"
+)
+
+const selectInitialAccessTokens = `SELECT id, name, max_uses, uses, created_by, expires_at, created_at
+	FROM initial_access_tokens`
+
+// SaveInitialAccessToken saves initial access token identified by its hash
+// and returns it with ID and creation time set.
+func (s *Storage) SaveInitialAccessToken(
+	ctx context.Context,
+	token models.InitialAccessToken,
+	tokenHash string,
+) (models.InitialAccessToken, error) {
+	const op = "storage.postgresql.SaveInitialAccessToken"
+
+	err := s.DB.QueryRowContext(
+		ctx,
+		`INSERT INTO initial_access_tokens(name, token_hash, max_uses, created_by, expires_at)
+		VALUES($1, $2, $3, $4, $5) RETURNING id, created_at`,
+		token.Name, tokenHash, token.MaxUses, sql.NullInt64{Int64: token.CreatedBy, Valid: token.CreatedBy != 0},
+		nullTime(token.ExpiresAt),
+	).Scan(&token.ID, &token.CreatedAt)
+	if err != nil {
+		return models.InitialAccessToken{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return token, nil
+}
+
+// InitialAccessTokens returns all initial access tokens.
+func (s *Storage) InitialAccessTokens(ctx context.Context) ([]models.InitialAccessToken, error) {
+	const op = "storage.postgresql.InitialAccessTokens"
+
+	rows, err := s.DB.QueryContext(ctx, selectInitialAccessTokens+" ORDER BY id")
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var tokens []models.InitialAccessToken
+	for rows.Next() {
+		var (
+			token     models.InitialAccessToken
+			createdBy sql.NullInt64
+			expiresAt sql.NullTime
+		)
+		err := rows.Scan(&token.ID, &token.Name, &token.MaxUses, &token.Uses, &createdBy, &expiresAt, &token.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		token.CreatedBy = createdBy.Int64
+		token.ExpiresAt = expiresAt.Time
+		tokens = append(tokens, token)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return tokens, nil
+}
+
+// DeleteInitialAccessToken deletes initial access token, apps registered
+// with it are kept.
+func (s *Storage) DeleteInitialAccessToken(ctx context.Context, id int64) error {
+	const op = "storage.postgresql.DeleteInitialAccessToken"
+
+	res, err := s.DB.ExecContext(ctx, "DELETE FROM initial_access_tokens WHERE id = $1", id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrInitialTokenNotFound)
+	}
+
+	return nil
+}
+
+// RegisterClient uses the initial access token identified by its hash and
+// saves the app it registers in one transaction. The app is managed with
+// the registration access token identified by registrationTokenHash.
+// Expired and used up initial access tokens are not found.
+func (s *Storage) RegisterClient(
+	ctx context.Context,
+	app models.App,
+	initialTokenHash string,
+	registrationTokenHash string,
+) (models.App, error) {
+	const op = "storage.postgresql.RegisterClient"
+
+	id, err := s.nextAppID(ctx)
+	if err != nil {
+		return models.App{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	secret, err := s.encrypt(appSecretColumn, appKey(id), app.Secret)
+	if err != nil {
+		return models.App{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return models.App{}, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(
+		ctx,
+		`UPDATE initial_access_tokens SET uses = uses + 1
+		WHERE token_hash = $1 AND (expires_at IS NULL OR expires_at > now()) AND (max_uses = 0 OR uses < max_uses)`,
+		initialTokenHash,
+	)
+	if err != nil {
+		return models.App{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if n, _ := res.RowsAffected(); n == 0 {
+		return models.App{}, fmt.Errorf("%s: %w", op, storage.ErrInitialTokenNotFound)
+	}
+
+	err = tx.QueryRowContext(
+		ctx,
+		`INSERT INTO apps(id, name, secret, secret_kid, redirect_uris, grant_types, token_endpoint_auth_method,
+			consent_required, registration_token_hash)
+		VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING created_at`,
+		id, app.Name, secret, app.SecretKID, pq.Array(app.RedirectURIs), pq.Array(app.GrantTypes),
+		app.TokenEndpointAuthMethod, app.ConsentRequired, registrationTokenHash,
+	).Scan(&app.CreatedAt)
+	if err != nil {
+		if pgErrorCode(err) == codeUniqueViolation {
+			return models.App{}, fmt.Errorf("%s: %w", op, storage.ErrAppExists)
+		}
+
+		return models.App{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return models.App{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	app.ID = id
+	app.SelfRegistered = true
+
+	return app, nil
+}
+
+// ClientByRegistrationToken returns dynamically registered app by its ID
+// and hash of its registration access token.
+func (s *Storage) ClientByRegistrationToken(ctx context.Context, appID int, tokenHash string) (models.App, error) {
+	const op = "storage.postgresql.ClientByRegistrationToken"
+
+	app, err := s.scanApp(s.DB.QueryRowContext(
+		ctx,
+		selectApps+" WHERE id = $1 AND registration_token_hash = $2",
+		appID, tokenHash,
+	))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.App{}, fmt.Errorf("%s: %w", op, storage.ErrAppNotFound)
+		}
+
+		return models.App{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return app, nil
+}
+
+// UpdateClient replaces metadata of dynamically registered app.
+func (s *Storage) UpdateClient(ctx context.Context, appID int, metadata models.ClientMetadata) error {
+	const op = "storage.postgresql.UpdateClient"
+
+	res, err := s.DB.ExecContext(
+		ctx,
+		`UPDATE apps SET name = $1, redirect_uris = $2, grant_types = $3, token_endpoint_auth_method = $4
+		WHERE id = $5 AND registration_token_hash IS NOT NULL`,
+		metadata.Name, pq.Array(metadata.RedirectURIs), pq.Array(metadata.GrantTypes),
+		metadata.TokenEndpointAuthMethod, appID,
+	)
+	if err != nil {
+		if pgErrorCode(err) == codeUniqueViolation {
+			return fmt.Errorf("%s: %w", op, storage.ErrAppExists)
+		}
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrAppNotFound)
+	}
+
+	return nil
+}
@@ -0,0 +1,197 @@
+package postgresql
+
+import (
+	"SSO/internal/domain/models"
+	"SSO/internal/storage"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/lib/pq"
+)
+
+const selectConsents = `SELECT user_id, app_id, scopes, granted_at, updated_at FROM consents`
+
+// SaveScope creates scope of the app or updates its description.
+func (s *Storage) SaveScope(ctx context.Context, scope models.Scope) error {
+	const op = "storage.postgresql.SaveScope"
+
+	_, err := s.DB.ExecContext(
+		ctx,
+		`INSERT INTO app_scopes(app_id, name, description) VALUES($1, $2, $3)
+		ON CONFLICT (app_id, name) DO UPDATE SET description = EXCLUDED.description`,
+		scope.AppID, scope.Name, scope.Description,
+	)
+	if err != nil {
+		if pgErrorCode(err) == codeForeignKeyViolation {
+			return fmt.Errorf("%s: %w", op, storage.ErrAppNotFound)
+		}
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// AppScopes returns scopes defined for the app.
+func (s *Storage) AppScopes(ctx context.Context, appID int) ([]models.Scope, error) {
+	const op = "storage.postgresql.AppScopes"
+
+	rows, err := s.DB.QueryContext(
+		ctx,
+		"SELECT app_id, name, description, created_at FROM app_scopes WHERE app_id = $1 ORDER BY name",
+		appID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var scopes []models.Scope
+	for rows.Next() {
+		var scope models.Scope
+		if err := rows.Scan(&scope.AppID, &scope.Name, &scope.Description, &scope.CreatedAt); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		scopes = append(scopes, scope)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return scopes, nil
+}
+
+// DeleteScope deletes scope of the app and removes it from consents
+// granted to the app.
+func (s *Storage) DeleteScope(ctx context.Context, appID int, name string) error {
+	const op = "storage.postgresql.DeleteScope"
+
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, "DELETE FROM app_scopes WHERE app_id = $1 AND name = $2", appID, name)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrScopeNotFound)
+	}
+
+	_, err = tx.ExecContext(
+		ctx,
+		"UPDATE consents SET scopes = array_remove(scopes, $2), updated_at = now() WHERE app_id = $1",
+		appID, name,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// SaveConsent adds scopes to the consent the user granted to the app and
+// returns the updated consent.
+func (s *Storage) SaveConsent(ctx context.Context, userID int64, appID int, scopes []string) (models.Consent, error) {
+	const op = "storage.postgresql.SaveConsent"
+
+	consent, err := scanConsent(s.DB.QueryRowContext(
+		ctx,
+		`INSERT INTO consents(user_id, app_id, scopes) VALUES($1, $2, $3)
+		ON CONFLICT (user_id, app_id) DO UPDATE SET
+			scopes = ARRAY(SELECT DISTINCT unnest(consents.scopes || EXCLUDED.scopes) ORDER BY 1),
+			updated_at = now()
+		RETURNING user_id, app_id, scopes, granted_at, updated_at`,
+		userID, appID, pq.Array(scopes),
+	))
+	if err != nil {
+		if pgErrorCode(err) == codeForeignKeyViolation {
+			return models.Consent{}, fmt.Errorf("%s: %w", op, storage.ErrAppNotFound)
+		}
+
+		return models.Consent{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return consent, nil
+}
+
+// Consent returns consent the user granted to the app.
+func (s *Storage) Consent(ctx context.Context, userID int64, appID int) (models.Consent, error) {
+	const op = "storage.postgresql.Consent"
+
+	consent, err := scanConsent(s.DB.QueryRowContext(
+		ctx,
+		selectConsents+" WHERE user_id = $1 AND app_id = $2",
+		userID, appID,
+	))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Consent{}, fmt.Errorf("%s: %w", op, storage.ErrConsentNotFound)
+		}
+
+		return models.Consent{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return consent, nil
+}
+
+// Consents returns consents the user granted.
+func (s *Storage) Consents(ctx context.Context, userID int64) ([]models.Consent, error) {
+	const op = "storage.postgresql.Consents"
+
+	rows, err := s.DB.QueryContext(ctx, selectConsents+" WHERE user_id = $1 ORDER BY app_id", userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var consents []models.Consent
+	for rows.Next() {
+		consent, err := scanConsent(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		consents = append(consents, consent)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return consents, nil
+}
+
+// DeleteConsent deletes consent the user granted to the app.
+func (s *Storage) DeleteConsent(ctx context.Context, userID int64, appID int) error {
+	const op = "storage.postgresql.DeleteConsent"
+
+	res, err := s.DB.ExecContext(ctx, "DELETE FROM consents WHERE user_id = $1 AND app_id = $2", userID, appID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrConsentNotFound)
+	}
+
+	return nil
+}
+
+func scanConsent(row rowScanner) (models.Consent, error) {
+	var consent models.Consent
+	err := row.Scan(&consent.UserID, &consent.AppID, pq.Array(&consent.Scopes), &consent.GrantedAt, &consent.UpdatedAt)
+	if err != nil {
+		return models.Consent{}, err
+	}
+
+	return consent, nil
+}
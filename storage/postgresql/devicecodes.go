@@ -0,0 +1,168 @@
+package postgresql
+
+import (
+	"SSO/internal/domain/models"
+	"SSO/internal/storage"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/lib/pq"
+	"time"
+)
+
+const selectDeviceCodes = `SELECT id, app_id, user_code, scopes, status, COALESCE(user_id, 0), poll_interval,
+	last_polled_at, expires_at, created_at FROM device_codes`
+
+// SaveDeviceCode saves device authorization request with hash of its
+// device code and returns it with ID and creation time set.
+func (s *Storage) SaveDeviceCode(ctx context.Context, code models.DeviceCode, deviceCodeHash string) (models.DeviceCode, error) {
+	const op = "storage.postgresql.SaveDeviceCode"
+
+	err := s.DB.QueryRowContext(
+		ctx,
+		`INSERT INTO device_codes(app_id, device_code_hash, user_code, scopes, poll_interval, expires_at)
+		VALUES($1, $2, $3, $4, $5, $6) RETURNING id, status, created_at`,
+		code.AppID, deviceCodeHash, code.UserCode, pq.Array(code.Scopes), int(code.Interval.Seconds()), code.ExpiresAt,
+	).Scan(&code.ID, &code.Status, &code.CreatedAt)
+	if err != nil {
+		switch pgErrorCode(err) {
+		case codeUniqueViolation:
+			return models.DeviceCode{}, fmt.Errorf("%s: %w", op, storage.ErrDeviceCodeExists)
+		case codeForeignKeyViolation:
+			return models.DeviceCode{}, fmt.Errorf("%s: %w", op, storage.ErrAppNotFound)
+		}
+
+		return models.DeviceCode{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return code, nil
+}
+
+// DeviceCodeByUserCode returns device authorization request by its user code.
+func (s *Storage) DeviceCodeByUserCode(ctx context.Context, userCode string) (models.DeviceCode, error) {
+	const op = "storage.postgresql.DeviceCodeByUserCode"
+
+	code, err := scanDeviceCode(s.DB.QueryRowContext(ctx, selectDeviceCodes+" WHERE user_code = $1", userCode))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.DeviceCode{}, fmt.Errorf("%s: %w", op, storage.ErrDeviceCodeNotFound)
+		}
+
+		return models.DeviceCode{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return code, nil
+}
+
+// DeviceCodeByHash returns device authorization request by hash of its device code.
+func (s *Storage) DeviceCodeByHash(ctx context.Context, deviceCodeHash string) (models.DeviceCode, error) {
+	const op = "storage.postgresql.DeviceCodeByHash"
+
+	code, err := scanDeviceCode(s.DB.QueryRowContext(ctx, selectDeviceCodes+" WHERE device_code_hash = $1", deviceCodeHash))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.DeviceCode{}, fmt.Errorf("%s: %w", op, storage.ErrDeviceCodeNotFound)
+		}
+
+		return models.DeviceCode{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return code, nil
+}
+
+// DecideDeviceCode records the user's decision on pending device
+// authorization request.
+func (s *Storage) DecideDeviceCode(ctx context.Context, id, userID int64, status string) error {
+	const op = "storage.postgresql.DecideDeviceCode"
+
+	res, err := s.DB.ExecContext(
+		ctx,
+		"UPDATE device_codes SET status = $1, user_id = $2 WHERE id = $3 AND status = 'pending'",
+		status, userID, id,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrDeviceCodeNotFound)
+	}
+
+	return nil
+}
+
+// TouchDeviceCode records poll of the device and its current poll interval.
+func (s *Storage) TouchDeviceCode(ctx context.Context, id int64, polledAt time.Time, interval time.Duration) error {
+	const op = "storage.postgresql.TouchDeviceCode"
+
+	_, err := s.DB.ExecContext(
+		ctx,
+		"UPDATE device_codes SET last_polled_at = $1, poll_interval = $2 WHERE id = $3",
+		polledAt, int(interval.Seconds()), id,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// DeleteDeviceCode deletes device authorization request.
+func (s *Storage) DeleteDeviceCode(ctx context.Context, id int64) error {
+	const op = "storage.postgresql.DeleteDeviceCode"
+
+	res, err := s.DB.ExecContext(ctx, "DELETE FROM device_codes WHERE id = $1", id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrDeviceCodeNotFound)
+	}
+
+	return nil
+}
+
+// DeleteExpiredDeviceCodes deletes device authorization requests expired
+// before the time and returns how many were deleted.
+func (s *Storage) DeleteExpiredDeviceCodes(ctx context.Context, before time.Time) (int64, error) {
+	const op = "storage.postgresql.DeleteExpiredDeviceCodes"
+
+	res, err := s.DB.ExecContext(ctx, "DELETE FROM device_codes WHERE expires_at <= $1", before)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	n, _ := res.RowsAffected()
+
+	return n, nil
+}
+
+func scanDeviceCode(row rowScanner) (models.DeviceCode, error) {
+	var (
+		code     models.DeviceCode
+		interval int
+		polledAt sql.NullTime
+	)
+	err := row.Scan(
+		&code.ID,
+		&code.AppID,
+		&code.UserCode,
+		pq.Array(&code.Scopes),
+		&code.Status,
+		&code.UserID,
+		&interval,
+		&polledAt,
+		&code.ExpiresAt,
+		&code.CreatedAt,
+	)
+	if err != nil {
+		return models.DeviceCode{}, err
+	}
+
+	code.Interval = time.Duration(interval) * time.Second
+	code.LastPolledAt = polledAt.Time
+
+	return code, nil
+}
@@ -0,0 +1,144 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+)
+
+// encryptedColumn is a column encrypted with the storage cipher.
+//
+// Every column keeping a secret in a recoverable form must be listed in
+// encryptedColumns, so it is re-encrypted when the key is rotated.
+type encryptedColumn struct {
+	table  string
+	column string
+	key    string // Expression identifying the row, unique within the table.
+}
+
+var (
+	appSecretColumn          = encryptedColumn{table: "apps", column: "secret", key: "id::TEXT"}
+	formerAppSecretColumn    = encryptedColumn{table: "app_secrets", column: "secret", key: "app_id || ':' || kid"}
+	federationNonceColumn    = encryptedColumn{table: "federation_states", column: "nonce", key: "state_hash"}
+	federationVerifierColumn = encryptedColumn{table: "federation_states", column: "code_verifier", key: "state_hash"}
+)
+
+var encryptedColumns = []encryptedColumn{
+	appSecretColumn,
+	formerAppSecretColumn,
+	federationNonceColumn,
+	federationVerifierColumn,
+}
+
+// aad returns associated data binding values of the column to the row
+// identified by key, so a value copied to another row or column doesn't
+// decrypt.
+func (c encryptedColumn) aad(key string) []byte {
+	return []byte(c.table + "." + c.column + ":" + key)
+}
+
+// encrypt encrypts value of the column in the row identified by key.
+func (s *Storage) encrypt(col encryptedColumn, key, value string) (string, error) {
+	return s.cipher.Encrypt(value, col.aad(key))
+}
+
+// decrypt decrypts value of the column in the row identified by key.
+func (s *Storage) decrypt(col encryptedColumn, key, value string) (string, error) {
+	return s.cipher.Decrypt(value, col.aad(key))
+}
+
+// appKey returns key of the app row as selected by appSecretColumn.
+func appKey(appID int) string {
+	return strconv.Itoa(appID)
+}
+
+// formerAppSecretKey returns key of the former app secret row as selected
+// by formerAppSecretColumn.
+func formerAppSecretKey(appID int, kid string) string {
+	return appKey(appID) + ":" + kid
+}
+
+// ReencryptColumns decrypts and encrypts again every encrypted value which
+// is stale, e.g. plaintext or encrypted with a retired key encryption key.
+// It returns the number of re-encrypted values.
+func (s *Storage) ReencryptColumns(ctx context.Context, stale func(value string) bool) (int, error) {
+	const op = "storage.postgresql.ReencryptColumns"
+
+	var total int
+	for _, col := range encryptedColumns {
+		n, err := s.reencryptColumn(ctx, col, stale)
+		if err != nil {
+			return total, fmt.Errorf("%s: %s.%s: %w", op, col.table, col.column, err)
+		}
+		total += n
+	}
+
+	return total, nil
+}
+
+func (s *Storage) reencryptColumn(ctx context.Context, col encryptedColumn, stale func(string) bool) (int, error) {
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(
+		ctx,
+		fmt.Sprintf("SELECT %s, %s FROM %s FOR UPDATE", col.key, col.column, col.table),
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	updates := make(map[string]string)
+	for rows.Next() {
+		var key, value string
+		if err := rows.Scan(&key, &value); err != nil {
+			rows.Close()
+			return 0, err
+		}
+
+		if stale(value) {
+			updates[key] = value
+		}
+	}
+	rows.Close()
+
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	for key, value := range updates {
+		if err := s.reencryptValue(ctx, tx, col, key, value); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	return len(updates), nil
+}
+
+func (s *Storage) reencryptValue(ctx context.Context, tx *sql.Tx, col encryptedColumn, key, value string) error {
+	plaintext, err := s.decrypt(col, key, value)
+	if err != nil {
+		return err
+	}
+
+	encrypted, err := s.encrypt(col, key, plaintext)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(
+		ctx,
+		fmt.Sprintf("UPDATE %s SET %s = $1 WHERE %s = $2", col.table, col.column, col.key),
+		encrypted, key,
+	)
+
+	return err
+}
@@ -0,0 +1,43 @@
+package postgresql
+
+import (
+	"SSO/internal/lib/envelope"
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncryptedColumns_BoundToRow(t *testing.T) {
+	s := newTestStorage(t)
+	s.cipher = envelope.New(envelope.NewFileKEK(bytes.Repeat([]byte{1}, envelope.KEKSize)))
+	ctx := context.Background()
+
+	appID, otherID := saveTestApp(t, s), saveTestApp(t, s)
+
+	app, err := s.App(ctx, appID)
+	require.NoError(t, err)
+
+	require.NoError(t, s.RotateAppSecret(ctx, appID, "second secret", "second", time.Now().Add(time.Hour)))
+
+	got, err := s.App(ctx, appID)
+	require.NoError(t, err)
+	assert.Equal(t, "second secret", got.Secret)
+
+	former, err := s.AppSecret(ctx, appID, app.SecretKID)
+	require.NoError(t, err)
+	assert.Equal(t, app.Secret, former.Secret, "former secret is encrypted for its new row")
+
+	_, err = s.DB.ExecContext(
+		ctx,
+		"UPDATE apps SET secret = (SELECT secret FROM apps WHERE id = $1) WHERE id = $2",
+		appID, otherID,
+	)
+	require.NoError(t, err)
+
+	_, err = s.App(ctx, otherID)
+	assert.Error(t, err, "secret copied to another app must not decrypt")
+}
@@ -0,0 +1,100 @@
+package postgresql
+
+import (
+	"SSO/internal/domain/models"
+	"SSO/internal/storage"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/lib/pq"
+)
+
+// SaveFederationState saves sign in through an upstream provider by hash of
+// its state and deletes expired ones.
+func (s *Storage) SaveFederationState(ctx context.Context, state models.FederationState, stateHash string) error {
+	const op = "storage.postgresql.SaveFederationState"
+
+	nonce, err := s.encrypt(federationNonceColumn, stateHash, state.Nonce)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	verifier, err := s.encrypt(federationVerifierColumn, stateHash, state.CodeVerifier)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM federation_states WHERE expires_at <= now()"); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = tx.ExecContext(
+		ctx,
+		`INSERT INTO federation_states(state_hash, provider, app_id, scopes, nonce, code_verifier, return_to,
+		link_user_id, expires_at) VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+		stateHash, state.Provider, state.AppID, pq.Array(state.Scopes), nonce, verifier, state.ReturnTo,
+		sql.NullInt64{Int64: state.LinkUserID, Valid: state.LinkUserID != 0}, state.ExpiresAt,
+	)
+	if err != nil {
+		if pgErrorCode(err) == codeForeignKeyViolation {
+			return fmt.Errorf("%s: %w", op, storage.ErrAppNotFound)
+		}
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// ConsumeFederationState deletes sign in through an upstream provider by
+// hash of its state and returns it, so every state is used once.
+func (s *Storage) ConsumeFederationState(ctx context.Context, stateHash string) (models.FederationState, error) {
+	const op = "storage.postgresql.ConsumeFederationState"
+
+	var state models.FederationState
+	err := s.DB.QueryRowContext(
+		ctx,
+		`DELETE FROM federation_states WHERE state_hash = $1
+		RETURNING provider, app_id, scopes, nonce, code_verifier, return_to, COALESCE(link_user_id, 0), expires_at`,
+		stateHash,
+	).Scan(
+		&state.Provider,
+		&state.AppID,
+		pq.Array(&state.Scopes),
+		&state.Nonce,
+		&state.CodeVerifier,
+		&state.ReturnTo,
+		&state.LinkUserID,
+		&state.ExpiresAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.FederationState{}, fmt.Errorf("%s: %w", op, storage.ErrStateNotFound)
+		}
+
+		return models.FederationState{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	state.Nonce, err = s.decrypt(federationNonceColumn, stateHash, state.Nonce)
+	if err != nil {
+		return models.FederationState{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	state.CodeVerifier, err = s.decrypt(federationVerifierColumn, stateHash, state.CodeVerifier)
+	if err != nil {
+		return models.FederationState{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return state, nil
+}
@@ -0,0 +1,487 @@
+package postgresql
+
+import (
+	"SSO/internal/domain/models"
+	"SSO/internal/storage"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/lib/pq"
+)
+
+// SaveFollow makes the follower follow the user and returns status of the
+// follow: accepted and counted if the user's account is public, pending
+// approval otherwise. Following the user again is not an error, status of
+// the former follow is returned then.
+func (s *Storage) SaveFollow(ctx context.Context, followerID, followingID int64) (string, error) {
+	const op = "storage.postgresql.SaveFollow"
+
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	// The lock also keeps the account from going public in between, which
+	// would leave the request pending.
+	private, err := lockFollowUsers(ctx, tx, followerID, followingID)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	status := models.FollowAccepted
+	if private {
+		status = models.FollowPending
+	}
+
+	res, err := tx.ExecContext(
+		ctx,
+		`INSERT INTO followers(follower_id, following_id, status) VALUES($1, $2, $3)
+		ON CONFLICT (follower_id, following_id) DO NOTHING`,
+		followerID, followingID, status,
+	)
+	if err != nil {
+		if pgErrorCode(err) == codeForeignKeyViolation {
+			return "", fmt.Errorf("%s: %w", op, storage.ErrUserNotFound)
+		}
+
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	switch {
+	case n == 0:
+		err = tx.QueryRowContext(
+			ctx,
+			"SELECT status FROM followers WHERE follower_id = $1 AND following_id = $2",
+			followerID, followingID,
+		).Scan(&status)
+	case status == models.FollowAccepted:
+		err = addFollowCounts(ctx, tx, followerID, followingID, 1)
+	}
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	return status, nil
+}
+
+// DeleteFollow makes the follower stop following the user or cancels the
+// request to follow, and uncounts accepted follows. Unfollowing a user who
+// isn't followed is not an error.
+func (s *Storage) DeleteFollow(ctx context.Context, followerID, followingID int64) error {
+	const op = "storage.postgresql.DeleteFollow"
+
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	if _, err := lockFollowUsers(ctx, tx, followerID, followingID); err != nil {
+		if errors.Is(err, storage.ErrUserNotFound) {
+			return nil
+		}
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	var status string
+	err = tx.QueryRowContext(
+		ctx,
+		"DELETE FROM followers WHERE follower_id = $1 AND following_id = $2 RETURNING status",
+		followerID, followingID,
+	).Scan(&status)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if status == models.FollowAccepted {
+		if err := addFollowCounts(ctx, tx, followerID, followingID, -1); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// AcceptFollow approves the pending request of the follower to follow the
+// user and counts the follow.
+func (s *Storage) AcceptFollow(ctx context.Context, followerID, followingID int64) error {
+	const op = "storage.postgresql.AcceptFollow"
+
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	if _, err := lockFollowUsers(ctx, tx, followerID, followingID); err != nil {
+		if errors.Is(err, storage.ErrUserNotFound) {
+			return fmt.Errorf("%s: %w", op, storage.ErrFollowRequestNotFound)
+		}
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := tx.ExecContext(
+		ctx,
+		`UPDATE followers SET status = $3
+		WHERE follower_id = $1 AND following_id = $2 AND status = $4`,
+		followerID, followingID, models.FollowAccepted, models.FollowPending,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if n == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrFollowRequestNotFound)
+	}
+
+	if err := addFollowCounts(ctx, tx, followerID, followingID, 1); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// DeleteFollowRequest denies the pending request of the follower to follow
+// the user.
+func (s *Storage) DeleteFollowRequest(ctx context.Context, followerID, followingID int64) error {
+	const op = "storage.postgresql.DeleteFollowRequest"
+
+	res, err := s.DB.ExecContext(
+		ctx,
+		"DELETE FROM followers WHERE follower_id = $1 AND following_id = $2 AND status = $3",
+		followerID, followingID, models.FollowPending,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if n == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrFollowRequestNotFound)
+	}
+
+	return nil
+}
+
+// SetUserPrivate sets whether follows of the user need the user's
+// approval. Pending requests are approved and counted when the account
+// goes public; it returns how many.
+func (s *Storage) SetUserPrivate(ctx context.Context, userID int64, private bool) (int64, error) {
+	const op = "storage.postgresql.SetUserPrivate"
+
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	// Approved followers are counted too, so all the users are locked in
+	// order of their IDs up front as follows lock them.
+	if !private {
+		_, err = tx.ExecContext(
+			ctx,
+			`SELECT id FROM users
+			WHERE id = $1 OR id IN (SELECT follower_id FROM followers WHERE following_id = $1 AND status = $2)
+			ORDER BY id FOR NO KEY UPDATE`,
+			userID, models.FollowPending,
+		)
+		if err != nil {
+			return 0, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	res, err := tx.ExecContext(ctx, "UPDATE users SET is_private = $2 WHERE id = $1", userID, private)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if n == 0 {
+		return 0, fmt.Errorf("%s: %w", op, storage.ErrUserNotFound)
+	}
+
+	var approved []int64
+	if !private {
+		approved, err = approveFollowRequests(ctx, tx, userID)
+		if err != nil {
+			return 0, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return int64(len(approved)), nil
+}
+
+// Followers returns up to limit followers of the user with the status who
+// followed before the follow with beforeID, or the latest ones if it's 0.
+func (s *Storage) Followers(
+	ctx context.Context,
+	userID int64,
+	status string,
+	beforeID int64,
+	limit int,
+) ([]models.FollowEntry, error) {
+	const op = "storage.postgresql.Followers"
+
+	entries, err := s.follows(
+		ctx,
+		`SELECT f.id, u.id, u.username FROM followers f
+		JOIN users u ON u.id = f.follower_id
+		WHERE f.following_id = $1 AND f.status = $2 AND ($3 = 0 OR f.id < $3)
+		ORDER BY f.id DESC LIMIT $4`,
+		userID, status, beforeID, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return entries, nil
+}
+
+// Following returns up to limit users the user followed with the status
+// before the follow with beforeID, or the latest ones if it's 0.
+func (s *Storage) Following(
+	ctx context.Context,
+	userID int64,
+	status string,
+	beforeID int64,
+	limit int,
+) ([]models.FollowEntry, error) {
+	const op = "storage.postgresql.Following"
+
+	entries, err := s.follows(
+		ctx,
+		`SELECT f.id, u.id, u.username FROM followers f
+		JOIN users u ON u.id = f.following_id
+		WHERE f.follower_id = $1 AND f.status = $2 AND ($3 = 0 OR f.id < $3)
+		ORDER BY f.id DESC LIMIT $4`,
+		userID, status, beforeID, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return entries, nil
+}
+
+// FollowCounts returns follower and following counts of the users, users
+// that don't exist are skipped.
+func (s *Storage) FollowCounts(ctx context.Context, userIDs []int64) ([]models.FollowCounts, error) {
+	const op = "storage.postgresql.FollowCounts"
+
+	rows, err := s.DB.QueryContext(
+		ctx,
+		"SELECT id, followers_count, following_count, is_private FROM users WHERE id = ANY($1)",
+		pq.Array(userIDs),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var counts []models.FollowCounts
+	for rows.Next() {
+		var c models.FollowCounts
+		if err := rows.Scan(&c.UserID, &c.Followers, &c.Following, &c.Private); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		counts = append(counts, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return counts, nil
+}
+
+// FollowsBetween returns follows and requests to follow between the user
+// and the other users in either direction.
+func (s *Storage) FollowsBetween(ctx context.Context, userID int64, otherIDs []int64) ([]models.Follow, error) {
+	const op = "storage.postgresql.FollowsBetween"
+
+	rows, err := s.DB.QueryContext(
+		ctx,
+		`SELECT id, follower_id, following_id, status FROM followers
+		WHERE (follower_id = $1 AND following_id = ANY($2))
+		OR (following_id = $1 AND follower_id = ANY($2))`,
+		userID, pq.Array(otherIDs),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var follows []models.Follow
+	for rows.Next() {
+		var f models.Follow
+		if err := rows.Scan(&f.ID, &f.FollowerID, &f.FollowingID, &f.Status); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		follows = append(follows, f)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return follows, nil
+}
+
+func (s *Storage) follows(ctx context.Context, query string, args ...any) ([]models.FollowEntry, error) {
+	rows, err := s.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []models.FollowEntry
+	for rows.Next() {
+		var e models.FollowEntry
+		if err := rows.Scan(&e.ID, &e.UserID, &e.Username); err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+
+	return entries, rows.Err()
+}
+
+// approveFollowRequests accepts pending requests to follow the user and
+// counts them, returning IDs of the followers.
+func approveFollowRequests(ctx context.Context, tx *sql.Tx, userID int64) ([]int64, error) {
+	rows, err := tx.QueryContext(
+		ctx,
+		"UPDATE followers SET status = $2 WHERE following_id = $1 AND status = $3 RETURNING follower_id",
+		userID, models.FollowAccepted, models.FollowPending,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var followerIDs []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		followerIDs = append(followerIDs, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(followerIDs) == 0 {
+		return nil, nil
+	}
+
+	_, err = tx.ExecContext(
+		ctx,
+		`UPDATE users SET
+			followers_count = followers_count + CASE WHEN id = $1 THEN $3::integer ELSE 0 END,
+			following_count = following_count + CASE WHEN id = ANY($2) THEN 1 ELSE 0 END
+		WHERE id = $1 OR id = ANY($2)`,
+		userID, pq.Array(followerIDs), len(followerIDs),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return followerIDs, nil
+}
+
+// lockFollowUsers locks rows of both users of the follow in order of their
+// IDs, so that follows counted concurrently, e.g. of the same user or
+// mutual ones, don't deadlock. It returns whether the account of the
+// followed user is private.
+func lockFollowUsers(ctx context.Context, tx *sql.Tx, followerID, followingID int64) (bool, error) {
+	rows, err := tx.QueryContext(
+		ctx,
+		"SELECT id, is_private FROM users WHERE id IN ($1, $2) ORDER BY id FOR NO KEY UPDATE",
+		followerID, followingID,
+	)
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+
+	found, private := false, false
+	for rows.Next() {
+		var id int64
+		var isPrivate bool
+		if err := rows.Scan(&id, &isPrivate); err != nil {
+			return false, err
+		}
+		if id == followingID {
+			found, private = true, isPrivate
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return false, err
+	}
+
+	if !found {
+		return false, storage.ErrUserNotFound
+	}
+
+	return private, nil
+}
+
+// addFollowCounts adds delta to counts of both users of the follow. Rows of
+// the users must be locked with lockFollowUsers first.
+func addFollowCounts(ctx context.Context, tx *sql.Tx, followerID, followingID int64, delta int) error {
+	_, err := tx.ExecContext(
+		ctx,
+		`UPDATE users SET
+			followers_count = followers_count + CASE WHEN id = $2 THEN $3::integer ELSE 0 END,
+			following_count = following_count + CASE WHEN id = $1 THEN $3::integer ELSE 0 END
+		WHERE id IN ($1, $2)`,
+		followerID, followingID, delta,
+	)
+
+	return err
+}
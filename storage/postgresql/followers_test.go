@@ -0,0 +1,136 @@
+package postgresql
+
+import (
+	"SSO/internal/domain/models"
+	"SSO/internal/storage"
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// followCounts returns follow counts of the user.
+func followCounts(t *testing.T, s *Storage, userID int64) models.FollowCounts {
+	t.Helper()
+
+	counts, err := s.FollowCounts(context.Background(), []int64{userID})
+	require.NoError(t, err)
+	require.Len(t, counts, 1)
+
+	return counts[0]
+}
+
+// deleteFollowsOnCleanup deletes follows of the users before the users are
+// deleted, follows don't cascade.
+func deleteFollowsOnCleanup(t *testing.T, s *Storage, userIDs ...int64) {
+	t.Helper()
+
+	t.Cleanup(func() {
+		_, _ = s.DB.Exec(
+			"DELETE FROM followers WHERE follower_id = ANY($1) OR following_id = ANY($1)",
+			pq.Array(userIDs),
+		)
+	})
+}
+
+// saveFollows saves the follows concurrently and returns their errors.
+func saveFollows(s *Storage, follows [][2]int64) []error {
+	errs := make([]error, len(follows))
+
+	var wg sync.WaitGroup
+	for i, f := range follows {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, errs[i] = s.SaveFollow(context.Background(), f[0], f[1])
+		}()
+	}
+	wg.Wait()
+
+	return errs
+}
+
+func TestSaveFollow_Concurrent(t *testing.T) {
+	s := newTestStorage(t)
+	userID := saveTestUser(t, s)
+
+	const n = 20
+	followerIDs := make([]int64, 0, n)
+	follows := make([][2]int64, 0, n)
+	for i := 0; i < n; i++ {
+		followerID := saveTestUser(t, s)
+		followerIDs = append(followerIDs, followerID)
+		follows = append(follows, [2]int64{followerID, userID})
+	}
+	deleteFollowsOnCleanup(t, s, userID)
+
+	for _, err := range saveFollows(s, follows) {
+		assert.NoError(t, err)
+	}
+
+	assert.Equal(t, int64(n), followCounts(t, s, userID).Followers)
+	for _, followerID := range followerIDs {
+		assert.Equal(t, int64(1), followCounts(t, s, followerID).Following)
+	}
+}
+
+func TestSaveFollow_Mutual(t *testing.T) {
+	s := newTestStorage(t)
+
+	const n = 10
+	pairs := make([][2]int64, 0, n)
+	follows := make([][2]int64, 0, 2*n)
+	for i := 0; i < n; i++ {
+		a, b := saveTestUser(t, s), saveTestUser(t, s)
+		pairs = append(pairs, [2]int64{a, b})
+		follows = append(follows, [2]int64{a, b}, [2]int64{b, a})
+		deleteFollowsOnCleanup(t, s, a, b)
+	}
+
+	for _, err := range saveFollows(s, follows) {
+		assert.NoError(t, err, "mutual follows don't deadlock")
+	}
+
+	for _, p := range pairs {
+		for _, userID := range p {
+			counts := followCounts(t, s, userID)
+			assert.Equal(t, int64(1), counts.Followers)
+			assert.Equal(t, int64(1), counts.Following)
+		}
+	}
+}
+
+func TestSaveFollow_Private(t *testing.T) {
+	s := newTestStorage(t)
+	ctx := context.Background()
+	userID, followerID := saveTestUser(t, s), saveTestUser(t, s)
+	deleteFollowsOnCleanup(t, s, userID)
+
+	_, err := s.SetUserPrivate(ctx, userID, true)
+	require.NoError(t, err)
+
+	status, err := s.SaveFollow(ctx, followerID, userID)
+	require.NoError(t, err)
+	assert.Equal(t, models.FollowPending, status)
+	assert.Zero(t, followCounts(t, s, userID).Followers, "pending follows are not counted")
+
+	status, err = s.SaveFollow(ctx, followerID, userID)
+	require.NoError(t, err)
+	assert.Equal(t, models.FollowPending, status, "following again returns the former status")
+
+	approved, err := s.SetUserPrivate(ctx, userID, false)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), approved)
+	assert.Equal(t, int64(1), followCounts(t, s, userID).Followers)
+	assert.Equal(t, int64(1), followCounts(t, s, followerID).Following)
+
+	require.NoError(t, s.DeleteFollow(ctx, followerID, userID))
+	assert.Zero(t, followCounts(t, s, userID).Followers)
+	assert.Zero(t, followCounts(t, s, followerID).Following)
+
+	_, err = s.SaveFollow(ctx, followerID, -1)
+	assert.ErrorIs(t, err, storage.ErrUserNotFound)
+}
@@ -0,0 +1,403 @@
+package postgresql
+
+import (
+	"SSO/internal/domain/models"
+	"SSO/internal/storage"
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/lib/pq"
+)
+
+// userGroupsCTE resolves groups the user belongs to directly or through
+// nested groups. User ID is the first argument of the query.
+// UNION (not UNION ALL) stops the recursion on already visited groups.
+const userGroupsCTE = `WITH RECURSIVE user_groups(group_id) AS (
+	SELECT group_id FROM group_members WHERE user_id = $1
+	UNION
+	SELECT gs.parent_id FROM group_subgroups gs JOIN user_groups ug ON ug.group_id = gs.child_id
+)`
+
+// userRolesCTE resolves IDs of the roles assigned to the user directly
+// or through any of its groups. User ID is the first argument of the query.
+const userRolesCTE = userGroupsCTE + `, user_role_ids(role_id) AS (
+	SELECT role_id FROM user_roles WHERE user_id = $1
+	UNION
+	SELECT gr.role_id FROM group_roles gr JOIN user_groups ug ON ug.group_id = gr.group_id
+)`
+
+const selectGroups = `SELECT g.id, g.app_id, g.name, g.created_at,
+	COALESCE((SELECT array_agg(r.name ORDER BY r.name)
+		FROM group_roles gr JOIN roles r ON r.id = gr.role_id
+		WHERE gr.group_id = g.id), '{}'),
+	COALESCE((SELECT array_agg(gs.child_id ORDER BY gs.child_id)
+		FROM group_subgroups gs
+		WHERE gs.parent_id = g.id), '{}')
+	FROM groups g`
+
+// SaveGroup saves group of the app to database.
+func (s *Storage) SaveGroup(ctx context.Context, appID int, name string) (int64, error) {
+	const op = "storage.postgresql.SaveGroup"
+
+	var id int64
+	err := s.DB.QueryRowContext(
+		ctx,
+		"INSERT INTO groups(app_id, name) VALUES($1, $2) RETURNING id",
+		appID, name,
+	).Scan(&id)
+	if err != nil {
+		switch pgErrorCode(err) {
+		case codeUniqueViolation:
+			return 0, fmt.Errorf("%s: %w", op, storage.ErrGroupExists)
+		case codeForeignKeyViolation:
+			return 0, fmt.Errorf("%s: %w", op, storage.ErrAppNotFound)
+		}
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+// DeleteGroup deletes group of the app together with its memberships.
+func (s *Storage) DeleteGroup(ctx context.Context, appID int, groupID int64) error {
+	const op = "storage.postgresql.DeleteGroup"
+
+	res, err := s.DB.ExecContext(ctx, "DELETE FROM groups WHERE id = $1 AND app_id = $2", groupID, appID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrGroupNotFound)
+	}
+
+	return nil
+}
+
+// Groups returns groups of the app with their roles and direct subgroups.
+func (s *Storage) Groups(ctx context.Context, appID int) ([]models.Group, error) {
+	const op = "storage.postgresql.Groups"
+
+	groups, err := s.queryGroups(ctx, selectGroups+" WHERE g.app_id = $1 ORDER BY g.name", appID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return groups, nil
+}
+
+// UserGroups returns groups of the app user belongs to directly
+// or through nested groups.
+func (s *Storage) UserGroups(ctx context.Context, userID int64, appID int) ([]models.Group, error) {
+	const op = "storage.postgresql.UserGroups"
+
+	groups, err := s.queryGroups(
+		ctx,
+		userGroupsCTE+"\n"+selectGroups+`
+		JOIN user_groups ug ON ug.group_id = g.id
+		WHERE g.app_id = $2
+		ORDER BY g.name`,
+		userID, appID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return groups, nil
+}
+
+func (s *Storage) queryGroups(ctx context.Context, query string, args ...any) ([]models.Group, error) {
+	rows, err := s.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var groups []models.Group
+	for rows.Next() {
+		var g models.Group
+		err := rows.Scan(&g.ID, &g.AppID, &g.Name, &g.CreatedAt, pq.Array(&g.Roles), pq.Array(&g.Subgroups))
+		if err != nil {
+			return nil, err
+		}
+		groups = append(groups, g)
+	}
+
+	return groups, rows.Err()
+}
+
+// SaveGroupMember adds user to group of the app.
+// Adding already added user is not an error.
+func (s *Storage) SaveGroupMember(ctx context.Context, appID int, groupID, userID int64) error {
+	const op = "storage.postgresql.SaveGroupMember"
+
+	if err := checkGroups(ctx, s.DB, appID, groupID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err := s.DB.ExecContext(
+		ctx,
+		"INSERT INTO group_members(group_id, user_id) VALUES($1, $2) ON CONFLICT DO NOTHING",
+		groupID, userID,
+	)
+	if err != nil {
+		if pgErrorCode(err) == codeForeignKeyViolation {
+			return fmt.Errorf("%s: %w", op, storage.ErrUserNotFound)
+		}
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// DeleteGroupMember removes user from group of the app.
+func (s *Storage) DeleteGroupMember(ctx context.Context, appID int, groupID, userID int64) error {
+	const op = "storage.postgresql.DeleteGroupMember"
+
+	if err := checkGroups(ctx, s.DB, appID, groupID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := s.DB.ExecContext(
+		ctx,
+		"DELETE FROM group_members WHERE group_id = $1 AND user_id = $2",
+		groupID, userID,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrMemberNotFound)
+	}
+
+	return nil
+}
+
+// GroupMembers returns members of group of the app including members
+// of the nested groups.
+func (s *Storage) GroupMembers(ctx context.Context, appID int, groupID int64) ([]models.GroupMember, error) {
+	const op = "storage.postgresql.GroupMembers"
+
+	if err := checkGroups(ctx, s.DB, appID, groupID); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := s.DB.QueryContext(
+		ctx,
+		`WITH RECURSIVE subgroups(group_id) AS (
+			SELECT $1::INTEGER
+			UNION
+			SELECT gs.child_id FROM group_subgroups gs JOIN subgroups sg ON sg.group_id = gs.parent_id
+		)
+		SELECT u.id, u.email, bool_or(gm.group_id = $1)
+		FROM subgroups sg
+		JOIN group_members gm ON gm.group_id = sg.group_id
+		JOIN users u ON u.id = gm.user_id
+		GROUP BY u.id
+		ORDER BY u.email`,
+		groupID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var members []models.GroupMember
+	for rows.Next() {
+		m := models.GroupMember{GroupID: groupID}
+		if err := rows.Scan(&m.UserID, &m.Email, &m.Direct); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		members = append(members, m)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return members, nil
+}
+
+// SaveSubgroup nests child group into parent group, both must belong to the app.
+// Nesting which would make a cycle is refused with storage.ErrGroupCycle.
+func (s *Storage) SaveSubgroup(ctx context.Context, appID int, parentID, childID int64) error {
+	const op = "storage.postgresql.SaveSubgroup"
+
+	if parentID == childID {
+		return fmt.Errorf("%s: %w", op, storage.ErrGroupCycle)
+	}
+
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	// Concurrent nestings could make a cycle none of them sees alone.
+	if _, err := tx.ExecContext(ctx, "LOCK TABLE group_subgroups IN SHARE ROW EXCLUSIVE MODE"); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := checkGroups(ctx, tx, appID, parentID, childID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	var cycle bool
+	err = tx.QueryRowContext(
+		ctx,
+		`WITH RECURSIVE descendants(group_id) AS (
+			SELECT $1::INTEGER
+			UNION
+			SELECT gs.child_id FROM group_subgroups gs JOIN descendants d ON d.group_id = gs.parent_id
+		)
+		SELECT EXISTS(SELECT 1 FROM descendants WHERE group_id = $2)`,
+		childID, parentID,
+	).Scan(&cycle)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if cycle {
+		return fmt.Errorf("%s: %w", op, storage.ErrGroupCycle)
+	}
+
+	_, err = tx.ExecContext(
+		ctx,
+		"INSERT INTO group_subgroups(parent_id, child_id) VALUES($1, $2) ON CONFLICT DO NOTHING",
+		parentID, childID,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// DeleteSubgroup removes child group from parent group of the app.
+func (s *Storage) DeleteSubgroup(ctx context.Context, appID int, parentID, childID int64) error {
+	const op = "storage.postgresql.DeleteSubgroup"
+
+	res, err := s.DB.ExecContext(
+		ctx,
+		`DELETE FROM group_subgroups
+		WHERE parent_id = (SELECT id FROM groups WHERE id = $1 AND app_id = $3) AND child_id = $2`,
+		parentID, childID, appID,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrGroupNotFound)
+	}
+
+	return nil
+}
+
+// AssignGroupRole assigns role to group, both must belong to the app.
+// Assigning already assigned role is not an error.
+func (s *Storage) AssignGroupRole(ctx context.Context, appID int, groupID, roleID int64) error {
+	const op = "storage.postgresql.AssignGroupRole"
+
+	if err := s.checkGroupAndRole(ctx, appID, groupID, roleID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err := s.DB.ExecContext(
+		ctx,
+		"INSERT INTO group_roles(group_id, role_id) VALUES($1, $2) ON CONFLICT DO NOTHING",
+		groupID, roleID,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// UnassignGroupRole removes role from group of the app.
+func (s *Storage) UnassignGroupRole(ctx context.Context, appID int, groupID, roleID int64) error {
+	const op = "storage.postgresql.UnassignGroupRole"
+
+	if err := s.checkGroupAndRole(ctx, appID, groupID, roleID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err := s.DB.ExecContext(
+		ctx,
+		"DELETE FROM group_roles WHERE group_id = $1 AND role_id = $2",
+		groupID, roleID,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// SetGroupClaims sets whether tokens issued for the app carry groups of the user.
+func (s *Storage) SetGroupClaims(ctx context.Context, appID int, enabled bool) error {
+	const op = "storage.postgresql.SetGroupClaims"
+
+	res, err := s.DB.ExecContext(ctx, "UPDATE apps SET group_claims = $1 WHERE id = $2", enabled, appID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrAppNotFound)
+	}
+
+	return nil
+}
+
+func (s *Storage) checkGroupAndRole(ctx context.Context, appID int, groupID, roleID int64) error {
+	if err := checkGroups(ctx, s.DB, appID, groupID); err != nil {
+		return err
+	}
+
+	var roleExists bool
+	err := s.DB.QueryRowContext(
+		ctx,
+		"SELECT EXISTS(SELECT 1 FROM roles WHERE id = $1 AND app_id = $2)",
+		roleID, appID,
+	).Scan(&roleExists)
+	if err != nil {
+		return err
+	}
+
+	if !roleExists {
+		return storage.ErrRoleNotFound
+	}
+
+	return nil
+}
+
+type queryRower interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// checkGroups returns storage.ErrGroupNotFound unless all groups belong to the app.
+func checkGroups(ctx context.Context, q queryRower, appID int, groupIDs ...int64) error {
+	var found int
+	err := q.QueryRowContext(
+		ctx,
+		"SELECT count(*) FROM groups WHERE app_id = $1 AND id = ANY($2)",
+		appID, pq.Array(groupIDs),
+	).Scan(&found)
+	if err != nil {
+		return err
+	}
+
+	if found != len(groupIDs) {
+		return storage.ErrGroupNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,142 @@
+package postgresql
+
+import (
+	"SSO/internal/domain/models"
+	"SSO/internal/storage"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// saveTestGroups saves groups with given names in the app and returns their ids.
+func saveTestGroups(t *testing.T, s *Storage, appID int, names ...string) []int64 {
+	t.Helper()
+
+	ids := make([]int64, 0, len(names))
+	for _, name := range names {
+		id, err := s.SaveGroup(context.Background(), appID, name)
+		require.NoError(t, err)
+		ids = append(ids, id)
+	}
+
+	return ids
+}
+
+func groupNames(groups []models.Group) []string {
+	names := make([]string, 0, len(groups))
+	for _, g := range groups {
+		names = append(names, g.Name)
+	}
+
+	return names
+}
+
+func TestSaveSubgroup_Cycles(t *testing.T) {
+	s := newTestStorage(t)
+	ctx := context.Background()
+	appID := saveTestApp(t, s)
+	ids := saveTestGroups(t, s, appID, "a", "b", "c")
+	a, b, c := ids[0], ids[1], ids[2]
+
+	require.NoError(t, s.SaveSubgroup(ctx, appID, a, b))
+	require.NoError(t, s.SaveSubgroup(ctx, appID, b, c))
+	require.NoError(t, s.SaveSubgroup(ctx, appID, a, b), "nesting is idempotent")
+
+	assert.ErrorIs(t, s.SaveSubgroup(ctx, appID, a, a), storage.ErrGroupCycle)
+	assert.ErrorIs(t, s.SaveSubgroup(ctx, appID, b, a), storage.ErrGroupCycle)
+	assert.ErrorIs(t, s.SaveSubgroup(ctx, appID, c, a), storage.ErrGroupCycle, "cycles through several groups are refused")
+	require.NoError(t, s.SaveSubgroup(ctx, appID, a, c), "shortcut is not a cycle")
+
+	other := saveTestGroups(t, s, saveTestApp(t, s), "a")[0]
+	assert.ErrorIs(t, s.SaveSubgroup(ctx, appID, a, other), storage.ErrGroupNotFound, "groups of another app can't be nested")
+
+	require.NoError(t, s.DeleteSubgroup(ctx, appID, b, c))
+	require.NoError(t, s.DeleteSubgroup(ctx, appID, a, c))
+	require.NoError(t, s.SaveSubgroup(ctx, appID, c, a), "no cycle once nesting is removed")
+}
+
+func TestUserGroups_Depth(t *testing.T) {
+	s := newTestStorage(t)
+	ctx := context.Background()
+	appID := saveTestApp(t, s)
+	userID := saveTestUser(t, s)
+
+	names := []string{"g0", "g1", "g2", "g3", "g4", "g5"}
+	ids := saveTestGroups(t, s, appID, names...)
+	for i := 1; i < len(ids); i++ {
+		require.NoError(t, s.SaveSubgroup(ctx, appID, ids[i-1], ids[i]))
+	}
+	require.NoError(t, s.SaveGroupMember(ctx, appID, ids[len(ids)-1], userID))
+
+	groups, err := s.UserGroups(ctx, userID, appID)
+	require.NoError(t, err)
+	assert.Equal(t, names, groupNames(groups), "user belongs to every ancestor of its group")
+
+	require.NoError(t, s.DeleteSubgroup(ctx, appID, ids[2], ids[3]))
+	groups, err = s.UserGroups(ctx, userID, appID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"g3", "g4", "g5"}, groupNames(groups))
+
+	groups, err = s.UserGroups(ctx, userID, saveTestApp(t, s))
+	require.NoError(t, err)
+	assert.Empty(t, groups, "groups are scoped to the app")
+}
+
+func TestUserRoles_InheritedFromGroups(t *testing.T) {
+	s := newTestStorage(t)
+	ctx := context.Background()
+	appID := saveTestApp(t, s)
+	userID := saveTestUser(t, s)
+
+	ids := saveTestGroups(t, s, appID, "staff", "editors")
+	staff, editors := ids[0], ids[1]
+	require.NoError(t, s.SaveSubgroup(ctx, appID, staff, editors))
+	require.NoError(t, s.SaveGroupMember(ctx, appID, editors, userID))
+
+	roleID, err := s.SaveRole(ctx, appID, "reader")
+	require.NoError(t, err)
+	permissionID, err := s.SavePermission(ctx, appID, "posts.read")
+	require.NoError(t, err)
+	require.NoError(t, s.GrantPermission(ctx, appID, roleID, permissionID))
+	require.NoError(t, s.AssignGroupRole(ctx, appID, staff, roleID))
+
+	roles, err := s.UserRoles(ctx, userID, appID)
+	require.NoError(t, err)
+	require.Len(t, roles, 1)
+	assert.Equal(t, "reader", roles[0].Name)
+
+	permissions, err := s.UserPermissions(ctx, userID, appID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"posts.read"}, permissions)
+
+	has, err := s.HasRole(ctx, userID, appID, "reader")
+	require.NoError(t, err)
+	assert.True(t, has)
+	allowed, err := s.HasPermission(ctx, userID, appID, "posts.read")
+	require.NoError(t, err)
+	assert.True(t, allowed)
+
+	require.NoError(t, s.DeleteGroupMember(ctx, appID, editors, userID))
+	has, err = s.HasRole(ctx, userID, appID, "reader")
+	require.NoError(t, err)
+	assert.False(t, has, "role is lost with the group")
+}
+
+func TestSetGroupClaims(t *testing.T) {
+	s := newTestStorage(t)
+	ctx := context.Background()
+	appID := saveTestApp(t, s)
+
+	app, err := s.App(ctx, appID)
+	require.NoError(t, err)
+	assert.False(t, app.GroupClaims, "groups are not put into tokens by default")
+
+	require.NoError(t, s.SetGroupClaims(ctx, appID, true))
+	app, err = s.App(ctx, appID)
+	require.NoError(t, err)
+	assert.True(t, app.GroupClaims)
+
+	assert.ErrorIs(t, s.SetGroupClaims(ctx, -1, true), storage.ErrAppNotFound)
+}
@@ -0,0 +1,187 @@
+package postgresql
+
+import (
+	"SSO/internal/domain/models"
+	"SSO/internal/storage"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const selectIdentities = `SELECT id, user_id, provider, subject, email, created_at, last_login_at FROM identities`
+
+// SaveIdentity links identity at an upstream provider to the user and
+// returns it with ID and creation time set.
+func (s *Storage) SaveIdentity(ctx context.Context, identity models.Identity) (models.Identity, error) {
+	const op = "storage.postgresql.SaveIdentity"
+
+	err := s.DB.QueryRowContext(
+		ctx,
+		`INSERT INTO identities(user_id, provider, subject, email, last_login_at)
+		VALUES($1, $2, $3, $4, $5) RETURNING id, created_at`,
+		identity.UserID, identity.Provider, identity.Subject, identity.Email, nullTime(identity.LastLoginAt),
+	).Scan(&identity.ID, &identity.CreatedAt)
+	if err != nil {
+		switch pgErrorCode(err) {
+		case codeUniqueViolation:
+			return models.Identity{}, fmt.Errorf("%s: %w", op, storage.ErrIdentityExists)
+		case codeForeignKeyViolation:
+			return models.Identity{}, fmt.Errorf("%s: %w", op, storage.ErrUserNotFound)
+		}
+
+		return models.Identity{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return identity, nil
+}
+
+// Identity returns identity by the provider and subject at it.
+func (s *Storage) Identity(ctx context.Context, provider, subject string) (models.Identity, error) {
+	const op = "storage.postgresql.Identity"
+
+	identity, err := scanIdentity(s.DB.QueryRowContext(
+		ctx,
+		selectIdentities+" WHERE provider = $1 AND subject = $2",
+		provider, subject,
+	))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Identity{}, fmt.Errorf("%s: %w", op, storage.ErrIdentityNotFound)
+		}
+
+		return models.Identity{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return identity, nil
+}
+
+// Identities returns identities linked to the user.
+func (s *Storage) Identities(ctx context.Context, userID int64) ([]models.Identity, error) {
+	const op = "storage.postgresql.Identities"
+
+	rows, err := s.DB.QueryContext(ctx, selectIdentities+" WHERE user_id = $1 ORDER BY id", userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var identities []models.Identity
+	for rows.Next() {
+		identity, err := scanIdentity(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		identities = append(identities, identity)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return identities, nil
+}
+
+// DeleteIdentity unlinks identity from the user. The last identity of the
+// user without password is not deleted, the user row is locked for
+// concurrent unlinks to not leave the user without login method.
+func (s *Storage) DeleteIdentity(ctx context.Context, userID, id int64) error {
+	const op = "storage.postgresql.DeleteIdentity"
+
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	var hasPassword bool
+	err = tx.QueryRowContext(
+		ctx,
+		"SELECT COALESCE(length(pass_hash), 0) > 0 FROM users WHERE id = $1 FOR UPDATE",
+		userID,
+	).Scan(&hasPassword)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("%s: %w", op, storage.ErrUserNotFound)
+		}
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if !hasPassword {
+		var others int
+		err = tx.QueryRowContext(
+			ctx,
+			"SELECT count(*) FROM identities WHERE user_id = $1 AND id <> $2",
+			userID, id,
+		).Scan(&others)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		if others == 0 {
+			return fmt.Errorf("%s: %w", op, storage.ErrLastLoginMethod)
+		}
+	}
+
+	res, err := tx.ExecContext(ctx, "DELETE FROM identities WHERE id = $1 AND user_id = $2", id, userID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrIdentityNotFound)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// TouchIdentity records sign in with the identity and the email the
+// provider reported.
+func (s *Storage) TouchIdentity(ctx context.Context, id int64, email string, at time.Time) error {
+	const op = "storage.postgresql.TouchIdentity"
+
+	res, err := s.DB.ExecContext(
+		ctx,
+		"UPDATE identities SET email = $1, last_login_at = $2 WHERE id = $3",
+		email, at, id,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrIdentityNotFound)
+	}
+
+	return nil
+}
+
+func scanIdentity(row rowScanner) (models.Identity, error) {
+	var (
+		identity    models.Identity
+		lastLoginAt sql.NullTime
+	)
+	err := row.Scan(
+		&identity.ID,
+		&identity.UserID,
+		&identity.Provider,
+		&identity.Subject,
+		&identity.Email,
+		&identity.CreatedAt,
+		&lastLoginAt,
+	)
+	if err != nil {
+		return models.Identity{}, err
+	}
+
+	identity.LastLoginAt = lastLoginAt.Time
+
+	return identity, nil
+}
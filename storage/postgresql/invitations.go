@@ -0,0 +1,203 @@
+package postgresql
+
+import (
+	"SSO/internal/domain/models"
+	"SSO/internal/storage"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+const pendingInvitation = "accepted_at IS NULL AND revoked_at IS NULL AND expires_at > now()"
+
+// SaveInvitation saves invitation to organization identified by hash of its token.
+func (s *Storage) SaveInvitation(ctx context.Context, inv models.Invitation, tokenHash string) (int64, error) {
+	const op = "storage.postgresql.SaveInvitation"
+
+	var id int64
+	err := s.DB.QueryRowContext(
+		ctx,
+		`INSERT INTO organization_invitations(organization_id, email, role, token_hash, invited_by, expires_at)
+		VALUES($1, $2, $3, $4, $5, $6) RETURNING id`,
+		inv.OrganizationID, inv.Email, inv.Role, tokenHash, inv.InvitedBy, inv.ExpiresAt,
+	).Scan(&id)
+	if err != nil {
+		if pgErrorCode(err) == codeForeignKeyViolation {
+			return 0, fmt.Errorf("%s: %w", op, storage.ErrOrgNotFound)
+		}
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+// PendingInvitations returns not accepted, not revoked and not expired invitations to organization.
+func (s *Storage) PendingInvitations(ctx context.Context, orgID int64) ([]models.Invitation, error) {
+	const op = "storage.postgresql.PendingInvitations"
+
+	rows, err := s.DB.QueryContext(
+		ctx,
+		`SELECT id, organization_id, email, role, COALESCE(invited_by, 0), expires_at, created_at
+		FROM organization_invitations
+		WHERE organization_id = $1 AND `+pendingInvitation+`
+		ORDER BY created_at`,
+		orgID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var invitations []models.Invitation
+	for rows.Next() {
+		var inv models.Invitation
+		err := rows.Scan(&inv.ID, &inv.OrganizationID, &inv.Email, &inv.Role, &inv.InvitedBy, &inv.ExpiresAt, &inv.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		invitations = append(invitations, inv)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return invitations, nil
+}
+
+// PendingInvitationByToken returns pending invitation by hash of its token.
+func (s *Storage) PendingInvitationByToken(ctx context.Context, tokenHash string) (models.Invitation, error) {
+	const op = "storage.postgresql.PendingInvitationByToken"
+
+	var inv models.Invitation
+	err := s.DB.QueryRowContext(
+		ctx,
+		`SELECT id, organization_id, email, role, COALESCE(invited_by, 0), expires_at, created_at
+		FROM organization_invitations
+		WHERE token_hash = $1 AND `+pendingInvitation,
+		tokenHash,
+	).Scan(&inv.ID, &inv.OrganizationID, &inv.Email, &inv.Role, &inv.InvitedBy, &inv.ExpiresAt, &inv.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Invitation{}, fmt.Errorf("%s: %w", op, storage.ErrInviteNotFound)
+		}
+
+		return models.Invitation{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return inv, nil
+}
+
+// RevokeInvitation revokes pending invitation to organization.
+func (s *Storage) RevokeInvitation(ctx context.Context, orgID, invitationID int64) error {
+	const op = "storage.postgresql.RevokeInvitation"
+
+	res, err := s.DB.ExecContext(
+		ctx,
+		`UPDATE organization_invitations SET revoked_at = now()
+		WHERE id = $1 AND organization_id = $2 AND `+pendingInvitation,
+		invitationID, orgID,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrInviteNotFound)
+	}
+
+	return nil
+}
+
+// AcceptInvitation marks pending invitation accepted and adds user to
+// the organization with the invited role. Invitation can be accepted once.
+func (s *Storage) AcceptInvitation(ctx context.Context, invitationID, userID int64) error {
+	const op = "storage.postgresql.AcceptInvitation"
+
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	if err := acceptInvitation(ctx, tx, invitationID, userID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// AcceptInvitationAsNewUser saves user and accepts invitation for it in
+// one transaction, so no account is left behind if the invitation has
+// already been used, revoked or has expired.
+func (s *Storage) AcceptInvitationAsNewUser(ctx context.Context, invitationID int64, user models.User) (int64, error) {
+	const op = "storage.postgresql.AcceptInvitationAsNewUser"
+
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	userID, err := insertUser(ctx, tx, user)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := acceptInvitation(ctx, tx, invitationID, userID); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return userID, nil
+}
+
+// acceptInvitation marks pending invitation accepted within tx and adds
+// user to the organization with the invited role.
+func acceptInvitation(ctx context.Context, tx *sql.Tx, invitationID, userID int64) error {
+	var (
+		orgID int64
+		role  string
+	)
+	err := tx.QueryRowContext(
+		ctx,
+		`UPDATE organization_invitations SET accepted_at = now()
+		WHERE id = $1 AND `+pendingInvitation+`
+		RETURNING organization_id, role`,
+		invitationID,
+	).Scan(&orgID, &role)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return storage.ErrInviteNotFound
+		}
+
+		return err
+	}
+
+	_, err = tx.ExecContext(
+		ctx,
+		"INSERT INTO organization_members(organization_id, user_id, role) VALUES($1, $2, $3)",
+		orgID, userID, role,
+	)
+	if err != nil {
+		switch pgErrorCode(err) {
+		case codeUniqueViolation:
+			return storage.ErrMemberExists
+		case codeForeignKeyViolation:
+			return storage.ErrUserNotFound
+		}
+
+		return err
+	}
+
+	return nil
+}
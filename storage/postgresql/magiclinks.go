@@ -0,0 +1,73 @@
+package postgresql
+
+import (
+	"SSO/internal/domain/models"
+	"SSO/internal/storage"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/lib/pq"
+)
+
+// SaveMagicLink saves link by hash of its token. Expired links and former
+// links of the user to the app are deleted, so only the latest one works.
+func (s *Storage) SaveMagicLink(ctx context.Context, link models.MagicLink, tokenHash string) error {
+	const op = "storage.postgresql.SaveMagicLink"
+
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(
+		ctx,
+		"DELETE FROM magic_links WHERE expires_at <= now() OR (user_id = $1 AND app_id = $2)",
+		link.UserID, link.AppID,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = tx.ExecContext(
+		ctx,
+		"INSERT INTO magic_links(token_hash, user_id, app_id, scopes, expires_at) VALUES($1, $2, $3, $4, $5)",
+		tokenHash, link.UserID, link.AppID, pq.Array(link.Scopes), link.ExpiresAt,
+	)
+	if err != nil {
+		if pgErrorCode(err) == codeForeignKeyViolation {
+			return fmt.Errorf("%s: %w", op, storage.ErrAppNotFound)
+		}
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// ConsumeMagicLink deletes link by hash of its token and returns it, so
+// every link is used once.
+func (s *Storage) ConsumeMagicLink(ctx context.Context, tokenHash string) (models.MagicLink, error) {
+	const op = "storage.postgresql.ConsumeMagicLink"
+
+	var link models.MagicLink
+	err := s.DB.QueryRowContext(
+		ctx,
+		"DELETE FROM magic_links WHERE token_hash = $1 RETURNING user_id, app_id, scopes, expires_at",
+		tokenHash,
+	).Scan(&link.UserID, &link.AppID, pq.Array(&link.Scopes), &link.ExpiresAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.MagicLink{}, fmt.Errorf("%s: %w", op, storage.ErrMagicLinkNotFound)
+		}
+
+		return models.MagicLink{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return link, nil
+}
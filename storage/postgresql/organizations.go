@@ -0,0 +1,399 @@
+package postgresql
+
+import (
+	"SSO/internal/domain/models"
+	"SSO/internal/storage"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// SaveOrganization saves organization and makes owner its first member.
+func (s *Storage) SaveOrganization(ctx context.Context, name string, ownerID int64) (int64, error) {
+	const op = "storage.postgresql.SaveOrganization"
+
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	var id int64
+	err = tx.QueryRowContext(ctx, "INSERT INTO organizations(name) VALUES($1) RETURNING id", name).Scan(&id)
+	if err != nil {
+		if pgErrorCode(err) == codeUniqueViolation {
+			return 0, fmt.Errorf("%s: %w", op, storage.ErrOrgExists)
+		}
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = tx.ExecContext(
+		ctx,
+		"INSERT INTO organization_members(organization_id, user_id, role) VALUES($1, $2, $3)",
+		id, ownerID, models.OrgRoleOwner,
+	)
+	if err != nil {
+		if pgErrorCode(err) == codeForeignKeyViolation {
+			return 0, fmt.Errorf("%s: %w", op, storage.ErrUserNotFound)
+		}
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+// DeleteOrganization deletes organization with its memberships.
+func (s *Storage) DeleteOrganization(ctx context.Context, orgID int64) error {
+	const op = "storage.postgresql.DeleteOrganization"
+
+	res, err := s.DB.ExecContext(ctx, "DELETE FROM organizations WHERE id = $1", orgID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrOrgNotFound)
+	}
+
+	return nil
+}
+
+// Organization returns organization by id.
+func (s *Storage) Organization(ctx context.Context, orgID int64) (models.Organization, error) {
+	const op = "storage.postgresql.Organization"
+
+	var org models.Organization
+	err := s.DB.QueryRowContext(
+		ctx,
+		"SELECT id, name, created_at FROM organizations WHERE id = $1",
+		orgID,
+	).Scan(&org.ID, &org.Name, &org.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Organization{}, fmt.Errorf("%s: %w", op, storage.ErrOrgNotFound)
+		}
+
+		return models.Organization{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return org, nil
+}
+
+// UserOrganizations returns memberships of user in organizations.
+func (s *Storage) UserOrganizations(ctx context.Context, userID int64) ([]models.OrganizationMember, error) {
+	const op = "storage.postgresql.UserOrganizations"
+
+	members, err := s.queryMembers(
+		ctx,
+		`SELECT m.organization_id, o.name, m.user_id, u.email, m.role, m.created_at
+		FROM organization_members m
+		JOIN organizations o ON o.id = m.organization_id
+		JOIN users u ON u.id = m.user_id
+		WHERE m.user_id = $1
+		ORDER BY o.name`,
+		userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return members, nil
+}
+
+// SaveMember adds user to organization with role.
+func (s *Storage) SaveMember(ctx context.Context, orgID, userID int64, role string) error {
+	const op = "storage.postgresql.SaveMember"
+
+	_, err := s.DB.ExecContext(
+		ctx,
+		"INSERT INTO organization_members(organization_id, user_id, role) VALUES($1, $2, $3)",
+		orgID, userID, role,
+	)
+	if err != nil {
+		switch pgErrorCode(err) {
+		case codeUniqueViolation:
+			return fmt.Errorf("%s: %w", op, storage.ErrMemberExists)
+		case codeForeignKeyViolation:
+			if pgConstraintName(err) == "organization_members_user_id_fkey" {
+				return fmt.Errorf("%s: %w", op, storage.ErrUserNotFound)
+			}
+
+			return fmt.Errorf("%s: %w", op, storage.ErrOrgNotFound)
+		}
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// UpdateMemberRole changes role of organization member. The last owner
+// is not demoted, owner rows are locked for concurrent demotions to not
+// leave the organization without owners.
+func (s *Storage) UpdateMemberRole(ctx context.Context, orgID, userID int64, role string) error {
+	const op = "storage.postgresql.UpdateMemberRole"
+
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	if role != models.OrgRoleOwner {
+		if err := checkNotLastOwner(ctx, tx, orgID, userID); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	res, err := tx.ExecContext(
+		ctx,
+		"UPDATE organization_members SET role = $3 WHERE organization_id = $1 AND user_id = $2",
+		orgID, userID, role,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrMemberNotFound)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// DeleteMember removes user from organization. The last owner is not
+// removed, see UpdateMemberRole.
+func (s *Storage) DeleteMember(ctx context.Context, orgID, userID int64) error {
+	const op = "storage.postgresql.DeleteMember"
+
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	if err := checkNotLastOwner(ctx, tx, orgID, userID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := tx.ExecContext(
+		ctx,
+		"DELETE FROM organization_members WHERE organization_id = $1 AND user_id = $2",
+		orgID, userID,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrMemberNotFound)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// checkNotLastOwner locks owner memberships of organization until the end
+// of tx and returns storage.ErrLastOwner if user is its only owner.
+func checkNotLastOwner(ctx context.Context, tx *sql.Tx, orgID, userID int64) error {
+	rows, err := tx.QueryContext(
+		ctx,
+		"SELECT user_id FROM organization_members WHERE organization_id = $1 AND role = $2 FOR UPDATE",
+		orgID, models.OrgRoleOwner,
+	)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	var owners []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return err
+		}
+		owners = append(owners, id)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if len(owners) == 1 && owners[0] == userID {
+		return storage.ErrLastOwner
+	}
+
+	return nil
+}
+
+// Member returns membership of user in organization.
+func (s *Storage) Member(ctx context.Context, orgID, userID int64) (models.OrganizationMember, error) {
+	const op = "storage.postgresql.Member"
+
+	members, err := s.queryMembers(
+		ctx,
+		`SELECT m.organization_id, o.name, m.user_id, u.email, m.role, m.created_at
+		FROM organization_members m
+		JOIN organizations o ON o.id = m.organization_id
+		JOIN users u ON u.id = m.user_id
+		WHERE m.organization_id = $1 AND m.user_id = $2`,
+		orgID, userID,
+	)
+	if err != nil {
+		return models.OrganizationMember{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if len(members) == 0 {
+		return models.OrganizationMember{}, fmt.Errorf("%s: %w", op, storage.ErrMemberNotFound)
+	}
+
+	return members[0], nil
+}
+
+// Members returns members of organization.
+func (s *Storage) Members(ctx context.Context, orgID int64) ([]models.OrganizationMember, error) {
+	const op = "storage.postgresql.Members"
+
+	members, err := s.queryMembers(
+		ctx,
+		`SELECT m.organization_id, o.name, m.user_id, u.email, m.role, m.created_at
+		FROM organization_members m
+		JOIN organizations o ON o.id = m.organization_id
+		JOIN users u ON u.id = m.user_id
+		WHERE m.organization_id = $1
+		ORDER BY m.created_at`,
+		orgID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return members, nil
+}
+
+func (s *Storage) queryMembers(ctx context.Context, query string, args ...any) ([]models.OrganizationMember, error) {
+	rows, err := s.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var members []models.OrganizationMember
+	for rows.Next() {
+		var m models.OrganizationMember
+		if err := rows.Scan(&m.OrganizationID, &m.OrganizationName, &m.UserID, &m.Email, &m.Role, &m.CreatedAt); err != nil {
+			return nil, err
+		}
+		members = append(members, m)
+	}
+
+	return members, rows.Err()
+}
+
+// SaveOrganizationApp allows members of organization to log in to the app.
+func (s *Storage) SaveOrganizationApp(ctx context.Context, orgID int64, appID int) error {
+	const op = "storage.postgresql.SaveOrganizationApp"
+
+	_, err := s.DB.ExecContext(
+		ctx,
+		"INSERT INTO organization_apps(organization_id, app_id) VALUES($1, $2) ON CONFLICT DO NOTHING",
+		orgID, appID,
+	)
+	if err != nil {
+		if pgErrorCode(err) == codeForeignKeyViolation {
+			if pgConstraintName(err) == "organization_apps_app_id_fkey" {
+				return fmt.Errorf("%s: %w", op, storage.ErrAppNotFound)
+			}
+
+			return fmt.Errorf("%s: %w", op, storage.ErrOrgNotFound)
+		}
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// DeleteOrganizationApp revokes access of organization to the app.
+func (s *Storage) DeleteOrganizationApp(ctx context.Context, orgID int64, appID int) error {
+	const op = "storage.postgresql.DeleteOrganizationApp"
+
+	res, err := s.DB.ExecContext(
+		ctx,
+		"DELETE FROM organization_apps WHERE organization_id = $1 AND app_id = $2",
+		orgID, appID,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrAppNotFound)
+	}
+
+	return nil
+}
+
+// OrganizationApps returns apps organization has access to. Secrets are not loaded.
+func (s *Storage) OrganizationApps(ctx context.Context, orgID int64) ([]models.App, error) {
+	const op = "storage.postgresql.OrganizationApps"
+
+	rows, err := s.DB.QueryContext(
+		ctx,
+		`SELECT a.id, a.name
+		FROM organization_apps oa
+		JOIN apps a ON a.id = oa.app_id
+		WHERE oa.organization_id = $1
+		ORDER BY a.id`,
+		orgID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var apps []models.App
+	for rows.Next() {
+		var app models.App
+		if err := rows.Scan(&app.ID, &app.Name); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		apps = append(apps, app)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return apps, nil
+}
+
+// HasAppAccess reports whether organization has access to the app.
+func (s *Storage) HasAppAccess(ctx context.Context, orgID int64, appID int) (bool, error) {
+	const op = "storage.postgresql.HasAppAccess"
+
+	var has bool
+	err := s.DB.QueryRowContext(
+		ctx,
+		"SELECT EXISTS(SELECT 1 FROM organization_apps WHERE organization_id = $1 AND app_id = $2)",
+		orgID, appID,
+	).Scan(&has)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return has, nil
+}
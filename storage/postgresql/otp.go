@@ -0,0 +1,276 @@
+package postgresql
+
+import (
+	"SSO/internal/domain/models"
+	"SSO/internal/storage"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/lib/pq"
+	"time"
+)
+
+const selectOTPChallenges = `SELECT purpose, user_id, COALESCE(factor_id, 0), channel, destination,
+	COALESCE(app_id, 0), COALESCE(organization_id, 0), scopes, grant_type, code_hash, attempts, sends, sent_at,
+	expires_at FROM otp_challenges`
+
+// SaveOTPFactor saves confirmed factor of the user, replacing former
+// destination of the channel, and returns it with ID and creation time set.
+func (s *Storage) SaveOTPFactor(ctx context.Context, factor models.OTPFactor) (models.OTPFactor, error) {
+	const op = "storage.postgresql.SaveOTPFactor"
+
+	err := s.DB.QueryRowContext(
+		ctx,
+		`INSERT INTO otp_factors(user_id, channel, destination) VALUES($1, $2, $3)
+		ON CONFLICT (user_id, channel) DO UPDATE SET destination = EXCLUDED.destination, created_at = now()
+		RETURNING id, created_at`,
+		factor.UserID, factor.Channel, factor.Destination,
+	).Scan(&factor.ID, &factor.CreatedAt)
+	if err != nil {
+		if pgErrorCode(err) == codeForeignKeyViolation {
+			return models.OTPFactor{}, fmt.Errorf("%s: %w", op, storage.ErrUserNotFound)
+		}
+
+		return models.OTPFactor{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return factor, nil
+}
+
+// OTPFactors returns factors of the user.
+func (s *Storage) OTPFactors(ctx context.Context, userID int64) ([]models.OTPFactor, error) {
+	const op = "storage.postgresql.OTPFactors"
+
+	rows, err := s.DB.QueryContext(
+		ctx,
+		"SELECT id, user_id, channel, destination, created_at FROM otp_factors WHERE user_id = $1 ORDER BY id",
+		userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var factors []models.OTPFactor
+	for rows.Next() {
+		var f models.OTPFactor
+		if err := rows.Scan(&f.ID, &f.UserID, &f.Channel, &f.Destination, &f.CreatedAt); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		factors = append(factors, f)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return factors, nil
+}
+
+// DeleteOTPFactor deletes factor of the user with challenges sent to it.
+func (s *Storage) DeleteOTPFactor(ctx context.Context, userID, id int64) error {
+	const op = "storage.postgresql.DeleteOTPFactor"
+
+	res, err := s.DB.ExecContext(ctx, "DELETE FROM otp_factors WHERE id = $1 AND user_id = $2", id, userID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrOTPFactorNotFound)
+	}
+
+	return nil
+}
+
+// SaveOTPChallenge saves challenge by hash of its token and deletes
+// expired ones. The challenge voids former ones of the user with the same
+// purpose, so each user has one open challenge of a purpose.
+func (s *Storage) SaveOTPChallenge(ctx context.Context, challenge models.OTPChallenge, tokenHash string) error {
+	const op = "storage.postgresql.SaveOTPChallenge"
+
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(
+		ctx,
+		"DELETE FROM otp_challenges WHERE expires_at <= now() OR (user_id = $1 AND purpose = $2)",
+		challenge.UserID, challenge.Purpose,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = tx.ExecContext(
+		ctx,
+		`INSERT INTO otp_challenges(token_hash, purpose, user_id, factor_id, channel, destination, app_id,
+		organization_id, scopes, grant_type, code_hash, sends, sent_at, expires_at)
+		VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)`,
+		tokenHash,
+		challenge.Purpose,
+		challenge.UserID,
+		sql.NullInt64{Int64: challenge.FactorID, Valid: challenge.FactorID != 0},
+		challenge.Channel,
+		challenge.Destination,
+		sql.NullInt64{Int64: int64(challenge.AppID), Valid: challenge.AppID != 0},
+		sql.NullInt64{Int64: challenge.OrganizationID, Valid: challenge.OrganizationID != 0},
+		pq.Array(challenge.Scopes),
+		challenge.GrantType,
+		challenge.CodeHash,
+		challenge.Sends,
+		nullTime(challenge.SentAt),
+		challenge.ExpiresAt,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// OTPChallenge returns challenge by hash of its token.
+func (s *Storage) OTPChallenge(ctx context.Context, tokenHash string) (models.OTPChallenge, error) {
+	const op = "storage.postgresql.OTPChallenge"
+
+	challenge, err := scanOTPChallenge(s.DB.QueryRowContext(ctx, selectOTPChallenges+" WHERE token_hash = $1", tokenHash))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.OTPChallenge{}, fmt.Errorf("%s: %w", op, storage.ErrOTPChallengeNotFound)
+		}
+
+		return models.OTPChallenge{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return challenge, nil
+}
+
+// SendOTPChallenge records a new code sent for the challenge and resets
+// its attempts. It fails with ErrOTPChallengeNotFound if another code was
+// sent since prevSentAt, so concurrent resends can't bypass throttling.
+func (s *Storage) SendOTPChallenge(
+	ctx context.Context,
+	tokenHash string,
+	challenge models.OTPChallenge,
+	prevSentAt time.Time,
+) error {
+	const op = "storage.postgresql.SendOTPChallenge"
+
+	res, err := s.DB.ExecContext(
+		ctx,
+		`UPDATE otp_challenges SET factor_id = $1, channel = $2, destination = $3, code_hash = $4,
+		attempts = 0, sends = sends + 1, sent_at = $5, expires_at = $6
+		WHERE token_hash = $7 AND sent_at IS NOT DISTINCT FROM $8`,
+		sql.NullInt64{Int64: challenge.FactorID, Valid: challenge.FactorID != 0},
+		challenge.Channel,
+		challenge.Destination,
+		challenge.CodeHash,
+		challenge.SentAt,
+		challenge.ExpiresAt,
+		tokenHash,
+		nullTime(prevSentAt),
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrOTPChallengeNotFound)
+	}
+
+	return nil
+}
+
+// AttemptOTPChallenge counts an attempt to enter code of the challenge and
+// returns the challenge with the attempt counted.
+func (s *Storage) AttemptOTPChallenge(ctx context.Context, tokenHash string) (models.OTPChallenge, error) {
+	const op = "storage.postgresql.AttemptOTPChallenge"
+
+	challenge, err := scanOTPChallenge(s.DB.QueryRowContext(
+		ctx,
+		`UPDATE otp_challenges SET attempts = attempts + 1 WHERE token_hash = $1
+		RETURNING purpose, user_id, COALESCE(factor_id, 0), channel, destination, COALESCE(app_id, 0),
+		COALESCE(organization_id, 0), scopes, grant_type, code_hash, attempts, sends, sent_at, expires_at`,
+		tokenHash,
+	))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.OTPChallenge{}, fmt.Errorf("%s: %w", op, storage.ErrOTPChallengeNotFound)
+		}
+
+		return models.OTPChallenge{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return challenge, nil
+}
+
+// LastOTPSentAt returns when the last code for challenges of the user with
+// the purpose was sent, zero time if none was.
+func (s *Storage) LastOTPSentAt(ctx context.Context, userID int64, purpose string) (time.Time, error) {
+	const op = "storage.postgresql.LastOTPSentAt"
+
+	var sentAt sql.NullTime
+	err := s.DB.QueryRowContext(
+		ctx,
+		"SELECT MAX(sent_at) FROM otp_challenges WHERE user_id = $1 AND purpose = $2 AND expires_at > now()",
+		userID, purpose,
+	).Scan(&sentAt)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return sentAt.Time, nil
+}
+
+// DeleteOTPChallenge deletes challenge by hash of its token.
+func (s *Storage) DeleteOTPChallenge(ctx context.Context, tokenHash string) error {
+	const op = "storage.postgresql.DeleteOTPChallenge"
+
+	res, err := s.DB.ExecContext(ctx, "DELETE FROM otp_challenges WHERE token_hash = $1", tokenHash)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrOTPChallengeNotFound)
+	}
+
+	return nil
+}
+
+func scanOTPChallenge(row rowScanner) (models.OTPChallenge, error) {
+	var (
+		c      models.OTPChallenge
+		sentAt sql.NullTime
+	)
+
+	err := row.Scan(
+		&c.Purpose,
+		&c.UserID,
+		&c.FactorID,
+		&c.Channel,
+		&c.Destination,
+		&c.AppID,
+		&c.OrganizationID,
+		pq.Array(&c.Scopes),
+		&c.GrantType,
+		&c.CodeHash,
+		&c.Attempts,
+		&c.Sends,
+		&sentAt,
+		&c.ExpiresAt,
+	)
+	if err != nil {
+		return models.OTPChallenge{}, err
+	}
+	c.SentAt = sentAt.Time
+
+	return c, nil
+}
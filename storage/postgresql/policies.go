@@ -0,0 +1,208 @@
+package postgresql
+
+import (
+	"SSO/internal/domain/models"
+	"SSO/internal/storage"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/lib/pq"
+)
+
+// SavePolicy saves policy of the app to database.
+func (s *Storage) SavePolicy(ctx context.Context, p models.Policy) (int64, error) {
+	const op = "storage.postgresql.SavePolicy"
+
+	conditions, err := json.Marshal(nonNilConditions(p.Conditions))
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var id int64
+	err = s.DB.QueryRowContext(
+		ctx,
+		`INSERT INTO policies(app_id, name, description, effect, actions, resources, conditions)
+		VALUES($1, $2, $3, $4, $5, $6, $7) RETURNING id`,
+		p.AppID, p.Name, p.Description, p.Effect, pq.Array(p.Actions), pq.Array(p.Resources), conditions,
+	).Scan(&id)
+	if err != nil {
+		switch pgErrorCode(err) {
+		case codeUniqueViolation:
+			return 0, fmt.Errorf("%s: %w", op, storage.ErrPolicyExists)
+		case codeForeignKeyViolation:
+			return 0, fmt.Errorf("%s: %w", op, storage.ErrAppNotFound)
+		}
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+// UpdatePolicy replaces policy of the app.
+func (s *Storage) UpdatePolicy(ctx context.Context, p models.Policy) error {
+	const op = "storage.postgresql.UpdatePolicy"
+
+	conditions, err := json.Marshal(nonNilConditions(p.Conditions))
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := s.DB.ExecContext(
+		ctx,
+		`UPDATE policies
+		SET name = $3, description = $4, effect = $5, actions = $6, resources = $7, conditions = $8
+		WHERE id = $1 AND app_id = $2`,
+		p.ID, p.AppID, p.Name, p.Description, p.Effect, pq.Array(p.Actions), pq.Array(p.Resources), conditions,
+	)
+	if err != nil {
+		if pgErrorCode(err) == codeUniqueViolation {
+			return fmt.Errorf("%s: %w", op, storage.ErrPolicyExists)
+		}
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrPolicyNotFound)
+	}
+
+	return nil
+}
+
+// DeletePolicy deletes policy of the app.
+func (s *Storage) DeletePolicy(ctx context.Context, appID int, policyID int64) error {
+	const op = "storage.postgresql.DeletePolicy"
+
+	res, err := s.DB.ExecContext(ctx, "DELETE FROM policies WHERE id = $1 AND app_id = $2", policyID, appID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrPolicyNotFound)
+	}
+
+	return nil
+}
+
+// Policies returns policies of the app.
+func (s *Storage) Policies(ctx context.Context, appID int) ([]models.Policy, error) {
+	const op = "storage.postgresql.Policies"
+
+	rows, err := s.DB.QueryContext(
+		ctx,
+		`SELECT id, app_id, name, description, effect, actions, resources, conditions
+		FROM policies WHERE app_id = $1 ORDER BY id`,
+		appID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var policies []models.Policy
+	for rows.Next() {
+		var (
+			p          models.Policy
+			conditions []byte
+		)
+
+		err := rows.Scan(&p.ID, &p.AppID, &p.Name, &p.Description, &p.Effect,
+			pq.Array(&p.Actions), pq.Array(&p.Resources), &conditions)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		if err := json.Unmarshal(conditions, &p.Conditions); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		policies = append(policies, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return policies, nil
+}
+
+// SaveAuthorizationRequest records authorization decision.
+func (s *Storage) SaveAuthorizationRequest(ctx context.Context, r models.AuthorizationRequest) (int64, error) {
+	const op = "storage.postgresql.SaveAuthorizationRequest"
+
+	attributes, err := json.Marshal(r.Attributes)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var id int64
+	err = s.DB.QueryRowContext(
+		ctx,
+		`INSERT INTO authorization_requests(app_id, user_id, action, resource_type, attributes, allowed)
+		VALUES($1, $2, $3, $4, $5, $6) RETURNING id`,
+		r.AppID, r.UserID, r.Action, r.ResourceType, attributes, r.Allowed,
+	).Scan(&id)
+	if err != nil {
+		if pgErrorCode(err) == codeForeignKeyViolation {
+			return 0, fmt.Errorf("%s: %w", op, storage.ErrAppNotFound)
+		}
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+// AuthorizationRequests returns the latest recorded authorization requests of the app.
+func (s *Storage) AuthorizationRequests(ctx context.Context, appID int, limit int) ([]models.AuthorizationRequest, error) {
+	const op = "storage.postgresql.AuthorizationRequests"
+
+	rows, err := s.DB.QueryContext(
+		ctx,
+		`SELECT id, app_id, user_id, action, resource_type, attributes, allowed, created_at
+		FROM authorization_requests
+		WHERE app_id = $1
+		ORDER BY id DESC
+		LIMIT $2`,
+		appID, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var requests []models.AuthorizationRequest
+	for rows.Next() {
+		var (
+			r          models.AuthorizationRequest
+			attributes []byte
+		)
+
+		err := rows.Scan(&r.ID, &r.AppID, &r.UserID, &r.Action, &r.ResourceType, &attributes, &r.Allowed, &r.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		if err := json.Unmarshal(attributes, &r.Attributes); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		requests = append(requests, r)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return requests, nil
+}
+
+func nonNilConditions(conditions []models.PolicyCondition) []models.PolicyCondition {
+	if conditions == nil {
+		return []models.PolicyCondition{}
+	}
+
+	return conditions
+}
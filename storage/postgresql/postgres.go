@@ -12,9 +12,24 @@ import (
 	"log/slog"
 )
 
+// Коды ошибок Postgres
+const (
+	codeForeignKeyViolation = "23503"
+	codeUniqueViolation     = "23505"
+)
+
 type Storage struct {
-	DB  *sql.DB
-	log *slog.Logger
+	DB     *sql.DB
+	log    *slog.Logger
+	cipher Cipher
+}
+
+// Cipher encrypts sensitive columns, such as app secrets, before they are
+// written and decrypts them after they are read. Values are bound to aad
+// identifying the row and column they are stored in.
+type Cipher interface {
+	Encrypt(plaintext string, aad []byte) (string, error)
+	Decrypt(value string, aad []byte) (string, error)
 }
 
 var connectionString = fmt.Sprintf("postgres://%s:@%s:%d/%s",
@@ -24,7 +39,9 @@ var connectionString = fmt.Sprintf("postgres://%s:@%s:%d/%s",
 	"sso_for_app",
 )
 
-func New(storagePath string) (*Storage, error) {
+// New connects to database. Sensitive columns are encrypted with cipher,
+// or stored as is if cipher is nil.
+func New(storagePath string, cipher Cipher) (*Storage, error) {
 	const op = "storage.postgresql.New"
 
 	db, err := sql.Open("postgres", storagePath)
@@ -37,7 +54,11 @@ func New(storagePath string) (*Storage, error) {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	return &Storage{DB: db}, nil
+	if cipher == nil {
+		cipher = plaintext{}
+	}
+
+	return &Storage{DB: db, cipher: cipher}, nil
 }
 
 func (s *Storage) Stop() error {
@@ -57,22 +78,35 @@ func (s *Storage) Stop() error {
 func (s *Storage) SaveUser(ctx context.Context, email string, passHash []byte, username, sex, location, dateOfBirth string) (int64, error) {
 	const op = "storage.postgresql.SaveUser"
 
+	id, err := insertUser(ctx, s.DB, models.User{
+		Email:       email,
+		PassHash:    passHash,
+		Username:    username,
+		Sex:         sex,
+		Location:    location,
+		DateOfBirth: dateOfBirth,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+// insertUser saves user with password and returns its id.
+func insertUser(ctx context.Context, q queryRower, user models.User) (int64, error) {
 	var id int64
-	err := s.DB.QueryRowContext(
+	err := q.QueryRowContext(
 		ctx,
-		"INSERT INTO users(email, pass_hash, username, location, birth_date, sex) VALUES($1, $2, $3, $4, $5, $6) RETURNING id",
-		email, passHash, username, location, dateOfBirth, sex,
+		"INSERT INTO users(email, pass_hash, username, location, birth_date, sex) VALUES($1, $2, $3, NULLIF($4, ''), NULLIF($5, '')::DATE, $6) RETURNING id",
+		user.Email, user.PassHash, user.Username, user.Location, user.DateOfBirth, user.Sex,
 	).Scan(&id)
-
 	if err != nil {
-		var pgErr *pq.Error
-		if errors.As(err, &pgErr) {
-			// Код уникального ограничения
-			if pgErr.Code == "23505" {
-				return 0, fmt.Errorf("%s: %w", op, storage.ErrUserExists)
-			}
+		if pgErrorCode(err) == codeUniqueViolation {
+			return 0, storage.ErrUserExists
 		}
-		return 0, fmt.Errorf("%s: %w", op, err)
+
+		return 0, err
 	}
 
 	return id, nil
@@ -86,9 +120,9 @@ func (s *Storage) User(ctx context.Context, email string) (models.User, error) {
 
 	err := s.DB.QueryRowContext(
 		ctx,
-		"SELECT id, email, pass_hash, username, location, sex, birth_date FROM users WHERE email = $1",
+		"SELECT id, email, pass_hash, username, COALESCE(location, ''), COALESCE(sex, ''), COALESCE(birth_date::text, ''), kind FROM users WHERE email = $1",
 		email,
-	).Scan(&user.ID, &user.Email, &user.PassHash, &user.Username, &user.Location, &user.Sex, &user.DateOfBirth)
+	).Scan(&user.ID, &user.Email, &user.PassHash, &user.Username, &user.Location, &user.Sex, &user.DateOfBirth, &user.Kind)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return models.User{}, fmt.Errorf("%s: %w", op, storage.ErrUserNotFound)
@@ -100,24 +134,52 @@ func (s *Storage) User(ctx context.Context, email string) (models.User, error) {
 	return user, nil
 }
 
+// UserByID returns user by id.
+func (s *Storage) UserByID(ctx context.Context, userID int64) (models.User, error) {
+	const op = "storage.postgresql.UserByID"
+
+	var user models.User
+
+	err := s.DB.QueryRowContext(
+		ctx,
+		"SELECT id, email, pass_hash, username, COALESCE(location, ''), COALESCE(sex, ''), COALESCE(birth_date::text, ''), kind FROM users WHERE id = $1",
+		userID,
+	).Scan(&user.ID, &user.Email, &user.PassHash, &user.Username, &user.Location, &user.Sex, &user.DateOfBirth, &user.Kind)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.User{}, fmt.Errorf("%s: %w", op, storage.ErrUserNotFound)
+		}
+
+		return models.User{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return user, nil
+}
+
+// IsAdmin reports whether user holds the admin role in any app.
 func (s *Storage) IsAdmin(ctx context.Context, userID int64) (bool, error) {
 	const op = "storage.postgresql.IsAdmin"
 	var isAdmin bool
 
 	err := s.DB.QueryRowContext(
 		ctx,
-		"SELECT is_admin FROM users WHERE id = $1",
+		`SELECT EXISTS(
+			SELECT 1 FROM user_roles ur
+			JOIN roles r ON r.id = ur.role_id
+			WHERE ur.user_id = u.id AND r.name = 'admin'
+		)
+		FROM users u WHERE u.id = $1`,
 		userID,
 	).Scan(&isAdmin)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return false, fmt.Errorf("%s: %w", op, storage.ErrAppNotFound)
+			return false, fmt.Errorf("%s: %w", op, storage.ErrUserNotFound)
 		}
 
 		return false, fmt.Errorf("%s: %w", op, err)
 	}
 
-	return true, nil
+	return isAdmin, nil
 }
 
 func (s *Storage) IsExists(ctx context.Context, email string) (bool, error) {
@@ -141,22 +203,28 @@ func (s *Storage) IsExists(ctx context.Context, email string) (bool, error) {
 	return isExists, nil
 }
 
-func (s *Storage) App(ctx context.Context, appID int) (models.App, error) {
-	const op = "storage.postgresql.App"
-	var app models.App
+// plaintext is the Cipher storing values as is.
+type plaintext struct{}
 
-	err := s.DB.QueryRowContext(
-		ctx,
-		"SELECT id, name, secret FROM apps WHERE id = $1",
-		appID,
-	).Scan(&app.ID, &app.Name, &app.Secret)
-	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return models.App{}, fmt.Errorf("%s: %w", op, storage.ErrAppNotFound)
-		}
+func (plaintext) Encrypt(value string, _ []byte) (string, error) { return value, nil }
+func (plaintext) Decrypt(value string, _ []byte) (string, error) { return value, nil }
+
+// pgErrorCode returns Postgres error code of err or empty string.
+func pgErrorCode(err error) string {
+	var pgErr *pq.Error
+	if errors.As(err, &pgErr) {
+		return string(pgErr.Code)
+	}
+
+	return ""
+}
 
-		return models.App{}, fmt.Errorf("%s: %w", op, err)
+// pgConstraintName returns name of the constraint violated by err or empty string.
+func pgConstraintName(err error) string {
+	var pgErr *pq.Error
+	if errors.As(err, &pgErr) {
+		return pgErr.Constraint
 	}
 
-	return app, nil
+	return ""
 }
@@ -0,0 +1,57 @@
+package postgresql
+
+import (
+	"SSO/internal/domain/models"
+	"context"
+	"os"
+	"testing"
+
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/stretchr/testify/require"
+)
+
+// storagePathEnv names the database storage tests run against. The
+// database must have all migrations applied, tests are skipped without it.
+const storagePathEnv = "SSO_TEST_STORAGE_PATH"
+
+func newTestStorage(t *testing.T) *Storage {
+	t.Helper()
+
+	path := os.Getenv(storagePathEnv)
+	if path == "" {
+		t.Skipf("%s is not set", storagePathEnv)
+	}
+
+	s, err := New(path, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = s.DB.Close() })
+
+	return s
+}
+
+// saveTestUser saves a user with random email and returns its id.
+func saveTestUser(t *testing.T, s *Storage) int64 {
+	t.Helper()
+
+	id, err := s.SaveUser(context.Background(), gofakeit.Email(), []byte("hash"), gofakeit.Username(), "", "", "")
+	require.NoError(t, err)
+	t.Cleanup(func() { _, _ = s.DB.Exec("DELETE FROM users WHERE id = $1", id) })
+
+	return id
+}
+
+// saveTestApp saves an app with random name and returns its id.
+func saveTestApp(t *testing.T, s *Storage) int {
+	t.Helper()
+
+	app, err := s.SaveApp(context.Background(), models.App{
+		Name:         gofakeit.UUID(),
+		Secret:       gofakeit.UUID(),
+		RedirectURIs: []string{},
+		GrantTypes:   []string{models.GrantPassword},
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _, _ = s.DB.Exec("DELETE FROM apps WHERE id = $1", app.ID) })
+
+	return app.ID
+}
@@ -0,0 +1,383 @@
+package postgresql
+
+import (
+	"SSO/internal/domain/models"
+	"SSO/internal/storage"
+	"context"
+	"fmt"
+	"github.com/lib/pq"
+)
+
+// SaveRole saves role of the app to database.
+func (s *Storage) SaveRole(ctx context.Context, appID int, name string) (int64, error) {
+	const op = "storage.postgresql.SaveRole"
+
+	var id int64
+	err := s.DB.QueryRowContext(
+		ctx,
+		"INSERT INTO roles(app_id, name) VALUES($1, $2) RETURNING id",
+		appID, name,
+	).Scan(&id)
+	if err != nil {
+		switch pgErrorCode(err) {
+		case codeUniqueViolation:
+			return 0, fmt.Errorf("%s: %w", op, storage.ErrRoleExists)
+		case codeForeignKeyViolation:
+			return 0, fmt.Errorf("%s: %w", op, storage.ErrAppNotFound)
+		}
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+// DeleteRole deletes role of the app together with its assignments.
+func (s *Storage) DeleteRole(ctx context.Context, appID int, roleID int64) error {
+	const op = "storage.postgresql.DeleteRole"
+
+	res, err := s.DB.ExecContext(ctx, "DELETE FROM roles WHERE id = $1 AND app_id = $2", roleID, appID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrRoleNotFound)
+	}
+
+	return nil
+}
+
+// Roles returns roles of the app with names of their permissions.
+func (s *Storage) Roles(ctx context.Context, appID int) ([]models.Role, error) {
+	const op = "storage.postgresql.Roles"
+
+	roles, err := s.queryRoles(
+		ctx,
+		`SELECT r.id, r.app_id, r.name,
+			COALESCE(array_agg(p.name ORDER BY p.name) FILTER (WHERE p.id IS NOT NULL), '{}')
+		FROM roles r
+		LEFT JOIN role_permissions rp ON rp.role_id = r.id
+		LEFT JOIN permissions p ON p.id = rp.permission_id
+		WHERE r.app_id = $1
+		GROUP BY r.id
+		ORDER BY r.name`,
+		appID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return roles, nil
+}
+
+// UserRoles returns roles assigned to user in the app
+// directly or through any of the groups.
+func (s *Storage) UserRoles(ctx context.Context, userID int64, appID int) ([]models.Role, error) {
+	const op = "storage.postgresql.UserRoles"
+
+	roles, err := s.queryRoles(
+		ctx,
+		userRolesCTE+`
+		SELECT r.id, r.app_id, r.name,
+			COALESCE(array_agg(p.name ORDER BY p.name) FILTER (WHERE p.id IS NOT NULL), '{}')
+		FROM user_role_ids ur
+		JOIN roles r ON r.id = ur.role_id
+		LEFT JOIN role_permissions rp ON rp.role_id = r.id
+		LEFT JOIN permissions p ON p.id = rp.permission_id
+		WHERE r.app_id = $2
+		GROUP BY r.id
+		ORDER BY r.name`,
+		userID, appID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return roles, nil
+}
+
+func (s *Storage) queryRoles(ctx context.Context, query string, args ...any) ([]models.Role, error) {
+	rows, err := s.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var roles []models.Role
+	for rows.Next() {
+		var role models.Role
+		if err := rows.Scan(&role.ID, &role.AppID, &role.Name, pq.Array(&role.Permissions)); err != nil {
+			return nil, err
+		}
+		roles = append(roles, role)
+	}
+
+	return roles, rows.Err()
+}
+
+// SavePermission saves permission of the app to database.
+func (s *Storage) SavePermission(ctx context.Context, appID int, name string) (int64, error) {
+	const op = "storage.postgresql.SavePermission"
+
+	var id int64
+	err := s.DB.QueryRowContext(
+		ctx,
+		"INSERT INTO permissions(app_id, name) VALUES($1, $2) RETURNING id",
+		appID, name,
+	).Scan(&id)
+	if err != nil {
+		switch pgErrorCode(err) {
+		case codeUniqueViolation:
+			return 0, fmt.Errorf("%s: %w", op, storage.ErrPermissionExists)
+		case codeForeignKeyViolation:
+			return 0, fmt.Errorf("%s: %w", op, storage.ErrAppNotFound)
+		}
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+// DeletePermission deletes permission of the app and revokes it from all roles.
+func (s *Storage) DeletePermission(ctx context.Context, appID int, permissionID int64) error {
+	const op = "storage.postgresql.DeletePermission"
+
+	res, err := s.DB.ExecContext(ctx, "DELETE FROM permissions WHERE id = $1 AND app_id = $2", permissionID, appID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrPermissionNotFound)
+	}
+
+	return nil
+}
+
+// Permissions returns permissions of the app.
+func (s *Storage) Permissions(ctx context.Context, appID int) ([]models.Permission, error) {
+	const op = "storage.postgresql.Permissions"
+
+	rows, err := s.DB.QueryContext(
+		ctx,
+		"SELECT id, app_id, name FROM permissions WHERE app_id = $1 ORDER BY name",
+		appID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var permissions []models.Permission
+	for rows.Next() {
+		var p models.Permission
+		if err := rows.Scan(&p.ID, &p.AppID, &p.Name); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		permissions = append(permissions, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return permissions, nil
+}
+
+// GrantPermission grants permission to role. Both must belong to the app.
+// Granting already granted permission is not an error.
+func (s *Storage) GrantPermission(ctx context.Context, appID int, roleID, permissionID int64) error {
+	const op = "storage.postgresql.GrantPermission"
+
+	if err := s.checkRoleAndPermission(ctx, appID, roleID, permissionID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err := s.DB.ExecContext(
+		ctx,
+		"INSERT INTO role_permissions(role_id, permission_id) VALUES($1, $2) ON CONFLICT DO NOTHING",
+		roleID, permissionID,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// RevokePermission revokes permission from role.
+func (s *Storage) RevokePermission(ctx context.Context, appID int, roleID, permissionID int64) error {
+	const op = "storage.postgresql.RevokePermission"
+
+	if err := s.checkRoleAndPermission(ctx, appID, roleID, permissionID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err := s.DB.ExecContext(
+		ctx,
+		"DELETE FROM role_permissions WHERE role_id = $1 AND permission_id = $2",
+		roleID, permissionID,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *Storage) checkRoleAndPermission(ctx context.Context, appID int, roleID, permissionID int64) error {
+	var roleExists, permissionExists bool
+
+	err := s.DB.QueryRowContext(
+		ctx,
+		`SELECT
+			EXISTS(SELECT 1 FROM roles WHERE id = $1 AND app_id = $3),
+			EXISTS(SELECT 1 FROM permissions WHERE id = $2 AND app_id = $3)`,
+		roleID, permissionID, appID,
+	).Scan(&roleExists, &permissionExists)
+	if err != nil {
+		return err
+	}
+
+	if !roleExists {
+		return storage.ErrRoleNotFound
+	}
+	if !permissionExists {
+		return storage.ErrPermissionNotFound
+	}
+
+	return nil
+}
+
+// AssignRole assigns role of the app to user.
+// Assigning already assigned role is not an error.
+func (s *Storage) AssignRole(ctx context.Context, appID int, userID, roleID int64) error {
+	const op = "storage.postgresql.AssignRole"
+
+	res, err := s.DB.ExecContext(
+		ctx,
+		`INSERT INTO user_roles(user_id, role_id)
+		SELECT $1, id FROM roles WHERE id = $2 AND app_id = $3
+		ON CONFLICT DO NOTHING`,
+		userID, roleID, appID,
+	)
+	if err != nil {
+		if pgErrorCode(err) == codeForeignKeyViolation {
+			return fmt.Errorf("%s: %w", op, storage.ErrUserNotFound)
+		}
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if n, _ := res.RowsAffected(); n == 0 {
+		var exists bool
+		err := s.DB.QueryRowContext(
+			ctx,
+			"SELECT EXISTS(SELECT 1 FROM roles WHERE id = $1 AND app_id = $2)",
+			roleID, appID,
+		).Scan(&exists)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		if !exists {
+			return fmt.Errorf("%s: %w", op, storage.ErrRoleNotFound)
+		}
+	}
+
+	return nil
+}
+
+// UnassignRole removes role of the app from user.
+func (s *Storage) UnassignRole(ctx context.Context, appID int, userID, roleID int64) error {
+	const op = "storage.postgresql.UnassignRole"
+
+	res, err := s.DB.ExecContext(
+		ctx,
+		`DELETE FROM user_roles
+		WHERE user_id = $1 AND role_id = (SELECT id FROM roles WHERE id = $2 AND app_id = $3)`,
+		userID, roleID, appID,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrRoleNotFound)
+	}
+
+	return nil
+}
+
+// UserPermissions returns names of the permissions user holds in the app
+// through any of the assigned roles, including roles of the groups.
+func (s *Storage) UserPermissions(ctx context.Context, userID int64, appID int) ([]string, error) {
+	const op = "storage.postgresql.UserPermissions"
+
+	var permissions []string
+	err := s.DB.QueryRowContext(
+		ctx,
+		userRolesCTE+`
+		SELECT COALESCE(array_agg(DISTINCT p.name), '{}')
+		FROM user_role_ids ur
+		JOIN roles r ON r.id = ur.role_id
+		JOIN role_permissions rp ON rp.role_id = r.id
+		JOIN permissions p ON p.id = rp.permission_id
+		WHERE r.app_id = $2`,
+		userID, appID,
+	).Scan(pq.Array(&permissions))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return permissions, nil
+}
+
+// HasPermission reports whether user holds permission in the app.
+func (s *Storage) HasPermission(ctx context.Context, userID int64, appID int, permission string) (bool, error) {
+	const op = "storage.postgresql.HasPermission"
+
+	var allowed bool
+	err := s.DB.QueryRowContext(
+		ctx,
+		userRolesCTE+`
+		SELECT EXISTS(
+			SELECT 1
+			FROM user_role_ids ur
+			JOIN roles r ON r.id = ur.role_id
+			JOIN role_permissions rp ON rp.role_id = r.id
+			JOIN permissions p ON p.id = rp.permission_id
+			WHERE r.app_id = $2 AND p.name = $3
+		)`,
+		userID, appID, permission,
+	).Scan(&allowed)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return allowed, nil
+}
+
+// HasRole reports whether user holds role with given name in the app.
+func (s *Storage) HasRole(ctx context.Context, userID int64, appID int, role string) (bool, error) {
+	const op = "storage.postgresql.HasRole"
+
+	var has bool
+	err := s.DB.QueryRowContext(
+		ctx,
+		userRolesCTE+`
+		SELECT EXISTS(
+			SELECT 1
+			FROM user_role_ids ur
+			JOIN roles r ON r.id = ur.role_id
+			WHERE r.app_id = $2 AND r.name = $3
+		)`,
+		userID, appID, role,
+	).Scan(&has)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return has, nil
+}
@@ -0,0 +1,121 @@
+package postgresql
+
+import (
+	"SSO/internal/domain/models"
+	"SSO/internal/storage"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/lib/pq"
+)
+
+const selectServiceProviders = `SELECT app_id, entity_id, acs_urls, name_id_format, attributes, created_at
+	FROM saml_service_providers`
+
+// SaveServiceProvider creates or replaces SAML service provider of the app
+// and returns it with creation time set.
+func (s *Storage) SaveServiceProvider(ctx context.Context, sp models.ServiceProvider) (models.ServiceProvider, error) {
+	const op = "storage.postgresql.SaveServiceProvider"
+
+	attributes, err := json.Marshal(nonNilAttributes(sp.Attributes))
+	if err != nil {
+		return models.ServiceProvider{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = s.DB.QueryRowContext(
+		ctx,
+		`INSERT INTO saml_service_providers(app_id, entity_id, acs_urls, name_id_format, attributes)
+		VALUES($1, $2, $3, $4, $5)
+		ON CONFLICT (app_id) DO UPDATE SET entity_id = EXCLUDED.entity_id, acs_urls = EXCLUDED.acs_urls,
+			name_id_format = EXCLUDED.name_id_format, attributes = EXCLUDED.attributes
+		RETURNING created_at`,
+		sp.AppID, sp.EntityID, pq.Array(sp.ACSURLs), sp.NameIDFormat, attributes,
+	).Scan(&sp.CreatedAt)
+	if err != nil {
+		switch pgErrorCode(err) {
+		case codeUniqueViolation:
+			return models.ServiceProvider{}, fmt.Errorf("%s: %w", op, storage.ErrServiceProviderExists)
+		case codeForeignKeyViolation:
+			return models.ServiceProvider{}, fmt.Errorf("%s: %w", op, storage.ErrAppNotFound)
+		}
+
+		return models.ServiceProvider{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return sp, nil
+}
+
+// ServiceProvider returns SAML service provider of the app.
+func (s *Storage) ServiceProvider(ctx context.Context, appID int) (models.ServiceProvider, error) {
+	const op = "storage.postgresql.ServiceProvider"
+
+	sp, err := scanServiceProvider(s.DB.QueryRowContext(ctx, selectServiceProviders+" WHERE app_id = $1", appID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.ServiceProvider{}, fmt.Errorf("%s: %w", op, storage.ErrServiceProviderNotFound)
+		}
+
+		return models.ServiceProvider{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return sp, nil
+}
+
+// ServiceProviderByEntityID returns SAML service provider by its entity ID.
+func (s *Storage) ServiceProviderByEntityID(ctx context.Context, entityID string) (models.ServiceProvider, error) {
+	const op = "storage.postgresql.ServiceProviderByEntityID"
+
+	sp, err := scanServiceProvider(s.DB.QueryRowContext(ctx, selectServiceProviders+" WHERE entity_id = $1", entityID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.ServiceProvider{}, fmt.Errorf("%s: %w", op, storage.ErrServiceProviderNotFound)
+		}
+
+		return models.ServiceProvider{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return sp, nil
+}
+
+// DeleteServiceProvider deletes SAML service provider of the app.
+func (s *Storage) DeleteServiceProvider(ctx context.Context, appID int) error {
+	const op = "storage.postgresql.DeleteServiceProvider"
+
+	res, err := s.DB.ExecContext(ctx, "DELETE FROM saml_service_providers WHERE app_id = $1", appID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrServiceProviderNotFound)
+	}
+
+	return nil
+}
+
+func scanServiceProvider(row rowScanner) (models.ServiceProvider, error) {
+	var (
+		sp         models.ServiceProvider
+		attributes []byte
+	)
+	err := row.Scan(&sp.AppID, &sp.EntityID, pq.Array(&sp.ACSURLs), &sp.NameIDFormat, &attributes, &sp.CreatedAt)
+	if err != nil {
+		return models.ServiceProvider{}, err
+	}
+
+	if err := json.Unmarshal(attributes, &sp.Attributes); err != nil {
+		return models.ServiceProvider{}, err
+	}
+
+	return sp, nil
+}
+
+func nonNilAttributes(attributes map[string]string) map[string]string {
+	if attributes == nil {
+		return map[string]string{}
+	}
+
+	return attributes
+}
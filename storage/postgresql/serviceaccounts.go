@@ -0,0 +1,292 @@
+package postgresql
+
+import (
+	"SSO/internal/domain/models"
+	"SSO/internal/storage"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+const selectServiceAccounts = `SELECT user_id, COALESCE(app_id, 0), COALESCE(organization_id, 0), name, description,
+	disabled, COALESCE(created_by, 0), created_at FROM service_accounts`
+
+const selectServiceAccountKeys = `SELECT id, service_account_id, kid, public_key, expires_at, created_at FROM service_account_keys`
+
+// SaveServiceAccount saves service account as a user of the service kind
+// with given email and username, without password. Service account of an
+// organization becomes its member. Returns the account with ID and
+// creation time set.
+func (s *Storage) SaveServiceAccount(
+	ctx context.Context,
+	sa models.ServiceAccount,
+	email,
+	username string,
+) (models.ServiceAccount, error) {
+	const op = "storage.postgresql.SaveServiceAccount"
+
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return models.ServiceAccount{}, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(
+		ctx,
+		"INSERT INTO users(email, username, kind) VALUES($1, $2, 'service') RETURNING id",
+		email, username,
+	).Scan(&sa.ID)
+	if err != nil {
+		if pgErrorCode(err) == codeUniqueViolation {
+			return models.ServiceAccount{}, fmt.Errorf("%s: %w", op, storage.ErrAccountExists)
+		}
+
+		return models.ServiceAccount{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = tx.QueryRowContext(
+		ctx,
+		`INSERT INTO service_accounts(user_id, app_id, organization_id, name, description, created_by)
+		VALUES($1, NULLIF($2, 0), NULLIF($3, 0), $4, $5, NULLIF($6, 0)) RETURNING created_at`,
+		sa.ID, sa.AppID, sa.OrganizationID, sa.Name, sa.Description, sa.CreatedBy,
+	).Scan(&sa.CreatedAt)
+	if err != nil {
+		switch pgErrorCode(err) {
+		case codeUniqueViolation:
+			return models.ServiceAccount{}, fmt.Errorf("%s: %w", op, storage.ErrAccountExists)
+		case codeForeignKeyViolation:
+			if sa.AppID != 0 {
+				return models.ServiceAccount{}, fmt.Errorf("%s: %w", op, storage.ErrAppNotFound)
+			}
+
+			return models.ServiceAccount{}, fmt.Errorf("%s: %w", op, storage.ErrOrgNotFound)
+		}
+
+		return models.ServiceAccount{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if sa.OrganizationID != 0 {
+		_, err := tx.ExecContext(
+			ctx,
+			"INSERT INTO organization_members(organization_id, user_id, role) VALUES($1, $2, $3)",
+			sa.OrganizationID, sa.ID, models.OrgRoleMember,
+		)
+		if err != nil {
+			return models.ServiceAccount{}, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return models.ServiceAccount{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return sa, nil
+}
+
+// ServiceAccount returns service account by its user ID.
+func (s *Storage) ServiceAccount(ctx context.Context, id int64) (models.ServiceAccount, error) {
+	const op = "storage.postgresql.ServiceAccount"
+
+	sa, err := scanServiceAccount(s.DB.QueryRowContext(ctx, selectServiceAccounts+" WHERE user_id = $1", id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.ServiceAccount{}, fmt.Errorf("%s: %w", op, storage.ErrAccountNotFound)
+		}
+
+		return models.ServiceAccount{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return sa, nil
+}
+
+// ServiceAccounts returns service accounts of the app, if appID is not 0,
+// or of the organization.
+func (s *Storage) ServiceAccounts(ctx context.Context, appID int, orgID int64) ([]models.ServiceAccount, error) {
+	const op = "storage.postgresql.ServiceAccounts"
+
+	query, owner := selectServiceAccounts+" WHERE app_id = $1 ORDER BY user_id", any(appID)
+	if appID == 0 {
+		query, owner = selectServiceAccounts+" WHERE organization_id = $1 ORDER BY user_id", orgID
+	}
+
+	rows, err := s.DB.QueryContext(ctx, query, owner)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var accounts []models.ServiceAccount
+	for rows.Next() {
+		sa, err := scanServiceAccount(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		accounts = append(accounts, sa)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return accounts, nil
+}
+
+// SetServiceAccountDisabled disables or enables service account.
+func (s *Storage) SetServiceAccountDisabled(ctx context.Context, id int64, disabled bool) error {
+	const op = "storage.postgresql.SetServiceAccountDisabled"
+
+	res, err := s.DB.ExecContext(ctx, "UPDATE service_accounts SET disabled = $1 WHERE user_id = $2", disabled, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrAccountNotFound)
+	}
+
+	return nil
+}
+
+// DeleteServiceAccount deletes service account together with its user,
+// keys, roles and memberships.
+func (s *Storage) DeleteServiceAccount(ctx context.Context, id int64) error {
+	const op = "storage.postgresql.DeleteServiceAccount"
+
+	res, err := s.DB.ExecContext(ctx, "DELETE FROM users WHERE id = $1 AND kind = 'service'", id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrAccountNotFound)
+	}
+
+	return nil
+}
+
+// SaveServiceAccountKey saves public key of service account and returns
+// it with ID and creation time set.
+func (s *Storage) SaveServiceAccountKey(ctx context.Context, key models.ServiceAccountKey) (models.ServiceAccountKey, error) {
+	const op = "storage.postgresql.SaveServiceAccountKey"
+
+	err := s.DB.QueryRowContext(
+		ctx,
+		`INSERT INTO service_account_keys(service_account_id, kid, public_key, expires_at)
+		VALUES($1, $2, $3, $4) RETURNING id, created_at`,
+		key.ServiceAccountID, key.KID, key.PublicKey, nullTime(key.ExpiresAt),
+	).Scan(&key.ID, &key.CreatedAt)
+	if err != nil {
+		switch pgErrorCode(err) {
+		case codeUniqueViolation:
+			return models.ServiceAccountKey{}, fmt.Errorf("%s: %w", op, storage.ErrAccountKeyExists)
+		case codeForeignKeyViolation:
+			return models.ServiceAccountKey{}, fmt.Errorf("%s: %w", op, storage.ErrAccountNotFound)
+		}
+
+		return models.ServiceAccountKey{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return key, nil
+}
+
+// ServiceAccountKeys returns public keys of service account.
+func (s *Storage) ServiceAccountKeys(ctx context.Context, id int64) ([]models.ServiceAccountKey, error) {
+	const op = "storage.postgresql.ServiceAccountKeys"
+
+	rows, err := s.DB.QueryContext(ctx, selectServiceAccountKeys+" WHERE service_account_id = $1 ORDER BY id", id)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var keys []models.ServiceAccountKey
+	for rows.Next() {
+		key, err := scanServiceAccountKey(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		keys = append(keys, key)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return keys, nil
+}
+
+// ServiceAccountKey returns not expired public key of service account by its kid.
+func (s *Storage) ServiceAccountKey(ctx context.Context, id int64, kid string) (models.ServiceAccountKey, error) {
+	const op = "storage.postgresql.ServiceAccountKey"
+
+	key, err := scanServiceAccountKey(s.DB.QueryRowContext(
+		ctx,
+		selectServiceAccountKeys+` WHERE service_account_id = $1 AND kid = $2
+		AND (expires_at IS NULL OR expires_at > now())`,
+		id, kid,
+	))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.ServiceAccountKey{}, fmt.Errorf("%s: %w", op, storage.ErrAccountKeyNotFound)
+		}
+
+		return models.ServiceAccountKey{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return key, nil
+}
+
+// DeleteServiceAccountKey deletes public key of service account.
+func (s *Storage) DeleteServiceAccountKey(ctx context.Context, id, keyID int64) error {
+	const op = "storage.postgresql.DeleteServiceAccountKey"
+
+	res, err := s.DB.ExecContext(
+		ctx,
+		"DELETE FROM service_account_keys WHERE id = $1 AND service_account_id = $2",
+		keyID, id,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrAccountKeyNotFound)
+	}
+
+	return nil
+}
+
+func scanServiceAccount(row rowScanner) (models.ServiceAccount, error) {
+	var sa models.ServiceAccount
+	err := row.Scan(
+		&sa.ID,
+		&sa.AppID,
+		&sa.OrganizationID,
+		&sa.Name,
+		&sa.Description,
+		&sa.Disabled,
+		&sa.CreatedBy,
+		&sa.CreatedAt,
+	)
+	if err != nil {
+		return models.ServiceAccount{}, err
+	}
+
+	return sa, nil
+}
+
+func scanServiceAccountKey(row rowScanner) (models.ServiceAccountKey, error) {
+	var (
+		key     models.ServiceAccountKey
+		expires sql.NullTime
+	)
+	err := row.Scan(&key.ID, &key.ServiceAccountID, &key.KID, &key.PublicKey, &expires, &key.CreatedAt)
+	if err != nil {
+		return models.ServiceAccountKey{}, err
+	}
+
+	key.ExpiresAt = expires.Time
+
+	return key, nil
+}
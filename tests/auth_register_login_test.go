@@ -2,6 +2,7 @@ package tests
 
 import (
 	"SSO/tests/suite"
+	"context"
 	"github.com/brianvoe/gofakeit/v6"
 	ssov1 "github.com/futod4m4/protos/gen/go/sso"
 	"github.com/golang-jwt/jwt"
@@ -15,6 +16,7 @@ const (
 	emptyAppId = 0
 	appID      = 1
 	appSecret  = "test-secret"
+	otherAppID = 2
 
 	passDefaultLen = 10
 )
@@ -36,6 +38,7 @@ func TestRegisterLogin_Login_HappyPath(t *testing.T) {
 		Sex:         sex,
 		Location:    location,
 		DateOfBirth: dateOfBirth,
+		AppId:       appID,
 	})
 	require.NoError(t, err)
 	require.NotEmpty(t, respReg.GetUserId())
@@ -86,6 +89,7 @@ func TestRegister_DoubleRegister(t *testing.T) {
 		Sex:         sex,
 		Location:    location,
 		DateOfBirth: dateOfBirth,
+		AppId:       appID,
 	})
 	require.NoError(t, err)
 	require.NotEmpty(t, respReg.GetUserId())
@@ -97,12 +101,56 @@ func TestRegister_DoubleRegister(t *testing.T) {
 		Sex:         sex,
 		Location:    location,
 		DateOfBirth: dateOfBirth,
+		AppId:       appID,
 	})
 	require.Error(t, err)
 	require.Empty(t, respReg2.GetUserId())
 	assert.ErrorContains(t, err, "user already exists")
 }
 
+func TestRegister_EmptyAppID(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	respReg, err := st.AuthClient.Register(ctx, &ssov1.RegisterRequest{
+		Email:       gofakeit.Email(),
+		Password:    randomFakePassword(),
+		Username:    gofakeit.Username(),
+		Location:    gofakeit.Country(),
+		DateOfBirth: gofakeit.Date().Format("2006-01-02"),
+		AppId:       emptyAppId,
+	})
+	require.Error(t, err)
+	require.Empty(t, respReg.GetUserId())
+	assert.ErrorContains(t, err, "incorrect app_id")
+}
+
 func randomFakePassword() string {
 	return gofakeit.Password(true, true, true, true, false, passDefaultLen)
 }
+
+// registerAndLogin registers a new user and logs it in to the test app.
+func registerAndLogin(ctx context.Context, st *suite.Suite) (int64, string) {
+	st.Helper()
+
+	email := gofakeit.Email()
+	password := randomFakePassword()
+
+	respReg, err := st.AuthClient.Register(ctx, &ssov1.RegisterRequest{
+		Email:       email,
+		Password:    password,
+		Username:    gofakeit.Username(),
+		Location:    gofakeit.Country(),
+		DateOfBirth: gofakeit.Date().Format("2006-01-02"),
+		AppId:       appID,
+	})
+	require.NoError(st, err)
+
+	respLogin, err := st.AuthClient.Login(ctx, &ssov1.LoginRequest{
+		Email:    email,
+		Password: password,
+		AppId:    appID,
+	})
+	require.NoError(st, err)
+
+	return respReg.GetUserId(), respLogin.GetToken()
+}
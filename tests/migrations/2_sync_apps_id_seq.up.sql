@@ -0,0 +1 @@
+SELECT setval('apps_id_seq', (SELECT MAX(id) FROM apps));
@@ -0,0 +1,4 @@
+INSERT INTO apps (id, name, secret)
+VALUES (2, 'test-other', 'test-other-secret')
+ON CONFLICT DO NOTHING;
+SELECT setval('apps_id_seq', (SELECT MAX(id) FROM apps));
@@ -0,0 +1,106 @@
+package tests
+
+import (
+	"SSO/tests/suite"
+	"github.com/brianvoe/gofakeit/v6"
+	ssov1 "github.com/futod4m4/protos/gen/go/sso"
+	"github.com/golang-jwt/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"testing"
+)
+
+func TestPermissions_CheckPermission_NoRoles(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	userID, token := registerAndLogin(ctx, st)
+
+	tokenParsed, err := jwt.Parse(token, func(token *jwt.Token) (interface{}, error) {
+		return []byte(appSecret), nil
+	})
+	require.NoError(t, err)
+
+	claims := tokenParsed.Claims.(jwt.MapClaims)
+	assert.Empty(t, claims["roles"])
+	assert.Empty(t, claims["permissions"])
+
+	ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token)
+
+	resp, err := st.PermissionsClient.CheckPermission(ctx, &ssov1.CheckPermissionRequest{
+		AppId:      appID,
+		UserId:     userID,
+		Permission: "posts.edit",
+	})
+	require.NoError(t, err)
+	assert.False(t, resp.GetAllowed())
+
+	isAdmin, err := st.AuthClient.IsAdmin(ctx, &ssov1.IsAdminRequest{UserId: userID})
+	require.NoError(t, err)
+	assert.False(t, isAdmin.GetIsAdmin())
+}
+
+func TestPermissions_CheckPermission_RequiresApp(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	userID, _ := registerAndLogin(ctx, st)
+	req := &ssov1.CheckPermissionRequest{
+		AppId:      appID,
+		UserId:     userID,
+		Permission: "posts.edit",
+	}
+
+	_, err := st.PermissionsClient.CheckPermission(ctx, req)
+	require.Error(t, err)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	email := gofakeit.Email()
+	password := randomFakePassword()
+
+	_, err = st.AuthClient.Register(ctx, &ssov1.RegisterRequest{
+		Email:       email,
+		Password:    password,
+		Username:    gofakeit.Username(),
+		Location:    gofakeit.Country(),
+		DateOfBirth: gofakeit.Date().Format("2006-01-02"),
+		AppId:       otherAppID,
+	})
+	require.NoError(t, err)
+
+	respLogin, err := st.AuthClient.Login(ctx, &ssov1.LoginRequest{
+		Email:    email,
+		Password: password,
+		AppId:    otherAppID,
+	})
+	require.NoError(t, err)
+
+	ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+respLogin.GetToken())
+
+	_, err = st.PermissionsClient.CheckPermission(ctx, req)
+	require.Error(t, err)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+}
+
+func TestPermissions_CreateRole_RequiresAdmin(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	_, err := st.PermissionsClient.CreateRole(ctx, &ssov1.CreateRoleRequest{
+		AppId: appID,
+		Name:  "editor",
+	})
+	require.Error(t, err)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, token := registerAndLogin(ctx, st)
+
+	ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token)
+
+	_, err = st.PermissionsClient.CreateRole(ctx, &ssov1.CreateRoleRequest{
+		AppId: appID,
+		Name:  "editor",
+	})
+	require.Error(t, err)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+}
@@ -12,9 +12,10 @@ import (
 )
 
 type Suite struct {
-	*testing.T                  // Потребуется для вызова методов *testing.T
-	Cfg        *config.Config   // Конфигурация приложения
-	AuthClient ssov1.AuthClient // Клиент для взаимодействия с gRPC-сервером
+	*testing.T                                // Потребуется для вызова методов *testing.T
+	Cfg               *config.Config          // Конфигурация приложения
+	AuthClient        ssov1.AuthClient        // Клиент для взаимодействия с gRPC-сервером
+	PermissionsClient ssov1.PermissionsClient // Клиент для управления ролями и правами
 }
 
 const (
@@ -42,9 +43,10 @@ func New(t *testing.T) (context.Context, *Suite) {
 	}
 
 	return ctx, &Suite{
-		T:          t,
-		Cfg:        cfg,
-		AuthClient: ssov1.NewAuthClient(cc),
+		T:                 t,
+		Cfg:               cfg,
+		AuthClient:        ssov1.NewAuthClient(cc),
+		PermissionsClient: ssov1.NewPermissionsClient(cc),
 	}
 }
 